@@ -366,6 +366,22 @@ func columnDescToAvroSchema(colDesc *descpb.ColumnDescriptor) (*avroSchemaField,
 		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.ParseDJSON(x.(string))
 		}
+	case types.TSVectorFamily:
+		avroType = avroSchemaString
+		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DTSVector).TSVector.String(), nil
+		}
+		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.ParseDTSVector(x.(string))
+		}
+	case types.TSQueryFamily:
+		avroType = avroSchemaString
+		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DTSQuery).TSQuery.String(), nil
+		}
+		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.ParseDTSQuery(x.(string))
+		}
 	default:
 		return nil, errors.Errorf(`column %s: type %s not yet supported with avro`,
 			colDesc.Name, colDesc.Type.SQLString())
@@ -278,6 +278,37 @@ const (
 	// JoinTokensTable adds the system table for storing ephemeral generated
 	// join tokens.
 	JoinTokensTable
+	// DeferrableConstraints allows foreign key and UNIQUE WITHOUT INDEX
+	// constraints to be declared DEFERRABLE.
+	DeferrableConstraints
+	// ExpressionIndexes allows indexes to be created on arbitrary expressions,
+	// which are backed by inaccessible virtual computed columns.
+	ExpressionIndexes
+	// TextSearchTypes enables the use of the tsvector and tsquery types.
+	TextSearchTypes
+	// CompositeTypes enables the creation of user-defined composite types.
+	CompositeTypes
+	// Domains enables the creation of user-defined domain types.
+	Domains
+	// TrigramInvertedIndexes enables the creation of inverted indexes over the
+	// trigrams of string columns.
+	TrigramInvertedIndexes
+	// RowLevelTTL enables the automatic expiration of the rows of tables with
+	// the ttl_expire_after or ttl_expiration_column storage parameters.
+	RowLevelTTL
+	// RowLevelSecurity enables row-level security policies on tables.
+	RowLevelSecurity
+	// DefaultPrivileges enables ALTER DEFAULT PRIVILEGES.
+	DefaultPrivileges
+	// IncrementalMaterializedViews enables materialized views with
+	// auto_refresh = incremental.
+	IncrementalMaterializedViews
+	// PlanBaselines adds the system.plan_baselines table, which stores plans
+	// pinned for statement fingerprints.
+	PlanBaselines
+	// StatementHints adds the system.statement_hints table, which stores
+	// optimizer hints for statement fingerprints.
+	StatementHints
 
 	// Step (1): Add new versions here.
 )
@@ -484,6 +515,54 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     JoinTokensTable,
 		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 50},
 	},
+	{
+		Key:     DeferrableConstraints,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 52},
+	},
+	{
+		Key:     ExpressionIndexes,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 54},
+	},
+	{
+		Key:     TextSearchTypes,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 56},
+	},
+	{
+		Key:     CompositeTypes,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 58},
+	},
+	{
+		Key:     Domains,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 60},
+	},
+	{
+		Key:     TrigramInvertedIndexes,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 62},
+	},
+	{
+		Key:     RowLevelTTL,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 64},
+	},
+	{
+		Key:     RowLevelSecurity,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 66},
+	},
+	{
+		Key:     DefaultPrivileges,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 68},
+	},
+	{
+		Key:     IncrementalMaterializedViews,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 70},
+	},
+	{
+		Key:     PlanBaselines,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 72},
+	},
+	{
+		Key:     StatementHints,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 74},
+	},
 	// Step (2): Add new versions here.
 })
 
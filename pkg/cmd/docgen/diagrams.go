@@ -667,7 +667,7 @@ var specs = []stmtSpec{
 	},
 	{
 		name:   "delete_stmt",
-		inline: []string{"opt_with_clause", "with_clause", "cte_list", "table_expr_opt_alias_idx", "table_name_opt_idx", "opt_where_clause", "where_clause", "returning_clause", "opt_sort_clause", "opt_limit_clause", "opt_only", "opt_descendant"},
+		inline: []string{"opt_with_clause", "with_clause", "cte_list", "table_expr_opt_alias_idx", "table_name_opt_idx", "opt_where_clause", "where_clause", "returning_clause", "opt_sort_clause", "opt_limit_clause", "opt_only", "opt_descendant", "opt_using_clause", "from_list"},
 		replace: map[string]string{
 			"relation_expr": "table_name",
 		},
@@ -928,6 +928,31 @@ var specs = []stmtSpec{
 		name:   "like_table_option_list",
 		inline: []string{"like_table_option"},
 	},
+	{
+		name: "merge_stmt",
+		inline: []string{
+			"opt_with_clause",
+			"with_clause",
+			"cte_list",
+			"table_expr_opt_alias_idx",
+			"table_name_opt_idx",
+			"merge_when_list",
+			"opt_only",
+			"opt_descendant",
+		},
+		replace: map[string]string{"relation_expr": "table_name"},
+		relink:  map[string]string{"table_name": "relation_expr"},
+		nosplit: true,
+	},
+	{
+		name: "merge_when_clause",
+		inline: []string{
+			"opt_merge_when_cond",
+			"insert_column_list",
+			"insert_column_item",
+		},
+		nosplit: true,
+	},
 	{
 		name: "on_conflict",
 		inline: []string{"name_list", "set_clause_list", "insert_column_list",
@@ -1196,6 +1221,10 @@ var specs = []stmtSpec{
 			regexp.MustCompile("'SET' 'CLUSTER'"),
 		},
 	},
+	{
+		name: "set_constraints",
+		stmt: "set_constraints_stmt",
+	},
 	{
 		name: "set_transaction",
 		stmt: "nonpreparable_set_stmt",
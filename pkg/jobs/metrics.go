@@ -25,7 +25,8 @@ import (
 type Metrics struct {
 	JobMetrics [jobspb.NumJobTypes]*JobTypeMetrics
 
-	Changefeed metric.Struct
+	Changefeed  metric.Struct
+	RowLevelTTL metric.Struct
 }
 
 // JobTypeMetrics is a metric.Struct containing metrics for each type of job.
@@ -130,6 +131,9 @@ func (m *Metrics) init(histogramWindowInterval time.Duration) {
 	if MakeChangefeedMetricsHook != nil {
 		m.Changefeed = MakeChangefeedMetricsHook(histogramWindowInterval)
 	}
+	if MakeRowLevelTTLMetricsHook != nil {
+		m.RowLevelTTL = MakeRowLevelTTLMetricsHook(histogramWindowInterval)
+	}
 	for i := 0; i < jobspb.NumJobTypes; i++ {
 		jt := jobspb.Type(i)
 		if jt == jobspb.TypeUnspecified { // do not track TypeUnspecified
@@ -152,6 +156,10 @@ func (m *Metrics) init(histogramWindowInterval time.Duration) {
 // ccl code.
 var MakeChangefeedMetricsHook func(time.Duration) metric.Struct
 
+// MakeRowLevelTTLMetricsHook allows for registration of row-level TTL metrics
+// from the package implementing the row-level TTL job.
+var MakeRowLevelTTLMetricsHook func(time.Duration) metric.Struct
+
 // JobTelemetryMetrics is a telemetry metrics for individual job types.
 type JobTelemetryMetrics struct {
 	Successful telemetry.Counter
@@ -1221,6 +1221,13 @@ func (tc *TxnCoordSender) GetSteppingMode(ctx context.Context) (curMode kv.Stepp
 	return curMode
 }
 
+// SetReadSeqNum is part of the TxnSender interface.
+func (tc *TxnCoordSender) SetReadSeqNum(seq enginepb.TxnSeq) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.interceptorAlloc.txnSeqNumAllocator.setReadSeqLocked(seq)
+}
+
 // ManualRefresh is part of the TxnSender interface.
 func (tc *TxnCoordSender) ManualRefresh(ctx context.Context) error {
 	tc.mu.Lock()
@@ -144,6 +144,18 @@ func (s *txnSeqNumAllocator) stepLocked(ctx context.Context) error {
 	return nil
 }
 
+// setReadSeqLocked moves the read seqnum to the given sequencing
+// point, which must have been established at or before the current
+// write seqnum.
+func (s *txnSeqNumAllocator) setReadSeqLocked(seq enginepb.TxnSeq) error {
+	if seq < 0 || seq > s.writeSeq {
+		return errors.AssertionFailedf(
+			"invalid read seqnum %d (write seqnum %d)", seq, s.writeSeq)
+	}
+	s.readSeq = seq
+	return nil
+}
+
 // configureSteppingLocked configures the stepping mode.
 //
 // When enabling stepping from the non-enabled state, the read seqnum
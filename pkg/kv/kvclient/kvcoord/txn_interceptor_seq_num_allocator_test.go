@@ -360,6 +360,46 @@ func TestSequenceNumberAllocationAfterLeafInitialization(t *testing.T) {
 	require.NotNil(t, br)
 }
 
+// TestSequenceNumberAllocationSetReadSeq tests that read-only requests are
+// performed at a read seqnum explicitly moved back to an earlier sequencing
+// point, and that the read seqnum cannot be moved past the write seqnum.
+func TestSequenceNumberAllocationSetReadSeq(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	s, mockSender := makeMockTxnSeqNumAllocator()
+
+	txn := makeTxnProto()
+	keyA := roachpb.Key("a")
+
+	s.configureSteppingLocked(true /* enabled */)
+	s.writeSeq = 5
+	require.NoError(t, s.stepLocked(ctx))
+
+	require.Error(t, s.setReadSeqLocked(6))
+	require.Equal(t, enginepb.TxnSeq(5), s.readSeq)
+	require.NoError(t, s.setReadSeqLocked(2))
+
+	var ba roachpb.BatchRequest
+	ba.Header = roachpb.Header{Txn: &txn}
+	ba.Add(&roachpb.GetRequest{RequestHeader: roachpb.RequestHeader{Key: keyA}})
+	ba.Add(&roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: keyA}})
+
+	mockSender.MockSend(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		require.Len(t, ba.Requests, 2)
+		require.Equal(t, enginepb.TxnSeq(2), ba.Requests[0].GetInner().Header().Sequence)
+		require.Equal(t, enginepb.TxnSeq(6), ba.Requests[1].GetInner().Header().Sequence)
+
+		br := ba.CreateReply()
+		br.Txn = ba.Txn
+		return br, nil
+	})
+
+	br, pErr := s.SendLocked(ctx, ba)
+	require.Nil(t, pErr)
+	require.NotNil(t, br)
+}
+
 // TestSequenceNumberAllocationSavepoint tests that the allocator populates a
 // savepoint with the cur seq num.
 func TestSequenceNumberAllocationSavepoint(t *testing.T) {
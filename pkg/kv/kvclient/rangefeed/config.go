@@ -13,6 +13,7 @@ package rangefeed
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 )
 
@@ -27,6 +28,7 @@ type config struct {
 	withInitialScan    bool
 	withDiff           bool
 	onInitialScanError OnInitialScanError
+	onFrontierAdvance  OnFrontierAdvance
 }
 
 type optionFunc func(*config)
@@ -65,6 +67,19 @@ func WithOnInitialScanError(f OnInitialScanError) Option {
 	})
 }
 
+// OnFrontierAdvance is called when the frontier of the rangefeed advances,
+// that is when all the values at or below the given timestamp in the span
+// have been passed to the value function.
+type OnFrontierAdvance func(ctx context.Context, timestamp hlc.Timestamp)
+
+// WithOnFrontierAdvance sets up a callback which is invoked whenever the
+// frontier of the rangefeed advances.
+func WithOnFrontierAdvance(f OnFrontierAdvance) Option {
+	return optionFunc(func(c *config) {
+		c.onFrontierAdvance = f
+	})
+}
+
 // WithDiff makes an option to enable an initial scan which defaults to
 // false.
 func WithDiff() Option {
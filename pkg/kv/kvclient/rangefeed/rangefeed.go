@@ -306,7 +306,10 @@ func (f *RangeFeed) processEvents(
 			case ev.Val != nil:
 				f.onValue(ctx, ev.Val)
 			case ev.Checkpoint != nil:
-				frontier.Forward(ev.Checkpoint.Span, ev.Checkpoint.ResolvedTS)
+				advanced := frontier.Forward(ev.Checkpoint.Span, ev.Checkpoint.ResolvedTS)
+				if advanced && f.onFrontierAdvance != nil {
+					f.onFrontierAdvance(ctx, frontier.Frontier())
+				}
 			case ev.Error != nil:
 				// Intentionally do nothing, we'll get an error returned from the
 				// call to RangeFeed.
@@ -285,6 +285,49 @@ func TestRangeFeedMock(t *testing.T) {
 		<-rows
 		r.Close()
 	})
+	t.Run("frontier advance", func(t *testing.T) {
+		stopper := stop.NewStopper()
+		ctx := context.Background()
+		defer stopper.Stop(ctx)
+		sp := roachpb.Span{
+			Key:    roachpb.Key("a"),
+			EndKey: roachpb.Key("c"),
+		}
+		ts1 := hlc.Timestamp{WallTime: 1}
+		ts2 := ts1.Next()
+		checkpoint := func(span roachpb.Span, ts hlc.Timestamp) *roachpb.RangeFeedEvent {
+			return &roachpb.RangeFeedEvent{
+				Checkpoint: &roachpb.RangeFeedCheckpoint{Span: span, ResolvedTS: ts},
+			}
+		}
+		mc := mockClient{
+			rangefeed: func(
+				ctx context.Context, span roachpb.Span, startFrom hlc.Timestamp, withDiff bool, eventC chan<- *roachpb.RangeFeedEvent,
+			) error {
+				// A partial checkpoint does not advance the frontier, nor does a
+				// checkpoint which does not forward it.
+				eventC <- checkpoint(roachpb.Span{Key: sp.Key, EndKey: roachpb.Key("b")}, ts2)
+				eventC <- checkpoint(sp, ts1)
+				eventC <- checkpoint(sp, ts2)
+				eventC <- checkpoint(sp, ts2)
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}
+		f := rangefeed.NewFactoryWithDB(stopper, &mc, nil /* knobs */)
+		frontiers := make(chan hlc.Timestamp, 4)
+		r, err := f.RangeFeed(ctx, "foo", sp, hlc.Timestamp{}, func(
+			ctx context.Context, value *roachpb.RangeFeedValue,
+		) {
+		}, rangefeed.WithOnFrontierAdvance(func(ctx context.Context, ts hlc.Timestamp) {
+			frontiers <- ts
+		}))
+		require.NoError(t, err)
+		require.Equal(t, ts1, <-frontiers)
+		require.Equal(t, ts2, <-frontiers)
+		r.Close()
+		require.Len(t, frontiers, 0)
+	})
 	t.Run("stopper already stopped", func(t *testing.T) {
 		stopper := stop.NewStopper()
 		ctx := context.Background()
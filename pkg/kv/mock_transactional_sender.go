@@ -203,6 +203,11 @@ func (m *MockTransactionalSender) GetSteppingMode(context.Context) SteppingMode
 	return SteppingDisabled
 }
 
+// SetReadSeqNum is part of the TxnSender interface.
+func (m *MockTransactionalSender) SetReadSeqNum(enginepb.TxnSeq) error {
+	return nil
+}
+
 // ManualRefresh is part of the TxnSender interface.
 func (m *MockTransactionalSender) ManualRefresh(ctx context.Context) error {
 	panic("unimplemented")
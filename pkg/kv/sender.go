@@ -296,6 +296,14 @@ type TxnSender interface {
 	// for use in tests and assertion checks.
 	GetSteppingMode(ctx context.Context) (curMode SteppingMode)
 
+	// SetReadSeqNum sets the sequence number at which read-only
+	// operations are performed when stepping mode is enabled. The
+	// sequence number must not be greater than the current write
+	// sequence number. This is used to resume reads at a sequencing
+	// point established earlier in the transaction, e.g. by a SQL
+	// cursor.
+	SetReadSeqNum(seq enginepb.TxnSeq) error
+
 	// ManualRefresh attempts to refresh a transactions read timestamp up to its
 	// provisional commit timestamp. In the case that the two are already the
 	// same, it is a no-op. The reason one might want to do that is to ensure
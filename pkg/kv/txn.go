@@ -1272,6 +1272,15 @@ func (txn *Txn) ConfigureStepping(ctx context.Context, mode SteppingMode) (prevM
 	return txn.mu.sender.ConfigureStepping(ctx, mode)
 }
 
+// SetReadSeqNum sets the sequencing point at which subsequent read-only
+// operations are performed. Step-wise execution must be already enabled
+// for this to have an effect.
+func (txn *Txn) SetReadSeqNum(seq enginepb.TxnSeq) error {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.SetReadSeqNum(seq)
+}
+
 // CreateSavepoint establishes a savepoint.
 // This method is only valid when called on RootTxns.
 func (txn *Txn) CreateSavepoint(ctx context.Context) (SavepointToken, error) {
@@ -67,6 +67,16 @@ var migrations = []migration.Migration{
 		toCV(clusterversion.JoinTokensTable),
 		joinTokensTableMigration,
 	),
+	migration.NewSQLMigration(
+		"add the system.plan_baselines table",
+		toCV(clusterversion.PlanBaselines),
+		planBaselinesTableMigration,
+	),
+	migration.NewSQLMigration(
+		"add the system.statement_hints table",
+		toCV(clusterversion.StatementHints),
+		statementHintsTableMigration,
+	),
 }
 
 func init() {
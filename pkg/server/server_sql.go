@@ -49,12 +49,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/contention"
+	"github.com/cockroachdb/cockroach/pkg/sql/costcalibration"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/gcjob/gcjobnotifier"
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
+	"github.com/cockroachdb/cockroach/pkg/sql/planbaselines"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -64,6 +66,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmthints"
 	"github.com/cockroachdb/cockroach/pkg/sqlmigrations"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
@@ -107,6 +110,9 @@ type SQLServer struct {
 	// sqlMemMetrics are used to track memory usage of sql sessions.
 	sqlMemMetrics           sql.MemoryMetrics
 	stmtDiagnosticsRegistry *stmtdiagnostics.Registry
+	planBaselinesRegistry   *planbaselines.Registry
+	statementHintsRegistry  *stmthints.Registry
+	costCalibrator          *costcalibration.Calibrator
 	sqlLivenessProvider     sqlliveness.Provider
 	metricsRegistry         *metric.Registry
 	diagnosticsReporter     *diagnostics.Reporter
@@ -578,6 +584,9 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 	if backupRestoreKnobs := cfg.TestingKnobs.BackupRestore; backupRestoreKnobs != nil {
 		execCfg.BackupRestoreTestingKnobs = backupRestoreKnobs.(*sql.BackupRestoreTestingKnobs)
 	}
+	if ttlKnobs := cfg.TestingKnobs.TTL; ttlKnobs != nil {
+		execCfg.TTLTestingKnobs = ttlKnobs.(*sql.TTLTestingKnobs)
+	}
 
 	statsRefresher := stats.MakeRefresher(
 		cfg.Settings,
@@ -638,6 +647,20 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 		cfg.Settings,
 	)
 	execCfg.StmtDiagnosticsRecorder = stmtDiagnosticsRegistry
+	planBaselinesRegistry := planbaselines.NewRegistry(cfg.circularInternalExecutor, cfg.Settings)
+	execCfg.PlanBaselines = planBaselinesRegistry
+	statementHintsRegistry := stmthints.NewRegistry(cfg.circularInternalExecutor, cfg.Settings)
+	execCfg.StatementHints = statementHintsRegistry
+	// Secondary tenants can't calibrate the cost model, since there is no
+	// meta1 leaseholder among their SQL pods to pick the one that fits it.
+	var calibratorIsMeta1Leaseholder func(context.Context, hlc.ClockTimestamp) (bool, error)
+	if codec.ForSystemTenant() {
+		calibratorIsMeta1Leaseholder = cfg.isMeta1Leaseholder
+	}
+	costCalibrator := costcalibration.NewCalibrator(
+		cfg.circularInternalExecutor, cfg.Settings, cfg.clock, calibratorIsMeta1Leaseholder,
+	)
+	execCfg.CostCalibrator = costCalibrator
 
 	if cfg.TenantID == roachpb.SystemTenantID {
 		// We only need to attach a version upgrade hook if we're the system
@@ -714,6 +737,9 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 		internalMemMetrics:      internalMemMetrics,
 		sqlMemMetrics:           sqlMemMetrics,
 		stmtDiagnosticsRegistry: stmtDiagnosticsRegistry,
+		planBaselinesRegistry:   planBaselinesRegistry,
+		statementHintsRegistry:  statementHintsRegistry,
+		costCalibrator:          costCalibrator,
 		sqlLivenessProvider:     cfg.sqlLivenessProvider,
 		metricsRegistry:         cfg.registry,
 		diagnosticsReporter:     reporter,
@@ -749,6 +775,9 @@ func (s *SQLServer) preStart(
 		return err
 	}
 	s.stmtDiagnosticsRegistry.Start(ctx, stopper)
+	s.planBaselinesRegistry.Start(ctx, stopper)
+	s.statementHintsRegistry.Start(ctx, stopper)
+	s.costCalibrator.Start(ctx, stopper)
 
 	// Before serving SQL requests, we have to make sure the database is
 	// in an acceptable form for this version of the software.
@@ -0,0 +1,274 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type alterDefaultPrivilegesNode struct {
+	n     *tree.AlterDefaultPrivileges
+	roles []security.SQLUsername
+
+	// dbDesc is set if the default privileges apply to the whole database, and
+	// schemaDescs otherwise.
+	dbDesc      *dbdesc.Mutable
+	schemaDescs []*schemadesc.Mutable
+	// dbNames are the names of the databases of schemaDescs.
+	dbNames []string
+}
+
+// AlterDefaultPrivileges changes the privileges granted on the objects created
+// in the future by a role in the current database, or in the given schemas.
+// Privileges: the current user must be a member of the roles, like in
+// Postgres. No privilege is needed on the database or schemas, since the
+// default privileges only apply to the objects created by the roles.
+func (p *planner) AlterDefaultPrivileges(
+	ctx context.Context, n *tree.AlterDefaultPrivileges,
+) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.DefaultPrivileges) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"not all nodes are the correct version for ALTER DEFAULT PRIVILEGES")
+	}
+	sqltelemetry.IncIAMAlterDefaultPrivilegesCounter(strings.ToLower(n.TargetObject.String()))
+
+	if len(n.Schemas) > 0 && n.TargetObject == privilege.Schemas {
+		return nil, pgerror.New(pgcode.InvalidGrantOperation,
+			"cannot use IN SCHEMA clause when using GRANT/REVOKE ON SCHEMAS")
+	}
+	if err := privilege.ValidatePrivileges(n.Privileges, n.TargetObject.ObjectType()); err != nil {
+		return nil, err
+	}
+
+	roles := n.Roles
+	if len(roles) == 0 {
+		roles = []security.SQLUsername{p.User()}
+	}
+	if err := p.checkCanAlterDefaultPrivileges(ctx, roles); err != nil {
+		return nil, err
+	}
+
+	node := &alterDefaultPrivilegesNode{n: n, roles: roles}
+	if len(n.Schemas) == 0 {
+		if p.CurrentDatabase() == "" {
+			return nil, errNoDatabase
+		}
+		_, dbDesc, err := p.Descriptors().GetMutableDatabaseByName(ctx, p.txn,
+			p.CurrentDatabase(), tree.DatabaseLookupFlags{Required: true})
+		if err != nil {
+			return nil, err
+		}
+		node.dbDesc = dbDesc
+		return node, nil
+	}
+
+	for _, sc := range n.Schemas {
+		dbName := p.CurrentDatabase()
+		if sc.ExplicitCatalog {
+			dbName = sc.Catalog()
+		}
+		_, dbDesc, err := p.Descriptors().GetImmutableDatabaseByName(ctx, p.txn,
+			dbName, tree.DatabaseLookupFlags{Required: true})
+		if err != nil {
+			return nil, err
+		}
+		_, resSchema, err := p.ResolveMutableSchemaDescriptor(
+			ctx, dbDesc.GetID(), sc.Schema(), true /* required */)
+		if err != nil {
+			return nil, err
+		}
+		if resSchema.Kind != catalog.SchemaUserDefined {
+			err := pgerror.Newf(pgcode.InvalidSchemaName,
+				"cannot set default privileges in schema %q", resSchema.Name)
+			if resSchema.Kind == catalog.SchemaPublic {
+				err = errors.WithHint(err,
+					"omit IN SCHEMA to set the default privileges for the whole database")
+			}
+			return nil, err
+		}
+		node.schemaDescs = append(node.schemaDescs, resSchema.Desc.(*schemadesc.Mutable))
+		node.dbNames = append(node.dbNames, dbDesc.GetName())
+	}
+	return node, nil
+}
+
+// checkCanAlterDefaultPrivileges returns an error if the current user is
+// neither an admin nor a member of all the given roles.
+func (p *planner) checkCanAlterDefaultPrivileges(
+	ctx context.Context, roles []security.SQLUsername,
+) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if hasAdmin {
+		return nil
+	}
+	memberOf, err := p.MemberOfWithAdminOption(ctx, p.User())
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if role == p.User() {
+			continue
+		}
+		if _, ok := memberOf[role]; !ok {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be a member of %s to change its default privileges", role)
+		}
+	}
+	return nil
+}
+
+func (n *alterDefaultPrivilegesNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+
+	// Check that the roles and the grantees exist. The public role can be a
+	// grantee even though it does not exist.
+	users, err := p.GetAllRoles(ctx)
+	if err != nil {
+		return err
+	}
+	for _, role := range n.roles {
+		if _, ok := users[role]; !ok {
+			return pgerror.Newf(pgcode.UndefinedObject, "role %s does not exist", role)
+		}
+	}
+	users[security.PublicRoleName()] = true // isRole
+	for _, grantee := range n.n.Grantees {
+		if _, ok := users[grantee]; !ok {
+			return pgerror.Newf(pgcode.UndefinedObject, "user or role %s does not exist", grantee)
+		}
+	}
+
+	stmt := tree.AsStringWithFQNames(n.n, p.Ann())
+	if n.dbDesc != nil {
+		if err := n.changeDefaultPrivileges(&n.dbDesc.DefaultPrivileges); err != nil {
+			return err
+		}
+		if err := p.writeNonDropDatabaseChange(ctx, n.dbDesc, stmt); err != nil {
+			return err
+		}
+		return n.logEvents(params, n.dbDesc.GetID(), n.dbDesc.GetName(), "" /* schemaName */)
+	}
+
+	for i, schemaDesc := range n.schemaDescs {
+		if err := n.changeDefaultPrivileges(&schemaDesc.DefaultPrivileges); err != nil {
+			return err
+		}
+		if err := p.writeSchemaDescChange(ctx, schemaDesc, stmt); err != nil {
+			return err
+		}
+		if err := n.logEvents(params, schemaDesc.GetID(), n.dbNames[i], schemaDesc.GetName()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changeDefaultPrivileges grants or revokes the privileges of the statement in
+// the given default privileges, which are allocated if needed and cleared if
+// they become empty.
+func (n *alterDefaultPrivilegesNode) changeDefaultPrivileges(
+	ptr **descpb.DefaultPrivilegeDescriptor,
+) error {
+	if *ptr == nil {
+		*ptr = &descpb.DefaultPrivilegeDescriptor{}
+	}
+	defaultPrivs := *ptr
+	for _, role := range n.roles {
+		for _, grantee := range n.n.Grantees {
+			if n.n.IsGrant {
+				defaultPrivs.Grant(role, n.n.TargetObject, grantee, n.n.Privileges)
+			} else {
+				defaultPrivs.Revoke(role, n.n.TargetObject, grantee, n.n.Privileges)
+			}
+		}
+	}
+	if defaultPrivs.IsEmpty() {
+		*ptr = nil
+	}
+	return defaultPrivs.Validate()
+}
+
+// logEvents records the change of the default privileges of each role and
+// grantee in the event log.
+func (n *alterDefaultPrivilegesNode) logEvents(
+	params runParams, descID descpb.ID, dbName, schemaName string,
+) error {
+	var privs eventpb.CommonSQLPrivilegeEventDetails
+	if n.n.IsGrant {
+		privs.GrantedPrivileges = n.n.Privileges.SortedNames()
+	} else {
+		privs.RevokedPrivileges = n.n.Privileges.SortedNames()
+	}
+	for _, role := range n.roles {
+		for _, grantee := range n.n.Grantees {
+			privs.Grantee = grantee.Normalized()
+			if err := params.p.logEvent(params.ctx, descID, &eventpb.AlterDefaultPrivileges{
+				CommonSQLPrivilegeEventDetails: privs,
+				DatabaseName:                   dbName,
+				SchemaName:                     schemaName,
+				RoleName:                       role.Normalized(),
+				ObjectType:                     n.n.TargetObject.String(),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyDefaultPrivileges grants the default privileges on the objects of the
+// given type created by the owner of privs, the privileges of a new object in
+// the given database and schema. Both the default privileges of the database
+// and those of the schema apply.
+func (p *planner) applyDefaultPrivileges(
+	ctx context.Context,
+	privs *descpb.PrivilegeDescriptor,
+	dbDesc catalog.DatabaseDescriptor,
+	schemaID descpb.ID,
+	targetObject privilege.TargetObjectType,
+) error {
+	owner := privs.Owner()
+	dbDesc.GetDefaultPrivileges().ApplyTo(privs, owner, targetObject)
+	resolvedSchema, err := p.Descriptors().GetImmutableSchemaByID(
+		ctx, p.txn, schemaID, tree.SchemaLookupFlags{Required: true})
+	if err != nil {
+		return err
+	}
+	if resolvedSchema.Kind == catalog.SchemaUserDefined {
+		resolvedSchema.Desc.GetDefaultPrivileges().ApplyTo(privs, owner, targetObject)
+	}
+	return nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+func (n *alterDefaultPrivilegesNode) ReadingOwnWrites() {}
+
+func (n *alterDefaultPrivilegesNode) Next(runParams) (bool, error) { return false, nil }
+func (n *alterDefaultPrivilegesNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *alterDefaultPrivilegesNode) Close(context.Context)        {}
@@ -0,0 +1,134 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type alterDomainNode struct {
+	n    *tree.AlterDomain
+	desc *typedesc.Mutable
+}
+
+// alterDomainNode implements planNode. We set n here to satisfy the linter.
+var _ planNode = &alterDomainNode{n: nil}
+
+func (p *planner) AlterDomain(ctx context.Context, n *tree.AlterDomain) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER DOMAIN",
+	); err != nil {
+		return nil, err
+	}
+
+	// Resolve the domain.
+	desc, err := p.ResolveMutableTypeDescriptor(ctx, n.Domain, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if desc.Kind != descpb.TypeDescriptor_DOMAIN {
+		return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a domain", desc.Name)
+	}
+
+	// The user needs ownership privilege to alter the domain.
+	if err := p.canModifyType(ctx, desc); err != nil {
+		return nil, err
+	}
+
+	return &alterDomainNode{
+		n:    n,
+		desc: desc,
+	}, nil
+}
+
+func (n *alterDomainNode) startExec(params runParams) error {
+	telemetry.Inc(n.n.Cmd.TelemetryCounter())
+
+	var err error
+	switch t := n.n.Cmd.(type) {
+	case *tree.AlterDomainAddConstraint:
+		err = params.p.addDomainConstraint(params.ctx, n, t)
+	case *tree.AlterDomainDropConstraint:
+		err = params.p.dropDomainConstraint(params.ctx, n, t)
+	default:
+		err = errors.AssertionFailedf("unknown alter domain cmd %s", t)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Write a log event.
+	return params.p.logEvent(params.ctx,
+		n.desc.ID,
+		&eventpb.AlterType{
+			TypeName: tree.AsStringWithFQNames(n.n.Domain, params.p.Ann()),
+		})
+}
+
+// addDomainConstraint adds a CHECK constraint to a domain. The constraint is
+// enforced for new values right away, and the type schema change job validates
+// it against the existing values of the columns that use the domain.
+func (p *planner) addDomainConstraint(
+	ctx context.Context, n *alterDomainNode, cmd *tree.AlterDomainAddConstraint,
+) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.Domains) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"not all nodes are the correct version for domain constraints")
+	}
+	typeName := tree.NewUnqualifiedTypeName(tree.Name(n.desc.Name))
+	check, err := p.makeDomainCheck(ctx, typeName, n.desc.Domain, cmd.Name, cmd.Expr)
+	if err != nil {
+		return err
+	}
+	check.Validity = descpb.ConstraintValidity_Validating
+	n.desc.Domain.Checks = append(n.desc.Domain.Checks, check)
+	return p.writeTypeSchemaChange(ctx, n.desc, tree.AsStringWithFQNames(n.n, p.Ann()))
+}
+
+// dropDomainConstraint removes a CHECK constraint from a domain.
+func (p *planner) dropDomainConstraint(
+	ctx context.Context, n *alterDomainNode, cmd *tree.AlterDomainDropConstraint,
+) error {
+	checks := n.desc.Domain.Checks
+	for i := range checks {
+		if checks[i].Name == string(cmd.Name) {
+			n.desc.Domain.Checks = append(checks[:i:i], checks[i+1:]...)
+			return p.writeTypeSchemaChange(ctx, n.desc, tree.AsStringWithFQNames(n.n, p.Ann()))
+		}
+	}
+	if cmd.IfExists {
+		p.BufferClientNotice(
+			ctx,
+			pgnotice.Newf("constraint %q of domain %q does not exist, skipping", cmd.Name, n.desc.Name),
+		)
+		return nil
+	}
+	return pgerror.Newf(pgcode.UndefinedObject,
+		"constraint %q of domain %q does not exist", cmd.Name, n.desc.Name)
+}
+
+func (n *alterDomainNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *alterDomainNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *alterDomainNode) Close(ctx context.Context)           {}
+func (n *alterDomainNode) ReadingOwnWrites()                   {}
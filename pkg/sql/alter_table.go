@@ -425,6 +425,18 @@ func (n *alterTableNode) startExec(params runParams) error {
 				continue
 			}
 
+			if ttl := n.tableDesc.GetRowLevelTTL(); ttl != nil && ttl.ExpirationColumnID == colToDrop.GetID() {
+				return pgerror.Newf(
+					pgcode.InvalidColumnReference,
+					"cannot drop column %s as it is used to store the expiration time of rows with row-level TTL",
+					t.Column,
+				)
+			}
+
+			if err := checkColumnNotUsedByPolicies(n.tableDesc, colToDrop); err != nil {
+				return err
+			}
+
 			// If the dropped column uses a sequence, remove references to it from that sequence.
 			if colToDrop.NumUsesSequences() > 0 {
 				if err := params.p.removeSequenceDependencies(params.ctx, n.tableDesc, colToDrop.ColumnDesc()); err != nil {
@@ -878,6 +890,19 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			descriptorChanged = descriptorChanged || changed
 
+		case *tree.AlterTableSetRowLevelSecurity:
+			if err := params.p.checkCanAlterRowLevelSecurity(params.ctx, n.tableDesc); err != nil {
+				return err
+			}
+			if t.Enable && !params.p.ExecCfg().Settings.Version.IsActive(
+				params.ctx, clusterversion.RowLevelSecurity,
+			) {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"not all nodes are the correct version for row-level security")
+			}
+			descriptorChanged = descriptorChanged || n.tableDesc.RowLevelSecurity != t.Enable
+			n.tableDesc.RowLevelSecurity = t.Enable
+
 		case *tree.AlterTableInjectStats:
 			sd, ok := n.statsData[i]
 			if !ok {
@@ -1585,18 +1585,24 @@ func ValidateInvertedIndexes(
 
 			start := timeutil.Now()
 			col := idx.InvertedColumnName()
+			colExpr := fmt.Sprintf("%q", col)
+			// The column backing an expression index element is inaccessible, so
+			// the expression it is computed from must be used in its place.
+			if c, err := tableDesc.FindColumnWithName(tree.Name(col)); err == nil && c.IsInaccessible() {
+				colExpr = fmt.Sprintf("(%s)", c.GetComputeExpr())
+			}
 
 			if err := runHistoricalTxn(ctx, func(ctx context.Context, txn *kv.Txn, ie *InternalExecutor) error {
 				var stmt string
 				if geoindex.IsEmptyConfig(&idx.GeoConfig) {
 					stmt = fmt.Sprintf(
-						`SELECT coalesce(sum_int(crdb_internal.num_inverted_index_entries(%q, %d)), 0) FROM [%d AS t]`,
-						col, idx.Version, tableDesc.GetID(),
+						`SELECT coalesce(sum_int(crdb_internal.num_inverted_index_entries(%s, %d)), 0) FROM [%d AS t]`,
+						colExpr, idx.Version, tableDesc.GetID(),
 					)
 				} else {
 					stmt = fmt.Sprintf(
-						`SELECT coalesce(sum_int(crdb_internal.num_geo_inverted_index_entries(%d, %d, %q)), 0) FROM [%d AS t]`,
-						tableDesc.GetID(), idx.ID, col, tableDesc.GetID(),
+						`SELECT coalesce(sum_int(crdb_internal.num_geo_inverted_index_entries(%d, %d, %s)), 0) FROM [%d AS t]`,
+						tableDesc.GetID(), idx.ID, colExpr, tableDesc.GetID(),
 					)
 				}
 				// If the index is a partial index the predicate must be added
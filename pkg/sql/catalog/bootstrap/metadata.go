@@ -347,6 +347,8 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 	// Tables introduced in 21.1.
 
 	target.AddDescriptor(keys.SystemDatabaseID, systemschema.JoinTokensTable)
+	target.AddDescriptor(keys.SystemDatabaseID, systemschema.PlanBaselinesTable)
+	target.AddDescriptor(keys.SystemDatabaseID, systemschema.StatementHintsTable)
 }
 
 // addSplitIDs adds a split point for each of the PseudoTableIDs to the supplied
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/errors"
 )
@@ -59,7 +60,9 @@ func IndexForDisplay(
 		f.FormatNode(tableName)
 	}
 	f.WriteString(" (")
-	index.ColNamesFormat(f)
+	if err := formatIndexColumns(ctx, table, index, f, semaCtx); err != nil {
+		return "", err
+	}
 	f.WriteByte(')')
 
 	if index.IsSharded() {
@@ -168,3 +171,53 @@ func IndexForDisplay(
 
 	return f.CloseAndGetString(), nil
 }
+
+// formatIndexColumns writes the explicit columns of the index, along with their
+// directions, to f. Inaccessible virtual columns that back expression index
+// elements are formatted as their expressions.
+func formatIndexColumns(
+	ctx context.Context,
+	table catalog.TableDescriptor,
+	index *descpb.IndexDescriptor,
+	f *tree.FmtCtx,
+	semaCtx *tree.SemaContext,
+) error {
+	startIdx := index.ExplicitColumnStartIdx()
+	for i := startIdx; i < len(index.ColumnNames); i++ {
+		if i > startIdx {
+			f.WriteString(", ")
+		}
+		col, err := table.FindColumnWithName(tree.Name(index.ColumnNames[i]))
+		if err == nil && col.IsInaccessible() {
+			expr, err := schemaexpr.FormatExprForDisplay(
+				ctx, table, col.GetComputeExpr(), semaCtx, tree.FmtParsable,
+			)
+			if err != nil {
+				return err
+			}
+			// As in CREATE INDEX, expressions other than function calls must be
+			// wrapped in parentheses.
+			parsed, err := parser.ParseExpr(col.GetComputeExpr())
+			if err != nil {
+				return err
+			}
+			if _, isFunc := parsed.(*tree.FuncExpr); isFunc {
+				f.WriteString(expr)
+			} else {
+				f.WriteByte('(')
+				f.WriteString(expr)
+				f.WriteByte(')')
+			}
+		} else {
+			f.FormatNameP(&index.ColumnNames[i])
+		}
+		if index.Type != descpb.IndexDescriptor_INVERTED {
+			f.WriteByte(' ')
+			f.WriteString(index.ColumnDirections[i].String())
+		} else if opClass := index.InvertedColumnOpClass(); opClass != "" && i == len(index.ColumnNames)-1 {
+			f.WriteByte(' ')
+			f.FormatNode(&opClass)
+		}
+	}
+	return nil
+}
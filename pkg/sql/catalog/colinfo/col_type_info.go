@@ -95,12 +95,36 @@ func ValidateColumnDefType(t *types.T) error {
 		if err := types.CheckArrayElementType(t.ArrayContents()); err != nil {
 			return err
 		}
+		if t.ArrayContents().Family() == types.TupleFamily {
+			// Arrays of composite types have no value encoding.
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"arrays of composite types are unsupported as column type: %s", t.SQLString())
+		}
+		if t.ArrayContents().IsDomain() {
+			// The constraints of a domain are not enforced on array elements.
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"arrays of domains are unsupported as column type: %s", t.SQLString())
+		}
 		return ValidateColumnDefType(t.ArrayContents())
 
+	case types.TupleFamily:
+		// Only composite types can be used as column types, anonymous tuple types
+		// cannot.
+		if !t.UserDefined() {
+			return pgerror.Newf(pgcode.InvalidTableDefinition,
+				"value type %s cannot be used for table columns", t.String())
+		}
+		for _, elem := range t.TupleContents() {
+			if err := ValidateColumnDefType(elem); err != nil {
+				return err
+			}
+		}
+
 	case types.BitFamily, types.IntFamily, types.FloatFamily, types.BoolFamily, types.BytesFamily, types.DateFamily,
 		types.INetFamily, types.IntervalFamily, types.JsonFamily, types.OidFamily, types.TimeFamily,
 		types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily, types.TimeTZFamily,
-		types.GeographyFamily, types.GeometryFamily, types.EnumFamily, types.Box2DFamily:
+		types.GeographyFamily, types.GeometryFamily, types.EnumFamily, types.Box2DFamily,
+		types.TSVectorFamily, types.TSQueryFamily:
 		// These types are OK.
 
 	default:
@@ -123,7 +147,15 @@ func ColumnTypeIsIndexable(t *types.T) bool {
 func ColumnTypeIsInvertedIndexable(t *types.T) bool {
 	family := t.Family()
 	return family == types.JsonFamily || family == types.ArrayFamily ||
-		family == types.GeographyFamily || family == types.GeometryFamily
+		family == types.GeographyFamily || family == types.GeometryFamily ||
+		family == types.TSVectorFamily
+}
+
+// ColumnTypeIsTrigramIndexable returns whether the type t is valid to be indexed
+// using a trigram inverted index, i.e. an inverted index created with the
+// gin_trgm_ops operator class.
+func ColumnTypeIsTrigramIndexable(t *types.T) bool {
+	return t.Family() == types.StringFamily
 }
 
 // MustBeValueEncoded returns true if columns of the given kind can only be value
@@ -137,7 +169,8 @@ func MustBeValueEncoded(semanticType *types.T) bool {
 		default:
 			return MustBeValueEncoded(semanticType.ArrayContents())
 		}
-	case types.JsonFamily, types.TupleFamily, types.GeographyFamily, types.GeometryFamily:
+	case types.JsonFamily, types.TupleFamily, types.GeographyFamily, types.GeometryFamily,
+		types.TSVectorFamily, types.TSQueryFamily:
 		return true
 	}
 	return false
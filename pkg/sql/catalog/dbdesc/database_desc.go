@@ -209,6 +209,7 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 
 	// Validate the privilege descriptor.
 	vea.Report(desc.Privileges.Validate(desc.GetID(), privilege.Database))
+	vea.Report(desc.DefaultPrivileges.Validate())
 
 	if desc.IsMultiRegion() {
 		desc.validateMultiRegion(vea)
@@ -83,6 +83,23 @@ func (x ForeignKeyReference_Action) String() string {
 	}
 }
 
+// ConstraintDeferrabilityValue allows the conversion from a
+// tree.ConstraintDeferrability to a ConstraintDeferrability.
+var ConstraintDeferrabilityValue = [...]ConstraintDeferrability{
+	tree.NotDeferrableConstraint:      ConstraintDeferrability_NotDeferrable,
+	tree.InitiallyImmediateConstraint: ConstraintDeferrability_InitiallyImmediate,
+	tree.InitiallyDeferredConstraint:  ConstraintDeferrability_InitiallyDeferred,
+}
+
+// ConstraintDeferrabilityType allows the conversion from a
+// ConstraintDeferrability to a tree.ConstraintDeferrability.
+// This should match ConstraintDeferrabilityValue.
+var ConstraintDeferrabilityType = [...]tree.ConstraintDeferrability{
+	ConstraintDeferrability_NotDeferrable:      tree.NotDeferrableConstraint,
+	ConstraintDeferrability_InitiallyImmediate: tree.InitiallyImmediateConstraint,
+	ConstraintDeferrability_InitiallyDeferred:  tree.InitiallyDeferredConstraint,
+}
+
 // ConstraintType is used to identify the type of a constraint.
 type ConstraintType string
 
@@ -120,3 +137,16 @@ type ConstraintDetail struct {
 	// Only populated for Check Constraints.
 	CheckConstraint *TableDescriptor_CheckConstraint
 }
+
+// Deferrability returns the deferrability of the constraint. Only foreign key
+// and UNIQUE WITHOUT INDEX constraints can be deferrable.
+func (c ConstraintDetail) Deferrability() ConstraintDeferrability {
+	switch {
+	case c.FK != nil:
+		return c.FK.Deferrability
+	case c.UniqueWithoutIndexConstraint != nil:
+		return c.UniqueWithoutIndexConstraint.Deferrability
+	default:
+		return ConstraintDeferrability_NotDeferrable
+	}
+}
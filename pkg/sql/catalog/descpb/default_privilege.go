@@ -0,0 +1,152 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package descpb
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/errors"
+)
+
+// Role accesses the role field.
+func (d DefaultPrivilegeDescriptor_DefaultPrivilegesForRole) Role() security.SQLUsername {
+	return d.RoleProto.Decode()
+}
+
+// less orders the default privileges by role and target object type.
+func (d DefaultPrivilegeDescriptor_DefaultPrivilegesForRole) less(
+	role security.SQLUsername, targetObject privilege.TargetObjectType,
+) bool {
+	if d.Role() != role {
+		return d.Role().LessThan(role)
+	}
+	return d.TargetObjectType < targetObject
+}
+
+// search returns the index at which the default privileges for the given role
+// and target object type are, or should be inserted, and whether they exist.
+func (d *DefaultPrivilegeDescriptor) search(
+	role security.SQLUsername, targetObject privilege.TargetObjectType,
+) (int, bool) {
+	perRole := d.DefaultPrivilegesPerRole
+	idx := sort.Search(len(perRole), func(i int) bool {
+		return !perRole[i].less(role, targetObject)
+	})
+	found := idx < len(perRole) && perRole[idx].Role() == role &&
+		perRole[idx].TargetObjectType == targetObject
+	return idx, found
+}
+
+// Grant adds privileges to the default privileges of grantee on the objects
+// of the given type created by role.
+func (d *DefaultPrivilegeDescriptor) Grant(
+	role security.SQLUsername,
+	targetObject privilege.TargetObjectType,
+	grantee security.SQLUsername,
+	privList privilege.List,
+) {
+	idx, found := d.search(role, targetObject)
+	if !found {
+		d.DefaultPrivilegesPerRole = append(d.DefaultPrivilegesPerRole,
+			DefaultPrivilegeDescriptor_DefaultPrivilegesForRole{})
+		copy(d.DefaultPrivilegesPerRole[idx+1:], d.DefaultPrivilegesPerRole[idx:])
+		d.DefaultPrivilegesPerRole[idx] = DefaultPrivilegeDescriptor_DefaultPrivilegesForRole{
+			RoleProto:        role.EncodeProto(),
+			TargetObjectType: targetObject,
+		}
+	}
+	// The users are managed in the same way as in a PrivilegeDescriptor.
+	forRole := &d.DefaultPrivilegesPerRole[idx]
+	privs := PrivilegeDescriptor{Users: forRole.Users}
+	privs.Grant(grantee, privList)
+	forRole.Users = privs.Users
+}
+
+// Revoke removes privileges from the default privileges of grantee on the
+// objects of the given type created by role.
+func (d *DefaultPrivilegeDescriptor) Revoke(
+	role security.SQLUsername,
+	targetObject privilege.TargetObjectType,
+	grantee security.SQLUsername,
+	privList privilege.List,
+) {
+	idx, found := d.search(role, targetObject)
+	if !found {
+		return
+	}
+	forRole := &d.DefaultPrivilegesPerRole[idx]
+	privs := PrivilegeDescriptor{Users: forRole.Users}
+	privs.Revoke(grantee, privList, targetObject.ObjectType())
+	forRole.Users = privs.Users
+	if len(forRole.Users) == 0 {
+		d.DefaultPrivilegesPerRole = append(
+			d.DefaultPrivilegesPerRole[:idx], d.DefaultPrivilegesPerRole[idx+1:]...)
+	}
+}
+
+// IsEmpty returns true if no default privileges are set. It can be called on
+// a nil descriptor.
+func (d *DefaultPrivilegeDescriptor) IsEmpty() bool {
+	return d == nil || len(d.DefaultPrivilegesPerRole) == 0
+}
+
+// ApplyTo grants the default privileges on the objects of the given type
+// created by role to the users of privs, the privileges of a new object. It
+// can be called on a nil descriptor.
+func (d *DefaultPrivilegeDescriptor) ApplyTo(
+	privs *PrivilegeDescriptor, role security.SQLUsername, targetObject privilege.TargetObjectType,
+) {
+	if d == nil {
+		return
+	}
+	idx, found := d.search(role, targetObject)
+	if !found {
+		return
+	}
+	objectType := targetObject.ObjectType()
+	for _, u := range d.DefaultPrivilegesPerRole[idx].Users {
+		privs.Grant(u.User(), privilege.ListFromBitField(u.Privileges, objectType))
+	}
+}
+
+// Validate returns an error if the default privileges are not sorted, or if
+// they contain privileges which cannot be granted on their target objects. It
+// can be called on a nil descriptor.
+func (d *DefaultPrivilegeDescriptor) Validate() error {
+	if d == nil {
+		return nil
+	}
+	for i, forRole := range d.DefaultPrivilegesPerRole {
+		if forRole.Role().Undefined() {
+			return errors.AssertionFailedf("found no role for default privileges")
+		}
+		switch forRole.TargetObjectType {
+		case privilege.Tables, privilege.Sequences, privilege.Types, privilege.Schemas:
+		default:
+			return errors.AssertionFailedf("invalid target object type %d for default privileges of %s",
+				forRole.TargetObjectType, forRole.Role())
+		}
+		if i > 0 && !d.DefaultPrivilegesPerRole[i-1].less(forRole.Role(), forRole.TargetObjectType) {
+			return errors.AssertionFailedf("default privileges are not sorted")
+		}
+		objectType := forRole.TargetObjectType.ObjectType()
+		for _, u := range forRole.Users {
+			privs := privilege.ListFromBitField(u.Privileges, privilege.Any)
+			if err := privilege.ValidatePrivileges(privs, objectType); err != nil {
+				return errors.Wrapf(err, "default privileges of %s on %s for %s",
+					u.User(), forRole.TargetObjectType, forRole.Role())
+			}
+		}
+	}
+	return nil
+}
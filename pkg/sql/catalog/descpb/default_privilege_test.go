@@ -0,0 +1,136 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package descpb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestDefaultPrivileges(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	deployUser := security.MakeSQLUsernameFromPreNormalizedString("deploy")
+	analyticsRole := security.MakeSQLUsernameFromPreNormalizedString("analytics")
+	testUser := security.TestUserName()
+
+	var d DefaultPrivilegeDescriptor
+	d.Grant(testUser, privilege.Types, analyticsRole, privilege.List{privilege.USAGE})
+	d.Grant(deployUser, privilege.Tables, analyticsRole, privilege.List{privilege.SELECT})
+	d.Grant(deployUser, privilege.Tables, testUser, privilege.List{privilege.ALL})
+	d.Grant(deployUser, privilege.Sequences, analyticsRole, privilege.List{privilege.SELECT})
+	if err := d.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if d.IsEmpty() {
+		t.Fatal("expected default privileges")
+	}
+
+	// The default privileges only apply to the objects of the given type
+	// created by the given role.
+	for _, tc := range []struct {
+		role         security.SQLUsername
+		targetObject privilege.TargetObjectType
+		show         []UserPrivilegeString
+	}{
+		{deployUser, privilege.Tables, []UserPrivilegeString{
+			{security.AdminRoleName(), []string{"ALL"}},
+			{analyticsRole, []string{"SELECT"}},
+			{security.RootUserName(), []string{"ALL"}},
+			{testUser, []string{"ALL"}},
+		}},
+		{deployUser, privilege.Types, []UserPrivilegeString{
+			{security.AdminRoleName(), []string{"ALL"}},
+			{security.RootUserName(), []string{"ALL"}},
+		}},
+		{testUser, privilege.Types, []UserPrivilegeString{
+			{security.AdminRoleName(), []string{"ALL"}},
+			{analyticsRole, []string{"USAGE"}},
+			{security.RootUserName(), []string{"ALL"}},
+		}},
+		{testUser, privilege.Tables, []UserPrivilegeString{
+			{security.AdminRoleName(), []string{"ALL"}},
+			{security.RootUserName(), []string{"ALL"}},
+		}},
+	} {
+		privs := NewDefaultPrivilegeDescriptor(tc.role)
+		d.ApplyTo(privs, tc.role, tc.targetObject)
+		if show := privs.Show(tc.targetObject.ObjectType()); !reflect.DeepEqual(show, tc.show) {
+			t.Fatalf("%s on %s: expected %v, got %v", tc.role, tc.targetObject, tc.show, show)
+		}
+	}
+
+	// Revoking a privilege from ALL leaves the other privileges.
+	d.Revoke(deployUser, privilege.Tables, testUser, privilege.List{privilege.DROP})
+	privs := NewDefaultPrivilegeDescriptor(deployUser)
+	d.ApplyTo(privs, deployUser, privilege.Tables)
+	expected := []UserPrivilegeString{
+		{security.AdminRoleName(), []string{"ALL"}},
+		{analyticsRole, []string{"SELECT"}},
+		{security.RootUserName(), []string{"ALL"}},
+		{testUser, []string{"CREATE", "DELETE", "GRANT", "INSERT", "SELECT", "UPDATE", "ZONECONFIG"}},
+	}
+	if show := privs.Show(privilege.Table); !reflect.DeepEqual(show, expected) {
+		t.Fatalf("expected %v, got %v", expected, show)
+	}
+
+	// Revoking all the privileges removes the default privileges.
+	d.Revoke(deployUser, privilege.Tables, testUser, privilege.List{privilege.ALL})
+	d.Revoke(deployUser, privilege.Tables, analyticsRole, privilege.List{privilege.SELECT})
+	d.Revoke(deployUser, privilege.Sequences, analyticsRole, privilege.List{privilege.SELECT})
+	d.Revoke(testUser, privilege.Types, analyticsRole, privilege.List{privilege.USAGE})
+	if !d.IsEmpty() {
+		t.Fatalf("expected no default privileges, got %v", d)
+	}
+
+	// A nil descriptor has no default privileges.
+	var nilDesc *DefaultPrivilegeDescriptor
+	if !nilDesc.IsEmpty() {
+		t.Fatal("expected no default privileges")
+	}
+	nilDesc.ApplyTo(privs, deployUser, privilege.Tables)
+	if err := nilDesc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaultPrivilegesValidate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	deployUser := security.MakeSQLUsernameFromPreNormalizedString("deploy")
+	testUser := security.TestUserName()
+
+	var d DefaultPrivilegeDescriptor
+	d.Grant(deployUser, privilege.Types, testUser, privilege.List{privilege.SELECT})
+	if err := d.Validate(); !testutils.IsError(err, "invalid privilege type SELECT for type") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d = DefaultPrivilegeDescriptor{DefaultPrivilegesPerRole: []DefaultPrivilegeDescriptor_DefaultPrivilegesForRole{
+		{RoleProto: testUser.EncodeProto(), TargetObjectType: privilege.Tables},
+		{RoleProto: deployUser.EncodeProto(), TargetObjectType: privilege.Tables},
+	}}
+	if err := d.Validate(); !testutils.IsError(err, "default privileges are not sorted") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d = DefaultPrivilegeDescriptor{DefaultPrivilegesPerRole: []DefaultPrivilegeDescriptor_DefaultPrivilegesForRole{
+		{RoleProto: testUser.EncodeProto()},
+	}}
+	if err := d.Validate(); !testutils.IsError(err, "invalid target object type 0") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
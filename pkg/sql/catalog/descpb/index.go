@@ -13,6 +13,8 @@ package descpb
 import (
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
@@ -98,10 +100,26 @@ func (desc *IndexDescriptor) ColNamesFormat(ctx *tree.FmtCtx) {
 func (desc *IndexDescriptor) FillColumns(elems tree.IndexElemList) error {
 	desc.ColumnNames = make([]string, 0, len(elems))
 	desc.ColumnDirections = make([]IndexDescriptor_Direction, 0, len(elems))
-	for _, c := range elems {
+	for i, c := range elems {
 		if c.Expr != nil {
 			return unimplemented.NewWithIssuef(9682, "only simple columns are supported as index elements")
 		}
+		if c.OpClass != "" {
+			if desc.Type != IndexDescriptor_INVERTED {
+				return pgerror.Newf(pgcode.UndefinedObject,
+					"operator class %q does not exist for access method \"btree\"", c.OpClass)
+			}
+			if i != len(elems)-1 {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"operator class %q is only allowed for the last column of an inverted index", c.OpClass)
+			}
+			switch c.OpClass {
+			case TrigramOpClass, "gist_trgm_ops":
+				desc.InvertedColumnKind = IndexDescriptor_TRIGRAM
+			default:
+				return pgerror.Newf(pgcode.UndefinedObject, "operator class %q does not exist", c.OpClass)
+			}
+		}
 		desc.ColumnNames = append(desc.ColumnNames, string(c.Column))
 		switch c.Direction {
 		case tree.Ascending, tree.DefaultDirection:
@@ -115,6 +133,21 @@ func (desc *IndexDescriptor) FillColumns(elems tree.IndexElemList) error {
 	return nil
 }
 
+// TrigramOpClass is the operator class with which trigram inverted indexes are
+// created. Trigram indexes created with the gist_trgm_ops operator class are
+// displayed with it as well.
+const TrigramOpClass = "gin_trgm_ops"
+
+// InvertedColumnOpClass returns the operator class that the inverted column of
+// the index is displayed with, or the empty string if the index is not an
+// inverted index or its inverted column is of the DEFAULT kind.
+func (desc *IndexDescriptor) InvertedColumnOpClass() tree.Name {
+	if desc.Type == IndexDescriptor_INVERTED && desc.InvertedColumnKind == IndexDescriptor_TRIGRAM {
+		return TrigramOpClass
+	}
+	return ""
+}
+
 type returnTrue struct{}
 
 func (returnTrue) Error() string { panic("unimplemented") }
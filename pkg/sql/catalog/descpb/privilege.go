@@ -449,6 +449,8 @@ var SystemAllowedPrivileges = map[ID]privilege.List{
 	keys.SqllivenessID:                        privilege.ReadWriteData,
 	keys.MigrationsID:                         privilege.ReadWriteData,
 	keys.JoinTokensTableID:                    privilege.ReadWriteData,
+	keys.PlanBaselinesTableID:                 privilege.ReadWriteData,
+	keys.StatementHintsTableID:                privilege.ReadWriteData,
 }
 
 // SetOwner sets the owner of the privilege descriptor to the provided string.
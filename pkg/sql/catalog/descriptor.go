@@ -166,6 +166,7 @@ type DatabaseDescriptor interface {
 	DatabaseDesc() *descpb.DatabaseDescriptor
 
 	GetRegionConfig() *descpb.DatabaseDescriptor_RegionConfig
+	GetDefaultPrivileges() *descpb.DefaultPrivilegeDescriptor
 	IsMultiRegion() bool
 	PrimaryRegionName() (descpb.RegionName, error)
 	MultiRegionEnumID() (descpb.ID, error)
@@ -179,6 +180,7 @@ type DatabaseDescriptor interface {
 type SchemaDescriptor interface {
 	Descriptor
 	SchemaDesc() *descpb.SchemaDescriptor
+	GetDefaultPrivileges() *descpb.DefaultPrivilegeDescriptor
 }
 
 // TableDescriptor is an interface around the table descriptor types.
@@ -348,6 +350,11 @@ type TableDescriptor interface {
 	IsLocalityGlobal() bool
 	GetRegionalByTableRegion() (descpb.RegionName, error)
 	GetRegionalByRowTableRegionColumnName() (tree.Name, error)
+
+	GetRowLevelTTL() *descpb.TableDescriptor_RowLevelTTL
+	GetRowLevelSecurity() bool
+	GetPolicies() []descpb.TableDescriptor_Policy
+	GetIncrementalRefresh() *descpb.TableDescriptor_IncrementalRefresh
 }
 
 // TypeDescriptor will eventually be called typedesc.Descriptor.
@@ -142,6 +142,7 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 
 	// Validate the privilege descriptor.
 	vea.Report(desc.Privileges.Validate(desc.GetID(), privilege.Schema))
+	vea.Report(desc.DefaultPrivileges.Validate())
 }
 
 // GetReferencedDescIDs returns the IDs of all descriptors referenced by
@@ -0,0 +1,68 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// DomainValueName is the name by which the CHECK constraints of a domain
+// refer to the value being checked.
+const DomainValueName = tree.Name("value")
+
+// ValidateDomainCheckExpr verifies that expr is a valid CHECK constraint for a
+// domain over the given base type. The expression must be of type BOOL and may
+// only refer to the value being checked, as VALUE. The serialized expression
+// is returned if it is valid.
+func ValidateDomainCheckExpr(
+	ctx context.Context, expr tree.Expr, baseType *types.T, semaCtx *tree.SemaContext,
+) (string, error) {
+	replacedExpr, err := ReplaceDomainValue(expr, &dummyColumn{typ: baseType, name: DomainValueName})
+	if err != nil {
+		return "", err
+	}
+	typedExpr, err := SanitizeVarFreeExpr(
+		ctx, replacedExpr, types.Bool, "CHECK", semaCtx, tree.VolatilityVolatile,
+	)
+	if err != nil {
+		return "", err
+	}
+	return tree.Serialize(typedExpr), nil
+}
+
+// ReplaceDomainValue replaces the references to VALUE in the CHECK constraint
+// expression of a domain with the given expression. Any other column reference
+// results in a pgcode.UndefinedColumn error.
+func ReplaceDomainValue(rootExpr tree.Expr, value tree.Expr) (tree.Expr, error) {
+	return tree.SimpleVisit(rootExpr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		vBase, ok := expr.(tree.VarName)
+		if !ok {
+			return true, expr, nil
+		}
+
+		v, err := vBase.NormalizeVarName()
+		if err != nil {
+			return false, nil, err
+		}
+
+		c, ok := v.(*tree.ColumnItem)
+		if !ok || c.TableName != nil || c.ColumnName != DomainValueName {
+			return false, nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist, referenced in %q", tree.AsString(v), rootExpr.String())
+		}
+		return false, value, nil
+	})
+}
@@ -44,6 +44,28 @@ func DequalifyAndValidateExpr(
 	maxVolatility tree.Volatility,
 	tn *tree.TableName,
 ) (string, catalog.TableColSet, error) {
+	typedExpr, colIDs, err := dequalifyAndTypeCheckExpr(
+		ctx, desc, expr, typ, op, semaCtx, maxVolatility, tn,
+	)
+	if err != nil {
+		return "", colIDs, err
+	}
+	return tree.Serialize(typedExpr), colIDs, nil
+}
+
+// dequalifyAndTypeCheckExpr is like DequalifyAndValidateExpr, but it returns
+// the type-checked expression rather than its serialized form. The returned
+// expression contains dummyColumns, so it must not be evaluated.
+func dequalifyAndTypeCheckExpr(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	expr tree.Expr,
+	typ *types.T,
+	op string,
+	semaCtx *tree.SemaContext,
+	maxVolatility tree.Volatility,
+	tn *tree.TableName,
+) (tree.TypedExpr, catalog.TableColSet, error) {
 	var colIDs catalog.TableColSet
 	nonDropColumns := desc.NonDropColumns()
 	nonDropColumnDescs := make([]descpb.ColumnDescriptor, len(nonDropColumns))
@@ -59,14 +81,14 @@ func DequalifyAndValidateExpr(
 	)
 	expr, err := dequalifyColumnRefs(ctx, sourceInfo, expr)
 	if err != nil {
-		return "", colIDs, err
+		return nil, colIDs, err
 	}
 
 	// Replace the column variables with dummyColumns so that they can be
 	// type-checked.
 	replacedExpr, colIDs, err := replaceColumnVars(desc, expr)
 	if err != nil {
-		return "", colIDs, err
+		return nil, colIDs, err
 	}
 
 	typedExpr, err := SanitizeVarFreeExpr(
@@ -79,10 +101,10 @@ func DequalifyAndValidateExpr(
 	)
 
 	if err != nil {
-		return "", colIDs, err
+		return nil, colIDs, err
 	}
 
-	return tree.Serialize(typedExpr), colIDs, nil
+	return typedExpr, colIDs, nil
 }
 
 // ExtractColumnIDs returns the set of column IDs within the given expression.
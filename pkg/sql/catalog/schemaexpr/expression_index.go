@@ -0,0 +1,76 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// ValidateExpressionIndex verifies that an expression is a valid element of an
+// expression index. If the expression is valid, it returns the serialized
+// expression with the columns dequalified, along with the type of the
+// expression.
+//
+// Expression index elements are stored as inaccessible virtual computed
+// columns, so an expression is valid if it is a valid virtual computed column
+// expression (see ComputedColumnValidator.Validate) and its type can be
+// determined without a type hint.
+func ValidateExpressionIndex(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	expr tree.Expr,
+	tableName *tree.TableName,
+	semaCtx *tree.SemaContext,
+) (serializedExpr string, _ *types.T, _ error) {
+	// Type-check the expression to determine the type of the column backing
+	// the index element.
+	typedExpr, _, err := dequalifyAndTypeCheckExpr(
+		ctx,
+		desc,
+		expr,
+		types.Any,
+		"index element",
+		semaCtx,
+		tree.VolatilityImmutable,
+		tableName,
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	typ := typedExpr.ResolvedType()
+	if typ.Family() == types.UnknownFamily || typ.Family() == types.AnyFamily {
+		return "", nil, errors.WithHint(
+			pgerror.Newf(
+				pgcode.InvalidTableDefinition,
+				"type of index element %s is ambiguous", tree.AsString(expr),
+			),
+			"consider adding a type cast to the expression",
+		)
+	}
+
+	d := &tree.ColumnTableDef{Type: typ}
+	d.Computed.Computed = true
+	d.Computed.Virtual = true
+	d.Computed.Expr = expr
+	v := MakeComputedColumnValidator(ctx, desc, semaCtx, tableName)
+	serializedExpr, err = v.Validate(d)
+	if err != nil {
+		return "", nil, err
+	}
+	return serializedExpr, typ, nil
+}
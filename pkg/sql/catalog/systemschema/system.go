@@ -365,6 +365,22 @@ CREATE TABLE system.join_tokens (
     expiration   TIMESTAMPTZ NOT NULL,
     FAMILY "primary" (id, secret, expiration)
 )`
+
+	PlanBaselinesTableSchema = `
+CREATE TABLE system.plan_baselines (
+    fingerprint  STRING NOT NULL PRIMARY KEY,
+    hints        BYTES NOT NULL,
+    plan         STRING NOT NULL,
+    created      TIMESTAMPTZ NOT NULL,
+    FAMILY "primary" (fingerprint, hints, plan, created)
+)`
+
+	StatementHintsTableSchema = `
+CREATE TABLE system.statement_hints (
+    fingerprint  STRING NOT NULL PRIMARY KEY,
+    hints        STRING NOT NULL,
+    FAMILY "primary" (fingerprint, hints)
+)`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -1807,6 +1823,92 @@ var (
 		FormatVersion:  descpb.InterleavedFormatVersion,
 		NextMutationID: 1,
 	})
+
+	// PlanBaselinesTable is the descriptor for the plan baselines table. It
+	// stores the plans pinned for statement fingerprints, which the optimizer
+	// honors when planning statements with those fingerprints.
+	PlanBaselinesTable = makeTable(descpb.TableDescriptor{
+		Name:                    "plan_baselines",
+		ID:                      keys.PlanBaselinesTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []descpb.ColumnDescriptor{
+			{Name: "fingerprint", ID: 1, Type: types.String, Nullable: false},
+			{Name: "hints", ID: 2, Type: types.Bytes, Nullable: false},
+			{Name: "plan", ID: 3, Type: types.String, Nullable: false},
+			{Name: "created", ID: 4, Type: types.TimestampTZ, Nullable: false},
+		},
+		NextColumnID: 5,
+		Families: []descpb.ColumnFamilyDescriptor{
+			{
+				Name:            "primary",
+				ID:              0,
+				ColumnNames:     []string{"fingerprint", "hints", "plan", "created"},
+				ColumnIDs:       []descpb.ColumnID{1, 2, 3, 4},
+				DefaultColumnID: 0,
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: descpb.IndexDescriptor{
+			Name:        tabledesc.PrimaryKeyIndexName,
+			ID:          1,
+			Unique:      true,
+			ColumnNames: []string{"fingerprint"},
+			ColumnDirections: []descpb.IndexDescriptor_Direction{
+				descpb.IndexDescriptor_ASC,
+			},
+			ColumnIDs: []descpb.ColumnID{1},
+			Version:   descpb.EmptyArraysInInvertedIndexesVersion,
+		},
+		NextIndexID: 2,
+		Privileges: descpb.NewCustomSuperuserPrivilegeDescriptor(
+			descpb.SystemAllowedPrivileges[keys.PlanBaselinesTableID], security.NodeUserName()),
+		FormatVersion:  descpb.InterleavedFormatVersion,
+		NextMutationID: 1,
+	})
+
+	// StatementHintsTable is the descriptor for the statement hints table. It
+	// stores optimizer hints for statement fingerprints, which are applied when
+	// planning statements with those fingerprints.
+	StatementHintsTable = makeTable(descpb.TableDescriptor{
+		Name:                    "statement_hints",
+		ID:                      keys.StatementHintsTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []descpb.ColumnDescriptor{
+			{Name: "fingerprint", ID: 1, Type: types.String, Nullable: false},
+			{Name: "hints", ID: 2, Type: types.String, Nullable: false},
+		},
+		NextColumnID: 3,
+		Families: []descpb.ColumnFamilyDescriptor{
+			{
+				Name:            "primary",
+				ID:              0,
+				ColumnNames:     []string{"fingerprint", "hints"},
+				ColumnIDs:       []descpb.ColumnID{1, 2},
+				DefaultColumnID: 0,
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: descpb.IndexDescriptor{
+			Name:        tabledesc.PrimaryKeyIndexName,
+			ID:          1,
+			Unique:      true,
+			ColumnNames: []string{"fingerprint"},
+			ColumnDirections: []descpb.IndexDescriptor_Direction{
+				descpb.IndexDescriptor_ASC,
+			},
+			ColumnIDs: []descpb.ColumnID{1},
+			Version:   descpb.EmptyArraysInInvertedIndexesVersion,
+		},
+		NextIndexID: 2,
+		Privileges: descpb.NewCustomSuperuserPrivilegeDescriptor(
+			descpb.SystemAllowedPrivileges[keys.StatementHintsTableID], security.NodeUserName()),
+		FormatVersion:  descpb.InterleavedFormatVersion,
+		NextMutationID: 1,
+	})
 )
 
 // newCommentPrivilegeDescriptor returns a privilege descriptor for comment table
@@ -234,6 +234,10 @@ type Column interface {
 	// IsHidden returns true iff the column is not visible.
 	IsHidden() bool
 
+	// IsInaccessible returns true iff the column cannot be referenced in
+	// queries. Such columns back expression indexes.
+	IsInaccessible() bool
+
 	// NumUsesSequences returns the number of sequences used by this column.
 	NumUsesSequences() int
 
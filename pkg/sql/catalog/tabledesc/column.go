@@ -127,6 +127,12 @@ func (w column) IsHidden() bool {
 	return w.desc.Hidden
 }
 
+// IsInaccessible returns true iff the column cannot be referenced in
+// queries. Such columns back expression indexes.
+func (w column) IsInaccessible() bool {
+	return w.desc.Inaccessible
+}
+
 // NumUsesSequences returns the number of sequences used by this column.
 func (w column) NumUsesSequences() int {
 	return len(w.desc.UsesSequenceIds)
@@ -250,7 +256,7 @@ func newColumnCache(desc *descpb.TableDescriptor, mutations *mutationCache) *col
 		}
 	}
 	for _, col := range c.deletable {
-		if col.Public() && !col.IsHidden() {
+		if col.Public() && !col.IsHidden() && !col.IsInaccessible() {
 			lazyAllocAppendColumn(&c.visible, col, numPublic)
 		}
 		if col.HasType() && col.GetType().UserDefined() {
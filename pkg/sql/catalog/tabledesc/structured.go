@@ -145,7 +145,14 @@ func buildIndexName(tableDesc *Mutable, index catalog.Index) string {
 	idx := index.IndexDesc()
 	segments := make([]string, 0, len(idx.ColumnNames)+2)
 	segments = append(segments, tableDesc.Name)
-	segments = append(segments, idx.ColumnNames[idx.ExplicitColumnStartIdx():]...)
+	for _, name := range idx.ColumnNames[idx.ExplicitColumnStartIdx():] {
+		// Columns backing expression index elements are named "expr" so that
+		// the generated name does not include the internal column name.
+		if col, err := tableDesc.FindColumnWithName(tree.Name(name)); err == nil && col.IsInaccessible() {
+			name = "expr"
+		}
+		segments = append(segments, name)
+	}
 	if idx.Unique {
 		segments = append(segments, "key")
 	} else {
@@ -1002,11 +1009,36 @@ func checkColumnsValidForIndex(tableDesc *Mutable, indexColNames []string) error
 	return nil
 }
 
-func checkColumnsValidForInvertedIndex(tableDesc *Mutable, indexColNames []string) error {
+func checkColumnsValidForInvertedIndex(tableDesc *Mutable, idx *descpb.IndexDescriptor) error {
+	indexColNames := idx.ColumnNames
 	lastCol := len(indexColNames) - 1
 	for i, indexCol := range indexColNames {
 		for _, col := range tableDesc.NonDropColumns() {
 			if col.GetName() == indexCol {
+				// The last column indexed by a trigram index must be a string.
+				if i == lastCol && idx.InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM {
+					if !colinfo.ColumnTypeIsTrigramIndexable(col.GetType()) {
+						return pgerror.Newf(
+							pgcode.DatatypeMismatch,
+							"operator class %q does not accept data type %s",
+							descpb.TrigramOpClass,
+							col.GetType().Name(),
+						)
+					}
+					continue
+				}
+				// Strings can only be inverted indexed using trigrams, which
+				// must be requested with an operator class.
+				if i == lastCol && colinfo.ColumnTypeIsTrigramIndexable(col.GetType()) {
+					return errors.WithHint(
+						pgerror.Newf(
+							pgcode.UndefinedObject,
+							"data type %s has no default operator class for access method \"gin\"",
+							col.GetType().Name(),
+						),
+						"You must specify an operator class for the index, such as gin_trgm_ops.",
+					)
+				}
 				// The last column indexed by an inverted index must be
 				// inverted indexable.
 				if i == lastCol && !colinfo.ColumnTypeIsInvertedIndexable(col.GetType()) {
@@ -1072,7 +1104,7 @@ func (desc *Mutable) AddIndex(idx descpb.IndexDescriptor, primary bool) error {
 		}
 
 	} else {
-		if err := checkColumnsValidForInvertedIndex(desc, idx.ColumnNames); err != nil {
+		if err := checkColumnsValidForInvertedIndex(desc, &idx); err != nil {
 			return err
 		}
 		desc.AddPublicNonPrimaryIndex(idx)
@@ -1954,7 +1986,7 @@ func (desc *Mutable) AddIndexMutation(
 			return err
 		}
 	case descpb.IndexDescriptor_INVERTED:
-		if err := checkColumnsValidForInvertedIndex(desc, idx.ColumnNames); err != nil {
+		if err := checkColumnsValidForInvertedIndex(desc, idx); err != nil {
 			return err
 		}
 	}
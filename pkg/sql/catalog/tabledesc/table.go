@@ -165,6 +165,11 @@ func EvalShardBucketCount(
 	return int32(buckets), nil
 }
 
+// RowLevelTTLExpirationColumnName is the name of the hidden column added to
+// tables created with the ttl_expire_after storage parameter, which holds the
+// time at which each row expires.
+const RowLevelTTLExpirationColumnName = "crdb_internal_expiration"
+
 // GetShardColumnName generates a name for the hidden shard column to be used to create a
 // hash sharded index.
 func GetShardColumnName(colNames []string, buckets int32) string {
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
@@ -559,6 +560,9 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			desc.validateUniqueWithoutIndexConstraints(columnIDs),
 			desc.validateTableIndexes(columnNames),
 			desc.validatePartitioning(),
+			desc.validateRowLevelTTL(columnIDs),
+			desc.validatePolicies(),
+			desc.validateIncrementalRefresh(),
 		}
 		hasErrs := false
 		for _, err := range newErrs {
@@ -1205,6 +1209,76 @@ func (desc *wrapper) validatePartitioning() error {
 	})
 }
 
+// validateRowLevelTTL validates that the expiration column of a table with
+// row-level TTL exists and has the TIMESTAMPTZ type.
+func (desc *wrapper) validateRowLevelTTL(
+	columnIDs map[descpb.ColumnID]*descpb.ColumnDescriptor,
+) error {
+	ttl := desc.RowLevelTTL
+	if ttl == nil {
+		return nil
+	}
+	col, ok := columnIDs[ttl.ExpirationColumnID]
+	if !ok {
+		return errors.AssertionFailedf(
+			"row-level TTL expiration column %d does not exist", ttl.ExpirationColumnID)
+	}
+	if col.Type.Family() != types.TimestampTZFamily {
+		return errors.AssertionFailedf(
+			"row-level TTL expiration column %q has type %s, expected TIMESTAMPTZ",
+			col.Name, col.Type.SQLString())
+	}
+	if ttl.SelectBatchSize < 0 || ttl.DeleteBatchSize < 0 || ttl.DeleteRateLimit < 0 {
+		return errors.AssertionFailedf("row-level TTL batch sizes and rate limit must not be negative")
+	}
+	return nil
+}
+
+// validateIncrementalRefresh validates that only materialized views are
+// refreshed incrementally.
+func (desc *wrapper) validateIncrementalRefresh() error {
+	if desc.IncrementalRefresh != nil && !desc.MaterializedView() {
+		return errors.AssertionFailedf("only materialized views can be refreshed incrementally")
+	}
+	return nil
+}
+
+// validatePolicies validates that the row-level security policies of a table
+// have unique names, and only have the expressions meaningful for the commands
+// to which they apply.
+func (desc *wrapper) validatePolicies() error {
+	names := make(map[string]struct{}, len(desc.Policies))
+	for i := range desc.Policies {
+		p := &desc.Policies[i]
+		if p.Name == "" {
+			return errors.AssertionFailedf("empty policy name")
+		}
+		if _, ok := names[p.Name]; ok {
+			return errors.AssertionFailedf("duplicate policy name: %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		switch p.Command {
+		case descpb.TableDescriptor_Policy_ALL, descpb.TableDescriptor_Policy_UPDATE:
+		case descpb.TableDescriptor_Policy_SELECT, descpb.TableDescriptor_Policy_DELETE:
+			if p.WithCheckExpr != "" {
+				return errors.AssertionFailedf(
+					"policy %q for %s cannot have a WITH CHECK expression", p.Name, p.Command)
+			}
+		case descpb.TableDescriptor_Policy_INSERT:
+			if p.UsingExpr != "" {
+				return errors.AssertionFailedf(
+					"policy %q for %s cannot have a USING expression", p.Name, p.Command)
+			}
+		default:
+			return errors.AssertionFailedf("policy %q has unknown command %d", p.Name, p.Command)
+		}
+		if len(p.Roles) == 0 {
+			return errors.AssertionFailedf("policy %q does not apply to any role", p.Name)
+		}
+	}
+	return nil
+}
+
 // validateTableLocalityConfig validates whether the descriptor's locality
 // config is valid under the given database.
 func (desc *wrapper) validateTableLocalityConfig(
@@ -124,6 +124,10 @@ var validationMap = []struct {
 			"Temporary":                     {status: thisFieldReferencesNoObjects},
 			"LocalityConfig":                {status: iSolemnlySwearThisFieldIsValidated},
 			"PartitionAllBy":                {status: iSolemnlySwearThisFieldIsValidated},
+			"RowLevelTTL":                   {status: iSolemnlySwearThisFieldIsValidated},
+			"RowLevelSecurity":              {status: thisFieldReferencesNoObjects},
+			"Policies":                      {status: iSolemnlySwearThisFieldIsValidated},
+			"IncrementalRefresh":            {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
@@ -152,16 +156,17 @@ var validationMap = []struct {
 			"ForeignKey":   {status: thisFieldReferencesNoObjects},
 			"ReferencedBy": {status: thisFieldReferencesNoObjects},
 
-			"Interleave":        {status: iSolemnlySwearThisFieldIsValidated},
-			"InterleavedBy":     {status: iSolemnlySwearThisFieldIsValidated},
-			"Partitioning":      {status: iSolemnlySwearThisFieldIsValidated},
-			"Type":              {status: thisFieldReferencesNoObjects},
-			"CreatedExplicitly": {status: thisFieldReferencesNoObjects},
-			"EncodingType":      {status: thisFieldReferencesNoObjects},
-			"Sharded":           {status: iSolemnlySwearThisFieldIsValidated},
-			"Disabled":          {status: thisFieldReferencesNoObjects},
-			"GeoConfig":         {status: thisFieldReferencesNoObjects},
-			"Predicate":         {status: iSolemnlySwearThisFieldIsValidated},
+			"Interleave":         {status: iSolemnlySwearThisFieldIsValidated},
+			"InterleavedBy":      {status: iSolemnlySwearThisFieldIsValidated},
+			"Partitioning":       {status: iSolemnlySwearThisFieldIsValidated},
+			"Type":               {status: thisFieldReferencesNoObjects},
+			"CreatedExplicitly":  {status: thisFieldReferencesNoObjects},
+			"EncodingType":       {status: thisFieldReferencesNoObjects},
+			"Sharded":            {status: iSolemnlySwearThisFieldIsValidated},
+			"Disabled":           {status: thisFieldReferencesNoObjects},
+			"GeoConfig":          {status: thisFieldReferencesNoObjects},
+			"Predicate":          {status: iSolemnlySwearThisFieldIsValidated},
+			"InvertedColumnKind": {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -188,6 +193,7 @@ var validationMap = []struct {
 				reason: "initial import: TODO(features): add validation"},
 			"AlterColumnTypeInProgress": {status: thisFieldReferencesNoObjects},
 			"SystemColumnKind":          {status: thisFieldReferencesNoObjects},
+			"Inaccessible":              {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -206,16 +212,18 @@ var validationMap = []struct {
 			"OnDelete":          {status: thisFieldReferencesNoObjects},
 			"OnUpdate":          {status: thisFieldReferencesNoObjects},
 			"Match":             {status: thisFieldReferencesNoObjects},
+			"Deferrability":     {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
 		obj: descpb.UniqueWithoutIndexConstraint{},
 		fieldMap: map[string]validationStatusInfo{
-			"TableID":   {status: iSolemnlySwearThisFieldIsValidated},
-			"ColumnIDs": {status: iSolemnlySwearThisFieldIsValidated},
-			"Name":      {status: thisFieldReferencesNoObjects},
-			"Validity":  {status: thisFieldReferencesNoObjects},
-			"Predicate": {status: iSolemnlySwearThisFieldIsValidated},
+			"TableID":       {status: iSolemnlySwearThisFieldIsValidated},
+			"ColumnIDs":     {status: iSolemnlySwearThisFieldIsValidated},
+			"Name":          {status: thisFieldReferencesNoObjects},
+			"Validity":      {status: thisFieldReferencesNoObjects},
+			"Predicate":     {status: iSolemnlySwearThisFieldIsValidated},
+			"Deferrability": {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -237,35 +245,39 @@ var validationMap = []struct {
 			"Privileges":               {status: iSolemnlySwearThisFieldIsValidated},
 			"OfflineReason":            {status: thisFieldReferencesNoObjects},
 			"RegionConfig":             {status: iSolemnlySwearThisFieldIsValidated},
+			"Composite":                {status: iSolemnlySwearThisFieldIsValidated},
+			"Domain":                   {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
 		obj: descpb.DatabaseDescriptor{},
 		fieldMap: map[string]validationStatusInfo{
-			"Name":             {status: iSolemnlySwearThisFieldIsValidated},
-			"ID":               {status: iSolemnlySwearThisFieldIsValidated},
-			"Version":          {status: thisFieldReferencesNoObjects},
-			"ModificationTime": {status: thisFieldReferencesNoObjects},
-			"DrainingNames":    {status: thisFieldReferencesNoObjects},
-			"Privileges":       {status: iSolemnlySwearThisFieldIsValidated},
-			"Schemas":          {status: iSolemnlySwearThisFieldIsValidated},
-			"State":            {status: thisFieldReferencesNoObjects},
-			"OfflineReason":    {status: thisFieldReferencesNoObjects},
-			"RegionConfig":     {status: iSolemnlySwearThisFieldIsValidated},
+			"Name":              {status: iSolemnlySwearThisFieldIsValidated},
+			"ID":                {status: iSolemnlySwearThisFieldIsValidated},
+			"Version":           {status: thisFieldReferencesNoObjects},
+			"ModificationTime":  {status: thisFieldReferencesNoObjects},
+			"DrainingNames":     {status: thisFieldReferencesNoObjects},
+			"Privileges":        {status: iSolemnlySwearThisFieldIsValidated},
+			"Schemas":           {status: iSolemnlySwearThisFieldIsValidated},
+			"State":             {status: thisFieldReferencesNoObjects},
+			"OfflineReason":     {status: thisFieldReferencesNoObjects},
+			"RegionConfig":      {status: iSolemnlySwearThisFieldIsValidated},
+			"DefaultPrivileges": {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
 		obj: descpb.SchemaDescriptor{},
 		fieldMap: map[string]validationStatusInfo{
-			"Name":             {status: iSolemnlySwearThisFieldIsValidated},
-			"ID":               {status: iSolemnlySwearThisFieldIsValidated},
-			"State":            {status: thisFieldReferencesNoObjects},
-			"OfflineReason":    {status: thisFieldReferencesNoObjects},
-			"ModificationTime": {status: thisFieldReferencesNoObjects},
-			"Version":          {status: thisFieldReferencesNoObjects},
-			"DrainingNames":    {status: thisFieldReferencesNoObjects},
-			"ParentID":         {status: iSolemnlySwearThisFieldIsValidated},
-			"Privileges":       {status: iSolemnlySwearThisFieldIsValidated},
+			"Name":              {status: iSolemnlySwearThisFieldIsValidated},
+			"ID":                {status: iSolemnlySwearThisFieldIsValidated},
+			"State":             {status: thisFieldReferencesNoObjects},
+			"OfflineReason":     {status: thisFieldReferencesNoObjects},
+			"ModificationTime":  {status: thisFieldReferencesNoObjects},
+			"Version":           {status: thisFieldReferencesNoObjects},
+			"DrainingNames":     {status: thisFieldReferencesNoObjects},
+			"ParentID":          {status: iSolemnlySwearThisFieldIsValidated},
+			"Privileges":        {status: iSolemnlySwearThisFieldIsValidated},
+			"DefaultPrivileges": {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 }
@@ -1048,6 +1060,79 @@ func TestValidateTableDesc(t *testing.T) {
 				NextFamilyID: 1,
 				NextIndexID:  3,
 			}},
+		{`duplicate policy name: "p"`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.FamilyFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary", ColumnIDs: []descpb.ColumnID{1}, ColumnNames: []string{"bar"}},
+				},
+				PrimaryIndex: descpb.IndexDescriptor{
+					ID: 1, Name: "primary", ColumnIDs: []descpb.ColumnID{1}, ColumnNames: []string{"bar"},
+					ColumnDirections: []descpb.IndexDescriptor_Direction{descpb.IndexDescriptor_ASC},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				NextIndexID:  2,
+				Policies: []descpb.TableDescriptor_Policy{
+					{Name: "p", Roles: []security.SQLUsernameProto{"public"}, UsingExpr: "bar > 0"},
+					{Name: "p", Roles: []security.SQLUsernameProto{"public"}, UsingExpr: "bar < 0"},
+				},
+			}},
+		{`policy "p" for INSERT cannot have a USING expression`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.FamilyFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary", ColumnIDs: []descpb.ColumnID{1}, ColumnNames: []string{"bar"}},
+				},
+				PrimaryIndex: descpb.IndexDescriptor{
+					ID: 1, Name: "primary", ColumnIDs: []descpb.ColumnID{1}, ColumnNames: []string{"bar"},
+					ColumnDirections: []descpb.IndexDescriptor_Direction{descpb.IndexDescriptor_ASC},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				NextIndexID:  2,
+				Policies: []descpb.TableDescriptor_Policy{
+					{
+						Name:      "p",
+						Command:   descpb.TableDescriptor_Policy_INSERT,
+						Roles:     []security.SQLUsernameProto{"public"},
+						UsingExpr: "bar > 0",
+					},
+				},
+			}},
+		{`only materialized views can be refreshed incrementally`,
+			descpb.TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: descpb.FamilyFormatVersion,
+				Columns: []descpb.ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []descpb.ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary", ColumnIDs: []descpb.ColumnID{1}, ColumnNames: []string{"bar"}},
+				},
+				PrimaryIndex: descpb.IndexDescriptor{
+					ID: 1, Name: "primary", ColumnIDs: []descpb.ColumnID{1}, ColumnNames: []string{"bar"},
+					ColumnDirections: []descpb.IndexDescriptor_Direction{descpb.IndexDescriptor_ASC},
+				},
+				NextColumnID:       2,
+				NextFamilyID:       1,
+				NextIndexID:        2,
+				IncrementalRefresh: &descpb.TableDescriptor_IncrementalRefresh{JobID: 1},
+			}},
 	}
 	for i, d := range testData {
 		t.Run(d.err, func(t *testing.T) {
@@ -495,6 +495,47 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
 		}
 		desc.validateEnumMembers(vea)
+	case descpb.TypeDescriptor_COMPOSITE:
+		vea.Report(desc.Privileges.Validate(desc.ID, privilege.Type))
+		if desc.RegionConfig != nil {
+			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
+		}
+		if desc.Composite == nil {
+			vea.Report(errors.AssertionFailedf("COMPOSITE type desc has nil composite elements"))
+			break
+		}
+		seenLabels := make(map[string]struct{}, len(desc.Composite.Elements))
+		for _, e := range desc.Composite.Elements {
+			if e.ElementType == nil {
+				vea.Report(errors.AssertionFailedf("composite element %q has nil type", e.ElementLabel))
+			}
+			if _, ok := seenLabels[e.ElementLabel]; ok {
+				vea.Report(errors.AssertionFailedf("duplicate composite element label %q", e.ElementLabel))
+			}
+			seenLabels[e.ElementLabel] = struct{}{}
+		}
+	case descpb.TypeDescriptor_DOMAIN:
+		vea.Report(desc.Privileges.Validate(desc.ID, privilege.Type))
+		if desc.RegionConfig != nil {
+			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
+		}
+		if desc.Domain == nil {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has nil domain"))
+			break
+		}
+		if desc.Domain.BaseType == nil {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has nil base type"))
+		}
+		seenNames := make(map[string]struct{}, len(desc.Domain.Checks))
+		for _, c := range desc.Domain.Checks {
+			if c.Expr == "" {
+				vea.Report(errors.AssertionFailedf("domain check constraint %q has empty expression", c.Name))
+			}
+			if _, ok := seenNames[c.Name]; ok {
+				vea.Report(errors.AssertionFailedf("duplicate domain check constraint name %q", c.Name))
+			}
+			seenNames[c.Name] = struct{}{}
+		}
 	case descpb.TypeDescriptor_ALIAS:
 		if desc.RegionConfig != nil {
 			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
@@ -592,7 +633,8 @@ func (desc *immutable) ValidateCrossReferences(
 
 	// Validate that the referenced types exist.
 	switch desc.GetKind() {
-	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM:
+	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM,
+		descpb.TypeDescriptor_COMPOSITE, descpb.TypeDescriptor_DOMAIN:
 		// Ensure that the referenced array type exists.
 		if _, err := vdg.GetTypeDescriptor(desc.GetArrayTypeID()); err != nil {
 			vea.Report(errors.Wrapf(err, "arrayTypeID %d does not exist for %q", desc.GetArrayTypeID(), desc.GetKind()))
@@ -704,6 +746,24 @@ func (desc *immutable) MakeTypesT(
 			return nil, err
 		}
 		return typ, nil
+	case descpb.TypeDescriptor_COMPOSITE:
+		contents := make([]*types.T, len(desc.Composite.Elements))
+		labels := make([]string, len(desc.Composite.Elements))
+		for i, e := range desc.Composite.Elements {
+			contents[i] = e.ElementType
+			labels[i] = e.ElementLabel
+		}
+		typ := types.MakeComposite(TypeIDToOID(desc.GetID()), TypeIDToOID(desc.ArrayTypeID), contents, labels)
+		if err := desc.HydrateTypeInfoWithName(ctx, typ, name, res); err != nil {
+			return nil, err
+		}
+		return typ, nil
+	case descpb.TypeDescriptor_DOMAIN:
+		typ := types.MakeDomain(TypeIDToOID(desc.GetID()), TypeIDToOID(desc.ArrayTypeID), desc.Domain.BaseType)
+		if err := desc.HydrateTypeInfoWithName(ctx, typ, name, res); err != nil {
+			return nil, err
+		}
+		return typ, nil
 	case descpb.TypeDescriptor_ALIAS:
 		// Hydrate the alias and return it.
 		if err := desc.HydrateTypeInfoWithName(ctx, desc.Alias, name, res); err != nil {
@@ -781,6 +841,28 @@ func (desc *immutable) HydrateTypeInfoWithName(
 			IsMemberReadOnly:        desc.readOnlyMembers,
 		}
 		return nil
+	case descpb.TypeDescriptor_COMPOSITE:
+		if typ.Family() != types.TupleFamily {
+			return errors.New("cannot hydrate a non-tuple type with a composite type descriptor")
+		}
+		// The elements of a composite type are stored in the types.T of the
+		// columns which use it, so there is nothing else to fill in.
+		return nil
+	case descpb.TypeDescriptor_DOMAIN:
+		if !typ.IsDomain() {
+			return errors.New("cannot hydrate a non-domain type with a domain type descriptor")
+		}
+		checks := make([]types.DomainCheck, len(desc.Domain.Checks))
+		for i, c := range desc.Domain.Checks {
+			checks[i] = types.DomainCheck{Name: c.Name, Expr: c.Expr}
+		}
+		typ.TypeMeta.DomainData = &types.DomainMetadata{
+			BaseType:    desc.Domain.BaseType,
+			NotNull:     desc.Domain.NotNull,
+			DefaultExpr: desc.Domain.DefaultExpr,
+			Checks:      checks,
+		}
+		return nil
 	case descpb.TypeDescriptor_ALIAS:
 		if typ.UserDefined() {
 			switch typ.Family() {
@@ -772,6 +772,85 @@ func TestValidateTypeDesc(t *testing.T) {
 				Privileges:  defaultPrivileges,
 			},
 		},
+		{
+			`COMPOSITE type desc has nil composite elements`,
+			descpb.TypeDescriptor{
+				Name:           "t",
+				ID:             typeDescID,
+				ParentID:       100,
+				ParentSchemaID: keys.PublicSchemaID,
+				Kind:           descpb.TypeDescriptor_COMPOSITE,
+				ArrayTypeID:    102,
+				Privileges:     defaultPrivileges,
+			},
+		},
+		{
+			`duplicate composite element label "a"`,
+			descpb.TypeDescriptor{
+				Name:           "t",
+				ID:             typeDescID,
+				ParentID:       100,
+				ParentSchemaID: keys.PublicSchemaID,
+				Kind:           descpb.TypeDescriptor_COMPOSITE,
+				Composite: &descpb.TypeDescriptor_Composite{
+					Elements: []descpb.TypeDescriptor_Composite_CompositeElement{
+						{ElementType: types.Int, ElementLabel: "a"},
+						{ElementType: types.String, ElementLabel: "a"},
+					},
+				},
+				ArrayTypeID: 102,
+				Privileges:  defaultPrivileges,
+			},
+		},
+		{
+			`arrayTypeID 500 does not exist for "COMPOSITE": referenced type ID 500: descriptor not found`,
+			descpb.TypeDescriptor{
+				Name:           "t",
+				ID:             typeDescID,
+				ParentID:       100,
+				ParentSchemaID: keys.PublicSchemaID,
+				Kind:           descpb.TypeDescriptor_COMPOSITE,
+				Composite: &descpb.TypeDescriptor_Composite{
+					Elements: []descpb.TypeDescriptor_Composite_CompositeElement{
+						{ElementType: types.Int, ElementLabel: "a"},
+					},
+				},
+				ArrayTypeID: 500,
+				Privileges:  defaultPrivileges,
+			},
+		},
+		{
+			`DOMAIN type desc has nil base type`,
+			descpb.TypeDescriptor{
+				Name:           "t",
+				ID:             typeDescID,
+				ParentID:       100,
+				ParentSchemaID: keys.PublicSchemaID,
+				Kind:           descpb.TypeDescriptor_DOMAIN,
+				Domain:         &descpb.TypeDescriptor_Domain{},
+				ArrayTypeID:    102,
+				Privileges:     defaultPrivileges,
+			},
+		},
+		{
+			`duplicate domain check constraint name "c"`,
+			descpb.TypeDescriptor{
+				Name:           "t",
+				ID:             typeDescID,
+				ParentID:       100,
+				ParentSchemaID: keys.PublicSchemaID,
+				Kind:           descpb.TypeDescriptor_DOMAIN,
+				Domain: &descpb.TypeDescriptor_Domain{
+					BaseType: types.Int,
+					Checks: []descpb.TypeDescriptor_Domain_CheckConstraint{
+						{Name: "c", Expr: "value > 0:::INT8"},
+						{Name: "c", Expr: "value < 10:::INT8"},
+					},
+				},
+				ArrayTypeID: 102,
+				Privileges:  defaultPrivileges,
+			},
+		},
 	}
 
 	for i, test := range testData {
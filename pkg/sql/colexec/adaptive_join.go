@@ -0,0 +1,354 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// adaptiveJoinState represents the state of the AdaptiveJoiner.
+type adaptiveJoinState int
+
+const (
+	// ajStarting means that Next has not been called yet.
+	ajStarting adaptiveJoinState = iota
+	// ajLookupJoining means that the rows are emitted by the lookup join.
+	ajLookupJoining
+	// ajHashJoining means that the rows are emitted by the hash join.
+	ajHashJoining
+	// ajDone means that all rows have been emitted.
+	ajDone
+)
+
+// AdaptiveJoinConstructor constructs one of the two joins of an
+// AdaptiveJoiner given the input it should read from. It returns the join
+// operator as well as the operator that reads from KV on behalf of the join.
+type AdaptiveJoinConstructor func(input colexecop.Operator) (colexecop.Operator, colexecop.KVReader, error)
+
+// AdaptiveJoiner is an operator that joins its input with an index of a table
+// either by looking up the input rows in the index or by performing a hash
+// join between the input and a full scan of the index, and that chooses
+// between the two strategies based on the actual number of input rows rather
+// than on the optimizer estimate.
+//
+// The AdaptiveJoiner operates in one of two modes:
+// - if the input isn't buffered, the input rows are passed to the lookup join
+//   until threshold rows have been seen. If there are more input rows, the
+//   remaining rows are then passed to the hash join. This mode is used when
+//   the optimizer expected few input rows.
+// - if the input is buffered, up to threshold input rows are buffered first.
+//   If the input is exhausted by then, the buffered rows are passed to the
+//   lookup join, and otherwise all rows are passed to the hash join. This mode
+//   is used when the optimizer expected many input rows.
+//
+// Since each input row is joined with the same index by both joins, the input
+// rows can be split between the two joins without changing the result, as
+// long as the result doesn't need to be ordered.
+type AdaptiveJoiner struct {
+	colexecop.OneInputNode
+
+	// allocator is used to buffer the input rows.
+	allocator   *colmem.Allocator
+	memoryLimit int64
+	inputTypes  []*types.T
+	threshold   uint64
+	bufferInput bool
+
+	state    adaptiveJoinState
+	lookupOp colexecop.Operator
+	hashOp   colexecop.Operator
+	// lookupReader and hashReader read from KV on behalf of the lookup join
+	// and the hash join, respectively.
+	lookupReader colexecop.KVReader
+	hashReader   colexecop.KVReader
+
+	// rowsSeen is the number of input rows passed to the lookup join or
+	// buffered so far.
+	rowsSeen uint64
+	// buffered contains the buffered input rows, which are emitted from
+	// bufferedIdx on by the input of the chosen join.
+	buffered     []coldata.Batch
+	bufferedIdx  int
+	bufferedSize int64
+	// pending, if non-nil, contains the input rows that were read but not
+	// passed to the lookup join, and that must be passed to the hash join.
+	pending coldata.Batch
+
+	mu struct {
+		syncutil.Mutex
+		strategy execinfrapb.ExecStats_JoinStrategy
+	}
+}
+
+var _ colexecop.Operator = &AdaptiveJoiner{}
+var _ colexecop.KVReader = &AdaptiveJoiner{}
+
+// NewAdaptiveJoiner returns a new AdaptiveJoiner. The allocator and the memory
+// limit are used to buffer the input rows if bufferInput is true, in which
+// case the input rows are buffered until either there are more than threshold
+// of them or they exceed the memory limit. The constructors are used to
+// construct the lookup join and the hash join on top of the inputs provided by
+// the AdaptiveJoiner.
+func NewAdaptiveJoiner(
+	allocator *colmem.Allocator,
+	memoryLimit int64,
+	input colexecop.Operator,
+	inputTypes []*types.T,
+	threshold uint64,
+	bufferInput bool,
+	makeLookupJoin AdaptiveJoinConstructor,
+	makeHashJoin AdaptiveJoinConstructor,
+) (*AdaptiveJoiner, error) {
+	a := &AdaptiveJoiner{
+		OneInputNode: colexecop.NewOneInputNode(input),
+		allocator:    allocator,
+		memoryLimit:  memoryLimit,
+		inputTypes:   inputTypes,
+		threshold:    threshold,
+		bufferInput:  bufferInput,
+	}
+	var err error
+	a.lookupOp, a.lookupReader, err = makeLookupJoin(&adaptiveJoinInput{next: a.nextLookupInput})
+	if err != nil {
+		return nil, err
+	}
+	a.hashOp, a.hashReader, err = makeHashJoin(&adaptiveJoinInput{next: a.nextHashInput})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Init is part of the colexecop.Operator interface. The joins are initialized
+// once they are chosen.
+func (a *AdaptiveJoiner) Init() {
+	a.Input.Init()
+}
+
+// Next is part of the colexecop.Operator interface.
+func (a *AdaptiveJoiner) Next(ctx context.Context) coldata.Batch {
+	for {
+		switch a.state {
+		case ajStarting:
+			if a.bufferInput && a.bufferInputRows(ctx) {
+				a.startHashJoin(execinfrapb.ExecStats_HASH_JOIN)
+				continue
+			}
+			a.setJoinStrategy(execinfrapb.ExecStats_LOOKUP_JOIN)
+			a.lookupOp.Init()
+			a.state = ajLookupJoining
+
+		case ajLookupJoining:
+			if b := a.lookupOp.Next(ctx); b.Length() > 0 {
+				return b
+			}
+			if a.pending == nil {
+				a.state = ajDone
+				continue
+			}
+			// The threshold was crossed, so the remaining input rows are
+			// joined by the hash join.
+			a.startHashJoin(execinfrapb.ExecStats_LOOKUP_THEN_HASH_JOIN)
+
+		case ajHashJoining:
+			b := a.hashOp.Next(ctx)
+			if b.Length() == 0 {
+				a.state = ajDone
+			}
+			return b
+
+		case ajDone:
+			return coldata.ZeroBatch
+		}
+	}
+}
+
+// bufferInputRows buffers the input rows until either the input is exhausted,
+// in which case it returns false, or the threshold or the memory limit is
+// crossed, in which case it returns true.
+func (a *AdaptiveJoiner) bufferInputRows(ctx context.Context) bool {
+	for {
+		b := a.Input.Next(ctx)
+		n := b.Length()
+		if n == 0 {
+			return false
+		}
+		copied := a.copyRows(b, 0 /* startIdx */, n)
+		a.buffered = append(a.buffered, copied)
+		a.bufferedSize += colmem.GetBatchMemSize(copied)
+		a.rowsSeen += uint64(n)
+		if a.rowsSeen > a.threshold || a.bufferedSize > a.memoryLimit {
+			return true
+		}
+	}
+}
+
+// startHashJoin switches to the hash join with the given strategy.
+func (a *AdaptiveJoiner) startHashJoin(strategy execinfrapb.ExecStats_JoinStrategy) {
+	a.setJoinStrategy(strategy)
+	a.hashOp.Init()
+	a.state = ajHashJoining
+}
+
+// copyRows returns a new batch containing the rows of b in [startIdx, endIdx).
+func (a *AdaptiveJoiner) copyRows(b coldata.Batch, startIdx, endIdx int) coldata.Batch {
+	n := endIdx - startIdx
+	copied := a.allocator.NewMemBatchWithFixedCapacity(a.inputTypes, n)
+	a.allocator.PerformOperation(copied.ColVecs(), func() {
+		for i, vec := range copied.ColVecs() {
+			vec.Copy(coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					Src:         b.ColVec(i),
+					Sel:         b.Selection(),
+					SrcStartIdx: startIdx,
+					SrcEndIdx:   endIdx,
+				},
+			})
+		}
+		copied.SetLength(n)
+	})
+	return copied
+}
+
+// nextBufferedBatch returns the next buffered batch, or nil if all buffered
+// batches have been emitted. The memory of the previously emitted batch is
+// released, since the joins don't hold on to their input batches.
+func (a *AdaptiveJoiner) nextBufferedBatch() coldata.Batch {
+	if a.bufferedIdx > 0 {
+		prev := a.buffered[a.bufferedIdx-1]
+		a.allocator.ReleaseMemory(colmem.GetBatchMemSize(prev))
+		a.buffered[a.bufferedIdx-1] = nil
+	}
+	if a.bufferedIdx == len(a.buffered) {
+		return nil
+	}
+	a.bufferedIdx++
+	return a.buffered[a.bufferedIdx-1]
+}
+
+// nextLookupInput returns the next batch of input rows of the lookup join.
+func (a *AdaptiveJoiner) nextLookupInput(ctx context.Context) coldata.Batch {
+	if a.bufferInput {
+		// The lookup join is only chosen once the input has been exhausted.
+		if b := a.nextBufferedBatch(); b != nil {
+			return b
+		}
+		return coldata.ZeroBatch
+	}
+	if a.pending != nil {
+		return coldata.ZeroBatch
+	}
+	b := a.Input.Next(ctx)
+	n := b.Length()
+	if n == 0 {
+		return b
+	}
+	if a.rowsSeen == a.threshold {
+		// The input has more rows than the threshold, so the lookup join
+		// must stop here.
+		a.pending = b
+		return coldata.ZeroBatch
+	}
+	if remaining := a.threshold - a.rowsSeen; uint64(n) > remaining {
+		// Only the rows up to the threshold are passed to the lookup join, and
+		// the rest are kept for the hash join.
+		a.pending = a.copyRows(b, int(remaining), n)
+		b.SetLength(int(remaining))
+		n = int(remaining)
+	}
+	a.rowsSeen += uint64(n)
+	return b
+}
+
+// nextHashInput returns the next batch of input rows of the hash join.
+func (a *AdaptiveJoiner) nextHashInput(ctx context.Context) coldata.Batch {
+	if b := a.nextBufferedBatch(); b != nil {
+		return b
+	}
+	if b := a.pending; b != nil {
+		a.pending = nil
+		return b
+	}
+	return a.Input.Next(ctx)
+}
+
+func (a *AdaptiveJoiner) setJoinStrategy(strategy execinfrapb.ExecStats_JoinStrategy) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.mu.strategy = strategy
+}
+
+// GetJoinStrategy returns the join strategy used so far.
+func (a *AdaptiveJoiner) GetJoinStrategy() execinfrapb.ExecStats_JoinStrategy {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.mu.strategy
+}
+
+// GetBytesRead is part of the colexecop.KVReader interface.
+func (a *AdaptiveJoiner) GetBytesRead() int64 {
+	var bytesRead int64
+	for _, r := range []colexecop.KVReader{a.lookupReader, a.hashReader} {
+		if r != nil {
+			bytesRead += r.GetBytesRead()
+		}
+	}
+	return bytesRead
+}
+
+// GetRowsRead is part of the colexecop.KVReader interface.
+func (a *AdaptiveJoiner) GetRowsRead() int64 {
+	var rowsRead int64
+	for _, r := range []colexecop.KVReader{a.lookupReader, a.hashReader} {
+		if r != nil {
+			rowsRead += r.GetRowsRead()
+		}
+	}
+	return rowsRead
+}
+
+// GetCumulativeContentionTime is part of the colexecop.KVReader interface.
+func (a *AdaptiveJoiner) GetCumulativeContentionTime() time.Duration {
+	var contentionTime time.Duration
+	for _, r := range []colexecop.KVReader{a.lookupReader, a.hashReader} {
+		if r != nil {
+			contentionTime += r.GetCumulativeContentionTime()
+		}
+	}
+	return contentionTime
+}
+
+// adaptiveJoinInput is the input of one of the joins of an AdaptiveJoiner. It
+// emits the input rows that the AdaptiveJoiner routes to that join.
+type adaptiveJoinInput struct {
+	colexecop.ZeroInputNode
+	colexecop.NonExplainable
+	next func(ctx context.Context) coldata.Batch
+}
+
+var _ colexecop.Operator = &adaptiveJoinInput{}
+
+// Init is part of the colexecop.Operator interface. The input of the
+// AdaptiveJoiner is initialized by the AdaptiveJoiner itself.
+func (i *adaptiveJoinInput) Init() {}
+
+// Next is part of the colexecop.Operator interface.
+func (i *adaptiveJoinInput) Next(ctx context.Context) coldata.Batch {
+	return i.next(ctx)
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexectestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+func TestAdaptiveJoiner(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tuples := colexectestutils.Tuples{{1}, {2}, {3}, {4}}
+	tcs := []struct {
+		tuples      colexectestutils.Tuples
+		threshold   uint64
+		bufferInput bool
+		memoryLimit int64
+		// expected contains the input tuples tagged with 0 if they were joined
+		// by the lookup join, and with 1 if they were joined by the hash join.
+		expected colexectestutils.Tuples
+		strategy execinfrapb.ExecStats_JoinStrategy
+	}{
+		{
+			tuples:    tuples,
+			threshold: 2,
+			expected:  colexectestutils.Tuples{{1, 0}, {2, 0}, {3, 1}, {4, 1}},
+			strategy:  execinfrapb.ExecStats_LOOKUP_THEN_HASH_JOIN,
+		},
+		{
+			tuples:    tuples,
+			threshold: 3,
+			expected:  colexectestutils.Tuples{{1, 0}, {2, 0}, {3, 0}, {4, 1}},
+			strategy:  execinfrapb.ExecStats_LOOKUP_THEN_HASH_JOIN,
+		},
+		{
+			tuples:    tuples,
+			threshold: 4,
+			expected:  colexectestutils.Tuples{{1, 0}, {2, 0}, {3, 0}, {4, 0}},
+			strategy:  execinfrapb.ExecStats_LOOKUP_JOIN,
+		},
+		{
+			tuples:    colexectestutils.Tuples{},
+			threshold: 1,
+			expected:  colexectestutils.Tuples{},
+			strategy:  execinfrapb.ExecStats_LOOKUP_JOIN,
+		},
+		{
+			tuples:      tuples,
+			threshold:   3,
+			bufferInput: true,
+			expected:    colexectestutils.Tuples{{1, 1}, {2, 1}, {3, 1}, {4, 1}},
+			strategy:    execinfrapb.ExecStats_HASH_JOIN,
+		},
+		{
+			tuples:      tuples,
+			threshold:   4,
+			bufferInput: true,
+			expected:    colexectestutils.Tuples{{1, 0}, {2, 0}, {3, 0}, {4, 0}},
+			strategy:    execinfrapb.ExecStats_LOOKUP_JOIN,
+		},
+		{
+			tuples:      tuples,
+			threshold:   4,
+			bufferInput: true,
+			memoryLimit: 1,
+			expected:    colexectestutils.Tuples{{1, 1}, {2, 1}, {3, 1}, {4, 1}},
+			strategy:    execinfrapb.ExecStats_HASH_JOIN,
+		},
+	}
+
+	ctx := context.Background()
+	typs := []*types.T{types.Int}
+	// The joins are replaced by operators that tag their input rows.
+	makeJoin := func(tag int64) AdaptiveJoinConstructor {
+		return func(input colexecop.Operator) (colexecop.Operator, colexecop.KVReader, error) {
+			op, err := colexecbase.NewConstOp(testAllocator, input, types.Int, tag, 1 /* outputIdx */)
+			return op, nil, err
+		}
+	}
+	for _, tc := range tcs {
+		memoryLimit := tc.memoryLimit
+		if memoryLimit == 0 {
+			memoryLimit = math.MaxInt64
+		}
+		newAdaptiveJoiner := func(input colexecop.Operator) (*AdaptiveJoiner, error) {
+			return NewAdaptiveJoiner(
+				testAllocator, memoryLimit, input, typs, tc.threshold, tc.bufferInput,
+				makeJoin(0), makeJoin(1),
+			)
+		}
+		colexectestutils.RunTestsWithoutAllNullsInjection(t, testAllocator, []colexectestutils.Tuples{tc.tuples}, [][]*types.T{typs}, tc.expected, colexectestutils.OrderedVerifier, func(input []colexecop.Operator) (colexecop.Operator, error) {
+			return newAdaptiveJoiner(input[0])
+		})
+
+		// Check that the join strategy is reported correctly once all rows have
+		// been emitted.
+		a, err := newAdaptiveJoiner(colexectestutils.NewOpTestInput(testAllocator, 1 /* batchSize */, tc.tuples, typs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.Init()
+		for b := a.Next(ctx); b.Length() > 0; b = a.Next(ctx) {
+		}
+		if strategy := a.GetJoinStrategy(); strategy != tc.strategy {
+			t.Errorf("expected join strategy %s, found %s", tc.strategy, strategy)
+		}
+	}
+}
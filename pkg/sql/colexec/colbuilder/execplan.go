@@ -37,6 +37,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedidx"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -219,6 +221,60 @@ func supportedNatively(spec *execinfrapb.ProcessorSpec) error {
 		}
 		return nil
 
+	case spec.Core.JoinReader != nil:
+		if !spec.Core.JoinReader.LookupExpr.Empty() {
+			return errors.Newf("lookup joins with lookup expressions are not supported")
+		}
+		if spec.Core.JoinReader.LeftJoinWithPairedJoiner || spec.Core.JoinReader.OutputGroupContinuationForLeftRow {
+			return errors.Newf("paired lookup joins are not supported")
+		}
+		switch spec.Core.JoinReader.Type {
+		case descpb.InnerJoin:
+		case descpb.LeftOuterJoin, descpb.LeftSemiJoin, descpb.LeftAntiJoin:
+			if len(spec.Core.JoinReader.LookupColumns) == 0 {
+				return errors.Newf("can't plan vectorized non-inner index joins")
+			}
+			if !spec.Core.JoinReader.OnExpr.Empty() {
+				return errors.Newf("can't plan vectorized non-inner lookup joins with ON expressions")
+			}
+		default:
+			return errors.Newf("lookup join of type %s is not supported", spec.Core.JoinReader.Type)
+		}
+		return nil
+
+	case spec.Core.ZigzagJoiner != nil:
+		if len(spec.Core.ZigzagJoiner.Tables) != 2 {
+			return errors.Newf("zigzag joins of %d tables are not supported", len(spec.Core.ZigzagJoiner.Tables))
+		}
+		if spec.Core.ZigzagJoiner.Type != descpb.InnerJoin {
+			return errors.Newf("zigzag join of type %s is not supported", spec.Core.ZigzagJoiner.Type)
+		}
+		return nil
+
+	case spec.Core.InvertedJoiner != nil:
+		if spec.Core.InvertedJoiner.OutputGroupContinuationForLeftRow {
+			return errors.Newf("paired inverted joins are not supported")
+		}
+		switch spec.Core.InvertedJoiner.Type {
+		case descpb.InnerJoin, descpb.LeftOuterJoin, descpb.LeftSemiJoin, descpb.LeftAntiJoin:
+		default:
+			return errors.Newf("inverted join of type %s is not supported", spec.Core.InvertedJoiner.Type)
+		}
+		return nil
+
+	case spec.Core.InvertedFilterer != nil:
+		invertedColIdx := int(spec.Core.InvertedFilterer.InvertedColIdx)
+		if len(spec.Input) != 1 || invertedColIdx >= len(spec.Input[0].ColumnTypes) {
+			return errors.Newf("inverted filterer with invalid inverted column is not supported")
+		}
+		if spec.Input[0].ColumnTypes[invertedColIdx].Family() != types.BytesFamily {
+			return errors.Newf("inverted filterer is supported only with the inverted column of bytes type")
+		}
+		if len(spec.Input[0].ColumnTypes) < 2 {
+			return errors.Newf("inverted filterer without non-inverted columns is not supported")
+		}
+		return nil
+
 	case spec.Core.Filterer != nil:
 		return nil
 
@@ -242,6 +298,9 @@ func supportedNatively(spec *execinfrapb.ProcessorSpec) error {
 	case spec.Core.Ordinality != nil:
 		return nil
 
+	case spec.Core.ProjectSet != nil:
+		return nil
+
 	case spec.Core.HashJoiner != nil:
 		if !spec.Core.HashJoiner.OnExpr.Empty() && spec.Core.HashJoiner.Type != descpb.InnerJoin {
 			return errors.Newf("can't plan vectorized non-inner hash joins with ON expressions")
@@ -259,22 +318,13 @@ func supportedNatively(spec *execinfrapb.ProcessorSpec) error {
 
 	case spec.Core.Windower != nil:
 		for _, wf := range spec.Core.Windower.WindowFns {
-			if wf.Frame != nil {
-				frame, err := wf.Frame.ConvertToAST()
-				if err != nil {
-					return err
-				}
-				if !frame.IsDefaultFrame() {
-					return errors.Newf("window functions with non-default window frames are not supported")
-				}
-			}
 			if wf.FilterColIdx != tree.NoColumnIdx {
 				return errors.Newf("window functions with FILTER clause are not supported")
 			}
 			if wf.Func.AggregateFunc != nil {
-				return errors.Newf("aggregate functions used as window functions are not supported")
+				// All aggregate functions are supported as window functions.
+				continue
 			}
-
 			if _, supported := colexecwindow.SupportedWindowFns[*wf.Func.WindowFunc]; !supported {
 				return errors.Newf("window function %s is not supported", wf.String())
 			}
@@ -505,6 +555,70 @@ func (r opResult) createDiskBackedSort(
 	), nil
 }
 
+// createDiskBackedHashJoin creates a new hash joiner with the given spec that
+// spills to disk when it exceeds its memory limit, unless disk spilling is
+// disabled by the testing knobs.
+func (r opResult) createDiskBackedHashJoin(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	args *colexecargs.NewColOperatorArgs,
+	hjSpec colexecjoin.HashJoinerSpec,
+	leftInput, rightInput colexecop.Operator,
+	factory coldata.ColumnFactory,
+) colexecop.Operator {
+	processorID := args.Spec.ProcessorID
+	memoryLimit := execinfra.GetWorkMemLimit(flowCtx.Cfg)
+	useStreamingMemAccountForBuffering := args.TestingKnobs.UseStreamingMemAccountForBuffering
+	var hashJoinerMemMonitorName string
+	var hashJoinerMemAccount *mon.BoundAccount
+	var hashJoinerUnlimitedAllocator *colmem.Allocator
+	if useStreamingMemAccountForBuffering {
+		hashJoinerMemAccount = args.StreamingMemAccount
+		hashJoinerUnlimitedAllocator = colmem.NewAllocator(ctx, args.StreamingMemAccount, factory)
+	} else {
+		opName := "hash-joiner"
+		hashJoinerMemAccount, hashJoinerMemMonitorName = r.createMemAccountForSpillStrategy(
+			ctx, flowCtx, opName, processorID,
+		)
+		hashJoinerUnlimitedAllocator = colmem.NewAllocator(
+			ctx, r.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName, processorID), factory,
+		)
+	}
+	inMemoryHashJoiner := colexecjoin.NewHashJoiner(
+		colmem.NewAllocator(ctx, hashJoinerMemAccount, factory),
+		hashJoinerUnlimitedAllocator, hjSpec, leftInput, rightInput,
+		colexecjoin.HashJoinerInitialNumBuckets, memoryLimit,
+	)
+	if useStreamingMemAccountForBuffering || args.TestingKnobs.DiskSpillingDisabled {
+		// We will not be creating a disk-backed hash joiner because we're
+		// running a test that explicitly asked for only in-memory hash joiner.
+		return inMemoryHashJoiner
+	}
+	opName := "external-hash-joiner"
+	diskAccount := r.createDiskAccount(ctx, flowCtx, opName, processorID)
+	return colexec.NewTwoInputDiskSpiller(
+		leftInput, rightInput, inMemoryHashJoiner.(colexecop.BufferingInMemoryOperator),
+		hashJoinerMemMonitorName,
+		func(inputOne, inputTwo colexecop.Operator) colexecop.Operator {
+			unlimitedAllocator := colmem.NewAllocator(
+				ctx, r.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName, processorID), factory,
+			)
+			ehj := colexec.NewExternalHashJoiner(
+				unlimitedAllocator,
+				flowCtx,
+				args,
+				hjSpec,
+				inputOne, inputTwo,
+				r.makeDiskBackedSorterConstructor(ctx, flowCtx, args, opName, factory),
+				diskAccount,
+			)
+			r.ToClose = append(r.ToClose, ehj.(colexecop.Closer))
+			return ehj
+		},
+		args.TestingKnobs.SpillingCallbackFn,
+	)
+}
+
 // makeDistBackedSorterConstructors creates a DiskBackedSorterConstructor that
 // can be used by the hash-based partitioner.
 // NOTE: unless DelegateFDAcquisitions testing knob is set to true, it is up to
@@ -683,7 +797,6 @@ func NewColOperator(
 	}
 	streamingMemAccount := args.StreamingMemAccount
 	streamingAllocator := colmem.NewAllocator(ctx, streamingMemAccount, factory)
-	useStreamingMemAccountForBuffering := args.TestingKnobs.UseStreamingMemAccountForBuffering
 	if args.ExprHelper == nil {
 		args.ExprHelper = colexecargs.NewExprHelper()
 	}
@@ -780,6 +893,217 @@ func NewColOperator(
 			result.ColumnTypes = scanOp.ResultTypes
 			result.ToClose = append(result.ToClose, scanOp)
 
+		case core.JoinReader != nil:
+			if err := checkNumIn(inputs, 1); err != nil {
+				return r, err
+			}
+
+			opName := "join-reader"
+			bufferingAllocator := colmem.NewAllocator(
+				ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName, spec.ProcessorID), factory,
+			)
+			if core.JoinReader.AdaptiveThreshold > 0 && len(core.JoinReader.LookupColumns) > 0 &&
+				!core.JoinReader.MaintainOrdering {
+				// The join is planned as an adaptive join, which performs
+				// either the lookup join or a hash join against a full scan of
+				// the index depending on the actual number of input rows.
+				inputTypes := spec.Input[0].ColumnTypes
+				var joinReaderOp *colfetcher.ColJoinReader
+				makeLookupJoin := func(input colexecop.Operator) (colexecop.Operator, colexecop.KVReader, error) {
+					var err error
+					joinReaderOp, err = colfetcher.NewColJoinReader(
+						ctx, streamingAllocator, bufferingAllocator, flowCtx, evalCtx,
+						input, inputTypes, core.JoinReader, post,
+					)
+					if err != nil {
+						return nil, nil, err
+					}
+					result.MetadataSources = append(result.MetadataSources, joinReaderOp)
+					result.Releasables = append(result.Releasables, joinReaderOp)
+					result.ToClose = append(result.ToClose, joinReaderOp)
+					return joinReaderOp, joinReaderOp, nil
+				}
+				makeHashJoin := func(input colexecop.Operator) (colexecop.Operator, colexecop.KVReader, error) {
+					trSpec, keyCols, err := joinReaderOp.FullScanSpec(core.JoinReader)
+					if err != nil {
+						return nil, nil, err
+					}
+					scanOp, err := colfetcher.NewColBatchScan(
+						ctx, streamingAllocator, flowCtx, evalCtx, trSpec, &execinfrapb.PostProcessSpec{},
+						0, /* estimatedRowCount */
+					)
+					if err != nil {
+						return nil, nil, err
+					}
+					result.MetadataSources = append(result.MetadataSources, scanOp)
+					result.Releasables = append(result.Releasables, scanOp)
+					result.ToClose = append(result.ToClose, scanOp)
+					hjSpec := colexecjoin.MakeHashJoinerSpec(
+						core.JoinReader.Type,
+						core.JoinReader.LookupColumns,
+						keyCols,
+						inputTypes,
+						scanOp.ResultTypes,
+						core.JoinReader.LookupColumnsAreKey,
+					)
+					return result.createDiskBackedHashJoin(
+						ctx, flowCtx, args, hjSpec, input, colexecutils.NewCancelChecker(scanOp), factory,
+					), scanOp, nil
+				}
+				adaptiveJoiner, err := colexec.NewAdaptiveJoiner(
+					colmem.NewAllocator(
+						ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, "adaptive-joiner", spec.ProcessorID), factory,
+					),
+					execinfra.GetWorkMemLimit(flowCtx.Cfg), inputs[0], inputTypes,
+					core.JoinReader.AdaptiveThreshold, core.JoinReader.AdaptiveBufferInput,
+					makeLookupJoin, makeHashJoin,
+				)
+				if err != nil {
+					return r, err
+				}
+				result.Op = adaptiveJoiner
+				if args.TestingKnobs.PlanInvariantsCheckers {
+					result.Op = colexec.NewInvariantsChecker(result.Op)
+				}
+				result.KVReader = adaptiveJoiner
+				result.Op = colexecutils.NewCancelChecker(result.Op)
+				result.ColumnTypes = joinReaderOp.ResultTypes
+			} else {
+				joinReaderOp, err := colfetcher.NewColJoinReader(
+					ctx, streamingAllocator, bufferingAllocator, flowCtx, evalCtx,
+					inputs[0], spec.Input[0].ColumnTypes, core.JoinReader, post,
+				)
+				if err != nil {
+					return r, err
+				}
+				result.Op = joinReaderOp
+				if args.TestingKnobs.PlanInvariantsCheckers {
+					result.Op = colexec.NewInvariantsChecker(result.Op)
+				}
+				result.KVReader = joinReaderOp
+				result.MetadataSources = append(result.MetadataSources, result.Op.(colexecop.MetadataSource))
+				result.Releasables = append(result.Releasables, joinReaderOp)
+				result.Op = colexecutils.NewCancelChecker(result.Op)
+				result.ColumnTypes = joinReaderOp.ResultTypes
+				result.ToClose = append(result.ToClose, joinReaderOp)
+			}
+
+			if !core.JoinReader.OnExpr.Empty() {
+				// Only inner lookup joins with ON expressions are supported, so
+				// the ON expression can be planned as a filter on top.
+				if err = result.planAndMaybeWrapFilter(
+					ctx, flowCtx, evalCtx, args, spec.ProcessorID, core.JoinReader.OnExpr, factory,
+				); err != nil {
+					return r, err
+				}
+			}
+
+		case core.ZigzagJoiner != nil:
+			if err := checkNumIn(inputs, 0); err != nil {
+				return r, err
+			}
+
+			opName := "zigzag-joiner"
+			bufferingAllocator := colmem.NewAllocator(
+				ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName, spec.ProcessorID), factory,
+			)
+			zigzagJoinerOp, err := colfetcher.NewColZigzagJoiner(
+				ctx, streamingAllocator, bufferingAllocator, flowCtx, evalCtx, core.ZigzagJoiner, post,
+			)
+			if err != nil {
+				return r, err
+			}
+			result.Op = zigzagJoinerOp
+			if args.TestingKnobs.PlanInvariantsCheckers {
+				result.Op = colexec.NewInvariantsChecker(result.Op)
+			}
+			result.KVReader = zigzagJoinerOp
+			result.MetadataSources = append(result.MetadataSources, result.Op.(colexecop.MetadataSource))
+			result.Releasables = append(result.Releasables, zigzagJoinerOp)
+			result.Op = colexecutils.NewCancelChecker(result.Op)
+			result.ColumnTypes = zigzagJoinerOp.ResultTypes
+			result.ToClose = append(result.ToClose, zigzagJoinerOp)
+
+			if !core.ZigzagJoiner.OnExpr.Empty() {
+				// Only inner zigzag joins are supported, so the ON expression
+				// can be planned as a filter on top.
+				if err = result.planAndMaybeWrapFilter(
+					ctx, flowCtx, evalCtx, args, spec.ProcessorID, core.ZigzagJoiner.OnExpr, factory,
+				); err != nil {
+					return r, err
+				}
+			}
+
+		case core.InvertedJoiner != nil:
+			if err := checkNumIn(inputs, 1); err != nil {
+				return r, err
+			}
+
+			opName := "inverted-joiner"
+			bufferingAllocator := colmem.NewAllocator(
+				ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName, spec.ProcessorID), factory,
+			)
+			invertedJoinerOp, err := colfetcher.NewColInvertedJoiner(
+				ctx, streamingAllocator, bufferingAllocator, flowCtx, evalCtx,
+				inputs[0], spec.Input[0].ColumnTypes, core.InvertedJoiner,
+			)
+			if err != nil {
+				return r, err
+			}
+			result.Op = invertedJoinerOp
+			if args.TestingKnobs.PlanInvariantsCheckers {
+				result.Op = colexec.NewInvariantsChecker(result.Op)
+			}
+			result.KVReader = invertedJoinerOp
+			result.MetadataSources = append(result.MetadataSources, result.Op.(colexecop.MetadataSource))
+			result.Releasables = append(result.Releasables, invertedJoinerOp)
+			result.Op = colexecutils.NewCancelChecker(result.Op)
+			result.ColumnTypes = invertedJoinerOp.ResultTypes
+			result.ToClose = append(result.ToClose, invertedJoinerOp)
+
+		case core.InvertedFilterer != nil:
+			if err := checkNumIn(inputs, 1); err != nil {
+				return r, err
+			}
+
+			var preFilterer inverted.PreFilterer
+			var preFilterState interface{}
+			if preFiltererSpec := core.InvertedFilterer.PreFiltererSpec; preFiltererSpec != nil {
+				semaCtx := flowCtx.TypeResolverFactory.NewSemaContext(evalCtx.Txn)
+				var exprHelper execinfrapb.ExprHelper
+				colTypes := []*types.T{preFiltererSpec.Type}
+				if err := exprHelper.Init(preFiltererSpec.Expression, colTypes, semaCtx, evalCtx); err != nil {
+					return r, err
+				}
+				preFilterer, preFilterState, err = invertedidx.NewBoundPreFilterer(preFiltererSpec.Type, exprHelper.Expr)
+				if err != nil {
+					return r, err
+				}
+			}
+			opName := "inverted-filterer"
+			// We are using unlimited memory monitors here because the inverted
+			// filterer itself is responsible for making sure that we stay
+			// within the memory limit, and it will fall back to disk if
+			// necessary.
+			unlimitedAllocator := colmem.NewAllocator(
+				ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName, spec.ProcessorID), factory,
+			)
+			mapAllocator := colmem.NewAllocator(
+				ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName+"-dedup", spec.ProcessorID), factory,
+			)
+			diskAccount := result.createDiskAccount(ctx, flowCtx, opName, spec.ProcessorID)
+			result.Op, err = colexec.NewInvertedFilterer(
+				streamingAllocator, unlimitedAllocator, mapAllocator, execinfra.GetWorkMemLimit(flowCtx.Cfg),
+				args.DiskQueueCfg, args.FDSemaphore, diskAccount, inputs[0], spec.Input[0].ColumnTypes,
+				core.InvertedFilterer, preFilterer, preFilterState,
+			)
+			if err != nil {
+				return r, err
+			}
+			result.ToClose = append(result.ToClose, result.Op.(colexecop.Closer))
+			result.ColumnTypes = make([]*types.T, len(spec.Input[0].ColumnTypes))
+			copy(result.ColumnTypes, spec.Input[0].ColumnTypes)
+
 		case core.Filterer != nil:
 			if err := checkNumIn(inputs, 1); err != nil {
 				return r, err
@@ -998,6 +1322,22 @@ func NewColOperator(
 			result.Op = colexecbase.NewOrdinalityOp(streamingAllocator, inputs[0], outputIdx)
 			result.ColumnTypes = appendOneType(spec.Input[0].ColumnTypes, types.Int)
 
+		case core.ProjectSet != nil:
+			if err := checkNumIn(inputs, 1); err != nil {
+				return r, err
+			}
+			result.Op, err = colexec.NewProjectSetOp(
+				streamingAllocator, flowCtx, evalCtx, inputs[0], spec.Input[0].ColumnTypes,
+				core.ProjectSet, execinfra.GetWorkMemLimit(flowCtx.Cfg),
+			)
+			if err != nil {
+				return r, err
+			}
+			result.ToClose = append(result.ToClose, result.Op.(colexecop.Closer))
+			result.ColumnTypes = make([]*types.T, 0, len(spec.Input[0].ColumnTypes)+len(core.ProjectSet.GeneratedColumns))
+			result.ColumnTypes = append(result.ColumnTypes, spec.Input[0].ColumnTypes...)
+			result.ColumnTypes = append(result.ColumnTypes, core.ProjectSet.GeneratedColumns...)
+
 		case core.HashJoiner != nil:
 			if err := checkNumIn(inputs, 2); err != nil {
 				return r, err
@@ -1027,21 +1367,6 @@ func NewColOperator(
 				)
 				result.ToClose = append(result.ToClose, result.Op.(colexecop.Closer))
 			} else {
-				var hashJoinerMemMonitorName string
-				var hashJoinerMemAccount *mon.BoundAccount
-				var hashJoinerUnlimitedAllocator *colmem.Allocator
-				if useStreamingMemAccountForBuffering {
-					hashJoinerMemAccount = streamingMemAccount
-					hashJoinerUnlimitedAllocator = streamingAllocator
-				} else {
-					opName := "hash-joiner"
-					hashJoinerMemAccount, hashJoinerMemMonitorName = result.createMemAccountForSpillStrategy(
-						ctx, flowCtx, opName, spec.ProcessorID,
-					)
-					hashJoinerUnlimitedAllocator = colmem.NewAllocator(
-						ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName, spec.ProcessorID), factory,
-					)
-				}
 				hjSpec := colexecjoin.MakeHashJoinerSpec(
 					core.HashJoiner.Type,
 					core.HashJoiner.LeftEqColumns,
@@ -1050,42 +1375,9 @@ func NewColOperator(
 					rightTypes,
 					core.HashJoiner.RightEqColumnsAreKey,
 				)
-
-				inMemoryHashJoiner := colexecjoin.NewHashJoiner(
-					colmem.NewAllocator(ctx, hashJoinerMemAccount, factory),
-					hashJoinerUnlimitedAllocator, hjSpec, inputs[0], inputs[1],
-					colexecjoin.HashJoinerInitialNumBuckets, memoryLimit,
+				result.Op = result.createDiskBackedHashJoin(
+					ctx, flowCtx, args, hjSpec, inputs[0], inputs[1], factory,
 				)
-				if useStreamingMemAccountForBuffering || args.TestingKnobs.DiskSpillingDisabled {
-					// We will not be creating a disk-backed hash joiner because
-					// we're running a test that explicitly asked for only
-					// in-memory hash joiner.
-					result.Op = inMemoryHashJoiner
-				} else {
-					opName := "external-hash-joiner"
-					diskAccount := result.createDiskAccount(ctx, flowCtx, opName, spec.ProcessorID)
-					result.Op = colexec.NewTwoInputDiskSpiller(
-						inputs[0], inputs[1], inMemoryHashJoiner.(colexecop.BufferingInMemoryOperator),
-						hashJoinerMemMonitorName,
-						func(inputOne, inputTwo colexecop.Operator) colexecop.Operator {
-							unlimitedAllocator := colmem.NewAllocator(
-								ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName, spec.ProcessorID), factory,
-							)
-							ehj := colexec.NewExternalHashJoiner(
-								unlimitedAllocator,
-								flowCtx,
-								args,
-								hjSpec,
-								inputOne, inputTwo,
-								result.makeDiskBackedSorterConstructor(ctx, flowCtx, args, opName, factory),
-								diskAccount,
-							)
-							result.ToClose = append(result.ToClose, ehj.(colexecop.Closer))
-							return ehj
-						},
-						args.TestingKnobs.SpillingCallbackFn,
-					)
-				}
 			}
 
 			result.ColumnTypes = core.HashJoiner.Type.MakeOutputTypes(leftTypes, rightTypes)
@@ -1181,7 +1473,6 @@ func NewColOperator(
 				copy(typs, result.ColumnTypes)
 				tempColOffset, partitionColIdx := uint32(0), tree.NoColumnIdx
 				peersColIdx := tree.NoColumnIdx
-				windowFn := *wf.Func.WindowFunc
 				if len(core.Windower.PartitionBy) > 0 {
 					// TODO(yuzefovich): add support for hashing partitioner
 					// (probably by leveraging hash routers once we can
@@ -1215,7 +1506,7 @@ func NewColOperator(
 				if err != nil {
 					return r, err
 				}
-				if colexecwindow.WindowFnNeedsPeersInfo(*wf.Func.WindowFunc) {
+				if colexecwindow.WindowFnNeedsPeersInfo(&wf) {
 					peersColIdx = int(wf.OutputColIdx + tempColOffset)
 					input, err = colexecwindow.NewWindowPeerGrouper(
 						streamingAllocator, input, typs, wf.Ordering.Columns,
@@ -1227,38 +1518,116 @@ func NewColOperator(
 					typs[len(typs)-1] = types.Bool
 				}
 
+				argTypes := make([]*types.T, len(wf.ArgsIdxs))
+				argIdxs := make([]int, len(wf.ArgsIdxs))
+				for i, idx := range wf.ArgsIdxs {
+					argIdxs[i] = int(idx)
+					argTypes[i] = typs[idx]
+				}
+				_, returnType, err := execinfrapb.GetWindowFunctionInfo(wf.Func, argTypes...)
+				if err != nil {
+					return r, err
+				}
+
 				outputIdx := int(wf.OutputColIdx + tempColOffset)
-				switch windowFn {
-				case execinfrapb.WindowerSpec_ROW_NUMBER:
-					result.Op = colexecwindow.NewRowNumberOperator(streamingAllocator, input, outputIdx, partitionColIdx)
-				case execinfrapb.WindowerSpec_RANK, execinfrapb.WindowerSpec_DENSE_RANK:
-					result.Op, err = colexecwindow.NewRankOperator(
-						streamingAllocator, input, windowFn, wf.Ordering.Columns,
-						outputIdx, partitionColIdx, peersColIdx,
+				if wf.Func.WindowFunc != nil {
+					windowFn := *wf.Func.WindowFunc
+					switch windowFn {
+					case execinfrapb.WindowerSpec_ROW_NUMBER:
+						result.Op = colexecwindow.NewRowNumberOperator(streamingAllocator, input, outputIdx, partitionColIdx)
+					case execinfrapb.WindowerSpec_RANK, execinfrapb.WindowerSpec_DENSE_RANK:
+						result.Op, err = colexecwindow.NewRankOperator(
+							streamingAllocator, input, windowFn, wf.Ordering.Columns,
+							outputIdx, partitionColIdx, peersColIdx,
+						)
+					case execinfrapb.WindowerSpec_PERCENT_RANK, execinfrapb.WindowerSpec_CUME_DIST:
+						// We are using an unlimited memory monitor here because
+						// relative rank operators themselves are responsible for
+						// making sure that we stay within the memory limit, and
+						// they will fall back to disk if necessary.
+						opName := opNamePrefix + "relative-rank"
+						unlimitedAllocator := colmem.NewAllocator(
+							ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName, spec.ProcessorID), factory,
+						)
+						diskAcc := result.createDiskAccount(ctx, flowCtx, opName, spec.ProcessorID)
+						result.Op, err = colexecwindow.NewRelativeRankOperator(
+							unlimitedAllocator, execinfra.GetWorkMemLimit(flowCtx.Cfg), args.DiskQueueCfg,
+							args.FDSemaphore, input, typs, windowFn, wf.Ordering.Columns,
+							outputIdx, partitionColIdx, peersColIdx, diskAcc,
+						)
+					case execinfrapb.WindowerSpec_NTILE:
+						windowArgs := result.makeWindowArgs(
+							ctx, flowCtx, args, evalCtx, opNamePrefix+"ntile", spec.ProcessorID, factory,
+							input, typs, outputIdx, partitionColIdx, peersColIdx,
+						)
+						result.Op = colexecwindow.NewNTileOperator(windowArgs, argIdxs[0])
+					case execinfrapb.WindowerSpec_LAG, execinfrapb.WindowerSpec_LEAD:
+						windowArgs := result.makeWindowArgs(
+							ctx, flowCtx, args, evalCtx, opNamePrefix+"lead-lag", spec.ProcessorID, factory,
+							input, typs, outputIdx, partitionColIdx, peersColIdx,
+						)
+						result.Op, err = colexecwindow.NewLeadLagOperator(windowArgs, windowFn, argIdxs)
+					case execinfrapb.WindowerSpec_FIRST_VALUE, execinfrapb.WindowerSpec_LAST_VALUE,
+						execinfrapb.WindowerSpec_NTH_VALUE:
+						windowArgs := result.makeWindowArgs(
+							ctx, flowCtx, args, evalCtx, opNamePrefix+"value", spec.ProcessorID, factory,
+							input, typs, outputIdx, partitionColIdx, peersColIdx,
+						)
+						result.Op, err = colexecwindow.NewValueWindowOperator(
+							windowArgs, windowFn, wf.Frame, &wf.Ordering, argIdxs,
+						)
+					default:
+						return r, errors.AssertionFailedf("window function %s is not supported", wf.String())
+					}
+				} else {
+					aggType := *wf.Func.AggregateFunc
+					windowArgs := result.makeWindowArgs(
+						ctx, flowCtx, args, evalCtx, opNamePrefix+"aggregate", spec.ProcessorID, factory,
+						input, typs, outputIdx, partitionColIdx, peersColIdx,
 					)
-				case execinfrapb.WindowerSpec_PERCENT_RANK, execinfrapb.WindowerSpec_CUME_DIST:
-					// We are using an unlimited memory monitor here because
-					// relative rank operators themselves are responsible for
-					// making sure that we stay within the memory limit, and
-					// they will fall back to disk if necessary.
-					opName := opNamePrefix + "relative-rank"
-					unlimitedAllocator := colmem.NewAllocator(
-						ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName, spec.ProcessorID), factory,
+					// The aggregate function is given a batch that consists only
+					// of its arguments.
+					aggArgIdxs := make([]uint32, len(argIdxs))
+					for i := range aggArgIdxs {
+						aggArgIdxs[i] = uint32(i)
+					}
+					aggregations := []execinfrapb.AggregatorSpec_Aggregation{{Func: aggType, ColIdx: aggArgIdxs}}
+					newAggArgs := &colexecagg.NewAggregatorArgs{
+						Allocator:  windowArgs.MainAllocator,
+						InputTypes: argTypes,
+						Spec:       &execinfrapb.AggregatorSpec{Aggregations: aggregations},
+						EvalCtx:    evalCtx,
+					}
+					semaCtx := flowCtx.TypeResolverFactory.NewSemaContext(evalCtx.Txn)
+					newAggArgs.Constructors, newAggArgs.ConstArguments, newAggArgs.OutputTypes, err = colexecagg.ProcessAggregations(
+						evalCtx, semaCtx, aggregations, argTypes,
 					)
-					diskAcc := result.createDiskAccount(ctx, flowCtx, opName, spec.ProcessorID)
-					result.Op, err = colexecwindow.NewRelativeRankOperator(
-						unlimitedAllocator, execinfra.GetWorkMemLimit(flowCtx.Cfg), args.DiskQueueCfg,
-						args.FDSemaphore, input, typs, windowFn, wf.Ordering.Columns,
-						outputIdx, partitionColIdx, peersColIdx, diskAcc,
+					if err != nil {
+						return r, err
+					}
+					var aggAlloc *colexecagg.AggregateFuncsAlloc
+					var inputArgsConverter *colconv.VecToDatumConverter
+					var toClose colexecop.Closers
+					aggAlloc, inputArgsConverter, toClose, err = colexecagg.NewAggregateFuncsAlloc(
+						newAggArgs, 1 /* allocSize */, true, /* isHashAgg */
 					)
-					// NewRelativeRankOperator sometimes returns a constOp when
-					// there are no ordering columns, so we check that the
-					// returned operator is a Closer.
-					if c, ok := result.Op.(colexecop.Closer); ok {
-						result.ToClose = append(result.ToClose, c)
+					if err != nil {
+						return r, err
 					}
-				default:
-					return r, errors.AssertionFailedf("window function %s is not supported", wf.String())
+					result.Op, err = colexecwindow.NewWindowAggregatorOperator(
+						windowArgs, aggType, wf.Frame, &wf.Ordering, argIdxs, returnType,
+						aggAlloc, inputArgsConverter, toClose,
+					)
+				}
+				if err != nil {
+					return r, err
+				}
+				// Some window operators need to be closed (note that
+				// NewRelativeRankOperator sometimes returns a constOp when
+				// there are no ordering columns, so we check that the returned
+				// operator is a Closer).
+				if c, ok := result.Op.(colexecop.Closer); ok {
+					result.ToClose = append(result.ToClose, c)
 				}
 
 				if tempColOffset > 0 {
@@ -1273,10 +1642,6 @@ func NewColOperator(
 					result.Op = colexecbase.NewSimpleProjectOp(result.Op, int(wf.OutputColIdx+tempColOffset), projection)
 				}
 
-				_, returnType, err := execinfrapb.GetWindowFunctionInfo(wf.Func, []*types.T{}...)
-				if err != nil {
-					return r, err
-				}
 				result.ColumnTypes = appendOneType(result.ColumnTypes, returnType)
 				input = result.Op
 			}
@@ -1362,7 +1727,13 @@ func NewColOperator(
 				for j := 0; j < castedIdx; j++ {
 					post.RenderExprs[j].Expr = fmt.Sprintf("@%d", j+1)
 				}
-				post.RenderExprs[castedIdx].Expr = fmt.Sprintf("@%d::%s", i+1, expected.SQLStandardName())
+				typName := expected.SQLStandardName()
+				if expected.UserDefined() {
+					// User-defined types (like domains) can't be resolved by
+					// name in DistSQL, so they are referenced by OID.
+					typName = (&tree.OIDTypeReference{OID: expected.Oid()}).SQLString()
+				}
+				post.RenderExprs[castedIdx].Expr = fmt.Sprintf("@%d::%s", i+1, typName)
 				result.Op = input
 				if err = result.wrapPostProcessSpec(ctx, flowCtx, args, post, resultTypes, factory, err); err != nil {
 					return r, err
@@ -1588,6 +1959,46 @@ func (r opResult) createBufferingUnlimitedMemAccount(
 	return &bufferingMemAccount
 }
 
+// makeWindowArgs creates the memory and disk accounts for a window operator
+// that buffers each partition and returns the arguments for it. We are using
+// unlimited memory monitors here because such operators themselves are
+// responsible for making sure that we stay within the memory limit, and they
+// will fall back to disk if necessary.
+func (r opResult) makeWindowArgs(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	args *colexecargs.NewColOperatorArgs,
+	evalCtx *tree.EvalContext,
+	opName string,
+	processorID int32,
+	factory coldata.ColumnFactory,
+	input colexecop.Operator,
+	inputTypes []*types.T,
+	outputColIdx, partitionColIdx, peersColIdx int,
+) *colexecwindow.WindowArgs {
+	return &colexecwindow.WindowArgs{
+		EvalCtx: evalCtx,
+		MainAllocator: colmem.NewAllocator(
+			ctx, r.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName, processorID), factory,
+		),
+		QueueAllocator: colmem.NewAllocator(
+			ctx, r.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName+"-queue", processorID), factory,
+		),
+		BufferAllocator: colmem.NewAllocator(
+			ctx, r.createBufferingUnlimitedMemAccount(ctx, flowCtx, opName+"-buffer", processorID), factory,
+		),
+		MemoryLimit:     execinfra.GetWorkMemLimit(flowCtx.Cfg),
+		DiskQueueCfg:    args.DiskQueueCfg,
+		FdSemaphore:     args.FDSemaphore,
+		DiskAcc:         r.createDiskAccount(ctx, flowCtx, opName, processorID),
+		Input:           input,
+		InputTypes:      inputTypes,
+		OutputColIdx:    outputColIdx,
+		PartitionColIdx: partitionColIdx,
+		PeersColIdx:     peersColIdx,
+	}
+}
+
 // createDiskAccount instantiates an unlimited disk monitor and a disk account
 // to be used for disk spilling infrastructure in vectorized engine.
 // TODO(azhng): consolidates all allocation monitors/account manage into one
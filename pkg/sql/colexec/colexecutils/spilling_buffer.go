@@ -0,0 +1,369 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexecutils
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+	"github.com/marusama/semaphore"
+)
+
+// SpillingBuffer is a container of tuples that supports random access to the
+// stored tuples by their index. The tuples are kept in memory until the memory
+// limit is reached, at which point all the tuples that are appended afterwards
+// are written to a rewindable disk queue in batches of coldata.BatchSize()
+// length.
+//
+// Only the columns specified at the construction time are stored, and the
+// columns are referenced by their ordinal among the stored columns when the
+// tuples are accessed.
+//
+// Accessing the tuples that have been spilled to disk is the most efficient
+// when it is done in the non-decreasing order of indices. A small number of
+// the most recently read batches are cached in memory, so accesses that go
+// back by less than the number of cached batches don't require rewinding the
+// disk queue either.
+//
+// WARNING: all tuples must be appended before any tuple that has been
+// spilled to disk is accessed (this is a limitation of
+// colcontainer.RewindableQueue interface).
+type SpillingBuffer struct {
+	unlimitedAllocator *colmem.Allocator
+	memoryLimit        int64
+	diskQueueCfg       colcontainer.DiskQueueCfg
+	fdSemaphore        semaphore.Semaphore
+	diskAcc            *mon.BoundAccount
+
+	// storedTypes are the types of the stored columns.
+	storedTypes []*types.T
+	// storedColIdxs are the indices of the stored columns in the batches that
+	// are appended to the buffer.
+	storedColIdxs []int
+
+	// length is the total number of tuples in the buffer.
+	length int
+	// inMemTuples contains the tuples that are kept in memory. These are
+	// always the first tuples in the buffer.
+	inMemTuples *AppendOnlyBufferedBatch
+
+	diskQueue colcontainer.RewindableQueue
+	// numDiskTuples is the number of tuples that have been spilled to disk.
+	numDiskTuples int
+	// diskAppendScratch accumulates the tuples to be written to the disk queue
+	// until it is full.
+	diskAppendScratch coldata.Batch
+	// doneAppending indicates whether the disk queue has been finalized.
+	// Appending to the buffer after that is prohibited.
+	doneAppending bool
+	// numDequeued is the number of batches that have been dequeued from the
+	// disk queue since it was last rewound.
+	numDequeued int
+	// dequeueScratch is the batch into which the batches are dequeued from the
+	// disk queue, and dequeueScratchMemUsage is its memory footprint that is
+	// registered with the allocator.
+	dequeueScratch         coldata.Batch
+	dequeueScratchMemUsage int64
+	// cache contains the most recently dequeued batches. cachedBatchIdxs[i]
+	// is the ordinal of the batch in the disk queue that is stored in
+	// cache[i], or -1 if that slot is unused.
+	cache           []coldata.Batch
+	cachedBatchIdxs []int
+}
+
+// spillingBufferNumCachedBatches is the maximum number of batches dequeued
+// from the disk queue that the SpillingBuffer keeps in memory.
+const spillingBufferNumCachedBatches = 4
+
+// NewSpillingBuffer creates a new SpillingBuffer that will store the columns
+// of the appended batches with the given indices. An unlimited allocator must
+// be passed in, and it must not be shared with anything else. The
+// SpillingBuffer will use this allocator to check whether memory usage exceeds
+// the given memory limit and use disk if so.
+// If fdSemaphore is nil, no Acquire or Release calls will happen. The caller
+// may want to do this if requesting FDs up front.
+func NewSpillingBuffer(
+	unlimitedAllocator *colmem.Allocator,
+	memoryLimit int64,
+	diskQueueCfg colcontainer.DiskQueueCfg,
+	fdSemaphore semaphore.Semaphore,
+	typs []*types.T,
+	diskAcc *mon.BoundAccount,
+	storedColIdxs ...int,
+) *SpillingBuffer {
+	storedTypes := make([]*types.T, len(storedColIdxs))
+	for i, colIdx := range storedColIdxs {
+		storedTypes[i] = typs[colIdx]
+	}
+	return &SpillingBuffer{
+		unlimitedAllocator: unlimitedAllocator,
+		memoryLimit:        memoryLimit,
+		diskQueueCfg:       diskQueueCfg,
+		fdSemaphore:        fdSemaphore,
+		diskAcc:            diskAcc,
+		storedTypes:        storedTypes,
+		storedColIdxs:      storedColIdxs,
+		inMemTuples:        NewAppendOnlyBufferedBatch(unlimitedAllocator, storedTypes, nil /* colsToStore */),
+	}
+}
+
+// AppendTuples adds the tuples with indices in range [startIdx, endIdx) from
+// batch (paying attention to the selection vector) to the buffer.
+func (b *SpillingBuffer) AppendTuples(
+	ctx context.Context, batch coldata.Batch, startIdx, endIdx int,
+) {
+	if b.doneAppending {
+		colexecerror.InternalError(
+			errors.AssertionFailedf("attempted to append to SpillingBuffer after spilled tuples were read"))
+	}
+	if startIdx >= endIdx {
+		return
+	}
+	sel := batch.Selection()
+	if b.numDiskTuples == 0 && b.unlimitedAllocator.Used() < b.memoryLimit {
+		b.unlimitedAllocator.PerformOperation(b.inMemTuples.ColVecs(), func() {
+			for i, colIdx := range b.storedColIdxs {
+				b.inMemTuples.ColVec(i).Append(
+					coldata.SliceArgs{
+						Src:         batch.ColVec(colIdx),
+						Sel:         sel,
+						DestIdx:     b.inMemTuples.Length(),
+						SrcStartIdx: startIdx,
+						SrcEndIdx:   endIdx,
+					},
+				)
+			}
+		})
+		b.inMemTuples.SetLength(b.inMemTuples.Length() + endIdx - startIdx)
+		b.length += endIdx - startIdx
+		return
+	}
+	if b.diskQueue == nil {
+		if err := b.spillToDisk(ctx); err != nil {
+			HandleErrorFromDiskQueue(err)
+		}
+	}
+	for startIdx < endIdx {
+		length := b.diskAppendScratch.Length()
+		toAppend := coldata.BatchSize() - length
+		if toAppend > endIdx-startIdx {
+			toAppend = endIdx - startIdx
+		}
+		b.unlimitedAllocator.PerformOperation(b.diskAppendScratch.ColVecs(), func() {
+			for i, colIdx := range b.storedColIdxs {
+				b.diskAppendScratch.ColVec(i).Copy(
+					coldata.CopySliceArgs{
+						SliceArgs: coldata.SliceArgs{
+							Src:         batch.ColVec(colIdx),
+							Sel:         sel,
+							DestIdx:     length,
+							SrcStartIdx: startIdx,
+							SrcEndIdx:   startIdx + toAppend,
+						},
+					},
+				)
+			}
+		})
+		b.diskAppendScratch.SetLength(length + toAppend)
+		if b.diskAppendScratch.Length() == coldata.BatchSize() {
+			b.flushDiskAppendScratch(ctx)
+		}
+		startIdx += toAppend
+		b.numDiskTuples += toAppend
+		b.length += toAppend
+	}
+}
+
+func (b *SpillingBuffer) numFDsOpenAtAnyGivenTime() int {
+	if b.diskQueueCfg.CacheMode != colcontainer.DiskQueueCacheModeDefault {
+		// The access pattern must be write-everything then read-everything so
+		// either a read FD or a write FD are open at any one point.
+		return 1
+	}
+	// Otherwise, both will be open.
+	return 2
+}
+
+func (b *SpillingBuffer) spillToDisk(ctx context.Context) error {
+	if b.fdSemaphore != nil {
+		if err := b.fdSemaphore.Acquire(ctx, b.numFDsOpenAtAnyGivenTime()); err != nil {
+			return err
+		}
+	}
+	log.VEvent(ctx, 1, "spilled to disk")
+	diskQueue, err := colcontainer.NewRewindableDiskQueue(ctx, b.storedTypes, b.diskQueueCfg, b.diskAcc)
+	if err != nil {
+		return err
+	}
+	b.diskQueue = diskQueue
+	b.diskAppendScratch = b.unlimitedAllocator.NewMemBatchWithFixedCapacity(b.storedTypes, coldata.BatchSize())
+	return nil
+}
+
+// flushDiskAppendScratch writes the accumulated tuples to the disk queue.
+func (b *SpillingBuffer) flushDiskAppendScratch(ctx context.Context) {
+	if err := b.diskQueue.Enqueue(ctx, b.diskAppendScratch); err != nil {
+		HandleErrorFromDiskQueue(err)
+	}
+	b.diskAppendScratch.ResetInternalBatch()
+}
+
+// GetVecWithTuple returns the vector of the stored column with ordinal colIdx
+// that contains the tuple with index idx, the index of that tuple within the
+// vector, and the number of tuples in the vector. The returned vector is
+// valid until the next call to GetVecWithTuple and must not be modified.
+func (b *SpillingBuffer) GetVecWithTuple(
+	ctx context.Context, colIdx, idx int,
+) (_ coldata.Vec, rowIdx int, length int) {
+	if idx < 0 || idx >= b.length {
+		colexecerror.InternalError(errors.AssertionFailedf(
+			"index %d out of range for SpillingBuffer of length %d", idx, b.length))
+	}
+	if idx < b.inMemTuples.Length() {
+		return b.inMemTuples.ColVec(colIdx), idx, b.inMemTuples.Length()
+	}
+	batch := b.getDiskBatch(ctx, (idx-b.inMemTuples.Length())/coldata.BatchSize())
+	return batch.ColVec(colIdx), (idx - b.inMemTuples.Length()) % coldata.BatchSize(), batch.Length()
+}
+
+// getDiskBatch returns the batch with the given ordinal among the batches
+// that have been spilled to disk.
+func (b *SpillingBuffer) getDiskBatch(ctx context.Context, batchIdx int) coldata.Batch {
+	if !b.doneAppending {
+		// Finalize the disk queue.
+		if b.diskAppendScratch.Length() > 0 {
+			b.flushDiskAppendScratch(ctx)
+		}
+		if err := b.diskQueue.Enqueue(ctx, coldata.ZeroBatch); err != nil {
+			HandleErrorFromDiskQueue(err)
+		}
+		b.doneAppending = true
+		b.cache = make([]coldata.Batch, spillingBufferNumCachedBatches)
+		b.cachedBatchIdxs = make([]int, spillingBufferNumCachedBatches)
+		for i := range b.cachedBatchIdxs {
+			b.cachedBatchIdxs[i] = -1
+		}
+	}
+	// The slot with the smallest batch ordinal will be evicted if the batch is
+	// not cached.
+	evictIdx := 0
+	for i, cachedBatchIdx := range b.cachedBatchIdxs {
+		if cachedBatchIdx == batchIdx {
+			return b.cache[i]
+		}
+		if cachedBatchIdx < b.cachedBatchIdxs[evictIdx] {
+			evictIdx = i
+		}
+	}
+	if batchIdx < b.numDequeued {
+		if err := b.diskQueue.Rewind(); err != nil {
+			HandleErrorFromDiskQueue(err)
+		}
+		b.numDequeued = 0
+	}
+	if b.dequeueScratch == nil {
+		// The actual memory footprint of dequeueScratch is registered with the
+		// allocator once the data has been dequeued into it.
+		b.dequeueScratch = b.unlimitedAllocator.NewMemBatchWithFixedCapacity(b.storedTypes, coldata.BatchSize())
+		b.unlimitedAllocator.ReleaseMemory(colmem.GetBatchMemSize(b.dequeueScratch))
+	}
+	for b.numDequeued <= batchIdx {
+		ok, err := b.diskQueue.Dequeue(ctx, b.dequeueScratch)
+		if err != nil {
+			HandleErrorFromDiskQueue(err)
+		}
+		if !ok {
+			colexecerror.InternalError(errors.AssertionFailedf(
+				"failed to dequeue batch %d from SpillingBuffer", batchIdx))
+		}
+		b.numDequeued++
+	}
+	newMemUsage := colmem.GetBatchMemSize(b.dequeueScratch)
+	b.unlimitedAllocator.AdjustMemoryUsage(newMemUsage - b.dequeueScratchMemUsage)
+	b.dequeueScratchMemUsage = newMemUsage
+	if b.cache[evictIdx] == nil {
+		b.cache[evictIdx] = b.unlimitedAllocator.NewMemBatchWithFixedCapacity(b.storedTypes, coldata.BatchSize())
+	}
+	cached := b.cache[evictIdx]
+	cached.ResetInternalBatch()
+	n := b.dequeueScratch.Length()
+	b.unlimitedAllocator.PerformOperation(cached.ColVecs(), func() {
+		for i := range b.storedTypes {
+			cached.ColVec(i).Copy(
+				coldata.CopySliceArgs{
+					SliceArgs: coldata.SliceArgs{
+						Src:       b.dequeueScratch.ColVec(i),
+						SrcEndIdx: n,
+					},
+				},
+			)
+		}
+		cached.SetLength(n)
+	})
+	b.cachedBatchIdxs[evictIdx] = batchIdx
+	return cached
+}
+
+// Length returns the number of tuples in the buffer.
+func (b *SpillingBuffer) Length() int {
+	return b.length
+}
+
+// Spilled returns whether the buffer has spilled to disk.
+func (b *SpillingBuffer) Spilled() bool {
+	return b.diskQueue != nil
+}
+
+// Reset removes all tuples from the buffer, releases the memory used by them,
+// and closes the disk queue, if any.
+func (b *SpillingBuffer) Reset(ctx context.Context) {
+	if err := b.Close(ctx); err != nil {
+		colexecerror.InternalError(err)
+	}
+	b.inMemTuples = NewAppendOnlyBufferedBatch(b.unlimitedAllocator, b.storedTypes, nil /* colsToStore */)
+}
+
+// Close releases the memory used by the buffer and closes the disk queue, if
+// any.
+func (b *SpillingBuffer) Close(ctx context.Context) error {
+	// The allocator is used exclusively by the buffer, so we can release all
+	// of its memory.
+	b.unlimitedAllocator.ReleaseMemory(b.unlimitedAllocator.Used())
+	b.inMemTuples = nil
+	b.diskAppendScratch = nil
+	b.dequeueScratch = nil
+	b.dequeueScratchMemUsage = 0
+	b.cache = nil
+	b.cachedBatchIdxs = nil
+	b.length = 0
+	b.numDiskTuples = 0
+	b.numDequeued = 0
+	b.doneAppending = false
+	if b.diskQueue == nil {
+		return nil
+	}
+	diskQueue := b.diskQueue
+	b.diskQueue = nil
+	if err := diskQueue.Close(ctx); err != nil {
+		return err
+	}
+	if b.fdSemaphore != nil {
+		b.fdSemaphore.Release(b.numFDsOpenAtAnyGivenTime())
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexecutils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coldatatestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/colcontainerutils"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpillingBuffer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	queueCfg, cleanup := colcontainerutils.NewTestingDiskQueueCfg(t, true /* inMem */)
+	defer cleanup()
+
+	ctx := context.Background()
+	rng, _ := randutil.NewPseudoRand()
+	for _, memoryLimit := range []int64{
+		0,
+		10 << 10,                        /* 10 KiB */
+		1<<20 + int64(rng.Intn(63<<20)), /* 1 MiB up to 64 MiB */
+	} {
+		numBatches := 1 + rng.Intn(16)
+		inputBatchSize := 1 + rng.Intn(coldata.BatchSize())
+		log.Infof(ctx, "MemoryLimit=%s/NumBatches=%d/BatchSize=%d",
+			humanizeutil.IBytes(memoryLimit), numBatches, inputBatchSize)
+
+		// We track all input tuples in order to verify the tuples returned by
+		// the buffer.
+		var tuples *AppendOnlyBufferedBatch
+		op := coldatatestutils.NewRandomDataOp(testAllocator, rng, coldatatestutils.RandomDataOpArgs{
+			NumBatches: numBatches,
+			BatchSize:  inputBatchSize,
+			Selection:  true,
+			Nulls:      true,
+			BatchAccumulator: func(b coldata.Batch, typs []*types.T) {
+				if b.Length() == 0 {
+					return
+				}
+				if tuples == nil {
+					tuples = NewAppendOnlyBufferedBatch(testAllocator, typs, nil /* colsToStore */)
+				}
+				tuples.AppendTuples(b, 0 /* startIdx */, b.Length())
+			},
+		})
+		typs := op.Typs()
+
+		// Store a random non-empty subset of the columns.
+		var storedColIdxs []int
+		for colIdx := range typs {
+			if rng.Float64() < 0.5 {
+				storedColIdxs = append(storedColIdxs, colIdx)
+			}
+		}
+		if len(storedColIdxs) == 0 {
+			storedColIdxs = append(storedColIdxs, rng.Intn(len(typs)))
+		}
+
+		// We need to create a separate unlimited allocator for the spilling
+		// buffer so that it could measure only its own memory usage.
+		memAcc := testMemMonitor.MakeBoundAccount()
+		spillingBufferUnlimitedAllocator := colmem.NewAllocator(ctx, &memAcc, testColumnFactory)
+		buf := NewSpillingBuffer(
+			spillingBufferUnlimitedAllocator, memoryLimit, queueCfg,
+			colexecop.NewTestingSemaphore(2), typs, testDiskAcc, storedColIdxs...,
+		)
+
+		op.Init()
+		for {
+			b := op.Next(ctx)
+			if b.Length() == 0 {
+				break
+			}
+			// Append the tuples of the batch in two chunks in order to
+			// exercise the partial appends.
+			splitIdx := rng.Intn(b.Length() + 1)
+			buf.AppendTuples(ctx, b, 0 /* startIdx */, splitIdx)
+			buf.AppendTuples(ctx, b, splitIdx, b.Length())
+		}
+		require.Equal(t, tuples.Length(), buf.Length())
+		if memoryLimit == 0 {
+			require.True(t, buf.Spilled())
+		}
+
+		checkTuple := func(idx int) {
+			for storedIdx, colIdx := range storedColIdxs {
+				vec, rowIdx, length := buf.GetVecWithTuple(ctx, storedIdx, idx)
+				require.Less(t, rowIdx, length)
+				expected := coldata.NewMemBatchWithCapacity([]*types.T{typs[colIdx]}, 1 /* capacity */, testColumnFactory)
+				expected.ColVec(0).Copy(coldata.CopySliceArgs{SliceArgs: coldata.SliceArgs{
+					Src: tuples.ColVec(colIdx), SrcStartIdx: idx, SrcEndIdx: idx + 1,
+				}})
+				expected.SetLength(1)
+				actual := coldata.NewMemBatchWithCapacity([]*types.T{typs[colIdx]}, 1 /* capacity */, testColumnFactory)
+				actual.ColVec(0).Copy(coldata.CopySliceArgs{SliceArgs: coldata.SliceArgs{
+					Src: vec, SrcStartIdx: rowIdx, SrcEndIdx: rowIdx + 1,
+				}})
+				actual.SetLength(1)
+				if expected.ColVec(0).Nulls().NullAt(0) {
+					// The values behind NULLs are undefined.
+					require.True(t, actual.ColVec(0).Nulls().NullAt(0))
+					continue
+				}
+				coldata.AssertEquivalentBatches(t, expected, actual)
+			}
+		}
+		// First, read all tuples in order, then read some tuples in random
+		// order which will require rewinding the disk queue.
+		for idx := 0; idx < buf.Length(); idx++ {
+			checkTuple(idx)
+		}
+		for i := 0; i < 100; i++ {
+			checkTuple(rng.Intn(buf.Length()))
+		}
+
+		// Reuse the buffer after resetting it.
+		buf.Reset(ctx)
+		require.Equal(t, 0, buf.Length())
+		require.NoError(t, buf.Close(ctx))
+		require.Equal(t, int64(0), spillingBufferUnlimitedAllocator.Used())
+		memAcc.Close(ctx)
+	}
+}
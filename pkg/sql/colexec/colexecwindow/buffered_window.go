@@ -0,0 +1,363 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexecwindow
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+	"github.com/marusama/semaphore"
+)
+
+// WindowArgs extracts common arguments to the window operators that buffer
+// each partition before computing the window function.
+type WindowArgs struct {
+	EvalCtx *tree.EvalContext
+	// MainAllocator is used for the output batch and for any state of the
+	// window function that isn't the buffered tuples.
+	MainAllocator *colmem.Allocator
+	// QueueAllocator and BufferAllocator must be unlimited allocators that
+	// are used exclusively by the spilling queue holding the tuples of the
+	// current partition and by the spilling buffer of the window function,
+	// respectively.
+	QueueAllocator  *colmem.Allocator
+	BufferAllocator *colmem.Allocator
+	MemoryLimit     int64
+	DiskQueueCfg    colcontainer.DiskQueueCfg
+	FdSemaphore     semaphore.Semaphore
+	DiskAcc         *mon.BoundAccount
+	Input           colexecop.Operator
+	InputTypes      []*types.T
+	OutputColIdx    int
+	PartitionColIdx int
+	PeersColIdx     int
+}
+
+// BufferedWindowNumRequiredFDs is the minimum number of file descriptors that
+// might be needed by the window operators that buffer each partition: 2 FDs
+// for each of the spilling queue and the spilling buffer plus we use an
+// external sort to handle PARTITION BY and/or ORDER BY clauses.
+const BufferedWindowNumRequiredFDs = 4 + colexecop.ExternalSorterMinPartitions
+
+// bufferedWindowQueueMemLimitFraction defines the fraction of the memory
+// limit that will be given to the spilling queue that holds the tuples of the
+// current partition. The remainder is given to the spilling buffer of the
+// window function.
+const bufferedWindowQueueMemLimitFraction = 0.5
+
+// newBuffer returns a SpillingBuffer that stores the columns of the input with
+// the given indices for use by the window function.
+func (args *WindowArgs) newBuffer(colIdxs ...int) *colexecutils.SpillingBuffer {
+	return colexecutils.NewSpillingBuffer(
+		args.BufferAllocator,
+		args.MemoryLimit-int64(float64(args.MemoryLimit)*bufferedWindowQueueMemLimitFraction),
+		args.DiskQueueCfg, args.FdSemaphore, args.InputTypes, args.DiskAcc, colIdxs...,
+	)
+}
+
+// bufferedWindower is the interface that window functions which need to see
+// the whole partition before computing the output for any of its tuples must
+// implement in order to be used by bufferedWindowOp.
+type bufferedWindower interface {
+	Init()
+	Close(ctx context.Context) error
+
+	// startNewPartition prepares the window function for a new partition.
+	startNewPartition(ctx context.Context)
+	// bufferTuples is given the tuples with indices in range [startIdx,
+	// endIdx) of batch which belong to the current partition. The batch has
+	// no selection vector.
+	bufferTuples(ctx context.Context, batch coldata.Batch, startIdx, endIdx int)
+	// transitionToProcessing is called once all tuples of the current
+	// partition have been buffered.
+	transitionToProcessing(ctx context.Context)
+	// processBatch computes the output column for the tuples with indices in
+	// range [startIdx, endIdx) of batch. The tuples are passed to
+	// processBatch in the same order in which they were buffered, and batch
+	// has no selection vector.
+	processBatch(ctx context.Context, batch coldata.Batch, startIdx, endIdx int)
+}
+
+// bufferedWindowerBase extracts common fields of the bufferedWindower
+// implementations.
+type bufferedWindowerBase struct {
+	allocator    *colmem.Allocator
+	outputColIdx int
+	// partitionSize is the number of tuples in the current partition.
+	partitionSize int
+}
+
+func (b *bufferedWindowerBase) startNewPartition(context.Context) {
+	b.partitionSize = 0
+}
+
+func (b *bufferedWindowerBase) bufferTuples(_ context.Context, _ coldata.Batch, startIdx, endIdx int) {
+	b.partitionSize += endIdx - startIdx
+}
+
+func (b *bufferedWindowerBase) transitionToProcessing(context.Context) {}
+
+type bufferedWindowState int
+
+const (
+	// windowLoading is the state in which the operator reads the tuples of
+	// the current partition from the input and buffers them. Once the first
+	// tuple of the next partition (or a zero-length batch) is seen, the
+	// operator transitions to windowProcessing state.
+	windowLoading bufferedWindowState = iota
+	// windowProcessing is the state in which the operator dequeues the
+	// buffered tuples of the current partition, computes the output of the
+	// window function for them, and emits the result. Once the partition is
+	// fully processed, the operator transitions either to windowLoading or
+	// windowFinished state.
+	windowProcessing
+	// windowFinished is the state in which the operator closes any non-closed
+	// disk resources and emits the zero-length batch.
+	windowFinished
+)
+
+// bufferedWindowOp is an operator that buffers all tuples of each partition
+// before passing them to the window function to compute the output.
+type bufferedWindowOp struct {
+	colexecop.OneInputNode
+	colexecop.CloserHelper
+
+	windower bufferedWindower
+
+	state           bufferedWindowState
+	mainAllocator   *colmem.Allocator
+	inputTypes      []*types.T
+	outputColIdx    int
+	partitionColIdx int
+
+	// bufferQueue stores the tuples of the current partition.
+	bufferQueue *colexecutils.SpillingQueue
+	// partitionSize is the number of tuples in the current partition.
+	partitionSize int
+	// inputExhausted indicates whether the zero-length batch has been
+	// received from the input.
+	inputExhausted bool
+
+	// currentBatch is the input batch that is being loaded, and
+	// currentBatchIdx is the index of the first tuple of currentBatch that
+	// hasn't been buffered yet.
+	currentBatch    coldata.Batch
+	currentBatchIdx int
+
+	scratch coldata.Batch
+	output  coldata.Batch
+}
+
+var _ colexecop.ClosableOperator = &bufferedWindowOp{}
+
+func newBufferedWindowOperator(
+	args *WindowArgs, windower bufferedWindower, outputColType *types.T,
+) colexecop.Operator {
+	outputTypes := make([]*types.T, len(args.InputTypes), len(args.InputTypes)+1)
+	copy(outputTypes, args.InputTypes)
+	outputTypes = append(outputTypes, outputColType)
+	return &bufferedWindowOp{
+		OneInputNode:    colexecop.NewOneInputNode(args.Input),
+		windower:        windower,
+		mainAllocator:   args.MainAllocator,
+		inputTypes:      args.InputTypes,
+		outputColIdx:    args.OutputColIdx,
+		partitionColIdx: args.PartitionColIdx,
+		bufferQueue: colexecutils.NewSpillingQueue(
+			&colexecutils.NewSpillingQueueArgs{
+				UnlimitedAllocator: args.QueueAllocator,
+				Types:              args.InputTypes,
+				MemoryLimit:        int64(float64(args.MemoryLimit) * bufferedWindowQueueMemLimitFraction),
+				DiskQueueCfg:       args.DiskQueueCfg,
+				FDSemaphore:        args.FdSemaphore,
+				DiskAcc:            args.DiskAcc,
+			},
+		),
+		output: args.MainAllocator.NewMemBatchWithFixedCapacity(outputTypes, coldata.BatchSize()),
+	}
+}
+
+func (b *bufferedWindowOp) Init() {
+	b.Input.Init()
+	b.windower.Init()
+	b.state = windowLoading
+	b.scratch = b.mainAllocator.NewMemBatchWithFixedCapacity(b.inputTypes, coldata.BatchSize())
+}
+
+// findPartitionEnd returns the index of the first tuple starting from
+// b.currentBatchIdx that doesn't belong to the current partition (or the
+// length of the current batch if all remaining tuples belong to it).
+func (b *bufferedWindowOp) findPartitionEnd() int {
+	n := b.currentBatch.Length()
+	if b.partitionColIdx == tree.NoColumnIdx {
+		// All tuples belong to the same partition.
+		return n
+	}
+	partitionCol := b.currentBatch.ColVec(b.partitionColIdx).Bool()
+	sel := b.currentBatch.Selection()
+	i := b.currentBatchIdx
+	if b.partitionSize == 0 {
+		// The first tuple starts the current partition.
+		i++
+	}
+	if sel != nil {
+		for ; i < n; i++ {
+			if partitionCol[sel[i]] {
+				return i
+			}
+		}
+		return n
+	}
+	for ; i < n; i++ {
+		if partitionCol[i] {
+			return i
+		}
+	}
+	return n
+}
+
+func (b *bufferedWindowOp) Next(ctx context.Context) coldata.Batch {
+	var err error
+	for {
+		switch b.state {
+		case windowLoading:
+			if b.currentBatch == nil {
+				b.currentBatch = b.Input.Next(ctx)
+				b.currentBatchIdx = 0
+			}
+			if b.currentBatch.Length() == 0 {
+				b.inputExhausted = true
+				if b.partitionSize == 0 {
+					// The input was empty.
+					b.state = windowFinished
+					continue
+				}
+				b.state = windowProcessing
+				b.transitionToProcessing(ctx)
+				continue
+			}
+			endIdx := b.findPartitionEnd()
+			if endIdx > b.currentBatchIdx {
+				// Deselect the tuples of the current partition into the scratch
+				// batch and buffer them.
+				startIdx := b.currentBatchIdx
+				sel := b.currentBatch.Selection()
+				b.scratch.ResetInternalBatch()
+				b.mainAllocator.PerformOperation(b.scratch.ColVecs(), func() {
+					for colIdx, vec := range b.scratch.ColVecs() {
+						vec.Copy(
+							coldata.CopySliceArgs{
+								SliceArgs: coldata.SliceArgs{
+									Src:         b.currentBatch.ColVec(colIdx),
+									Sel:         sel,
+									SrcStartIdx: startIdx,
+									SrcEndIdx:   endIdx,
+								},
+							},
+						)
+					}
+					b.scratch.SetLength(endIdx - startIdx)
+				})
+				b.bufferQueue.Enqueue(ctx, b.scratch)
+				b.windower.bufferTuples(ctx, b.scratch, 0 /* startIdx */, b.scratch.Length())
+				b.partitionSize += endIdx - startIdx
+				b.currentBatchIdx = endIdx
+			}
+			if endIdx == b.currentBatch.Length() {
+				// All tuples of the current batch have been buffered, but the
+				// current partition might continue in the next batch.
+				b.currentBatch = nil
+				continue
+			}
+			// The next partition begins at endIdx of the current batch.
+			b.state = windowProcessing
+			b.transitionToProcessing(ctx)
+			continue
+
+		case windowProcessing:
+			var batch coldata.Batch
+			if batch, err = b.bufferQueue.Dequeue(ctx); err != nil {
+				colexecerror.InternalError(err)
+			}
+			n := batch.Length()
+			if n == 0 {
+				// The current partition has been fully processed.
+				b.bufferQueue.Reset(ctx)
+				b.windower.startNewPartition(ctx)
+				b.partitionSize = 0
+				if b.inputExhausted {
+					b.state = windowFinished
+				} else {
+					b.state = windowLoading
+				}
+				continue
+			}
+			b.output.ResetInternalBatch()
+			// First, we copy over the buffered up columns.
+			b.mainAllocator.PerformOperation(b.output.ColVecs()[:len(b.inputTypes)], func() {
+				for colIdx, vec := range b.output.ColVecs()[:len(b.inputTypes)] {
+					vec.Copy(
+						coldata.CopySliceArgs{
+							SliceArgs: coldata.SliceArgs{
+								Src:       batch.ColVec(colIdx),
+								SrcEndIdx: n,
+							},
+						},
+					)
+				}
+			})
+			// Now we compute the output column.
+			b.windower.processBatch(ctx, b.output, 0 /* startIdx */, n)
+			b.output.SetLength(n)
+			return b.output
+
+		case windowFinished:
+			if err = b.Close(ctx); err != nil {
+				colexecerror.InternalError(err)
+			}
+			return coldata.ZeroBatch
+
+		default:
+			colexecerror.InternalError(errors.AssertionFailedf("window operator in unhandled state"))
+			// This code is unreachable, but the compiler cannot infer that.
+			return nil
+		}
+	}
+}
+
+// transitionToProcessing finalizes the buffering of the current partition.
+func (b *bufferedWindowOp) transitionToProcessing(ctx context.Context) {
+	b.bufferQueue.Enqueue(ctx, coldata.ZeroBatch)
+	b.windower.transitionToProcessing(ctx)
+}
+
+func (b *bufferedWindowOp) Close(ctx context.Context) error {
+	if !b.CloserHelper.Close() {
+		return nil
+	}
+	var lastErr error
+	if err := b.bufferQueue.Close(ctx); err != nil {
+		lastErr = err
+	}
+	if err := b.windower.Close(ctx); err != nil {
+		lastErr = err
+	}
+	return lastErr
+}
@@ -21,7 +21,6 @@ func TestNoLinkForbidden(t *testing.T) {
 		"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecwindow", true,
 		[]string{
 			"github.com/cockroachdb/cockroach/pkg/sql/colexec",
-			"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecagg",
 			"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexechash",
 			"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecjoin",
 			"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecproj",
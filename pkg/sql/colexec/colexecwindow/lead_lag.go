@@ -0,0 +1,154 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexecwindow
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/errors"
+)
+
+// NewLeadLagOperator creates a new Operator that computes window function
+// LEAD or LAG (depending on the passed in windowFn). argIdxs specifies the
+// columns of the value, the offset and the default value arguments (the last
+// two are optional).
+func NewLeadLagOperator(
+	args *WindowArgs, windowFn execinfrapb.WindowerSpec_WindowFunc, argIdxs []int,
+) (colexecop.Operator, error) {
+	if len(argIdxs) < 1 || len(argIdxs) > 3 {
+		return nil, errors.AssertionFailedf("unexpected number of arguments %d for %s", len(argIdxs), windowFn)
+	}
+	if windowFn != execinfrapb.WindowerSpec_LEAD && windowFn != execinfrapb.WindowerSpec_LAG {
+		return nil, errors.AssertionFailedf("unsupported lead/lag window function %s", windowFn)
+	}
+	w := &leadLagWindower{
+		bufferedWindowerBase: bufferedWindowerBase{
+			allocator:    args.MainAllocator,
+			outputColIdx: args.OutputColIdx,
+		},
+		forward:    windowFn == execinfrapb.WindowerSpec_LEAD,
+		offsetIdx:  -1,
+		defaultIdx: -1,
+	}
+	if len(argIdxs) > 1 {
+		w.offsetIdx = argIdxs[1]
+	}
+	if len(argIdxs) > 2 {
+		w.defaultIdx = argIdxs[2]
+	}
+	w.buffer = args.newBuffer(argIdxs[0])
+	return newBufferedWindowOperator(args, w, args.InputTypes[argIdxs[0]]), nil
+}
+
+// leadLagWindower returns the value of the tuple that is at the given offset
+// after (LEAD) or before (LAG) the current tuple within the partition.
+type leadLagWindower struct {
+	bufferedWindowerBase
+
+	// buffer stores the value argument of the tuples in the current
+	// partition.
+	buffer  *colexecutils.SpillingBuffer
+	forward bool
+	// offsetIdx and defaultIdx are the indices of the offset and the default
+	// value arguments, or -1 if those arguments are omitted.
+	offsetIdx, defaultIdx int
+	// currentRow is the index of the next tuple to be processed within the
+	// partition.
+	currentRow int
+}
+
+var _ bufferedWindower = &leadLagWindower{}
+
+func (w *leadLagWindower) Init() {}
+
+func (w *leadLagWindower) startNewPartition(ctx context.Context) {
+	w.bufferedWindowerBase.startNewPartition(ctx)
+	w.buffer.Reset(ctx)
+	w.currentRow = 0
+}
+
+func (w *leadLagWindower) bufferTuples(
+	ctx context.Context, batch coldata.Batch, startIdx, endIdx int,
+) {
+	w.bufferedWindowerBase.bufferTuples(ctx, batch, startIdx, endIdx)
+	w.buffer.AppendTuples(ctx, batch, startIdx, endIdx)
+}
+
+func (w *leadLagWindower) processBatch(
+	ctx context.Context, batch coldata.Batch, startIdx, endIdx int,
+) {
+	outputVec := batch.ColVec(w.outputColIdx)
+	w.allocator.PerformOperation([]coldata.Vec{outputVec}, func() {
+		for i := startIdx; i < endIdx; i++ {
+			offset := 1
+			if w.offsetIdx != -1 {
+				offsetVec := batch.ColVec(w.offsetIdx)
+				if offsetVec.Nulls().NullAt(i) {
+					outputVec.Nulls().SetNull(i)
+					w.currentRow++
+					continue
+				}
+				offset = int(getIntValue(offsetVec, i))
+			}
+			if !w.forward {
+				offset *= -1
+			}
+			if targetRow := w.currentRow + offset; targetRow < 0 || targetRow >= w.partitionSize {
+				// Target row is out of the partition; supply default value if
+				// provided, otherwise return NULL.
+				if w.defaultIdx != -1 {
+					copyValue(batch.ColVec(w.defaultIdx), i, outputVec, i)
+				} else {
+					outputVec.Nulls().SetNull(i)
+				}
+			} else {
+				vec, vecIdx, _ := w.buffer.GetVecWithTuple(ctx, 0 /* colIdx */, targetRow)
+				copyValue(vec, vecIdx, outputVec, i)
+			}
+			w.currentRow++
+		}
+	})
+}
+
+func (w *leadLagWindower) Close(ctx context.Context) error {
+	return w.buffer.Close(ctx)
+}
+
+// copyValue copies the value at index srcIdx of src into index destIdx of
+// dest.
+func copyValue(src coldata.Vec, srcIdx int, dest coldata.Vec, destIdx int) {
+	dest.Copy(
+		coldata.CopySliceArgs{
+			SliceArgs: coldata.SliceArgs{
+				Src:         src,
+				DestIdx:     destIdx,
+				SrcStartIdx: srcIdx,
+				SrcEndIdx:   srcIdx + 1,
+			},
+		},
+	)
+}
+
+// getIntValue returns the value at index idx of the given integer vector.
+func getIntValue(vec coldata.Vec, idx int) int64 {
+	switch vec.Type().Width() {
+	case 16:
+		return int64(vec.Int16()[idx])
+	case 32:
+		return int64(vec.Int32()[idx])
+	default:
+		return vec.Int64()[idx]
+	}
+}
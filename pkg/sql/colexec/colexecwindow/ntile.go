@@ -0,0 +1,121 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexecwindow
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+var errInvalidArgumentForNtile = pgerror.Newf(
+	pgcode.InvalidParameterValue, "argument of ntile() must be greater than zero")
+
+// NewNTileOperator creates a new Operator that computes window function NTILE.
+// argIdx specifies the column of the number of buckets argument.
+func NewNTileOperator(args *WindowArgs, argIdx int) colexecop.Operator {
+	w := &ntileWindower{
+		bufferedWindowerBase: bufferedWindowerBase{
+			allocator:    args.MainAllocator,
+			outputColIdx: args.OutputColIdx,
+		},
+		argIdx: argIdx,
+	}
+	return newBufferedWindowOperator(args, w, types.Int)
+}
+
+// ntileWindower divides the partition into the given number of buckets as
+// evenly as possible and returns the bucket number of each tuple. Only the
+// size of the partition is needed, so the tuples themselves are not buffered.
+type ntileWindower struct {
+	bufferedWindowerBase
+
+	argIdx int
+	// initialized indicates whether the number of buckets has been determined
+	// for the current partition. It is taken from the argument of the first
+	// tuple in the partition that has a non-NULL argument (all tuples before
+	// that one get NULL as the result).
+	initialized    bool
+	ntile          int64
+	curBucketCount int
+	boundary       int
+	remainder      int
+}
+
+var _ bufferedWindower = &ntileWindower{}
+
+func (w *ntileWindower) Init() {}
+
+func (w *ntileWindower) startNewPartition(ctx context.Context) {
+	w.bufferedWindowerBase.startNewPartition(ctx)
+	w.initialized = false
+}
+
+func (w *ntileWindower) processBatch(
+	_ context.Context, batch coldata.Batch, startIdx, endIdx int,
+) {
+	outputVec := batch.ColVec(w.outputColIdx)
+	outputNulls := outputVec.Nulls()
+	outputCol := outputVec.Int64()
+	argVec := batch.ColVec(w.argIdx)
+	argNulls := argVec.Nulls()
+	for i := startIdx; i < endIdx; i++ {
+		if !w.initialized {
+			if argNulls.NullAt(i) {
+				// Per spec: if argument is the null value, then the result is
+				// the null value.
+				outputNulls.SetNull(i)
+				continue
+			}
+			nbuckets := int(getIntValue(argVec, i))
+			if nbuckets <= 0 {
+				// Per spec: if argument is less than or equal to 0, then an
+				// error is returned.
+				colexecerror.ExpectedError(errInvalidArgumentForNtile)
+			}
+			w.initialized = true
+			w.ntile = 1
+			w.curBucketCount = 0
+			w.remainder = 0
+			w.boundary = w.partitionSize / nbuckets
+			if w.boundary <= 0 {
+				w.boundary = 1
+			} else {
+				// If the total number is not divisible, add 1 row to leading
+				// buckets.
+				w.remainder = w.partitionSize % nbuckets
+				if w.remainder != 0 {
+					w.boundary++
+				}
+			}
+		}
+		w.curBucketCount++
+		if w.boundary < w.curBucketCount {
+			// Move to next ntile bucket.
+			if w.remainder != 0 && int(w.ntile) == w.remainder {
+				w.remainder = 0
+				w.boundary--
+			}
+			w.ntile++
+			w.curBucketCount = 1
+		}
+		outputCol[i] = w.ntile
+	}
+}
+
+func (w *ntileWindower) Close(context.Context) error {
+	return nil
+}
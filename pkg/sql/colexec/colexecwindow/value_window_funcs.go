@@ -0,0 +1,151 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexecwindow
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+var errInvalidArgumentForNthValue = pgerror.Newf(
+	pgcode.InvalidParameterValue, "argument of nth_value() must be greater than zero")
+
+// NewValueWindowOperator creates a new Operator that computes window function
+// FIRST_VALUE, LAST_VALUE, or NTH_VALUE (depending on the passed in windowFn)
+// over the window frame of each tuple. argIdxs specifies the columns of the
+// value argument and, for NTH_VALUE, of the n argument.
+func NewValueWindowOperator(
+	args *WindowArgs,
+	windowFn execinfrapb.WindowerSpec_WindowFunc,
+	frame *execinfrapb.WindowerSpec_Frame,
+	ordering *execinfrapb.Ordering,
+	argIdxs []int,
+) (colexecop.Operator, error) {
+	w := &valueWindower{
+		bufferedWindowerBase: bufferedWindowerBase{
+			allocator:    args.MainAllocator,
+			outputColIdx: args.OutputColIdx,
+		},
+		windowFn: windowFn,
+	}
+	switch windowFn {
+	case execinfrapb.WindowerSpec_FIRST_VALUE, execinfrapb.WindowerSpec_LAST_VALUE:
+		if len(argIdxs) != 1 {
+			return nil, errors.AssertionFailedf("unexpected number of arguments %d for %s", len(argIdxs), windowFn)
+		}
+	case execinfrapb.WindowerSpec_NTH_VALUE:
+		if len(argIdxs) != 2 {
+			return nil, errors.AssertionFailedf("unexpected number of arguments %d for %s", len(argIdxs), windowFn)
+		}
+		w.nthArgIdx = argIdxs[1]
+	default:
+		return nil, errors.AssertionFailedf("unsupported value window function %s", windowFn)
+	}
+	// The value argument is always the first stored column.
+	colsToStore := []int{argIdxs[0]}
+	var err error
+	w.framer, colsToStore, err = newWindowFramer(
+		args.EvalCtx, frame, ordering, args.InputTypes, args.PeersColIdx, colsToStore,
+	)
+	if err != nil {
+		return nil, err
+	}
+	w.buffer = args.newBuffer(colsToStore...)
+	w.framer.buffer = w.buffer
+	return newBufferedWindowOperator(args, w, args.InputTypes[argIdxs[0]]), nil
+}
+
+// valueWindower returns the value of a particular tuple from the window frame
+// of the current tuple: the first (FIRST_VALUE), the last (LAST_VALUE), or the
+// nth (NTH_VALUE) one. If there is no such tuple, NULL is returned.
+type valueWindower struct {
+	bufferedWindowerBase
+
+	windowFn execinfrapb.WindowerSpec_WindowFunc
+	// nthArgIdx is the index of the n argument of NTH_VALUE.
+	nthArgIdx int
+	// buffer stores the value argument (as the first stored column) along
+	// with the columns needed by the framer.
+	buffer *colexecutils.SpillingBuffer
+	framer *windowFramer
+}
+
+var _ bufferedWindower = &valueWindower{}
+
+func (w *valueWindower) Init() {}
+
+func (w *valueWindower) startNewPartition(ctx context.Context) {
+	w.bufferedWindowerBase.startNewPartition(ctx)
+	w.buffer.Reset(ctx)
+}
+
+func (w *valueWindower) bufferTuples(
+	ctx context.Context, batch coldata.Batch, startIdx, endIdx int,
+) {
+	w.bufferedWindowerBase.bufferTuples(ctx, batch, startIdx, endIdx)
+	w.buffer.AppendTuples(ctx, batch, startIdx, endIdx)
+}
+
+func (w *valueWindower) transitionToProcessing(context.Context) {
+	w.framer.startPartition(w.partitionSize)
+}
+
+func (w *valueWindower) processBatch(
+	ctx context.Context, batch coldata.Batch, startIdx, endIdx int,
+) {
+	outputVec := batch.ColVec(w.outputColIdx)
+	w.allocator.PerformOperation([]coldata.Vec{outputVec}, func() {
+		for i := startIdx; i < endIdx; i++ {
+			var idx int
+			switch w.windowFn {
+			case execinfrapb.WindowerSpec_FIRST_VALUE:
+				w.framer.next(ctx)
+				idx = w.framer.frameFirstIdx()
+			case execinfrapb.WindowerSpec_LAST_VALUE:
+				w.framer.next(ctx)
+				idx = w.framer.frameLastIdx()
+			default:
+				nthVec := batch.ColVec(w.nthArgIdx)
+				if nthVec.Nulls().NullAt(i) {
+					// The framer still needs to advance to the next tuple.
+					w.framer.next(ctx)
+					outputVec.Nulls().SetNull(i)
+					continue
+				}
+				nth := int(getIntValue(nthVec, i))
+				if nth <= 0 {
+					colexecerror.ExpectedError(errInvalidArgumentForNthValue)
+				}
+				w.framer.next(ctx)
+				// We subtract 1 because nth is counting from 1.
+				idx = w.framer.frameNthIdx(nth - 1)
+			}
+			if idx == -1 {
+				outputVec.Nulls().SetNull(i)
+				continue
+			}
+			vec, vecIdx, _ := w.buffer.GetVecWithTuple(ctx, 0 /* colIdx */, idx)
+			copyValue(vec, vecIdx, outputVec, i)
+		}
+	})
+}
+
+func (w *valueWindower) Close(ctx context.Context) error {
+	return w.buffer.Close(ctx)
+}
@@ -0,0 +1,291 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexecwindow
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecagg"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// NewWindowAggregatorOperator creates a new Operator that computes the given
+// aggregate function over the window frame of each tuple. argIdxs specifies
+// the columns of the arguments of the aggregate function. aggAlloc must have
+// been created for a single hash aggregate function whose arguments are the
+// columns [0, len(argIdxs)) of a batch that contains only those arguments,
+// and inputArgsConverter and closers must be the ones returned alongside it.
+func NewWindowAggregatorOperator(
+	args *WindowArgs,
+	aggType execinfrapb.AggregatorSpec_Func,
+	frame *execinfrapb.WindowerSpec_Frame,
+	ordering *execinfrapb.Ordering,
+	argIdxs []int,
+	outputType *types.T,
+	aggAlloc *colexecagg.AggregateFuncsAlloc,
+	inputArgsConverter *colconv.VecToDatumConverter,
+	closers colexecop.Closers,
+) (colexecop.Operator, error) {
+	argTypes := make([]*types.T, len(argIdxs))
+	inputIdxs := make([]uint32, len(argIdxs))
+	for i, argIdx := range argIdxs {
+		argTypes[i] = args.InputTypes[argIdx]
+		inputIdxs[i] = uint32(i)
+	}
+	w := &windowAggregator{
+		bufferedWindowerBase: bufferedWindowerBase{
+			allocator:    args.MainAllocator,
+			outputColIdx: args.OutputColIdx,
+		},
+		agg:                aggAlloc.MakeAggregateFuncs()[0],
+		inputArgsConverter: inputArgsConverter,
+		closers:            closers,
+		inputIdxs:          inputIdxs,
+		vecs:               make([]coldata.Vec, len(argIdxs)),
+		canAccumulate:      aggregateFuncCanAccumulate(aggType, argTypes),
+		outputType:         outputType,
+	}
+	if colexecagg.IsAggOptimized(aggType) {
+		// Only the non-optimized aggregate functions convert their arguments
+		// to datums.
+		w.inputArgsConverter = nil
+	}
+	// The arguments of the aggregate function are always the first stored
+	// columns (we don't deduplicate them since the aggregate function
+	// references them by their position).
+	colsToStore := append([]int(nil), argIdxs...)
+	var err error
+	w.framer, colsToStore, err = newWindowFramer(
+		args.EvalCtx, frame, ordering, args.InputTypes, args.PeersColIdx, colsToStore,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(colsToStore) > 0 {
+		w.buffer = args.newBuffer(colsToStore...)
+		w.framer.buffer = w.buffer
+	}
+	return newBufferedWindowOperator(args, w, outputType), nil
+}
+
+// aggregateFuncCanAccumulate returns whether the aggregate function can keep
+// on accumulating more values after the result has been flushed. This is not
+// the case for the functions whose Flush releases the current aggregation
+// state as well as for json_object_agg and jsonb_object_agg (which don't
+// support adding values after the result has been retrieved).
+func aggregateFuncCanAccumulate(aggType execinfrapb.AggregatorSpec_Func, argTypes []*types.T) bool {
+	switch aggType {
+	case execinfrapb.AnyNotNull, execinfrapb.Min, execinfrapb.Max:
+		switch typeconv.TypeFamilyToCanonicalTypeFamily(argTypes[0].Family()) {
+		case types.BytesFamily, typeconv.DatumVecCanonicalTypeFamily:
+			return false
+		}
+		return true
+	case execinfrapb.ConcatAgg, execinfrapb.JSONObjectAgg, execinfrapb.JSONBObjectAgg:
+		return false
+	default:
+		return true
+	}
+}
+
+// windowAggregator computes an aggregate function over the window frame of
+// each tuple. The aggregation is performed anew for each tuple unless either
+// the frame is the same as the one of the previous tuple (in which case the
+// previous result is reused) or the frame is an extension of the previous one
+// (in which case only the new tuples are added to the aggregation, if the
+// aggregate function allows for it).
+type windowAggregator struct {
+	bufferedWindowerBase
+
+	// buffer stores the arguments of the aggregate function (as the first
+	// stored columns) along with the columns needed by the framer. It is nil
+	// if no columns need to be stored.
+	buffer *colexecutils.SpillingBuffer
+	framer *windowFramer
+
+	agg                colexecagg.AggregateFunc
+	inputArgsConverter *colconv.VecToDatumConverter
+	closers            colexecop.Closers
+	canAccumulate      bool
+	outputType         *types.T
+
+	// aggState describes the tuples currently accumulated by agg, and
+	// aggStateValid indicates whether agg has accumulated a single interval
+	// of tuples (which can be extended).
+	aggState      windowInterval
+	aggStateValid bool
+	// prevIntervals is the frame of the previous tuple, and result contains
+	// the result of the aggregation over that frame (if resultValid is true).
+	prevIntervals []windowInterval
+	result        coldata.Batch
+	resultValid   bool
+
+	inputIdxs []uint32
+	vecs      []coldata.Vec
+	sel       []int
+}
+
+var _ bufferedWindower = &windowAggregator{}
+
+func (w *windowAggregator) Init() {
+	w.result = w.allocator.NewMemBatchWithFixedCapacity([]*types.T{w.outputType}, 1 /* capacity */)
+	w.agg.SetOutput(w.result.ColVec(0))
+	w.sel = make([]int, coldata.BatchSize())
+	w.allocator.AdjustMemoryUsage(int64(colmem.SizeOfBatchSizeSelVector))
+}
+
+func (w *windowAggregator) startNewPartition(ctx context.Context) {
+	w.bufferedWindowerBase.startNewPartition(ctx)
+	if w.buffer != nil {
+		w.buffer.Reset(ctx)
+	}
+	w.resultValid = false
+	w.aggStateValid = false
+}
+
+func (w *windowAggregator) bufferTuples(
+	ctx context.Context, batch coldata.Batch, startIdx, endIdx int,
+) {
+	w.bufferedWindowerBase.bufferTuples(ctx, batch, startIdx, endIdx)
+	if w.buffer != nil {
+		w.buffer.AppendTuples(ctx, batch, startIdx, endIdx)
+	}
+}
+
+func (w *windowAggregator) transitionToProcessing(context.Context) {
+	w.framer.startPartition(w.partitionSize)
+}
+
+func (w *windowAggregator) processBatch(
+	ctx context.Context, batch coldata.Batch, startIdx, endIdx int,
+) {
+	outputVec := batch.ColVec(w.outputColIdx)
+	w.allocator.PerformOperation([]coldata.Vec{outputVec}, func() {
+		for i := startIdx; i < endIdx; i++ {
+			w.framer.next(ctx)
+			intervals := w.framer.frameIntervals()
+			if !w.resultValid || !intervalsEqual(intervals, w.prevIntervals) {
+				w.computeResult(ctx, intervals)
+			}
+			copyValue(w.result.ColVec(0), 0 /* srcIdx */, outputVec, i)
+		}
+	})
+}
+
+// computeResult performs the aggregation over the given frame and stores the
+// result in w.result.
+func (w *windowAggregator) computeResult(ctx context.Context, intervals []windowInterval) {
+	if w.canAccumulate && w.aggStateValid && len(intervals) == 1 &&
+		intervals[0].start == w.aggState.start && intervals[0].end >= w.aggState.end {
+		// The new frame extends the tuples that have already been aggregated,
+		// so we only need to add the new ones.
+		w.aggregate(ctx, w.aggState.end, intervals[0].end)
+		w.aggState.end = intervals[0].end
+	} else {
+		w.agg.Reset()
+		for _, interval := range intervals {
+			w.aggregate(ctx, interval.start, interval.end)
+		}
+		w.aggStateValid = len(intervals) == 1
+		if w.aggStateValid {
+			w.aggState = intervals[0]
+		}
+	}
+	w.result.ResetInternalBatch()
+	w.allocator.PerformOperation(w.result.ColVecs(), func() {
+		w.agg.Flush(0 /* outputIdx */)
+	})
+	if !w.canAccumulate {
+		// The aggregation state might have been released by Flush.
+		w.aggStateValid = false
+	}
+	w.prevIntervals = append(w.prevIntervals[:0], intervals...)
+	w.resultValid = true
+}
+
+// aggregate adds the tuples in range [startIdx, endIdx) of the partition to
+// the aggregation.
+func (w *windowAggregator) aggregate(ctx context.Context, startIdx, endIdx int) {
+	for startIdx < endIdx {
+		toProcess := endIdx - startIdx
+		if toProcess > len(w.sel) {
+			toProcess = len(w.sel)
+		}
+		if len(w.vecs) == 0 {
+			// The aggregate function doesn't take any arguments (this is the
+			// case for COUNT_ROWS), so only the number of tuples matters.
+			for i := range w.sel[:toProcess] {
+				w.sel[i] = i
+			}
+			w.agg.Compute(w.vecs, w.inputIdxs, toProcess, w.sel[:toProcess])
+			startIdx += toProcess
+			continue
+		}
+		var vecIdx, n int
+		for j := range w.vecs {
+			w.vecs[j], vecIdx, n = w.buffer.GetVecWithTuple(ctx, j, startIdx)
+		}
+		if n-vecIdx < toProcess {
+			toProcess = n - vecIdx
+		}
+		if w.inputArgsConverter != nil {
+			// The arguments are converted to datums "sparsely" (up to the
+			// largest index in the selection vector), so we use windows into
+			// the vectors in order to not convert the tuples that precede the
+			// ones we're interested in.
+			for j := range w.vecs {
+				w.vecs[j] = w.vecs[j].Window(vecIdx, vecIdx+toProcess)
+			}
+			vecIdx = 0
+		}
+		for i := range w.sel[:toProcess] {
+			w.sel[i] = vecIdx + i
+		}
+		sel := w.sel[:toProcess]
+		if w.inputArgsConverter != nil {
+			w.inputArgsConverter.ConvertVecs(w.vecs, toProcess, sel)
+		}
+		w.agg.Compute(w.vecs, w.inputIdxs, toProcess, sel)
+		startIdx += toProcess
+	}
+}
+
+func intervalsEqual(a, b []windowInterval) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *windowAggregator) Close(ctx context.Context) error {
+	var lastErr error
+	if w.buffer != nil {
+		if err := w.buffer.Close(ctx); err != nil {
+			lastErr = err
+		}
+	}
+	if err := w.closers.Close(ctx); err != nil {
+		lastErr = err
+	}
+	return lastErr
+}
@@ -0,0 +1,513 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexecwindow
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// windowInterval represents a range of tuples [start, end) within the
+// current partition.
+type windowInterval struct {
+	start, end int
+}
+
+// windowFramer computes the window frame of each tuple in the partition
+// according to the frame specification of the window function. The frame is
+// represented by a list of intervals which is a result of removing the tuples
+// excluded by the EXCLUDE clause from the range [start, end) of tuples.
+//
+// windowFramer is expected to be used in the following manner:
+// - startPartition is called once all tuples of the partition have been
+//   buffered,
+// - next is called once for each tuple of the partition in order,
+// - after each call to next, the frame of the current tuple is accessed via
+//   frameIntervals, frameFirstIdx, frameLastIdx, or frameNthIdx methods.
+//
+// The bounds of the frame change monotonically as the current tuple advances
+// throughout the partition, so the framer keeps track of them incrementally
+// which allows it to compute the frames in linear time overall.
+type windowFramer struct {
+	evalCtx *tree.EvalContext
+	buffer  *colexecutils.SpillingBuffer
+
+	mode        execinfrapb.WindowerSpec_Frame_Mode
+	startBound  execinfrapb.WindowerSpec_Frame_BoundType
+	endBound    execinfrapb.WindowerSpec_Frame_BoundType
+	exclusion   execinfrapb.WindowerSpec_Frame_Exclusion
+	startOffset uint64
+	endOffset   uint64
+
+	// peersColOrd is the ordinal of the peers column among the columns stored
+	// in the buffer, or -1 if the framer doesn't need the peers information.
+	peersColOrd int
+
+	// The following fields are only used when the frame is in RANGE mode and
+	// has an offset bound.
+	//
+	// ordColOrd is the ordinal of the ordering column among the columns
+	// stored in the buffer, and ordColAsc indicates whether the ordering is
+	// ascending.
+	ordColOrd int
+	ordColAsc bool
+	ordColTyp *types.T
+	// startDatumOffset and endDatumOffset are the decoded offsets of the
+	// bounds. startBinOp and endBinOp are the binary operations that compute
+	// the values at the bounds based on the value of the current tuple.
+	startDatumOffset, endDatumOffset tree.Datum
+	startBinOp, endBinOp             *tree.BinOp
+	datumAlloc                       rowenc.DatumAlloc
+	converted                        []tree.Datum
+
+	partitionSize int
+	// currentRow is the index of the current tuple within the partition.
+	currentRow int
+	// currentGroup is the index of the peer group of the current tuple, and
+	// [peerGroupStart, peerGroupEnd) is the range of tuples in that peer
+	// group.
+	currentGroup   int
+	peerGroupStart int
+	peerGroupEnd   int
+	// startGroup and endGroup track the peer groups at the bounds of the frame
+	// in GROUPS mode with offsets.
+	startGroup, endGroup groupPointer
+
+	// frameStart and frameEnd define the range [frameStart, frameEnd) of the
+	// tuples of the current frame before applying the exclusion.
+	frameStart, frameEnd int
+	intervals            []windowInterval
+}
+
+// groupPointer refers to the peer group with ordinal group which starts at
+// the tuple with index idx.
+type groupPointer struct {
+	group int
+	idx   int
+}
+
+// newWindowFramer returns a windowFramer for the given frame (nil frame means
+// the default frame). It adds the columns it needs to colsToStore and returns
+// the updated slice; the buffer must be set once it is created using the
+// updated slice.
+func newWindowFramer(
+	evalCtx *tree.EvalContext,
+	frame *execinfrapb.WindowerSpec_Frame,
+	ordering *execinfrapb.Ordering,
+	inputTypes []*types.T,
+	peersColIdx int,
+	colsToStore []int,
+) (*windowFramer, []int, error) {
+	f := &windowFramer{
+		evalCtx:     evalCtx,
+		mode:        execinfrapb.WindowerSpec_Frame_RANGE,
+		startBound:  execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING,
+		endBound:    execinfrapb.WindowerSpec_Frame_CURRENT_ROW,
+		peersColOrd: -1,
+		ordColOrd:   -1,
+	}
+	if frame != nil {
+		f.mode = frame.Mode
+		f.startBound = frame.Bounds.Start.BoundType
+		f.startOffset = frame.Bounds.Start.IntOffset
+		if frame.Bounds.End != nil {
+			f.endBound = frame.Bounds.End.BoundType
+			f.endOffset = frame.Bounds.End.IntOffset
+		}
+		f.exclusion = frame.Exclusion
+	}
+	if frameNeedsPeersInfo(frame) {
+		colsToStore, f.peersColOrd = addColToStore(colsToStore, peersColIdx)
+	}
+	if f.mode == execinfrapb.WindowerSpec_Frame_RANGE && (isOffsetBound(f.startBound) || isOffsetBound(f.endBound)) {
+		if len(ordering.Columns) != 1 {
+			return nil, nil, errors.AssertionFailedf(
+				"RANGE mode with offset requires exactly one ordering column, %d found", len(ordering.Columns))
+		}
+		ordCol := ordering.Columns[0]
+		colsToStore, f.ordColOrd = addColToStore(colsToStore, int(ordCol.ColIdx))
+		f.ordColAsc = ordCol.Direction == execinfrapb.Ordering_Column_ASC
+		f.ordColTyp = inputTypes[ordCol.ColIdx]
+		f.converted = make([]tree.Datum, 1)
+		var err error
+		if isOffsetBound(f.startBound) {
+			f.startDatumOffset, f.startBinOp, err = f.decodeRangeOffset(&frame.Bounds.Start)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		if isOffsetBound(f.endBound) {
+			f.endDatumOffset, f.endBinOp, err = f.decodeRangeOffset(frame.Bounds.End)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return f, colsToStore, nil
+}
+
+// decodeRangeOffset decodes the offset of the given bound in RANGE mode and
+// returns the binary operation that needs to be applied to the value of the
+// current tuple and the offset in order to get the value at the bound.
+func (f *windowFramer) decodeRangeOffset(
+	bound *execinfrapb.WindowerSpec_Frame_Bound,
+) (tree.Datum, *tree.BinOp, error) {
+	offset, rem, err := rowenc.DecodeTableValue(&f.datumAlloc, bound.OffsetType.Type, bound.TypedOffset)
+	if err != nil {
+		return nil, nil, errors.NewAssertionErrorWithWrappedErrf(err,
+			"error decoding %d bytes", errors.Safe(len(bound.TypedOffset)))
+	}
+	if len(rem) != 0 {
+		return nil, nil, errors.AssertionFailedf(
+			"%d trailing bytes in encoded value", errors.Safe(len(rem)))
+	}
+	// Type of offset depends on the ordering column's type.
+	offsetTyp := f.ordColTyp
+	if types.IsDateTimeType(f.ordColTyp) {
+		// For datetime related ordering columns, offset must be an Interval.
+		offsetTyp = types.Interval
+	}
+	plusOp, minusOp, found := tree.WindowFrameRangeOps{}.LookupImpl(f.ordColTyp, offsetTyp)
+	if !found {
+		return nil, nil, pgerror.Newf(pgcode.Windowing,
+			"given logical offset cannot be combined with ordering column")
+	}
+	// When the ordering is descending, "preceding" means larger values, so the
+	// direction of the operation is flipped.
+	plus := bound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING
+	if !f.ordColAsc {
+		plus = !plus
+	}
+	if plus {
+		return offset, plusOp, nil
+	}
+	return offset, minusOp, nil
+}
+
+// addColToStore adds colIdx to colsToStore if it isn't present there already
+// and returns the updated slice as well as the ordinal of colIdx in it.
+func addColToStore(colsToStore []int, colIdx int) (_ []int, ord int) {
+	for i := range colsToStore {
+		if colsToStore[i] == colIdx {
+			return colsToStore, i
+		}
+	}
+	return append(colsToStore, colIdx), len(colsToStore)
+}
+
+func isOffsetBound(bound execinfrapb.WindowerSpec_Frame_BoundType) bool {
+	return bound == execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING ||
+		bound == execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING
+}
+
+// frameNeedsPeersInfo returns whether computing the given window frame (nil
+// frame means the default frame) requires the peers information.
+func frameNeedsPeersInfo(frame *execinfrapb.WindowerSpec_Frame) bool {
+	if frame == nil {
+		// The default frame is in RANGE mode.
+		return true
+	}
+	return frame.Mode != execinfrapb.WindowerSpec_Frame_ROWS ||
+		frame.Exclusion == execinfrapb.WindowerSpec_Frame_EXCLUDE_GROUP ||
+		frame.Exclusion == execinfrapb.WindowerSpec_Frame_EXCLUDE_TIES
+}
+
+// startPartition prepares the framer for a new partition of the given size
+// that has been fully buffered.
+func (f *windowFramer) startPartition(partitionSize int) {
+	f.partitionSize = partitionSize
+	f.currentRow = -1
+	f.currentGroup = -1
+	f.peerGroupStart, f.peerGroupEnd = 0, 0
+	f.startGroup = groupPointer{}
+	f.endGroup = groupPointer{}
+	f.frameStart, f.frameEnd = 0, 0
+}
+
+// next advances the framer to the next tuple in the partition and computes
+// its window frame.
+func (f *windowFramer) next(ctx context.Context) {
+	f.currentRow++
+	if f.peersColOrd != -1 && f.currentRow >= f.peerGroupEnd {
+		// The current tuple begins a new peer group.
+		f.currentGroup++
+		f.peerGroupStart = f.currentRow
+		f.peerGroupEnd = f.nextPeerGroupStart(ctx, f.currentRow)
+	}
+	f.frameStart = f.computeStart(ctx)
+	f.frameEnd = f.computeEnd(ctx)
+	f.computeIntervals()
+}
+
+// nextPeerGroupStart returns the index of the first tuple after idx that
+// begins a new peer group (or the partition size if there is no such tuple).
+func (f *windowFramer) nextPeerGroupStart(ctx context.Context, idx int) int {
+	idx++
+	for idx < f.partitionSize {
+		vec, vecIdx, n := f.buffer.GetVecWithTuple(ctx, f.peersColOrd, idx)
+		peersCol := vec.Bool()
+		for ; vecIdx < n; vecIdx++ {
+			if peersCol[vecIdx] {
+				return idx
+			}
+			idx++
+		}
+	}
+	return f.partitionSize
+}
+
+// advanceToGroup moves p forward to the peer group with the given ordinal (or
+// to the end of the partition if there are fewer groups).
+func (f *windowFramer) advanceToGroup(ctx context.Context, p *groupPointer, group int) {
+	for p.group < group && p.idx < f.partitionSize {
+		p.idx = f.nextPeerGroupStart(ctx, p.idx)
+		p.group++
+	}
+}
+
+// clampedOffset returns the given offset limited by the partition size since
+// all larger offsets have the same effect.
+func (f *windowFramer) clampedOffset(offset uint64) int {
+	if offset > uint64(f.partitionSize) {
+		return f.partitionSize
+	}
+	return int(offset)
+}
+
+func (f *windowFramer) computeStart(ctx context.Context) int {
+	switch f.startBound {
+	case execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING:
+		return 0
+	case execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING:
+		switch f.mode {
+		case execinfrapb.WindowerSpec_Frame_ROWS:
+			return max(f.currentRow-f.clampedOffset(f.startOffset), 0)
+		case execinfrapb.WindowerSpec_Frame_GROUPS:
+			f.advanceToGroup(ctx, &f.startGroup, max(f.currentGroup-f.clampedOffset(f.startOffset), 0))
+			return f.startGroup.idx
+		default:
+			return f.searchRange(ctx, f.frameStart, f.startDatumOffset, f.startBinOp, true /* isStart */)
+		}
+	case execinfrapb.WindowerSpec_Frame_CURRENT_ROW:
+		if f.mode == execinfrapb.WindowerSpec_Frame_ROWS {
+			return f.currentRow
+		}
+		return f.peerGroupStart
+	case execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING:
+		switch f.mode {
+		case execinfrapb.WindowerSpec_Frame_ROWS:
+			return min(f.currentRow+f.clampedOffset(f.startOffset), f.partitionSize)
+		case execinfrapb.WindowerSpec_Frame_GROUPS:
+			f.advanceToGroup(ctx, &f.startGroup, f.currentGroup+f.clampedOffset(f.startOffset))
+			return f.startGroup.idx
+		default:
+			return f.searchRange(ctx, f.frameStart, f.startDatumOffset, f.startBinOp, true /* isStart */)
+		}
+	default:
+		colexecerror.InternalError(errors.AssertionFailedf("unexpected start bound %s", f.startBound))
+		// This code is unreachable, but the compiler cannot infer that.
+		return 0
+	}
+}
+
+func (f *windowFramer) computeEnd(ctx context.Context) int {
+	switch f.endBound {
+	case execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING:
+		switch f.mode {
+		case execinfrapb.WindowerSpec_Frame_ROWS:
+			return max(f.currentRow-f.clampedOffset(f.endOffset)+1, 0)
+		case execinfrapb.WindowerSpec_Frame_GROUPS:
+			offset := f.clampedOffset(f.endOffset)
+			if f.currentGroup-offset < 0 {
+				return 0
+			}
+			// The frame ends where the group following the one at the
+			// offset begins.
+			f.advanceToGroup(ctx, &f.endGroup, f.currentGroup-offset+1)
+			return f.endGroup.idx
+		default:
+			return f.searchRange(ctx, f.frameEnd, f.endDatumOffset, f.endBinOp, false /* isStart */)
+		}
+	case execinfrapb.WindowerSpec_Frame_CURRENT_ROW:
+		if f.mode == execinfrapb.WindowerSpec_Frame_ROWS {
+			return f.currentRow + 1
+		}
+		return f.peerGroupEnd
+	case execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING:
+		switch f.mode {
+		case execinfrapb.WindowerSpec_Frame_ROWS:
+			return min(f.currentRow+f.clampedOffset(f.endOffset)+1, f.partitionSize)
+		case execinfrapb.WindowerSpec_Frame_GROUPS:
+			f.advanceToGroup(ctx, &f.endGroup, f.currentGroup+f.clampedOffset(f.endOffset)+1)
+			return f.endGroup.idx
+		default:
+			return f.searchRange(ctx, f.frameEnd, f.endDatumOffset, f.endBinOp, false /* isStart */)
+		}
+	case execinfrapb.WindowerSpec_Frame_UNBOUNDED_FOLLOWING:
+		return f.partitionSize
+	default:
+		colexecerror.InternalError(errors.AssertionFailedf("unexpected end bound %s", f.endBound))
+		// This code is unreachable, but the compiler cannot infer that.
+		return 0
+	}
+}
+
+// ordValueAt returns the value of the ordering column of the tuple with the
+// given index.
+func (f *windowFramer) ordValueAt(ctx context.Context, idx int) tree.Datum {
+	vec, vecIdx, _ := f.buffer.GetVecWithTuple(ctx, f.ordColOrd, idx)
+	colconv.ColVecToDatumAndDeselect(f.converted, vec, 1 /* length */, []int{vecIdx}, &f.datumAlloc)
+	return f.converted[0]
+}
+
+// searchRange returns the bound of the frame in RANGE mode with an offset.
+// The value at the bound is computed by applying binOp to the value of the
+// current tuple and the offset, and then the tuples are scanned starting from
+// idx (the bound of the previous tuple) until:
+// - for the start bound, the first tuple that isn't before the target value,
+// - for the end bound, the first tuple that is after the target value,
+// in the ordering of the partition is found. NULL values are ordered before
+// all other values, and NULL target value is only equal to NULL values.
+func (f *windowFramer) searchRange(
+	ctx context.Context, idx int, offset tree.Datum, binOp *tree.BinOp, isStart bool,
+) int {
+	target := f.ordValueAt(ctx, f.currentRow)
+	if target != tree.DNull {
+		var err error
+		target, err = binOp.Fn(f.evalCtx, target, offset)
+		if err != nil {
+			colexecerror.ExpectedError(err)
+		}
+	}
+	for ; idx < f.partitionSize; idx++ {
+		cmp := f.ordValueAt(ctx, idx).Compare(f.evalCtx, target)
+		if !f.ordColAsc {
+			cmp = -cmp
+		}
+		if cmp > 0 || (isStart && cmp == 0) {
+			break
+		}
+	}
+	return idx
+}
+
+// computeIntervals populates f.intervals with the frame of the current tuple
+// after applying the exclusion clause.
+func (f *windowFramer) computeIntervals() {
+	f.intervals = f.intervals[:0]
+	if f.frameStart >= f.frameEnd {
+		return
+	}
+	f.intervals = append(f.intervals, windowInterval{start: f.frameStart, end: f.frameEnd})
+	switch f.exclusion {
+	case execinfrapb.WindowerSpec_Frame_EXCLUDE_CURRENT_ROW:
+		f.excludeRange(f.currentRow, f.currentRow+1)
+	case execinfrapb.WindowerSpec_Frame_EXCLUDE_GROUP:
+		f.excludeRange(f.peerGroupStart, f.peerGroupEnd)
+	case execinfrapb.WindowerSpec_Frame_EXCLUDE_TIES:
+		f.excludeRange(f.peerGroupStart, f.currentRow)
+		f.excludeRange(f.currentRow+1, f.peerGroupEnd)
+	}
+}
+
+// excludeRange removes the tuples in range [start, end) from f.intervals.
+func (f *windowFramer) excludeRange(start, end int) {
+	if start >= end {
+		return
+	}
+	// There are at most three intervals, so we simply rebuild the list.
+	var scratch [4]windowInterval
+	newIntervals := scratch[:0]
+	for _, interval := range f.intervals {
+		if interval.end <= start || interval.start >= end {
+			newIntervals = append(newIntervals, interval)
+			continue
+		}
+		if interval.start < start {
+			newIntervals = append(newIntervals, windowInterval{start: interval.start, end: start})
+		}
+		if interval.end > end {
+			newIntervals = append(newIntervals, windowInterval{start: end, end: interval.end})
+		}
+	}
+	f.intervals = append(f.intervals[:0], newIntervals...)
+}
+
+// frameIntervals returns the frame of the current tuple as a list of
+// non-empty intervals in increasing order. The slice is only valid until the
+// next call to next.
+func (f *windowFramer) frameIntervals() []windowInterval {
+	return f.intervals
+}
+
+// frameNthIdx returns the index of the nth (zero-based) tuple in the frame of
+// the current tuple or -1 if the frame has fewer tuples.
+func (f *windowFramer) frameNthIdx(n int) int {
+	for _, interval := range f.intervals {
+		if size := interval.end - interval.start; n < size {
+			return interval.start + n
+		} else {
+			n -= size
+		}
+	}
+	return -1
+}
+
+// frameFirstIdx returns the index of the first tuple in the frame of the
+// current tuple or -1 if the frame is empty.
+func (f *windowFramer) frameFirstIdx() int {
+	if len(f.intervals) == 0 {
+		return -1
+	}
+	return f.intervals[0].start
+}
+
+// frameLastIdx returns the index of the last tuple in the frame of the
+// current tuple or -1 if the frame is empty.
+func (f *windowFramer) frameLastIdx() int {
+	if len(f.intervals) == 0 {
+		return -1
+	}
+	return f.intervals[len(f.intervals)-1].end - 1
+}
+
+// isDefaultFrame returns whether the frame of the framer is RANGE BETWEEN
+// UNBOUNDED PRECEDING AND CURRENT ROW without exclusion.
+func (f *windowFramer) isDefaultFrame() bool {
+	return f.mode == execinfrapb.WindowerSpec_Frame_RANGE &&
+		f.startBound == execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING &&
+		f.endBound == execinfrapb.WindowerSpec_Frame_CURRENT_ROW &&
+		f.exclusion == execinfrapb.WindowerSpec_Frame_NO_EXCLUSION
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -15,11 +15,13 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecargs"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexectestutils"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/testutils/colcontainerutils"
@@ -61,6 +63,23 @@ func TestWindowFunctions(t *testing.T) {
 	denseRankFn := execinfrapb.WindowerSpec_DENSE_RANK
 	percentRankFn := execinfrapb.WindowerSpec_PERCENT_RANK
 	cumeDistFn := execinfrapb.WindowerSpec_CUME_DIST
+	ntileFn := execinfrapb.WindowerSpec_NTILE
+	lagFn := execinfrapb.WindowerSpec_LAG
+	leadFn := execinfrapb.WindowerSpec_LEAD
+	firstValueFn := execinfrapb.WindowerSpec_FIRST_VALUE
+	lastValueFn := execinfrapb.WindowerSpec_LAST_VALUE
+	nthValueFn := execinfrapb.WindowerSpec_NTH_VALUE
+	countFn := execinfrapb.Count
+	countRowsFn := execinfrapb.CountRows
+	maxFn := execinfrapb.Max
+	minFn := execinfrapb.Min
+	encodeIntOffset := func(offset int) []byte {
+		var a rowenc.DatumAlloc
+		d := rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(offset)))
+		buf, err := d.Encode(types.Int, &a, descpb.DatumEncoding_VALUE, nil /* appendTo */)
+		require.NoError(t, err)
+		return buf
+	}
 	accounts := make([]*mon.BoundAccount, 0)
 	monitors := make([]*mon.BytesMonitor, 0)
 	for _, spillForced := range []bool{false, true} {
@@ -271,6 +290,215 @@ func TestWindowFunctions(t *testing.T) {
 					},
 				},
 			},
+			{
+				tuples:   colexectestutils.Tuples{{1, 2}, {1, 2}, {1, 2}, {2, 3}, {2, 3}},
+				expected: colexectestutils.Tuples{{1, 2, 1}, {1, 2, 1}, {1, 2, 2}, {2, 3, 1}, {2, 3, 2}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &ntileFn},
+							ArgsIdxs:     []uint32{1},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			{
+				tuples:   colexectestutils.Tuples{{1, 1}, {1, 2}, {1, 3}, {2, 1}, {2, 2}},
+				expected: colexectestutils.Tuples{{1, 1, nil}, {1, 2, 1}, {1, 3, 2}, {2, 1, nil}, {2, 2, 1}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &lagFn},
+							ArgsIdxs:     []uint32{1},
+							Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			{
+				tuples:   colexectestutils.Tuples{{1, 1, 1}, {1, 2, 2}, {1, 3, nil}, {1, 4, -1}, {1, 5, 5}},
+				expected: colexectestutils.Tuples{{1, 1, 1, 2}, {1, 2, 2, 4}, {1, 3, nil, nil}, {1, 4, -1, 3}, {1, 5, 5, 5}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &leadFn},
+							ArgsIdxs:     []uint32{1, 2, 1},
+							Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							OutputColIdx: 3,
+						},
+					},
+				},
+			},
+			{
+				tuples:   colexectestutils.Tuples{{1, 1}, {1, 2}, {1, 2}, {1, 3}, {2, 4}},
+				expected: colexectestutils.Tuples{{1, 1, 1}, {1, 2, 1}, {1, 2, 1}, {1, 3, 1}, {2, 4, 4}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &firstValueFn},
+							ArgsIdxs:     []uint32{1},
+							Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			{
+				tuples:   colexectestutils.Tuples{{1, 1}, {1, 2}, {1, 2}, {1, 3}, {2, 4}},
+				expected: colexectestutils.Tuples{{1, 1, 1}, {1, 2, 2}, {1, 2, 2}, {1, 3, 3}, {2, 4, 4}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &lastValueFn},
+							ArgsIdxs:     []uint32{1},
+							Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			{
+				tuples:   colexectestutils.Tuples{{1, 1, 2}, {1, 2, 2}, {1, 2, nil}, {1, 3, 4}},
+				expected: colexectestutils.Tuples{{1, 1, 2, nil}, {1, 2, 2, 2}, {1, 2, nil, nil}, {1, 3, 4, 3}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &nthValueFn},
+							ArgsIdxs:     []uint32{1, 2},
+							Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							OutputColIdx: 3,
+						},
+					},
+				},
+			},
+			// Aggregate functions with ROWS frame and offsets.
+			{
+				tuples:   colexectestutils.Tuples{{1, 1}, {1, 2}, {1, nil}, {1, 4}, {2, 5}},
+				expected: colexectestutils.Tuples{{1, nil, 1}, {1, 1, 2}, {1, 2, 3}, {1, 4, 2}, {2, 5, 1}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &countFn},
+							ArgsIdxs: []uint32{1},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							Frame: &execinfrapb.WindowerSpec_Frame{
+								Mode: execinfrapb.WindowerSpec_Frame_ROWS,
+								Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+									Start: execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+										IntOffset: 1,
+									},
+									End: &execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING,
+										IntOffset: 1,
+									},
+								},
+							},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			// Aggregate function with ROWS frame and EXCLUDE CURRENT ROW.
+			{
+				tuples:   colexectestutils.Tuples{{1, 1}, {1, 2}, {1, nil}, {1, 4}, {2, 5}},
+				expected: colexectestutils.Tuples{{1, nil, 1}, {1, 1, 2}, {1, 2, 4}, {1, 4, 2}, {2, 5, nil}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &maxFn},
+							ArgsIdxs: []uint32{1},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							Frame: &execinfrapb.WindowerSpec_Frame{
+								Mode: execinfrapb.WindowerSpec_Frame_ROWS,
+								Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+									Start: execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+										IntOffset: 1,
+									},
+									End: &execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING,
+										IntOffset: 1,
+									},
+								},
+								Exclusion: execinfrapb.WindowerSpec_Frame_EXCLUDE_CURRENT_ROW,
+							},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			// Aggregate function with GROUPS frame and EXCLUDE TIES.
+			{
+				tuples:   colexectestutils.Tuples{{1, 1}, {1, 3}, {1, 2}, {1, 3}, {1, 1}, {1, 3}},
+				expected: colexectestutils.Tuples{{1, 1, 1}, {1, 1, 1}, {1, 2, 3}, {1, 3, 2}, {1, 3, 2}, {1, 3, 2}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &countRowsFn},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							Frame: &execinfrapb.WindowerSpec_Frame{
+								Mode: execinfrapb.WindowerSpec_Frame_GROUPS,
+								Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+									Start: execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+										IntOffset: 1,
+									},
+									End: &execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_CURRENT_ROW,
+									},
+								},
+								Exclusion: execinfrapb.WindowerSpec_Frame_EXCLUDE_TIES,
+							},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			// Aggregate function with RANGE frame with offsets, descending
+			// ordering and EXCLUDE GROUP.
+			{
+				tuples:   colexectestutils.Tuples{{1, 1}, {1, 2}, {1, nil}, {1, 4}, {1, 2}},
+				expected: colexectestutils.Tuples{{1, 4, nil}, {1, 2, 1}, {1, 2, 1}, {1, 1, 2}, {1, nil, nil}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &minFn},
+							ArgsIdxs: []uint32{1},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1, Direction: execinfrapb.Ordering_Column_DESC}}},
+							Frame: &execinfrapb.WindowerSpec_Frame{
+								Mode: execinfrapb.WindowerSpec_Frame_RANGE,
+								Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+									Start: execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType:   execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+										TypedOffset: encodeIntOffset(1),
+										OffsetType:  execinfrapb.DatumInfo{Encoding: descpb.DatumEncoding_VALUE, Type: types.Int},
+									},
+									End: &execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType:   execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING,
+										TypedOffset: encodeIntOffset(1),
+										OffsetType:  execinfrapb.DatumInfo{Encoding: descpb.DatumEncoding_VALUE, Type: types.Int},
+									},
+								},
+								Exclusion: execinfrapb.WindowerSpec_Frame_EXCLUDE_GROUP,
+							},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
 		} {
 			log.Infof(ctx, "spillForced=%t/%s", spillForced, tc.windowerSpec.WindowFns[0].Func.String())
 			var semsToCheck []semaphore.Semaphore
@@ -280,10 +508,14 @@ func TestWindowFunctions(t *testing.T) {
 				for i := range ct {
 					ct[i] = types.Int
 				}
-				resultType := types.Int
-				wf := tc.windowerSpec.WindowFns[0].Func.WindowFunc
-				if wf == &percentRankFn || wf == &cumeDistFn {
-					resultType = types.Float
+				wf := tc.windowerSpec.WindowFns[0]
+				argTypes := make([]*types.T, len(wf.ArgsIdxs))
+				for i, idx := range wf.ArgsIdxs {
+					argTypes[i] = ct[idx]
+				}
+				_, resultType, err := execinfrapb.GetWindowFunctionInfo(wf.Func, argTypes...)
+				if err != nil {
+					return nil, err
 				}
 				spec := &execinfrapb.ProcessorSpec{
 					Input: []execinfrapb.InputSyncSpec{{ColumnTypes: ct}},
@@ -292,9 +524,9 @@ func TestWindowFunctions(t *testing.T) {
 					},
 					ResultTypes: append(ct, resultType),
 				}
-				// Relative rank operators currently require the most number of
-				// FDs.
-				sem := colexecop.NewTestingSemaphore(relativeRankNumRequiredFDs)
+				// Buffered window operators currently require the most number
+				// of FDs.
+				sem := colexecop.NewTestingSemaphore(BufferedWindowNumRequiredFDs)
 				args := &colexecargs.NewColOperatorArgs{
 					Spec:                spec,
 					Inputs:              inputs,
@@ -17,13 +17,20 @@ import (
 )
 
 // SupportedWindowFns contains all window functions supported by the
-// vectorized engine.
+// vectorized engine. Note that all aggregate functions are supported as window
+// functions too.
 var SupportedWindowFns = map[execinfrapb.WindowerSpec_WindowFunc]struct{}{
 	execinfrapb.WindowerSpec_ROW_NUMBER:   {},
 	execinfrapb.WindowerSpec_RANK:         {},
 	execinfrapb.WindowerSpec_DENSE_RANK:   {},
 	execinfrapb.WindowerSpec_PERCENT_RANK: {},
 	execinfrapb.WindowerSpec_CUME_DIST:    {},
+	execinfrapb.WindowerSpec_NTILE:        {},
+	execinfrapb.WindowerSpec_LAG:          {},
+	execinfrapb.WindowerSpec_LEAD:         {},
+	execinfrapb.WindowerSpec_FIRST_VALUE:  {},
+	execinfrapb.WindowerSpec_LAST_VALUE:   {},
+	execinfrapb.WindowerSpec_NTH_VALUE:    {},
 }
 
 // WindowFnNeedsPeersInfo returns whether a window function pays attention to
@@ -31,11 +38,20 @@ var SupportedWindowFns = map[execinfrapb.WindowerSpec_WindowFunc]struct{}{
 // same partition - from PARTITION BY clause - that are not distinct on the
 // columns in ORDER BY clause). For most window functions, the result of
 // computation should be the same for "peers", so most window functions do need
-// this information.
-func WindowFnNeedsPeersInfo(windowFn execinfrapb.WindowerSpec_WindowFunc) bool {
-	switch windowFn {
-	case execinfrapb.WindowerSpec_ROW_NUMBER:
-		// row_number doesn't pay attention to the concept of "peers."
+// this information. The functions that are computed over the window frame
+// (aggregate functions as well as FIRST_VALUE, LAST_VALUE, and NTH_VALUE)
+// need this information only if the frame is defined in terms of peers.
+func WindowFnNeedsPeersInfo(windowFn *execinfrapb.WindowerSpec_WindowFn) bool {
+	if windowFn.Func.AggregateFunc != nil {
+		return frameNeedsPeersInfo(windowFn.Frame)
+	}
+	switch *windowFn.Func.WindowFunc {
+	case
+		execinfrapb.WindowerSpec_ROW_NUMBER,
+		execinfrapb.WindowerSpec_NTILE,
+		execinfrapb.WindowerSpec_LAG,
+		execinfrapb.WindowerSpec_LEAD:
+		// These functions don't pay attention to the concept of "peers."
 		return false
 	case
 		execinfrapb.WindowerSpec_RANK,
@@ -43,8 +59,13 @@ func WindowFnNeedsPeersInfo(windowFn execinfrapb.WindowerSpec_WindowFunc) bool {
 		execinfrapb.WindowerSpec_PERCENT_RANK,
 		execinfrapb.WindowerSpec_CUME_DIST:
 		return true
+	case
+		execinfrapb.WindowerSpec_FIRST_VALUE,
+		execinfrapb.WindowerSpec_LAST_VALUE,
+		execinfrapb.WindowerSpec_NTH_VALUE:
+		return frameNeedsPeersInfo(windowFn.Frame)
 	default:
-		colexecerror.InternalError(errors.AssertionFailedf("window function %s is not supported", windowFn.String()))
+		colexecerror.InternalError(errors.AssertionFailedf("window function %s is not supported", windowFn.Func.WindowFunc.String()))
 		// This code is unreachable, but the compiler cannot infer that.
 		return false
 	}
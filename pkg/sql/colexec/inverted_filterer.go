@@ -0,0 +1,305 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+	"github.com/marusama/semaphore"
+)
+
+// invertedFiltererState represents the state of the invertedFiltererOp.
+type invertedFiltererState int
+
+const (
+	// invertedFiltererReading means that the inverted index rows are being
+	// read from the input.
+	invertedFiltererReading invertedFiltererState = iota
+	// invertedFiltererEmitting means that the results of the evaluation are
+	// being emitted.
+	invertedFiltererEmitting
+	// invertedFiltererDone means that the operator has emitted all of its
+	// output.
+	invertedFiltererDone
+)
+
+// invertedFiltererOp is the columnar equivalent of the invertedFilterer
+// processor. It reads all rows of an inverted index (where the inverted column
+// contains the encoded inverted key as Bytes), de-duplicates the remaining
+// (primary key) columns, and feeds them into the inverted expression
+// evaluator. Once the input is exhausted, the expression is evaluated, and the
+// resulting tuples are emitted with the inverted column set to NULL.
+//
+// NOTE: the de-duplicating map is only kept in memory, but the de-duplicated
+// tuples themselves can spill to disk.
+type invertedFiltererOp struct {
+	colexecop.OneInputNode
+	colexecop.CloserHelper
+
+	state          invertedFiltererState
+	allocator      *colmem.Allocator
+	mapAllocator   *colmem.Allocator
+	memoryLimit    int64
+	inputTypes     []*types.T
+	invertedColIdx int
+	// storedColIdxs are the ordinals of the input columns other than the
+	// inverted column.
+	storedColIdxs []int
+
+	invertedEval inverted.BatchedExprEvaluator
+	// converter converts the stored columns of the input batches to datums
+	// which are then used to compute the fingerprints of the tuples.
+	converter  *colconv.VecToDatumConverter
+	datumAlloc rowenc.DatumAlloc
+	scratch    []byte
+	// keyIndexes maps the fingerprint of a tuple to the index of that tuple
+	// in buffer.
+	keyIndexes map[string]inverted.KeyIndex
+	buffer     *colexecutils.SpillingBuffer
+	// newTuplesSel contains the physical indices of the tuples of the current
+	// input batch that haven't been seen before.
+	newTuplesSel []int
+
+	// evalResult is the result of the evaluation of the inverted expression,
+	// and resultIdx is the position of the next tuple to emit.
+	evalResult []inverted.KeyIndex
+	resultIdx  int
+	srcSel     []int
+
+	output coldata.Batch
+}
+
+var _ colexecop.ClosableOperator = &invertedFiltererOp{}
+
+// keyIndexesEntryOverhead is the memory footprint of an entry in the
+// de-duplicating map, not including the key bytes.
+const keyIndexesEntryOverhead = int64(unsafe.Sizeof("") + unsafe.Sizeof(inverted.KeyIndex(0)))
+
+// NewInvertedFilterer returns a new columnar operator that performs the
+// filtering specified by spec. The inverted column of the input must be of
+// Bytes type. preFilterer and preFilterState are optional and are used to
+// pre-filter the inverted index rows.
+//
+// unlimitedAllocator is used by the buffer of the de-duplicated tuples and
+// must not be shared with anything else, and mapAllocator is used to account
+// for the memory used by the de-duplicating map.
+func NewInvertedFilterer(
+	allocator *colmem.Allocator,
+	unlimitedAllocator *colmem.Allocator,
+	mapAllocator *colmem.Allocator,
+	memoryLimit int64,
+	diskQueueCfg colcontainer.DiskQueueCfg,
+	fdSemaphore semaphore.Semaphore,
+	diskAcc *mon.BoundAccount,
+	input colexecop.Operator,
+	inputTypes []*types.T,
+	spec *execinfrapb.InvertedFiltererSpec,
+	preFilterer inverted.PreFilterer,
+	preFilterState interface{},
+) (colexecop.Operator, error) {
+	invertedColIdx := int(spec.InvertedColIdx)
+	if invertedColIdx >= len(inputTypes) || inputTypes[invertedColIdx].Family() != types.BytesFamily {
+		return nil, errors.AssertionFailedf("inverted column must be of bytes type")
+	}
+	if len(inputTypes) < 2 {
+		return nil, errors.AssertionFailedf("at least one non-inverted column is required")
+	}
+	storedColIdxs := make([]int, 0, len(inputTypes)-1)
+	for i := range inputTypes {
+		if i != invertedColIdx {
+			storedColIdxs = append(storedColIdxs, i)
+		}
+	}
+	op := &invertedFiltererOp{
+		OneInputNode:   colexecop.NewOneInputNode(input),
+		allocator:      allocator,
+		mapAllocator:   mapAllocator,
+		memoryLimit:    memoryLimit,
+		inputTypes:     inputTypes,
+		invertedColIdx: invertedColIdx,
+		storedColIdxs:  storedColIdxs,
+		invertedEval: inverted.BatchedExprEvaluator{
+			Exprs: []*inverted.SpanExpressionProto{&spec.InvertedExpr},
+		},
+		converter:  colconv.NewVecToDatumConverter(len(inputTypes), storedColIdxs),
+		keyIndexes: make(map[string]inverted.KeyIndex),
+		buffer: colexecutils.NewSpillingBuffer(
+			unlimitedAllocator, memoryLimit, diskQueueCfg, fdSemaphore, inputTypes, diskAcc, storedColIdxs...,
+		),
+	}
+	if preFilterer != nil {
+		op.invertedEval.Filterer = preFilterer
+		op.invertedEval.PreFilterState = append(op.invertedEval.PreFilterState, preFilterState)
+	}
+	// Prepare inverted evaluator for later evaluation.
+	if _, err := op.invertedEval.Init(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (f *invertedFiltererOp) Init() {
+	f.Input.Init()
+}
+
+func (f *invertedFiltererOp) Next(ctx context.Context) coldata.Batch {
+	for {
+		switch f.state {
+		case invertedFiltererReading:
+			batch := f.Input.Next(ctx)
+			if batch.Length() == 0 {
+				// invertedEval had a single expression in the batch, and the
+				// results for that expression are in evalResult[0].
+				f.evalResult = f.invertedEval.Evaluate()[0]
+				f.state = invertedFiltererEmitting
+				continue
+			}
+			f.addBatch(ctx, batch)
+		case invertedFiltererEmitting:
+			if f.resultIdx == len(f.evalResult) {
+				f.state = invertedFiltererDone
+				continue
+			}
+			return f.emit(ctx)
+		case invertedFiltererDone:
+			return coldata.ZeroBatch
+		default:
+			colexecerror.InternalError(errors.AssertionFailedf("unexpected state %d", f.state))
+		}
+	}
+}
+
+// addBatch adds all tuples from batch to the inverted expression evaluator,
+// buffering the tuples that haven't been seen before.
+func (f *invertedFiltererOp) addBatch(ctx context.Context, batch coldata.Batch) {
+	n := batch.Length()
+	sel := batch.Selection()
+	f.converter.ConvertBatchAndDeselect(batch)
+	invertedCol := batch.ColVec(f.invertedColIdx)
+	invertedKeys := invertedCol.Bytes()
+	f.newTuplesSel = f.newTuplesSel[:0]
+	var mapMemUsage int64
+	for i := 0; i < n; i++ {
+		srcIdx := i
+		if sel != nil {
+			srcIdx = sel[i]
+		}
+		if invertedCol.Nulls().NullAt(srcIdx) {
+			// NULLs are never stored in inverted indexes.
+			colexecerror.InternalError(errors.AssertionFailedf("unexpected NULL in the inverted column"))
+		}
+		// NB: inverted columns are custom encoded in a manner that does not
+		// correspond to Datum encoding, and we want only the encoded bytes.
+		shouldAdd, err := f.invertedEval.PrepareAddIndexRow(invertedKeys.Get(srcIdx), nil /* encFull */)
+		if err != nil {
+			colexecerror.ExpectedError(err)
+		}
+		if !shouldAdd {
+			continue
+		}
+		f.scratch = f.scratch[:0]
+		for _, colIdx := range f.storedColIdxs {
+			encDatum := rowenc.DatumToEncDatum(f.inputTypes[colIdx], f.converter.GetDatumColumn(colIdx)[i])
+			f.scratch, err = encDatum.Fingerprint(ctx, f.inputTypes[colIdx], &f.datumAlloc, f.scratch, nil /* acc */)
+			if err != nil {
+				colexecerror.ExpectedError(err)
+			}
+		}
+		keyIndex, ok := f.keyIndexes[string(f.scratch)]
+		if !ok {
+			keyIndex = f.buffer.Length() + len(f.newTuplesSel)
+			f.keyIndexes[string(f.scratch)] = keyIndex
+			f.newTuplesSel = append(f.newTuplesSel, srcIdx)
+			mapMemUsage += int64(len(f.scratch)) + keyIndexesEntryOverhead
+		}
+		if err = f.invertedEval.AddIndexRow(keyIndex); err != nil {
+			colexecerror.ExpectedError(err)
+		}
+	}
+	f.mapAllocator.AdjustMemoryUsage(mapMemUsage)
+	if len(f.newTuplesSel) > 0 {
+		// Update the selection vector of the batch to include only the new
+		// tuples so that we can append them all at once.
+		batch.SetSelection(true)
+		copy(batch.Selection(), f.newTuplesSel)
+		batch.SetLength(len(f.newTuplesSel))
+		f.buffer.AppendTuples(ctx, batch, 0 /* startIdx */, len(f.newTuplesSel))
+	}
+}
+
+// emit populates the output batch with the next tuples from the evaluation
+// result.
+func (f *invertedFiltererOp) emit(ctx context.Context) coldata.Batch {
+	toEmit := len(f.evalResult) - f.resultIdx
+	f.output, _ = f.allocator.ResetMaybeReallocate(f.inputTypes, f.output, toEmit, f.memoryLimit)
+	if toEmit > f.output.Capacity() {
+		toEmit = f.output.Capacity()
+	}
+	f.allocator.PerformOperation(f.output.ColVecs(), func() {
+		for outputIdx := 0; outputIdx < toEmit; {
+			// Find the run of the result tuples that are stored in the same
+			// vectors of the buffer.
+			startIdx := f.evalResult[f.resultIdx+outputIdx]
+			var endIdx int
+			f.srcSel = f.srcSel[:0]
+			for i, colIdx := range f.storedColIdxs {
+				vec, rowIdx, length := f.buffer.GetVecWithTuple(ctx, i, startIdx)
+				if i == 0 {
+					endIdx = startIdx + length - rowIdx
+					for j := outputIdx; j < toEmit; j++ {
+						tupleIdx := f.evalResult[f.resultIdx+j]
+						if tupleIdx >= endIdx {
+							break
+						}
+						f.srcSel = append(f.srcSel, rowIdx+tupleIdx-startIdx)
+					}
+				}
+				f.output.ColVec(colIdx).Copy(
+					coldata.CopySliceArgs{
+						SliceArgs: coldata.SliceArgs{
+							Src:       vec,
+							Sel:       f.srcSel,
+							DestIdx:   outputIdx,
+							SrcEndIdx: len(f.srcSel),
+						},
+					},
+				)
+			}
+			outputIdx += len(f.srcSel)
+		}
+	})
+	f.output.ColVec(f.invertedColIdx).Nulls().SetNulls()
+	f.resultIdx += toEmit
+	f.output.SetLength(toEmit)
+	return f.output
+}
+
+// Close is part of the colexecop.Closer interface.
+func (f *invertedFiltererOp) Close(ctx context.Context) error {
+	if !f.CloserHelper.Close() {
+		return nil
+	}
+	return f.buffer.Close(ctx)
+}
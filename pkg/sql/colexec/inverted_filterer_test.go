@@ -0,0 +1,118 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexectestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/colcontainerutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+func intToEncodedInvertedVal(v int64) []byte {
+	return encoding.EncodeVarintAscending(nil, v)
+}
+
+func intSpanToEncodedSpan(start, end int64) inverted.SpanExpressionProto_Span {
+	return inverted.SpanExpressionProto_Span{
+		Start: intToEncodedInvertedVal(start),
+		End:   intToEncodedInvertedVal(end),
+	}
+}
+
+func TestInvertedFilterer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	queueCfg, cleanup := colcontainerutils.NewTestingDiskQueueCfg(t, true /* inMem */)
+	defer cleanup()
+
+	// The expression intersects the spans for the inverted column values 1
+	// and 3.
+	invertedExpr := inverted.SpanExpressionProto{
+		Node: inverted.SpanExpressionProto_Node{
+			Operator: inverted.SetIntersection,
+			Left: &inverted.SpanExpressionProto_Node{
+				FactoredUnionSpans: []inverted.SpanExpressionProto_Span{intSpanToEncodedSpan(1, 2)},
+			},
+			Right: &inverted.SpanExpressionProto_Node{
+				FactoredUnionSpans: []inverted.SpanExpressionProto_Span{intSpanToEncodedSpan(3, 4)},
+			},
+		},
+	}
+	one, three, five := intToEncodedInvertedVal(1), intToEncodedInvertedVal(3), intToEncodedInvertedVal(5)
+	for _, tc := range []struct {
+		description    string
+		invertedColIdx uint32
+		tuples         colexectestutils.Tuples
+		typs           []*types.T
+		expected       colexectestutils.Tuples
+	}{
+		{
+			description: "simple intersection",
+			tuples: colexectestutils.Tuples{
+				{one, 12}, {one, 23}, {one, 41}, {one, 50}, {five, 23},
+				{three, 23}, {three, 34}, {three, 36}, {three, 41}, {three, 50}, {three, 51},
+			},
+			typs:     []*types.T{types.Bytes, types.Int},
+			expected: colexectestutils.Tuples{{nil, 23}, {nil, 41}, {nil, 50}},
+		},
+		{
+			description:    "inverted column in the middle",
+			invertedColIdx: 1,
+			tuples: colexectestutils.Tuples{
+				{12, one, "a"}, {13, one, "b"}, {14, one, "c"}, {12, three, "a"}, {14, three, "c"}, {13, three, "a"},
+			},
+			typs:     []*types.T{types.Int, types.Bytes, types.String},
+			expected: colexectestutils.Tuples{{12, nil, "a"}, {14, nil, "c"}},
+		},
+	} {
+		for _, memoryLimit := range []int64{1 /* spill immediately */, 64 << 20} {
+			t.Run(fmt.Sprintf("%s/memoryLimit=%d", tc.description, memoryLimit), func(t *testing.T) {
+				var closers []colexecop.Closer
+				// NULLs are never stored in the inverted column, so the all
+				// NULLs injection isn't applicable.
+				colexectestutils.RunTestsWithoutAllNullsInjection(
+					t, testAllocator, []colexectestutils.Tuples{tc.tuples}, [][]*types.T{tc.typs}, tc.expected,
+					colexectestutils.OrderedVerifier, func(inputs []colexecop.Operator) (colexecop.Operator, error) {
+						spec := &execinfrapb.InvertedFiltererSpec{
+							InvertedColIdx: tc.invertedColIdx,
+							InvertedExpr:   invertedExpr,
+						}
+						sem := colexecop.NewTestingSemaphore(colexecop.ExternalSorterMinPartitions)
+						op, err := NewInvertedFilterer(
+							testAllocator, testAllocator, testAllocator, memoryLimit, queueCfg, sem,
+							testDiskAcc, inputs[0], tc.typs, spec, nil /* preFilterer */, nil, /* preFilterState */
+						)
+						if err == nil {
+							closers = append(closers, op.(colexecop.Closer))
+						}
+						return op, err
+					},
+				)
+				for _, c := range closers {
+					if err := c.Close(context.Background()); err != nil {
+						t.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,324 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// projectSetOp is the columnar equivalent of the projectSetProcessor. For
+// every input tuple it evaluates the expressions specified in the ROWS FROM
+// syntax (set-returning functions as well as scalar expressions) and emits the
+// input tuple extended with the generated values for as long as at least one
+// of the expressions produces a new value.
+//
+// Every output batch contains tuples that originate from a single input
+// batch which allows us to copy the input columns with a selection vector.
+type projectSetOp struct {
+	colexecop.OneInputNode
+	colexecop.CloserHelper
+
+	allocator   *colmem.Allocator
+	flowCtx     *execinfra.FlowCtx
+	evalCtx     *tree.EvalContext
+	spec        *execinfrapb.ProjectSetSpec
+	inputTypes  []*types.T
+	outputTypes []*types.T
+	memoryLimit int64
+
+	// exprHelpers are the constant-folded, type checked expressions specified
+	// in the ROWS FROM syntax.
+	exprHelpers []*execinfrapb.ExprHelper
+	// funcs contains a valid pointer to a SRF FuncExpr for every entry in
+	// exprHelpers that is actually a SRF function application.
+	funcs []*tree.FuncExpr
+	// gens contains the current "active" ValueGenerators for each entry in
+	// funcs. They are initialized anew for every input tuple.
+	gens []tree.ValueGenerator
+	// done indicates for each expression whether the values produced by it
+	// are fully consumed for the current input tuple, and thus whether NULLs
+	// should be emitted instead.
+	done []bool
+
+	converter *colconv.VecToDatumConverter
+	// datumToVecFns[i] converts a datum of the i-th generated column into the
+	// physical representation of the corresponding vector.
+	datumToVecFns []func(tree.Datum) interface{}
+
+	// batch is the current input batch, and inputIdx is the position of the
+	// current input tuple among the tuples of batch (not taking the selection
+	// vector into account).
+	batch    coldata.Batch
+	inputIdx int
+	// inputRowReady is set when the generators have been set up for the
+	// current input tuple and they might still produce values.
+	inputRowReady bool
+	// row contains the current input tuple.
+	row rowenc.EncDatumRow
+	// generated contains the next set of generated values.
+	generated tree.Datums
+
+	output coldata.Batch
+	// outputSrcIdxs contains the physical index of the input tuple for each
+	// tuple in the output batch.
+	outputSrcIdxs []int
+}
+
+var _ colexecop.ClosableOperator = &projectSetOp{}
+
+// NewProjectSetOp returns a new columnar operator that evaluates the
+// expressions of a ProjectSetSpec on each tuple of the input. The output
+// schema is the input schema followed by spec.GeneratedColumns.
+func NewProjectSetOp(
+	allocator *colmem.Allocator,
+	flowCtx *execinfra.FlowCtx,
+	evalCtx *tree.EvalContext,
+	input colexecop.Operator,
+	inputTypes []*types.T,
+	spec *execinfrapb.ProjectSetSpec,
+	memoryLimit int64,
+) (colexecop.Operator, error) {
+	outputTypes := make([]*types.T, 0, len(inputTypes)+len(spec.GeneratedColumns))
+	outputTypes = append(outputTypes, inputTypes...)
+	outputTypes = append(outputTypes, spec.GeneratedColumns...)
+	p := &projectSetOp{
+		OneInputNode:  colexecop.NewOneInputNode(input),
+		allocator:     allocator,
+		flowCtx:       flowCtx,
+		evalCtx:       evalCtx,
+		spec:          spec,
+		inputTypes:    inputTypes,
+		outputTypes:   outputTypes,
+		memoryLimit:   memoryLimit,
+		exprHelpers:   make([]*execinfrapb.ExprHelper, len(spec.Exprs)),
+		funcs:         make([]*tree.FuncExpr, len(spec.Exprs)),
+		gens:          make([]tree.ValueGenerator, len(spec.Exprs)),
+		done:          make([]bool, len(spec.Exprs)),
+		converter:     colconv.NewAllVecToDatumConverter(len(inputTypes)),
+		datumToVecFns: make([]func(tree.Datum) interface{}, len(spec.GeneratedColumns)),
+		row:           make(rowenc.EncDatumRow, len(inputTypes)),
+		generated:     make(tree.Datums, len(spec.GeneratedColumns)),
+	}
+	for i, typ := range spec.GeneratedColumns {
+		p.datumToVecFns[i] = colconv.GetDatumToPhysicalFn(typ)
+	}
+	semaCtx := flowCtx.TypeResolverFactory.NewSemaContext(evalCtx.Txn)
+	for i, expr := range spec.Exprs {
+		var helper execinfrapb.ExprHelper
+		if err := helper.Init(expr, inputTypes, semaCtx, evalCtx); err != nil {
+			return nil, err
+		}
+		if tFunc, ok := helper.Expr.(*tree.FuncExpr); ok && tFunc.IsGeneratorApplication() {
+			// Expr is a set-generating function.
+			p.funcs[i] = tFunc
+		}
+		p.exprHelpers[i] = &helper
+	}
+	return p, nil
+}
+
+func (p *projectSetOp) Init() {
+	p.Input.Init()
+}
+
+func (p *projectSetOp) Next(ctx context.Context) coldata.Batch {
+	for {
+		if !p.inputRowReady && (p.batch == nil || p.inputIdx == p.batch.Length()) {
+			p.batch = p.Input.Next(ctx)
+			p.inputIdx = 0
+			if p.batch.Length() == 0 {
+				return coldata.ZeroBatch
+			}
+			p.converter.ConvertBatchAndDeselect(p.batch)
+		}
+		// Most commonly every input tuple results in at least one output
+		// tuple, so we use the number of remaining input tuples as the hint
+		// for the capacity of the output batch.
+		p.output, _ = p.allocator.ResetMaybeReallocate(
+			p.outputTypes, p.output, p.batch.Length()-p.inputIdx, p.memoryLimit,
+		)
+		if n := p.fillOutput(ctx); n > 0 {
+			return p.output
+		}
+	}
+}
+
+// fillOutput populates the output batch with the tuples generated for the
+// remaining input tuples of the current input batch, until either the output
+// batch is full or the input batch has been fully processed. The number of
+// output tuples is returned.
+func (p *projectSetOp) fillOutput(ctx context.Context) int {
+	numInputCols := len(p.inputTypes)
+	sel := p.batch.Selection()
+	p.outputSrcIdxs = p.outputSrcIdxs[:0]
+	outputIdx := 0
+	generatedVecs := p.output.ColVecs()[numInputCols:]
+	p.allocator.PerformOperation(generatedVecs, func() {
+		for outputIdx < p.output.Capacity() {
+			if !p.inputRowReady {
+				if p.inputIdx == p.batch.Length() {
+					return
+				}
+				p.startInputRow(ctx)
+			}
+			if !p.nextGeneratorValues(ctx) {
+				// The values for the current input tuple have been exhausted,
+				// so we advance to the next input tuple.
+				p.inputRowReady = false
+				p.inputIdx++
+				continue
+			}
+			for i, d := range p.generated {
+				if d == tree.DNull {
+					generatedVecs[i].Nulls().SetNull(outputIdx)
+				} else {
+					coldata.SetValueAt(generatedVecs[i], p.datumToVecFns[i](d), outputIdx)
+				}
+			}
+			srcIdx := p.inputIdx
+			if sel != nil {
+				srcIdx = sel[srcIdx]
+			}
+			p.outputSrcIdxs = append(p.outputSrcIdxs, srcIdx)
+			outputIdx++
+		}
+	})
+	if outputIdx == 0 {
+		return 0
+	}
+	p.allocator.PerformOperation(p.output.ColVecs()[:numInputCols], func() {
+		for i := 0; i < numInputCols; i++ {
+			p.output.ColVec(i).Copy(
+				coldata.CopySliceArgs{
+					SliceArgs: coldata.SliceArgs{
+						Src:       p.batch.ColVec(i),
+						Sel:       p.outputSrcIdxs,
+						SrcEndIdx: outputIdx,
+					},
+				},
+			)
+		}
+	})
+	p.output.SetLength(outputIdx)
+	return outputIdx
+}
+
+// startInputRow prepares the generators and the scalar expressions for the
+// current input tuple.
+func (p *projectSetOp) startInputRow(ctx context.Context) {
+	for i := range p.inputTypes {
+		p.row[i] = rowenc.DatumToEncDatum(p.inputTypes[i], p.converter.GetDatumColumn(i)[p.inputIdx])
+	}
+	for i := range p.exprHelpers {
+		if fn := p.funcs[i]; fn != nil {
+			// A set-generating function. Prepare its ValueGenerator.
+			if p.gens[i] != nil {
+				p.gens[i].Close(ctx)
+				p.gens[i] = nil
+			}
+			// Set ExprHelper.Row so that we can use it as an
+			// IndexedVarContainer.
+			p.exprHelpers[i].Row = p.row
+			p.evalCtx.IVarContainer = p.exprHelpers[i]
+			gen, err := fn.EvalArgsAndGetGenerator(p.evalCtx)
+			if err != nil {
+				colexecerror.ExpectedError(err)
+			}
+			if gen == nil {
+				gen = builtins.EmptyGenerator()
+			}
+			if err := gen.Start(ctx, p.flowCtx.Txn); err != nil {
+				colexecerror.ExpectedError(err)
+			}
+			p.gens[i] = gen
+		}
+		p.done[i] = false
+	}
+	p.inputRowReady = true
+}
+
+// nextGeneratorValues populates p.generated with the next set of generated
+// values. It returns true if any of the expressions produced a new value.
+func (p *projectSetOp) nextGeneratorValues(ctx context.Context) (newValAvail bool) {
+	colIdx := 0
+	for i := range p.exprHelpers {
+		if fn := p.funcs[i]; fn != nil {
+			gen := p.gens[i]
+			numCols := int(p.spec.NumColsPerGen[i])
+			if !p.done[i] {
+				hasVals, err := gen.Next(ctx)
+				if err != nil {
+					colexecerror.ExpectedError(err)
+				}
+				if hasVals {
+					values, err := gen.Values()
+					if err != nil {
+						colexecerror.ExpectedError(err)
+					}
+					for _, value := range values {
+						p.generated[colIdx] = value
+						colIdx++
+					}
+					newValAvail = true
+					continue
+				}
+				p.done[i] = true
+			}
+			// No values left, so NULLs are emitted for this generator.
+			for j := 0; j < numCols; j++ {
+				p.generated[colIdx] = tree.DNull
+				colIdx++
+			}
+		} else {
+			// A simple scalar result which is produced only once, and NULLs
+			// are emitted after that.
+			if !p.done[i] {
+				value, err := p.exprHelpers[i].Eval(p.row)
+				if err != nil {
+					colexecerror.ExpectedError(err)
+				}
+				p.generated[colIdx] = value
+				newValAvail = true
+				p.done[i] = true
+			} else {
+				p.generated[colIdx] = tree.DNull
+			}
+			colIdx++
+		}
+	}
+	return newValAvail
+}
+
+// Close is part of the colexecop.Closer interface.
+func (p *projectSetOp) Close(ctx context.Context) error {
+	if !p.CloserHelper.Close() {
+		return nil
+	}
+	for i, gen := range p.gens {
+		if gen != nil {
+			gen.Close(ctx)
+			p.gens[i] = nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexectestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+func TestProjectSetOp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg: &execinfra.ServerConfig{
+			Settings: st,
+		},
+	}
+
+	for _, tc := range []struct {
+		description string
+		spec        execinfrapb.ProjectSetSpec
+		tuples      colexectestutils.Tuples
+		inputTypes  []*types.T
+		expected    colexectestutils.Tuples
+	}{
+		{
+			description: "scalar function",
+			spec: execinfrapb.ProjectSetSpec{
+				Exprs:            []execinfrapb.Expression{{Expr: "@1 + 1"}},
+				GeneratedColumns: []*types.T{types.Int},
+				NumColsPerGen:    []uint32{1},
+			},
+			tuples:     colexectestutils.Tuples{{2}, {nil}},
+			inputTypes: []*types.T{types.Int},
+			expected:   colexectestutils.Tuples{{2, 3}, {nil, nil}},
+		},
+		{
+			description: "set-returning function",
+			spec: execinfrapb.ProjectSetSpec{
+				Exprs:            []execinfrapb.Expression{{Expr: "generate_series(@1, 2)"}},
+				GeneratedColumns: []*types.T{types.Int},
+				NumColsPerGen:    []uint32{1},
+			},
+			tuples:     colexectestutils.Tuples{{0}, {3}, {1}},
+			inputTypes: []*types.T{types.Int},
+			expected:   colexectestutils.Tuples{{0, 0}, {0, 1}, {0, 2}, {1, 1}, {1, 2}},
+		},
+		{
+			description: "multiple exprs with different lengths",
+			spec: execinfrapb.ProjectSetSpec{
+				Exprs: []execinfrapb.Expression{
+					{Expr: "0"},
+					{Expr: "generate_series(0, 0)"},
+					{Expr: "generate_series(0, 1)"},
+					{Expr: "generate_series(0, @2)"},
+				},
+				GeneratedColumns: []*types.T{types.Int, types.Int, types.Int, types.Int},
+				NumColsPerGen:    []uint32{1, 1, 1, 1},
+			},
+			tuples:     colexectestutils.Tuples{{"a", 2}, {"b", -1}},
+			inputTypes: []*types.T{types.String, types.Int},
+			expected: colexectestutils.Tuples{
+				{"a", 2, 0, 0, 0, 0},
+				{"a", 2, nil, nil, 1, 1},
+				{"a", 2, nil, nil, nil, 2},
+				{"b", -1, 0, 0, 0, nil},
+				{"b", -1, nil, nil, 1, nil},
+			},
+		},
+		{
+			description: "multi-column generator",
+			spec: execinfrapb.ProjectSetSpec{
+				Exprs:            []execinfrapb.Expression{{Expr: "json_each(@1)"}},
+				GeneratedColumns: []*types.T{types.String, types.Jsonb},
+				NumColsPerGen:    []uint32{2},
+			},
+			tuples:     colexectestutils.Tuples{{`'{"a": 1, "b": [2]}'`}},
+			inputTypes: []*types.T{types.Jsonb},
+			expected: colexectestutils.Tuples{
+				{`'{"a": 1, "b": [2]}'`, "a", `'1'`},
+				{`'{"a": 1, "b": [2]}'`, "b", `'[2]'`},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			colexectestutils.RunTestsWithTyps(t, testAllocator, []colexectestutils.Tuples{tc.tuples}, [][]*types.T{tc.inputTypes}, tc.expected, colexectestutils.OrderedVerifier, func(inputs []colexecop.Operator) (colexecop.Operator, error) {
+				return NewProjectSetOp(
+					testAllocator, flowCtx, &evalCtx, inputs[0], tc.inputTypes, &tc.spec, execinfra.GetWorkMemLimit(flowCtx.Cfg),
+				)
+			})
+		})
+	}
+}
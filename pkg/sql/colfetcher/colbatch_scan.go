@@ -267,7 +267,9 @@ func NewColBatchScan(
 	fetcher.estimatedRowCount = estimatedRowCount
 	if _, _, err := initCRowFetcher(
 		flowCtx.Codec(), allocator, execinfra.GetWorkMemLimit(flowCtx.Cfg),
-		fetcher, table, columnIdxMap, neededColumns, spec, spec.HasSystemColumns,
+		fetcher, table, int(spec.IndexIdx), columnIdxMap, neededColumns, spec.Reverse,
+		spec.Visibility, spec.LockingStrength, spec.LockingWaitPolicy, virtualColumn,
+		spec.HasSystemColumns,
 	); err != nil {
 		return nil, err
 	}
@@ -299,12 +301,16 @@ func initCRowFetcher(
 	memoryLimit int64,
 	fetcher *cFetcher,
 	desc catalog.TableDescriptor,
+	indexIdx int,
 	colIdxMap catalog.TableColMap,
 	valNeededForCol util.FastIntSet,
-	spec *execinfrapb.TableReaderSpec,
+	reverse bool,
+	visibility execinfrapb.ScanVisibility,
+	lockStrength descpb.ScanLockingStrength,
+	lockWaitPolicy descpb.ScanLockingWaitPolicy,
+	virtualColumn catalog.Column,
 	withSystemColumns bool,
 ) (index *descpb.IndexDescriptor, isSecondaryIndex bool, err error) {
-	indexIdx := int(spec.IndexIdx)
 	if indexIdx >= len(desc.ActiveIndexes()) {
 		return nil, false, errors.Errorf("invalid indexIdx %d", indexIdx)
 	}
@@ -320,11 +326,10 @@ func initCRowFetcher(
 		ValNeededForCol:  valNeededForCol,
 	}
 
-	virtualColumn := tabledesc.FindVirtualColumn(desc, spec.VirtualColumn)
-	tableArgs.InitCols(desc, spec.Visibility, withSystemColumns, virtualColumn)
+	tableArgs.InitCols(desc, visibility, withSystemColumns, virtualColumn)
 
 	if err := fetcher.Init(
-		codec, allocator, memoryLimit, spec.Reverse, spec.LockingStrength, spec.LockingWaitPolicy, tableArgs,
+		codec, allocator, memoryLimit, reverse, lockStrength, lockWaitPolicy, tableArgs,
 	); err != nil {
 		return nil, false, err
 	}
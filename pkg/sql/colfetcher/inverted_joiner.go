@@ -0,0 +1,775 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colfetcher
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedidx"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/span"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// colInvertedJoinerState represents the state of the ColInvertedJoiner.
+type colInvertedJoinerState int
+
+const (
+	// cijReadingInput means that a batch of input rows is being buffered.
+	cijReadingInput colInvertedJoinerState = iota
+	// cijPerformingScan means that the inverted index is being scanned for the
+	// current batch of input rows.
+	cijPerformingScan
+	// cijEmittingRows means that the joined rows for the current batch of
+	// input rows are being emitted.
+	cijEmittingRows
+	// cijDone means that the input has been exhausted.
+	cijDone
+)
+
+// colInvertedJoinerBatchSize is the minimum number of input rows that are
+// buffered before the inverted index is scanned.
+var colInvertedJoinerBatchSize = util.ConstantWithMetamorphicTestValue(
+	"col-inverted-joiner-batch-size",
+	1024, /* defaultValue */
+	1,    /* metamorphicValue */
+)
+
+// lookedUpKeysEntryOverhead is the memory footprint of an entry in the map
+// that de-duplicates the looked up rows, not including the key bytes.
+const lookedUpKeysEntryOverhead = int64(unsafe.Sizeof("") + unsafe.Sizeof(inverted.KeyIndex(0)))
+
+// ColInvertedJoiner is the exec.Operator implementation of the InvertedJoiner:
+// it performs a join between its input and an inverted index of a table. The
+// input rows are buffered in batches, and for each batch an inverted
+// expression is computed for every input row. The union of the spans of these
+// expressions is scanned by a cFetcher which puts the encoded inverted keys
+// into a Bytes vector, the looked up rows are de-duplicated, and the inverted
+// expressions are evaluated to find the matching rows.
+//
+// NOTE: the looked up rows of a single batch of input rows are kept in memory.
+type ColInvertedJoiner struct {
+	colexecop.OneInputNode
+	flowCtx *execinfra.FlowCtx
+	rf      *cFetcher
+
+	// allocator is used for the output batches, and bufferingAllocator for the
+	// buffered input and looked up rows.
+	allocator          *colmem.Allocator
+	bufferingAllocator *colmem.Allocator
+	// memoryLimit is the memory limit for the output batches.
+	memoryLimit int64
+	output      coldata.Batch
+
+	state     colInvertedJoinerState
+	joinType  descpb.JoinType
+	batchSize int
+
+	inputTypes []*types.T
+	// inputColIdxs contains the ordinals of all input columns.
+	inputColIdxs []int
+	// inputRows contains the current batch of input rows.
+	inputRows *colexecutils.AppendOnlyBufferedBatch
+	// inputDone is true once the input has been exhausted.
+	inputDone      bool
+	inputConverter *colconv.VecToDatumConverter
+
+	datumsToInvertedExpr invertedexpr.DatumsToInvertedExpr
+	canPreFilter         bool
+	batchedExprEval      inverted.BatchedExprEvaluator
+
+	table       catalog.TableDescriptor
+	index       *descpb.IndexDescriptor
+	spanBuilder *span.Builder
+	// prefixEqualityCols contains the ordinals of the input columns that are
+	// equal to the non-inverted prefix columns of a multi-column inverted
+	// index, and prefixColIdxs contains the ordinals of those prefix columns
+	// among the looked up columns.
+	prefixEqualityCols []uint32
+	prefixColIdxs      []int
+	prefixTypes        []*types.T
+	prefixRow          rowenc.EncDatumRow
+
+	// lookedUpTypes are the types of the columns of the table where the
+	// inverted column has Bytes type, and invertedColIdx is the ordinal of the
+	// inverted column.
+	lookedUpTypes  []*types.T
+	invertedColIdx int
+	// keyColIdxs contains the ordinals of the non-inverted index columns. The
+	// looked up rows are de-duplicated on these columns.
+	keyColIdxs        []int
+	lookedUpConverter *colconv.VecToDatumConverter
+	// lookedUpRows contains the de-duplicated looked up rows for the current
+	// batch of input rows.
+	lookedUpRows          *colexecutils.AppendOnlyBufferedBatch
+	lookedUpRowsConverter *colconv.VecToDatumConverter
+	// lookedUpKeys maps the fingerprint of the key columns of a looked up row
+	// to the index of that row in lookedUpRows.
+	lookedUpKeys       map[string]inverted.KeyIndex
+	lookedUpKeysMemory int64
+	newLookedUpSel     []int
+	fingerprint        []byte
+	datumAlloc         rowenc.DatumAlloc
+
+	onExpr      execinfrapb.ExprHelper
+	combinedRow rowenc.EncDatumRow
+
+	// emitState contains the joined rows that are yet to be emitted. Each row
+	// joins the input row and the looked up row at the same position in
+	// leftIdxs and rightIdxs. A negative right index denotes a looked up row
+	// made of NULLs.
+	emitState struct {
+		leftIdxs  []int
+		rightIdxs []int
+		cursor    int
+		scratch   []int
+	}
+
+	// tracingSpan is created when the stats should be collected for the query
+	// execution, and it will be finished when closing the operator.
+	tracingSpan *tracing.Span
+	mu          struct {
+		syncutil.Mutex
+		ctx context.Context
+		// init is true after Init() has been called.
+		init bool
+		// rowsRead contains the number of total rows this ColInvertedJoiner
+		// has read from the inverted index so far.
+		rowsRead int64
+		// bytesRead contains the number of bytes read by the previous scans.
+		bytesRead int64
+	}
+
+	// ResultTypes is the slice of resulting column types from this operator.
+	ResultTypes []*types.T
+}
+
+var _ colexecop.KVReader = &ColInvertedJoiner{}
+var _ execinfra.Releasable = &ColInvertedJoiner{}
+var _ colexecop.Closer = &ColInvertedJoiner{}
+var _ colexecop.DrainableOperator = &ColInvertedJoiner{}
+
+// Init initializes a ColInvertedJoiner.
+func (ij *ColInvertedJoiner) Init() {
+	ij.Input.Init()
+	ij.mu.Lock()
+	defer ij.mu.Unlock()
+	ij.mu.init = true
+}
+
+// Next is part of the Operator interface.
+func (ij *ColInvertedJoiner) Next(ctx context.Context) coldata.Batch {
+	ij.mu.Lock()
+	if ij.mu.ctx == nil {
+		// This is the first call to Next(), so we will capture the context and
+		// possibly replace it with a child below.
+		ij.mu.ctx = ctx
+		if execinfra.ShouldCollectStats(ij.mu.ctx, ij.flowCtx) {
+			// We need to start a child span so that the only contention events
+			// present in the recording would be because of this cFetcher.
+			ij.mu.ctx, ij.tracingSpan = execinfra.ProcessorSpan(ij.mu.ctx, "colinvertedjoiner")
+		}
+	}
+	ctx = ij.mu.ctx
+	ij.mu.Unlock()
+	for {
+		switch ij.state {
+		case cijReadingInput:
+			ij.state = ij.readInput(ctx)
+		case cijPerformingScan:
+			batch, err := ij.rf.NextBatch(ctx)
+			if err != nil {
+				colexecerror.InternalError(err)
+			}
+			if batch.Length() == 0 {
+				log.VEvent(ctx, 1, "done scanning the inverted index")
+				ij.prepareToEmit(ij.batchedExprEval.Evaluate())
+				ij.state = cijEmittingRows
+				continue
+			}
+			ij.mu.Lock()
+			ij.mu.rowsRead += int64(batch.Length())
+			ij.mu.Unlock()
+			ij.addLookedUpBatch(ctx, batch)
+		case cijEmittingRows:
+			if ij.emitState.cursor < len(ij.emitState.leftIdxs) {
+				return ij.emitJoinedRows()
+			}
+			ij.resetBatchState()
+			ij.state = cijReadingInput
+		case cijDone:
+			return coldata.ZeroBatch
+		default:
+			colexecerror.InternalError(errors.AssertionFailedf("unsupported state: %d", ij.state))
+		}
+	}
+}
+
+// readInput buffers the next batch of input rows, computes the inverted
+// expressions for them, and starts the scan of the inverted index.
+func (ij *ColInvertedJoiner) readInput(ctx context.Context) colInvertedJoinerState {
+	for !ij.inputDone && ij.inputRows.Length() < ij.batchSize {
+		batch := ij.Input.Next(ctx)
+		n := batch.Length()
+		if n == 0 {
+			ij.inputDone = true
+			break
+		}
+		ij.bufferingAllocator.PerformOperation(ij.inputRows.ColVecs(), func() {
+			ij.inputRows.AppendTuples(batch, 0 /* startIdx */, n)
+		})
+	}
+	n := ij.inputRows.Length()
+	if n == 0 {
+		log.VEvent(ctx, 1, "no more input rows")
+		return cijDone
+	}
+	log.VEventf(ctx, 1, "read %d input rows", n)
+
+	ij.inputConverter.ConvertVecs(ij.inputRows.ColVecs(), n, nil /* sel */)
+	inputRow := ij.combinedRow[:len(ij.inputTypes)]
+	for i := 0; i < n; i++ {
+		ij.setInputRow(inputRow, i)
+		expr, preFilterState, err := ij.datumsToInvertedExpr.Convert(ctx, inputRow)
+		if err != nil {
+			colexecerror.ExpectedError(err)
+		}
+		// A nil expression means that one of the input columns was NULL, and
+		// it serves as a marker that will result in an empty set as the
+		// evaluation result.
+		ij.batchedExprEval.Exprs = append(ij.batchedExprEval.Exprs, expr)
+		if ij.canPreFilter {
+			if expr == nil {
+				preFilterState = nil
+			}
+			ij.batchedExprEval.PreFilterState = append(ij.batchedExprEval.PreFilterState, preFilterState)
+		}
+		if len(ij.prefixEqualityCols) > 0 {
+			if expr == nil {
+				// The evaluation result will be an empty set, so don't bother
+				// creating a prefix key span.
+				ij.batchedExprEval.NonInvertedPrefixes = append(ij.batchedExprEval.NonInvertedPrefixes, roachpb.Key{})
+				continue
+			}
+			for prefixIdx, colIdx := range ij.prefixEqualityCols {
+				ij.prefixRow[prefixIdx] = inputRow[colIdx]
+			}
+			ij.batchedExprEval.NonInvertedPrefixes = append(ij.batchedExprEval.NonInvertedPrefixes, ij.makePrefixKey())
+		}
+	}
+
+	spans, err := ij.batchedExprEval.Init()
+	if err != nil {
+		colexecerror.ExpectedError(err)
+	}
+	if len(spans) == 0 {
+		// Nothing to scan, so none of the input rows have matches.
+		ij.prepareToEmit(nil /* joinedRowIdxs */)
+		return cijEmittingRows
+	}
+	// NB: spans is already sorted, and that sorting is preserved when
+	// generating indexSpans.
+	indexSpans, err := ij.spanBuilder.SpansFromInvertedSpans(spans, nil /* constraint */)
+	if err != nil {
+		colexecerror.InternalError(err)
+	}
+
+	log.VEventf(ctx, 1, "scanning %d spans", len(indexSpans))
+	ij.mu.Lock()
+	defer ij.mu.Unlock()
+	// The cFetcher creates a new KV fetcher for every scan, so the bytes read
+	// by the previous scan need to be accumulated first.
+	ij.mu.bytesRead += ij.rf.fetcher.GetBytesRead()
+	if err := ij.rf.StartScan(
+		ij.flowCtx.Txn, indexSpans, false /* limitBatches */, 0, /* limitHint */
+		ij.flowCtx.TraceKV, ij.flowCtx.EvalCtx.TestingKnobs.ForceProductionBatchSizes,
+	); err != nil {
+		colexecerror.InternalError(err)
+	}
+	return cijPerformingScan
+}
+
+// setInputRow populates row with the input row with the given index from the
+// last conversion of the input rows.
+func (ij *ColInvertedJoiner) setInputRow(row rowenc.EncDatumRow, rowIdx int) {
+	for i, typ := range ij.inputTypes {
+		row[i] = rowenc.DatumToEncDatum(typ, ij.inputConverter.GetDatumColumn(i)[rowIdx])
+	}
+}
+
+// makePrefixKey encodes ij.prefixRow into the key prefix of the inverted
+// index.
+func (ij *ColInvertedJoiner) makePrefixKey() roachpb.Key {
+	prefixKey, _, _, err := rowenc.MakeKeyFromEncDatums(
+		ij.prefixRow, ij.prefixTypes, ij.index.ColumnDirections, ij.table, ij.index,
+		&ij.datumAlloc, nil, /* keyPrefix */
+	)
+	if err != nil {
+		colexecerror.InternalError(err)
+	}
+	return prefixKey
+}
+
+// addLookedUpBatch adds the looked up rows of the given batch to the inverted
+// expression evaluator, buffering the rows that haven't been seen before.
+func (ij *ColInvertedJoiner) addLookedUpBatch(ctx context.Context, batch coldata.Batch) {
+	n := batch.Length()
+	ij.lookedUpConverter.ConvertVecs(batch.ColVecs(), n, nil /* sel */)
+	invertedKeys := batch.ColVec(ij.invertedColIdx).Bytes()
+	ij.newLookedUpSel = ij.newLookedUpSel[:0]
+	var memUsage int64
+	for i := 0; i < n; i++ {
+		// NB: inverted columns are custom encoded in a manner that does not
+		// correspond to Datum encoding, and we want only the encoded bytes.
+		encInvertedVal := invertedKeys.Get(i)
+		var encFullVal []byte
+		if len(ij.prefixEqualityCols) > 0 {
+			for prefixIdx, colIdx := range ij.prefixColIdxs {
+				ij.prefixRow[prefixIdx] = rowenc.DatumToEncDatum(
+					ij.prefixTypes[prefixIdx], ij.lookedUpConverter.GetDatumColumn(colIdx)[i],
+				)
+			}
+			// We append the encoded inverted value to the key prefix
+			// representing the non-inverted prefix columns, to generate the
+			// key for the inverted index.
+			encFullVal = append(ij.makePrefixKey(), encInvertedVal...)
+		}
+		shouldAdd, err := ij.batchedExprEval.PrepareAddIndexRow(encInvertedVal, encFullVal)
+		if err != nil {
+			colexecerror.ExpectedError(err)
+		}
+		if !shouldAdd {
+			continue
+		}
+		ij.fingerprint = ij.fingerprint[:0]
+		for _, colIdx := range ij.keyColIdxs {
+			encDatum := rowenc.DatumToEncDatum(ij.lookedUpTypes[colIdx], ij.lookedUpConverter.GetDatumColumn(colIdx)[i])
+			ij.fingerprint, err = encDatum.Fingerprint(ctx, ij.lookedUpTypes[colIdx], &ij.datumAlloc, ij.fingerprint, nil /* acc */)
+			if err != nil {
+				colexecerror.ExpectedError(err)
+			}
+		}
+		keyIndex, ok := ij.lookedUpKeys[string(ij.fingerprint)]
+		if !ok {
+			keyIndex = ij.lookedUpRows.Length() + len(ij.newLookedUpSel)
+			ij.lookedUpKeys[string(ij.fingerprint)] = keyIndex
+			ij.newLookedUpSel = append(ij.newLookedUpSel, i)
+			memUsage += int64(len(ij.fingerprint)) + lookedUpKeysEntryOverhead
+		}
+		if err = ij.batchedExprEval.AddIndexRow(keyIndex); err != nil {
+			colexecerror.ExpectedError(err)
+		}
+	}
+	ij.bufferingAllocator.AdjustMemoryUsage(memUsage)
+	ij.lookedUpKeysMemory += memUsage
+	if numNew := len(ij.newLookedUpSel); numNew > 0 {
+		ij.bufferingAllocator.PerformOperation(ij.lookedUpRows.ColVecs(), func() {
+			for _, colIdx := range ij.keyColIdxs {
+				ij.lookedUpRows.ColVec(colIdx).Append(coldata.SliceArgs{
+					Src:       batch.ColVec(colIdx),
+					Sel:       ij.newLookedUpSel,
+					DestIdx:   ij.lookedUpRows.Length(),
+					SrcEndIdx: numNew,
+				})
+			}
+		})
+		ij.lookedUpRows.SetLength(ij.lookedUpRows.Length() + numNew)
+	}
+}
+
+// prepareToEmit populates emitState with the joined rows given the evaluation
+// result of the inverted expressions, in which joinedRowIdxs[i] contains the
+// indices of the looked up rows that match the i-th input row.
+func (ij *ColInvertedJoiner) prepareToEmit(joinedRowIdxs [][]inverted.KeyIndex) {
+	hasOnExpr := ij.onExpr.Expr != nil
+	if hasOnExpr && ij.lookedUpRows.Length() > 0 {
+		ij.lookedUpRowsConverter.ConvertVecs(ij.lookedUpRows.ColVecs(), ij.lookedUpRows.Length(), nil /* sel */)
+	}
+	for leftIdx := 0; leftIdx < ij.inputRows.Length(); leftIdx++ {
+		var matches []inverted.KeyIndex
+		if joinedRowIdxs != nil {
+			matches = joinedRowIdxs[leftIdx]
+		}
+		if hasOnExpr && len(matches) > 0 {
+			ij.setInputRow(ij.combinedRow[:len(ij.inputTypes)], leftIdx)
+		}
+		seenMatch := false
+	MatchLoop:
+		for _, rightIdx := range matches {
+			if hasOnExpr && !ij.evalOnExpr(rightIdx) {
+				continue
+			}
+			seenMatch = true
+			switch ij.joinType {
+			case descpb.InnerJoin, descpb.LeftOuterJoin:
+				ij.addJoinedRow(leftIdx, rightIdx)
+			case descpb.LeftSemiJoin:
+				ij.addJoinedRow(leftIdx, -1 /* rightIdx */)
+				break MatchLoop
+			case descpb.LeftAntiJoin:
+				break MatchLoop
+			}
+		}
+		if !seenMatch && (ij.joinType == descpb.LeftOuterJoin || ij.joinType == descpb.LeftAntiJoin) {
+			ij.addJoinedRow(leftIdx, -1 /* rightIdx */)
+		}
+	}
+}
+
+// evalOnExpr evaluates the ON expression on the current input row (which has
+// already been set in combinedRow) and the looked up row with the given index.
+func (ij *ColInvertedJoiner) evalOnExpr(rightIdx int) bool {
+	rightRow := ij.combinedRow[len(ij.inputTypes):]
+	for _, colIdx := range ij.keyColIdxs {
+		rightRow[colIdx] = rowenc.DatumToEncDatum(
+			ij.lookedUpTypes[colIdx], ij.lookedUpRowsConverter.GetDatumColumn(colIdx)[rightIdx],
+		)
+	}
+	pass, err := ij.onExpr.EvalFilter(ij.combinedRow)
+	if err != nil {
+		colexecerror.ExpectedError(err)
+	}
+	return pass
+}
+
+// addJoinedRow adds a row joining the input row and the looked up row with the
+// given indices to the rows to emit. A negative rightIdx denotes a looked up
+// row made of NULLs.
+func (ij *ColInvertedJoiner) addJoinedRow(leftIdx, rightIdx int) {
+	ij.emitState.leftIdxs = append(ij.emitState.leftIdxs, leftIdx)
+	ij.emitState.rightIdxs = append(ij.emitState.rightIdxs, rightIdx)
+}
+
+// resetBatchState prepares the ColInvertedJoiner for the next batch of input
+// rows.
+func (ij *ColInvertedJoiner) resetBatchState() {
+	ij.inputRows.ResetInternalBatch()
+	ij.lookedUpRows.ResetInternalBatch()
+	// This loop gets optimized to a runtime.mapclear call.
+	for k := range ij.lookedUpKeys {
+		delete(ij.lookedUpKeys, k)
+	}
+	ij.bufferingAllocator.ReleaseMemory(ij.lookedUpKeysMemory)
+	ij.lookedUpKeysMemory = 0
+	ij.batchedExprEval.Reset()
+	ij.emitState.leftIdxs = ij.emitState.leftIdxs[:0]
+	ij.emitState.rightIdxs = ij.emitState.rightIdxs[:0]
+	ij.emitState.cursor = 0
+}
+
+// emitJoinedRows returns the next batch of joined rows from emitState.
+func (ij *ColInvertedJoiner) emitJoinedRows() coldata.Batch {
+	start := ij.emitState.cursor
+	n := len(ij.emitState.leftIdxs) - start
+	if n > coldata.BatchSize() {
+		n = coldata.BatchSize()
+	}
+	ij.emitState.cursor += n
+	ij.output, _ = ij.allocator.ResetMaybeReallocate(ij.ResultTypes, ij.output, n, ij.memoryLimit)
+	leftSel := ij.emitState.leftIdxs[start : start+n]
+	ij.allocator.PerformOperation(ij.output.ColVecs(), func() {
+		var rightColIdxs []int
+		if ij.joinType.ShouldIncludeRightColsInOutput() {
+			rightColIdxs = ij.keyColIdxs
+		}
+		ij.emitState.scratch = copyJoinedRows(
+			ij.output, ij.inputRows, ij.inputColIdxs, leftSel, ij.lookedUpRows,
+			len(ij.inputTypes), rightColIdxs, ij.emitState.rightIdxs[start:start+n], ij.emitState.scratch,
+		)
+		if rightColIdxs != nil {
+			// The columns that are not stored in the inverted index are NULL.
+			for i := len(ij.inputTypes); i < len(ij.ResultTypes); i++ {
+				if !ij.isKeyCol(i - len(ij.inputTypes)) {
+					ij.output.ColVec(i).Nulls().SetNulls()
+				}
+			}
+		}
+	})
+	ij.output.SetLength(n)
+	return ij.output
+}
+
+// isKeyCol returns whether the looked up column with the given ordinal is one
+// of the non-inverted index columns.
+func (ij *ColInvertedJoiner) isKeyCol(colIdx int) bool {
+	for _, idx := range ij.keyColIdxs {
+		if idx == colIdx {
+			return true
+		}
+	}
+	return false
+}
+
+// DrainMeta is part of the colexecop.MetadataSource interface.
+func (ij *ColInvertedJoiner) DrainMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
+	ij.mu.Lock()
+	initialized := ij.mu.init
+	ij.mu.Unlock()
+	if !initialized {
+		return nil
+	}
+	var trailingMeta []execinfrapb.ProducerMetadata
+	if tfs := execinfra.GetLeafTxnFinalState(ctx, ij.flowCtx.Txn); tfs != nil {
+		trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{LeafTxnFinalState: tfs})
+	}
+	meta := execinfrapb.GetProducerMeta()
+	meta.Metrics = execinfrapb.GetMetricsMeta()
+	meta.Metrics.BytesRead = ij.GetBytesRead()
+	meta.Metrics.RowsRead = ij.GetRowsRead()
+	trailingMeta = append(trailingMeta, *meta)
+	if ij.tracingSpan != nil {
+		// If tracingSpan is non-nil, then we have derived a new context in
+		// Next() and we have to collect the trace data. See the comment in
+		// ColBatchScan.DrainMeta for more details.
+		ij.mu.Lock()
+		traceCtx := ij.mu.ctx
+		ij.mu.Unlock()
+		if trace := execinfra.GetTraceData(traceCtx); trace != nil {
+			trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{TraceData: trace})
+		}
+	}
+	return trailingMeta
+}
+
+// GetBytesRead is part of the colexecop.KVReader interface.
+func (ij *ColInvertedJoiner) GetBytesRead() int64 {
+	ij.mu.Lock()
+	defer ij.mu.Unlock()
+	return ij.mu.bytesRead + ij.rf.fetcher.GetBytesRead()
+}
+
+// GetRowsRead is part of the colexecop.KVReader interface.
+func (ij *ColInvertedJoiner) GetRowsRead() int64 {
+	ij.mu.Lock()
+	defer ij.mu.Unlock()
+	return ij.mu.rowsRead
+}
+
+// GetCumulativeContentionTime is part of the colexecop.KVReader interface.
+func (ij *ColInvertedJoiner) GetCumulativeContentionTime() time.Duration {
+	ij.mu.Lock()
+	defer ij.mu.Unlock()
+	if ij.mu.ctx == nil {
+		// Next was never called, so there was no contention events.
+		return 0
+	}
+	return execinfra.GetCumulativeContentionTime(ij.mu.ctx)
+}
+
+var colInvertedJoinerPool = sync.Pool{
+	New: func() interface{} {
+		return &ColInvertedJoiner{}
+	},
+}
+
+// NewColInvertedJoiner creates a new ColInvertedJoiner operator. The allocator
+// is used for the output batches, and the buffering allocator for the
+// buffered input and looked up rows.
+func NewColInvertedJoiner(
+	ctx context.Context,
+	allocator *colmem.Allocator,
+	bufferingAllocator *colmem.Allocator,
+	flowCtx *execinfra.FlowCtx,
+	evalCtx *tree.EvalContext,
+	input colexecop.Operator,
+	inputTypes []*types.T,
+	spec *execinfrapb.InvertedJoinerSpec,
+) (*ColInvertedJoiner, error) {
+	switch spec.Type {
+	case descpb.InnerJoin, descpb.LeftOuterJoin, descpb.LeftSemiJoin, descpb.LeftAntiJoin:
+	default:
+		return nil, errors.AssertionFailedf("unexpected inverted join type %s", spec.Type)
+	}
+	if spec.OutputGroupContinuationForLeftRow {
+		return nil, errors.AssertionFailedf("paired joins are not supported")
+	}
+
+	table := spec.BuildTableDescriptor()
+	indexIdx := int(spec.IndexIdx)
+	if indexIdx >= len(table.ActiveIndexes()) {
+		return nil, errors.Errorf("invalid indexIdx %d", indexIdx)
+	}
+	index := table.ActiveIndexes()[indexIdx].IndexDesc()
+	invertedColID := index.InvertedColumnID()
+	invertedCol, err := table.FindColumnWithID(invertedColID)
+	if err != nil {
+		return nil, err
+	}
+	// The inverted column is fetched as a virtual column of Bytes type that
+	// contains the encoded inverted keys.
+	virtualColDesc := *invertedCol.ColumnDesc()
+	virtualColDesc.Type = types.Bytes
+	virtualColumn := tabledesc.FindVirtualColumn(table, &virtualColDesc)
+
+	// Inverted joins are not used for mutations.
+	cols := table.PublicColumns()
+	columnIdxMap := catalog.ColumnIDToOrdinalMap(cols)
+	rightTypes := catalog.ColumnTypes(cols)
+	lookedUpTypes := catalog.ColumnTypesWithVirtualCol(cols, virtualColumn)
+	// Before we can safely use types from the table descriptor, we need to
+	// make sure they are hydrated. See the comment in NewColBatchScan.
+	resolver := flowCtx.TypeResolverFactory.NewTypeResolver(evalCtx.Txn)
+	if err := resolver.HydrateTypeSlice(ctx, rightTypes); err != nil {
+		return nil, err
+	}
+	if err := resolver.HydrateTypeSlice(ctx, lookedUpTypes); err != nil {
+		return nil, err
+	}
+
+	// In general we need all the columns in the index to compute the set
+	// expression.
+	indexColumnIDs, _ := index.FullColumnIDs()
+	var allIndexCols util.FastIntSet
+	var keyColIdxs []int
+	for _, colID := range indexColumnIDs {
+		colIdx := columnIdxMap.GetDefault(colID)
+		allIndexCols.Add(colIdx)
+		if colID != invertedColID {
+			keyColIdxs = append(keyColIdxs, colIdx)
+		}
+	}
+	prefixColIdxs := make([]int, len(spec.PrefixEqualityColumns))
+	prefixTypes := make([]*types.T, len(spec.PrefixEqualityColumns))
+	for i := range prefixColIdxs {
+		prefixColIdxs[i] = columnIdxMap.GetDefault(indexColumnIDs[i])
+		prefixTypes[i] = lookedUpTypes[prefixColIdxs[i]]
+	}
+
+	var resultTypes []*types.T
+	if spec.Type.ShouldIncludeRightColsInOutput() {
+		resultTypes = make([]*types.T, 0, len(inputTypes)+len(rightTypes))
+		resultTypes = append(resultTypes, inputTypes...)
+		resultTypes = append(resultTypes, rightTypes...)
+	} else {
+		resultTypes = inputTypes
+	}
+
+	semaCtx := flowCtx.TypeResolverFactory.NewSemaContext(evalCtx.Txn)
+	onExprColTypes := make([]*types.T, 0, len(inputTypes)+len(rightTypes))
+	onExprColTypes = append(onExprColTypes, inputTypes...)
+	onExprColTypes = append(onExprColTypes, rightTypes...)
+	var invertedExprHelper execinfrapb.ExprHelper
+	if err := invertedExprHelper.Init(spec.InvertedExpr, onExprColTypes, semaCtx, evalCtx); err != nil {
+		return nil, err
+	}
+	datumsToInvertedExpr, err := invertedidx.NewDatumsToInvertedExpr(
+		evalCtx, onExprColTypes, invertedExprHelper.Expr, index,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fetcher := cFetcherPool.Get().(*cFetcher)
+	if _, _, err := initCRowFetcher(
+		flowCtx.Codec(), allocator, execinfra.GetWorkMemLimit(flowCtx.Cfg),
+		fetcher, table, indexIdx, columnIdxMap, allIndexCols, false, /* reverse */
+		execinfra.ScanVisibilityPublic, descpb.ScanLockingStrength_FOR_NONE,
+		descpb.ScanLockingWaitPolicy_BLOCK, virtualColumn, false, /* withSystemColumns */
+	); err != nil {
+		return nil, err
+	}
+
+	spanBuilder := span.MakeBuilder(evalCtx, flowCtx.Codec(), table, index)
+	spanBuilder.SetNeededColumns(allIndexCols)
+
+	ij := colInvertedJoinerPool.Get().(*ColInvertedJoiner)
+	*ij = ColInvertedJoiner{
+		OneInputNode:          colexecop.NewOneInputNode(input),
+		flowCtx:               flowCtx,
+		rf:                    fetcher,
+		allocator:             allocator,
+		bufferingAllocator:    bufferingAllocator,
+		memoryLimit:           execinfra.GetWorkMemLimit(flowCtx.Cfg),
+		joinType:              spec.Type,
+		batchSize:             colInvertedJoinerBatchSize,
+		inputTypes:            inputTypes,
+		inputColIdxs:          makeOrdinals(len(inputTypes)),
+		inputRows:             colexecutils.NewAppendOnlyBufferedBatch(bufferingAllocator, inputTypes, nil /* colsToStore */),
+		inputConverter:        colconv.NewAllVecToDatumConverter(len(inputTypes)),
+		datumsToInvertedExpr:  datumsToInvertedExpr,
+		canPreFilter:          datumsToInvertedExpr.CanPreFilter(),
+		table:                 table,
+		index:                 index,
+		spanBuilder:           spanBuilder,
+		prefixEqualityCols:    spec.PrefixEqualityColumns,
+		prefixColIdxs:         prefixColIdxs,
+		prefixTypes:           prefixTypes,
+		prefixRow:             make(rowenc.EncDatumRow, len(prefixColIdxs)),
+		lookedUpTypes:         lookedUpTypes,
+		invertedColIdx:        columnIdxMap.GetDefault(invertedColID),
+		keyColIdxs:            keyColIdxs,
+		lookedUpConverter:     colconv.NewVecToDatumConverter(len(lookedUpTypes), keyColIdxs),
+		lookedUpRows:          colexecutils.NewAppendOnlyBufferedBatch(bufferingAllocator, lookedUpTypes, keyColIdxs),
+		lookedUpRowsConverter: colconv.NewVecToDatumConverter(len(lookedUpTypes), keyColIdxs),
+		lookedUpKeys:          make(map[string]inverted.KeyIndex),
+		ResultTypes:           resultTypes,
+	}
+	if ij.canPreFilter {
+		ij.batchedExprEval.Filterer = datumsToInvertedExpr
+	}
+	if err := ij.onExpr.Init(spec.OnExpr, onExprColTypes, semaCtx, evalCtx); err != nil {
+		return nil, err
+	}
+	ij.combinedRow = make(rowenc.EncDatumRow, len(onExprColTypes))
+	for i := len(inputTypes); i < len(onExprColTypes); i++ {
+		// Only the index columns of the looked up rows are available, so the
+		// remaining ones are NULL.
+		ij.combinedRow[i] = rowenc.DatumToEncDatum(onExprColTypes[i], tree.DNull)
+	}
+	return ij, nil
+}
+
+// SetBatchSize sets the desired batch size. It should only be used in tests.
+func (ij *ColInvertedJoiner) SetBatchSize(batchSize int) {
+	ij.batchSize = batchSize
+}
+
+// Release implements the execinfra.Releasable interface.
+func (ij *ColInvertedJoiner) Release() {
+	ij.rf.Release()
+	ij.inputConverter.Release()
+	ij.lookedUpConverter.Release()
+	ij.lookedUpRowsConverter.Release()
+	*ij = ColInvertedJoiner{}
+	colInvertedJoinerPool.Put(ij)
+}
+
+// Close implements the colexecop.Closer interface.
+func (ij *ColInvertedJoiner) Close(context.Context) error {
+	if ij.tracingSpan != nil {
+		ij.tracingSpan.Finish()
+		ij.tracingSpan = nil
+	}
+	return nil
+}
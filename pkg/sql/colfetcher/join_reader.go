@@ -0,0 +1,812 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colfetcher
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/span"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// colJoinReaderState represents the state of the ColJoinReader.
+type colJoinReaderState int
+
+const (
+	// cjrReadingInput means that a batch of input rows is being buffered.
+	cjrReadingInput colJoinReaderState = iota
+	// cjrPerformingLookup means that the rows for the current batch of input
+	// rows are being looked up.
+	cjrPerformingLookup
+	// cjrEmittingLookedUpRows means that the rows joined with the last batch of
+	// looked up rows are being emitted, after which the lookup continues.
+	cjrEmittingLookedUpRows
+	// cjrEmittingRows means that all rows have been looked up for the current
+	// batch of input rows, and the remaining joined rows are being emitted.
+	cjrEmittingRows
+	// cjrDone means that the input has been exhausted.
+	cjrDone
+)
+
+// ColJoinReader is the exec.Operator implementation of the JoinReader: it
+// performs a lookup or an index join between its input and an index of a
+// table. The input rows are buffered in batches, the spans to look up are
+// generated for each batch, and the looked up KVs are decoded directly into
+// vectors by a cFetcher.
+type ColJoinReader struct {
+	colexecop.OneInputNode
+	flowCtx *execinfra.FlowCtx
+	rf      *cFetcher
+
+	// allocator is used for the output batches.
+	allocator *colmem.Allocator
+	// memoryLimit is the memory limit for the output batches.
+	memoryLimit int64
+	output      coldata.Batch
+
+	state    colJoinReaderState
+	strategy colJoinReaderStrategy
+	spanGen  colJoinReaderSpanGenerator
+	joinType descpb.JoinType
+
+	// isIndexJoin is true if the ColJoinReader performs an index join, in
+	// which case the input rows are ignored and the looked up rows are output
+	// as is.
+	isIndexJoin      bool
+	maintainOrdering bool
+	// shouldLimitBatches is true if the number of KVs returned by a single
+	// lookup must be limited, which is the case when the lookup columns don't
+	// form a key.
+	shouldLimitBatches bool
+
+	inputTypes []*types.T
+	// inputColIdxs contains the ordinals of all input columns.
+	inputColIdxs []int
+	// inputRows contains the current batch of input rows.
+	inputRows *colexecutils.AppendOnlyBufferedBatch
+	// inputRowsSizeBytes is the size of the current batch of input rows.
+	inputRowsSizeBytes int64
+	// batchSizeBytes is the target size of a batch of input rows.
+	batchSizeBytes int64
+	// inputDone is true once the input has been exhausted.
+	inputDone bool
+
+	// neededRightCols contains the ordinals of the looked up columns that need
+	// to be emitted.
+	neededRightCols []int
+
+	// emitState contains the joined rows that are yet to be emitted. Each row
+	// joins the input row and the looked up row (from rightRows) at the same
+	// position in leftIdxs and rightIdxs. A negative right index denotes a
+	// looked up row made of NULLs.
+	emitState struct {
+		leftIdxs  []int
+		rightIdxs []int
+		rightRows coldata.Batch
+		cursor    int
+		scratch   []int
+	}
+
+	// tracingSpan is created when the stats should be collected for the query
+	// execution, and it will be finished when closing the operator.
+	tracingSpan *tracing.Span
+	mu          struct {
+		syncutil.Mutex
+		ctx context.Context
+		// init is true after Init() has been called.
+		init bool
+		// rowsRead contains the number of total rows this ColJoinReader has
+		// looked up so far.
+		rowsRead int64
+		// bytesRead contains the number of bytes read by the previous lookups.
+		bytesRead int64
+	}
+
+	// ResultTypes is the slice of resulting column types from this operator.
+	ResultTypes []*types.T
+}
+
+var _ colexecop.KVReader = &ColJoinReader{}
+var _ execinfra.Releasable = &ColJoinReader{}
+var _ colexecop.Closer = &ColJoinReader{}
+var _ colexecop.DrainableOperator = &ColJoinReader{}
+
+// Init initializes a ColJoinReader.
+func (j *ColJoinReader) Init() {
+	j.Input.Init()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.mu.init = true
+}
+
+// Next is part of the Operator interface.
+func (j *ColJoinReader) Next(ctx context.Context) coldata.Batch {
+	j.mu.Lock()
+	if j.mu.ctx == nil {
+		// This is the first call to Next(), so we will capture the context and
+		// possibly replace it with a child below.
+		j.mu.ctx = ctx
+		if execinfra.ShouldCollectStats(j.mu.ctx, j.flowCtx) {
+			// We need to start a child span so that the only contention events
+			// present in the recording would be because of this cFetcher.
+			j.mu.ctx, j.tracingSpan = execinfra.ProcessorSpan(j.mu.ctx, "coljoinreader")
+		}
+	}
+	ctx = j.mu.ctx
+	j.mu.Unlock()
+	for {
+		switch j.state {
+		case cjrReadingInput:
+			j.state = j.readInput(ctx)
+		case cjrPerformingLookup:
+			batch, err := j.rf.NextBatch(ctx)
+			if err != nil {
+				colexecerror.InternalError(err)
+			}
+			if batch.Length() == 0 {
+				log.VEvent(ctx, 1, "done joining rows")
+				j.strategy.prepareToEmit(ctx)
+				j.state = cjrEmittingRows
+				continue
+			}
+			j.mu.Lock()
+			j.mu.rowsRead += int64(batch.Length())
+			j.mu.Unlock()
+			if out := j.strategy.processLookedUpBatch(ctx, batch); out != nil {
+				return out
+			}
+			if len(j.emitState.leftIdxs) > 0 {
+				j.state = cjrEmittingLookedUpRows
+			}
+		case cjrEmittingLookedUpRows, cjrEmittingRows:
+			if j.emitState.cursor < len(j.emitState.leftIdxs) {
+				return j.emitJoinedRows()
+			}
+			j.resetEmitState()
+			if j.state == cjrEmittingLookedUpRows {
+				j.state = cjrPerformingLookup
+			} else {
+				j.state = cjrReadingInput
+			}
+		case cjrDone:
+			return coldata.ZeroBatch
+		default:
+			colexecerror.InternalError(errors.AssertionFailedf("unsupported state: %d", j.state))
+		}
+	}
+}
+
+// readInput buffers the next batch of input rows and starts the lookup of
+// the corresponding rows.
+func (j *ColJoinReader) readInput(ctx context.Context) colJoinReaderState {
+	j.inputRows.ResetInternalBatch()
+	j.inputRowsSizeBytes = 0
+	for !j.inputDone && j.inputRowsSizeBytes < j.batchSizeBytes {
+		batch := j.Input.Next(ctx)
+		n := batch.Length()
+		if n == 0 {
+			j.inputDone = true
+			break
+		}
+		j.inputRowsSizeBytes += colmem.GetProportionalBatchMemSize(batch, int64(n))
+		j.strategy.allocator().PerformOperation(j.inputRows.ColVecs(), func() {
+			j.inputRows.AppendTuples(batch, 0 /* startIdx */, n)
+		})
+	}
+	if j.inputRows.Length() == 0 {
+		log.VEvent(ctx, 1, "no more input rows")
+		return cjrDone
+	}
+	log.VEventf(ctx, 1, "read %d input rows", j.inputRows.Length())
+
+	spans, err := j.spanGen.generateSpans(j.inputRows)
+	if err != nil {
+		colexecerror.InternalError(err)
+	}
+	j.strategy.processLookupRows(j.inputRows)
+	if len(spans) == 0 {
+		// All of the input rows were filtered out. Skip the index lookup.
+		j.strategy.prepareToEmit(ctx)
+		return cjrEmittingRows
+	}
+
+	// Sort the spans for the following cases:
+	// - for lookup joins: this is so that we can rely upon the fetcher to
+	//   limit the number of results per batch. It's safe to reorder the spans
+	//   here because the looked up rows are matched back to the input rows.
+	// - for index joins when !maintainOrdering: this allows lower layers to
+	//   optimize iteration over the data. Note that the looked up rows are
+	//   output unchanged, in the retrieval order, so it is not safe to do this
+	//   when maintainOrdering is true.
+	if !j.isIndexJoin || !j.maintainOrdering {
+		sort.Sort(spans)
+	}
+
+	log.VEventf(ctx, 1, "scanning %d spans", len(spans))
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	// The cFetcher creates a new KV fetcher for every scan, so the bytes read
+	// by the previous scan need to be accumulated first.
+	j.mu.bytesRead += j.rf.fetcher.GetBytesRead()
+	if err := j.rf.StartScan(
+		j.flowCtx.Txn, spans, j.shouldLimitBatches, 0, /* limitHint */
+		j.flowCtx.TraceKV, j.flowCtx.EvalCtx.TestingKnobs.ForceProductionBatchSizes,
+	); err != nil {
+		colexecerror.InternalError(err)
+	}
+	return cjrPerformingLookup
+}
+
+// addJoinedRow adds a row joining the input row and the looked up row with the
+// given indices to the rows to emit. A negative rightIdx denotes a looked up
+// row made of NULLs.
+func (j *ColJoinReader) addJoinedRow(leftIdx, rightIdx int) {
+	j.emitState.leftIdxs = append(j.emitState.leftIdxs, leftIdx)
+	j.emitState.rightIdxs = append(j.emitState.rightIdxs, rightIdx)
+}
+
+func (j *ColJoinReader) resetEmitState() {
+	j.emitState.leftIdxs = j.emitState.leftIdxs[:0]
+	j.emitState.rightIdxs = j.emitState.rightIdxs[:0]
+	j.emitState.rightRows = nil
+	j.emitState.cursor = 0
+}
+
+// emitJoinedRows returns the next batch of joined rows from emitState.
+func (j *ColJoinReader) emitJoinedRows() coldata.Batch {
+	start := j.emitState.cursor
+	n := len(j.emitState.leftIdxs) - start
+	if n > coldata.BatchSize() {
+		n = coldata.BatchSize()
+	}
+	j.emitState.cursor += n
+	j.output, _ = j.allocator.ResetMaybeReallocate(j.ResultTypes, j.output, n, j.memoryLimit)
+	leftSel := j.emitState.leftIdxs[start : start+n]
+	j.allocator.PerformOperation(j.output.ColVecs(), func() {
+		var rightColIdxs []int
+		if j.joinType.ShouldIncludeRightColsInOutput() {
+			rightColIdxs = j.neededRightCols
+		}
+		j.emitState.scratch = copyJoinedRows(
+			j.output, j.inputRows, j.inputColIdxs, leftSel, j.emitState.rightRows,
+			len(j.inputTypes), rightColIdxs, j.emitState.rightIdxs[start:start+n], j.emitState.scratch,
+		)
+	})
+	j.output.SetLength(n)
+	return j.output
+}
+
+// copyJoinedRows copies the joined rows into the first len(leftSel) tuples of
+// output. The i-th joined row is made of the columns leftColIdxs of the left
+// row with index leftSel[i] and the columns rightColIdxs of the right row with
+// index rightIdxs[i], and the right columns are written at rightOffset in
+// output. A negative right index denotes a right row made of NULLs. The
+// updated scratch slice is returned. It must be called from within
+// Allocator.PerformOperation.
+func copyJoinedRows(
+	output, leftRows coldata.Batch,
+	leftColIdxs, leftSel []int,
+	rightRows coldata.Batch,
+	rightOffset int,
+	rightColIdxs, rightIdxs, scratch []int,
+) []int {
+	n := len(leftSel)
+	for _, colIdx := range leftColIdxs {
+		output.ColVec(colIdx).Copy(coldata.CopySliceArgs{
+			SliceArgs: coldata.SliceArgs{
+				Src:       leftRows.ColVec(colIdx),
+				Sel:       leftSel,
+				SrcEndIdx: n,
+			},
+		})
+	}
+	if len(rightColIdxs) == 0 {
+		return scratch
+	}
+	// Right rows made of NULLs are copied from the first right row, if there
+	// is one, and then set to NULL.
+	rightSel := colexecutils.EnsureSelectionVectorLength(scratch, n)
+	var hasNulls bool
+	for i, idx := range rightIdxs {
+		if idx < 0 {
+			hasNulls = true
+			idx = 0
+		}
+		rightSel[i] = idx
+	}
+	canCopy := rightRows != nil && rightRows.Length() > 0
+	for _, colIdx := range rightColIdxs {
+		vec := output.ColVec(rightOffset + colIdx)
+		if canCopy {
+			vec.Copy(coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					Src:       rightRows.ColVec(colIdx),
+					Sel:       rightSel,
+					SrcEndIdx: n,
+				},
+			})
+		}
+		if hasNulls {
+			nulls := vec.Nulls()
+			for i, idx := range rightIdxs {
+				if idx < 0 {
+					nulls.SetNull(i)
+				}
+			}
+		}
+	}
+	return rightSel
+}
+
+// makeOrdinals returns the slice of ordinals [0, n).
+func makeOrdinals(n int) []int {
+	ordinals := make([]int, n)
+	for i := range ordinals {
+		ordinals[i] = i
+	}
+	return ordinals
+}
+
+// DrainMeta is part of the colexecop.MetadataSource interface.
+func (j *ColJoinReader) DrainMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
+	j.mu.Lock()
+	initialized := j.mu.init
+	j.mu.Unlock()
+	if !initialized {
+		return nil
+	}
+	var trailingMeta []execinfrapb.ProducerMetadata
+	if tfs := execinfra.GetLeafTxnFinalState(ctx, j.flowCtx.Txn); tfs != nil {
+		trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{LeafTxnFinalState: tfs})
+	}
+	meta := execinfrapb.GetProducerMeta()
+	meta.Metrics = execinfrapb.GetMetricsMeta()
+	meta.Metrics.BytesRead = j.GetBytesRead()
+	meta.Metrics.RowsRead = j.GetRowsRead()
+	trailingMeta = append(trailingMeta, *meta)
+	if j.tracingSpan != nil {
+		// If tracingSpan is non-nil, then we have derived a new context in
+		// Next() and we have to collect the trace data. See the comment in
+		// ColBatchScan.DrainMeta for more details.
+		j.mu.Lock()
+		traceCtx := j.mu.ctx
+		j.mu.Unlock()
+		if trace := execinfra.GetTraceData(traceCtx); trace != nil {
+			trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{TraceData: trace})
+		}
+	}
+	return trailingMeta
+}
+
+// GetBytesRead is part of the colexecop.KVReader interface.
+func (j *ColJoinReader) GetBytesRead() int64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.mu.bytesRead + j.rf.fetcher.GetBytesRead()
+}
+
+// GetRowsRead is part of the colexecop.KVReader interface.
+func (j *ColJoinReader) GetRowsRead() int64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.mu.rowsRead
+}
+
+// GetCumulativeContentionTime is part of the colexecop.KVReader interface.
+func (j *ColJoinReader) GetCumulativeContentionTime() time.Duration {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.mu.ctx == nil {
+		// Next was never called, so there was no contention events.
+		return 0
+	}
+	return execinfra.GetCumulativeContentionTime(j.mu.ctx)
+}
+
+var colJoinReaderPool = sync.Pool{
+	New: func() interface{} {
+		return &ColJoinReader{}
+	},
+}
+
+// NewColJoinReader creates a new ColJoinReader operator. The allocator is used
+// for the output batches, and the buffering allocator for the buffered input
+// and looked up rows.
+func NewColJoinReader(
+	ctx context.Context,
+	allocator *colmem.Allocator,
+	bufferingAllocator *colmem.Allocator,
+	flowCtx *execinfra.FlowCtx,
+	evalCtx *tree.EvalContext,
+	input colexecop.Operator,
+	inputTypes []*types.T,
+	spec *execinfrapb.JoinReaderSpec,
+	post *execinfrapb.PostProcessSpec,
+) (*ColJoinReader, error) {
+	if !spec.LookupExpr.Empty() {
+		return nil, errors.AssertionFailedf("lookup expressions are not supported")
+	}
+	if spec.LeftJoinWithPairedJoiner || spec.OutputGroupContinuationForLeftRow {
+		return nil, errors.AssertionFailedf("paired joins are not supported")
+	}
+	isIndexJoin := len(spec.LookupColumns) == 0
+	switch spec.Type {
+	case descpb.InnerJoin:
+	case descpb.LeftOuterJoin, descpb.LeftSemiJoin, descpb.LeftAntiJoin:
+		if isIndexJoin {
+			return nil, errors.AssertionFailedf("only inner index joins are supported, %s requested", spec.Type)
+		}
+		if !spec.OnExpr.Empty() {
+			return nil, errors.AssertionFailedf("ON expressions are only supported for inner lookup joins")
+		}
+	default:
+		return nil, errors.AssertionFailedf("only inner and left {outer, semi, anti} lookup joins are supported, %s requested", spec.Type)
+	}
+	if isIndexJoin {
+		if spec.IndexIdx != 0 {
+			return nil, errors.AssertionFailedf("index join must be against primary index")
+		}
+		if !spec.OnExpr.Empty() {
+			return nil, errors.AssertionFailedf("non-empty ON expressions are not supported for index joins")
+		}
+	}
+
+	table := spec.BuildTableDescriptor()
+	indexIdx := int(spec.IndexIdx)
+	if indexIdx >= len(table.ActiveIndexes()) {
+		return nil, errors.Errorf("invalid indexIdx %d", indexIdx)
+	}
+	index := table.ActiveIndexes()[indexIdx]
+	cols := table.PublicColumns()
+	if spec.Visibility == execinfra.ScanVisibilityPublicAndNotPublic {
+		cols = table.DeletableColumns()
+	}
+	columnIdxMap := catalog.ColumnIDToOrdinalMap(cols)
+	typs := catalog.ColumnTypes(cols)
+
+	// Add all requested system columns to the output.
+	if spec.HasSystemColumns {
+		for _, sysCol := range table.SystemColumns() {
+			typs = append(typs, sysCol.GetType())
+			columnIdxMap.Set(sysCol.GetID(), columnIdxMap.Len())
+		}
+	}
+
+	// Before we can safely use types from the table descriptor, we need to
+	// make sure they are hydrated. See the comment in NewColBatchScan.
+	resolver := flowCtx.TypeResolverFactory.NewTypeResolver(evalCtx.Txn)
+	if err := resolver.HydrateTypeSlice(ctx, typs); err != nil {
+		return nil, err
+	}
+
+	columnIDs, _ := index.IndexDesc().FullColumnIDs()
+	var lookupCols []uint32
+	if isIndexJoin {
+		// The input rows of an index join are made of the primary key columns.
+		lookupCols = make([]uint32, index.NumColumns())
+		for i := range lookupCols {
+			lookupCols[i] = uint32(i)
+		}
+	} else {
+		lookupCols = spec.LookupColumns
+	}
+	if len(lookupCols) > len(columnIDs) {
+		return nil, errors.Errorf(
+			"%d lookup columns specified, expecting at most %d", len(lookupCols), len(columnIDs))
+	}
+
+	// The index join outputs the looked up rows as is, whereas the lookup join
+	// outputs the input rows followed by the looked up rows, if the join type
+	// includes them.
+	var resultTypes []*types.T
+	switch {
+	case isIndexJoin:
+		resultTypes = typs
+	case spec.Type.ShouldIncludeRightColsInOutput():
+		resultTypes = make([]*types.T, 0, len(inputTypes)+len(typs))
+		resultTypes = append(resultTypes, inputTypes...)
+		resultTypes = append(resultTypes, typs...)
+	default:
+		resultTypes = inputTypes
+	}
+
+	// Determine the columns that need to be fetched: the columns used by the
+	// post-processing stage and by the ON expression, as well as the index
+	// columns used to match the looked up rows with the input rows.
+	semaCtx := flowCtx.TypeResolverFactory.NewSemaContext(evalCtx.Txn)
+	var outputHelper execinfra.ProcOutputHelper
+	if err := outputHelper.Init(post, resultTypes, semaCtx, evalCtx, nil /* output */); err != nil {
+		return nil, err
+	}
+	neededCols := outputHelper.NeededColumns()
+	var neededRightCols util.FastIntSet
+	if isIndexJoin {
+		neededRightCols = neededCols
+	} else {
+		for i, ok := neededCols.Next(len(inputTypes)); ok; i, ok = neededCols.Next(i + 1) {
+			neededRightCols.Add(i - len(inputTypes))
+		}
+		if !spec.OnExpr.Empty() {
+			onExprTypes := make([]*types.T, 0, len(inputTypes)+len(typs))
+			onExprTypes = append(onExprTypes, inputTypes...)
+			onExprTypes = append(onExprTypes, typs...)
+			var onExpr execinfrapb.ExprHelper
+			if err := onExpr.Init(spec.OnExpr, onExprTypes, semaCtx, evalCtx); err != nil {
+				return nil, err
+			}
+			for i := range typs {
+				if onExpr.Vars.IndexedVarUsed(len(inputTypes) + i) {
+					neededRightCols.Add(i)
+				}
+			}
+		}
+	}
+	lookedUpKeyCols := make([]int, len(lookupCols))
+	for i := range lookedUpKeyCols {
+		lookedUpKeyCols[i] = columnIdxMap.GetDefault(columnIDs[i])
+	}
+	if !isIndexJoin {
+		for _, colIdx := range lookedUpKeyCols {
+			neededRightCols.Add(colIdx)
+		}
+	}
+	if !index.Primary() {
+		var indexCols util.FastIntSet
+		if err := index.IndexDesc().RunOverAllColumns(func(id descpb.ColumnID) error {
+			indexCols.Add(columnIdxMap.GetDefault(id))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		if !neededRightCols.SubsetOf(indexCols) {
+			return nil, errors.Errorf("joinreader index does not cover all columns")
+		}
+	}
+
+	fetcher := cFetcherPool.Get().(*cFetcher)
+	if _, _, err := initCRowFetcher(
+		flowCtx.Codec(), allocator, execinfra.GetWorkMemLimit(flowCtx.Cfg),
+		fetcher, table, indexIdx, columnIdxMap, neededRightCols, false, /* reverse */
+		spec.Visibility, spec.LockingStrength, spec.LockingWaitPolicy, nil, /* virtualColumn */
+		spec.HasSystemColumns,
+	); err != nil {
+		return nil, err
+	}
+
+	spanBuilder := span.MakeBuilder(evalCtx, flowCtx.Codec(), table, index.IndexDesc())
+	spanBuilder.SetNeededColumns(neededRightCols)
+
+	j := colJoinReaderPool.Get().(*ColJoinReader)
+	*j = ColJoinReader{
+		OneInputNode:     colexecop.NewOneInputNode(input),
+		flowCtx:          flowCtx,
+		rf:               fetcher,
+		allocator:        allocator,
+		memoryLimit:      execinfra.GetWorkMemLimit(flowCtx.Cfg),
+		joinType:         spec.Type,
+		isIndexJoin:      isIndexJoin,
+		maintainOrdering: spec.MaintainOrdering,
+		// If the lookup columns form a key, there is only one result per
+		// lookup, so the fetcher should parallelize the key lookups it
+		// performs.
+		shouldLimitBatches: !spec.LookupColumnsAreKey && !isIndexJoin,
+		inputTypes:         inputTypes,
+		inputColIdxs:       makeOrdinals(len(inputTypes)),
+		inputRows:          colexecutils.NewAppendOnlyBufferedBatch(bufferingAllocator, inputTypes, nil /* colsToStore */),
+		ResultTypes:        resultTypes,
+		spanGen: colJoinReaderSpanGenerator{
+			spanBuilder:     spanBuilder,
+			lookupCols:      lookupCols,
+			lookedUpKeyCols: lookedUpKeyCols,
+			inputTypes:      inputTypes,
+			lookedUpTypes:   typs,
+		},
+	}
+	if !isIndexJoin {
+		// Index joins already have unique rows in the input that generate
+		// unique spans, and simply output the looked up rows, so they don't
+		// need to map the looked up rows to the input rows.
+		j.spanGen.keyToInputRowIndices = make(map[string][]int)
+		neededRightCols.ForEach(func(colIdx int) {
+			j.neededRightCols = append(j.neededRightCols, colIdx)
+		})
+	}
+	j.initStrategy(bufferingAllocator, typs)
+	j.batchSizeBytes = j.strategy.getLookupRowsBatchSizeHint()
+	return j, nil
+}
+
+// SetBatchSizeBytes sets the desired batch size. It should only be used in
+// tests.
+func (j *ColJoinReader) SetBatchSizeBytes(batchSize int64) {
+	j.batchSizeBytes = batchSize
+}
+
+// FullScanSpec returns the spec of a full scan of the index that the
+// ColJoinReader looks up, which produces the looked up columns needed by the
+// ColJoinReader, as well as the ordinals of the index columns used for the
+// lookup among the columns produced by the scan. It is used to perform the
+// same join as a hash join instead.
+func (j *ColJoinReader) FullScanSpec(
+	spec *execinfrapb.JoinReaderSpec,
+) (*execinfrapb.TableReaderSpec, []uint32, error) {
+	if j.isIndexJoin {
+		return nil, nil, errors.AssertionFailedf("index joins cannot be performed as hash joins")
+	}
+	table := spec.BuildTableDescriptor()
+	index := table.ActiveIndexes()[spec.IndexIdx]
+	trSpec := &execinfrapb.TableReaderSpec{
+		Table:             spec.Table,
+		IndexIdx:          spec.IndexIdx,
+		Spans:             []execinfrapb.TableReaderSpan{{Span: table.IndexSpan(j.flowCtx.Codec(), index.GetID())}},
+		Visibility:        spec.Visibility,
+		LockingStrength:   spec.LockingStrength,
+		LockingWaitPolicy: spec.LockingWaitPolicy,
+		HasSystemColumns:  spec.HasSystemColumns,
+		NeededColumns:     make([]uint32, len(j.neededRightCols)),
+	}
+	for i, colIdx := range j.neededRightCols {
+		trSpec.NeededColumns[i] = uint32(colIdx)
+	}
+	keyCols := make([]uint32, len(j.spanGen.lookupCols))
+	for i := range keyCols {
+		keyCols[i] = uint32(j.spanGen.lookedUpKeyCols[i])
+	}
+	return trSpec, keyCols, nil
+}
+
+// Release implements the execinfra.Releasable interface.
+func (j *ColJoinReader) Release() {
+	j.rf.Release()
+	j.spanGen.release()
+	*j = ColJoinReader{}
+	colJoinReaderPool.Put(j)
+}
+
+// Close implements the colexecop.Closer interface.
+func (j *ColJoinReader) Close(context.Context) error {
+	if j.tracingSpan != nil {
+		j.tracingSpan.Finish()
+		j.tracingSpan = nil
+	}
+	return nil
+}
+
+// colJoinReaderSpanGenerator generates the spans to look up for a batch of
+// input rows, and maps the looked up rows back to the input rows.
+type colJoinReaderSpanGenerator struct {
+	spanBuilder *span.Builder
+	// lookupCols contains the ordinals of the input columns that are equal to
+	// the index columns used for the lookup.
+	lookupCols []uint32
+	// lookedUpKeyCols contains the ordinals of the looked up columns that are
+	// used for the lookup.
+	lookedUpKeyCols []int
+
+	inputTypes    []*types.T
+	lookedUpTypes []*types.T
+	// inputConverter and lookedUpConverter convert the lookup columns of the
+	// input rows and of the looked up rows to datums, which are needed to
+	// encode the lookup keys.
+	inputConverter    *colconv.VecToDatumConverter
+	lookedUpConverter *colconv.VecToDatumConverter
+
+	indexKeyRow rowenc.EncDatumRow
+	// keyToInputRowIndices maps a lookup span key to the input row indices that
+	// desire that span. This is used for lookup joins, for de-duping spans, and
+	// to map the looked up rows to the input rows that need to join with them.
+	keyToInputRowIndices map[string][]int
+
+	scratchSpans roachpb.Spans
+}
+
+// generateSpans generates the spans to look up for the given input rows.
+func (g *colJoinReaderSpanGenerator) generateSpans(
+	inputRows coldata.Batch,
+) (roachpb.Spans, error) {
+	// This loop gets optimized to a runtime.mapclear call.
+	for k := range g.keyToInputRowIndices {
+		delete(g.keyToInputRowIndices, k)
+	}
+	if g.inputConverter == nil {
+		vecIdxs := make([]int, len(g.lookupCols))
+		for i, colIdx := range g.lookupCols {
+			vecIdxs[i] = int(colIdx)
+		}
+		g.inputConverter = colconv.NewVecToDatumConverter(len(g.inputTypes), vecIdxs)
+	}
+	n := inputRows.Length()
+	g.inputConverter.ConvertVecs(inputRows.ColVecs(), n, nil /* sel */)
+	g.scratchSpans = g.scratchSpans[:0]
+RowLoop:
+	for i := 0; i < n; i++ {
+		g.indexKeyRow = g.indexKeyRow[:0]
+		for _, colIdx := range g.lookupCols {
+			d := g.inputConverter.GetDatumColumn(int(colIdx))[i]
+			if d == tree.DNull {
+				// Rows with NULL lookup columns cannot match any looked up row.
+				continue RowLoop
+			}
+			g.indexKeyRow = append(g.indexKeyRow, rowenc.DatumToEncDatum(g.inputTypes[colIdx], d))
+		}
+		generatedSpan, containsNull, err := g.spanBuilder.SpanFromEncDatums(g.indexKeyRow, len(g.lookupCols))
+		if err != nil {
+			return nil, err
+		}
+		if g.keyToInputRowIndices == nil {
+			// Index join.
+			g.scratchSpans = g.spanBuilder.MaybeSplitSpanIntoSeparateFamilies(
+				g.scratchSpans, generatedSpan, len(g.lookupCols), containsNull)
+			continue
+		}
+		inputRowIndices := g.keyToInputRowIndices[string(generatedSpan.Key)]
+		if inputRowIndices == nil {
+			g.scratchSpans = g.spanBuilder.MaybeSplitSpanIntoSeparateFamilies(
+				g.scratchSpans, generatedSpan, len(g.lookupCols), containsNull)
+		}
+		g.keyToInputRowIndices[string(generatedSpan.Key)] = append(inputRowIndices, i)
+	}
+	return g.scratchSpans, nil
+}
+
+// convertLookedUpRows must be called on each batch of looked up rows before
+// getMatchingRowIndices.
+func (g *colJoinReaderSpanGenerator) convertLookedUpRows(lookedUpRows coldata.Batch) {
+	if g.lookedUpConverter == nil {
+		g.lookedUpConverter = colconv.NewVecToDatumConverter(len(g.lookedUpTypes), g.lookedUpKeyCols)
+	}
+	g.lookedUpConverter.ConvertVecs(lookedUpRows.ColVecs(), lookedUpRows.Length(), nil /* sel */)
+}
+
+// getMatchingRowIndices returns the indices of the input rows that desire the
+// looked up row with the given index in the last converted batch.
+func (g *colJoinReaderSpanGenerator) getMatchingRowIndices(rowIdx int) ([]int, error) {
+	g.indexKeyRow = g.indexKeyRow[:0]
+	for _, colIdx := range g.lookedUpKeyCols {
+		d := g.lookedUpConverter.GetDatumColumn(colIdx)[rowIdx]
+		g.indexKeyRow = append(g.indexKeyRow, rowenc.DatumToEncDatum(g.lookedUpTypes[colIdx], d))
+	}
+	key, _, err := g.spanBuilder.SpanFromEncDatums(g.indexKeyRow, len(g.lookedUpKeyCols))
+	if err != nil {
+		return nil, err
+	}
+	return g.keyToInputRowIndices[string(key.Key)], nil
+}
+
+func (g *colJoinReaderSpanGenerator) release() {
+	if g.inputConverter != nil {
+		g.inputConverter.Release()
+	}
+	if g.lookedUpConverter != nil {
+		g.lookedUpConverter.Release()
+	}
+}
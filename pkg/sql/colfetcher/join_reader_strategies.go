@@ -0,0 +1,259 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colfetcher
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// colJoinReaderStrategy abstracts the processing of looked up rows. The
+// ColJoinReader cycles through reading a batch of input rows, looking up the
+// corresponding rows, and emitting the joined rows; a colJoinReaderStrategy
+// decides which rows are emitted, and in which order. These are the columnar
+// counterparts of the strategies in rowexec/joinreader_strategies.go.
+type colJoinReaderStrategy interface {
+	// getLookupRowsBatchSizeHint returns the size in bytes of the batch of
+	// input rows.
+	getLookupRowsBatchSizeHint() int64
+	// allocator returns the allocator used for the buffered rows.
+	allocator() *colmem.Allocator
+	// processLookupRows is called with the new batch of input rows, once the
+	// corresponding spans have been generated.
+	processLookupRows(inputRows coldata.Batch)
+	// processLookedUpBatch processes a batch of looked up rows. It either
+	// returns a batch that should be emitted as is, or adds the joined rows to
+	// be emitted to the emit state of the ColJoinReader and returns nil. The
+	// looked up batch is only valid until the next lookup.
+	processLookedUpBatch(ctx context.Context, lookedUpRows coldata.Batch) coldata.Batch
+	// prepareToEmit is called once all rows have been looked up for the
+	// current batch of input rows, and adds the remaining joined rows to the
+	// emit state of the ColJoinReader.
+	prepareToEmit(ctx context.Context)
+}
+
+// initStrategy initializes the strategy of the ColJoinReader. typs are the
+// types of the looked up rows.
+func (j *ColJoinReader) initStrategy(bufferingAllocator *colmem.Allocator, typs []*types.T) {
+	switch {
+	case j.isIndexJoin:
+		j.strategy = &colJoinReaderIndexJoinStrategy{
+			bufferingAllocator: bufferingAllocator,
+		}
+	case !j.maintainOrdering:
+		j.strategy = &colJoinReaderNoOrderingStrategy{
+			j:                  j,
+			bufferingAllocator: bufferingAllocator,
+		}
+	default:
+		j.strategy = &colJoinReaderOrderingStrategy{
+			j:                  j,
+			bufferingAllocator: bufferingAllocator,
+			lookedUpRows:       colexecutils.NewAppendOnlyBufferedBatch(bufferingAllocator, typs, j.neededRightCols),
+		}
+	}
+}
+
+// colJoinReaderIndexJoinStrategy is a colJoinReaderStrategy that executes an
+// index join. It does not need to maintain any state and simply emits the
+// looked up rows.
+type colJoinReaderIndexJoinStrategy struct {
+	bufferingAllocator *colmem.Allocator
+}
+
+var _ colJoinReaderStrategy = &colJoinReaderIndexJoinStrategy{}
+
+func (s *colJoinReaderIndexJoinStrategy) getLookupRowsBatchSizeHint() int64 {
+	return 4 << 20 /* 4 MB */
+}
+
+func (s *colJoinReaderIndexJoinStrategy) allocator() *colmem.Allocator {
+	return s.bufferingAllocator
+}
+
+func (s *colJoinReaderIndexJoinStrategy) processLookupRows(coldata.Batch) {}
+
+func (s *colJoinReaderIndexJoinStrategy) processLookedUpBatch(
+	_ context.Context, lookedUpRows coldata.Batch,
+) coldata.Batch {
+	return lookedUpRows
+}
+
+func (s *colJoinReaderIndexJoinStrategy) prepareToEmit(context.Context) {}
+
+// colJoinReaderNoOrderingStrategy is a colJoinReaderStrategy that doesn't
+// maintain the ordering of the input rows. The rows joined with each batch of
+// looked up rows are emitted right away, and the unmatched input rows, if
+// any, are emitted after all rows have been looked up.
+type colJoinReaderNoOrderingStrategy struct {
+	j                  *ColJoinReader
+	bufferingAllocator *colmem.Allocator
+
+	// matched[i] is true if the i-th input row has been matched with a looked
+	// up row. It is only used for outer, semi and anti joins.
+	matched []bool
+}
+
+var _ colJoinReaderStrategy = &colJoinReaderNoOrderingStrategy{}
+
+func (s *colJoinReaderNoOrderingStrategy) getLookupRowsBatchSizeHint() int64 {
+	return 2 << 20 /* 2 MiB */
+}
+
+func (s *colJoinReaderNoOrderingStrategy) allocator() *colmem.Allocator {
+	return s.bufferingAllocator
+}
+
+func (s *colJoinReaderNoOrderingStrategy) processLookupRows(inputRows coldata.Batch) {
+	if s.j.joinType != descpb.InnerJoin {
+		s.matched = colexecutils.MaybeAllocateBoolArray(s.matched, inputRows.Length())
+	}
+}
+
+func (s *colJoinReaderNoOrderingStrategy) processLookedUpBatch(
+	_ context.Context, lookedUpRows coldata.Batch,
+) coldata.Batch {
+	j := s.j
+	j.spanGen.convertLookedUpRows(lookedUpRows)
+	j.emitState.rightRows = lookedUpRows
+	for i, n := 0, lookedUpRows.Length(); i < n; i++ {
+		inputRowIndices, err := j.spanGen.getMatchingRowIndices(i)
+		if err != nil {
+			colexecerror.InternalError(err)
+		}
+		for _, inputRowIdx := range inputRowIndices {
+			switch j.joinType {
+			case descpb.InnerJoin:
+				j.addJoinedRow(inputRowIdx, i)
+			case descpb.LeftOuterJoin:
+				s.matched[inputRowIdx] = true
+				j.addJoinedRow(inputRowIdx, i)
+			case descpb.LeftSemiJoin:
+				// A semi join emits each matched input row once.
+				if !s.matched[inputRowIdx] {
+					s.matched[inputRowIdx] = true
+					j.addJoinedRow(inputRowIdx, -1 /* rightIdx */)
+				}
+			case descpb.LeftAntiJoin:
+				s.matched[inputRowIdx] = true
+			}
+		}
+	}
+	return nil
+}
+
+func (s *colJoinReaderNoOrderingStrategy) prepareToEmit(context.Context) {
+	j := s.j
+	if j.joinType != descpb.LeftOuterJoin && j.joinType != descpb.LeftAntiJoin {
+		return
+	}
+	// Emit the input rows that have not been matched. Note that the input rows
+	// with NULL lookup columns are never matched.
+	for i, matched := range s.matched[:j.inputRows.Length()] {
+		if !matched {
+			j.addJoinedRow(i, -1 /* rightIdx */)
+		}
+	}
+}
+
+// colJoinReaderOrderingStrategy is a colJoinReaderStrategy that maintains the
+// ordering of the input rows. The looked up rows are buffered, and the joined
+// rows are emitted in the order of the input rows once all rows have been
+// looked up.
+type colJoinReaderOrderingStrategy struct {
+	j                  *ColJoinReader
+	bufferingAllocator *colmem.Allocator
+
+	// lookedUpRows contains the needed columns of the looked up rows for the
+	// current batch of input rows.
+	lookedUpRows *colexecutils.AppendOnlyBufferedBatch
+	// inputRowIdxToLookedUpRowIndices is a multimap from input row indices to
+	// the corresponding looked up rows in lookedUpRows.
+	inputRowIdxToLookedUpRowIndices [][]int
+}
+
+var _ colJoinReaderStrategy = &colJoinReaderOrderingStrategy{}
+
+func (s *colJoinReaderOrderingStrategy) getLookupRowsBatchSizeHint() int64 {
+	return 10 << 10 /* 10 KiB */
+}
+
+func (s *colJoinReaderOrderingStrategy) allocator() *colmem.Allocator {
+	return s.bufferingAllocator
+}
+
+func (s *colJoinReaderOrderingStrategy) processLookupRows(inputRows coldata.Batch) {
+	n := inputRows.Length()
+	if cap(s.inputRowIdxToLookedUpRowIndices) >= n {
+		s.inputRowIdxToLookedUpRowIndices = s.inputRowIdxToLookedUpRowIndices[:n]
+	} else {
+		s.inputRowIdxToLookedUpRowIndices = make([][]int, n)
+	}
+	for i := range s.inputRowIdxToLookedUpRowIndices {
+		s.inputRowIdxToLookedUpRowIndices[i] = s.inputRowIdxToLookedUpRowIndices[i][:0]
+	}
+	s.lookedUpRows.ResetInternalBatch()
+}
+
+func (s *colJoinReaderOrderingStrategy) processLookedUpBatch(
+	_ context.Context, lookedUpRows coldata.Batch,
+) coldata.Batch {
+	j := s.j
+	j.spanGen.convertLookedUpRows(lookedUpRows)
+	n := lookedUpRows.Length()
+	offset := s.lookedUpRows.Length()
+	for i := 0; i < n; i++ {
+		inputRowIndices, err := j.spanGen.getMatchingRowIndices(i)
+		if err != nil {
+			colexecerror.InternalError(err)
+		}
+		for _, inputRowIdx := range inputRowIndices {
+			s.inputRowIdxToLookedUpRowIndices[inputRowIdx] = append(
+				s.inputRowIdxToLookedUpRowIndices[inputRowIdx], offset+i)
+		}
+	}
+	if j.joinType.ShouldIncludeRightColsInOutput() {
+		s.bufferingAllocator.PerformOperation(s.lookedUpRows.ColVecs(), func() {
+			s.lookedUpRows.AppendTuples(lookedUpRows, 0 /* startIdx */, n)
+		})
+	} else {
+		// Only the fact that an input row has been matched matters.
+		s.lookedUpRows.SetLength(offset + n)
+	}
+	return nil
+}
+
+func (s *colJoinReaderOrderingStrategy) prepareToEmit(context.Context) {
+	j := s.j
+	j.emitState.rightRows = s.lookedUpRows
+	for inputRowIdx, lookedUpRowIndices := range s.inputRowIdxToLookedUpRowIndices {
+		if len(lookedUpRowIndices) == 0 {
+			if j.joinType == descpb.LeftOuterJoin || j.joinType == descpb.LeftAntiJoin {
+				j.addJoinedRow(inputRowIdx, -1 /* rightIdx */)
+			}
+			continue
+		}
+		switch j.joinType {
+		case descpb.InnerJoin, descpb.LeftOuterJoin:
+			for _, lookedUpRowIdx := range lookedUpRowIndices {
+				j.addJoinedRow(inputRowIdx, lookedUpRowIdx)
+			}
+		case descpb.LeftSemiJoin:
+			j.addJoinedRow(inputRowIdx, -1 /* rightIdx */)
+		}
+	}
+}
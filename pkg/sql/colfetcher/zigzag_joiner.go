@@ -0,0 +1,776 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colfetcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/span"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// colZigzagJoinerState represents the state of the ColZigzagJoiner.
+type colZigzagJoinerState int
+
+const (
+	// czzjFetchingInitialRow means that the first row of the left side is
+	// about to be fetched.
+	czzjFetchingInitialRow colZigzagJoinerState = iota
+	// czzjJoining means that the sides are being scanned in the zigzag fashion
+	// in order to find the matching rows.
+	czzjJoining
+	// czzjEmittingRows means that the matched rows are being emitted.
+	czzjEmittingRows
+	// czzjDone means that all matches have been emitted.
+	czzjDone
+)
+
+// colZigzagJoinerBatchSize is the limit hint of each scan, which determines how
+// many rows are fetched by the first KV batch of a scan.
+var colZigzagJoinerBatchSize = int64(util.ConstantWithMetamorphicTestValue(
+	"col-zig-zag-joiner-batch-size",
+	5, /* defaultValue */
+	1, /* metamorphicValue */
+))
+
+// zigzagJoinerSide contains the state of one side of the ColZigzagJoiner.
+type zigzagJoinerSide struct {
+	rf    *cFetcher
+	table catalog.TableDescriptor
+	index *descpb.IndexDescriptor
+	// indexTypes and indexDirs are the types and the directions of all
+	// columns of the index (where the inverted column, if any, has Bytes
+	// type).
+	indexTypes []*types.T
+	indexDirs  []descpb.IndexDescriptor_Direction
+	// typs are the types of the public columns of the table.
+	typs []*types.T
+	// neededCols contains the ordinals of the columns that are needed by the
+	// output of the ColZigzagJoiner.
+	neededCols []int
+
+	eqCols  []int
+	eqTypes []*types.T
+	eqDirs  []encoding.Direction
+	// eqDatums is a scratch space for the equality datums of the current row.
+	eqDatums    tree.Datums
+	eqConverter *colconv.VecToDatumConverter
+
+	fixedValues rowenc.EncDatumRow
+	prefix      []byte
+	spanBuilder *span.Builder
+	// key and endKey are the boundaries of the span of the index that
+	// contains all rows with the fixed values.
+	key    roachpb.Key
+	endKey roachpb.Key
+
+	// batch is the last batch fetched from this side, and rowIdx is the
+	// position of the current row within it.
+	batch  coldata.Batch
+	rowIdx int
+	// matches contains the rows of this side that are yet to be emitted.
+	matches *colexecutils.AppendOnlyBufferedBatch
+}
+
+// ColZigzagJoiner is the exec.Operator implementation of the ZigzagJoiner. It
+// performs an inner join of two indexes (of the same or different tables)
+// that are constrained to fixed values on their prefixes by alternately
+// seeking each side to the equality columns of the last row fetched from the
+// other side. See the comment on zigzagJoiner for more details about the
+// algorithm.
+//
+// The ON expression is not evaluated by the ColZigzagJoiner and needs to be
+// planned separately.
+type ColZigzagJoiner struct {
+	colexecop.ZeroInputNode
+	flowCtx *execinfra.FlowCtx
+	evalCtx *tree.EvalContext
+
+	// allocator is used for the output batches, and bufferingAllocator for the
+	// matched rows.
+	allocator          *colmem.Allocator
+	bufferingAllocator *colmem.Allocator
+	// memoryLimit is the memory limit for the output batches.
+	memoryLimit int64
+	output      coldata.Batch
+
+	state colZigzagJoinerState
+	sides [2]zigzagJoinerSide
+	// side is the index of the side that is seeked next.
+	side int
+	// baseEqDatums are the equality datums of the base row, which is the
+	// current row of the other side. hasBaseRow is false once one of the sides
+	// has been exhausted, meaning that there are no more matches.
+	baseEqDatums tree.Datums
+	hasBaseRow   bool
+	neededDatums rowenc.EncDatumRow
+	datumAlloc   rowenc.DatumAlloc
+
+	// emitState contains the joined rows that are yet to be emitted. Each row
+	// joins the rows at the same position in leftIdxs and rightIdxs of the
+	// matches of the corresponding sides.
+	emitState struct {
+		leftIdxs  []int
+		rightIdxs []int
+		cursor    int
+		scratch   []int
+	}
+
+	// tracingSpan is created when the stats should be collected for the query
+	// execution, and it will be finished when closing the operator.
+	tracingSpan *tracing.Span
+	mu          struct {
+		syncutil.Mutex
+		ctx context.Context
+		// init is true after Init() has been called.
+		init bool
+		// rowsRead contains the number of total rows this ColZigzagJoiner has
+		// read from both sides so far.
+		rowsRead int64
+		// bytesRead contains the number of bytes read by the previous scans.
+		bytesRead int64
+	}
+
+	// ResultTypes is the slice of resulting column types from this operator.
+	ResultTypes []*types.T
+}
+
+var _ colexecop.KVReader = &ColZigzagJoiner{}
+var _ execinfra.Releasable = &ColZigzagJoiner{}
+var _ colexecop.Closer = &ColZigzagJoiner{}
+var _ colexecop.DrainableOperator = &ColZigzagJoiner{}
+
+// Init initializes a ColZigzagJoiner.
+func (z *ColZigzagJoiner) Init() {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.mu.init = true
+}
+
+// Next is part of the Operator interface.
+func (z *ColZigzagJoiner) Next(ctx context.Context) coldata.Batch {
+	z.mu.Lock()
+	if z.mu.ctx == nil {
+		// This is the first call to Next(), so we will capture the context and
+		// possibly replace it with a child below.
+		z.mu.ctx = ctx
+		if execinfra.ShouldCollectStats(z.mu.ctx, z.flowCtx) {
+			// We need to start a child span so that the only contention events
+			// present in the recording would be because of the cFetchers.
+			z.mu.ctx, z.tracingSpan = execinfra.ProcessorSpan(z.mu.ctx, "colzigzagjoiner")
+		}
+	}
+	ctx = z.mu.ctx
+	z.mu.Unlock()
+	for {
+		switch z.state {
+		case czzjFetchingInitialRow:
+			left := &z.sides[0]
+			z.startScan(left, left.key)
+			if !z.fetchRow(ctx, left) {
+				log.VEvent(ctx, 1, "no rows on the left side")
+				z.state = czzjDone
+				continue
+			}
+			z.setBaseRow(left)
+			z.side = 1
+			z.state = czzjJoining
+		case czzjJoining:
+			if !z.hasBaseRow {
+				z.state = czzjEmittingRows
+				continue
+			}
+			z.seek(ctx)
+			if len(z.emitState.leftIdxs) >= coldata.BatchSize() {
+				z.state = czzjEmittingRows
+			}
+		case czzjEmittingRows:
+			if z.emitState.cursor < len(z.emitState.leftIdxs) {
+				return z.emitJoinedRows()
+			}
+			for i := range z.sides {
+				z.sides[i].matches.ResetInternalBatch()
+			}
+			z.emitState.leftIdxs = z.emitState.leftIdxs[:0]
+			z.emitState.rightIdxs = z.emitState.rightIdxs[:0]
+			z.emitState.cursor = 0
+			if z.hasBaseRow {
+				z.state = czzjJoining
+			} else {
+				z.state = czzjDone
+			}
+		case czzjDone:
+			return coldata.ZeroBatch
+		default:
+			colexecerror.InternalError(errors.AssertionFailedf("unsupported state: %d", z.state))
+		}
+	}
+}
+
+// seek jumps to the next possible match of the base row on the current side.
+// If a match is found, all matching rows of both sides are collected, and the
+// base row is updated to the later of the first unmatched rows of the sides.
+// Otherwise, the fetched row becomes the new base row.
+func (z *ColZigzagJoiner) seek(ctx context.Context) {
+	cur, prev := &z.sides[z.side], &z.sides[1-z.side]
+	s, err := z.produceSpan(cur, true /* useBaseRow */)
+	if err != nil {
+		colexecerror.InternalError(err)
+	}
+	z.startScan(cur, s.Key)
+	if !z.fetchRow(ctx, cur) {
+		// There is no next possible match on the current side, so there are
+		// no more matches in the join.
+		z.hasBaseRow = false
+		return
+	}
+	if z.compareEqDatums(cur, z.baseEqDatums, z.currentEqDatums(cur)) != 0 {
+		// The current row doesn't match the base row, so it becomes the new
+		// base row, and we repeat the process with the other side.
+		z.setBaseRow(cur)
+		z.side = 1 - z.side
+		return
+	}
+
+	// We've detected a match! Now, we collect all subsequent matches on both
+	// sides for the current equality column values.
+	left, right := &z.sides[0], &z.sides[1]
+	leftStart, rightStart := left.matches.Length(), right.matches.Length()
+	z.appendCurrentRow(prev)
+	z.appendCurrentRow(cur)
+	prevHasNext := z.collectAllMatches(ctx, prev)
+	curHasNext := z.collectAllMatches(ctx, cur)
+	for l := leftStart; l < left.matches.Length(); l++ {
+		for r := rightStart; r < right.matches.Length(); r++ {
+			z.emitState.leftIdxs = append(z.emitState.leftIdxs, l)
+			z.emitState.rightIdxs = append(z.emitState.rightIdxs, r)
+		}
+	}
+	if !prevHasNext || !curHasNext {
+		z.hasBaseRow = false
+		return
+	}
+	// We want the new base row to be the later of the first unmatched rows
+	// since no match can occur before it. The current side should be the side
+	// after the base row's side.
+	if z.compareEqDatums(cur, z.currentEqDatums(prev), z.currentEqDatums(cur)) < 0 {
+		z.setBaseRow(cur)
+		z.side = 1 - z.side
+	} else {
+		z.setBaseRow(prev)
+	}
+}
+
+// collectAllMatches appends all subsequent rows of the given side that match
+// the base row to the matches of that side. It returns false if the side has
+// been exhausted, and true if the current row of the side is the first row
+// that doesn't match.
+func (z *ColZigzagJoiner) collectAllMatches(ctx context.Context, side *zigzagJoinerSide) bool {
+	for {
+		if !z.fetchRow(ctx, side) {
+			return false
+		}
+		if z.compareEqDatums(side, z.baseEqDatums, z.currentEqDatums(side)) != 0 {
+			return true
+		}
+		z.appendCurrentRow(side)
+	}
+}
+
+// startScan starts the scan of the given side from key until the end of the
+// span with the fixed values.
+func (z *ColZigzagJoiner) startScan(side *zigzagJoinerSide, key roachpb.Key) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	// The cFetcher creates a new KV fetcher for every scan, so the bytes read
+	// by the previous scan need to be accumulated first.
+	z.mu.bytesRead += side.rf.fetcher.GetBytesRead()
+	if err := side.rf.StartScan(
+		z.flowCtx.Txn, roachpb.Spans{{Key: key, EndKey: side.endKey}}, true, /* limitBatches */
+		colZigzagJoinerBatchSize, z.flowCtx.TraceKV,
+		z.flowCtx.EvalCtx.TestingKnobs.ForceProductionBatchSizes,
+	); err != nil {
+		colexecerror.InternalError(err)
+	}
+	side.batch = nil
+	side.rowIdx = -1
+}
+
+// fetchRow advances the given side to the next row that doesn't have NULLs
+// in the equality columns. It returns false if the side has been exhausted.
+func (z *ColZigzagJoiner) fetchRow(ctx context.Context, side *zigzagJoinerSide) bool {
+	for {
+		side.rowIdx++
+		if side.batch == nil || side.rowIdx >= side.batch.Length() {
+			batch, err := side.rf.NextBatch(ctx)
+			if err != nil {
+				colexecerror.InternalError(err)
+			}
+			n := batch.Length()
+			if n == 0 {
+				return false
+			}
+			z.mu.Lock()
+			z.mu.rowsRead += int64(n)
+			z.mu.Unlock()
+			side.batch = batch
+			side.rowIdx = 0
+			side.eqConverter.ConvertVecs(batch.ColVecs(), n, nil /* sel */)
+		}
+		hasNull := false
+		for _, colIdx := range side.eqCols {
+			if side.batch.ColVec(colIdx).Nulls().NullAt(side.rowIdx) {
+				hasNull = true
+				break
+			}
+		}
+		if !hasNull {
+			return true
+		}
+	}
+}
+
+// currentEqDatums returns the equality datums of the current row of the given
+// side. The returned slice is only valid until the next call.
+func (z *ColZigzagJoiner) currentEqDatums(side *zigzagJoinerSide) tree.Datums {
+	for i, colIdx := range side.eqCols {
+		side.eqDatums[i] = side.eqConverter.GetDatumColumn(colIdx)[side.rowIdx]
+	}
+	return side.eqDatums
+}
+
+// setBaseRow makes the current row of the given side the base row.
+func (z *ColZigzagJoiner) setBaseRow(side *zigzagJoinerSide) {
+	copy(z.baseEqDatums, z.currentEqDatums(side))
+	z.hasBaseRow = true
+}
+
+// compareEqDatums compares the given equality datums according to the
+// ordering of the equality columns of the given side.
+func (z *ColZigzagJoiner) compareEqDatums(side *zigzagJoinerSide, a, b tree.Datums) int {
+	for i := range a {
+		if cmp := a[i].Compare(z.evalCtx, b[i]); cmp != 0 {
+			if side.eqDirs[i] == encoding.Descending {
+				return -cmp
+			}
+			return cmp
+		}
+	}
+	return 0
+}
+
+// appendCurrentRow appends the current row of the given side to its matches.
+func (z *ColZigzagJoiner) appendCurrentRow(side *zigzagJoinerSide) {
+	z.bufferingAllocator.PerformOperation(side.matches.ColVecs(), func() {
+		side.matches.AppendTuples(side.batch, side.rowIdx, side.rowIdx+1)
+	})
+}
+
+// produceSpan returns the span of the given side that contains the rows with
+// the fixed values and, if useBaseRow is true, the equality datums of the base
+// row.
+func (z *ColZigzagJoiner) produceSpan(
+	side *zigzagJoinerSide, useBaseRow bool,
+) (roachpb.Span, error) {
+	z.neededDatums = append(z.neededDatums[:0], side.fixedValues...)
+	if useBaseRow {
+		for i, d := range z.baseEqDatums {
+			z.neededDatums = append(z.neededDatums, rowenc.DatumToEncDatum(side.eqTypes[i], d))
+		}
+	}
+	if side.index.Type == descpb.IndexDescriptor_INVERTED {
+		return z.produceInvertedIndexSpan(side, z.neededDatums)
+	}
+	s, _, err := side.spanBuilder.SpanFromEncDatums(z.neededDatums, len(z.neededDatums))
+	return s, err
+}
+
+// produceInvertedIndexSpan is the analog of produceSpan for inverted indexes,
+// where the fixed value of the inverted column is the encoded inverted key.
+func (z *ColZigzagJoiner) produceInvertedIndexSpan(
+	side *zigzagJoinerSide, datums rowenc.EncDatumRow,
+) (roachpb.Span, error) {
+	var colMap catalog.TableColMap
+	decodedDatums := make(tree.Datums, len(datums))
+	for i := range datums {
+		if err := datums[i].EnsureDecoded(side.indexTypes[i], &z.datumAlloc); err != nil {
+			return roachpb.Span{}, err
+		}
+		decodedDatums[i] = datums[i].Datum
+		if i < len(side.index.ColumnIDs) {
+			colMap.Set(side.index.ColumnIDs[i], i)
+		} else {
+			// This column's value will be encoded in the second part (i.e.
+			// EncodeColumns).
+			colMap.Set(side.index.ExtraColumnIDs[i-len(side.index.ColumnIDs)], i)
+		}
+	}
+	// First encode datums for any non-inverted prefix columns.
+	keyPrefix, err := rowenc.EncodeInvertedIndexPrefixKeys(side.index, colMap, decodedDatums, side.prefix)
+	if err != nil {
+		return roachpb.Span{}, err
+	}
+	// Add the inverted key, which is already encoded as a DBytes.
+	invOrd, ok := colMap.Get(side.index.InvertedColumnID())
+	if !ok {
+		return roachpb.Span{}, errors.AssertionFailedf("inverted column not found in colMap")
+	}
+	invertedKey, ok := decodedDatums[invOrd].(*tree.DBytes)
+	if !ok {
+		return roachpb.Span{}, errors.AssertionFailedf("inverted key must be type DBytes")
+	}
+	keyPrefix = append(keyPrefix, []byte(*invertedKey)...)
+	// Append the remaining datums to the key.
+	keyBytes, _, err := rowenc.EncodeColumns(
+		side.index.ExtraColumnIDs[:len(datums)-1], side.indexDirs[1:], colMap, decodedDatums, keyPrefix,
+	)
+	key := roachpb.Key(keyBytes)
+	return roachpb.Span{Key: key, EndKey: key.PrefixEnd()}, err
+}
+
+// emitJoinedRows returns the next batch of joined rows from emitState.
+func (z *ColZigzagJoiner) emitJoinedRows() coldata.Batch {
+	start := z.emitState.cursor
+	n := len(z.emitState.leftIdxs) - start
+	if n > coldata.BatchSize() {
+		n = coldata.BatchSize()
+	}
+	z.emitState.cursor += n
+	z.output, _ = z.allocator.ResetMaybeReallocate(z.ResultTypes, z.output, n, z.memoryLimit)
+	left, right := &z.sides[0], &z.sides[1]
+	z.allocator.PerformOperation(z.output.ColVecs(), func() {
+		z.emitState.scratch = copyJoinedRows(
+			z.output, left.matches, left.neededCols, z.emitState.leftIdxs[start:start+n],
+			right.matches, len(left.typs), right.neededCols,
+			z.emitState.rightIdxs[start:start+n], z.emitState.scratch,
+		)
+	})
+	z.output.SetLength(n)
+	return z.output
+}
+
+// DrainMeta is part of the colexecop.MetadataSource interface.
+func (z *ColZigzagJoiner) DrainMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
+	z.mu.Lock()
+	initialized := z.mu.init
+	z.mu.Unlock()
+	if !initialized {
+		return nil
+	}
+	var trailingMeta []execinfrapb.ProducerMetadata
+	if tfs := execinfra.GetLeafTxnFinalState(ctx, z.flowCtx.Txn); tfs != nil {
+		trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{LeafTxnFinalState: tfs})
+	}
+	meta := execinfrapb.GetProducerMeta()
+	meta.Metrics = execinfrapb.GetMetricsMeta()
+	meta.Metrics.BytesRead = z.GetBytesRead()
+	meta.Metrics.RowsRead = z.GetRowsRead()
+	trailingMeta = append(trailingMeta, *meta)
+	if z.tracingSpan != nil {
+		// If tracingSpan is non-nil, then we have derived a new context in
+		// Next() and we have to collect the trace data. See the comment in
+		// ColBatchScan.DrainMeta for more details.
+		z.mu.Lock()
+		traceCtx := z.mu.ctx
+		z.mu.Unlock()
+		if trace := execinfra.GetTraceData(traceCtx); trace != nil {
+			trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{TraceData: trace})
+		}
+	}
+	return trailingMeta
+}
+
+// GetBytesRead is part of the colexecop.KVReader interface.
+func (z *ColZigzagJoiner) GetBytesRead() int64 {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	bytesRead := z.mu.bytesRead
+	for i := range z.sides {
+		bytesRead += z.sides[i].rf.fetcher.GetBytesRead()
+	}
+	return bytesRead
+}
+
+// GetRowsRead is part of the colexecop.KVReader interface.
+func (z *ColZigzagJoiner) GetRowsRead() int64 {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	return z.mu.rowsRead
+}
+
+// GetCumulativeContentionTime is part of the colexecop.KVReader interface.
+func (z *ColZigzagJoiner) GetCumulativeContentionTime() time.Duration {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	if z.mu.ctx == nil {
+		// Next was never called, so there was no contention events.
+		return 0
+	}
+	return execinfra.GetCumulativeContentionTime(z.mu.ctx)
+}
+
+var colZigzagJoinerPool = sync.Pool{
+	New: func() interface{} {
+		return &ColZigzagJoiner{}
+	},
+}
+
+// NewColZigzagJoiner creates a new ColZigzagJoiner operator. The allocator is
+// used for the output batches, and the buffering allocator for the matched
+// rows.
+func NewColZigzagJoiner(
+	ctx context.Context,
+	allocator *colmem.Allocator,
+	bufferingAllocator *colmem.Allocator,
+	flowCtx *execinfra.FlowCtx,
+	evalCtx *tree.EvalContext,
+	spec *execinfrapb.ZigzagJoinerSpec,
+	post *execinfrapb.PostProcessSpec,
+) (*ColZigzagJoiner, error) {
+	tables := spec.BuildTableDescriptors()
+	if len(tables) != 2 {
+		return nil, errors.AssertionFailedf("zigzag joins only of two tables (or indexes) are supported, %d requested", len(tables))
+	}
+	if spec.Type != descpb.InnerJoin {
+		return nil, errors.AssertionFailedf("only inner zigzag joins are supported, %s requested", spec.Type)
+	}
+
+	// Before we can safely use types from the table descriptor, we need to
+	// make sure they are hydrated. See the comment in NewColBatchScan.
+	resolver := flowCtx.TypeResolverFactory.NewTypeResolver(evalCtx.Txn)
+	var resultTypes []*types.T
+	var sideTypes [2][]*types.T
+	for i, table := range tables {
+		sideTypes[i] = catalog.ColumnTypes(table.PublicColumns())
+		if err := resolver.HydrateTypeSlice(ctx, sideTypes[i]); err != nil {
+			return nil, err
+		}
+		resultTypes = append(resultTypes, sideTypes[i]...)
+	}
+
+	// Determine the columns that need to be fetched: the columns used by the
+	// post-processing stage and by the ON expression (which is planned on top
+	// of the ColZigzagJoiner), as well as the equality columns.
+	semaCtx := flowCtx.TypeResolverFactory.NewSemaContext(evalCtx.Txn)
+	var outputHelper execinfra.ProcOutputHelper
+	if err := outputHelper.Init(post, resultTypes, semaCtx, evalCtx, nil /* output */); err != nil {
+		return nil, err
+	}
+	neededCols := outputHelper.NeededColumns()
+	if !spec.OnExpr.Empty() {
+		var onExpr execinfrapb.ExprHelper
+		if err := onExpr.Init(spec.OnExpr, resultTypes, semaCtx, evalCtx); err != nil {
+			return nil, err
+		}
+		for i := range resultTypes {
+			if onExpr.Vars.IndexedVarUsed(i) {
+				neededCols.Add(i)
+			}
+		}
+	}
+
+	z := colZigzagJoinerPool.Get().(*ColZigzagJoiner)
+	*z = ColZigzagJoiner{
+		flowCtx:            flowCtx,
+		evalCtx:            evalCtx,
+		allocator:          allocator,
+		bufferingAllocator: bufferingAllocator,
+		memoryLimit:        execinfra.GetWorkMemLimit(flowCtx.Cfg),
+		ResultTypes:        resultTypes,
+	}
+	colOffset := 0
+	for i := range z.sides {
+		side := &z.sides[i]
+		if err := side.init(
+			flowCtx, evalCtx, allocator, bufferingAllocator, spec, i, tables[i], sideTypes[i],
+			neededCols, colOffset,
+		); err != nil {
+			return nil, err
+		}
+		s, err := z.produceSpan(side, false /* useBaseRow */)
+		if err != nil {
+			return nil, err
+		}
+		side.key, side.endKey = s.Key, s.EndKey
+		colOffset += len(sideTypes[i])
+	}
+	z.baseEqDatums = make(tree.Datums, len(z.sides[0].eqCols))
+	return z, nil
+}
+
+// init sets up the given side of the ColZigzagJoiner. neededCols are the
+// ordinals of the needed columns among the columns of both sides, where the
+// columns of this side start at colOffset.
+func (s *zigzagJoinerSide) init(
+	flowCtx *execinfra.FlowCtx,
+	evalCtx *tree.EvalContext,
+	allocator *colmem.Allocator,
+	bufferingAllocator *colmem.Allocator,
+	spec *execinfrapb.ZigzagJoinerSpec,
+	sideIdx int,
+	table catalog.TableDescriptor,
+	typs []*types.T,
+	neededCols util.FastIntSet,
+	colOffset int,
+) error {
+	if sideIdx < len(spec.FixedValues) {
+		var err error
+		s.fixedValues, err = valuesSpecToEncDatum(spec.FixedValues[sideIdx])
+		if err != nil {
+			return err
+		}
+	}
+	indexIdx := int(spec.IndexOrdinals[sideIdx])
+	if indexIdx >= len(table.ActiveIndexes()) {
+		return errors.Errorf("invalid indexIdx %d", indexIdx)
+	}
+	s.table = table
+	s.index = table.ActiveIndexes()[indexIdx].IndexDesc()
+	s.typs = typs
+	cols := table.PublicColumns()
+	columnIdxMap := catalog.ColumnIDToOrdinalMap(cols)
+
+	var columnIDs []descpb.ColumnID
+	columnIDs, s.indexDirs = s.index.FullColumnIDs()
+	s.indexTypes = make([]*types.T, len(columnIDs))
+	invertedColIdx := -1
+	for i, columnID := range columnIDs {
+		if s.index.Type == descpb.IndexDescriptor_INVERTED && columnID == s.index.InvertedColumnID() {
+			// Inverted key columns have type Bytes.
+			s.indexTypes[i] = types.Bytes
+			invertedColIdx = columnIdxMap.GetDefault(columnID)
+		} else {
+			s.indexTypes[i] = typs[columnIdxMap.GetDefault(columnID)]
+		}
+	}
+
+	var fetchedCols util.FastIntSet
+	for i, ok := neededCols.Next(colOffset); ok && i < colOffset+len(typs); i, ok = neededCols.Next(i + 1) {
+		if i-colOffset == invertedColIdx {
+			// The values of the inverted column cannot be produced from an
+			// inverted index.
+			return errors.AssertionFailedf("the inverted column cannot be outputted by the zigzag joiner")
+		}
+		s.neededCols = append(s.neededCols, i-colOffset)
+		fetchedCols.Add(i - colOffset)
+	}
+
+	s.eqCols = make([]int, len(spec.EqColumns[sideIdx].Columns))
+	s.eqTypes = make([]*types.T, len(s.eqCols))
+	s.eqDirs = make([]encoding.Direction, len(s.eqCols))
+	primaryIndex := table.GetPrimaryIndex()
+	for i, col := range spec.EqColumns[sideIdx].Columns {
+		colIdx := int(col)
+		s.eqCols[i] = colIdx
+		s.eqTypes[i] = typs[colIdx]
+		fetchedCols.Add(colIdx)
+		// Search the index columns, then the primary key columns to find the
+		// direction of the equality column.
+		colID := cols[colIdx].GetID()
+		var dir descpb.IndexDescriptor_Direction
+		if idx := findColumnID(s.index.ColumnIDs, colID); idx != -1 {
+			dir = s.index.ColumnDirections[idx]
+		} else if idx := findColumnID(primaryIndex.IndexDesc().ColumnIDs, colID); idx != -1 {
+			dir = primaryIndex.GetColumnDirection(idx)
+		} else {
+			return errors.New("ordering of equality column not found in index or primary key")
+		}
+		var err error
+		if s.eqDirs[i], err = dir.ToEncodingDirection(); err != nil {
+			return err
+		}
+	}
+	s.eqDatums = make(tree.Datums, len(s.eqCols))
+	s.eqConverter = colconv.NewVecToDatumConverter(len(typs), s.eqCols)
+
+	s.rf = cFetcherPool.Get().(*cFetcher)
+	if _, _, err := initCRowFetcher(
+		flowCtx.Codec(), allocator, execinfra.GetWorkMemLimit(flowCtx.Cfg),
+		s.rf, table, indexIdx, columnIdxMap, fetchedCols, false, /* reverse */
+		execinfra.ScanVisibilityPublic,
+		// NB: zigzag joins are disabled when a row-level locking clause is
+		// supplied, so there is no locking strength on *ZigzagJoinerSpec.
+		descpb.ScanLockingStrength_FOR_NONE, descpb.ScanLockingWaitPolicy_BLOCK,
+		nil /* virtualColumn */, false, /* withSystemColumns */
+	); err != nil {
+		return err
+	}
+	s.matches = colexecutils.NewAppendOnlyBufferedBatch(bufferingAllocator, typs, s.neededCols)
+	s.spanBuilder = span.MakeBuilder(evalCtx, flowCtx.Codec(), table, s.index)
+	s.prefix = rowenc.MakeIndexKeyPrefix(flowCtx.Codec(), table, s.index.ID)
+	return nil
+}
+
+// valuesSpecToEncDatum converts a values spec containing one tuple into
+// EncDatums for each cell (which is the way the fixed values are encoded in
+// the ZigzagJoinerSpec).
+func valuesSpecToEncDatum(valuesSpec *execinfrapb.ValuesCoreSpec) (rowenc.EncDatumRow, error) {
+	res := make(rowenc.EncDatumRow, len(valuesSpec.Columns))
+	rem := valuesSpec.RawBytes[0]
+	for i, colInfo := range valuesSpec.Columns {
+		var err error
+		res[i], rem, err = rowenc.EncDatumFromBuffer(colInfo.Type, colInfo.Encoding, rem)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// findColumnID returns the position of t in s, or -1 if it is not present.
+func findColumnID(s []descpb.ColumnID, t descpb.ColumnID) int {
+	for i := range s {
+		if s[i] == t {
+			return i
+		}
+	}
+	return -1
+}
+
+// Release implements the execinfra.Releasable interface.
+func (z *ColZigzagJoiner) Release() {
+	for i := range z.sides {
+		z.sides[i].rf.Release()
+		z.sides[i].eqConverter.Release()
+	}
+	*z = ColZigzagJoiner{}
+	colZigzagJoinerPool.Put(z)
+}
+
+// Close implements the colexecop.Closer interface.
+func (z *ColZigzagJoiner) Close(context.Context) error {
+	if z.tracingSpan != nil {
+		z.tracingSpan.Finish()
+		z.tracingSpan = nil
+	}
+	return nil
+}
@@ -161,18 +161,23 @@ func (vsc *vectorizedStatsCollectorImpl) GetStats() *execinfrapb.ComponentStats
 	}
 
 	if vsc.kvReader != nil {
-		// Note that kvReader is non-nil only for ColBatchScans, and this is the
-		// only case when we want to add the number of rows read, bytes read,
-		// and the contention time (because the wrapped row-execution KV reading
-		// processors - joinReaders, tableReaders, zigzagJoiners, and
-		// invertedJoiners - will add these statistics themselves). Similarly,
-		// for those wrapped processors it is ok to show the time as "execution
-		// time" since "KV time" would only make sense for tableReaders, and
-		// they are less likely to be wrapped than others.
+		// Note that kvReader is non-nil only for ColBatchScans,
+		// ColJoinReaders, AdaptiveJoiners, ColZigzagJoiners, and
+		// ColInvertedJoiners, and this is the only case when we want to add
+		// the number of rows read, bytes read, and the contention time
+		// (because the wrapped row-execution KV reading processors -
+		// joinReaders, tableReaders, zigzagJoiners, and invertedJoiners - will
+		// add these statistics themselves). Similarly, for those wrapped
+		// processors it is ok to show the time as "execution time" since "KV
+		// time" would only make sense for tableReaders, and they are less
+		// likely to be wrapped than others.
 		s.KV.KVTime.Set(time)
 		s.KV.TuplesRead.Set(uint64(vsc.kvReader.GetRowsRead()))
 		s.KV.BytesRead.Set(uint64(vsc.kvReader.GetBytesRead()))
 		s.KV.ContentionTime.Set(vsc.kvReader.GetCumulativeContentionTime())
+		if aj, ok := vsc.kvReader.(*colexec.AdaptiveJoiner); ok {
+			s.Exec.JoinStrategy = aj.GetJoinStrategy()
+		}
 	} else {
 		s.Exec.ExecTime.Set(time)
 	}
@@ -881,6 +881,9 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	}
 
 	if closeType != panicClose {
+		// Close all cursors, including the ones declared WITH HOLD.
+		ex.extraTxnState.sqlCursors.closeAll(ctx)
+
 		// Close all statements and prepared portals.
 		ex.extraTxnState.prepStmtsNamespace.resetTo(
 			ctx, prepStmtNamespace{}, &ex.extraTxnState.prepStmtsNamespaceMemAcc,
@@ -1060,6 +1063,13 @@ type connExecutor struct {
 		// connExecutor's closure.
 		prepStmtsNamespaceMemAcc mon.BoundAccount
 
+		// sqlCursors contains the SQL cursors declared in this session. Cursors
+		// declared WITHOUT HOLD are closed when the transaction that declared
+		// them finishes; WITH HOLD cursors are materialized when that
+		// transaction commits and remain open until closed explicitly or until
+		// the session ends.
+		sqlCursors cursorMap
+
 		// onTxnFinish (if non-nil) will be called when txn is finished (either
 		// committed or aborted). It is set when txn is started but can remain
 		// unset when txn is executed within another higher-level txn.
@@ -1093,6 +1103,10 @@ type connExecutor struct {
 
 		schemaChangerState SchemaChangerState
 
+		// deferredConstraints tracks the checks of DEFERRABLE constraints that
+		// were postponed until the transaction commits.
+		deferredConstraints DeferredConstraintState
+
 		// shouldCollectTxnExecutionStats specifies whether the statements in
 		// this transaction should collect execution stats.
 		shouldCollectTxnExecutionStats bool
@@ -1305,6 +1319,8 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 		}
 	}
 
+	ex.extraTxnState.deferredConstraints = DeferredConstraintState{}
+
 	for k := range ex.extraTxnState.schemaChangeJobsCache {
 		delete(ex.extraTxnState.schemaChangeJobsCache, k)
 	}
@@ -1312,20 +1328,21 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 	ex.extraTxnState.descCollection.ReleaseAll(ctx)
 
 	// Close all portals.
-	for name, p := range ex.extraTxnState.prepStmtsNamespace.portals {
-		p.decRef(ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc, name)
-		delete(ex.extraTxnState.prepStmtsNamespace.portals, name)
+	for name := range ex.extraTxnState.prepStmtsNamespace.portals {
+		ex.deletePortal(ctx, name)
 	}
 
 	switch ev {
 	case txnCommit, txnRollback:
 		ex.extraTxnState.savepoints.clear()
+		ex.extraTxnState.sqlCursors.finishTxn(ctx, ev == txnCommit)
 		// After txn is finished, we need to call onTxnFinish (if it's non-nil).
 		if ex.extraTxnState.onTxnFinish != nil {
 			ex.extraTxnState.onTxnFinish(ev)
 			ex.extraTxnState.onTxnFinish = nil
 		}
 	case txnRestart:
+		ex.extraTxnState.sqlCursors.finishTxn(ctx, false /* committed */)
 		if ex.extraTxnState.onTxnRestart != nil {
 			ex.extraTxnState.onTxnRestart()
 		}
@@ -1580,6 +1597,13 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 				Values: portal.Qargs,
 			}
 
+			// A pausable portal enforces its row limit itself, so the result
+			// doesn't need to.
+			pausable := portal.pauseInfo != nil || (tcmd.Limit > 0 && ex.isPausablePortal(portal))
+			limit := tcmd.Limit
+			if pausable {
+				limit = 0
+			}
 			stmtRes := ex.clientComm.CreateStatementResult(
 				portal.Stmt.AST,
 				// The client is using the extended protocol, so no row description is
@@ -1588,12 +1612,16 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 				pos, portal.OutFormats,
 				ex.sessionData.DataConversionConfig,
 				ex.sessionData.GetLocation(),
-				tcmd.Limit,
+				limit,
 				portalName,
 				ex.implicitTxn(),
 			)
 			res = stmtRes
-			ev, payload, err = ex.execPortal(ctx, portal, portalName, stmtRes, pinfo)
+			if pausable {
+				ev, payload, err = ex.execPausablePortal(ctx, portal, portalName, stmtRes, tcmd.Limit)
+			} else {
+				ev, payload, err = ex.execPortal(ctx, portal, portalName, stmtRes, pinfo)
+			}
 			return err
 		}()
 		// Note: we write to ex.statsCollector.phaseTimes, instead of ex.phaseTimes,
@@ -2246,6 +2274,7 @@ func (ex *connExecutor) resetEvalCtx(evalCtx *extendedEvalContext, txn *kv.Txn,
 	evalCtx.PrepareOnly = false
 	evalCtx.SkipNormalize = false
 	evalCtx.SchemaChangerState = &ex.extraTxnState.schemaChangerState
+	evalCtx.DeferredConstraints = &ex.extraTxnState.deferredConstraints
 }
 
 // getTransactionState retrieves a text representation of the given state.
@@ -2275,6 +2304,7 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.sessionDataMutator = ex.dataMutator
 	p.noticeSender = nil
 	p.preparedStatements = ex.getPrepStmtsAccessor()
+	p.sqlCursors = &ex.extraTxnState.sqlCursors
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/explain"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -39,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
+	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/fsm"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -46,6 +48,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
 )
 
 // execStmt executes one statement by dispatching according to the current
@@ -226,6 +229,237 @@ func (ex *connExecutor) execPortal(
 	}
 }
 
+// isPausablePortal returns whether the given portal, which is being executed
+// with a row limit, can be suspended once the limit is reached and resumed
+// later, after other statements and portals have been executed. This is only
+// supported, when enabled by the session, for read-only queries in explicit
+// transactions; other portals need to be executed to completion before other
+// commands are executed (see pgwire.limitedCommandResult).
+func (ex *connExecutor) isPausablePortal(portal PreparedPortal) bool {
+	if !ex.sessionData.MultipleActivePortalsEnabled {
+		return false
+	}
+	if _, ok := ex.machine.CurState().(stateOpen); !ok || ex.implicitTxn() {
+		return false
+	}
+	if _, ok := portal.Stmt.AST.(*tree.Select); !ok || portal.Stmt.Memo == nil {
+		return false
+	}
+	return !portal.Stmt.Memo.RootExpr().(memo.RelExpr).Relational().CanMutate
+}
+
+// execPausablePortal executes a portal for which isPausablePortal returned
+// true. At most limit rows are returned (all the remaining ones if limit is
+// 0); if the portal's query has more rows, the portal is suspended and its
+// query's execution is paused until the portal is executed again.
+//
+// The query runs through an internal executor inside the session's
+// transaction, like the query of a SQL cursor. It reads at the sequence number
+// of the transaction from the time the portal was first executed, so writes
+// performed by the transaction in the meantime are not visible to it.
+func (ex *connExecutor) execPausablePortal(
+	ctx context.Context, portal PreparedPortal, portalName string, res CommandResult, limit int,
+) (fsm.Event, fsm.EventPayload, error) {
+	if _, ok := ex.machine.CurState().(stateOpen); !ok {
+		// The statements of an aborted transaction are rejected by execStmt.
+		pinfo := &tree.PlaceholderInfo{
+			PlaceholderTypesInfo: tree.PlaceholderTypesInfo{
+				TypeHints: portal.Stmt.TypeHints,
+				Types:     portal.Stmt.Types,
+			},
+			Values: portal.Qargs,
+		}
+		return ex.execPortal(ctx, portal, portalName, res, pinfo)
+	}
+	if portal.exhausted {
+		return nil, nil, nil
+	}
+
+	pauseInfo := portal.pauseInfo
+	if pauseInfo == nil {
+		// The query outlives this execution of the portal, so it must not use
+		// its context.
+		pauseInfo = &portalPauseInfo{}
+		pauseInfo.ctx, pauseInfo.cancel = contextutil.WithCancel(
+			logtags.WithTags(context.Background(), logtags.FromContext(ctx)),
+		)
+	} else if pauseInfo.rows == nil {
+		// The portal's copy that was resumed by a transaction rewind refers to
+		// a query that was stopped when the transaction restarted.
+		err := pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"portal %q was closed by a transaction restart", portalName)
+		return eventNonRetriableErr{IsCommit: fsm.False}, eventNonRetriableErrPayload{err: err}, nil
+	}
+
+	// Each execution of the portal is registered as an active query of the
+	// session, so that it can be canceled and is subject to the statement
+	// timeout. Canceling it stops the portal's query, which is not resumed
+	// afterwards.
+	ctx, cancel := contextutil.WithCancel(ctx)
+	defer cancel()
+	queryID := ex.generateID()
+	unregisterFn := ex.addActiveQuery(portal.Stmt.AST, portal.Stmt.SQL, queryID, func() {
+		cancel()
+		pauseInfo.cancel()
+	})
+	defer unregisterFn()
+	ex.incrementStartedStmtCounter(portal.Stmt.AST)
+
+	var timeoutTicker *time.Timer
+	queryTimedOut := false
+	doneAfterFunc := make(chan struct{}, 1)
+	// stopTimeout stops the statement timeout timer, if any, and returns
+	// whether the query timed out.
+	stopTimeout := func() bool {
+		if timeoutTicker != nil {
+			if !timeoutTicker.Stop() {
+				// Wait for the timer callback to complete to avoid a data race on
+				// queryTimedOut.
+				<-doneAfterFunc
+			}
+			timeoutTicker = nil
+		}
+		return queryTimedOut
+	}
+	defer stopTimeout()
+	makeErrEvent := func(err error) (fsm.Event, fsm.EventPayload, error) {
+		// As in execStmtInOpenState, errors caused by the cancelation of the
+		// query are replaced by a nicer one.
+		if stopTimeout() {
+			err = sqlerrors.QueryTimeoutError
+		} else if ctx.Err() != nil || pauseInfo.ctx.Err() != nil {
+			err = cancelchecker.QueryCanceledError
+		}
+		return eventNonRetriableErr{IsCommit: fsm.False}, eventNonRetriableErrPayload{err: err}, nil
+	}
+	if ex.sessionData.StmtTimeout > 0 {
+		timerDuration := ex.sessionData.StmtTimeout - timeutil.Since(ex.phaseTimes[sessionQueryReceived])
+		if timerDuration < 0 {
+			queryTimedOut = true
+			_ = pauseInfo.close()
+			ex.exhaustPortal(portalName)
+			return makeErrEvent(sqlerrors.QueryTimeoutError)
+		}
+		timeoutTicker = time.AfterFunc(
+			timerDuration,
+			func() {
+				ex.cancelQuery(queryID)
+				queryTimedOut = true
+				doneAfterFunc <- struct{}{}
+			})
+	}
+
+	if portal.pauseInfo == nil {
+		query, err := ex.formatPortalQuery(portal)
+		if err != nil {
+			_ = pauseInfo.close()
+			ex.exhaustPortal(portalName)
+			return makeErrEvent(err)
+		}
+		txn := ex.state.mu.txn
+		rows, err := newPausedQueryIterator(
+			pauseInfo.ctx, ex.server.cfg, ex.sessionData, txn, "sql-portal", query,
+		)
+		if err != nil {
+			_ = pauseInfo.close()
+			ex.exhaustPortal(portalName)
+			return makeErrEvent(err)
+		}
+		pauseInfo.rows = rows
+		pauseInfo.txn = txn
+		pauseInfo.readSeqNum = txn.GetLeafTxnInputState(ctx).ReadSeqNum
+		portal.pauseInfo = pauseInfo
+		ex.extraTxnState.prepStmtsNamespace.portals[portalName] = portal
+	}
+	res.SetColumns(ctx, pauseInfo.rows.Types())
+
+	ex.mu.Lock()
+	ex.mu.ActiveQueries[queryID].phase = executing
+	ex.mu.Unlock()
+
+	txn := pauseInfo.txn
+	origReadSeqNum := txn.GetLeafTxnInputState(ctx).ReadSeqNum
+	if err := txn.SetReadSeqNum(pauseInfo.readSeqNum); err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if err := txn.SetReadSeqNum(origReadSeqNum); err != nil {
+			log.Warningf(ctx, "failed to restore read sequence number: %v", err)
+		}
+	}()
+
+	for n := 0; limit == 0 || n < limit; n++ {
+		ok, err := pauseInfo.rows.Next(ctx)
+		if err == nil && !ok {
+			// A query whose context was canceled while the portal was suspended
+			// stops without an error.
+			err = pauseInfo.ctx.Err()
+		}
+		if err != nil || !ok {
+			_ = pauseInfo.close()
+			ex.exhaustPortal(portalName)
+			if err != nil {
+				return makeErrEvent(err)
+			}
+			ex.incrementExecutedStmtCounter(portal.Stmt.AST)
+			return nil, nil, nil
+		}
+		if err := res.AddRow(ctx, pauseInfo.rows.Cur()); err != nil {
+			return nil, nil, err
+		}
+	}
+	res.SetPortalSuspended()
+	ex.incrementExecutedStmtCounter(portal.Stmt.AST)
+	return nil, nil, nil
+}
+
+// formatPortalQuery returns the text of the portal's query, with the
+// placeholders replaced by the values they were bound to.
+func (ex *connExecutor) formatPortalQuery(portal PreparedPortal) (string, error) {
+	evalCtx := &ex.planner.extendedEvalCtx.EvalContext
+	var evalErr error
+	f := tree.NewFmtCtx(tree.FmtParsable)
+	f.SetPlaceholderFormat(func(f *tree.FmtCtx, placeholder *tree.Placeholder) {
+		if evalErr != nil {
+			return
+		}
+		d, err := portal.Qargs[placeholder.Idx].Eval(evalCtx)
+		if err != nil {
+			evalErr = errors.Wrapf(err, "evaluating placeholder %s", placeholder)
+			return
+		}
+		if d == tree.DNull {
+			// NULL needs an explicit type for the query to be typed the way it
+			// was when it was prepared.
+			f.FormatNode(&tree.CastExpr{
+				Expr: tree.DNull, Type: portal.Stmt.Types[placeholder.Idx], SyntaxMode: tree.CastShort,
+			})
+			return
+		}
+		d.Format(f)
+	})
+	f.FormatNode(portal.Stmt.AST)
+	query := f.CloseAndGetString()
+	if evalErr != nil {
+		return "", evalErr
+	}
+	return query, nil
+}
+
+// closeSuspendedPortals stops the paused queries of all the suspended portals.
+// The portals themselves remain open until the end of the transaction, but
+// can no longer be resumed.
+func (ex *connExecutor) closeSuspendedPortals(ctx context.Context) {
+	for name, portal := range ex.extraTxnState.prepStmtsNamespace.portals {
+		if portal.pauseInfo == nil {
+			continue
+		}
+		if err := portal.pauseInfo.close(); err != nil {
+			log.Warningf(ctx, "error closing suspended portal %q: %v", name, err)
+		}
+	}
+}
+
 // execStmtInOpenState executes one statement in the context of the session's
 // current transaction.
 // It handles statements that affect the transaction state (BEGIN, COMMIT)
@@ -755,6 +989,15 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 		}
 	}
 
+	if pending := ex.extraTxnState.deferredConstraints.pending; len(pending) > 0 {
+		ie := ex.planner.extendedEvalCtx.InternalExecutor.(*InternalExecutor)
+		if err := validateDeferredConstraints(
+			ctx, pending, ie, ex.state.mu.txn, ex.server.cfg.Codec,
+		); err != nil {
+			return err
+		}
+	}
+
 	if err := ex.extraTxnState.descCollection.ValidateUncommittedDescriptors(ctx, ex.state.mu.txn); err != nil {
 		return err
 	}
@@ -763,6 +1006,15 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 		return err
 	}
 
+	// Cursors declared WITH HOLD outlive the transaction, so their results
+	// need to be read in full while the transaction can still be used.
+	if err := ex.persistHeldCursors(ctx); err != nil {
+		return err
+	}
+	// Suspended portals are closed at the end of the transaction; their paused
+	// queries must be stopped before the transaction is committed.
+	ex.closeSuspendedPortals(ctx)
+
 	if err := ex.state.mu.txn.Commit(ctx); err != nil {
 		return err
 	}
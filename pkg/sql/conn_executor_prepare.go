@@ -466,6 +466,11 @@ func (ex *connExecutor) deletePortal(ctx context.Context, name string) {
 	if !ok {
 		return
 	}
+	if portal.pauseInfo != nil {
+		if err := portal.pauseInfo.close(); err != nil {
+			log.Warningf(ctx, "error closing suspended portal %q: %v", name, err)
+		}
+	}
 	portal.decRef(ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc, name)
 	delete(ex.extraTxnState.prepStmtsNamespace.portals, name)
 }
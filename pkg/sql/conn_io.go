@@ -639,6 +639,12 @@ type ClientComm interface {
 type CommandResult interface {
 	RestrictedCommandResult
 	CommandResultClose
+
+	// SetPortalSuspended marks the result as belonging to the execution of a
+	// portal that was suspended after returning the requested number of rows.
+	// When the result is closed, the client is told that the portal was
+	// suspended instead of that the command completed.
+	SetPortalSuspended()
 }
 
 // CommandResultErrBase is the subset of CommandResult dealing with setting a
@@ -959,6 +965,9 @@ func (r *streamingCommandResult) Discard() {
 	}
 }
 
+// SetPortalSuspended is part of the CommandResult interface.
+func (r *streamingCommandResult) SetPortalSuspended() {}
+
 // SetInferredTypes is part of the DescribeResult interface.
 func (r *streamingCommandResult) SetInferredTypes([]oid.Oid) {}
 
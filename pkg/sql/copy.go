@@ -14,7 +14,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/binary"
-	"encoding/csv"
 	"io"
 	"strconv"
 	"strings"
@@ -32,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/errors"
@@ -41,6 +41,95 @@ type copyMachineInterface interface {
 	run(ctx context.Context) error
 }
 
+// CopyFormatOptions are the evaluated options of a COPY statement which
+// describe how rows are encoded. They are shared by COPY FROM, which is
+// executed by the copyMachine, and COPY TO, whose rows are encoded by the
+// pgwire connection.
+type CopyFormatOptions struct {
+	Format tree.CopyFormat
+	// Delimiter separates the fields of a row in the text and CSV formats.
+	Delimiter byte
+	// Null is the string that represents a NULL value in the text and CSV
+	// formats.
+	Null string
+	// Quote is the character used to quote fields in the CSV format.
+	Quote byte
+	// Header is set if the first line of CSV data contains the column names.
+	Header bool
+}
+
+// MakeCopyFormatOptions validates the options of a COPY statement and fills
+// in the defaults of the requested format. evalString is used to evaluate
+// the expressions of the DELIMITER, NULL and QUOTE options.
+func MakeCopyFormatOptions(
+	opts *tree.CopyOptions, evalString func(tree.Expr) (string, error),
+) (CopyFormatOptions, error) {
+	o := CopyFormatOptions{Format: opts.CopyFormat}
+	switch o.Format {
+	case tree.CopyFormatText:
+		o.Null = `\N`
+		o.Delimiter = '\t'
+	case tree.CopyFormatCSV:
+		o.Null = ""
+		o.Delimiter = ','
+		o.Quote = '"'
+	}
+
+	if opts.Delimiter != nil {
+		if o.Format == tree.CopyFormatBinary {
+			return o, pgerror.Newf(pgcode.Syntax, "DELIMITER unsupported in BINARY format")
+		}
+		delim, err := evalString(opts.Delimiter)
+		if err != nil {
+			return o, err
+		}
+		if len(delim) != 1 || !utf8.ValidString(delim) {
+			return o, pgerror.Newf(pgcode.FeatureNotSupported,
+				"delimiter must be a single-byte character")
+		}
+		o.Delimiter = delim[0]
+	}
+	if opts.Null != nil {
+		if o.Format == tree.CopyFormatBinary {
+			return o, pgerror.Newf(pgcode.Syntax, "NULL unsupported in BINARY format")
+		}
+		null, err := evalString(opts.Null)
+		if err != nil {
+			return o, err
+		}
+		o.Null = null
+	}
+	if opts.Quote != nil {
+		if o.Format != tree.CopyFormatCSV {
+			return o, pgerror.Newf(pgcode.FeatureNotSupported, "QUOTE available only in CSV mode")
+		}
+		quote, err := evalString(opts.Quote)
+		if err != nil {
+			return o, err
+		}
+		if len(quote) != 1 || !utf8.ValidString(quote) {
+			return o, pgerror.Newf(pgcode.FeatureNotSupported,
+				"quote must be a single-byte character")
+		}
+		o.Quote = quote[0]
+	}
+	if opts.Header {
+		if o.Format != tree.CopyFormatCSV {
+			return o, pgerror.Newf(pgcode.FeatureNotSupported, "HEADER available only in CSV mode")
+		}
+		o.Header = true
+	}
+	if o.Format == tree.CopyFormatCSV && o.Delimiter == o.Quote {
+		return o, pgerror.Newf(pgcode.InvalidParameterValue,
+			"delimiter and quote must be different")
+	}
+	if o.Format != tree.CopyFormatBinary && (o.Delimiter == '\n' || o.Delimiter == '\r') {
+		return o, pgerror.Newf(pgcode.InvalidParameterValue,
+			"delimiter cannot be newline or carriage return")
+	}
+	return o, nil
+}
+
 // copyMachine supports the Copy-in pgwire subprotocol (COPY...FROM STDIN). The
 // machine is created by the Executor when that statement is executed; from that
 // moment on, the machine takes control of the pgwire connection until
@@ -58,11 +147,17 @@ type copyMachine struct {
 	table         tree.TableExpr
 	columns       tree.NameList
 	resultColumns colinfo.ResultColumns
-	format        tree.CopyFormat
-	delimiter     byte
+	// where, if set, filters the rows that are inserted.
+	where     *tree.Where
+	format    tree.CopyFormat
+	delimiter byte
 	// textDelim is delimiter converted to a []byte so that we don't have to do that per row.
-	textDelim   []byte
-	null        string
+	textDelim []byte
+	null      string
+	quote     byte
+	// skipHeader is set while the header line of CSV data has not been read
+	// yet.
+	skipHeader  bool
 	binaryState binaryState
 	// forceNotNull disables converting values matching the null string to
 	// NULL. The spec says this is only supported for CSV, and also must specify
@@ -119,7 +214,7 @@ func newCopyMachine(
 		//  but that dependency can be removed by refactoring it.
 		table:   &n.Table,
 		columns: n.Columns,
-		format:  n.Options.CopyFormat,
+		where:   n.Where,
 		txnOpt:  txnOpt,
 		// The planner will be prepared before use.
 		p:              planner{execCfg: execCfg, alloc: &rowenc.DatumAlloc{}},
@@ -134,45 +229,21 @@ func newCopyMachine(
 	}()
 	c.parsingEvalCtx = c.p.EvalContext()
 
-	switch c.format {
-	case tree.CopyFormatText:
-		c.null = `\N`
-		c.delimiter = '\t'
-	case tree.CopyFormatCSV:
-		c.null = ""
-		c.delimiter = ','
-	}
-
-	if n.Options.Delimiter != nil {
-		if c.format == tree.CopyFormatBinary {
-			return nil, errors.Newf("DELIMITER unsupported in BINARY format")
-		}
-		fn, err := c.p.TypeAsString(ctx, n.Options.Delimiter, "COPY")
-		if err != nil {
-			return nil, err
-		}
-		delim, err := fn()
-		if err != nil {
-			return nil, err
-		}
-		if len(delim) != 1 || !utf8.ValidString(delim) {
-			return nil, errors.Newf("delimiter must be a single-byte character")
-		}
-		c.delimiter = delim[0]
-	}
-	if n.Options.Null != nil {
-		if c.format == tree.CopyFormatBinary {
-			return nil, errors.Newf("NULL unsupported in BINARY format")
-		}
-		fn, err := c.p.TypeAsString(ctx, n.Options.Null, "COPY")
-		if err != nil {
-			return nil, err
-		}
-		c.null, err = fn()
+	opts, err := MakeCopyFormatOptions(&n.Options, func(e tree.Expr) (string, error) {
+		fn, err := c.p.TypeAsString(ctx, e, "COPY")
 		if err != nil {
-			return nil, err
+			return "", err
 		}
+		return fn()
+	})
+	if err != nil {
+		return nil, err
 	}
+	c.format = opts.Format
+	c.delimiter = opts.Delimiter
+	c.null = opts.Null
+	c.quote = opts.Quote
+	c.skipHeader = opts.Header
 
 	flags := tree.ObjectLookupFlagsWithRequiredTableKind(tree.ResolveRequireTableDesc)
 	tableDesc, err := resolver.ResolveExistingTableObject(ctx, &c.p, &n.Table, flags)
@@ -242,6 +313,7 @@ func (c *copyMachine) run(ctx context.Context) error {
 	case tree.CopyFormatCSV:
 		c.csvReader = csv.NewReader(&c.buf)
 		c.csvReader.Comma = rune(c.delimiter)
+		c.csvReader.Quote = rune(c.quote)
 		c.csvReader.ReuseRecord = true
 		c.csvReader.FieldsPerRecord = len(c.resultColumns)
 	}
@@ -385,6 +457,10 @@ func (c *copyMachine) readCSVData(ctx context.Context, final bool) (brk bool, er
 		return false, pgerror.Wrap(err, pgcode.BadCopyFileFormat,
 			"read CSV record")
 	}
+	if c.skipHeader {
+		c.skipHeader = false
+		return false, nil
+	}
 	err = c.readCSVTuple(ctx, record)
 	return false, err
 }
@@ -587,7 +663,7 @@ func (c *copyMachine) insertRows(ctx context.Context) (retErr error) {
 		Table:   c.table,
 		Columns: c.columns,
 		Rows: &tree.Select{
-			Select: vc,
+			Select: c.filterRows(vc),
 		},
 		Returning: tree.AbsentReturningClause,
 	}
@@ -604,15 +680,48 @@ func (c *copyMachine) insertRows(ctx context.Context) (retErr error) {
 		return err
 	}
 
-	if rows := res.RowsAffected(); rows != numRows {
+	rows := res.RowsAffected()
+	if c.where == nil && rows != numRows {
 		log.Fatalf(ctx, "didn't insert all buffered rows and yet no error was reported. "+
 			"Inserted %d out of %d rows.", rows, numRows)
 	}
-	c.insertedRows += numRows
+	c.insertedRows += rows
 
 	return nil
 }
 
+// filterRows returns the source of the rows inserted by insertRows. If the
+// COPY statement has a WHERE clause, the buffered rows are filtered with a
+// query of the form:
+//
+//   SELECT * FROM (VALUES ...) AS t (col1, col2, ...) WHERE ...
+//
+// where t is the name of the destination table, so that the condition can
+// reference the copied columns.
+func (c *copyMachine) filterRows(vc *tree.ValuesClause) tree.SelectStatement {
+	if c.where == nil {
+		return vc
+	}
+	cols := make(tree.NameList, len(c.resultColumns))
+	for i := range c.resultColumns {
+		cols[i] = tree.Name(c.resultColumns[i].Name)
+	}
+	alias := tree.AliasClause{Cols: cols}
+	if tn, ok := c.table.(*tree.TableName); ok {
+		alias.Alias = tn.ObjectName
+	}
+	return &tree.SelectClause{
+		Exprs: tree.SelectExprs{tree.StarSelectExpr()},
+		From: tree.From{
+			Tables: tree.TableExprs{&tree.AliasedTableExpr{
+				Expr: &tree.Subquery{Select: &tree.ParenSelect{Select: &tree.Select{Select: vc}}},
+				As:   alias,
+			}},
+		},
+		Where: c.where,
+	}
+}
+
 func (c *copyMachine) readTextTuple(ctx context.Context, line []byte) error {
 	parts := bytes.Split(line, c.textDelim)
 	if len(parts) != len(c.resultColumns) {
@@ -87,6 +87,9 @@ func newFileUploadMachine(
 	if len(n.Columns) != 0 {
 		return nil, errors.New("expected 0 columns specified for file uploads")
 	}
+	if n.Where != nil {
+		return nil, errors.New("WHERE is not supported for file uploads")
+	}
 	c := &copyMachine{
 		conn: conn,
 		// The planner will be prepared before use.
@@ -0,0 +1,300 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package costcalibration
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+var calibrationEnabled = settings.RegisterBoolSetting(
+	"sql.optimizer.cost_model.calibration.enabled",
+	"if set, the execution statistics of sampled statements are used to fit the cost model factors "+
+		"to the hardware of the cluster",
+	false,
+)
+
+var calibrationInterval = settings.RegisterDurationSetting(
+	"sql.optimizer.cost_model.calibration.interval",
+	"rate at which the cost model factors are fit to the execution statistics collected since the last fit",
+	time.Hour,
+)
+
+// minRows and minLookups are the minimum amounts of work that must be
+// observed for each kind of cost before the cost model factors are fit, so
+// that a handful of statements don't skew the factors.
+const (
+	minRows    = 1000
+	minLookups = 100
+)
+
+// Calibrator fits the cost model factors of the optimizer (see
+// memo.CostFactors) to the execution statistics of the statements that are
+// sampled for statistics collection (see sql.txn_stats.sample_rate).
+//
+// The statistics of each processor are classified according to the kind of
+// work that the coster models: sequential reads for table readers, random
+// reads for join readers and inverted joiners, and CPU for the processors
+// that don't read from KV. Periodically, the Calibrator fits the cost of each
+// kind of work to the time it took, and writes the resulting factors to the
+// cluster settings. Only the node that holds the meta1 lease fits the factors,
+// to the statements that it sampled itself, so that the nodes don't overwrite
+// each other's factors and invalidate the cached plans of the whole cluster
+// every time. The other nodes discard their observations.
+type Calibrator struct {
+	mu struct {
+		syncutil.Mutex
+		obs observations
+	}
+	st    *cluster.Settings
+	ie    sqlutil.InternalExecutor
+	clock *hlc.Clock
+	// isMeta1Leaseholder returns whether the node holds the meta1 lease at the
+	// given time. It is nil on secondary tenants, which can't tell which of
+	// their SQL pods should fit the factors, so the calibration is disabled
+	// there.
+	isMeta1Leaseholder func(context.Context, hlc.ClockTimestamp) (bool, error)
+}
+
+// NewCalibrator constructs a new Calibrator. isMeta1Leaseholder is nil on
+// secondary tenants.
+func NewCalibrator(
+	ie sqlutil.InternalExecutor,
+	st *cluster.Settings,
+	clock *hlc.Clock,
+	isMeta1Leaseholder func(context.Context, hlc.ClockTimestamp) (bool, error),
+) *Calibrator {
+	return &Calibrator{ie: ie, st: st, clock: clock, isMeta1Leaseholder: isMeta1Leaseholder}
+}
+
+// Enabled returns true if the Calibrator needs the processor statistics of
+// sampled statements. It allows callers to avoid extracting them otherwise.
+func (c *Calibrator) Enabled() bool {
+	return c.isMeta1Leaseholder != nil && calibrationEnabled.Get(&c.st.SV)
+}
+
+// Record adds the statistics of the processors of a sampled statement to the
+// observations used for the next fit.
+func (c *Calibrator) Record(stats []execstats.ProcessorStats) {
+	var obs observations
+	for i := range stats {
+		obs.add(&stats[i])
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.obs.merge(&obs)
+}
+
+// Start will start the calibration loop for the Calibrator.
+func (c *Calibrator) Start(ctx context.Context, stopper *stop.Stopper) {
+	if c.isMeta1Leaseholder == nil {
+		log.Infof(ctx, "cost model calibration is not supported on secondary tenants")
+		return
+	}
+	sqlutil.StartPoller(
+		ctx, stopper, "cost-model-calibration", &c.st.SV, calibrationInterval,
+		"cost model calibration", c.fitCostFactors,
+	)
+}
+
+// fitCostFactors fits the cost model factors to the observations and writes
+// them to the cluster settings, if the node holds the meta1 lease. The
+// observations are kept until there are enough of them for a fit.
+func (c *Calibrator) fitCostFactors(ctx context.Context) error {
+	isLeaseholder := false
+	if c.Enabled() {
+		var err error
+		if isLeaseholder, err = c.isMeta1Leaseholder(ctx, c.clock.NowAsClockTimestamp()); err != nil {
+			return err
+		}
+	}
+	c.mu.Lock()
+	if !isLeaseholder {
+		// Discard the observations from before the calibration was disabled,
+		// or that another node is responsible for.
+		c.mu.obs = observations{}
+		c.mu.Unlock()
+		return nil
+	}
+	obs := c.mu.obs
+	c.mu.Unlock()
+
+	current := memo.MakeCostFactors(&c.st.SV)
+	factors, ok := obs.fit(current.SeqIO)
+	if !ok {
+		return nil
+	}
+	log.Infof(ctx, "calibrated cost model from %d scanned rows (%d bytes), %d lookups and %d processed rows: "+
+		"cpu_cost_factor=%g, rand_io_cost_factor=%g",
+		obs.scanRows, obs.scanBytes, obs.lookups, obs.cpuRows, factors.CPU, factors.RandIO)
+
+	for _, s := range []struct {
+		name           string
+		value, current memo.Cost
+	}{
+		{name: "sql.optimizer.cost_model.cpu_cost_factor", value: factors.CPU, current: current.CPU},
+		{name: "sql.optimizer.cost_model.rand_io_cost_factor", value: factors.RandIO, current: current.RandIO},
+	} {
+		if s.value == s.current {
+			// Changing a cost factor invalidates the cached plans, so a setting
+			// that doesn't change isn't written.
+			continue
+		}
+		stmt := fmt.Sprintf(
+			"SET CLUSTER SETTING %s = %s", s.name, strconv.FormatFloat(float64(s.value), 'g', -1, 64),
+		)
+		if _, err := c.ie.ExecEx(
+			ctx, "cost-model-calibration", nil, /* txn */
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			stmt,
+		); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.obs.sub(&obs)
+	return nil
+}
+
+// observations are the totals of the execution statistics of the processors
+// of sampled statements, grouped by the kind of work that the coster models.
+type observations struct {
+	// scanRows, scanBytes and scanTime are the rows and bytes read
+	// sequentially from KV by table readers, and the time it took.
+	scanRows  int64
+	scanBytes int64
+	scanTime  time.Duration
+
+	// lookups is the number of random reads from KV performed by join readers
+	// and inverted joiners, i.e. the number of their input rows. lookupRows,
+	// lookupBytes and lookupTime are the rows and bytes they retrieved, and the
+	// time it took.
+	lookups     int64
+	lookupRows  int64
+	lookupBytes int64
+	lookupTime  time.Duration
+
+	// cpuRows is the number of rows processed by the processors that don't
+	// read from KV, and cpuTime is the time that they spent doing so.
+	cpuRows int64
+	cpuTime time.Duration
+}
+
+// add adds the statistics of a processor to the observations. Processors that
+// don't fit one of the kinds of work, like zigzag joiners, are ignored.
+func (o *observations) add(p *execstats.ProcessorStats) {
+	s := p.Stats
+	var inputRows int64
+	for i := range s.Inputs {
+		inputRows += int64(s.Inputs[i].NumTuples.Value())
+	}
+	switch {
+	case p.Core.TableReader != nil:
+		o.scanRows += int64(s.KV.TuplesRead.Value())
+		o.scanBytes += int64(s.KV.BytesRead.Value())
+		o.scanTime += s.KV.KVTime.Value()
+
+	case p.Core.JoinReader != nil, p.Core.InvertedJoiner != nil:
+		// The vectorized engine doesn't report the input rows, in which case
+		// each retrieved row is assumed to need its own lookup.
+		lookups := inputRows
+		if len(s.Inputs) == 0 {
+			lookups = int64(s.KV.TuplesRead.Value())
+		}
+		o.lookups += lookups
+		o.lookupRows += int64(s.KV.TuplesRead.Value())
+		o.lookupBytes += int64(s.KV.BytesRead.Value())
+		o.lookupTime += s.KV.KVTime.Value()
+
+	case p.Core.ZigzagJoiner != nil:
+		// Zigzag joiners interleave sequential and random reads, which can't be
+		// told apart.
+
+	case s.Exec.ExecTime.HasValue():
+		// The coster charges most operators per input row, but the vectorized
+		// engine only reports the output rows.
+		rows := inputRows
+		if len(s.Inputs) == 0 {
+			rows = int64(s.Output.NumTuples.Value())
+		}
+		o.cpuRows += rows
+		o.cpuTime += s.Exec.ExecTime.Value()
+	}
+}
+
+func (o *observations) merge(other *observations) {
+	o.scanRows += other.scanRows
+	o.scanBytes += other.scanBytes
+	o.scanTime += other.scanTime
+	o.lookups += other.lookups
+	o.lookupRows += other.lookupRows
+	o.lookupBytes += other.lookupBytes
+	o.lookupTime += other.lookupTime
+	o.cpuRows += other.cpuRows
+	o.cpuTime += other.cpuTime
+}
+
+func (o *observations) sub(other *observations) {
+	o.scanRows -= other.scanRows
+	o.scanBytes -= other.scanBytes
+	o.scanTime -= other.scanTime
+	o.lookups -= other.lookups
+	o.lookupRows -= other.lookupRows
+	o.lookupBytes -= other.lookupBytes
+	o.lookupTime -= other.lookupTime
+	o.cpuRows -= other.cpuRows
+	o.cpuTime -= other.cpuTime
+}
+
+// fit returns the cost model factors for which the cost of each kind of
+// observed work is proportional to the time it took, keeping the given
+// sequential I/O cost factor. Each time per unit of work is the ratio of the
+// total time to the total work, which is the least squares fit of a
+// proportional model whose error grows with the amount of work. ok is false if
+// there are not enough observations for a fit.
+func (o *observations) fit(seqIOCostFactor memo.Cost) (_ memo.CostFactors, ok bool) {
+	if o.scanRows < minRows || o.lookups < minLookups || o.cpuRows < minRows ||
+		o.scanTime <= 0 || o.cpuTime <= 0 {
+		return memo.CostFactors{}, false
+	}
+	seqIOTime := float64(o.scanTime) / float64(o.scanRows)
+	cpuTime := float64(o.cpuTime) / float64(o.cpuRows)
+	// The coster charges lookups for the random read of each lookup plus the
+	// retrieval of each row, so the latter is subtracted from the lookup time.
+	// A random read is assumed to never be cheaper than a sequential one.
+	randIOTime := (float64(o.lookupTime) -
+		xform.LookupJoinRetrieveRowCost*seqIOTime*float64(o.lookupRows)) / float64(o.lookups)
+	if randIOTime < seqIOTime {
+		randIOTime = seqIOTime
+	}
+	return memo.CostFactors{
+		CPU:    seqIOCostFactor * memo.Cost(cpuTime/seqIOTime),
+		SeqIO:  seqIOCostFactor,
+		RandIO: seqIOCostFactor * memo.Cost(randIOTime/seqIOTime),
+	}, true
+}
@@ -0,0 +1,214 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package costcalibration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/optional"
+	"github.com/stretchr/testify/require"
+)
+
+func tableReader(rows uint64, kvTime time.Duration) execstats.ProcessorStats {
+	return execstats.ProcessorStats{
+		Core: &execinfrapb.ProcessorCoreUnion{TableReader: &execinfrapb.TableReaderSpec{}},
+		Stats: &execinfrapb.ComponentStats{
+			KV: execinfrapb.KVStats{
+				TuplesRead: optional.MakeUint(rows),
+				BytesRead:  optional.MakeUint(rows * 100),
+				KVTime:     optional.MakeTimeValue(kvTime),
+			},
+		},
+	}
+}
+
+func joinReader(inputRows, rows uint64, kvTime time.Duration) execstats.ProcessorStats {
+	s := execstats.ProcessorStats{
+		Core: &execinfrapb.ProcessorCoreUnion{JoinReader: &execinfrapb.JoinReaderSpec{}},
+		Stats: &execinfrapb.ComponentStats{
+			KV: execinfrapb.KVStats{
+				TuplesRead: optional.MakeUint(rows),
+				BytesRead:  optional.MakeUint(rows * 100),
+				KVTime:     optional.MakeTimeValue(kvTime),
+			},
+		},
+	}
+	if inputRows != 0 {
+		s.Stats.Inputs = []execinfrapb.InputStats{{NumTuples: optional.MakeUint(inputRows)}}
+	}
+	return s
+}
+
+func sorter(rows uint64, execTime time.Duration) execstats.ProcessorStats {
+	return execstats.ProcessorStats{
+		Core: &execinfrapb.ProcessorCoreUnion{Sorter: &execinfrapb.SorterSpec{}},
+		Stats: &execinfrapb.ComponentStats{
+			Exec:   execinfrapb.ExecStats{ExecTime: optional.MakeTimeValue(execTime)},
+			Output: execinfrapb.OutputStats{NumTuples: optional.MakeUint(rows)},
+		},
+	}
+}
+
+func TestObservations(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	t.Run("fit", func(t *testing.T) {
+		var obs observations
+		for _, s := range []execstats.ProcessorStats{
+			// Reading a row sequentially takes 1µs.
+			tableReader(1000, time.Millisecond),
+			tableReader(3000, 3*time.Millisecond),
+			// A random read takes 10µs, plus 2µs to retrieve each row.
+			joinReader(100, 100, 1200*time.Microsecond),
+			joinReader(0 /* inputRows */, 200, 2400*time.Microsecond),
+			// Processing a row takes 50ns.
+			sorter(2000, 100*time.Microsecond),
+		} {
+			obs.add(&s)
+		}
+		require.Equal(t, int64(4000), obs.scanRows)
+		require.Equal(t, int64(400000), obs.scanBytes)
+		require.Equal(t, int64(300), obs.lookups)
+		require.Equal(t, int64(300), obs.lookupRows)
+		require.Equal(t, int64(2000), obs.cpuRows)
+
+		factors, ok := obs.fit(1 /* seqIOCostFactor */)
+		require.True(t, ok)
+		require.InDelta(t, 0.05, float64(factors.CPU), 1e-9)
+		require.Equal(t, memo.Cost(1), factors.SeqIO)
+		require.InDelta(t, 10, float64(factors.RandIO), 1e-9)
+
+		// The factors are relative to the sequential I/O cost factor.
+		factors, ok = obs.fit(2 /* seqIOCostFactor */)
+		require.True(t, ok)
+		require.InDelta(t, 0.1, float64(factors.CPU), 1e-9)
+		require.InDelta(t, 20, float64(factors.RandIO), 1e-9)
+
+		// Subtracting the observations that were fit leaves nothing.
+		fitted := obs
+		obs.merge(&fitted)
+		obs.sub(&fitted)
+		obs.sub(&fitted)
+		require.Equal(t, observations{}, obs)
+	})
+
+	t.Run("not enough observations", func(t *testing.T) {
+		var obs observations
+		for _, s := range []execstats.ProcessorStats{
+			tableReader(1000, time.Millisecond),
+			joinReader(10, 10, time.Millisecond),
+			sorter(2000, 100*time.Microsecond),
+		} {
+			obs.add(&s)
+		}
+		_, ok := obs.fit(1 /* seqIOCostFactor */)
+		require.False(t, ok)
+	})
+
+	t.Run("cheap lookups", func(t *testing.T) {
+		var obs observations
+		for _, s := range []execstats.ProcessorStats{
+			tableReader(1000, time.Millisecond),
+			// Retrieving the rows takes less time than the sequential reads would,
+			// so random reads are assumed to be as cheap as sequential ones.
+			joinReader(100, 1000, time.Millisecond),
+			sorter(2000, 100*time.Microsecond),
+		} {
+			obs.add(&s)
+		}
+		factors, ok := obs.fit(1 /* seqIOCostFactor */)
+		require.True(t, ok)
+		require.InDelta(t, 1, float64(factors.RandIO), 1e-9)
+	})
+}
+
+// recordingExecutor records the statements it is asked to execute.
+type recordingExecutor struct {
+	sqlutil.InternalExecutor
+	stmts []string
+}
+
+func (ie *recordingExecutor) ExecEx(
+	_ context.Context,
+	_ string,
+	_ *kv.Txn,
+	_ sessiondata.InternalExecutorOverride,
+	stmt string,
+	_ ...interface{},
+) (int, error) {
+	ie.stmts = append(ie.stmts, stmt)
+	return 0, nil
+}
+
+// TestFitOnMeta1Leaseholder verifies that only the node that holds the meta1
+// lease writes the cost model factors.
+func TestFitOnMeta1Leaseholder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	calibrationEnabled.Override(&st.SV, true)
+	ie := &recordingExecutor{}
+	isLeaseholder := false
+	c := NewCalibrator(ie, st, hlc.NewClock(hlc.UnixNano, time.Nanosecond),
+		func(context.Context, hlc.ClockTimestamp) (bool, error) {
+			return isLeaseholder, nil
+		},
+	)
+	record := func() {
+		c.Record([]execstats.ProcessorStats{
+			tableReader(1000, time.Millisecond),
+			joinReader(100, 100, 1200*time.Microsecond),
+			sorter(2000, 100*time.Microsecond),
+		})
+	}
+
+	// The observations of a node that doesn't hold the lease are discarded.
+	record()
+	require.NoError(t, c.fitCostFactors(ctx))
+	require.Empty(t, ie.stmts)
+	isLeaseholder = true
+	require.NoError(t, c.fitCostFactors(ctx))
+	require.Empty(t, ie.stmts)
+
+	record()
+	require.NoError(t, c.fitCostFactors(ctx))
+	require.Equal(t, []string{
+		"SET CLUSTER SETTING sql.optimizer.cost_model.cpu_cost_factor = 0.05",
+		"SET CLUSTER SETTING sql.optimizer.cost_model.rand_io_cost_factor = 10",
+	}, ie.stmts)
+}
+
+// TestDisabledOnSecondaryTenants verifies that the calibration is disabled on
+// secondary tenants, which have no meta1 leaseholder.
+func TestDisabledOnSecondaryTenants(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	calibrationEnabled.Override(&st.SV, true)
+	ie := &recordingExecutor{}
+	c := NewCalibrator(ie, st, hlc.NewClock(hlc.UnixNano, time.Nanosecond), nil /* isMeta1Leaseholder */)
+	require.False(t, c.Enabled())
+	require.NoError(t, c.fitCostFactors(ctx))
+	require.Empty(t, ie.stmts)
+}
@@ -46,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -1978,6 +1979,78 @@ CREATE TABLE crdb_internal.create_type_statements (
 				); err != nil {
 					return err
 				}
+			case descpb.TypeDescriptor_COMPOSITE:
+				name, err := tree.NewUnresolvedObjectName(2, [3]string{typeDesc.GetName(), sc}, 0)
+				if err != nil {
+					return err
+				}
+				elements := typeDesc.TypeDesc().Composite.Elements
+				node := &tree.CreateType{
+					Variety:           tree.Composite,
+					TypeName:          name,
+					CompositeTypeList: make([]tree.CompositeTypeElem, len(elements)),
+				}
+				for i := range elements {
+					node.CompositeTypeList[i] = tree.CompositeTypeElem{
+						Label: tree.Name(elements[i].ElementLabel),
+						Type:  elements[i].ElementType,
+					}
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(db.GetID())),       // database_id
+					tree.NewDString(db.GetName()),             // database_name
+					tree.NewDString(sc),                       // schema_name
+					tree.NewDInt(tree.DInt(typeDesc.GetID())), // descriptor_id
+					tree.NewDString(typeDesc.GetName()),       // descriptor_name
+					tree.NewDString(tree.AsString(node)),      // create_statement
+					tree.DNull,                                // enum_members
+				); err != nil {
+					return err
+				}
+			case descpb.TypeDescriptor_DOMAIN:
+				name, err := tree.NewUnresolvedObjectName(2, [3]string{typeDesc.GetName(), sc}, 0)
+				if err != nil {
+					return err
+				}
+				domain := typeDesc.TypeDesc().Domain
+				node := &tree.CreateType{
+					Variety:    tree.Domain,
+					TypeName:   name,
+					DomainType: domain.BaseType,
+				}
+				if domain.NotNull {
+					node.DomainConstraints = append(node.DomainConstraints,
+						tree.NamedColumnQualification{Qualification: tree.NotNullConstraint{}})
+				}
+				if domain.DefaultExpr != nil {
+					expr, err := parser.ParseExpr(*domain.DefaultExpr)
+					if err != nil {
+						return err
+					}
+					node.DomainConstraints = append(node.DomainConstraints,
+						tree.NamedColumnQualification{Qualification: &tree.ColumnDefault{Expr: expr}})
+				}
+				for _, check := range domain.Checks {
+					expr, err := parser.ParseExpr(check.Expr)
+					if err != nil {
+						return err
+					}
+					node.DomainConstraints = append(node.DomainConstraints, tree.NamedColumnQualification{
+						Name:          tree.Name(check.Name),
+						Qualification: &tree.ColumnCheckConstraint{Expr: expr},
+					})
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(db.GetID())),       // database_id
+					tree.NewDString(db.GetName()),             // database_name
+					tree.NewDString(sc),                       // schema_name
+					tree.NewDInt(tree.DInt(typeDesc.GetID())), // descriptor_id
+					tree.NewDString(typeDesc.GetName()),       // descriptor_name
+					tree.NewDString(tree.AsString(node)),      // create_statement
+					tree.DNull,                                // enum_members
+				); err != nil {
+					return err
+				}
 			case descpb.TypeDescriptor_MULTIREGION_ENUM:
 				// Multi-region enums are created implicitly, so we don't have create
 				// statements for them.
@@ -12,6 +12,7 @@ package sql
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
@@ -147,6 +148,23 @@ func (p *planner) setupFamilyAndConstraintForShard(
 func MakeIndexDescriptor(
 	params runParams, n tree.CreateIndex, tableDesc *tabledesc.Mutable,
 ) (*descpb.IndexDescriptor, error) {
+	// Replace expression index elements with references to new inaccessible
+	// virtual computed columns.
+	var err error
+	n.Columns, err = replaceExpressionElemsWithVirtualCols(
+		params.ctx,
+		tableDesc,
+		&n.Table,
+		n.Columns,
+		n.Inverted,
+		false, /* isNewTable */
+		params.ExecCfg().Settings.Version,
+		params.p.SemaCtx(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// Ensure that the columns we want to index exist before trying to create the
 	// index.
 	if err := validateIndexColumnsExist(tableDesc, n.Columns); err != nil {
@@ -284,7 +302,7 @@ func MakeIndexDescriptor(
 func validateIndexColumnsExist(desc *tabledesc.Mutable, columns tree.IndexElemList) error {
 	for _, column := range columns {
 		if column.Expr != nil {
-			return unimplemented.NewWithIssuef(9682, "only simple columns are supported as index elements")
+			return errors.AssertionFailedf("index element %s was not replaced with a column", column.Expr)
 		}
 		foundColumn, err := desc.FindColumnWithName(column.Column)
 		if err != nil {
@@ -297,6 +315,108 @@ func validateIndexColumnsExist(desc *tabledesc.Mutable, columns tree.IndexElemLi
 	return nil
 }
 
+// replaceExpressionElemsWithVirtualCols returns a copy of elems in which each
+// expression element is replaced with a reference to a new inaccessible virtual
+// computed column that computes the expression. The new columns are added to
+// desc directly if isNewTable is true, and as column mutations otherwise.
+//
+// A parenthesized reference to an existing column, e.g. ((a)), is treated as
+// a simple column reference.
+func replaceExpressionElemsWithVirtualCols(
+	ctx context.Context,
+	desc *tabledesc.Mutable,
+	tn *tree.TableName,
+	elems tree.IndexElemList,
+	isInverted bool,
+	isNewTable bool,
+	version clusterversion.Handle,
+	semaCtx *tree.SemaContext,
+) (tree.IndexElemList, error) {
+	newElems := make(tree.IndexElemList, len(elems))
+	for i := range elems {
+		elem := elems[i]
+		if elem.OpClass != "" && !version.IsActive(ctx, clusterversion.TrigramInvertedIndexes) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use trigram inverted indexes",
+				clusterversion.ByKey(clusterversion.TrigramInvertedIndexes))
+		}
+		if elem.Expr == nil {
+			newElems[i] = elem
+			continue
+		}
+		if name, ok := elem.Expr.(*tree.UnresolvedName); ok && name.NumParts == 1 && !name.Star {
+			elem.Column = tree.Name(name.Parts[0])
+			elem.Expr = nil
+			newElems[i] = elem
+			continue
+		}
+
+		if !version.IsActive(ctx, clusterversion.ExpressionIndexes) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use expression indexes",
+				clusterversion.ByKey(clusterversion.ExpressionIndexes))
+		}
+
+		expr, typ, err := schemaexpr.ValidateExpressionIndex(ctx, desc, elem.Expr, tn, semaCtx)
+		if err != nil {
+			return nil, err
+		}
+		if isInverted && i == len(elems)-1 {
+			if elem.OpClass != "" {
+				// The operator class is validated along with the type of the new
+				// column when the index descriptor is built.
+			} else if !colinfo.ColumnTypeIsInvertedIndexable(typ) {
+				return nil, errors.WithHint(
+					pgerror.Newf(
+						pgcode.FeatureNotSupported,
+						"index element %s of type %s is not allowed as the last element in an inverted index",
+						tree.AsString(elem.Expr),
+						typ.Name(),
+					),
+					"see the documentation for more information about inverted indexes",
+				)
+			}
+		} else if !colinfo.ColumnTypeIsIndexable(typ) {
+			return nil, unimplemented.NewWithIssueDetailf(35730, typ.DebugString(),
+				"index element %s is of type %s and thus is not indexable",
+				tree.AsString(elem.Expr), typ.Name())
+		}
+
+		col := &descpb.ColumnDescriptor{
+			Name:         makeExpressionIndexColumnName(desc),
+			Type:         typ,
+			Nullable:     true,
+			ComputeExpr:  &expr,
+			Virtual:      true,
+			Inaccessible: true,
+		}
+		if isNewTable {
+			desc.AddColumn(col)
+		} else {
+			desc.AddColumnMutation(col, descpb.DescriptorMutation_ADD)
+		}
+		elem.Column = tree.Name(col.Name)
+		elem.Expr = nil
+		newElems[i] = elem
+	}
+	return newElems, nil
+}
+
+// makeExpressionIndexColumnName returns a name for an inaccessible virtual
+// column backing an expression index element that does not conflict with any
+// existing column in desc. The names are of the form crdb_internal_idx_expr,
+// crdb_internal_idx_expr_1, etc.
+func makeExpressionIndexColumnName(desc *tabledesc.Mutable) string {
+	const baseName = "crdb_internal_idx_expr"
+	name := baseName
+	for i := 1; ; i++ {
+		if _, err := desc.FindColumnWithName(tree.Name(name)); err != nil {
+			return name
+		}
+		name = fmt.Sprintf("%s_%d", baseName, i)
+	}
+}
+
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
 // This is because CREATE INDEX performs multiple KV operations on descriptors
 // and expects to see its own writes.
@@ -326,6 +446,11 @@ func setupShardedIndex(
 
 	colNames := make([]string, 0, len(columns))
 	for _, c := range columns {
+		col, err := tableDesc.FindColumnWithName(c.Column)
+		if err == nil && col.IsInaccessible() {
+			return nil, nil, false, pgerror.New(pgcode.FeatureNotSupported,
+				"hash sharded indexes don't support expressions")
+		}
 		colNames = append(colNames, string(c.Column))
 	}
 	buckets, err := tabledesc.EvalShardBucketCount(ctx, semaCtx, evalCtx, bucketsExpr)
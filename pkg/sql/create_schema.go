@@ -126,6 +126,7 @@ func CreateUserDefinedSchemaDescriptor(
 	} else {
 		privs.SetOwner(user)
 	}
+	db.GetDefaultPrivileges().ApplyTo(privs, privs.Owner(), privilege.Schemas)
 
 	// Create the SchemaDescriptor.
 	desc := schemadesc.NewBuilder(&descpb.SchemaDescriptor{
@@ -100,6 +100,11 @@ func doCreateSequence(
 	}
 
 	privs := CreateInheritedPrivilegesFromDBDesc(dbDesc, params.SessionData().User())
+	if err := params.p.applyDefaultPrivileges(
+		params.ctx, privs, dbDesc, schemaID, privilege.Sequences,
+	); err != nil {
+		return err
+	}
 
 	if persistence.IsTemporary() {
 		telemetry.Inc(sqltelemetry.CreateTempSequenceCounter)
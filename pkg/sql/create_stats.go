@@ -214,9 +214,16 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 
 	// Identify which columns we should create statistics for.
 	var colStats []jobspb.CreateStatsDetails_ColStat
-	if len(n.ColumnNames) == 0 {
+	if n.Options.UsingExtremes {
+		if colStats, err = n.createStatsExtremesColumns(ctx, tableDesc); err != nil {
+			return nil, err
+		}
+	} else if len(n.ColumnNames) == 0 {
 		multiColEnabled := stats.MultiColumnStatisticsClusterMode.Get(&n.p.ExecCfg().Settings.SV)
-		if colStats, err = createStatsDefaultColumns(tableDesc, multiColEnabled); err != nil {
+		multiColHistEnabled := stats.MultiColumnHistogramClusterMode.Get(&n.p.ExecCfg().Settings.SV)
+		if colStats, err = createStatsDefaultColumns(
+			tableDesc, multiColEnabled, multiColHistEnabled,
+		); err != nil {
 			return nil, err
 		}
 	} else {
@@ -234,11 +241,22 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 			return nil, err
 		}
 		isInvIndex := colinfo.ColumnTypeIsInvertedIndexable(col.GetType())
+		// By default, create histograms on all explicitly requested column stats
+		// with a single column that doesn't use an inverted index. Multi-column
+		// histograms are created if enabled and none of the columns use an
+		// inverted index.
+		hasHistogram := len(columnIDs) == 1 && !isInvIndex
+		if len(columnIDs) > 1 && stats.MultiColumnHistogramClusterMode.Get(&n.p.ExecCfg().Settings.SV) {
+			hasHistogram = true
+			for i := range columns {
+				if colinfo.ColumnTypeIsInvertedIndexable(columns[i].GetType()) {
+					hasHistogram = false
+				}
+			}
+		}
 		colStats = []jobspb.CreateStatsDetails_ColStat{{
-			ColumnIDs: columnIDs,
-			// By default, create histograms on all explicitly requested column stats
-			// with a single column that doesn't use an inverted index.
-			HasHistogram:        len(columnIDs) == 1 && !isInvIndex,
+			ColumnIDs:           columnIDs,
+			HasHistogram:        hasHistogram,
 			HistogramMaxBuckets: defaultHistogramBuckets,
 		}}
 		// Make histograms for inverted index column types.
@@ -287,11 +305,94 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 			Statement:       eventLogStatement,
 			AsOf:            asOf,
 			MaxFractionIdle: n.Options.Throttling,
+			UsingExtremes:   n.Options.UsingExtremes,
 		},
 		Progress: jobspb.CreateStatsProgress{},
 	}, nil
 }
 
+// createStatsExtremesColumns returns the column statistic to collect for
+// CREATE STATISTICS ... USING EXTREMES. Partial statistics at the extremes are
+// only supported on a single column that is the first key column of a forward,
+// non-partial index, and that already has a statistic with a histogram.
+func (n *createStatsNode) createStatsExtremesColumns(
+	ctx context.Context, tableDesc catalog.TableDescriptor,
+) ([]jobspb.CreateStatsDetails_ColStat, error) {
+	if len(n.ColumnNames) != 1 {
+		return nil, pgerror.New(
+			pgcode.FeatureNotSupported, "USING EXTREMES requires exactly one column",
+		)
+	}
+	columns, err := tabledesc.FindPublicColumnsWithNames(tableDesc, n.ColumnNames)
+	if err != nil {
+		return nil, err
+	}
+	col := columns[0]
+	if extremesIndex(tableDesc, col.GetID()) == nil {
+		return nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"table %s does not contain a non-partial forward index with %s as a prefix column",
+			tableDesc.GetName(), col.GetName(),
+		)
+	}
+	if _, err := latestStatisticForExtremes(
+		ctx, n.p.ExecCfg().TableStatsCache, tableDesc, col,
+	); err != nil {
+		return nil, err
+	}
+	return []jobspb.CreateStatsDetails_ColStat{{
+		ColumnIDs:           []descpb.ColumnID{col.GetID()},
+		HasHistogram:        true,
+		HistogramMaxBuckets: defaultHistogramBuckets,
+	}}, nil
+}
+
+// extremesIndex returns the first public, forward, non-partial index with the
+// given column as its first key column, or nil if there is no such index. The
+// primary index is preferred.
+func extremesIndex(desc catalog.TableDescriptor, colID descpb.ColumnID) catalog.Index {
+	for _, idx := range desc.ActiveIndexes() {
+		if idx.GetType() == descpb.IndexDescriptor_FORWARD && !idx.IsPartial() &&
+			idx.NumColumns() > 0 && idx.GetColumnID(0) == colID {
+			return idx
+		}
+	}
+	return nil
+}
+
+// latestStatisticForExtremes returns the most recent statistic on the given
+// column, which is the statistic that CREATE STATISTICS ... USING EXTREMES
+// extends. It returns an error if the statistic doesn't have a histogram with
+// at least one non-NULL value, since the bounds of the histogram determine
+// which values are scanned.
+func latestStatisticForExtremes(
+	ctx context.Context,
+	statsCache *stats.TableStatisticsCache,
+	desc catalog.TableDescriptor,
+	col catalog.Column,
+) (*stats.TableStatistic, error) {
+	tableStats, err := statsCache.GetTableStats(ctx, desc.GetID())
+	if err != nil {
+		return nil, err
+	}
+	for _, stat := range tableStats {
+		if len(stat.ColumnIDs) != 1 || stat.ColumnIDs[0] != col.GetID() {
+			continue
+		}
+		// The statistics are ordered by creation time, so this is the latest
+		// statistic on the column.
+		if stat.HistogramData == nil || len(stat.HistogramData.Buckets) == 0 {
+			return nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"the latest statistic on column %s does not have a histogram with non-NULL values",
+				col.GetName(),
+			)
+		}
+		return stat, nil
+	}
+	return nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+		"column %s does not have a prior statistic", col.GetName(),
+	)
+}
+
 // maxNonIndexCols is the maximum number of non-index columns that we will use
 // when choosing a default set of column statistics.
 const maxNonIndexCols = 100
@@ -310,11 +411,16 @@ const maxNonIndexCols = 100
 // predicate expressions are also likely to appear in query filters, so stats
 // are collected for those columns as well.
 //
+// If multiColHistEnabled is true, histograms are also collected for the
+// multi-column stats on index prefixes that don't include an inverted column.
+// These are used to estimate the selectivity of filters on correlated
+// columns, such as country = 'FR' AND city = 'Paris'.
+//
 // In addition to the index columns, we collect stats on up to maxNonIndexCols
 // other columns from the table. We only collect histograms for index columns,
 // plus any other boolean or enum columns (where the "histogram" is tiny).
 func createStatsDefaultColumns(
-	desc catalog.TableDescriptor, multiColEnabled bool,
+	desc catalog.TableDescriptor, multiColEnabled, multiColHistEnabled bool,
 ) ([]jobspb.CreateStatsDetails_ColStat, error) {
 	colStats := make([]jobspb.CreateStatsDetails_ColStat, 0, len(desc.ActiveIndexes()))
 
@@ -380,18 +486,27 @@ func createStatsDefaultColumns(
 		// Remember the requested stats so we don't request duplicates.
 		trackStatsIfNotExists(colIDs)
 
-		// Only generate non-histogram multi-column stats.
 		colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
-			ColumnIDs:    colIDs,
-			HasHistogram: false,
+			ColumnIDs:           colIDs,
+			HasHistogram:        multiColHistEnabled,
+			HistogramMaxBuckets: defaultHistogramBuckets,
 		})
 	}
 
 	// Add column stats for each secondary index.
 	for _, idx := range desc.PublicNonPrimaryIndexes() {
+		hasInvertedCol := false
 		for j, n := 0, idx.NumColumns(); j < n; j++ {
 			colID := idx.GetColumnID(j)
 			isInverted := idx.GetType() == descpb.IndexDescriptor_INVERTED && colID == idx.InvertedColumnID()
+			// Trigram indexes are not used to estimate selectivity, so string
+			// columns keep their regular histograms.
+			if isInverted && idx.IndexDesc().InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM {
+				isInverted = false
+			}
+			if idx.GetType() == descpb.IndexDescriptor_INVERTED && colID == idx.InvertedColumnID() {
+				hasInvertedCol = true
+			}
 
 			// Generate stats for each indexed column.
 			addIndexColumnStatsIfNotExists(colID, isInverted)
@@ -411,10 +526,11 @@ func createStatsDefaultColumns(
 				continue
 			}
 
-			// Only generate non-histogram multi-column stats.
+			// Multi-column histograms are not supported on inverted columns.
 			colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
-				ColumnIDs:    colIDs,
-				HasHistogram: false,
+				ColumnIDs:           colIDs,
+				HasHistogram:        multiColHistEnabled && !hasInvertedCol,
+				HistogramMaxBuckets: defaultHistogramBuckets,
 			})
 		}
 
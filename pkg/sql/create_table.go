@@ -68,6 +68,8 @@ var minimumTypeUsageVersions = map[types.Family]clusterversion.Key{
 	types.GeographyFamily: clusterversion.GeospatialType,
 	types.GeometryFamily:  clusterversion.GeospatialType,
 	types.Box2DFamily:     clusterversion.Box2DType,
+	types.TSQueryFamily:   clusterversion.TextSearchTypes,
+	types.TSVectorFamily:  clusterversion.TextSearchTypes,
 }
 
 // isTypeSupportedInVersion returns whether a given type is supported in the given version.
@@ -301,6 +303,11 @@ func (n *createTableNode) startExec(params runParams) error {
 	}
 
 	privs := CreateInheritedPrivilegesFromDBDesc(n.dbDesc, params.SessionData().User())
+	if err := params.p.applyDefaultPrivileges(
+		params.ctx, privs, n.dbDesc, schemaID, privilege.Tables,
+	); err != nil {
+		return err
+	}
 
 	var desc *tabledesc.Mutable
 	var affected map[descpb.ID]*tabledesc.Mutable
@@ -358,6 +365,12 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 	}
 
+	if desc.RowLevelTTL != nil {
+		if err := createRowLevelTTLSchedule(params, desc); err != nil {
+			return err
+		}
+	}
+
 	// Descriptor written to store here.
 	if err := params.p.createDescriptorWithID(
 		params.ctx, tKey.Key(params.ExecCfg().Codec), id, desc, params.EvalContext().Settings,
@@ -644,6 +657,7 @@ func addUniqueWithoutIndexColumnTableDef(
 		string(d.Unique.ConstraintName),
 		[]string{string(d.Name)},
 		"", /* predicate */
+		tree.NotDeferrableConstraint,
 		ts,
 		validationBehavior,
 	); err != nil {
@@ -691,6 +705,12 @@ func addUniqueWithoutIndexTableDef(
 			"partitioned unique constraints without an index are not supported",
 		)
 	}
+	if d.Deferrability != tree.NotDeferrableConstraint &&
+		!evalCtx.Settings.Version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use DEFERRABLE constraints",
+			clusterversion.DeferrableConstraints)
+	}
 
 	// If there is a predicate, validate it.
 	var predicate string
@@ -710,7 +730,7 @@ func addUniqueWithoutIndexTableDef(
 		colNames[i] = string(d.Columns[i].Column)
 	}
 	if err := ResolveUniqueWithoutIndexConstraint(
-		ctx, desc, string(d.Name), colNames, predicate, ts, validationBehavior,
+		ctx, desc, string(d.Name), colNames, predicate, d.Deferrability, ts, validationBehavior,
 	); err != nil {
 		return err
 	}
@@ -730,6 +750,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	constraintName string,
 	colNames []string,
 	predicate string,
+	deferrability tree.ConstraintDeferrability,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
@@ -783,11 +804,12 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:      constraintName,
-		TableID:   tbl.ID,
-		ColumnIDs: columnIDs,
-		Predicate: predicate,
-		Validity:  validity,
+		Name:          constraintName,
+		TableID:       tbl.ID,
+		ColumnIDs:     columnIDs,
+		Predicate:     predicate,
+		Validity:      validity,
+		Deferrability: descpb.ConstraintDeferrabilityValue[deferrability],
 	}
 
 	if ts == NewTable {
@@ -835,6 +857,13 @@ func ResolveFK(
 	validationBehavior tree.ValidationBehavior,
 	evalCtx *tree.EvalContext,
 ) error {
+	if d.Deferrability != tree.NotDeferrableConstraint &&
+		!evalCtx.Settings.Version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use DEFERRABLE constraints",
+			clusterversion.DeferrableConstraints)
+	}
+
 	var originColSet catalog.TableColSet
 	originCols := make([]catalog.Column, len(d.FromCols))
 	for i, fromCol := range d.FromCols {
@@ -1066,6 +1095,7 @@ func ResolveFK(
 		OnDelete:            descpb.ForeignKeyReferenceActionValue[d.Actions.Delete],
 		OnUpdate:            descpb.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               descpb.CompositeKeyMatchMethodValue[d.Match],
+		Deferrability:       descpb.ConstraintDeferrabilityValue[d.Deferrability],
 	}
 
 	if ts == NewTable {
@@ -1503,16 +1533,45 @@ func NewTableDesc(
 		id, parentID, parentSchemaID, n.Table.Table(), creationTime, privileges, persistence,
 	)
 
+	paramObserver := paramparse.NewTableStorageParamObserver(&desc)
 	if err := paramparse.ApplyStorageParameters(
 		ctx,
 		semaCtx,
 		evalCtx,
 		n.StorageParams,
-		&paramparse.TableStorageParamObserver{},
+		paramObserver,
 	); err != nil {
 		return nil, err
 	}
 
+	if desc.RowLevelTTL != nil {
+		if !st.Version.IsActive(ctx, clusterversion.RowLevelTTL) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use row-level TTL",
+				clusterversion.RowLevelTTL)
+		}
+		// With ttl_expire_after, the expiration time of each row is stored in a
+		// hidden column which defaults to the insertion time of the row plus
+		// the given interval, unless the column is already defined (e.g. by the
+		// output of SHOW CREATE TABLE).
+		expirationColExists := false
+		for _, def := range n.Defs {
+			if d, ok := def.(*tree.ColumnTableDef); ok &&
+				d.Name == tabledesc.RowLevelTTLExpirationColumnName {
+				expirationColExists = true
+				break
+			}
+		}
+		if desc.RowLevelTTL.ExpireAfter != "" && !expirationColExists {
+			colDef, err := rowLevelTTLDefaultColDef(desc.RowLevelTTL.ExpireAfter)
+			if err != nil {
+				return nil, err
+			}
+			n.Defs = append(n.Defs, colDef)
+			columnDefaultExprs = append(columnDefaultExprs, nil)
+		}
+	}
+
 	indexEncodingVersion := descpb.SecondaryIndexFamilyFormatVersion
 	// We can't use st.Version.IsActive because this method is used during
 	// server setup before the cluster version has been initialized.
@@ -1845,8 +1904,8 @@ func NewTableDesc(
 	}
 
 	setupShardedIndexForNewTable := func(
-		d tree.IndexTableDef, idx *descpb.IndexDescriptor,
-	) (columns tree.IndexElemList, _ error) {
+		d tree.IndexTableDef, columns tree.IndexElemList, idx *descpb.IndexDescriptor,
+	) (tree.IndexElemList, error) {
 		if n.PartitionByTable.ContainsPartitions() {
 			return nil, pgerror.New(pgcode.FeatureNotSupported, "sharded indexes don't support partitioning")
 		}
@@ -1855,7 +1914,7 @@ func NewTableDesc(
 			evalCtx,
 			semaCtx,
 			sessionData.HashShardedIndexesEnabled,
-			d.Columns,
+			columns,
 			d.Sharded.ShardBuckets,
 			&desc,
 			idx,
@@ -1898,7 +1957,19 @@ func NewTableDesc(
 			if d.Inverted {
 				idx.Type = descpb.IndexDescriptor_INVERTED
 			}
-			columns := d.Columns
+			columns, err := replaceExpressionElemsWithVirtualCols(
+				ctx,
+				&desc,
+				&n.Table,
+				d.Columns,
+				d.Inverted,
+				true, /* isNewTable */
+				st.Version,
+				semaCtx,
+			)
+			if err != nil {
+				return nil, err
+			}
 			if d.Sharded != nil {
 				if d.Interleave != nil {
 					return nil, pgerror.New(pgcode.FeatureNotSupported, "interleaved indexes cannot also be hash sharded")
@@ -1907,7 +1978,7 @@ func NewTableDesc(
 					return nil, hashShardedIndexesOnRegionalByRowError()
 				}
 				var err error
-				columns, err = setupShardedIndexForNewTable(*d, &idx)
+				columns, err = setupShardedIndexForNewTable(*d, columns, &idx)
 				if err != nil {
 					return nil, err
 				}
@@ -1996,6 +2067,22 @@ func NewTableDesc(
 				Version:          indexEncodingVersion,
 			}
 			columns := d.Columns
+			if !d.PrimaryKey {
+				var err error
+				columns, err = replaceExpressionElemsWithVirtualCols(
+					ctx,
+					&desc,
+					&n.Table,
+					d.Columns,
+					false, /* isInverted */
+					true,  /* isNewTable */
+					st.Version,
+					semaCtx,
+				)
+				if err != nil {
+					return nil, err
+				}
+			}
 			if d.Sharded != nil {
 				if n.Interleave != nil && d.PrimaryKey {
 					return nil, pgerror.New(pgcode.FeatureNotSupported, "interleaved indexes cannot also be hash sharded")
@@ -2004,7 +2091,7 @@ func NewTableDesc(
 					return nil, hashShardedIndexesOnRegionalByRowError()
 				}
 				var err error
-				columns, err = setupShardedIndexForNewTable(d.IndexTableDef, &idx)
+				columns, err = setupShardedIndexForNewTable(d.IndexTableDef, columns, &idx)
 				if err != nil {
 					return nil, err
 				}
@@ -2130,10 +2217,24 @@ func NewTableDesc(
 		}
 	}
 
+	// The expiration column of the row-level TTL configuration can only be
+	// resolved once column IDs are allocated, so the configuration is detached
+	// from the descriptor while AllocateIDs validates it.
+	rowLevelTTL := desc.RowLevelTTL
+	desc.RowLevelTTL = nil
 	if err := desc.AllocateIDs(ctx); err != nil {
 		return nil, err
 	}
 
+	if rowLevelTTL != nil {
+		desc.RowLevelTTL = rowLevelTTL
+		if err := resolveRowLevelTTLExpirationColumn(
+			&desc, paramObserver.TTLExpirationColumn(),
+		); err != nil {
+			return nil, err
+		}
+	}
+
 	for _, idx := range desc.PublicNonPrimaryIndexes() {
 		// Increment the counter if this index could be storing data across multiple column families.
 		if idx.NumStoredColumns() > 1 && len(desc.Families) > 1 {
@@ -2606,6 +2707,9 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 					if idx.GetColumnDirection(j) == descpb.IndexDescriptor_DESC {
 						elem.Direction = tree.Descending
 					}
+					if j == numColumns-1 {
+						elem.OpClass = idx.IndexDesc().InvertedColumnOpClass()
+					}
 					indexDef.Columns = append(indexDef.Columns, elem)
 				}
 				for j := 0; j < idx.NumStoredColumns(); j++ {
@@ -21,7 +21,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/enum"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -104,6 +106,10 @@ func (n *createTypeNode) startExec(params runParams) error {
 	switch n.n.Variety {
 	case tree.Enum:
 		return params.p.createUserDefinedEnum(params, n)
+	case tree.Composite:
+		return params.p.createUserDefinedComposite(params, n)
+	case tree.Domain:
+		return params.p.createUserDefinedDomain(params, n)
 	default:
 		return unimplemented.NewWithIssue(25123, "CREATE TYPE")
 	}
@@ -248,6 +254,20 @@ func (p *planner) createArrayType(
 	switch t := typDesc.Kind; t {
 	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM:
 		elemTyp = types.MakeEnum(typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id))
+	case descpb.TypeDescriptor_COMPOSITE:
+		contents := make([]*types.T, len(typDesc.Composite.Elements))
+		labels := make([]string, len(typDesc.Composite.Elements))
+		for i, e := range typDesc.Composite.Elements {
+			contents[i] = e.ElementType
+			labels[i] = e.ElementLabel
+		}
+		elemTyp = types.MakeComposite(
+			typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id), contents, labels,
+		)
+	case descpb.TypeDescriptor_DOMAIN:
+		elemTyp = types.MakeDomain(
+			typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id), typDesc.Domain.BaseType,
+		)
 	default:
 		return 0, errors.AssertionFailedf("cannot make array type for kind %s", t.String())
 	}
@@ -349,6 +369,11 @@ func (p *planner) createEnumWithID(
 
 	inheritUsagePrivilegeFromSchema(resolvedSchema, privs)
 	privs.Grant(params.p.User(), privilege.List{privilege.ALL})
+	if err := p.applyDefaultPrivileges(
+		params.ctx, privs, dbDesc, schemaID, privilege.Types,
+	); err != nil {
+		return err
+	}
 
 	enumKind := descpb.TypeDescriptor_ENUM
 	var regionConfig *descpb.TypeDescriptor_RegionConfig
@@ -409,6 +434,289 @@ func (p *planner) createEnumWithID(
 		})
 }
 
+func (p *planner) createUserDefinedComposite(params runParams, n *createTypeNode) error {
+	// Make sure that all nodes in the cluster are able to recognize composite
+	// types.
+	if !p.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.CompositeTypes) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"not all nodes are the correct version for composite type creation")
+	}
+
+	// Resolve the element types and ensure that there are no duplicate labels.
+	elements := make([]descpb.TypeDescriptor_Composite_CompositeElement, len(n.n.CompositeTypeList))
+	seenLabels := make(map[tree.Name]struct{})
+	for i := range n.n.CompositeTypeList {
+		elem := &n.n.CompositeTypeList[i]
+		if _, ok := seenLabels[elem.Label]; ok {
+			return pgerror.Newf(pgcode.DuplicateColumn,
+				"composite type definition contains duplicate element %q", elem.Label)
+		}
+		seenLabels[elem.Label] = struct{}{}
+		typ, err := tree.ResolveType(params.ctx, elem.Type, p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return err
+		}
+		if typ.UserDefined() {
+			// The elements of a composite type are stored in the types.T of the
+			// columns that use it, so the type descriptors they reference would
+			// not be tracked.
+			return unimplemented.NewWithIssueDetailf(27792, "user-defined element",
+				"composite type elements cannot be of user-defined type %s", typ.SQLString())
+		}
+		if err := colinfo.ValidateColumnDefType(typ); err != nil {
+			return err
+		}
+		elements[i] = descpb.TypeDescriptor_Composite_CompositeElement{
+			ElementType:  typ,
+			ElementLabel: string(elem.Label),
+		}
+	}
+
+	// Generate a key in the namespace table and a new id for this type.
+	typeKey, schemaID, err := getCreateTypeParams(params, n.typeName, n.dbDesc)
+	if err != nil {
+		return err
+	}
+	id, err := catalogkv.GenerateUniqueDescID(
+		params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Composite types get the same privileges as enums.
+	privs := descpb.NewDefaultPrivilegeDescriptor(params.p.User())
+	resolvedSchema, err := p.Descriptors().GetImmutableSchemaByID(
+		params.ctx, p.Txn(), schemaID, tree.SchemaLookupFlags{})
+	if err != nil {
+		return err
+	}
+	inheritUsagePrivilegeFromSchema(resolvedSchema, privs)
+	privs.Grant(params.p.User(), privilege.List{privilege.ALL})
+	if err := p.applyDefaultPrivileges(
+		params.ctx, privs, n.dbDesc, schemaID, privilege.Types,
+	); err != nil {
+		return err
+	}
+
+	typeDesc := typedesc.NewBuilder(&descpb.TypeDescriptor{
+		Name:           n.typeName.Type(),
+		ID:             id,
+		ParentID:       n.dbDesc.GetID(),
+		ParentSchemaID: schemaID,
+		Kind:           descpb.TypeDescriptor_COMPOSITE,
+		Composite:      &descpb.TypeDescriptor_Composite{Elements: elements},
+		Version:        1,
+		Privileges:     privs,
+	}).BuildCreatedMutableType()
+
+	// Create the implicit array type for this type before finishing the type.
+	arrayTypeID, err := p.createArrayType(params, n.typeName, typeDesc, n.dbDesc, schemaID)
+	if err != nil {
+		return err
+	}
+	typeDesc.ArrayTypeID = arrayTypeID
+
+	if err := p.createDescriptorWithID(
+		params.ctx,
+		typeKey.Key(params.ExecCfg().Codec),
+		id,
+		typeDesc,
+		params.EvalContext().Settings,
+		n.typeName.String(),
+	); err != nil {
+		return err
+	}
+
+	// Log the event.
+	return p.logEvent(params.ctx,
+		typeDesc.GetID(),
+		&eventpb.CreateType{
+			TypeName: n.typeName.FQString(),
+		})
+}
+
+func (p *planner) createUserDefinedDomain(params runParams, n *createTypeNode) error {
+	// Make sure that all nodes in the cluster are able to recognize domains.
+	if !p.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.Domains) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"not all nodes are the correct version for domain creation")
+	}
+
+	baseType, err := tree.ResolveType(params.ctx, n.n.DomainType, p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return err
+	}
+	if baseType.UserDefined() {
+		// The base type is stored in the types.T of the columns that use the
+		// domain, so the type descriptor it references would not be tracked.
+		return unimplemented.NewWithIssueDetailf(27796, "user-defined base type",
+			"domains cannot be defined over user-defined type %s", baseType.SQLString())
+	}
+	if baseType.Family() == types.ArrayFamily {
+		return unimplemented.NewWithIssueDetailf(27796, "array base type",
+			"domains cannot be defined over array type %s", baseType.SQLString())
+	}
+	if err := colinfo.ValidateColumnDefType(baseType); err != nil {
+		return err
+	}
+
+	domain, err := p.makeDomain(params.ctx, n.typeName, baseType, n.n.DomainConstraints)
+	if err != nil {
+		return err
+	}
+
+	// Generate a key in the namespace table and a new id for this type.
+	typeKey, schemaID, err := getCreateTypeParams(params, n.typeName, n.dbDesc)
+	if err != nil {
+		return err
+	}
+	id, err := catalogkv.GenerateUniqueDescID(
+		params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Domains get the same privileges as enums.
+	privs := descpb.NewDefaultPrivilegeDescriptor(params.p.User())
+	resolvedSchema, err := p.Descriptors().GetImmutableSchemaByID(
+		params.ctx, p.Txn(), schemaID, tree.SchemaLookupFlags{})
+	if err != nil {
+		return err
+	}
+	inheritUsagePrivilegeFromSchema(resolvedSchema, privs)
+	privs.Grant(params.p.User(), privilege.List{privilege.ALL})
+	if err := p.applyDefaultPrivileges(
+		params.ctx, privs, n.dbDesc, schemaID, privilege.Types,
+	); err != nil {
+		return err
+	}
+
+	typeDesc := typedesc.NewBuilder(&descpb.TypeDescriptor{
+		Name:           n.typeName.Type(),
+		ID:             id,
+		ParentID:       n.dbDesc.GetID(),
+		ParentSchemaID: schemaID,
+		Kind:           descpb.TypeDescriptor_DOMAIN,
+		Domain:         domain,
+		Version:        1,
+		Privileges:     privs,
+	}).BuildCreatedMutableType()
+
+	// Create the implicit array type for this type before finishing the type.
+	arrayTypeID, err := p.createArrayType(params, n.typeName, typeDesc, n.dbDesc, schemaID)
+	if err != nil {
+		return err
+	}
+	typeDesc.ArrayTypeID = arrayTypeID
+
+	if err := p.createDescriptorWithID(
+		params.ctx,
+		typeKey.Key(params.ExecCfg().Codec),
+		id,
+		typeDesc,
+		params.EvalContext().Settings,
+		n.typeName.String(),
+	); err != nil {
+		return err
+	}
+
+	// Log the event.
+	return p.logEvent(params.ctx,
+		typeDesc.GetID(),
+		&eventpb.CreateType{
+			TypeName: n.typeName.FQString(),
+		})
+}
+
+// makeDomain validates the constraints of a CREATE DOMAIN statement and
+// returns the resulting domain definition.
+func (p *planner) makeDomain(
+	ctx context.Context,
+	typeName *tree.TypeName,
+	baseType *types.T,
+	constraints []tree.NamedColumnQualification,
+) (*descpb.TypeDescriptor_Domain, error) {
+	domain := &descpb.TypeDescriptor_Domain{BaseType: baseType}
+	seenNull := false
+	for _, c := range constraints {
+		switch t := c.Qualification.(type) {
+		case tree.NotNullConstraint, tree.NullConstraint:
+			_, notNull := t.(tree.NotNullConstraint)
+			if seenNull && domain.NotNull != notNull {
+				return nil, pgerror.New(pgcode.Syntax, "conflicting NULL/NOT NULL constraints")
+			}
+			seenNull = true
+			domain.NotNull = notNull
+
+		case *tree.ColumnDefault:
+			if domain.DefaultExpr != nil {
+				return nil, pgerror.New(pgcode.Syntax, "multiple default expressions")
+			}
+			typedExpr, err := schemaexpr.SanitizeVarFreeExpr(
+				ctx, t.Expr, baseType, "DEFAULT", &p.semaCtx, tree.VolatilityVolatile,
+			)
+			if err != nil {
+				return nil, err
+			}
+			s := tree.Serialize(typedExpr)
+			domain.DefaultExpr = &s
+
+		case *tree.ColumnCheckConstraint:
+			check, err := p.makeDomainCheck(ctx, typeName, domain, c.Name, t.Expr)
+			if err != nil {
+				return nil, err
+			}
+			check.Validity = descpb.ConstraintValidity_Validated
+			domain.Checks = append(domain.Checks, check)
+
+		default:
+			return nil, errors.AssertionFailedf("unexpected domain constraint %T", t)
+		}
+	}
+	return domain, nil
+}
+
+// makeDomainCheck validates the expression of a CHECK constraint for the
+// given domain. If name is empty, a name that is not yet used by the domain is
+// generated for the constraint.
+func (p *planner) makeDomainCheck(
+	ctx context.Context,
+	typeName *tree.TypeName,
+	domain *descpb.TypeDescriptor_Domain,
+	name tree.Name,
+	expr tree.Expr,
+) (descpb.TypeDescriptor_Domain_CheckConstraint, error) {
+	nameInUse := func(name string) bool {
+		for i := range domain.Checks {
+			if domain.Checks[i].Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	checkName := string(name)
+	if checkName == "" {
+		checkName = typeName.Type() + "_check"
+		for i := 1; nameInUse(checkName); i++ {
+			checkName = fmt.Sprintf("%s_check%d", typeName.Type(), i)
+		}
+	} else if nameInUse(checkName) {
+		return descpb.TypeDescriptor_Domain_CheckConstraint{}, pgerror.Newf(pgcode.DuplicateObject,
+			"constraint %q for domain %s already exists", checkName, typeName.Type())
+	}
+
+	serialized, err := schemaexpr.ValidateDomainCheckExpr(ctx, expr, domain.BaseType, &p.semaCtx)
+	if err != nil {
+		return descpb.TypeDescriptor_Domain_CheckConstraint{}, err
+	}
+	return descpb.TypeDescriptor_Domain_CheckConstraint{
+		Name: checkName,
+		Expr: serialized,
+	}, nil
+}
+
 func (n *createTypeNode) Next(params runParams) (bool, error) { return false, nil }
 func (n *createTypeNode) Values() tree.Datums                 { return tree.Datums{} }
 func (n *createTypeNode) Close(ctx context.Context)           {}
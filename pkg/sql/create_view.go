@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -53,6 +54,9 @@ type createViewNode struct {
 	materialized bool
 	dbDesc       catalog.DatabaseDescriptor
 	columns      colinfo.ResultColumns
+	// storageParams contains the parameters of the WITH clause of a
+	// materialized view.
+	storageParams tree.StorageParams
 
 	// planDeps tracks which tables and views the view being created
 	// depends on. This is collected during the construction of
@@ -157,6 +161,11 @@ func (n *createViewNode) startExec(params runParams) error {
 	}
 
 	privs := CreateInheritedPrivilegesFromDBDesc(n.dbDesc, params.SessionData().User())
+	if err := params.p.applyDefaultPrivileges(
+		params.ctx, privs, n.dbDesc, schemaID, privilege.Tables,
+	); err != nil {
+		return err
+	}
 
 	var newDesc *tabledesc.Mutable
 	applyGlobalMultiRegionZoneConfig := false
@@ -234,6 +243,21 @@ func (n *createViewNode) startExec(params runParams) error {
 			}
 		}
 
+		if err := paramparse.ApplyStorageParameters(
+			params.ctx,
+			params.p.SemaCtx(),
+			params.EvalContext(),
+			n.storageParams,
+			paramparse.NewViewStorageParamObserver(&desc),
+		); err != nil {
+			return err
+		}
+		if desc.IncrementalRefresh != nil {
+			if err := n.setUpIncrementalRefresh(params, &desc); err != nil {
+				return err
+			}
+		}
+
 		// Collect all the tables/views this view depends on.
 		for backrefID := range n.planDeps {
 			desc.DependsOn = append(desc.DependsOn, backrefID)
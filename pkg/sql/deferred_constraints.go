@@ -0,0 +1,313 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// constraintMode is the checking mode of a DEFERRABLE constraint, as set by
+// SET CONSTRAINTS.
+type constraintMode int
+
+const (
+	// constraintModeDefault means that the constraint is checked according to
+	// its INITIALLY DEFERRED or INITIALLY IMMEDIATE declaration.
+	constraintModeDefault constraintMode = iota
+	// constraintModeImmediate means that the constraint is checked at the end
+	// of each statement.
+	constraintModeImmediate
+	// constraintModeDeferred means that the constraint is checked when the
+	// transaction commits.
+	constraintModeDeferred
+)
+
+// deferredConstraint identifies a DEFERRABLE constraint whose check has been
+// postponed until the end of the transaction.
+type deferredConstraint struct {
+	tableID    descpb.ID
+	name       string
+	foreignKey bool
+}
+
+// DeferredConstraintState is the transaction-scoped state of DEFERRABLE
+// constraints. It is stored in the connExecutor's extraTxnState and reset
+// whenever the transaction finishes.
+type DeferredConstraintState struct {
+	// allMode is the mode set by SET CONSTRAINTS ALL.
+	allMode constraintMode
+	// modes contains the modes set by SET CONSTRAINTS for individual
+	// constraints, keyed by constraint name. These take precedence over
+	// allMode.
+	modes map[string]constraintMode
+	// pending contains the constraints which were deferred and must be
+	// validated before the transaction commits.
+	pending []deferredConstraint
+}
+
+// isDeferred returns true if the check for the given constraint should be
+// postponed until the transaction commits.
+func (s *DeferredConstraintState) isDeferred(c *exec.DeferrableConstraint) bool {
+	mode := s.modes[c.Name]
+	if mode == constraintModeDefault {
+		mode = s.allMode
+	}
+	if mode == constraintModeDefault {
+		return c.InitiallyDeferred
+	}
+	return mode == constraintModeDeferred
+}
+
+// deferCheck records that the check for the given constraint was postponed.
+func (s *DeferredConstraintState) deferCheck(c *exec.DeferrableConstraint) {
+	dc := deferredConstraint{
+		tableID:    descpb.ID(c.Table.ID()),
+		name:       c.Name,
+		foreignKey: c.ForeignKey,
+	}
+	for i := range s.pending {
+		if s.pending[i] == dc {
+			return
+		}
+	}
+	s.pending = append(s.pending, dc)
+}
+
+// setMode updates the mode of the named constraints (or of all constraints,
+// if names is empty). When constraints become immediate, any of their checks
+// that were previously deferred must be performed right away; those checks are
+// removed from the pending set and returned.
+func (s *DeferredConstraintState) setMode(
+	names tree.NameList, deferred bool,
+) (nowImmediate []deferredConstraint) {
+	mode := constraintModeImmediate
+	if deferred {
+		mode = constraintModeDeferred
+	}
+	if len(names) == 0 {
+		s.allMode = mode
+		s.modes = nil
+	} else {
+		if s.modes == nil {
+			s.modes = make(map[string]constraintMode, len(names))
+		}
+		for _, name := range names {
+			s.modes[string(name)] = mode
+		}
+	}
+	if deferred {
+		return nil
+	}
+
+	remaining := s.pending[:0]
+	for _, dc := range s.pending {
+		if len(names) == 0 || s.modes[dc.name] == constraintModeImmediate {
+			nowImmediate = append(nowImmediate, dc)
+		} else {
+			remaining = append(remaining, dc)
+		}
+	}
+	s.pending = remaining
+	return nowImmediate
+}
+
+// validateDeferredConstraints verifies that all the rows in the tables of the
+// given constraints satisfy those constraints. Constraints which no longer
+// exist (e.g. because they were dropped later in the transaction) are ignored.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing kv.Txn safely.
+func validateDeferredConstraints(
+	ctx context.Context,
+	constraints []deferredConstraint,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	codec keys.SQLCodec,
+) error {
+	for _, dc := range constraints {
+		tableDesc, err := catalogkv.MustGetMutableTableDescByID(ctx, txn, codec, dc.tableID)
+		if err != nil {
+			return err
+		}
+		// The table may have been modified in this transaction, so always use
+		// the descriptors we just read rather than the leased versions.
+		syntheticDescs := []catalog.Descriptor{tableDesc}
+
+		if dc.foreignKey {
+			var fk *descpb.ForeignKeyConstraint
+			for i := range tableDesc.OutboundFKs {
+				if tableDesc.OutboundFKs[i].Name == dc.name {
+					fk = &tableDesc.OutboundFKs[i]
+					break
+				}
+			}
+			if fk == nil {
+				continue
+			}
+			if fk.ReferencedTableID != tableDesc.ID {
+				refDesc, err := catalogkv.MustGetTableDescByID(ctx, txn, codec, fk.ReferencedTableID)
+				if err != nil {
+					return err
+				}
+				syntheticDescs = append(syntheticDescs, refDesc)
+			}
+			if err := ie.WithSyntheticDescriptors(syntheticDescs, func() error {
+				return validateForeignKey(ctx, tableDesc, fk, ie, txn, codec)
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var uc *descpb.UniqueWithoutIndexConstraint
+		for i := range tableDesc.UniqueWithoutIndexConstraints {
+			if tableDesc.UniqueWithoutIndexConstraints[i].Name == dc.name {
+				uc = &tableDesc.UniqueWithoutIndexConstraints[i]
+				break
+			}
+		}
+		if uc == nil {
+			continue
+		}
+		if err := ie.WithSyntheticDescriptors(syntheticDescs, func() error {
+			return validateDeferredUniqueConstraint(ctx, tableDesc, uc, ie, txn)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDeferredUniqueConstraint verifies that there are no duplicate keys
+// for the given unique constraint. It differs from validateUniqueConstraint
+// only in the error it returns, which matches the one returned when a
+// non-deferred uniqueness check fails.
+func validateDeferredUniqueConstraint(
+	ctx context.Context,
+	tableDesc catalog.TableDescriptor,
+	uc *descpb.UniqueWithoutIndexConstraint,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+) error {
+	query, colNames, err := duplicateRowQuery(
+		tableDesc, uc.ColumnIDs, uc.Predicate, true, /* limitResults */
+	)
+	if err != nil {
+		return err
+	}
+
+	log.VEventf(ctx, 2, "validating deferred unique constraint %q with query %q", uc.Name, query)
+
+	values, err := ie.QueryRow(ctx, "validate deferred unique constraint", txn, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		valuesStr := make([]string, len(values))
+		for i := range values {
+			valuesStr[i] = values[i].String()
+		}
+		return errors.WithDetail(
+			pgerror.WithConstraintName(
+				pgerror.Newf(
+					pgcode.UniqueViolation, "duplicate key value violates unique constraint %q", uc.Name,
+				),
+				uc.Name,
+			),
+			fmt.Sprintf(
+				"Key (%s)=(%s) already exists.", strings.Join(colNames, ","), strings.Join(valuesStr, ","),
+			),
+		)
+	}
+	return nil
+}
+
+// SetConstraints sets the checking mode of DEFERRABLE constraints for the
+// current transaction.
+// Privileges: None.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	if p.extendedEvalCtx.TxnImplicit {
+		// Postgres only emits a warning in this case.
+		p.BufferClientNotice(ctx, pgnotice.Newf(
+			"SET CONSTRAINTS can only be used in transaction blocks",
+		))
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	for _, name := range n.Names {
+		if err := p.checkConstraintIsDeferrable(ctx, string(name)); err != nil {
+			return nil, err
+		}
+	}
+
+	state := p.extendedEvalCtx.DeferredConstraints
+	nowImmediate := state.setMode(n.Names, n.Deferred)
+	if len(nowImmediate) > 0 {
+		ie := p.extendedEvalCtx.InternalExecutor.(*InternalExecutor)
+		if err := validateDeferredConstraints(
+			ctx, nowImmediate, ie, p.txn, p.ExecCfg().Codec,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// checkConstraintIsDeferrable returns an error if there is no constraint with
+// the given name in the current database, or if any such constraint is not
+// DEFERRABLE.
+func (p *planner) checkConstraintIsDeferrable(ctx context.Context, name string) error {
+	_, dbDesc, err := p.Descriptors().GetImmutableDatabaseByName(
+		ctx, p.txn, p.CurrentDatabase(), tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return err
+	}
+	found := false
+	if err := forEachTableDesc(ctx, p, dbDesc, hideVirtual,
+		func(_ catalog.DatabaseDescriptor, _ string, table catalog.TableDescriptor) error {
+			conInfo, err := table.GetConstraintInfo()
+			if err != nil {
+				return err
+			}
+			detail, ok := conInfo[name]
+			if !ok {
+				return nil
+			}
+			found = true
+			if detail.Deferrability() == descpb.ConstraintDeferrability_NotDeferrable {
+				return pgerror.Newf(pgcode.WrongObjectType, "constraint %q is not deferrable", name)
+			}
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+	if !found {
+		return pgerror.Newf(pgcode.UndefinedObject, "constraint %q does not exist", name)
+	}
+	return nil
+}
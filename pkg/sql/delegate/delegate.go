@@ -136,6 +136,9 @@ func TryDelegate(
 	case *tree.ShowFullTableScans:
 		return d.delegateShowFullTableScans()
 
+	case *tree.ShowPlanBaselines:
+		return d.delegateShowPlanBaselines()
+
 	case *tree.ShowLastQueryStatistics:
 		return nil, unimplemented.New(
 			"show last query statistics",
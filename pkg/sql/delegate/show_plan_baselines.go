@@ -0,0 +1,24 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package delegate
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+func (d *delegator) delegateShowPlanBaselines() (tree.Statement, error) {
+	sqltelemetry.IncrementShowCounter(sqltelemetry.PlanBaselines)
+	const query = `
+  SELECT fingerprint, plan, created
+  FROM system.plan_baselines ORDER BY fingerprint`
+	return parse(query)
+}
@@ -51,7 +51,7 @@ type deleteRun struct {
 
 	// partialIndexDelValsOffset is the offset of partial index delete
 	// indicators in the source values. It is equal to the number of fetched
-	// columns.
+	// columns plus the number of passthrough columns.
 	partialIndexDelValsOffset int
 
 	// rowIdxToRetIdx is the mapping from the columns returned by the deleter
@@ -60,6 +60,11 @@ type deleteRun struct {
 	// of the mutation. Otherwise, the value at the i-th index refers to the
 	// index of the resultRowBuffer where the i-th column is to be returned.
 	rowIdxToRetIdx []int
+
+	// numPassthrough is the number of columns in addition to the set of
+	// columns of the target table being returned, that we must pass through
+	// from the input node.
+	numPassthrough int
 }
 
 func (d *deleteNode) startExec(params runParams) error {
@@ -163,12 +168,15 @@ func (d *deleteNode) processSourceRow(params runParams, sourceVals tree.Datums)
 		if err != nil {
 			return err
 		}
-
-		// Truncate sourceVals so that it no longer includes partial index
-		// predicate values.
-		sourceVals = sourceVals[:d.run.partialIndexDelValsOffset]
 	}
 
+	// Extract the values of the columns from the USING tables that are
+	// referenced in the RETURNING clause, then truncate sourceVals so that it
+	// only includes the fetched columns.
+	numFetchCols := len(d.run.td.rd.FetchCols)
+	passthroughValues := sourceVals[numFetchCols : numFetchCols+d.run.numPassthrough]
+	sourceVals = sourceVals[:numFetchCols]
+
 	// Queue the deletion in the KV batch.
 	if err := d.run.td.row(params.ctx, sourceVals, pm, d.run.traceKV); err != nil {
 		return err
@@ -190,6 +198,9 @@ func (d *deleteNode) processSourceRow(params runParams, sourceVals tree.Datums)
 			}
 		}
 
+		// The passthrough columns follow the columns of the target table.
+		copy(resultValues[len(resultValues)-d.run.numPassthrough:], passthroughValues)
+
 		if _, err := d.run.td.rows.AddRow(params.ctx, resultValues); err != nil {
 			return err
 		}
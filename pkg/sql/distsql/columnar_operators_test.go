@@ -18,15 +18,23 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecwindow"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/stretchr/testify/require"
@@ -1066,11 +1074,38 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 	maxNum := 10
 	typs := make([]*types.T, maxCols)
 	for i := range typs {
-		// TODO(yuzefovich): randomize the types of the columns once we support
-		// window functions that take in arguments.
+		// TODO(yuzefovich): randomize the types of the columns.
 		typs[i] = types.Int
 	}
+	// Sort the window functions so that the test is reproducible given the
+	// seed.
+	supportedWindowFns := make([]execinfrapb.WindowerSpec_WindowFunc, 0, len(colexecwindow.SupportedWindowFns))
 	for windowFn := range colexecwindow.SupportedWindowFns {
+		supportedWindowFns = append(supportedWindowFns, windowFn)
+	}
+	sort.Slice(supportedWindowFns, func(i, j int) bool {
+		return supportedWindowFns[i] < supportedWindowFns[j]
+	})
+	var windowFns []execinfrapb.WindowerSpec_Func
+	for _, windowFn := range supportedWindowFns {
+		windowFn := windowFn
+		windowFns = append(windowFns, execinfrapb.WindowerSpec_Func{WindowFunc: &windowFn})
+	}
+	// We only use the aggregate functions whose results don't depend on the
+	// order in which the tuples are aggregated.
+	for _, aggFn := range []execinfrapb.AggregatorSpec_Func{
+		execinfrapb.Avg,
+		execinfrapb.Count,
+		execinfrapb.CountRows,
+		execinfrapb.Max,
+		execinfrapb.Min,
+		execinfrapb.Sum,
+		execinfrapb.SumInt,
+	} {
+		aggFn := aggFn
+		windowFns = append(windowFns, execinfrapb.WindowerSpec_Func{AggregateFunc: &aggFn})
+	}
+	for _, windowFn := range windowFns {
 		for _, partitionBy := range [][]uint32{
 			{},     // No PARTITION BY clause.
 			{0},    // Partitioning on the first input column.
@@ -1088,28 +1123,36 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 					inputTypes := typs[:nCols:nCols]
 					rows := rowenc.MakeRandIntRowsInRange(rng, nRows, nCols, maxNum, nullProbability)
 
+					ordering := generateOrderingGivenPartitionBy(rng, nCols, nOrderingCols, partitionBy)
+					frame := generateWindowFrame(t, rng, ordering, maxNum)
+					if !windowFnIsDeterministic(windowFn, frame) &&
+						len(partitionBy)+len(ordering.Columns) < nCols {
+						// The output of the window function is not deterministic
+						// if there are columns that are not present in either
+						// PARTITION BY or ORDER BY clauses, so we skip such a
+						// configuration.
+						continue
+					}
+					argsIdxs := generateWindowFnArgs(rng, windowFn, nCols)
+					argTypes := make([]*types.T, len(argsIdxs))
+					for i, idx := range argsIdxs {
+						argTypes[i] = inputTypes[idx]
+					}
 					windowerSpec := &execinfrapb.WindowerSpec{
 						PartitionBy: partitionBy,
 						WindowFns: []execinfrapb.WindowerSpec_WindowFn{
 							{
-								Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &windowFn},
-								Ordering:     generateOrderingGivenPartitionBy(rng, nCols, nOrderingCols, partitionBy),
+								Func:         windowFn,
+								ArgsIdxs:     argsIdxs,
+								Ordering:     ordering,
+								Frame:        frame,
 								OutputColIdx: uint32(nCols),
 								FilterColIdx: tree.NoColumnIdx,
 							},
 						},
 					}
-					if windowFn == execinfrapb.WindowerSpec_ROW_NUMBER &&
-						len(partitionBy)+len(windowerSpec.WindowFns[0].Ordering.Columns) < nCols {
-						// The output of row_number is not deterministic if there are
-						// columns that are not present in either PARTITION BY or ORDER BY
-						// clauses, so we skip such a configuration.
-						continue
-					}
 
-					// Currently, we only support window functions that take no
-					// arguments, so we leave the second argument empty.
-					_, outputType, err := execinfrapb.GetWindowFunctionInfo(execinfrapb.WindowerSpec_Func{WindowFunc: &windowFn})
+					_, outputType, err := execinfrapb.GetWindowFunctionInfo(windowFn, argTypes...)
 					require.NoError(t, err)
 					pspec := &execinfrapb.ProcessorSpec{
 						Input:       []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
@@ -1124,6 +1167,7 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 					}
 					if err := verifyColOperator(t, args); err != nil {
 						fmt.Printf("seed = %d\n", seed)
+						t.Logf("windower spec = %s", windowerSpec)
 						prettyPrintTypes(inputTypes, "t" /* tableName */)
 						prettyPrintInput(rows, inputTypes, "t" /* tableName */)
 						t.Fatal(err)
@@ -1134,6 +1178,521 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 	}
 }
 
+// windowFnIsDeterministic returns whether the output of the window function
+// with the given frame doesn't depend on the order of the tuples within the
+// peer groups.
+func windowFnIsDeterministic(
+	windowFn execinfrapb.WindowerSpec_Func, frame *execinfrapb.WindowerSpec_Frame,
+) bool {
+	if windowFn.AggregateFunc != nil {
+		// The set of tuples within the window frame only depends on the order
+		// of the tuples when the frame is specified in terms of rows.
+		return frame == nil || frame.Mode != execinfrapb.WindowerSpec_Frame_ROWS
+	}
+	switch *windowFn.WindowFunc {
+	case execinfrapb.WindowerSpec_RANK, execinfrapb.WindowerSpec_DENSE_RANK,
+		execinfrapb.WindowerSpec_PERCENT_RANK, execinfrapb.WindowerSpec_CUME_DIST:
+		return true
+	default:
+		return false
+	}
+}
+
+// generateWindowFnArgs returns random columns (among the first nCols) to be
+// used as the arguments of the given window function.
+func generateWindowFnArgs(
+	rng *rand.Rand, windowFn execinfrapb.WindowerSpec_Func, nCols int,
+) []uint32 {
+	var nArgs int
+	if windowFn.AggregateFunc != nil {
+		nArgs = aggregateFuncToNumArguments[*windowFn.AggregateFunc]
+	} else {
+		switch *windowFn.WindowFunc {
+		case execinfrapb.WindowerSpec_NTILE, execinfrapb.WindowerSpec_FIRST_VALUE,
+			execinfrapb.WindowerSpec_LAST_VALUE:
+			nArgs = 1
+		case execinfrapb.WindowerSpec_NTH_VALUE:
+			nArgs = 2
+		case execinfrapb.WindowerSpec_LAG, execinfrapb.WindowerSpec_LEAD:
+			nArgs = 1 + rng.Intn(3)
+		}
+	}
+	argsIdxs := make([]uint32, nArgs)
+	for i := range argsIdxs {
+		argsIdxs[i] = uint32(rng.Intn(nCols))
+	}
+	return argsIdxs
+}
+
+// generateWindowFrame returns a random valid window frame (or nil, meaning the
+// default frame) given the ordering of the window function. It assumes that
+// the ordering columns are of Int type.
+func generateWindowFrame(
+	t *testing.T, rng *rand.Rand, ordering execinfrapb.Ordering, maxOffset int,
+) *execinfrapb.WindowerSpec_Frame {
+	if rng.Float64() < 0.2 {
+		return nil
+	}
+	frame := &execinfrapb.WindowerSpec_Frame{
+		Mode:      execinfrapb.WindowerSpec_Frame_Mode(rng.Intn(3)),
+		Exclusion: execinfrapb.WindowerSpec_Frame_Exclusion(rng.Intn(4)),
+	}
+	// Offsets are allowed in RANGE mode only with a single ordering column
+	// and in GROUPS mode only with an ordering.
+	offsetsAllowed := true
+	switch frame.Mode {
+	case execinfrapb.WindowerSpec_Frame_RANGE:
+		offsetsAllowed = len(ordering.Columns) == 1
+	case execinfrapb.WindowerSpec_Frame_GROUPS:
+		offsetsAllowed = len(ordering.Columns) > 0
+	}
+	// boundTypes lists the bound types in the order of the positions they
+	// refer to, so that the end bound can never precede the start bound.
+	boundTypes := []execinfrapb.WindowerSpec_Frame_BoundType{
+		execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING,
+		execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+		execinfrapb.WindowerSpec_Frame_CURRENT_ROW,
+		execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING,
+		execinfrapb.WindowerSpec_Frame_UNBOUNDED_FOLLOWING,
+	}
+	makeBound := func(minIdx, maxIdx int) execinfrapb.WindowerSpec_Frame_Bound {
+		var bound execinfrapb.WindowerSpec_Frame_Bound
+		for {
+			bound.BoundType = boundTypes[minIdx+rng.Intn(maxIdx-minIdx+1)]
+			if offsetsAllowed ||
+				(bound.BoundType != execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING &&
+					bound.BoundType != execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING) {
+				break
+			}
+		}
+		if bound.BoundType != execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING &&
+			bound.BoundType != execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING {
+			return bound
+		}
+		offset := rng.Intn(maxOffset)
+		if frame.Mode == execinfrapb.WindowerSpec_Frame_RANGE {
+			var a rowenc.DatumAlloc
+			d := rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(offset)))
+			var err error
+			bound.TypedOffset, err = d.Encode(types.Int, &a, descpb.DatumEncoding_VALUE, nil /* appendTo */)
+			require.NoError(t, err)
+			bound.OffsetType = execinfrapb.DatumInfo{Encoding: descpb.DatumEncoding_VALUE, Type: types.Int}
+		} else {
+			bound.IntOffset = uint64(offset)
+		}
+		return bound
+	}
+	// The start bound cannot be UNBOUNDED FOLLOWING.
+	frame.Bounds.Start = makeBound(0 /* minIdx */, len(boundTypes)-2 /* maxIdx */)
+	startIdx := 0
+	for i, boundType := range boundTypes {
+		if boundType == frame.Bounds.Start.BoundType {
+			startIdx = i
+		}
+	}
+	if startIdx <= 2 && rng.Float64() < 0.2 {
+		// Omit the end bound which is then CURRENT ROW.
+		return frame
+	}
+	// The end bound cannot be UNBOUNDED PRECEDING, and it can be OFFSET
+	// PRECEDING when the start bound is OFFSET PRECEDING.
+	minEndIdx := startIdx
+	if minEndIdx == 0 {
+		minEndIdx = 1
+	}
+	end := makeBound(minEndIdx, len(boundTypes)-1 /* maxIdx */)
+	if frame.Mode == execinfrapb.WindowerSpec_Frame_GROUPS && end.BoundType == frame.Bounds.Start.BoundType {
+		// The row-by-row windower doesn't support GROUPS frames in which the
+		// end bound precedes the start bound when both have offsets of the
+		// same type, so we make sure that the frame is not empty.
+		start := &frame.Bounds.Start
+		if (start.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING && start.IntOffset < end.IntOffset) ||
+			(start.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING && start.IntOffset > end.IntOffset) {
+			start.IntOffset, end.IntOffset = end.IntOffset, start.IntOffset
+		}
+	}
+	frame.Bounds.End = &end
+	return frame
+}
+
+// randIntFn returns a GenValueFn that generates random integers in
+// [0, maxNum), or NULLs with the given probability.
+func randIntFn(rng *rand.Rand, maxNum int, nullProbability float64) sqlutils.GenValueFn {
+	return func(int) tree.Datum {
+		if rng.Float64() < nullProbability {
+			return tree.DNull
+		}
+		return tree.NewDInt(tree.DInt(rng.Intn(maxNum)))
+	}
+}
+
+func TestJoinReaderAgainstProcessor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	rng, seed := randutil.NewPseudoRand()
+	const (
+		nTableRows = 200
+		nRows      = 100
+		nRuns      = 5
+		maxCols    = 3
+		maxNum     = 20
+	)
+	// The rows are unique on b alone, so that the lookups on a prefix of the
+	// indexes find several rows.
+	tableRows := make([][]tree.Datum, nTableRows)
+	genRow := sqlutils.ToRowFn(
+		randIntFn(rng, maxNum, 0 /* nullProbability */),
+		sqlutils.RowIdxFn,
+		randIntFn(rng, maxNum, nullProbability),
+		randIntFn(rng, maxNum, nullProbability),
+	)
+	sqlutils.CreateTable(t, sqlDB, "t",
+		"a INT, b INT, c INT, d INT, PRIMARY KEY (a, b), INDEX c_idx (c), FAMILY f1 (a, b, c), FAMILY f2 (d)",
+		nTableRows,
+		func(row int) []tree.Datum {
+			tableRows[row-1] = genRow(row)
+			return tableRows[row-1]
+		},
+	)
+	td := catalogkv.TestingGetTableDescriptor(kvDB, keys.SystemSQLCodec, "test", "t")
+	// The number of the key columns of the primary index (a, b) and of c_idx
+	// (c, a, b), and the number of the table columns that they contain.
+	nIndexKeyCols := []int{2, 3}
+	nIndexCols := []int{4, 3}
+	typs := make([]*types.T, maxCols)
+	for i := range typs {
+		typs[i] = types.Int
+	}
+	txn := kv.NewTxn(ctx, kvDB, s.NodeID())
+
+	for run := 0; run < nRuns; run++ {
+		// Index joins look up the rows of the primary index that the input
+		// rows, which come from a secondary index, point to, so each row is
+		// looked up at most once.
+		rows := make(rowenc.EncDatumRows, nRows)
+		for i, rowIdx := range rng.Perm(nTableRows)[:nRows] {
+			tableRow := tableRows[rowIdx]
+			rows[i] = rowenc.EncDatumRow{
+				rowenc.DatumToEncDatum(types.Int, tableRow[0]),
+				rowenc.DatumToEncDatum(types.Int, tableRow[1]),
+			}
+		}
+		inputTypes := typs[:2:2]
+		spec := &execinfrapb.JoinReaderSpec{
+			Table:            *td.TableDesc(),
+			MaintainOrdering: rng.Intn(2) == 0,
+		}
+		if err := verifyJoinReader(t, txn, spec, nil /* outputCols */, inputTypes, rows); err != nil {
+			fmt.Printf("--- index join seed = %d run = %d ---\n", seed, run)
+			prettyPrintInput(rows, inputTypes, "input" /* tableName */)
+			t.Fatal(err)
+		}
+
+		for indexIdx := range nIndexKeyCols {
+			for _, joinType := range []descpb.JoinType{
+				descpb.InnerJoin, descpb.LeftOuterJoin, descpb.LeftSemiJoin, descpb.LeftAntiJoin,
+			} {
+				nCols := 1 + rng.Intn(maxCols)
+				inputTypes := typs[:nCols:nCols]
+				rows := rowenc.MakeRandIntRowsInRange(rng, nRows, nCols, maxNum, nullProbability)
+				nLookupCols := 1 + rng.Intn(nIndexKeyCols[indexIdx])
+				if nLookupCols > nCols {
+					nLookupCols = nCols
+				}
+				spec := &execinfrapb.JoinReaderSpec{
+					Table:               *td.TableDesc(),
+					IndexIdx:            uint32(indexIdx),
+					LookupColumns:       generateEqualityColumns(rng, nCols, nLookupCols),
+					LookupColumnsAreKey: indexIdx == 0 && nLookupCols == nIndexKeyCols[indexIdx],
+					Type:                joinType,
+					MaintainOrdering:    rng.Intn(2) == 0,
+				}
+				if joinType == descpb.InnerJoin && rng.Intn(2) == 0 {
+					// Only the inner lookup joins with ON expressions are
+					// supported by the vectorized engine.
+					spec.OnExpr.Expr = fmt.Sprintf(
+						"@%d < @%d", 1+rng.Intn(nCols), nCols+1+rng.Intn(nIndexCols[indexIdx]),
+					)
+				}
+				// Only the table columns contained in the index are output.
+				var outputCols []uint32
+				if joinType.ShouldIncludeRightColsInOutput() {
+					for i := 0; i < nCols+nIndexCols[indexIdx]; i++ {
+						outputCols = append(outputCols, uint32(i))
+					}
+				}
+				if err := verifyJoinReader(t, txn, spec, outputCols, inputTypes, rows); err != nil {
+					fmt.Printf("--- seed = %d run = %d index = %d join type = %s onExpr = %q ---\n",
+						seed, run, indexIdx, joinType, spec.OnExpr.Expr)
+					fmt.Printf("--- lookup cols = %v ---\n", spec.LookupColumns)
+					prettyPrintTypes(inputTypes, "input" /* tableName */)
+					prettyPrintInput(rows, inputTypes, "input" /* tableName */)
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
+// verifyJoinReader verifies that the vectorized join reader with the given
+// spec returns the same rows as the row-by-row one. If outputCols is non-nil,
+// only those columns are output.
+func verifyJoinReader(
+	t *testing.T,
+	txn *kv.Txn,
+	spec *execinfrapb.JoinReaderSpec,
+	outputCols []uint32,
+	inputTypes []*types.T,
+	rows rowenc.EncDatumRows,
+) error {
+	tableTypes := catalog.ColumnTypes(spec.BuildTableDescriptor().PublicColumns())
+	resultTypes := tableTypes
+	if len(spec.LookupColumns) > 0 {
+		// Unlike the index joins, the lookup joins output the input columns.
+		resultTypes = spec.Type.MakeOutputTypes(inputTypes, tableTypes)
+	}
+	var post execinfrapb.PostProcessSpec
+	if outputCols != nil {
+		post = execinfrapb.PostProcessSpec{Projection: true, OutputColumns: outputCols}
+		internalTypes := resultTypes
+		resultTypes = make([]*types.T, len(outputCols))
+		for i, col := range outputCols {
+			resultTypes[i] = internalTypes[col]
+		}
+	}
+	return verifyColOperator(t, verifyColOperatorArgs{
+		anyOrder:   true,
+		inputTypes: [][]*types.T{inputTypes},
+		inputs:     []rowenc.EncDatumRows{rows},
+		pspec: &execinfrapb.ProcessorSpec{
+			Input:       []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+			Core:        execinfrapb.ProcessorCoreUnion{JoinReader: spec},
+			Post:        post,
+			ResultTypes: resultTypes,
+		},
+		txn: txn,
+	})
+}
+
+func TestZigzagJoinerAgainstProcessor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	rng, seed := randutil.NewPseudoRand()
+	const (
+		nTableRows = 200
+		nRuns      = 20
+		maxNum     = 5
+	)
+	sqlutils.CreateTable(t, sqlDB, "t",
+		"a INT, b INT, c INT, d INT, PRIMARY KEY (a, b), INDEX c_idx (c), INDEX d_idx (d DESC)",
+		nTableRows,
+		sqlutils.ToRowFn(
+			randIntFn(rng, maxNum, 0 /* nullProbability */),
+			sqlutils.RowIdxFn,
+			randIntFn(rng, maxNum, nullProbability),
+			randIntFn(rng, maxNum, nullProbability),
+		),
+	)
+	td := catalogkv.TestingGetTableDescriptor(kvDB, keys.SystemSQLCodec, "test", "t")
+	tableTypes := catalog.ColumnTypes(td.PublicColumns())
+	txn := kv.NewTxn(ctx, kvDB, s.NodeID())
+
+	fixedValues := func(v int) *execinfrapb.ValuesCoreSpec {
+		row := rowenc.EncDatumRow{rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(v)))}
+		spec, err := execinfra.GenerateValuesSpec(
+			[]*types.T{types.Int}, rowenc.EncDatumRows{row}, 1, /* rowsPerChunk */
+		)
+		require.NoError(t, err)
+		return &spec
+	}
+	// The left side reads c_idx, which contains the columns a, b and c, and
+	// the right side reads d_idx, which contains the columns a, b and d.
+	leftCols, rightCols := []uint32{0, 1, 2}, []uint32{0, 1, 3}
+	var outputCols []uint32
+	var resultTypes []*types.T
+	for _, col := range leftCols {
+		outputCols = append(outputCols, col)
+		resultTypes = append(resultTypes, tableTypes[col])
+	}
+	for _, col := range rightCols {
+		outputCols = append(outputCols, uint32(len(tableTypes))+col)
+		resultTypes = append(resultTypes, tableTypes[col])
+	}
+
+	for run := 0; run < nRuns; run++ {
+		// The rows are joined either on a or on (a, b), which follow the
+		// fixed columns in both indexes.
+		eqCols := []uint32{0, 1}[:1+rng.Intn(2)]
+		spec := &execinfrapb.ZigzagJoinerSpec{
+			Tables:        []descpb.TableDescriptor{*td.TableDesc(), *td.TableDesc()},
+			EqColumns:     []execinfrapb.Columns{{Columns: eqCols}, {Columns: eqCols}},
+			IndexOrdinals: []uint32{1 /* c_idx */, 2 /* d_idx */},
+			FixedValues:   []*execinfrapb.ValuesCoreSpec{fixedValues(rng.Intn(maxNum)), fixedValues(rng.Intn(maxNum))},
+			Type:          descpb.InnerJoin,
+		}
+		if rng.Intn(2) == 0 {
+			spec.OnExpr.Expr = fmt.Sprintf(
+				"@%d < @%d", 1+leftCols[rng.Intn(len(leftCols))],
+				1+len(tableTypes)+int(rightCols[rng.Intn(len(rightCols))]),
+			)
+		}
+		pspec := &execinfrapb.ProcessorSpec{
+			Core:        execinfrapb.ProcessorCoreUnion{ZigzagJoiner: spec},
+			Post:        execinfrapb.PostProcessSpec{Projection: true, OutputColumns: outputCols},
+			ResultTypes: resultTypes,
+		}
+		if err := verifyColOperator(t, verifyColOperatorArgs{anyOrder: true, pspec: pspec, txn: txn}); err != nil {
+			fmt.Printf("--- seed = %d run = %d eqCols = %v onExpr = %q ---\n",
+				seed, run, eqCols, spec.OnExpr.Expr)
+			t.Fatal(err)
+		}
+	}
+}
+
+// randIntArrayFn returns a GenValueFn that generates random arrays of up to
+// maxLen integers in [0, maxNum), or NULLs with nullProbability.
+func randIntArrayFn(rng *rand.Rand, maxLen, maxNum int) sqlutils.GenValueFn {
+	return func(int) tree.Datum {
+		if rng.Float64() < nullProbability {
+			return tree.DNull
+		}
+		arr := tree.NewDArray(types.Int)
+		for i, n := 0, rng.Intn(maxLen+1); i < n; i++ {
+			if err := arr.Append(tree.NewDInt(tree.DInt(rng.Intn(maxNum)))); err != nil {
+				panic(err)
+			}
+		}
+		return arr
+	}
+}
+
+func TestInvertedJoinerAgainstProcessor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	rng, seed := randutil.NewPseudoRand()
+	const (
+		nTableRows = 200
+		nRows      = 50
+		nRuns      = 5
+		maxNum     = 5
+		maxLen     = 3
+	)
+	sqlutils.CreateTable(t, sqlDB, "t",
+		"a INT PRIMARY KEY, b INT, c INT[], INVERTED INDEX c_idx (c), INVERTED INDEX bc_idx (b, c)",
+		nTableRows,
+		sqlutils.ToRowFn(
+			sqlutils.RowIdxFn,
+			randIntFn(rng, maxNum, nullProbability),
+			randIntArrayFn(rng, maxLen, maxNum),
+		),
+	)
+	td := catalogkv.TestingGetTableDescriptor(kvDB, keys.SystemSQLCodec, "test", "t")
+	tableTypes := catalog.ColumnTypes(td.PublicColumns())
+	txn := kv.NewTxn(ctx, kvDB, s.NodeID())
+
+	// The input rows consist of an integer, which is compared with the
+	// columns a and b, and of an array, which is compared with the inverted
+	// column c.
+	inputTypes := []*types.T{types.Int, types.IntArray}
+	genInputValues := []sqlutils.GenValueFn{
+		randIntFn(rng, maxNum, nullProbability), randIntArrayFn(rng, maxLen, maxNum),
+	}
+	invertedColIdx := len(inputTypes) + 2
+	invertedExprs := []string{
+		fmt.Sprintf("@%d @> @2", 1+invertedColIdx),
+		fmt.Sprintf("@%d <@ @2", 1+invertedColIdx),
+	}
+
+	for run := 0; run < nRuns; run++ {
+		for _, index := range []struct {
+			idx uint32
+			// nCols is the number of the table columns other than the
+			// inverted column that the index contains.
+			nCols int
+		}{
+			{idx: 1 /* c_idx */, nCols: 1 /* a */},
+			{idx: 2 /* bc_idx */, nCols: 2 /* a, b */},
+		} {
+			// Only the input columns and the columns of the index other than
+			// the inverted column are output.
+			var outputCols []uint32
+			for i := 0; i < len(inputTypes)+index.nCols; i++ {
+				outputCols = append(outputCols, uint32(i))
+			}
+			for _, joinType := range []descpb.JoinType{
+				descpb.InnerJoin, descpb.LeftOuterJoin, descpb.LeftSemiJoin, descpb.LeftAntiJoin,
+			} {
+				rows := make(rowenc.EncDatumRows, nRows)
+				for i := range rows {
+					rows[i] = make(rowenc.EncDatumRow, len(inputTypes))
+					for j := range rows[i] {
+						rows[i][j] = rowenc.DatumToEncDatum(inputTypes[j], genInputValues[j](i))
+					}
+				}
+				spec := &execinfrapb.InvertedJoinerSpec{
+					Table:            *td.TableDesc(),
+					IndexIdx:         index.idx,
+					InvertedExpr:     execinfrapb.Expression{Expr: invertedExprs[rng.Intn(len(invertedExprs))]},
+					Type:             joinType,
+					MaintainOrdering: rng.Intn(2) == 0,
+				}
+				if index.idx == 2 {
+					// The integer input column is equal to b, the prefix
+					// column of bc_idx.
+					spec.PrefixEqualityColumns = []uint32{0}
+				}
+				if rng.Intn(2) == 0 {
+					// The ON expression can only refer to the input columns
+					// and to the columns of the index other than the inverted
+					// column.
+					spec.OnExpr.Expr = fmt.Sprintf("@1 < @%d", len(inputTypes)+1+rng.Intn(index.nCols))
+				}
+				var post execinfrapb.PostProcessSpec
+				resultTypes := inputTypes
+				if joinType.ShouldIncludeRightColsInOutput() {
+					post = execinfrapb.PostProcessSpec{Projection: true, OutputColumns: outputCols}
+					internalTypes := append(inputTypes[:len(inputTypes):len(inputTypes)], tableTypes...)
+					resultTypes = make([]*types.T, len(outputCols))
+					for i, col := range outputCols {
+						resultTypes[i] = internalTypes[col]
+					}
+				}
+				args := verifyColOperatorArgs{
+					anyOrder:   true,
+					inputTypes: [][]*types.T{inputTypes},
+					inputs:     []rowenc.EncDatumRows{rows},
+					pspec: &execinfrapb.ProcessorSpec{
+						Input:       []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+						Core:        execinfrapb.ProcessorCoreUnion{InvertedJoiner: spec},
+						Post:        post,
+						ResultTypes: resultTypes,
+					},
+					txn: txn,
+				}
+				if err := verifyColOperator(t, args); err != nil {
+					fmt.Printf("--- seed = %d run = %d index = %d join type = %s invertedExpr = %q onExpr = %q ---\n",
+						seed, run, index.idx, joinType, spec.InvertedExpr.Expr, spec.OnExpr.Expr)
+					prettyPrintTypes(inputTypes, "input" /* tableName */)
+					prettyPrintInput(rows, inputTypes, "input" /* tableName */)
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
 // generateRandomSupportedTypes generates nCols random types that are supported
 // by the vectorized engine.
 func generateRandomSupportedTypes(rng *rand.Rand, nCols int) []*types.T {
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coldataext"
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
@@ -66,6 +67,8 @@ type verifyColOperatorArgs struct {
 	numForcedRepartitions int
 	// rng (if set) will be used to randomize batch size.
 	rng *rand.Rand
+	// txn must be set for the processors that read from KV.
+	txn *kv.Txn
 }
 
 // verifyColOperator passes inputs through both the processor defined by pspec
@@ -101,6 +104,7 @@ func verifyColOperator(t *testing.T, args verifyColOperatorArgs) error {
 			Settings:    st,
 			TempStorage: tempEngine,
 		},
+		Txn:         args.txn,
 		DiskMonitor: diskMonitor,
 	}
 	flowCtx.Cfg.TestingKnobs.ForceDiskSpill = args.forceDiskSpill
@@ -203,7 +207,9 @@ func verifyColOperator(t *testing.T, args verifyColOperatorArgs) error {
 		if rowProc != nil {
 			procRows = append(procRows, printRowForChecking(rowProc))
 		}
-		if metaProc != nil {
+		// The processors that read from KV emit the metrics of the reads,
+		// which aren't compared.
+		if metaProc != nil && metaProc.Metrics == nil {
 			if metaProc.Err == nil {
 				return errors.Errorf("unexpectedly processor returned non-error "+
 					"meta\n%+v", metaProc)
@@ -214,7 +220,7 @@ func verifyColOperator(t *testing.T, args verifyColOperatorArgs) error {
 		if rowColOp != nil {
 			colOpRows = append(colOpRows, printRowForChecking(rowColOp))
 		}
-		if metaColOp != nil {
+		if metaColOp != nil && metaColOp.Metrics == nil {
 			if metaColOp.Err == nil {
 				return errors.Errorf("unexpectedly columnar operator returned "+
 					"non-error meta\n%+v", metaColOp)
@@ -17,11 +17,17 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/span"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
@@ -83,12 +89,25 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 	for i, c := range scan.cols {
 		colIdxMap.Set(c.GetID(), i)
 	}
-	sb := span.MakeBuilder(planCtx.EvalContext(), planCtx.ExtendedEvalCtx.Codec, desc, scan.index)
-	scan.spans, err = sb.UnconstrainedSpans()
-	if err != nil {
-		return nil, err
+	var fullStatisticID uint64
+	if details.UsingExtremes {
+		// Only scan the values beyond the bounds of the existing histogram.
+		var idx catalog.Index
+		idx, scan.spans, fullStatisticID, err = dsp.createStatsExtremesSpans(
+			planCtx, desc, reqStats,
+		)
+		if err != nil {
+			return nil, err
+		}
+		scan.index = idx.IndexDesc()
+	} else {
+		sb := span.MakeBuilder(planCtx.EvalContext(), planCtx.ExtendedEvalCtx.Codec, desc, scan.index)
+		scan.spans, err = sb.UnconstrainedSpans()
+		if err != nil {
+			return nil, err
+		}
+		scan.isFull = true
 	}
-	scan.isFull = true
 
 	p, err := dsp.createTableReaders(planCtx, &scan)
 	if err != nil {
@@ -192,7 +211,9 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 	}
 
 	var rowsExpected uint64
-	if len(tableStats) > 0 {
+	// There is no estimate of the number of rows beyond the bounds of the
+	// histogram when collecting statistics at the extremes.
+	if len(tableStats) > 0 && !details.UsingExtremes {
 		overhead := stats.AutomaticStatisticsFractionStaleRows.Get(&dsp.st.SV)
 		// Convert to a signed integer first to make the linter happy.
 		rowsExpected = uint64(int64(
@@ -211,6 +232,7 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 		TableID:          desc.GetID(),
 		JobID:            job.ID(),
 		RowsExpected:     rowsExpected,
+		FullStatisticID:  fullStatisticID,
 	}
 	// Plan the SampleAggregator on the gateway, unless we have a single Sampler.
 	node := dsp.gatewayNodeID
@@ -228,12 +250,104 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 	return p, nil
 }
 
+// createStatsExtremesSpans returns the index to scan and the spans that cover
+// the values of the single requested column that are beyond the bounds of the
+// histogram of its latest statistic, for CREATE STATISTICS ... USING EXTREMES.
+// It also returns the ID of that statistic, into which the new statistic is
+// merged. NULL values are scanned as well, so that the NULL count of the merged
+// statistic includes rows added since the existing statistic was collected.
+func (dsp *DistSQLPlanner) createStatsExtremesSpans(
+	planCtx *PlanningCtx, desc catalog.TableDescriptor, reqStats []requestedStat,
+) (catalog.Index, roachpb.Spans, uint64, error) {
+	if len(reqStats) != 1 || len(reqStats[0].columns) != 1 || !reqStats[0].histogram {
+		return nil, nil, 0, errors.AssertionFailedf(
+			"statistics at the extremes require a single column with a histogram",
+		)
+	}
+	col, err := desc.FindColumnWithID(reqStats[0].columns[0])
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	idx := extremesIndex(desc, col.GetID())
+	if idx == nil {
+		return nil, nil, 0, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"table %s does not contain a non-partial forward index with %s as a prefix column",
+			desc.GetName(), col.GetName(),
+		)
+	}
+	stat, err := latestStatisticForExtremes(
+		planCtx.ctx, planCtx.ExtendedEvalCtx.ExecCfg.TableStatsCache, desc, col,
+	)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	// The decoded histogram starts with a fake bucket for NULLs if the column
+	// has any NULL values.
+	hist := stat.Histogram
+	if hist[0].UpperBound == tree.DNull {
+		hist = hist[1:]
+	}
+	lowerBound, upperBound := hist[0].UpperBound, hist[len(hist)-1].UpperBound
+
+	// Build a constraint on the first index column with one span below the
+	// lower bound (including NULLs) and one span above the upper bound. The
+	// constraint column ID is arbitrary; only its direction matters for
+	// building the index spans.
+	descending := idx.GetColumnDirection(0) == descpb.IndexDescriptor_DESC
+	var columns constraint.Columns
+	columns.InitSingle(opt.MakeOrderingColumn(opt.ColumnID(1), descending))
+	keyCtx := constraint.MakeKeyContext(&columns, planCtx.EvalContext())
+	var below, above constraint.Span
+	var spans constraint.Spans
+	spans.Alloc(2)
+	if !descending {
+		// NULLs sort first in ascending indexes: [ - /lower) and (/upper - ].
+		below.Init(
+			constraint.EmptyKey, constraint.IncludeBoundary,
+			constraint.MakeKey(lowerBound), constraint.ExcludeBoundary,
+		)
+		above.Init(
+			constraint.MakeKey(upperBound), constraint.ExcludeBoundary,
+			constraint.EmptyKey, constraint.IncludeBoundary,
+		)
+		spans.Append(&below)
+		spans.Append(&above)
+	} else {
+		// NULLs sort last in descending indexes: [ - /upper) and (/lower - ].
+		above.Init(
+			constraint.EmptyKey, constraint.IncludeBoundary,
+			constraint.MakeKey(upperBound), constraint.ExcludeBoundary,
+		)
+		below.Init(
+			constraint.MakeKey(lowerBound), constraint.ExcludeBoundary,
+			constraint.EmptyKey, constraint.IncludeBoundary,
+		)
+		spans.Append(&above)
+		spans.Append(&below)
+	}
+	var c constraint.Constraint
+	c.Init(&keyCtx, &spans)
+
+	sb := span.MakeBuilder(planCtx.EvalContext(), planCtx.ExtendedEvalCtx.Codec, desc, idx.IndexDesc())
+	roachSpans, err := sb.SpansFromConstraint(&c, exec.TableColumnOrdinalSet{}, false /* forDelete */)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return idx, roachSpans, stat.StatisticID, nil
+}
+
 func (dsp *DistSQLPlanner) createPlanForCreateStats(
 	planCtx *PlanningCtx, job *jobs.Job,
 ) (*PhysicalPlan, error) {
 	details := job.Details().(jobspb.CreateStatsDetails)
 	reqStats := make([]requestedStat, len(details.ColumnStats))
 	histogramCollectionEnabled := stats.HistogramClusterMode.Get(&dsp.st.SV)
+	if details.UsingExtremes && !histogramCollectionEnabled {
+		return nil, pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+			"cannot create statistics USING EXTREMES when histogram collection is disabled",
+		)
+	}
 	for i := 0; i < len(reqStats); i++ {
 		histogram := details.ColumnStats[i].HasHistogram && histogramCollectionEnabled
 		histogramMaxBuckets := defaultHistogramBuckets
@@ -1103,6 +1103,13 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 	}
 
 	for i := range plan.checkPlans {
+		if d := plan.checkPlans[i].deferrable; d != nil {
+			if dcs := planner.extendedEvalCtx.DeferredConstraints; dcs != nil && dcs.isDeferred(d) {
+				log.VEventf(ctx, 1, "deferring check query %d out of %d", i+1, len(plan.checkPlans))
+				dcs.deferCheck(d)
+				continue
+			}
+		}
 		log.VEventf(ctx, 1, "executing check query %d out of %d", i+1, len(plan.checkPlans))
 		if err := dsp.planAndRunPostquery(
 			ctx,
@@ -124,6 +124,17 @@ func (n *dropIndexNode) startExec(params runParams) error {
 		if idx != nil && idx.IsSharded() && !idx.Dropped() {
 			shardColName = idx.GetShardColumnName()
 		}
+		// If we're dropping an expression index, record the names of the
+		// inaccessible columns backing its elements to drop them as well.
+		var exprColNames []string
+		if idx != nil && !idx.Dropped() {
+			for i := 0; i < idx.NumColumns(); i++ {
+				col, err := tableDesc.FindColumnWithID(idx.GetColumnID(i))
+				if err == nil && col.IsInaccessible() {
+					exprColNames = append(exprColNames, col.GetName())
+				}
+			}
+		}
 
 		if err := params.p.dropIndexByName(
 			ctx, index.tn, index.idxName, tableDesc, n.n.IfExists, n.n.DropBehavior, checkIdxConstraint,
@@ -137,6 +148,12 @@ func (n *dropIndexNode) startExec(params runParams) error {
 				return err
 			}
 		}
+
+		if len(exprColNames) > 0 {
+			if err := n.maybeDropExpressionIndexColumns(params, tableDesc, exprColNames); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -209,6 +226,52 @@ func (n *dropIndexNode) maybeDropShardColumn(
 	return n.dropShardColumnAndConstraint(params, tableDesc, shardColDesc.ColumnDesc())
 }
 
+// maybeDropExpressionIndexColumns drops the given inaccessible columns, which
+// back the elements of an expression index, if there aren't any other indexes
+// referring to them.
+func (n *dropIndexNode) maybeDropExpressionIndexColumns(
+	params runParams, tableDesc *tabledesc.Mutable, colNames []string,
+) error {
+	droppedColumn := false
+	for _, colName := range colNames {
+		col, err := tableDesc.FindColumnWithName(tree.Name(colName))
+		if err != nil {
+			return err
+		}
+		if col.Dropped() {
+			continue
+		}
+		if catalog.FindNonDropIndex(tableDesc, func(otherIdx catalog.Index) bool {
+			return otherIdx.ContainsColumnID(col.GetID())
+		}) != nil {
+			continue
+		}
+		colDesc := col.ColumnDesc()
+		tableDesc.AddColumnMutation(colDesc, descpb.DescriptorMutation_DROP)
+		for i := range tableDesc.Columns {
+			if tableDesc.Columns[i].ID == colDesc.ID {
+				// Note the third slice parameter which will force a copy of the
+				// backing array if the column being removed is not the last column.
+				tableDesc.Columns = append(tableDesc.Columns[:i:i],
+					tableDesc.Columns[i+1:]...)
+				break
+			}
+		}
+		droppedColumn = true
+	}
+	if !droppedColumn {
+		return nil
+	}
+
+	if err := tableDesc.AllocateIDs(params.ctx); err != nil {
+		return err
+	}
+	mutationID := tableDesc.ClusterVersion.NextMutationID
+	return params.p.writeSchemaChange(
+		params.ctx, tableDesc, mutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
 func (*dropIndexNode) Next(runParams) (bool, error) { return false, nil }
 func (*dropIndexNode) Values() tree.Datums          { return tree.Datums{} }
 func (*dropIndexNode) Close(context.Context)        {}
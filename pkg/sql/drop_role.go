@@ -14,9 +14,11 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
@@ -221,6 +223,50 @@ func (n *DropRoleNode) startExec(params runParams) error {
 		}
 	}
 
+	// Check the default privileges of the databases and schemas, both for the
+	// roles whose new objects they apply to and for the grantees. Otherwise a
+	// new role with the same name would inherit them.
+	defaultPrivileges := make(map[security.SQLUsername][]string)
+	addDefaultPrivileges := func(d *descpb.DefaultPrivilegeDescriptor, in string) {
+		if d.IsEmpty() {
+			return
+		}
+		for _, forRole := range d.DefaultPrivilegesPerRole {
+			role := forRole.Role()
+			objects := strings.ToLower(forRole.TargetObjectType.String())
+			if _, ok := userNames[role]; ok {
+				defaultPrivileges[role] = append(defaultPrivileges[role], fmt.Sprintf(
+					"owner of default privileges on new %s belonging to role %s in %s", objects, role, in))
+			}
+			for _, u := range forRole.Users {
+				if _, ok := userNames[u.User()]; ok {
+					defaultPrivileges[u.User()] = append(defaultPrivileges[u.User()], fmt.Sprintf(
+						"privileges for default privileges on new %s belonging to role %s in %s", objects, role, in))
+				}
+			}
+		}
+	}
+	for _, dbID := range lCtx.dbIDs {
+		db := lCtx.dbDescs[dbID]
+		if !descriptorIsVisible(db, true /* allowAdding */) {
+			continue
+		}
+		addDefaultPrivileges(db.GetDefaultPrivileges(), fmt.Sprintf("database %s", db.GetName()))
+	}
+	for _, schemaID := range lCtx.schemaIDs {
+		schemaDesc := lCtx.schemaDescs[schemaID]
+		if !descriptorIsVisible(schemaDesc, true /* allowAdding */) {
+			continue
+		}
+		sc := tree.ObjectNamePrefix{
+			CatalogName:     tree.Name(lCtx.getDatabaseName(schemaDesc)),
+			SchemaName:      tree.Name(schemaDesc.GetName()),
+			ExplicitCatalog: true,
+			ExplicitSchema:  true,
+		}
+		addDefaultPrivileges(schemaDesc.GetDefaultPrivileges(), fmt.Sprintf("schema %s", sc.String()))
+	}
+
 	// Was there any object depending on that user?
 	if f.Len() > 0 {
 		fnl := tree.NewFmtCtx(tree.FmtSimple)
@@ -243,11 +289,14 @@ func (n *DropRoleNode) startExec(params runParams) error {
 		name := security.MakeSQLUsernameFromPreNormalizedString(names[i])
 		// Did the user own any objects?
 		ownedObjects := userNames[name]
-		if len(ownedObjects) > 0 {
+		if len(ownedObjects) > 0 || len(defaultPrivileges[name]) > 0 {
 			objectsMsg := tree.NewFmtCtx(tree.FmtSimple)
 			for _, obj := range ownedObjects {
 				objectsMsg.WriteString(fmt.Sprintf("\nowner of %s %s", obj.ObjectType, obj.ObjectName))
 			}
+			for _, dep := range defaultPrivileges[name] {
+				objectsMsg.WriteString("\n" + dep)
+			}
 			objects := objectsMsg.CloseAndGetString()
 			return pgerror.Newf(pgcode.DependentObjectsStillExist,
 				"role %s cannot be dropped because some objects depend on it%s",
@@ -362,6 +362,10 @@ func (p *planner) dropTableImpl(
 		droppedViews = append(droppedViews, qualifiedView.FQString())
 	}
 
+	if err := deleteRowLevelTTLSchedule(p.RunParams(ctx), tableDesc); err != nil {
+		return droppedViews, err
+	}
+
 	err := p.removeTableComments(ctx, tableDesc)
 	if err != nil {
 		return droppedViews, err
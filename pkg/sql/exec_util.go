@@ -51,6 +51,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/hydratedtables"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
 	"github.com/cockroachdb/cockroach/pkg/sql/contention"
+	"github.com/cockroachdb/cockroach/pkg/sql/costcalibration"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
@@ -62,6 +63,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/planbaselines"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -70,6 +72,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/stmtdiagnostics"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmthints"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
@@ -255,6 +258,18 @@ var zigzagJoinClusterMode = settings.RegisterBoolSetting(
 	true,
 )
 
+var adaptiveJoinsClusterMode = settings.RegisterBoolSetting(
+	"sql.defaults.adaptive_joins.enabled",
+	"default value for enable_adaptive_joins session setting; allows use of adaptive joins by default",
+	false,
+)
+
+var statementHintsClusterMode = settings.RegisterBoolSetting(
+	"sql.defaults.statement_hints.enabled",
+	"default value for enable_statement_hints session setting; applies the optimizer hints in statement comments and in system.statement_hints by default",
+	true,
+)
+
 var optDrivenFKCascadesClusterLimit = settings.RegisterIntSetting(
 	"sql.defaults.foreign_key_cascades_limit",
 	"default value for foreign_key_cascades_limit session setting; limits the number of cascading operations that run as part of a single query",
@@ -300,6 +315,15 @@ var optUseMultiColStatsClusterMode = settings.RegisterBoolSetting(
 	true,
 )
 
+// optUseForecastsClusterMode controls the cluster default for whether
+// statistics forecasted from the history of collected statistics are used by
+// the optimizer for cardinality estimation.
+var optUseForecastsClusterMode = settings.RegisterBoolSetting(
+	"sql.defaults.optimizer_use_forecasts.enabled",
+	"default value for optimizer_use_forecasts session setting; enables usage of forecasted stats in the optimizer by default",
+	true,
+)
+
 // localityOptimizedSearchMode controls the cluster default for the use of
 // locality optimized search. If enabled, the optimizer will try to plan scans
 // and lookup joins in which local nodes (i.e., nodes in the gateway region) are
@@ -389,6 +413,13 @@ var experimentalStreamReplicationEnabled = settings.RegisterBoolSetting(
 	false,
 )
 
+var multipleActivePortalsEnabledClusterMode = settings.RegisterBoolSetting(
+	"sql.defaults.multiple_active_portals.enabled",
+	"default value for multiple_active_portals_enabled session setting; "+
+		"allows suspended portals to be interleaved with other statements in a transaction",
+	false,
+)
+
 var stubCatalogTablesEnabledClusterValue = settings.RegisterBoolSetting(
 	`sql.defaults.stub_catalog_tables.enabled`,
 	`default value for stub_catalog_tables session setting`,
@@ -815,6 +846,7 @@ type ExecutorConfig struct {
 	EvalContextTestingKnobs       tree.EvalContextTestingKnobs
 	TenantTestingKnobs            *TenantTestingKnobs
 	BackupRestoreTestingKnobs     *BackupRestoreTestingKnobs
+	TTLTestingKnobs               *TTLTestingKnobs
 	// HistogramWindowInterval is (server.Config).HistogramWindowInterval.
 	HistogramWindowInterval time.Duration
 
@@ -831,6 +863,17 @@ type ExecutorConfig struct {
 	// StmtDiagnosticsRecorder deals with recording statement diagnostics.
 	StmtDiagnosticsRecorder *stmtdiagnostics.Registry
 
+	// PlanBaselines holds the plans pinned for statement fingerprints.
+	PlanBaselines *planbaselines.Registry
+
+	// StatementHints holds the optimizer hints stored for statement
+	// fingerprints.
+	StatementHints *stmthints.Registry
+
+	// CostCalibrator fits the cost model factors to the execution statistics
+	// of sampled statements.
+	CostCalibrator *costcalibration.Calibrator
+
 	ExternalIODirConfig base.ExternalIODirConfig
 
 	// HydratedTables is a node-level cache of table descriptors which utilize
@@ -1044,6 +1087,18 @@ var _ base.ModuleTestingKnobs = &BackupRestoreTestingKnobs{}
 // ModuleTestingKnobs implements the base.ModuleTestingKnobs interface.
 func (*BackupRestoreTestingKnobs) ModuleTestingKnobs() {}
 
+// TTLTestingKnobs contains knobs for the row-level TTL job.
+type TTLTestingKnobs struct {
+	// AOSTDuration overrides the AS OF SYSTEM TIME interval used when
+	// selecting the expired rows of a table.
+	AOSTDuration *time.Duration
+}
+
+var _ base.ModuleTestingKnobs = &TTLTestingKnobs{}
+
+// ModuleTestingKnobs implements the base.ModuleTestingKnobs interface.
+func (*TTLTestingKnobs) ModuleTestingKnobs() {}
+
 func shouldDistributeGivenRecAndMode(
 	rec distRecommendation, mode sessiondata.DistSQLExecMode,
 ) bool {
@@ -1212,6 +1267,8 @@ func checkResultType(typ *types.T) error {
 	case types.TimestampTZFamily:
 	case types.IntervalFamily:
 	case types.JsonFamily:
+	case types.TSQueryFamily:
+	case types.TSVectorFamily:
 	case types.UuidFamily:
 	case types.INetFamily:
 	case types.OidFamily:
@@ -2174,6 +2231,10 @@ func (m *sessionDataMutator) SetExtraFloatDigits(val int32) {
 	m.data.DataConversionConfig.ExtraFloatDigits = val
 }
 
+func (m *sessionDataMutator) SetTrigramSimilarityThreshold(val float64) {
+	m.data.TrigramSimilarityThreshold = val
+}
+
 func (m *sessionDataMutator) SetDatabase(dbName string) {
 	m.data.Database = dbName
 }
@@ -2226,6 +2287,14 @@ func (m *sessionDataMutator) SetZigzagJoinEnabled(val bool) {
 	m.data.ZigzagJoinEnabled = val
 }
 
+func (m *sessionDataMutator) SetAdaptiveJoinsEnabled(val bool) {
+	m.data.AdaptiveJoinsEnabled = val
+}
+
+func (m *sessionDataMutator) SetStatementHintsEnabled(val bool) {
+	m.data.StatementHintsEnabled = val
+}
+
 func (m *sessionDataMutator) SetExperimentalDistSQLPlanning(
 	val sessiondata.ExperimentalDistSQLPlanningMode,
 ) {
@@ -2264,6 +2333,10 @@ func (m *sessionDataMutator) SetOptimizerUseMultiColStats(val bool) {
 	m.data.OptimizerUseMultiColStats = val
 }
 
+func (m *sessionDataMutator) SetOptimizerUseForecasts(val bool) {
+	m.data.OptimizerUseForecasts = val
+}
+
 func (m *sessionDataMutator) SetLocalityOptimizedSearch(val bool) {
 	m.data.LocalityOptimizedSearch = val
 }
@@ -2371,6 +2444,10 @@ func (m *sessionDataMutator) SetStreamReplicationEnabled(val bool) {
 	m.data.EnableStreamReplication = val
 }
 
+func (m *sessionDataMutator) SetMultipleActivePortalsEnabled(val bool) {
+	m.data.MultipleActivePortalsEnabled = val
+}
+
 // RecordLatestSequenceValue records that value to which the session incremented
 // a sequence.
 func (m *sessionDataMutator) RecordLatestSequenceVal(seqID uint32, val int64) {
@@ -159,6 +159,9 @@ func (s *ComponentStats) formatStats(fn func(suffix string, value interface{}))
 	if s.Exec.MaxAllocatedDisk.HasValue() {
 		fn("max scratch disk allocated", humanize.IBytes(s.Exec.MaxAllocatedDisk.Value()))
 	}
+	if s.Exec.JoinStrategy != ExecStats_UNSET {
+		fn("join strategy", s.Exec.JoinStrategy.Description())
+	}
 
 	// Output stats.
 	if s.Output.NumBatches.HasValue() {
@@ -169,6 +172,21 @@ func (s *ComponentStats) formatStats(fn func(suffix string, value interface{}))
 	}
 }
 
+// Description returns the description of the join strategy shown in query
+// plans.
+func (s ExecStats_JoinStrategy) Description() string {
+	switch s {
+	case ExecStats_LOOKUP_JOIN:
+		return "lookup join"
+	case ExecStats_HASH_JOIN:
+		return "hash join"
+	case ExecStats_LOOKUP_THEN_HASH_JOIN:
+		return "lookup join, then hash join"
+	default:
+		return "unknown"
+	}
+}
+
 // Union creates a new ComponentStats that contains all statistics in either the
 // receiver (s) or the argument (other).
 // If a statistic is set in both, the one in the receiver (s) is preferred.
@@ -231,6 +249,9 @@ func (s *ComponentStats) Union(other *ComponentStats) *ComponentStats {
 	if !result.Exec.MaxAllocatedDisk.HasValue() {
 		result.Exec.MaxAllocatedDisk = other.Exec.MaxAllocatedDisk
 	}
+	if result.Exec.JoinStrategy == ExecStats_UNSET {
+		result.Exec.JoinStrategy = other.Exec.JoinStrategy
+	}
 
 	// Output stats.
 	if !result.Output.NumBatches.HasValue() {
@@ -75,12 +75,14 @@ KV bytes read: 80 B`,
 					ExecTime:         optional.MakeTimeValue(time.Second),
 					MaxAllocatedMem:  optional.MakeUint(1024),
 					MaxAllocatedDisk: optional.MakeUint(1024),
+					JoinStrategy:     ExecStats_HASH_JOIN,
 				},
 			},
 			expected: `
 execution time: 0µs
 max memory allocated: 0 B
-max scratch disk allocated: 0 B`,
+max scratch disk allocated: 0 B
+join strategy: hash join`,
 		},
 		{ // 5
 			stats: ComponentStats{
@@ -281,6 +283,7 @@ rows output: 100`,
 				Exec: ExecStats{
 					ExecTime:        optional.MakeTimeValue(time.Second),
 					MaxAllocatedMem: optional.MakeUint(1024 * 1000),
+					JoinStrategy:    ExecStats_LOOKUP_THEN_HASH_JOIN,
 				},
 				Output: OutputStats{
 					NumBatches: optional.MakeUint(10000),
@@ -306,6 +309,7 @@ KV bytes read: 12 KiB
 execution time: 1s
 max memory allocated: 1.0 KiB
 max scratch disk allocated: 1.0 KiB
+join strategy: lookup join, then hash join
 batches output: 10
 rows output: 100`,
 		},
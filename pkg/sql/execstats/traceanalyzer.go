@@ -24,6 +24,7 @@ import (
 type processorStats struct {
 	// TODO(radu): this field redundant with stats.Component.SQLInstanceID.
 	nodeID roachpb.NodeID
+	core   *execinfrapb.ProcessorCoreUnion
 	stats  *execinfrapb.ComponentStats
 }
 
@@ -82,8 +83,12 @@ func NewFlowsMetadata(flows map[roachpb.NodeID]*execinfrapb.FlowSpec) *FlowsMeta
 			)
 		}
 		a.flowStats[base.SQLInstanceID(nodeID)] = &flowStats{}
-		for _, proc := range flow.Processors {
-			a.processorStats[execinfrapb.ProcessorID(proc.ProcessorID)] = &processorStats{nodeID: nodeID}
+		for i := range flow.Processors {
+			proc := &flow.Processors[i]
+			a.processorStats[execinfrapb.ProcessorID(proc.ProcessorID)] = &processorStats{
+				nodeID: nodeID,
+				core:   &proc.Core,
+			}
 			for _, output := range proc.Output {
 				for _, stream := range output.Streams {
 					a.streamStats[stream.StreamID] = &streamStats{
@@ -127,6 +132,15 @@ type QueryLevelStats struct {
 	ContentionTime   time.Duration
 }
 
+// ProcessorStats are the execution statistics of a single processor of a
+// physical plan.
+type ProcessorStats struct {
+	// Core is the spec of the processor's core, which identifies the kind of
+	// work that the processor did.
+	Core  *execinfrapb.ProcessorCoreUnion
+	Stats *execinfrapb.ComponentStats
+}
+
 // Accumulate accumulates other's stats into the receiver.
 func (s *QueryLevelStats) Accumulate(other QueryLevelStats) {
 	s.NetworkBytesSent += other.NetworkBytesSent
@@ -392,6 +406,40 @@ func (a *TraceAnalyzer) GetQueryLevelStats() QueryLevelStats {
 	return a.queryLevelStats
 }
 
+// GetProcessorStats returns the stats of the processors that have stats in the
+// trace, in no particular order.
+func (a *TraceAnalyzer) GetProcessorStats() []ProcessorStats {
+	res := make([]ProcessorStats, 0, len(a.processorStats))
+	for _, stats := range a.processorStats {
+		if stats.stats == nil {
+			continue
+		}
+		res = append(res, ProcessorStats{Core: stats.core, Stats: stats.stats})
+	}
+	return res
+}
+
+// GetProcessorStats returns the stats of the processors of all the given flows
+// that have stats in the trace. Statistics that vary from run to run are never
+// made deterministic. If errors occur while processing the trace for some
+// flows, GetProcessorStats returns the combined errors along with the stats of
+// the other flows.
+func GetProcessorStats(
+	trace []tracingpb.RecordedSpan, flowsMetadata []*FlowsMetadata,
+) ([]ProcessorStats, error) {
+	var res []ProcessorStats
+	var errs error
+	for _, metadata := range flowsMetadata {
+		analyzer := NewTraceAnalyzer(metadata)
+		if err := analyzer.AddTrace(trace, false /* makeDeterministic */); err != nil {
+			errs = errors.CombineErrors(errs, errors.Wrap(err, "error analyzing trace statistics"))
+			continue
+		}
+		res = append(res, analyzer.GetProcessorStats()...)
+	}
+	return res, errs
+}
+
 // GetQueryLevelStats returns all the top-level stats in a QueryLevelStats
 // struct. GetQueryLevelStats tries to process as many stats as possible. If
 // errors occur while processing stats, GetQueryLevelStats returns the combined
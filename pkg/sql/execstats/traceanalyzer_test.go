@@ -188,6 +188,17 @@ func TestTraceAnalyzer(t *testing.T) {
 			// For tests, network messages is a synthetic value based on the number of
 			// network tuples. In this test 21 tuples flow over the network.
 			require.Equal(t, int64(21/2), queryLevelStats.NetworkMessages)
+
+			// All the rows are read by the table readers, one on each node.
+			var numTableReaders, tableReaderRowsRead int
+			for _, s := range tc.analyzer.GetProcessorStats() {
+				if s.Core.TableReader != nil {
+					numTableReaders++
+					tableReaderRowsRead += int(s.Stats.KV.TuplesRead.Value())
+				}
+			}
+			require.Equal(t, numNodes, numTableReaders)
+			require.Equal(t, 30, tableReaderRowsRead)
 		})
 	}
 }
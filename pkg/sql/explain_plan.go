@@ -114,6 +114,12 @@ func (e *explainPlanNode) startExec(params runParams) error {
 			}
 		}
 	}
+	if params.p.curPlan.flags.IsSet(planFlagUsedPlanBaseline) {
+		ob.AddTopLevelField("plan baseline", "used")
+	}
+	if hints := params.p.curPlan.statementHints; hints != nil {
+		ob.AddTopLevelField("statement hints", hints.String())
+	}
 
 	var rows []string
 	if e.options.Flags[tree.ExplainFlagJSON] {
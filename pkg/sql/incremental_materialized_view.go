@@ -0,0 +1,511 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// IncrementalViewPlan describes how a materialized view with
+// auto_refresh = incremental is kept up to date.
+//
+// The rows of the view are partitioned by a key: the grouping columns of a
+// view with aggregations, or the primary key columns of all its sources
+// otherwise. When a row of a source table changes, the keys of the view rows
+// derived from it before and after the change are selected, and the rows of
+// the view with these keys are recomputed and replaced.
+type IncrementalViewPlan struct {
+	// ViewQuery is the query of the view.
+	ViewQuery string
+	// ViewColumns are the names of the visible columns of the view, and
+	// RowIDColumn is the name of its hidden primary key column.
+	ViewColumns []string
+	RowIDColumn string
+	// From and Where are the FROM and WHERE clauses of the view query. Where
+	// is empty if the view query has no WHERE clause.
+	From  string
+	Where string
+	// KeyExprs are the expressions of the key over the sources, and
+	// KeyColumns are the corresponding columns of the view.
+	KeyExprs   []string
+	KeyColumns []string
+	// Sources are the tables the view reads from.
+	Sources []IncrementalViewSource
+}
+
+// IncrementalViewSource is a source table of a materialized view with
+// auto_refresh = incremental.
+type IncrementalViewSource struct {
+	Desc catalog.TableDescriptor
+	// PKExprs are the primary key columns of the table, qualified by its alias
+	// or name in the view query.
+	PKExprs []string
+	// PKTypes are the types of the primary key columns of the table.
+	PKTypes []*types.T
+}
+
+// LoadIncrementalViewPlan returns the IncrementalViewPlan of the given
+// materialized view, reading the descriptors of its sources in txn.
+func LoadIncrementalViewPlan(
+	ctx context.Context, txn *kv.Txn, codec keys.SQLCodec, viewDesc catalog.TableDescriptor,
+) (*IncrementalViewPlan, error) {
+	sources := make(map[string]catalog.TableDescriptor, len(viewDesc.GetDependsOn()))
+	for _, id := range viewDesc.GetDependsOn() {
+		desc, err := catalogkv.MustGetTableDescByID(ctx, txn, codec, id)
+		if err != nil {
+			return nil, err
+		}
+		dbDesc, err := catalogkv.MustGetDatabaseDescByID(ctx, txn, codec, desc.GetParentID())
+		if err != nil {
+			return nil, err
+		}
+		scName, err := resolver.ResolveSchemaNameByID(
+			ctx, txn, codec, desc.GetParentID(), desc.GetParentSchemaID(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		tn := tree.MakeTableNameWithSchema(
+			tree.Name(dbDesc.GetName()), tree.Name(scName), tree.Name(desc.GetName()),
+		)
+		sources[tn.FQString()] = desc
+	}
+	return makeIncrementalViewPlan(viewDesc, sources)
+}
+
+// setUpIncrementalRefresh checks that the materialized view being created can
+// be refreshed incrementally, and creates the job refreshing it.
+func (n *createViewNode) setUpIncrementalRefresh(params runParams, desc *tabledesc.Mutable) error {
+	execCfg := params.ExecCfg()
+	if !execCfg.Settings.Version.IsActive(params.ctx, clusterversion.IncrementalMaterializedViews) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"all nodes are not the correct version to use auto_refresh = incremental")
+	}
+	// The view is refreshed from the rangefeeds of its sources.
+	if !kvserver.RangefeedEnabled.Get(&execCfg.Settings.SV) {
+		return pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+			"auto_refresh = incremental requires the kv.rangefeed.enabled setting")
+	}
+
+	sources := make(map[string]catalog.TableDescriptor, len(n.planDeps))
+	for _, dep := range n.planDeps {
+		tn, err := params.p.getQualifiedTableName(params.ctx, dep.desc)
+		if err != nil {
+			return err
+		}
+		sources[tn.FQString()] = dep.desc
+	}
+	if _, err := makeIncrementalViewPlan(desc, sources); err != nil {
+		return err
+	}
+
+	record := jobs.Record{
+		Description:   fmt.Sprintf("incremental refresh of materialized view %s", n.viewName.FQString()),
+		Username:      params.p.User(),
+		DescriptorIDs: descpb.IDs{desc.ID},
+		Details:       jobspb.IncrementalMaterializedViewDetails{ViewID: desc.ID},
+		Progress:      jobspb.IncrementalMaterializedViewProgress{},
+	}
+	// The job is claimed by this node and started as soon as the view is
+	// created, rather than waiting for the adoption loop to pick it up. Unlike
+	// jobs queued by schema changes, the statement does not wait for it.
+	jobID := execCfg.JobRegistry.MakeJobID()
+	if _, err := execCfg.JobRegistry.CreateJobWithTxn(
+		params.ctx, record, jobID, params.p.txn,
+	); err != nil {
+		return err
+	}
+	params.p.txn.AddCommitTrigger(func(ctx context.Context) {
+		if err := execCfg.JobRegistry.NotifyToAdoptJobs(ctx); err != nil {
+			log.Warningf(ctx, "failed to notify job registry: %v", err)
+		}
+	})
+	desc.IncrementalRefresh.JobID = int64(jobID)
+	return nil
+}
+
+func newIncrementalViewUnsupportedError(format string, args ...interface{}) error {
+	return errors.WithHint(
+		pgerror.Newf(pgcode.FeatureNotSupported,
+			"auto_refresh = incremental is not supported for this view: "+format, args...),
+		"incremental refresh supports views selecting from tables, optionally "+
+			"joined with inner joins and grouped with GROUP BY",
+	)
+}
+
+// makeIncrementalViewPlan analyzes the query of a materialized view with
+// auto_refresh = incremental. sources maps the fully qualified names of the
+// relations the view depends on to their descriptors.
+func makeIncrementalViewPlan(
+	viewDesc catalog.TableDescriptor, sources map[string]catalog.TableDescriptor,
+) (*IncrementalViewPlan, error) {
+	stmt, err := parser.ParseOne(viewDesc.GetViewQuery())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse view query")
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, newIncrementalViewUnsupportedError("unsupported statement")
+	}
+	for {
+		if sel.With != nil {
+			return nil, newIncrementalViewUnsupportedError("WITH clauses are not supported")
+		}
+		if sel.OrderBy != nil || sel.Limit != nil {
+			return nil, newIncrementalViewUnsupportedError("ORDER BY and LIMIT are not supported")
+		}
+		if sel.Locking != nil {
+			return nil, newIncrementalViewUnsupportedError("locking clauses are not supported")
+		}
+		paren, ok := sel.Select.(*tree.ParenSelect)
+		if !ok {
+			break
+		}
+		sel = paren.Select
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok {
+		return nil, newIncrementalViewUnsupportedError("set operations and VALUES are not supported")
+	}
+	if clause.Distinct || clause.DistinctOn != nil {
+		return nil, newIncrementalViewUnsupportedError("DISTINCT is not supported")
+	}
+	if clause.Window != nil {
+		return nil, newIncrementalViewUnsupportedError("window functions are not supported")
+	}
+	if clause.From.AsOf.Expr != nil {
+		return nil, newIncrementalViewUnsupportedError("AS OF SYSTEM TIME is not supported")
+	}
+
+	a := incrementalViewAnalyzer{sourcesByName: sources}
+	for _, t := range clause.From.Tables {
+		if err := a.addTableExpr(t); err != nil {
+			return nil, err
+		}
+	}
+	if len(a.sources) == 0 {
+		return nil, newIncrementalViewUnsupportedError("the view must select from a table")
+	}
+
+	// Check the expressions of the view query, and find out whether the view
+	// has aggregations.
+	v := incrementalViewExprVisitor{}
+	for _, e := range clause.Exprs {
+		tree.WalkExprConst(&v, e.Expr)
+	}
+	if clause.Where != nil {
+		tree.WalkExprConst(&v, clause.Where.Expr)
+	}
+	if clause.Having != nil {
+		tree.WalkExprConst(&v, clause.Having.Expr)
+	}
+	for _, e := range clause.GroupBy {
+		tree.WalkExprConst(&v, e)
+	}
+	if v.err != nil {
+		return nil, v.err
+	}
+
+	plan := &IncrementalViewPlan{
+		ViewQuery: viewDesc.GetViewQuery(),
+		From:      tree.AsStringWithFlags(&clause.From.Tables, tree.FmtParsable),
+	}
+	if clause.Where != nil {
+		plan.Where = tree.AsStringWithFlags(clause.Where.Expr, tree.FmtParsable)
+	}
+	for _, col := range viewDesc.VisibleColumns() {
+		plan.ViewColumns = append(plan.ViewColumns, col.GetName())
+	}
+	if len(plan.ViewColumns) != len(clause.Exprs) {
+		return nil, errors.AssertionFailedf(
+			"view has %d columns but its query has %d", len(plan.ViewColumns), len(clause.Exprs))
+	}
+	plan.RowIDColumn = viewDesc.GetPrimaryIndex().GetColumnName(0)
+
+	for i := range a.sources {
+		src := &a.sources[i]
+		primaryIndex := src.desc.GetPrimaryIndex()
+		s := IncrementalViewSource{Desc: src.desc}
+		for j := 0; j < primaryIndex.NumColumns(); j++ {
+			col, err := src.desc.FindColumnWithID(primaryIndex.GetColumnID(j))
+			if err != nil {
+				return nil, err
+			}
+			if col.GetType().Family() == types.CollatedStringFamily {
+				return nil, newIncrementalViewUnsupportedError(
+					"the primary key of %s has a collated string column", src.desc.GetName())
+			}
+			s.PKExprs = append(s.PKExprs, src.qualify(col.GetName()))
+			s.PKTypes = append(s.PKTypes, col.GetType())
+		}
+		plan.Sources = append(plan.Sources, s)
+	}
+
+	// findViewColumn returns the view column selecting the given column of a
+	// source as is, if any.
+	findViewColumn := func(srcIdx int, colName tree.Name) (string, bool) {
+		for i, e := range clause.Exprs {
+			if s, c, ok := a.resolveColumn(e.Expr); ok && s == srcIdx && c == colName {
+				return plan.ViewColumns[i], true
+			}
+		}
+		return "", false
+	}
+
+	if len(clause.GroupBy) > 0 || v.aggregated {
+		if len(clause.GroupBy) == 0 {
+			return nil, newIncrementalViewUnsupportedError("aggregations require a GROUP BY clause")
+		}
+		// The key of the view is given by its grouping columns, each of which
+		// must be selected by the view.
+		for _, e := range clause.GroupBy {
+			var keyExpr, keyCol string
+			if srcIdx, colName, ok := a.resolveColumn(e); ok {
+				keyExpr = a.sources[srcIdx].qualify(string(colName))
+				if keyCol, ok = findViewColumn(srcIdx, colName); !ok {
+					return nil, newIncrementalViewUnsupportedError(
+						"grouping column %s must be selected", tree.AsString(e))
+				}
+			} else if i, ok := groupByTarget(clause, e); ok {
+				srcIdx, colName, ok := a.resolveColumn(clause.Exprs[i].Expr)
+				if !ok {
+					return nil, newIncrementalViewUnsupportedError(
+						"grouping expression %s must be a column", tree.AsString(e))
+				}
+				keyExpr = a.sources[srcIdx].qualify(string(colName))
+				keyCol = plan.ViewColumns[i]
+			} else {
+				return nil, newIncrementalViewUnsupportedError(
+					"grouping expression %s must be a column", tree.AsString(e))
+			}
+			plan.KeyExprs = append(plan.KeyExprs, keyExpr)
+			plan.KeyColumns = append(plan.KeyColumns, keyCol)
+		}
+	} else {
+		// The key of the view is given by the primary key columns of all its
+		// sources, each of which must be selected by the view.
+		for i := range a.sources {
+			src := &a.sources[i]
+			primaryIndex := src.desc.GetPrimaryIndex()
+			for j := 0; j < primaryIndex.NumColumns(); j++ {
+				colName := tree.Name(primaryIndex.GetColumnName(j))
+				keyCol, ok := findViewColumn(i, colName)
+				if !ok {
+					return nil, newIncrementalViewUnsupportedError(
+						"the primary key columns of %s must be selected", src.desc.GetName())
+				}
+				plan.KeyExprs = append(plan.KeyExprs, src.qualify(string(colName)))
+				plan.KeyColumns = append(plan.KeyColumns, keyCol)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// groupByTarget returns the index of the select expression referenced by a
+// GROUP BY expression which is an ordinal or the alias of a select
+// expression.
+func groupByTarget(clause *tree.SelectClause, e tree.Expr) (int, bool) {
+	switch t := e.(type) {
+	case *tree.NumVal:
+		i, err := t.AsInt64()
+		if err != nil || i < 1 || int(i) > len(clause.Exprs) {
+			return 0, false
+		}
+		return int(i) - 1, true
+	case *tree.UnresolvedName:
+		if t.NumParts != 1 || t.Star {
+			return 0, false
+		}
+		for i, se := range clause.Exprs {
+			if se.As != "" && string(se.As) == t.Parts[0] {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// incrementalViewAnalyzer collects the sources of the FROM clause of a
+// materialized view with auto_refresh = incremental.
+type incrementalViewAnalyzer struct {
+	// sources maps fully qualified names to descriptors; it is used to
+	// resolve the table names of the FROM clause.
+	sourcesByName map[string]catalog.TableDescriptor
+	sources       []incrementalViewAnalyzerSource
+}
+
+type incrementalViewAnalyzerSource struct {
+	desc  catalog.TableDescriptor
+	name  tree.TableName
+	alias tree.Name
+}
+
+// qualify returns the given column of the source qualified by the alias or
+// name of the source in the view query.
+func (s *incrementalViewAnalyzerSource) qualify(colName string) string {
+	if s.alias != "" {
+		return fmt.Sprintf("%s.%s", s.alias.String(), tree.NameString(colName))
+	}
+	return fmt.Sprintf("%s.%s", tree.AsStringWithFlags(&s.name, tree.FmtParsable), tree.NameString(colName))
+}
+
+func (a *incrementalViewAnalyzer) addTableExpr(expr tree.TableExpr) error {
+	switch t := expr.(type) {
+	case *tree.AliasedTableExpr:
+		if t.Ordinality || t.Lateral || len(t.As.Cols) > 0 {
+			return newIncrementalViewUnsupportedError("%s is not supported", tree.AsString(t))
+		}
+		tn, ok := t.Expr.(*tree.TableName)
+		if !ok {
+			return newIncrementalViewUnsupportedError("subqueries are not supported")
+		}
+		desc, ok := a.sourcesByName[tn.FQString()]
+		if !ok || !desc.IsTable() || desc.IsVirtualTable() {
+			return newIncrementalViewUnsupportedError("%s is not a table", tn.FQString())
+		}
+		if desc.GetPrimaryIndex().NumInterleaveAncestors() > 0 {
+			return newIncrementalViewUnsupportedError(
+				"interleaved table %s is not supported", tn.FQString())
+		}
+		a.sources = append(a.sources, incrementalViewAnalyzerSource{
+			desc: desc, name: *tn, alias: t.As.Alias,
+		})
+		return nil
+
+	case *tree.JoinTableExpr:
+		if t.JoinType != "" && t.JoinType != tree.AstInner && t.JoinType != tree.AstCross {
+			return newIncrementalViewUnsupportedError("%s joins are not supported", t.JoinType)
+		}
+		if err := a.addTableExpr(t.Left); err != nil {
+			return err
+		}
+		return a.addTableExpr(t.Right)
+
+	case *tree.ParenTableExpr:
+		return a.addTableExpr(t.Expr)
+	}
+	return newIncrementalViewUnsupportedError("%s is not supported", tree.AsString(expr))
+}
+
+// resolveColumn returns the source and name of the column referenced by the
+// given expression, if it is a column reference.
+func (a *incrementalViewAnalyzer) resolveColumn(expr tree.Expr) (int, tree.Name, bool) {
+	for {
+		paren, ok := expr.(*tree.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = paren.Expr
+	}
+	var ci *tree.ColumnItem
+	switch t := expr.(type) {
+	case *tree.UnresolvedName:
+		vn, err := t.NormalizeVarName()
+		if err != nil {
+			return 0, "", false
+		}
+		var ok bool
+		if ci, ok = vn.(*tree.ColumnItem); !ok {
+			return 0, "", false
+		}
+	case *tree.ColumnItem:
+		ci = t
+	default:
+		return 0, "", false
+	}
+	for i := range a.sources {
+		src := &a.sources[i]
+		if ci.TableName != nil {
+			tn := ci.TableName.ToTableName()
+			if src.alias != "" {
+				if tn.ExplicitSchema || tn.ObjectName != src.alias {
+					continue
+				}
+			} else if tn.ObjectName != src.name.ObjectName ||
+				(tn.ExplicitSchema && tn.SchemaName != src.name.SchemaName) ||
+				(tn.ExplicitCatalog && tn.CatalogName != src.name.CatalogName) {
+				continue
+			}
+		}
+		// An unqualified column may be present in several sources only if they
+		// are joined with USING or NATURAL, in which case their values are equal
+		// and any of the sources can be used.
+		if _, err := src.desc.FindColumnWithName(ci.ColumnName); err == nil {
+			return i, ci.ColumnName, true
+		}
+	}
+	return 0, "", false
+}
+
+// incrementalViewExprVisitor checks the expressions of the query of a
+// materialized view with auto_refresh = incremental, and finds out whether
+// the query has aggregations.
+type incrementalViewExprVisitor struct {
+	aggregated bool
+	err        error
+}
+
+var _ tree.Visitor = &incrementalViewExprVisitor{}
+
+// VisitPre is part of the tree.Visitor interface.
+func (v *incrementalViewExprVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case *tree.Subquery:
+		v.err = newIncrementalViewUnsupportedError("subqueries are not supported")
+		return false, expr
+	case *tree.FuncExpr:
+		if t.IsWindowFunctionApplication() {
+			v.err = newIncrementalViewUnsupportedError("window functions are not supported")
+			return false, expr
+		}
+		fd, err := t.Func.Resolve(sessiondata.DefaultSearchPath)
+		if err != nil {
+			v.err = err
+			return false, expr
+		}
+		switch fd.Class {
+		case tree.AggregateClass:
+			v.aggregated = true
+		case tree.GeneratorClass:
+			v.err = newIncrementalViewUnsupportedError("set-returning functions are not supported")
+			return false, expr
+		}
+	}
+	return true, expr
+}
+
+// VisitPost is part of the tree.Visitor interface.
+func (*incrementalViewExprVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
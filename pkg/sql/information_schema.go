@@ -401,6 +401,9 @@ https://www.postgresql.org/docs/9.5/infoschema-columns.html`,
 			dbNameStr := tree.NewDString(db.GetName())
 			scNameStr := tree.NewDString(scName)
 			for _, column := range table.PublicColumns() {
+				if column.IsInaccessible() {
+					continue
+				}
 				collationCatalog := tree.DNull
 				collationSchema := tree.DNull
 				collationName := tree.DNull
@@ -1427,6 +1430,8 @@ CREATE TABLE information_schema.table_constraints (
 				tbNameStr := tree.NewDString(table.GetName())
 
 				for conName, c := range conInfo {
+					isDeferrable := c.Deferrability() != descpb.ConstraintDeferrability_NotDeferrable
+					initiallyDeferred := c.Deferrability() == descpb.ConstraintDeferrability_InitiallyDeferred
 					if err := addRow(
 						dbNameStr,                       // constraint_catalog
 						scNameStr,                       // constraint_schema
@@ -1435,8 +1440,8 @@ CREATE TABLE information_schema.table_constraints (
 						scNameStr,                       // table_schema
 						tbNameStr,                       // table_name
 						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						yesOrNoDatum(isDeferrable),      // is_deferrable
+						yesOrNoDatum(initiallyDeferred), // initially_deferred
 					); err != nil {
 						return err
 					}
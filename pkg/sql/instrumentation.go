@@ -277,6 +277,15 @@ func (ih *instrumentationHelper) Finish(
 		}
 	}
 
+	if cfg.CostCalibrator != nil && cfg.CostCalibrator.Enabled() {
+		processorStats, err := execstats.GetProcessorStats(trace, flowsMetadata)
+		if err != nil {
+			log.VInfof(ctx, 1, "error getting processor stats for statement: %s: %+v", ih.fingerprint, err)
+		} else {
+			cfg.CostCalibrator.Record(processorStats)
+		}
+	}
+
 	var bundle diagnosticsBundle
 	if ih.collectBundle {
 		ie := p.extendedEvalCtx.InternalExecutor.(*InternalExecutor)
@@ -507,6 +516,17 @@ func (m execNodeTraceMetadata) associateNodeWithComponents(
 	m[node] = components
 }
 
+// appendJoinStrategy appends the given join strategy to strategies, unless it
+// is already present.
+func appendJoinStrategy(strategies []string, strategy string) []string {
+	for _, s := range strategies {
+		if s == strategy {
+			return strategies
+		}
+	}
+	return append(strategies, strategy)
+}
+
 // annotateExplain aggregates the statistics in the trace and annotates
 // explain.Nodes with execution stats.
 func (m execNodeTraceMetadata) annotateExplain(
@@ -535,6 +555,11 @@ func (m execNodeTraceMetadata) annotateExplain(
 				nodeStats.KVBytesRead.MaybeAdd(stats.KV.BytesRead)
 				nodeStats.KVRowsRead.MaybeAdd(stats.KV.TuplesRead)
 				nodeStats.VectorizedBatchCount.MaybeAdd(stats.Output.NumBatches)
+				if stats.Exec.JoinStrategy != execinfrapb.ExecStats_UNSET {
+					nodeStats.JoinStrategies = appendJoinStrategy(
+						nodeStats.JoinStrategies, stats.Exec.JoinStrategy.Description(),
+					)
+				}
 			}
 			// If we didn't get statistics for all processors, we don't show the
 			// incomplete results. In the future, we may consider an incomplete flag
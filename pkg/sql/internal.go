@@ -568,6 +568,9 @@ func applyOverrides(o sessiondata.InternalExecutorOverride, sd *sessiondata.Sess
 	if o.DatabaseIDToTempSchemaID != nil {
 		sd.DatabaseIDToTempSchemaID = o.DatabaseIDToTempSchemaID
 	}
+	if o.AllowMaterializedViewMutations {
+		sd.AllowMaterializedViewMutations = true
+	}
 }
 
 func (ie *InternalExecutor) maybeRootSessionDataOverride(
@@ -54,6 +54,15 @@ type lookupJoinNode struct {
 	isSecondJoinInPairedJoiner bool
 
 	reqOrdering ReqOrdering
+
+	// adaptiveThreshold, if non-zero, is the number of input rows above which
+	// the remaining input rows are joined using a hash join against a full scan
+	// of the index instead of being looked up.
+	adaptiveThreshold uint64
+
+	// adaptiveBufferInput is true when the adaptive join buffers the input rows
+	// until adaptiveThreshold is crossed before choosing the join strategy.
+	adaptiveBufferInput bool
 }
 
 func (lj *lookupJoinNode) startExec(params runParams) error {
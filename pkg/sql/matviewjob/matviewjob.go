@@ -0,0 +1,452 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package matviewjob implements the job which keeps the materialized views
+// created with auto_refresh = incremental up to date.
+package matviewjob
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// maxKeysPerQuery is the maximum number of keys selected by each query of the
+// job.
+const maxKeysPerQuery = 100
+
+// highWaterInterval is the maximum interval at which the high-water mark of
+// the job is persisted when its sources do not change. The job also checks
+// whether its view has been dropped at this interval.
+const highWaterInterval = 10 * time.Second
+
+// errViewDropped is returned when the view refreshed by the job has been
+// dropped, in which case the job is done.
+var errViewDropped = errors.New("materialized view dropped")
+
+type incrementalMaterializedViewResumer struct {
+	job *jobs.Job
+	st  *cluster.Settings
+}
+
+var _ jobs.Resumer = (*incrementalMaterializedViewResumer)(nil)
+
+// feedEvent is a change to a row of a source table of the view, or a
+// checkpoint of the rangefeed of the source if key is nil.
+type feedEvent struct {
+	source int
+	key    roachpb.Key
+	ts     hlc.Timestamp
+}
+
+// change is a change to the row of a source table with the given primary key.
+type change struct {
+	source int
+	pk     tree.Datums
+	ts     hlc.Timestamp
+}
+
+// Resume is part of the jobs.Resumer interface.
+func (r incrementalMaterializedViewResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.IncrementalMaterializedViewDetails)
+
+	// Wait for the view to be backfilled.
+	var plan *sql.IncrementalViewPlan
+	var startTS hlc.Timestamp
+	for opts := retry.StartWithCtx(ctx, retry.Options{MaxBackoff: 10 * time.Second}); ; {
+		var desc catalog.TableDescriptor
+		if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) (err error) {
+			desc, err = loadView(ctx, txn, execCfg, details.ViewID)
+			if err != nil || !desc.Public() {
+				return err
+			}
+			plan, err = sql.LoadIncrementalViewPlan(ctx, txn, execCfg.Codec, desc)
+			return err
+		}); err != nil {
+			if errors.Is(err, errViewDropped) {
+				return nil
+			}
+			return err
+		}
+		if desc.Public() {
+			startTS = desc.GetCreateAsOfTime()
+			break
+		}
+		if !opts.Next() {
+			return ctx.Err()
+		}
+	}
+	progress := r.job.Progress()
+	if hw := progress.GetHighWater(); hw != nil && startTS.Less(*hw) {
+		startTS = *hw
+	}
+
+	// Watch the changes to the primary index of each source.
+	events := make(chan feedEvent, 1024)
+	sendEvent := func(ctx context.Context, ev feedEvent) {
+		select {
+		case events <- ev:
+		case <-ctx.Done():
+		}
+	}
+	for i := range plan.Sources {
+		i := i
+		src := plan.Sources[i].Desc
+		rf, err := execCfg.RangeFeedFactory.RangeFeed(
+			ctx,
+			fmt.Sprintf("matview-%d-source-%d", details.ViewID, src.GetID()),
+			src.PrimaryIndexSpan(execCfg.Codec),
+			startTS,
+			func(ctx context.Context, value *roachpb.RangeFeedValue) {
+				sendEvent(ctx, feedEvent{source: i, key: value.Key, ts: value.Value.Timestamp})
+			},
+			rangefeed.WithOnFrontierAdvance(func(ctx context.Context, ts hlc.Timestamp) {
+				sendEvent(ctx, feedEvent{source: i, ts: ts})
+			}),
+		)
+		if err != nil {
+			return err
+		}
+		defer rf.Close()
+	}
+
+	// Apply the changes to the sources once the rangefeeds of all of them
+	// have advanced past them.
+	frontiers := make([]hlc.Timestamp, len(plan.Sources))
+	applied := startTS
+	lastApplied := timeutil.Now()
+	var pending []change
+	var alloc rowenc.DatumAlloc
+	for {
+		var ev feedEvent
+		select {
+		case ev = <-events:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if ev.key != nil {
+			pk, ok, err := decodePrimaryKey(execCfg, &plan.Sources[ev.source], ev.key, &alloc)
+			if err != nil {
+				return err
+			}
+			if ok {
+				pending = append(pending, change{source: ev.source, pk: pk, ts: ev.ts})
+			}
+			continue
+		}
+		frontiers[ev.source].Forward(ev.ts)
+		frontier := frontiers[0]
+		for _, ts := range frontiers[1:] {
+			if ts.Less(frontier) {
+				frontier = ts
+			}
+		}
+		if frontier.LessEq(applied) {
+			continue
+		}
+		var ready, rest []change
+		for _, c := range pending {
+			if c.ts.LessEq(frontier) {
+				ready = append(ready, c)
+			} else {
+				rest = append(rest, c)
+			}
+		}
+		if len(ready) == 0 && timeutil.Since(lastApplied) < highWaterInterval {
+			continue
+		}
+		if err := r.applyChanges(ctx, execCfg, details.ViewID, plan, ready, frontier); err != nil {
+			if errors.Is(err, errViewDropped) {
+				log.Infof(ctx, "materialized view %d was dropped, stopping job %d",
+					details.ViewID, r.job.ID())
+				return nil
+			}
+			return err
+		}
+		pending = rest
+		applied = frontier
+		lastApplied = timeutil.Now()
+	}
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r incrementalMaterializedViewResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{},
+) error {
+	return nil
+}
+
+// loadView returns the descriptor of the view refreshed by the job, or
+// errViewDropped if it has been dropped.
+func loadView(
+	ctx context.Context, txn *kv.Txn, execCfg *sql.ExecutorConfig, viewID descpb.ID,
+) (catalog.TableDescriptor, error) {
+	desc, err := catalogkv.MustGetTableDescByID(ctx, txn, execCfg.Codec, viewID)
+	if err != nil {
+		if errors.Is(err, catalog.ErrDescriptorNotFound) {
+			return nil, errViewDropped
+		}
+		return nil, err
+	}
+	if desc.Dropped() || desc.GetIncrementalRefresh() == nil {
+		return nil, errViewDropped
+	}
+	return desc, nil
+}
+
+// decodePrimaryKey decodes the primary key of the row of a source table
+// stored in the given key. It returns false if the key is not a key of the
+// primary index of the source, which is the case of the rows of its
+// interleaved children.
+func decodePrimaryKey(
+	execCfg *sql.ExecutorConfig,
+	src *sql.IncrementalViewSource,
+	key roachpb.Key,
+	alloc *rowenc.DatumAlloc,
+) (pk tree.Datums, ok bool, err error) {
+	key, err = execCfg.Codec.StripTenantPrefix(key)
+	if err != nil {
+		return nil, false, err
+	}
+	rest, tableID, indexID, err := rowenc.DecodePartialTableIDIndexID(key)
+	if err != nil {
+		return nil, false, err
+	}
+	if tableID != src.Desc.GetID() || indexID != src.Desc.GetPrimaryIndexID() {
+		return nil, false, nil
+	}
+	primaryIndex := src.Desc.GetPrimaryIndex()
+	dirs := make([]descpb.IndexDescriptor_Direction, primaryIndex.NumColumns())
+	for i := range dirs {
+		dirs[i] = primaryIndex.GetColumnDirection(i)
+	}
+	vals := make([]rowenc.EncDatum, len(src.PKTypes))
+	rest, _, err = rowenc.DecodeKeyVals(src.PKTypes, vals, dirs, rest)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, interleaved := encoding.DecodeIfInterleavedSentinel(rest); interleaved {
+		return nil, false, nil
+	}
+	pk = make(tree.Datums, len(vals))
+	for i := range vals {
+		if err := vals[i].EnsureDecoded(src.PKTypes[i], alloc); err != nil {
+			return nil, false, err
+		}
+		pk[i] = vals[i].Datum
+	}
+	return pk, true, nil
+}
+
+// applyChanges recomputes the rows of the view derived from the rows of its
+// sources which have changed, and persists the high-water mark of the job in
+// the same transaction.
+func (r incrementalMaterializedViewResumer) applyChanges(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	viewID descpb.ID,
+	plan *sql.IncrementalViewPlan,
+	changes []change,
+	highWater hlc.Timestamp,
+) error {
+	ie := execCfg.InternalExecutor
+	override := sessiondata.InternalExecutorOverride{
+		User:                           security.RootUserName(),
+		AllowMaterializedViewMutations: true,
+	}
+	q := makeQueryBuilder(viewID, plan)
+
+	// Select the keys of the view rows derived from the changed rows before
+	// each change.
+	type sourceAndTime struct {
+		source int
+		ts     hlc.Timestamp
+	}
+	before := make(map[sourceAndTime]*keySet)
+	var order []sourceAndTime
+	for _, c := range changes {
+		k := sourceAndTime{source: c.source, ts: c.ts}
+		if _, ok := before[k]; !ok {
+			before[k] = &keySet{}
+			order = append(order, k)
+		}
+		before[k].add(c.pk)
+	}
+	var oldKeys keySet
+	for _, k := range order {
+		pks := before[k].keys
+		for len(pks) > 0 {
+			batch := pks
+			if len(batch) > maxKeysPerQuery {
+				batch = batch[:maxKeysPerQuery]
+			}
+			pks = pks[len(batch):]
+			query, args := q.changedKeysQuery(k.source, batch, k.ts.Prev())
+			rows, err := ie.QueryBufferedEx(ctx, "matview-select-keys", nil /* txn */, override, query, args...)
+			if err != nil {
+				return errors.Wrap(err, "selecting the keys of changed rows")
+			}
+			oldKeys.add(rows...)
+		}
+	}
+
+	return execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		if _, err := loadView(ctx, txn, execCfg, viewID); err != nil {
+			return err
+		}
+		for i := range plan.Sources {
+			src := plan.Sources[i].Desc
+			desc, err := catalogkv.MustGetTableDescByID(ctx, txn, execCfg.Codec, src.GetID())
+			if err != nil {
+				return err
+			}
+			if desc.GetPrimaryIndexID() != src.GetPrimaryIndexID() {
+				return jobs.NewRetryJobError(fmt.Sprintf(
+					"primary index of table %s changed", desc.GetName()))
+			}
+		}
+
+		// Select the keys of the view rows derived from the changed rows after
+		// the changes.
+		keys := oldKeys.copy()
+		pksBySource := make([]keySet, len(plan.Sources))
+		for _, c := range changes {
+			pksBySource[c.source].add(c.pk)
+		}
+		for i := range pksBySource {
+			pks := pksBySource[i].keys
+			for len(pks) > 0 {
+				batch := pks
+				if len(batch) > maxKeysPerQuery {
+					batch = batch[:maxKeysPerQuery]
+				}
+				pks = pks[len(batch):]
+				query, args := q.changedKeysQuery(i, batch, hlc.Timestamp{})
+				rows, err := ie.QueryBufferedEx(ctx, "matview-select-keys", txn, override, query, args...)
+				if err != nil {
+					return errors.Wrap(err, "selecting the keys of changed rows")
+				}
+				keys.add(rows...)
+			}
+		}
+
+		// Replace the rows of the view with these keys.
+		for allKeys := keys.keys; len(allKeys) > 0; {
+			batch := allKeys
+			if len(batch) > maxKeysPerQuery {
+				batch = batch[:maxKeysPerQuery]
+			}
+			allKeys = allKeys[len(batch):]
+			if err := r.replaceRows(ctx, txn, ie, override, q, batch); err != nil {
+				return err
+			}
+		}
+
+		return r.job.Update(ctx, txn, func(_ *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+			return jobs.UpdateHighwaterProgressed(highWater, md, ju)
+		})
+	})
+}
+
+// replaceRows recomputes the rows of the view with the given keys, and
+// deletes and inserts the rows of the view which differ.
+func (r incrementalMaterializedViewResumer) replaceRows(
+	ctx context.Context,
+	txn *kv.Txn,
+	ie *sql.InternalExecutor,
+	override sessiondata.InternalExecutorOverride,
+	q queryBuilder,
+	keys []tree.Datums,
+) error {
+	query, args := q.viewRowsQuery(keys)
+	existing, err := ie.QueryBufferedEx(ctx, "matview-select-rows", txn, override, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "selecting view rows")
+	}
+	query, args = q.recomputeQuery(keys)
+	fresh, err := ie.QueryBufferedEx(ctx, "matview-recompute-rows", txn, override, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "recomputing view rows")
+	}
+
+	// Compute the multiset difference between the existing and the fresh
+	// rows, leaving the rows present in both untouched. The first column of
+	// the existing rows is their row ID.
+	unmatched := make(map[string]int, len(fresh))
+	for _, row := range fresh {
+		unmatched[rowKey(row)]++
+	}
+	var toDelete tree.Datums
+	for _, row := range existing {
+		k := rowKey(row[1:])
+		if unmatched[k] > 0 {
+			unmatched[k]--
+			continue
+		}
+		toDelete = append(toDelete, row[0])
+	}
+	var toInsert []tree.Datums
+	for _, row := range fresh {
+		k := rowKey(row)
+		if unmatched[k] > 0 {
+			unmatched[k]--
+			toInsert = append(toInsert, row)
+		}
+	}
+
+	if len(toDelete) > 0 {
+		query, args := q.deleteQuery(toDelete)
+		if _, err := ie.ExecEx(ctx, "matview-delete-rows", txn, override, query, args...); err != nil {
+			return errors.Wrap(err, "deleting view rows")
+		}
+	}
+	if len(toInsert) > 0 {
+		query, args := q.insertQuery(toInsert)
+		if _, err := ie.ExecEx(ctx, "matview-insert-rows", txn, override, query, args...); err != nil {
+			return errors.Wrap(err, "inserting view rows")
+		}
+	}
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeIncrementalMaterializedView,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &incrementalMaterializedViewResumer{
+				job: job,
+				st:  settings,
+			}
+		},
+	)
+}
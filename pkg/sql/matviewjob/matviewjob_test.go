@@ -0,0 +1,106 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package matviewjob_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncrementalMaterializedView(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	defer jobs.TestingSetAdoptAndCancelIntervals(100*time.Millisecond, 100*time.Millisecond)()
+
+	ctx := context.Background()
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	runner.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	runner.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms'`)
+	runner.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.side_transport_interval = '50ms'`)
+	runner.Exec(t, `SET CLUSTER SETTING kv.rangefeed.closed_timestamp_refresh_interval = '100ms'`)
+
+	runner.Exec(t, `CREATE DATABASE db`)
+	runner.Exec(t, `CREATE TABLE db.customers (id INT PRIMARY KEY, region STRING)`)
+	runner.Exec(t, `CREATE TABLE db.orders (id INT PRIMARY KEY, customer INT, amount INT)`)
+	runner.Exec(t, `INSERT INTO db.customers VALUES (1, 'east'), (2, 'west'), (3, 'east')`)
+	runner.Exec(t, `INSERT INTO db.orders VALUES (1, 1, 10), (2, 1, 20), (3, 2, 5)`)
+
+	views := map[string]string{
+		"totals": `SELECT customer, sum(amount) AS total, count(*) AS n FROM db.orders GROUP BY customer`,
+		"details": `SELECT o.id AS order_id, c.id AS customer_id, c.region, o.amount
+FROM db.orders AS o JOIN db.customers AS c ON o.customer = c.id WHERE o.amount > 1`,
+		"regions": `SELECT c.region, sum(o.amount) AS total
+FROM db.orders AS o JOIN db.customers AS c ON o.customer = c.id GROUP BY c.region`,
+	}
+	for name, query := range views {
+		runner.Exec(t, `CREATE MATERIALIZED VIEW db.`+name+
+			` WITH (auto_refresh = incremental) AS `+query)
+	}
+
+	// checkViews waits until the contents of every view match its query.
+	checkViews := func() {
+		for name, query := range views {
+			expected := runner.QueryStr(t, `SELECT * FROM (`+query+`) ORDER BY 1, 2`)
+			testutils.SucceedsSoon(t, func() error {
+				actual := runner.QueryStr(t, `SELECT * FROM db.`+name+` ORDER BY 1, 2`)
+				if !reflect.DeepEqual(expected, actual) {
+					return errors.Newf("view %s: expected %v, got %v", name, expected, actual)
+				}
+				return nil
+			})
+		}
+	}
+	checkViews()
+
+	runner.Exec(t, `INSERT INTO db.orders VALUES (4, 3, 7), (5, 2, 1), (6, NULL, 3)`)
+	checkViews()
+
+	runner.Exec(t, `UPDATE db.orders SET amount = amount * 2 WHERE customer = 1`)
+	runner.Exec(t, `UPDATE db.customers SET region = 'north' WHERE id = 2`)
+	checkViews()
+
+	runner.Exec(t, `DELETE FROM db.orders WHERE id IN (1, 3)`)
+	runner.Exec(t, `DELETE FROM db.customers WHERE id = 3`)
+	checkViews()
+
+	// Identical changes to the same key must not be lost.
+	runner.Exec(t, `INSERT INTO db.orders VALUES (7, 1, 20), (8, 1, 20)`)
+	checkViews()
+
+	// The job stops once the view is dropped.
+	tableDesc := catalogkv.TestingGetTableDescriptor(
+		kvDB, s.ExecutorConfig().(sql.ExecutorConfig).Codec, "db", "totals")
+	require.NotNil(t, tableDesc.GetIncrementalRefresh())
+	jobID := tableDesc.GetIncrementalRefresh().JobID
+	runner.Exec(t, `DROP MATERIALIZED VIEW db.totals`)
+	runner.CheckQueryResultsRetry(t,
+		fmt.Sprintf(`SELECT status FROM [SHOW JOBS] WHERE job_id = %d`, jobID),
+		[][]string{{string(jobs.StatusSucceeded)}},
+	)
+}
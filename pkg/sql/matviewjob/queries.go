@@ -0,0 +1,195 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package matviewjob
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// queryBuilder builds the queries maintaining a materialized view refreshed
+// incrementally. The view is referenced by ID, so that renaming it does not
+// affect the job.
+type queryBuilder struct {
+	viewID      descpb.ID
+	plan        *sql.IncrementalViewPlan
+	viewColumns string
+	keyColumns  []string
+}
+
+func makeQueryBuilder(viewID descpb.ID, plan *sql.IncrementalViewPlan) queryBuilder {
+	q := queryBuilder{viewID: viewID, plan: plan}
+	var buf bytes.Buffer
+	for i, col := range plan.ViewColumns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(tree.NameString(col))
+	}
+	q.viewColumns = buf.String()
+	for _, col := range plan.KeyColumns {
+		q.keyColumns = append(q.keyColumns, tree.NameString(col))
+	}
+	return q
+}
+
+// keyPredicate writes a predicate selecting the rows for which the given
+// expressions are equal to one of the given keys. The non-NULL values of the
+// keys are appended to args and referenced as placeholders.
+func keyPredicate(
+	buf *bytes.Buffer, exprs []string, keys []tree.Datums, args []interface{},
+) []interface{} {
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteString(" OR ")
+		}
+		buf.WriteByte('(')
+		for j, d := range key {
+			if j > 0 {
+				buf.WriteString(" AND ")
+			}
+			if d == tree.DNull {
+				fmt.Fprintf(buf, "%s IS NULL", exprs[j])
+				continue
+			}
+			args = append(args, d)
+			fmt.Fprintf(buf, "%s = $%d", exprs[j], len(args))
+		}
+		buf.WriteByte(')')
+	}
+	return args
+}
+
+// changedKeysQuery returns the query selecting the keys of the view rows
+// derived from the rows of the given source with the given primary keys, as
+// of the given time if it is set.
+func (q queryBuilder) changedKeysQuery(
+	source int, pks []tree.Datums, asOf hlc.Timestamp,
+) (string, []interface{}) {
+	var buf bytes.Buffer
+	buf.WriteString("SELECT DISTINCT ")
+	for i, e := range q.plan.KeyExprs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(e)
+	}
+	fmt.Fprintf(&buf, " FROM %s", q.plan.From)
+	if !asOf.IsEmpty() {
+		d := tree.TimestampToDecimal(asOf)
+		fmt.Fprintf(&buf, " AS OF SYSTEM TIME '%s'", d.String())
+	}
+	buf.WriteString(" WHERE ")
+	if q.plan.Where != "" {
+		fmt.Fprintf(&buf, "(%s) AND ", q.plan.Where)
+	}
+	buf.WriteByte('(')
+	args := keyPredicate(&buf, q.plan.Sources[source].PKExprs, pks, nil /* args */)
+	buf.WriteByte(')')
+	return buf.String(), args
+}
+
+// viewRowsQuery returns the query selecting the row IDs and columns of the
+// view rows with the given keys.
+func (q queryBuilder) viewRowsQuery(keys []tree.Datums) (string, []interface{}) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "SELECT %s, %s FROM [%d AS v] WHERE ",
+		tree.NameString(q.plan.RowIDColumn), q.viewColumns, q.viewID)
+	args := keyPredicate(&buf, q.keyColumns, keys, nil /* args */)
+	return buf.String(), args
+}
+
+// recomputeQuery returns the query computing the rows of the view with the
+// given keys. The filter on the keys is pushed into the view query by the
+// optimizer.
+func (q queryBuilder) recomputeQuery(keys []tree.Datums) (string, []interface{}) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "SELECT * FROM (%s) AS v (%s) WHERE ", q.plan.ViewQuery, q.viewColumns)
+	args := keyPredicate(&buf, q.keyColumns, keys, nil /* args */)
+	return buf.String(), args
+}
+
+// deleteQuery returns the query deleting the view rows with the given row IDs.
+func (q queryBuilder) deleteQuery(rowIDs tree.Datums) (string, []interface{}) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "DELETE FROM [%d AS v] WHERE %s IN (",
+		q.viewID, tree.NameString(q.plan.RowIDColumn))
+	args := make([]interface{}, len(rowIDs))
+	for i, d := range rowIDs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "$%d", i+1)
+		args[i] = d
+	}
+	buf.WriteByte(')')
+	return buf.String(), args
+}
+
+// insertQuery returns the query inserting the given rows into the view.
+func (q queryBuilder) insertQuery(rows []tree.Datums) (string, []interface{}) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "INSERT INTO [%d AS v] (%s) VALUES ", q.viewID, q.viewColumns)
+	var args []interface{}
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteByte('(')
+		for j, d := range row {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			args = append(args, d)
+			fmt.Fprintf(&buf, "$%d", len(args))
+		}
+		buf.WriteByte(')')
+	}
+	return buf.String(), args
+}
+
+// keySet is a set of keys, in insertion order.
+type keySet struct {
+	keys []tree.Datums
+	seen map[string]struct{}
+}
+
+func (s *keySet) add(keys ...tree.Datums) {
+	if s.seen == nil {
+		s.seen = make(map[string]struct{})
+	}
+	for _, k := range keys {
+		rk := rowKey(k)
+		if _, ok := s.seen[rk]; ok {
+			continue
+		}
+		s.seen[rk] = struct{}{}
+		s.keys = append(s.keys, k)
+	}
+}
+
+func (s *keySet) copy() keySet {
+	var res keySet
+	res.add(s.keys...)
+	return res
+}
+
+// rowKey returns a string identifying the values of a row.
+func rowKey(row tree.Datums) string {
+	f := tree.NewFmtCtx(tree.FmtParsable)
+	f.FormatNode(&row)
+	return f.CloseAndGetString()
+}
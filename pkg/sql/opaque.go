@@ -78,6 +78,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 	switch n := stmt.(type) {
 	case *tree.AlterDatabaseOwner:
 		return p.AlterDatabaseOwner(ctx, n)
+	case *tree.AlterDefaultPrivileges:
+		return p.AlterDefaultPrivileges(ctx, n)
 	case *tree.AlterDatabaseAddRegion:
 		return p.AlterDatabaseAddRegion(ctx, n)
 	case *tree.AlterDatabaseDropRegion:
@@ -100,6 +102,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.AlterTableSetSchema(ctx, n)
 	case *tree.AlterType:
 		return p.AlterType(ctx, n)
+	case *tree.AlterDomain:
+		return p.AlterDomain(ctx, n)
 	case *tree.AlterRole:
 		return p.AlterRole(ctx, n)
 	case *tree.AlterSequence:
@@ -116,6 +120,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.CreateDatabase(ctx, n)
 	case *tree.CreateIndex:
 		return p.CreateIndex(ctx, n)
+	case *tree.CreatePolicy:
+		return p.CreatePolicy(ctx, n)
 	case *tree.CreateSchema:
 		return p.CreateSchema(ctx, n)
 	case *tree.CreateType:
@@ -126,16 +132,28 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.CreateSequence(ctx, n)
 	case *tree.CreateExtension:
 		return p.CreateExtension(ctx, n)
+	case *tree.CloseCursor:
+		return p.CloseCursor(ctx, n)
 	case *tree.Deallocate:
 		return p.Deallocate(ctx, n)
+	case *tree.DeclareCursor:
+		return p.DeclareCursor(ctx, n)
 	case *tree.Discard:
 		return p.Discard(ctx, n)
+	case *tree.FetchCursor:
+		return p.FetchCursor(ctx, &n.CursorStmt, false /* isMove */)
+	case *tree.MoveCursor:
+		return p.FetchCursor(ctx, &n.CursorStmt, true /* isMove */)
 	case *tree.DropDatabase:
 		return p.DropDatabase(ctx, n)
 	case *tree.DropIndex:
 		return p.DropIndex(ctx, n)
 	case *tree.DropOwnedBy:
 		return p.DropOwnedBy(ctx)
+	case *tree.DropPlanBaseline:
+		return p.DropPlanBaseline(ctx, n)
+	case *tree.DropPolicy:
+		return p.DropPolicy(ctx, n)
 	case *tree.DropRole:
 		return p.DropRole(ctx, n)
 	case *tree.DropSchema:
@@ -152,6 +170,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.Grant(ctx, n)
 	case *tree.GrantRole:
 		return p.GrantRole(ctx, n)
+	case *tree.PinPlan:
+		return p.PinPlan(ctx, n)
 	case *tree.ReassignOwnedBy:
 		return p.ReassignOwnedBy(ctx, n)
 	case *tree.RefreshMaterializedView:
@@ -182,6 +202,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.SetVar(ctx, n)
 	case *tree.SetTransaction:
 		return p.SetTransaction(ctx, n)
+	case *tree.SetConstraints:
+		return p.SetConstraints(ctx, n)
 	case *tree.SetSessionAuthorizationDefault:
 		return p.SetSessionAuthorizationDefault()
 	case *tree.SetSessionCharacteristics:
@@ -219,6 +241,7 @@ func init() {
 		&tree.AlterDatabaseOwner{},
 		&tree.AlterDatabasePrimaryRegion{},
 		&tree.AlterDatabaseSurvivalGoal{},
+		&tree.AlterDefaultPrivileges{},
 		&tree.AlterIndex{},
 		&tree.AlterSchema{},
 		&tree.AlterTable{},
@@ -226,32 +249,41 @@ func init() {
 		&tree.AlterTableOwner{},
 		&tree.AlterTableSetSchema{},
 		&tree.AlterType{},
+		&tree.AlterDomain{},
 		&tree.AlterSequence{},
 		&tree.AlterRole{},
 		&tree.CommentOnColumn{},
 		&tree.CommentOnDatabase{},
 		&tree.CommentOnIndex{},
+		&tree.CloseCursor{},
 		&tree.CommentOnTable{},
 		&tree.CreateDatabase{},
 		&tree.CreateExtension{},
 		&tree.CreateIndex{},
+		&tree.CreatePolicy{},
 		&tree.CreateSchema{},
 		&tree.CreateSequence{},
 		&tree.CreateType{},
 		&tree.CreateRole{},
 		&tree.Deallocate{},
+		&tree.DeclareCursor{},
 		&tree.Discard{},
 		&tree.DropDatabase{},
 		&tree.DropIndex{},
 		&tree.DropOwnedBy{},
+		&tree.DropPlanBaseline{},
+		&tree.DropPolicy{},
 		&tree.DropRole{},
 		&tree.DropSchema{},
 		&tree.DropSequence{},
 		&tree.DropTable{},
 		&tree.DropType{},
 		&tree.DropView{},
+		&tree.FetchCursor{},
 		&tree.Grant{},
 		&tree.GrantRole{},
+		&tree.MoveCursor{},
+		&tree.PinPlan{},
 		&tree.ReassignOwnedBy{},
 		&tree.RefreshMaterializedView{},
 		&tree.RenameColumn{},
@@ -267,6 +299,7 @@ func init() {
 		&tree.SetZoneConfig{},
 		&tree.SetVar{},
 		&tree.SetTransaction{},
+		&tree.SetConstraints{},
 		&tree.SetSessionAuthorizationDefault{},
 		&tree.SetSessionCharacteristics{},
 		&tree.ShowClusterSetting{},
@@ -15,6 +15,7 @@ package cat
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -150,6 +151,14 @@ type Catalog interface {
 	// NOLOGIN instead of LOGIN.
 	HasRoleOption(ctx context.Context, roleOption roleoption.Option) (bool, error)
 
+	// HasOwnership returns true if the current user, or any role the user is a
+	// member of, owns the given catalog object.
+	HasOwnership(ctx context.Context, o Object) (bool, error)
+
+	// IsMemberOfRole returns true if the current user is the given role, or is
+	// a member of it, either directly or indirectly.
+	IsMemberOfRole(ctx context.Context, role security.SQLUsername) (bool, error)
+
 	// FullyQualifiedName retrieves the fully qualified name of a data source.
 	// Note that:
 	//  - this call may involve a database operation so it shouldn't be used in
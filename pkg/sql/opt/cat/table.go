@@ -13,6 +13,7 @@ package cat
 import (
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 )
 
@@ -133,6 +134,18 @@ type Table interface {
 	// Unique returns the ith unique constraint defined on this table, where
 	// i < UniqueCount.
 	Unique(i UniqueOrdinal) UniqueConstraint
+
+	// IsRowLevelSecurityEnabled returns true if the rows of the table which
+	// users other than its owner and admins can access are restricted by its
+	// row-level security policies.
+	IsRowLevelSecurityEnabled() bool
+
+	// PolicyCount returns the number of row-level security policies defined on
+	// this table.
+	PolicyCount() int
+
+	// Policy returns the ith row-level security policy, where i < PolicyCount.
+	Policy(i int) Policy
 }
 
 // CheckConstraint contains the SQL text and the validity status for a check
@@ -147,6 +160,32 @@ type CheckConstraint struct {
 	Validated  bool
 }
 
+// Policy contains the SQL text of the expressions of a row-level security
+// policy on a table, along with the command and roles it applies to. For
+// example, this policy only lets the members of the app role see the rows of
+// their tenant:
+//
+//   CREATE POLICY p ON a TO app USING (tenant = current_user())
+//
+type Policy struct {
+	Name    tree.Name
+	Command tree.PolicyCommand
+	// Roles are the roles to which the policy applies. A policy applying to the
+	// public role applies to all users.
+	Roles []security.SQLUsername
+	// UsingExpr filters the existing rows visible through the policy. It is
+	// empty if the policy does not grant access to existing rows.
+	UsingExpr string
+	// WithCheckExpr must hold for the rows written through the policy. If it is
+	// empty, UsingExpr is used instead.
+	WithCheckExpr string
+}
+
+// AppliesToCommand returns true if the policy applies to the given command.
+func (p *Policy) AppliesToCommand(cmd tree.PolicyCommand) bool {
+	return p.Command == tree.PolicyAll || p.Command == cmd
+}
+
 // TableStatistic is an interface to a table statistic. Each statistic is
 // associated with a set of columns.
 type TableStatistic interface {
@@ -173,10 +212,17 @@ type TableStatistic interface {
 	NullCount() uint64
 
 	// Histogram returns a slice of histogram buckets, sorted by UpperBound.
-	// It is only used for single-column stats (i.e., when ColumnCount() = 1),
-	// and it represents the distribution of values for that column.
-	// See HistogramBucket for more details.
+	// For single-column stats (i.e., when ColumnCount() = 1), it represents the
+	// distribution of values for that column. For multi-column stats, the
+	// upper bounds are tuples with the values of the columns, in the order
+	// given by ColumnOrdinal, and it represents the distribution of those
+	// tuples. See HistogramBucket for more details.
 	Histogram() []HistogramBucket
+
+	// IsForecast returns true if the statistic was forecasted from the history
+	// of collected statistics rather than collected. A forecast is a prediction
+	// of the statistic at its CreatedAt time, which can be in the future.
+	IsForecast() bool
 }
 
 // HistogramBucket contains the data for a single histogram bucket. Note
@@ -246,6 +292,12 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether checking of the constraint can be postponed
+	// until the end of the transaction. Since a deferred constraint can be
+	// violated in the middle of a transaction, no assumptions can be made about
+	// the data if the constraint is deferrable.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
@@ -284,6 +336,12 @@ type UniqueConstraint interface {
 	// cannot make any assumptions about the data. An unvalidated constraint still
 	// needs to be enforced on new mutations.
 	Validated() bool
+
+	// Deferrability returns whether checking of the constraint can be postponed
+	// until the end of the transaction. Since a deferred constraint can be
+	// violated in the middle of a transaction, no assumptions can be made about
+	// the data if the constraint is deferrable.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueOrdinal identifies a unique constraint (in the context of a Table).
@@ -161,6 +161,8 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 	tab := md.Table(ins.Table)
 
 	//  - there are no self-referencing foreign keys;
+	//  - there are no deferrable foreign keys (the fast path cannot postpone
+	//    its checks until the end of the transaction);
 	//  - all FK checks can be performed using direct lookups into unique indexes.
 	fkChecks := make([]exec.InsertFastPathFKCheck, len(ins.FKChecks))
 	for i := range ins.FKChecks {
@@ -170,6 +172,9 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 			return execPlan{}, false, nil
 		}
 		fk := tab.OutboundForeignKey(c.FKOrdinal)
+		if fk.Deferrability() != tree.NotDeferrableConstraint {
+			return execPlan{}, false, nil
+		}
 		lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
 		if !isLookupJoin || lookupJoin.JoinType != opt.AntiJoinOp {
 			// Not a lookup anti-join.
@@ -492,8 +497,14 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	//
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
-	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PartialIndexDelCols))
+	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PassthroughCols)+len(del.PartialIndexDelCols))
 	colList = appendColsWhenPresent(colList, del.FetchCols)
+	// The RETURNING clause of the Delete can refer to the columns in any of the
+	// USING tables. As a result, the Delete may need to passthrough those
+	// columns so the projection above can use them.
+	if del.NeedResults() {
+		colList = append(colList, del.PassthroughCols...)
+	}
 	colList = appendColsWhenPresent(colList, del.PartialIndexDelCols)
 
 	input, err := b.buildMutationInput(del, del.Input, colList, &del.MutationPrivate)
@@ -506,11 +517,22 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	tab := md.Table(del.Table)
 	fetchColOrds := ordinalSetFromColList(del.FetchCols)
 	returnColOrds := ordinalSetFromColList(del.ReturnCols)
+
+	// Construct the result columns for the passthrough set.
+	var passthroughCols colinfo.ResultColumns
+	if del.NeedResults() {
+		for _, passthroughCol := range del.PassthroughCols {
+			colMeta := b.mem.Metadata().ColumnMeta(passthroughCol)
+			passthroughCols = append(passthroughCols, colinfo.ResultColumn{Name: colMeta.Alias, Typ: colMeta.Type})
+		}
+	}
+
 	node, err := b.factory.ConstructDelete(
 		input.root,
 		tab,
 		fetchColOrds,
 		returnColOrds,
+		passthroughCols,
 		b.allowAutoCommit && len(del.FKChecks) == 0 && len(del.FKCascades) == 0,
 	)
 	if err != nil {
@@ -833,7 +855,10 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 		if err != nil {
 			return err
 		}
-		b.checks = append(b.checks, node)
+		b.checks = append(b.checks, exec.Check{
+			Root:       node,
+			Deferrable: deferrableUniqueCheck(md, c),
+		})
 	}
 	return nil
 }
@@ -859,11 +884,62 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 		if err != nil {
 			return err
 		}
-		b.checks = append(b.checks, node)
+		b.checks = append(b.checks, exec.Check{
+			Root:       node,
+			Deferrable: deferrableFKCheck(md, c),
+		})
 	}
 	return nil
 }
 
+// deferrableUniqueCheck returns a description of the constraint verified by
+// the given uniqueness check if that constraint is DEFERRABLE, or nil
+// otherwise.
+func deferrableUniqueCheck(
+	md *opt.Metadata, c *memo.UniqueChecksItem,
+) *exec.DeferrableConstraint {
+	tab := md.Table(c.Table)
+	uc := tab.Unique(c.CheckOrdinal)
+	if uc.Deferrability() == tree.NotDeferrableConstraint {
+		return nil
+	}
+	return &exec.DeferrableConstraint{
+		Table:             tab,
+		Name:              uc.Name(),
+		InitiallyDeferred: uc.Deferrability() == tree.InitiallyDeferredConstraint,
+	}
+}
+
+// deferrableFKCheck returns a description of the constraint verified by the
+// given FK check if that constraint is DEFERRABLE, or nil otherwise.
+func deferrableFKCheck(md *opt.Metadata, c *memo.FKChecksItem) *exec.DeferrableConstraint {
+	origin := md.Table(c.OriginTable)
+	var fk cat.ForeignKeyConstraint
+	if c.FKOutbound {
+		fk = origin.OutboundForeignKey(c.FKOrdinal)
+	} else {
+		fk = md.Table(c.ReferencedTable).InboundForeignKey(c.FKOrdinal)
+		action := fk.UpdateReferenceAction()
+		if c.OpName == "delete" {
+			action = fk.DeleteReferenceAction()
+		}
+		if action == tree.Restrict {
+			// As in Postgres, the RESTRICT action is checked immediately even if
+			// the constraint is deferrable.
+			return nil
+		}
+	}
+	if fk.Deferrability() == tree.NotDeferrableConstraint {
+		return nil
+	}
+	return &exec.DeferrableConstraint{
+		Table:             origin,
+		Name:              fk.Name(),
+		ForeignKey:        true,
+		InitiallyDeferred: fk.Deferrability() == tree.InitiallyDeferredConstraint,
+	}
+}
+
 // mkUniqueCheckErr generates a user-friendly error describing a uniqueness
 // violation. The keyVals are the values that correspond to the
 // cat.UniqueConstraint columns.
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -354,14 +355,27 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 		}
 		if scan, ok := e.(*memo.ScanExpr); ok {
 			tab := b.mem.Metadata().Table(scan.Table)
-			if tab.StatisticCount() > 0 {
-				// The first stat is the most recent one.
-				stat := tab.Statistic(0)
-				val.TableStatsRowCount = stat.RowCount()
-				if val.TableStatsRowCount == 0 {
-					val.TableStatsRowCount = 1
+			// The first stat is the most recent one. The row count is taken from
+			// the first stat used by the optimizer, which can be a forecast, but
+			// the creation time is that of the most recent collected stat.
+			useForecasts := b.evalCtx != nil && b.evalCtx.SessionData.OptimizerUseForecasts
+			rowCountFound := false
+			for i := 0; i < tab.StatisticCount(); i++ {
+				stat := tab.Statistic(i)
+				if stat.IsForecast() && !useForecasts {
+					continue
+				}
+				if !rowCountFound {
+					rowCountFound = true
+					val.TableStatsRowCount = stat.RowCount()
+					if val.TableStatsRowCount == 0 {
+						val.TableStatsRowCount = 1
+					}
+				}
+				if !stat.IsForecast() {
+					val.TableStatsCreatedAt = stat.CreatedAt()
+					break
 				}
-				val.TableStatsCreatedAt = stat.CreatedAt()
 			}
 		}
 		ef.AnnotateNode(ep.root, exec.EstimatedStatsID, &val)
@@ -920,6 +934,10 @@ func (b *Builder) buildHashJoin(join memo.RelExpr) (execPlan, error) {
 		)
 	}
 
+	if ep, ok, err := b.tryBuildAdaptiveHashJoin(join); ok || err != nil {
+		return ep, err
+	}
+
 	joinType := joinOpToJoinType(join.Op())
 	leftExpr := join.Child(0).(memo.RelExpr)
 	rightExpr := join.Child(1).(memo.RelExpr)
@@ -1536,6 +1554,15 @@ func (b *Builder) buildLookupJoin(join *memo.LookupJoinExpr) (execPlan, error) {
 		locking = forUpdateLocking
 	}
 
+	reqOrdering := res.reqOrdering(join)
+	var adaptiveThreshold uint64
+	if len(join.LookupExpr) == 0 && !join.IsSecondJoinInPairedJoiner && !join.LocalityOptimized &&
+		len(reqOrdering) == 0 && locking == nil && !join.Flags.Has(memo.DisallowHashJoinStoreRight) &&
+		canBuildAdaptiveJoin(joinOpToJoinType(join.JoinType), len(join.On) > 0) &&
+		b.keyColsMatchIndex(join.KeyCols, join.Table, idx) {
+		adaptiveThreshold = b.adaptiveJoinThreshold(tab)
+	}
+
 	res.root, err = b.factory.ConstructLookupJoin(
 		joinOpToJoinType(join.JoinType),
 		input.root,
@@ -1547,8 +1574,10 @@ func (b *Builder) buildLookupJoin(join *memo.LookupJoinExpr) (execPlan, error) {
 		lookupOrdinals,
 		onExpr,
 		join.IsSecondJoinInPairedJoiner,
-		res.reqOrdering(join),
+		reqOrdering,
 		locking,
+		adaptiveThreshold,
+		false, /* adaptiveBufferInput */
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -1569,6 +1598,161 @@ func (b *Builder) buildLookupJoin(join *memo.LookupJoinExpr) (execPlan, error) {
 	return res, nil
 }
 
+// adaptiveJoinLookupCostRatio is the ratio between the cost of looking up a
+// row and the cost of reading a row during a full scan of an index, which
+// matches the ratio between the random and sequential IO cost factors of the
+// coster.
+const adaptiveJoinLookupCostRatio = 4
+
+// adaptiveJoinThreshold returns the number of input rows of an adaptive join
+// into the given table above which a hash join against a full scan of the index
+// is expected to be cheaper than looking up the input rows. It returns 0 if
+// adaptive joins are disabled or if the table has no statistics.
+func (b *Builder) adaptiveJoinThreshold(tab cat.Table) uint64 {
+	if b.evalCtx == nil || !b.evalCtx.SessionData.AdaptiveJoinsEnabled ||
+		b.evalCtx.SessionData.VectorizeMode == sessiondatapb.VectorizeOff || tab.IsVirtualTable() {
+		// Adaptive joins are only executed by the vectorized engine.
+		return 0
+	}
+	useForecasts := b.evalCtx.SessionData.OptimizerUseForecasts
+	for i := 0; i < tab.StatisticCount(); i++ {
+		stat := tab.Statistic(i)
+		if stat.IsForecast() && !useForecasts {
+			continue
+		}
+		if threshold := stat.RowCount() / adaptiveJoinLookupCostRatio; threshold > 0 {
+			return threshold
+		}
+		return 1
+	}
+	return 0
+}
+
+// canBuildAdaptiveJoin returns true if a join of the given type can be
+// executed as an adaptive join. Only inner joins can have an ON condition.
+func canBuildAdaptiveJoin(joinType descpb.JoinType, hasOnCond bool) bool {
+	switch joinType {
+	case descpb.InnerJoin:
+		return true
+	case descpb.LeftOuterJoin, descpb.LeftSemiJoin, descpb.LeftAntiJoin:
+		return !hasOnCond
+	default:
+		return false
+	}
+}
+
+// keyColsMatchIndex returns true if the given columns have the same types as
+// the corresponding prefix of the key columns of the index, so that the rows
+// can be joined by a hash join as well as looked up.
+func (b *Builder) keyColsMatchIndex(keyCols opt.ColList, tabID opt.TableID, idx cat.Index) bool {
+	md := b.mem.Metadata()
+	if len(keyCols) == 0 || len(keyCols) > idx.KeyColumnCount() || idx.IsInverted() {
+		return false
+	}
+	for i, col := range keyCols {
+		indexCol := tabID.ColumnID(idx.Column(i).Ordinal())
+		if !md.ColumnMeta(col).Type.Identical(md.ColumnMeta(indexCol).Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// tryBuildAdaptiveHashJoin builds a hash join between an input and a full scan
+// of an index as an adaptive join, which looks up the input rows instead of
+// scanning the index if there are few of them. It returns ok=false if the join
+// is not eligible.
+func (b *Builder) tryBuildAdaptiveHashJoin(join memo.RelExpr) (_ execPlan, ok bool, _ error) {
+	scan, isScan := join.Child(1).(*memo.ScanExpr)
+	if !isScan || scan.Constraint != nil || scan.InvertedConstraint != nil || scan.HardLimit != 0 ||
+		scan.Locking != nil || scan.LocalityOptimized || b.forceForUpdateLocking ||
+		join.Private().(*memo.JoinPrivate).Flags.Has(memo.DisallowLookupJoinIntoRight) {
+		return execPlan{}, false, nil
+	}
+	leftExpr := join.Child(0).(memo.RelExpr)
+	filters := *join.Child(2).(*memo.FiltersExpr)
+	leftEq, rightEq := memo.ExtractJoinEqualityColumns(
+		leftExpr.Relational().OutputCols, scan.Relational().OutputCols, filters,
+	)
+	on := memo.ExtractRemainingJoinFilters(filters, leftEq, rightEq)
+	joinType := joinOpToJoinType(join.Op())
+	if !canBuildAdaptiveJoin(joinType, len(on) > 0) {
+		return execPlan{}, false, nil
+	}
+
+	// The equality columns must be a prefix of the index key columns, in any
+	// order.
+	md := b.mem.Metadata()
+	tab := md.Table(scan.Table)
+	idx := tab.Index(scan.Index)
+	if len(rightEq) == 0 || len(rightEq) > idx.KeyColumnCount() {
+		return execPlan{}, false, nil
+	}
+	keyCols := make(opt.ColList, len(rightEq))
+	for i := range keyCols {
+		indexCol := scan.Table.ColumnID(idx.Column(i).Ordinal())
+		for j := range rightEq {
+			if rightEq[j] == indexCol {
+				keyCols[i] = leftEq[j]
+				break
+			}
+		}
+		if keyCols[i] == 0 {
+			return execPlan{}, false, nil
+		}
+	}
+	if !b.keyColsMatchIndex(keyCols, scan.Table, idx) {
+		return execPlan{}, false, nil
+	}
+	threshold := b.adaptiveJoinThreshold(tab)
+	if threshold == 0 {
+		return execPlan{}, false, nil
+	}
+
+	input, err := b.buildRelational(leftExpr)
+	if err != nil {
+		return execPlan{}, false, err
+	}
+	keyOrdinals := make([]exec.NodeColumnOrdinal, len(keyCols))
+	for i, c := range keyCols {
+		keyOrdinals[i] = input.getNodeColumnOrdinal(c)
+	}
+	lookupOrdinals, lookupColMap := b.getColumns(scan.Cols, scan.Table)
+	allCols := joinOutputMap(input.outputCols, lookupColMap)
+	res := execPlan{outputCols: allCols}
+	if !joinType.ShouldIncludeRightColsInOutput() {
+		res.outputCols = input.outputCols
+	}
+	var onExpr tree.TypedExpr
+	if len(on) > 0 {
+		onExpr, err = b.buildScalarWithMap(allCols, &on)
+		if err != nil {
+			return execPlan{}, false, err
+		}
+	}
+
+	res.root, err = b.factory.ConstructLookupJoin(
+		joinType,
+		input.root,
+		tab,
+		idx,
+		keyOrdinals,
+		scan.Relational().FuncDeps.ColsAreStrictKey(rightEq.ToSet()),
+		nil, /* lookupExpr */
+		lookupOrdinals,
+		onExpr,
+		false, /* isSecondJoinInPairedJoiner */
+		nil,   /* reqOrdering */
+		nil,   /* locking */
+		threshold,
+		true, /* adaptiveBufferInput */
+	)
+	if err != nil {
+		return execPlan{}, false, err
+	}
+	return res, true, nil
+}
+
 func (b *Builder) buildInvertedJoin(join *memo.InvertedJoinExpr) (execPlan, error) {
 	input, err := b.buildRelational(join.Input)
 	if err != nil {
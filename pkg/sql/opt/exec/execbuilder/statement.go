@@ -66,6 +66,7 @@ func (b *Builder) buildCreateView(cv *memo.CreateViewExpr) (execPlan, error) {
 		cols,
 		cv.Deps,
 		cv.TypeDeps,
+		cv.StorageParams,
 	)
 	return execPlan{root: root}, err
 }
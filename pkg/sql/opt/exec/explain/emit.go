@@ -214,6 +214,9 @@ func (e *emitter) nodeName(n *Node) (string, error) {
 		if a.Table.IsVirtualTable() {
 			return e.joinNodeName("virtual table lookup", a.JoinType), nil
 		}
+		if a.AdaptiveThreshold > 0 {
+			return e.joinNodeName("adaptive", a.JoinType), nil
+		}
 		return e.joinNodeName("lookup", a.JoinType), nil
 
 	case invertedJoinOp:
@@ -350,6 +353,9 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		if s.KVBytesRead.HasValue() {
 			e.ob.AddField("KV bytes read", humanize.IBytes(s.KVBytesRead.Value()))
 		}
+		if len(s.JoinStrategies) > 0 {
+			e.ob.AddField("join strategy", strings.Join(s.JoinStrategies, "; "))
+		}
 	}
 
 	if stats, ok := n.annotations[exec.EstimatedStatsID]; ok {
@@ -570,6 +576,14 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		ob.Expr("lookup condition", a.LookupExpr, appendColumns(inputCols, tableColumns(a.Table, a.LookupCols)...))
 		ob.Expr("pred", a.OnCond, appendColumns(inputCols, tableColumns(a.Table, a.LookupCols)...))
 		e.emitLockingPolicy(a.Locking)
+		if a.AdaptiveThreshold > 0 {
+			if a.AdaptiveBufferInput {
+				ob.Attr("planned strategy", "hash join")
+			} else {
+				ob.Attr("planned strategy", "lookup join")
+			}
+			ob.Attr("adaptive threshold", humanizeutil.Count(a.AdaptiveThreshold))
+		}
 
 	case zigzagJoinOp:
 		a := n.args.(*zigzagJoinArgs)
@@ -241,6 +241,37 @@ type Cascade struct {
 	) (Plan, error)
 }
 
+// Check describes a check query, to be run after the main query and all
+// cascades have been executed. The query does not return results but can
+// generate errors (e.g. foreign key check failures).
+type Check struct {
+	// Root is the root of the check query.
+	Root Node
+
+	// Deferrable is set if the check verifies a DEFERRABLE constraint. The
+	// execution engine can postpone such checks until the transaction commits.
+	Deferrable *DeferrableConstraint
+}
+
+// DeferrableConstraint identifies a DEFERRABLE constraint that is verified by
+// a check query.
+type DeferrableConstraint struct {
+	// Table is the table on which the constraint is defined. For foreign keys,
+	// this is the origin (referencing) table.
+	Table cat.Table
+
+	// Name is the name of the constraint.
+	Name string
+
+	// ForeignKey is true if the constraint is a foreign key constraint and
+	// false if it is a unique constraint.
+	ForeignKey bool
+
+	// InitiallyDeferred is true if the constraint is deferred unless SET
+	// CONSTRAINTS makes it immediate.
+	InitiallyDeferred bool
+}
+
 // InsertFastPathFKCheck contains information about a foreign key check to be
 // performed by the insert fast-path (see ConstructInsertFastPath). It
 // identifies the index into which we can perform the lookup.
@@ -318,6 +349,10 @@ type ExecutionStats struct {
 	KVBytesRead optional.Uint
 	KVRowsRead  optional.Uint
 
+	// JoinStrategies are the distinct strategies chosen at runtime by an
+	// adaptive join.
+	JoinStrategies []string
+
 	// Nodes on which this operator was executed.
 	Nodes []string
 }
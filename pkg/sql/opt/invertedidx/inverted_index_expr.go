@@ -110,13 +110,28 @@ func TryFilterInvertedIndex(
 		}
 		typ = types.Geometry
 	} else {
-		filterPlanner = &jsonOrArrayFilterPlanner{
-			tabID:           tabID,
-			index:           index,
-			computedColumns: computedColumns,
-		}
 		col := index.VirtualInvertedColumn().InvertedSourceColumnOrdinal()
 		typ = factory.Metadata().Table(tabID).Column(col).DatumType()
+		switch typ.Family() {
+		case types.TSVectorFamily:
+			filterPlanner = &tsearchFilterPlanner{
+				tabID:           tabID,
+				index:           index,
+				computedColumns: computedColumns,
+			}
+		case types.StringFamily:
+			filterPlanner = &trigramFilterPlanner{
+				tabID:           tabID,
+				index:           index,
+				computedColumns: computedColumns,
+			}
+		default:
+			filterPlanner = &jsonOrArrayFilterPlanner{
+				tabID:           tabID,
+				index:           index,
+				computedColumns: computedColumns,
+			}
+		}
 	}
 
 	var invertedExpr inverted.Expression
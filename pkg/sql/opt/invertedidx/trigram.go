@@ -0,0 +1,106 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+)
+
+type trigramFilterPlanner struct {
+	tabID           opt.TableID
+	index           cat.Index
+	computedColumns map[opt.ColumnID]opt.ScalarExpr
+}
+
+var _ invertedFilterPlanner = &trigramFilterPlanner{}
+
+// extractInvertedFilterConditionFromLeaf is part of the invertedFilterPlanner
+// interface.
+func (t *trigramFilterPlanner) extractInvertedFilterConditionFromLeaf(
+	evalCtx *tree.EvalContext, expr opt.ScalarExpr,
+) (
+	invertedExpr inverted.Expression,
+	remainingFilters opt.ScalarExpr,
+	_ *invertedexpr.PreFiltererStateForInvertedFilterer,
+) {
+	switch e := expr.(type) {
+	case *memo.LikeExpr:
+		invertedExpr = t.extractLikeCondition(e.Left, e.Right)
+	case *memo.ILikeExpr:
+		// Trigrams are case-insensitive, so ILIKE uses the same spans as LIKE.
+		invertedExpr = t.extractLikeCondition(e.Left, e.Right)
+	case *memo.TrigramSimilarExpr:
+		// With a threshold of zero, strings that share no trigrams with the
+		// constant are similar to it as well, so the index cannot be used.
+		if evalCtx.SessionData.TrigramSimilarityThreshold > 0 {
+			invertedExpr = t.extractTrigramSimilarCondition(e.Left, e.Right)
+		}
+	}
+
+	if invertedExpr == nil {
+		// An inverted expression could not be extracted.
+		return inverted.NonInvertedColExpression{}, expr, nil
+	}
+
+	// The extracted inverted expression is never tight, since containing the
+	// trigrams of a pattern or string does not imply matching it, so the
+	// original filter must be applied after the inverted index scan.
+	remainingFilters = expr
+
+	// We do not currently support pre-filtering for trigram indexes, so the
+	// returned pre-filter state is nil.
+	return invertedExpr, remainingFilters, nil
+}
+
+// extractLikeCondition extracts an InvertedExpression representing an inverted
+// filter over the planner's inverted index, based on the arguments of a LIKE
+// or ILIKE operator. The left argument must be the indexed string column and
+// the right a constant pattern. Returns nil if no inverted filter could be
+// extracted.
+func (t *trigramFilterPlanner) extractLikeCondition(left, right opt.ScalarExpr) inverted.Expression {
+	if !isIndexColumn(t.tabID, t.index, left, t.computedColumns) || !memo.CanExtractConstDatum(right) {
+		return nil
+	}
+	pattern, ok := memo.ExtractConstDatum(right).(*tree.DString)
+	if !ok {
+		return nil
+	}
+	return trigram.EncodeLikeInvertedIndexSpans(string(*pattern))
+}
+
+// extractTrigramSimilarCondition extracts an InvertedExpression representing
+// an inverted filter over the planner's inverted index, based on the arguments
+// of a % operator. One of the arguments must be the indexed string column and
+// the other a constant string. Returns nil if no inverted filter could be
+// extracted.
+func (t *trigramFilterPlanner) extractTrigramSimilarCondition(
+	left, right opt.ScalarExpr,
+) inverted.Expression {
+	var constantVal opt.ScalarExpr
+	if isIndexColumn(t.tabID, t.index, left, t.computedColumns) && memo.CanExtractConstDatum(right) {
+		constantVal = right
+	} else if isIndexColumn(t.tabID, t.index, right, t.computedColumns) && memo.CanExtractConstDatum(left) {
+		constantVal = left
+	} else {
+		return nil
+	}
+	s, ok := memo.ExtractConstDatum(constantVal).(*tree.DString)
+	if !ok {
+		return nil
+	}
+	return trigram.EncodeSimilarInvertedIndexSpans(string(*s))
+}
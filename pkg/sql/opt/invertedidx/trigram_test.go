@@ -0,0 +1,151 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedidx"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+func TestTryFilterTrigramIndex(t *testing.T) {
+	semaCtx := tree.MakeSemaContext()
+	evalCtx := tree.NewTestingEvalContext(nil /* st */)
+	evalCtx.SessionData.TrigramSimilarityThreshold = 0.3
+
+	tc := testcat.New()
+	if _, err := tc.ExecuteDDL(
+		"CREATE TABLE t (s STRING, INVERTED INDEX (s gin_trgm_ops))",
+	); err != nil {
+		t.Fatal(err)
+	}
+	var f norm.Factory
+	f.Init(evalCtx, tc)
+	md := f.Metadata()
+	tn := tree.NewUnqualifiedTableName("t")
+	tab := md.AddTable(tc.Table(tn), tn)
+	const indexOrd = 1
+
+	// The trigram spans are never tight, so the original filters always remain.
+	testCases := []struct {
+		filters          string
+		ok               bool
+		unique           bool
+		remainingFilters string
+	}{
+		{
+			filters:          "s LIKE '%foo%'",
+			ok:               true,
+			unique:           true,
+			remainingFilters: "s LIKE '%foo%'",
+		},
+		{
+			filters:          "s LIKE 'foobar%'",
+			ok:               true,
+			unique:           true,
+			remainingFilters: "s LIKE 'foobar%'",
+		},
+		{
+			filters:          "s ILIKE '%Foo%'",
+			ok:               true,
+			unique:           true,
+			remainingFilters: "s ILIKE '%Foo%'",
+		},
+		{
+			filters:          "s % 'foo'",
+			ok:               true,
+			unique:           false,
+			remainingFilters: "s % 'foo'",
+		},
+		{
+			// The operands of % can be in either order.
+			filters:          "'foo' % s",
+			ok:               true,
+			unique:           false,
+			remainingFilters: "'foo' % s",
+		},
+		{
+			filters:          "s LIKE '%foo%' OR s LIKE '%bar%'",
+			ok:               true,
+			unique:           false,
+			remainingFilters: "s LIKE '%foo%' OR s LIKE '%bar%'",
+		},
+		{
+			// The pattern is too short to contain a trigram.
+			filters: "s LIKE '%fo%'",
+			ok:      false,
+		},
+		{
+			// The pattern must be constant.
+			filters: "s LIKE s",
+			ok:      false,
+		},
+		{
+			// The indexed column must be the left operand of LIKE.
+			filters: "'foo' LIKE s",
+			ok:      false,
+		},
+		{
+			filters: "s NOT LIKE '%foo%'",
+			ok:      false,
+		},
+		{
+			filters: "s = 'foo'",
+			ok:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Logf("test case: %v", tc)
+		filters := testutils.BuildFilters(t, &f, &semaCtx, evalCtx, tc.filters)
+
+		spanExpr, _, remainingFilters, _, ok := invertedidx.TryFilterInvertedIndex(
+			evalCtx,
+			&f,
+			filters,
+			nil, /* optionalFilters */
+			tab,
+			md.Table(tab).Index(indexOrd),
+			nil, /* computedColumns */
+		)
+		if tc.ok != ok {
+			t.Fatalf("expected %v, got %v", tc.ok, ok)
+		}
+		if !ok {
+			continue
+		}
+
+		if spanExpr.Tight {
+			t.Fatalf("expected tight=false, but got true")
+		}
+		if tc.unique != spanExpr.Unique {
+			t.Fatalf("expected unique=%v, but got %v", tc.unique, spanExpr.Unique)
+		}
+
+		if remainingFilters == nil {
+			if tc.remainingFilters != "" {
+				t.Fatalf("expected remainingFilters=%s, got <nil>", tc.remainingFilters)
+			}
+			continue
+		}
+		if tc.remainingFilters == "" {
+			t.Fatalf("expected remainingFilters=<nil>, got %v", remainingFilters)
+		}
+		expRemainingFilters := testutils.BuildFilters(t, &f, &semaCtx, evalCtx, tc.remainingFilters)
+		if remainingFilters.String() != expRemainingFilters.String() {
+			t.Errorf("expected remainingFilters=%v, got %v", expRemainingFilters, remainingFilters)
+		}
+	}
+}
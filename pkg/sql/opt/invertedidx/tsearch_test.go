@@ -0,0 +1,158 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedidx"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+func TestTryFilterTSVectorIndex(t *testing.T) {
+	semaCtx := tree.MakeSemaContext()
+	evalCtx := tree.NewTestingEvalContext(nil /* st */)
+
+	tc := testcat.New()
+	if _, err := tc.ExecuteDDL(
+		"CREATE TABLE t (v TSVECTOR, INVERTED INDEX (v))",
+	); err != nil {
+		t.Fatal(err)
+	}
+	var f norm.Factory
+	f.Init(evalCtx, tc)
+	md := f.Metadata()
+	tn := tree.NewUnqualifiedTableName("t")
+	tab := md.AddTable(tc.Table(tn), tn)
+	const indexOrd = 1
+
+	testCases := []struct {
+		filters          string
+		ok               bool
+		tight            bool
+		unique           bool
+		remainingFilters string
+	}{
+		{
+			filters: "v @@ 'cat'",
+			ok:      true,
+			tight:   true,
+			unique:  true,
+		},
+		{
+			// The operands of @@ can be in either order.
+			filters: "'cat'::TSQUERY @@ v",
+			ok:      true,
+			tight:   true,
+			unique:  true,
+		},
+		{
+			filters: "v @@ 'cat & rat'",
+			ok:      true,
+			tight:   true,
+			unique:  true,
+		},
+		{
+			filters: "v @@ 'cat | ra:*'",
+			ok:      true,
+			tight:   true,
+			unique:  false,
+		},
+		{
+			// Weights are not stored in the index.
+			filters:          "v @@ 'cat:A'",
+			ok:               true,
+			tight:            false,
+			unique:           true,
+			remainingFilters: "v @@ 'cat:A'",
+		},
+		{
+			// Positions are not stored in the index.
+			filters:          "v @@ 'fat <-> cat'",
+			ok:               true,
+			tight:            false,
+			unique:           true,
+			remainingFilters: "v @@ 'fat <-> cat'",
+		},
+		{
+			filters:          "v @@ 'cat & !rat'",
+			ok:               true,
+			tight:            false,
+			unique:           true,
+			remainingFilters: "v @@ 'cat & !rat'",
+		},
+		{
+			filters: "v @@ 'cat' OR v @@ 'rat'",
+			ok:      true,
+			tight:   true,
+			unique:  false,
+		},
+		{
+			// A negated query cannot be evaluated using the index.
+			filters: "v @@ '!cat'",
+			ok:      false,
+		},
+		{
+			// Nor can a query that contains only stopwords.
+			filters: "v @@ ''",
+			ok:      false,
+		},
+		{
+			filters: "v @@ 'cat' OR v IS NULL",
+			ok:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Logf("test case: %v", tc)
+		filters := testutils.BuildFilters(t, &f, &semaCtx, evalCtx, tc.filters)
+
+		spanExpr, _, remainingFilters, _, ok := invertedidx.TryFilterInvertedIndex(
+			evalCtx,
+			&f,
+			filters,
+			nil, /* optionalFilters */
+			tab,
+			md.Table(tab).Index(indexOrd),
+			nil, /* computedColumns */
+		)
+		if tc.ok != ok {
+			t.Fatalf("expected %v, got %v", tc.ok, ok)
+		}
+		if !ok {
+			continue
+		}
+
+		if tc.tight != spanExpr.Tight {
+			t.Fatalf("expected tight=%v, but got %v", tc.tight, spanExpr.Tight)
+		}
+		if tc.unique != spanExpr.Unique {
+			t.Fatalf("expected unique=%v, but got %v", tc.unique, spanExpr.Unique)
+		}
+
+		if remainingFilters == nil {
+			if tc.remainingFilters != "" {
+				t.Fatalf("expected remainingFilters=%s, got <nil>", tc.remainingFilters)
+			}
+			continue
+		}
+		if tc.remainingFilters == "" {
+			t.Fatalf("expected remainingFilters=<nil>, got %v", remainingFilters)
+		}
+		expRemainingFilters := testutils.BuildFilters(t, &f, &semaCtx, evalCtx, tc.remainingFilters)
+		if remainingFilters.String() != expRemainingFilters.String() {
+			t.Errorf("expected remainingFilters=%v, got %v", expRemainingFilters, remainingFilters)
+		}
+	}
+}
@@ -10,7 +10,11 @@
 
 package memo
 
-import "math"
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+)
 
 // Cost is the best-effort approximation of the actual cost of executing a
 // particular operator tree.
@@ -37,6 +41,60 @@ func (c Cost) Less(other Cost) bool {
 	return math.Float64bits(float64(c))+ulpTolerance <= math.Float64bits(float64(other))
 }
 
+// The following settings are the coefficients of the cost model, i.e. the
+// estimated costs of the basic units of work that the coster in the xform
+// package assigns to operators. The default values have been copied from the
+// Postgres optimizer:
+// https://github.com/postgres/postgres/blob/master/src/include/optimizer/cost.h
+//
+// Only the ratios between the coefficients affect plan choice, so they can be
+// calibrated to the hardware of a cluster (see the costcalibration package).
+// Changing them invalidates cached memos.
+
+// CPUCostFactor is the cost of processing one row in one operator.
+var CPUCostFactor = settings.RegisterFloatSetting(
+	"sql.optimizer.cost_model.cpu_cost_factor",
+	"estimated cost of processing a row in an operator, relative to the other cost model factors",
+	0.01,
+	settings.PositiveFloat,
+)
+
+// SeqIOCostFactor is the cost of reading one row sequentially from the KV
+// layer, e.g. during a scan.
+var SeqIOCostFactor = settings.RegisterFloatSetting(
+	"sql.optimizer.cost_model.seq_io_cost_factor",
+	"estimated cost of reading a row sequentially, relative to the other cost model factors",
+	1,
+	settings.PositiveFloat,
+)
+
+// RandIOCostFactor is the cost of seeking to a random key in the KV layer,
+// e.g. for each span of a scan or each lookup of a lookup join.
+var RandIOCostFactor = settings.RegisterFloatSetting(
+	"sql.optimizer.cost_model.rand_io_cost_factor",
+	"estimated cost of a random seek, relative to the other cost model factors",
+	4,
+	settings.PositiveFloat,
+)
+
+// CostFactors are the values of the cost model coefficients that a memo is
+// optimized with.
+type CostFactors struct {
+	CPU    Cost
+	SeqIO  Cost
+	RandIO Cost
+}
+
+// MakeCostFactors returns the cost model coefficients set in the given
+// settings.
+func MakeCostFactors(sv *settings.Values) CostFactors {
+	return CostFactors{
+		CPU:    Cost(CPUCostFactor.Get(sv)),
+		SeqIO:  Cost(SeqIOCostFactor.Get(sv)),
+		RandIO: Cost(RandIOCostFactor.Get(sv)),
+	}
+}
+
 // Sub subtracts the other cost from this cost and returns the result.
 func (c Cost) Sub(other Cost) Cost {
 	return c - other
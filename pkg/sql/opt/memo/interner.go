@@ -559,6 +559,14 @@ func (h *hasher) HashViewDeps(val opt.ViewDeps) {
 	}
 }
 
+func (h *hasher) HashStorageParams(val tree.StorageParams) {
+	// Hash the length and address of the first element.
+	h.HashInt(len(val))
+	if len(val) > 0 {
+		h.HashPointer(unsafe.Pointer(&val[0]))
+	}
+}
+
 func (h *hasher) HashViewTypeDeps(val opt.ViewTypeDeps) {
 	hash := h.hash
 	val.ForEach(func(i int) {
@@ -970,6 +978,13 @@ func (h *hasher) IsViewDepsEqual(l, r opt.ViewDeps) bool {
 	return len(l) == 0 || &l[0] == &r[0]
 }
 
+func (h *hasher) IsStorageParamsEqual(l, r tree.StorageParams) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	return len(l) == 0 || &l[0] == &r[0]
+}
+
 func (h *hasher) IsViewTypeDepsEqual(l, r opt.ViewTypeDeps) bool {
 	return l.Equals(r)
 }
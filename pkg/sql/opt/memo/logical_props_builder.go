@@ -1737,6 +1737,13 @@ func MakeTableFuncDep(md *opt.Metadata, tabID opt.TableID) *props.FuncDepSet {
 				continue
 			}
 
+			if unique.Deferrability() != tree.NotDeferrableConstraint {
+				// The check for a deferrable constraint may be postponed until the
+				// end of the transaction, so duplicates can be visible in the
+				// meantime.
+				continue
+			}
+
 			if _, isPartial := unique.Predicate(); isPartial {
 				// Partial constraints cannot be considered while building functional
 				// dependency keys for the table because their keys are only unique
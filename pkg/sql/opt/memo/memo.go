@@ -135,10 +135,18 @@ type Memo struct {
 	zigzagJoinEnabled       bool
 	useHistograms           bool
 	useMultiColStats        bool
+	useForecasts            bool
 	localityOptimizedSearch bool
 	safeUpdates             bool
 	preferLookupJoinsForFKs bool
 	saveTablesPrefix        string
+	trigramThreshold        float64
+	allowMatViewMutations   bool
+
+	// costFactors are the cost model coefficients from the cluster settings.
+	// Like the fields above, they need to be cross-checked before reusing a
+	// cached memo.
+	costFactors CostFactors
 
 	// curID is the highest currently in-use scalar expression ID.
 	curID opt.ScalarID
@@ -173,10 +181,14 @@ func (m *Memo) Init(evalCtx *tree.EvalContext) {
 		zigzagJoinEnabled:       evalCtx.SessionData.ZigzagJoinEnabled,
 		useHistograms:           evalCtx.SessionData.OptimizerUseHistograms,
 		useMultiColStats:        evalCtx.SessionData.OptimizerUseMultiColStats,
+		useForecasts:            evalCtx.SessionData.OptimizerUseForecasts,
 		localityOptimizedSearch: evalCtx.SessionData.LocalityOptimizedSearch,
 		safeUpdates:             evalCtx.SessionData.SafeUpdates,
 		preferLookupJoinsForFKs: evalCtx.SessionData.PreferLookupJoinsForFKs,
 		saveTablesPrefix:        evalCtx.SessionData.SaveTablesPrefix,
+		trigramThreshold:        evalCtx.SessionData.TrigramSimilarityThreshold,
+		allowMatViewMutations:   evalCtx.SessionData.AllowMaterializedViewMutations,
+		costFactors:             MakeCostFactors(&evalCtx.Settings.SV),
 	}
 	m.metadata.Init()
 	m.logPropsBuilder.init(evalCtx, m)
@@ -209,6 +221,12 @@ func (m *Memo) Metadata() *opt.Metadata {
 	return &m.metadata
 }
 
+// CostFactors returns the cost model coefficients that the memo is optimized
+// with.
+func (m *Memo) CostFactors() CostFactors {
+	return m.costFactors
+}
+
 // RootExpr returns the root memo expression previously set via a call to
 // SetRoot.
 func (m *Memo) RootExpr() opt.Expr {
@@ -281,10 +299,18 @@ func (m *Memo) IsStale(
 		m.zigzagJoinEnabled != evalCtx.SessionData.ZigzagJoinEnabled ||
 		m.useHistograms != evalCtx.SessionData.OptimizerUseHistograms ||
 		m.useMultiColStats != evalCtx.SessionData.OptimizerUseMultiColStats ||
+		m.useForecasts != evalCtx.SessionData.OptimizerUseForecasts ||
 		m.localityOptimizedSearch != evalCtx.SessionData.LocalityOptimizedSearch ||
 		m.safeUpdates != evalCtx.SessionData.SafeUpdates ||
 		m.preferLookupJoinsForFKs != evalCtx.SessionData.PreferLookupJoinsForFKs ||
-		m.saveTablesPrefix != evalCtx.SessionData.SaveTablesPrefix {
+		m.saveTablesPrefix != evalCtx.SessionData.SaveTablesPrefix ||
+		m.trigramThreshold != evalCtx.SessionData.TrigramSimilarityThreshold ||
+		m.allowMatViewMutations != evalCtx.SessionData.AllowMaterializedViewMutations {
+		return true, nil
+	}
+
+	// Memo is stale if the cost model coefficients have changed.
+	if m.costFactors != MakeCostFactors(&evalCtx.Settings.SV) {
 		return true, nil
 	}
 
@@ -204,6 +204,12 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData.OptimizerUseMultiColStats = false
 	notStale()
 
+	// Stale optimizer forecast usage enable.
+	evalCtx.SessionData.OptimizerUseForecasts = true
+	stale()
+	evalCtx.SessionData.OptimizerUseForecasts = false
+	notStale()
+
 	// Stale locality optimized search enable.
 	evalCtx.SessionData.LocalityOptimizedSearch = true
 	stale()
@@ -222,6 +228,24 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData.PreferLookupJoinsForFKs = false
 	notStale()
 
+	// Stale trigram similarity threshold.
+	evalCtx.SessionData.TrigramSimilarityThreshold = 0.5
+	stale()
+	evalCtx.SessionData.TrigramSimilarityThreshold = 0
+	notStale()
+
+	// Stale materialized view mutations allowance.
+	evalCtx.SessionData.AllowMaterializedViewMutations = true
+	stale()
+	evalCtx.SessionData.AllowMaterializedViewMutations = false
+	notStale()
+
+	// Stale cost model coefficients.
+	memo.RandIOCostFactor.Override(&evalCtx.Settings.SV, 10)
+	stale()
+	memo.RandIOCostFactor.Override(&evalCtx.Settings.SV, memo.RandIOCostFactor.Default())
+	notStale()
+
 	// Stale data sources and schema. Create new catalog so that data sources are
 	// recreated and can be modified independently.
 	catalog = testcat.New()
@@ -13,6 +13,7 @@ package memo
 import (
 	"math"
 	"reflect"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -530,7 +531,15 @@ func (sb *statisticsBuilder) makeTableStatistics(tabID opt.TableID) *props.Stati
 
 	// Make now and annotate the metadata table with it for next time.
 	stats = &props.Statistics{}
-	if tab.StatisticCount() == 0 {
+	// Skip any forecasted statistics if they are disabled. Forecasts are newer
+	// than all collected statistics, so they come first.
+	first := 0
+	if !sb.evalCtx.SessionData.OptimizerUseForecasts {
+		for first < tab.StatisticCount() && tab.Statistic(first).IsForecast() {
+			first++
+		}
+	}
+	if first == tab.StatisticCount() {
 		// No statistics.
 		stats.Available = false
 		stats.RowCount = unknownRowCount
@@ -538,7 +547,7 @@ func (sb *statisticsBuilder) makeTableStatistics(tabID opt.TableID) *props.Stati
 		// Get the RowCount from the most recent statistic. Stats are ordered
 		// with most recent first.
 		stats.Available = true
-		stats.RowCount = float64(tab.Statistic(0).RowCount())
+		stats.RowCount = float64(tab.Statistic(first).RowCount())
 
 		// Make sure the row count is at least 1. The stats may be stale, and we
 		// can end up with weird and inefficient plans if we estimate 0 rows.
@@ -546,11 +555,14 @@ func (sb *statisticsBuilder) makeTableStatistics(tabID opt.TableID) *props.Stati
 
 		// Add all the column statistics, using the most recent statistic for each
 		// column set. Stats are ordered with most recent first.
-		for i := 0; i < tab.StatisticCount(); i++ {
+		for i := first; i < tab.StatisticCount(); i++ {
 			stat := tab.Statistic(i)
 			if stat.ColumnCount() > 1 && !sb.evalCtx.SessionData.OptimizerUseMultiColStats {
 				continue
 			}
+			if stat.IsForecast() && !sb.evalCtx.SessionData.OptimizerUseForecasts {
+				continue
+			}
 
 			var cols opt.ColSet
 			for i := 0; i < stat.ColumnCount(); i++ {
@@ -560,6 +572,17 @@ func (sb *statisticsBuilder) makeTableStatistics(tabID opt.TableID) *props.Stati
 			if colStat, ok := stats.ColStats.Add(cols); ok {
 				colStat.DistinctCount = float64(stat.DistinctCount())
 				colStat.NullCount = float64(stat.NullCount())
+				if cols.Len() > 1 && stat.Histogram() != nil &&
+					sb.evalCtx.SessionData.OptimizerUseHistograms {
+					// The upper bounds of a multi-column histogram are tuples with the
+					// values of the columns in the order of the statistic.
+					colList := make(opt.ColList, stat.ColumnCount())
+					for i := range colList {
+						colList[i] = tabID.ColumnID(stat.ColumnOrdinal(i))
+					}
+					colStat.Histogram = &props.Histogram{}
+					colStat.Histogram.InitMultiCol(sb.evalCtx, colList, stat.Histogram())
+				}
 				if cols.Len() == 1 && stat.Histogram() != nil &&
 					sb.evalCtx.SessionData.OptimizerUseHistograms {
 					col, _ := cols.Next(0)
@@ -806,10 +829,22 @@ func (sb *statisticsBuilder) constrainScan(
 
 	// Calculate row count and selectivity
 	// -----------------------------------
+	multiColHistConstraints := tightConstraints(pred)
+	if constraint != nil {
+		multiColHistConstraints = append(multiColHistConstraints, constraint)
+	}
+	multiColHistSelectivity, multiColHistCols := sb.selectivityFromMultiColHistograms(
+		multiColHistConstraints, scan, s,
+	)
+	nullsRemovedIgnoreCols := constrainedCols.Union(multiColHistCols)
+	histCols = histCols.Difference(multiColHistCols)
+	constrainedCols = constrainedCols.Difference(multiColHistCols)
+
+	s.ApplySelectivity(multiColHistSelectivity)
 	s.ApplySelectivity(sb.selectivityFromHistograms(histCols, scan, s))
 	s.ApplySelectivity(sb.selectivityFromMultiColDistinctCounts(constrainedCols, scan, s))
 	s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(numUnappliedConjuncts))
-	s.ApplySelectivity(sb.selectivityFromNullsRemoved(scan, notNullCols, constrainedCols))
+	s.ApplySelectivity(sb.selectivityFromNullsRemoved(scan, notNullCols, nullsRemovedIgnoreCols))
 
 	// Adjust the selectivity so we don't double-count the histogram columns.
 	s.UnapplySelectivity(sb.selectivityFromSingleColDistinctCounts(histCols, scan, s))
@@ -2780,6 +2815,11 @@ const (
 	// statistics. See the comment above selectivityFromMultiColDistinctCounts for
 	// details.
 	multiColWeight = 9.0 / 10.0
+
+	// maxMultiColHistogramTuples is the maximum number of tuples of values that
+	// are looked up in a multi-column histogram to estimate the selectivity of
+	// a filter. See selectivityFromMultiColHistograms.
+	maxMultiColHistogramTuples = 100
 )
 
 // countPaths returns the number of JSON or Array paths in the specified
@@ -2856,11 +2896,19 @@ func (sb *statisticsBuilder) filterRelExpr(
 
 	// Calculate row count and selectivity
 	// -----------------------------------
+	multiColHistSelectivity, multiColHistCols := sb.selectivityFromMultiColHistograms(
+		tightConstraints(filters), e, s,
+	)
+	nullsRemovedIgnoreCols := constrainedCols.Union(multiColHistCols)
+	histCols = histCols.Difference(multiColHistCols)
+	constrainedCols = constrainedCols.Difference(multiColHistCols)
+
+	s.ApplySelectivity(multiColHistSelectivity)
 	s.ApplySelectivity(sb.selectivityFromHistograms(histCols, e, s))
 	s.ApplySelectivity(sb.selectivityFromMultiColDistinctCounts(constrainedCols, e, s))
 	s.ApplySelectivity(sb.selectivityFromEquivalencies(equivReps, &relProps.FuncDeps, e, s))
 	s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(numUnappliedConjuncts))
-	s.ApplySelectivity(sb.selectivityFromNullsRemoved(e, notNullCols, constrainedCols))
+	s.ApplySelectivity(sb.selectivityFromNullsRemoved(e, notNullCols, nullsRemovedIgnoreCols))
 
 	// Adjust the selectivity so we don't double-count the histogram columns.
 	s.UnapplySelectivity(sb.selectivityFromSingleColDistinctCounts(histCols, e, s))
@@ -3665,6 +3713,184 @@ func (sb *statisticsBuilder) selectivityFromHistograms(
 	return selectivity
 }
 
+// selectivityFromMultiColHistograms calculates the selectivity of filters that
+// constrain all the columns of a multi-column histogram to a finite set of
+// values, such as country = 'FR' AND city = 'Paris'. The number of rows with
+// each combination of values is estimated from the histogram, which captures
+// any correlation between the columns. Assuming independence between the
+// columns instead can underestimate the row count by orders of magnitude.
+//
+// Only tight constraints are used. The returned columns are the columns whose
+// selectivity has been accounted for; they should not be used in the other
+// selectivity calculations for the same filters.
+func (sb *statisticsBuilder) selectivityFromMultiColHistograms(
+	constraints []*constraint.Constraint, e RelExpr, s *props.Statistics,
+) (selectivity props.Selectivity, cols opt.ColSet) {
+	selectivity = props.OneSelectivity
+	if len(constraints) == 0 || !sb.evalCtx.SessionData.OptimizerUseMultiColStats ||
+		!sb.evalCtx.SessionData.OptimizerUseHistograms {
+		return selectivity, cols
+	}
+
+	// Find the multi-column histograms on the constrained columns.
+	var constrainedCols opt.ColSet
+	for _, c := range constraints {
+		for i, n := 0, c.Prefix(sb.evalCtx); i < n; i++ {
+			constrainedCols.Add(c.Columns.Get(i).ID())
+		}
+	}
+	var candidates []*props.ColumnStatistic
+	var tables []opt.TableID
+	constrainedCols.ForEach(func(col opt.ColumnID) {
+		tabID := sb.md.ColumnMeta(col).Table
+		if tabID == 0 {
+			return
+		}
+		for _, t := range tables {
+			if t == tabID {
+				return
+			}
+		}
+		tables = append(tables, tabID)
+		tableStats := sb.makeTableStatistics(tabID)
+		for i := 0; i < tableStats.ColStats.Count(); i++ {
+			colStat := tableStats.ColStats.Get(i)
+			if colStat.Histogram != nil && colStat.Cols.Len() > 1 &&
+				colStat.Cols.SubsetOf(constrainedCols) {
+				candidates = append(candidates, colStat)
+			}
+		}
+	})
+
+	// Prefer the histograms with the most columns.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Cols.Len() > candidates[j].Cols.Len()
+	})
+
+	for _, tableColStat := range candidates {
+		if tableColStat.Cols.Intersects(cols) {
+			continue
+		}
+		tuples, ok := sb.multiColHistogramTuples(tableColStat.Histogram.Cols(), constraints)
+		if !ok {
+			continue
+		}
+		inputColStat, inputStats := sb.colStatFromInput(tableColStat.Cols, e)
+		inputHist := inputColStat.Histogram
+		if inputHist == nil {
+			continue
+		}
+		oldCount := inputHist.ValuesCount()
+		if oldCount == 0 {
+			continue
+		}
+		var newCount float64
+		for _, tuple := range tuples {
+			newCount += inputHist.EqValuesCount(tuple)
+		}
+
+		// The filters reject rows with NULL values on all of the columns.
+		nonNullSelectivity := props.MakeSelectivityFromFraction(newCount, oldCount)
+		selectivity.Multiply(sb.predicateSelectivity(
+			nonNullSelectivity, props.ZeroSelectivity, inputColStat.NullCount, inputStats.RowCount,
+		))
+		cols.UnionWith(tableColStat.Cols)
+	}
+
+	return selectivity, cols
+}
+
+// multiColHistogramTuples returns the tuples of values of the given columns
+// that satisfy the constraints. It returns ok=false if the constraints do not
+// restrict each of the columns to a finite set of non-NULL values, or if there
+// would be more than maxMultiColHistogramTuples tuples.
+func (sb *statisticsBuilder) multiColHistogramTuples(
+	cols opt.ColList, constraints []*constraint.Constraint,
+) (tuples []tree.Datum, ok bool) {
+	vals := []tree.Datums{make(tree.Datums, len(cols))}
+	var assigned opt.ColSet
+	for _, c := range constraints {
+		// Use the leading columns of the constraint that have a single value in
+		// each span and that belong to the histogram.
+		n, prefix := 0, c.Prefix(sb.evalCtx)
+		for ; n < prefix; n++ {
+			col := c.Columns.Get(n).ID()
+			if _, found := cols.Find(col); !found || assigned.Contains(col) {
+				break
+			}
+		}
+		if n == 0 {
+			continue
+		}
+
+		// Collect the distinct combinations of values of those columns. The
+		// spans are ordered, so duplicate combinations are adjacent.
+		var combos []tree.Datums
+		for i := 0; i < c.Spans.Count(); i++ {
+			key := c.Spans.Get(i).StartKey()
+			combo := make(tree.Datums, n)
+			for j := range combo {
+				if combo[j] = key.Value(j); combo[j] == tree.DNull {
+					return nil, false
+				}
+			}
+			if len(combos) > 0 && combos[len(combos)-1].Compare(sb.evalCtx, combo) == 0 {
+				continue
+			}
+			combos = append(combos, combo)
+		}
+		if len(vals)*len(combos) > maxMultiColHistogramTuples {
+			return nil, false
+		}
+
+		newVals := make([]tree.Datums, 0, len(vals)*len(combos))
+		for _, v := range vals {
+			for _, combo := range combos {
+				newVal := append(tree.Datums(nil), v...)
+				for j := range combo {
+					ord, _ := cols.Find(c.Columns.Get(j).ID())
+					newVal[ord] = combo[j]
+				}
+				newVals = append(newVals, newVal)
+			}
+		}
+		vals = newVals
+		for j := 0; j < n; j++ {
+			assigned.Add(c.Columns.Get(j).ID())
+		}
+	}
+	if assigned.Len() != len(cols) {
+		return nil, false
+	}
+
+	contents := make([]*types.T, len(cols))
+	for i, col := range cols {
+		contents[i] = sb.md.ColumnMeta(col).Type
+	}
+	typ := types.MakeTuple(contents)
+	tuples = make([]tree.Datum, len(vals))
+	for i := range vals {
+		tuples[i] = tree.NewDTuple(typ, vals[i]...)
+	}
+	return tuples, true
+}
+
+// tightConstraints returns the constraints of the filters that have tight
+// constraints.
+func tightConstraints(filters FiltersExpr) []*constraint.Constraint {
+	var res []*constraint.Constraint
+	for i := range filters {
+		scalarProps := filters[i].ScalarProps()
+		if scalarProps.Constraints == nil || !scalarProps.TightConstraints {
+			continue
+		}
+		for j := 0; j < scalarProps.Constraints.Length(); j++ {
+			res = append(res, scalarProps.Constraints.Constraint(j))
+		}
+	}
+	return res
+}
+
 // selectivityFromNullsRemoved calculates the selectivity from null-rejecting
 // filters that were not already accounted for in selectivityFromMultiColDistinctCounts
 // or selectivityFromHistograms. The columns for filters already accounted for
@@ -141,6 +141,8 @@ var ComparisonOpReverseMap = map[Operator]tree.ComparisonOperator{
 	JsonSomeExistsOp: tree.JSONSomeExists,
 	JsonAllExistsOp:  tree.JSONAllExists,
 	OverlapsOp:       tree.Overlaps,
+	TSMatchesOp:      tree.TSMatches,
+	TrigramSimilarOp: tree.TrigramSimilar,
 	BBoxCoversOp:     tree.RegMatch,
 	BBoxIntersectsOp: tree.Overlaps,
 }
@@ -68,6 +68,11 @@ type Builder struct {
 	// This is used when re-preparing invalidated queries.
 	KeepPlaceholders bool
 
+	// Hints is a control knob: if set, the index hints it contains are applied
+	// to the scans of the tables they reference, unless the table expressions
+	// have explicit index flags.
+	Hints *tree.StatementHints
+
 	// -- Results --
 	//
 	// These fields are set during the building process and can be used after
@@ -261,7 +266,7 @@ func (b *Builder) buildStmt(
 	if b.insideViewDef {
 		// A blocklist of statements that can't be used from inside a view.
 		switch stmt := stmt.(type) {
-		case *tree.Delete, *tree.Insert, *tree.Update, *tree.CreateTable, *tree.CreateView,
+		case *tree.Delete, *tree.Insert, *tree.Merge, *tree.Update, *tree.CreateTable, *tree.CreateView,
 			*tree.Split, *tree.Unsplit, *tree.Relocate,
 			*tree.ControlJobs, *tree.ControlSchedules, *tree.CancelQueries, *tree.CancelSessions:
 			panic(pgerror.Newf(
@@ -292,6 +297,15 @@ func (b *Builder) buildStmt(
 			return b.buildUpdate(stmt, inScope)
 		})
 
+	case *tree.Merge:
+		// MERGE is planned using CTEs, so it must be at the top level.
+		if !inScope.atRoot {
+			panic(pgerror.Newf(pgcode.FeatureNotSupported, "MERGE must be used at the top level"))
+		}
+		return b.processWiths(stmt.With, inScope, func(inScope *scope) *scope {
+			return b.buildMerge(stmt, inScope)
+		})
+
 	case *tree.CreateTable:
 		return b.buildCreateTable(stmt, inScope)
 
@@ -336,6 +350,9 @@ func (b *Builder) buildStmt(
 		// ANALYZE is syntactic sugar for CREATE STATISTICS.
 		return b.buildCreateStatistics(&tree.CreateStats{Table: stmt.Table}, inScope)
 
+	case *tree.CopyTo:
+		return b.buildCopyTo(stmt, inScope)
+
 	case *tree.Export:
 		return b.buildExport(stmt, inScope)
 
@@ -0,0 +1,43 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+
+// buildCopyTo builds a COPY TO statement. The rows produced by the query (or
+// read from the table) are the result of the statement; encoding them in the
+// requested format is left to the connection.
+func (b *Builder) buildCopyTo(copyTo *tree.CopyTo, inScope *scope) (outScope *scope) {
+	stmt := copyTo.Statement
+	if stmt == nil {
+		// COPY t (a, b) TO STDOUT is equivalent to
+		// COPY (SELECT a, b FROM t) TO STDOUT.
+		exprs := tree.SelectExprs{tree.StarSelectExpr()}
+		if len(copyTo.Columns) > 0 {
+			exprs = make(tree.SelectExprs, len(copyTo.Columns))
+			for i := range copyTo.Columns {
+				exprs[i] = tree.SelectExpr{
+					Expr: &tree.UnresolvedName{NumParts: 1, Parts: tree.NameParts{string(copyTo.Columns[i])}},
+				}
+			}
+		}
+		tn := copyTo.Table
+		stmt = &tree.Select{
+			Select: &tree.SelectClause{
+				Exprs: exprs,
+				From:  tree.From{Tables: tree.TableExprs{&tn}},
+			},
+		}
+	}
+	// The query can't reference outer columns, so we pass a "blank" scope
+	// rather than inScope.
+	return b.buildStmt(stmt, nil /* desiredTypes */, b.allocScope())
+}
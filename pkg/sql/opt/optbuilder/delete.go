@@ -55,11 +55,11 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 	// Build the input expression that selects the rows that will be deleted:
 	//
 	//   WITH <with>
-	//   SELECT <cols> FROM <table> WHERE <where>
+	//   SELECT <cols> FROM <table> [, <using>] WHERE <where>
 	//   ORDER BY <order-by> LIMIT <limit>
 	//
 	// All columns from the delete table will be projected.
-	mb.buildInputForDelete(inScope, del.Table, del.Where, del.Limit, del.OrderBy)
+	mb.buildInputForDelete(inScope, del.Table, del.Using, del.Where, del.Limit, del.OrderBy)
 
 	// Build the final delete statement, including any returned expressions.
 	if resultsNeeded(del.Returning) {
@@ -80,6 +80,15 @@ func (mb *mutationBuilder) buildDelete(returning tree.ReturningExprs) {
 	mb.projectPartialIndexDelCols(mb.fetchScope)
 
 	private := mb.makeMutationPrivate(returning != nil)
+	if returning != nil {
+		// The RETURNING clause can refer to the columns of the USING tables, so
+		// they must be passed through the Delete.
+		for _, col := range mb.extraAccessibleCols {
+			if col.id != 0 {
+				private.PassthroughCols = append(private.PassthroughCols, col.id)
+			}
+		}
+	}
 	mb.outScope.expr = mb.b.factory.ConstructDelete(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
 	)
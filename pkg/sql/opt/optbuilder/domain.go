@@ -0,0 +1,161 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// checkDomainValues wraps each column of a domain type that is part of the
+// colIDs list (i.e. is not 0) with the functions that enforce the NOT NULL and
+// CHECK constraints of the domain. If a column is wrapped, then the list will
+// be updated with the column ID of the new synthesized column.
+func (mb *mutationBuilder) checkDomainValues(colIDs opt.OptionalColList) {
+	var projectionsScope *scope
+
+	for i, id := range colIDs {
+		if id == 0 {
+			// Column not mutated, so nothing to do.
+			continue
+		}
+
+		colType := mb.tab.Column(i).DatumType()
+		if !hasDomainConstraints(colType) {
+			continue
+		}
+
+		value := mb.b.factory.ConstructVariable(id)
+		checked := mb.b.buildDomainChecks(value, colType)
+
+		// Lazily create new scope and update the scope column to be checked.
+		if projectionsScope == nil {
+			projectionsScope = mb.outScope.replace()
+			projectionsScope.appendColumnsFromScope(mb.outScope)
+		}
+		scopeCol := projectionsScope.getColumn(id)
+		mb.b.populateSynthesizedColumn(scopeCol, checked)
+
+		// Overwrite the input column ID with the new synthesized column ID.
+		colIDs[i] = scopeCol.id
+
+		// Make sure that the new scope column keeps the name of the target column
+		// (see roundDecimalValues).
+		scopeCol.name = mb.tab.Column(i).ColName()
+	}
+
+	if projectionsScope != nil {
+		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+		mb.outScope = projectionsScope
+	}
+}
+
+// hasDomainConstraints returns true if typ is a domain with a NOT NULL or CHECK
+// constraint.
+func hasDomainConstraints(typ *types.T) bool {
+	if !typ.IsDomain() {
+		return false
+	}
+	domain := typ.TypeMeta.DomainData
+	return domain != nil && (domain.NotNull || len(domain.Checks) > 0)
+}
+
+// buildDomainChecks wraps the given scalar value of the domain type typ with
+// calls to the crdb_internal.check_domain_not_null and
+// crdb_internal.check_domain_constraint functions, which return their input
+// value unchanged if the constraints of the domain are satisfied, and error
+// otherwise.
+func (b *Builder) buildDomainChecks(value opt.ScalarExpr, typ *types.T) opt.ScalarExpr {
+	if !hasDomainConstraints(typ) {
+		return value
+	}
+	domain := typ.TypeMeta.DomainData
+	domainName := tree.NewDString(typ.Name())
+	valueExpr := &domainValue{typ: typ, scalar: value}
+
+	var expr tree.Expr = valueExpr
+	if domain.NotNull {
+		expr = &tree.FuncExpr{
+			Func:  tree.WrapFunction("crdb_internal.check_domain_not_null"),
+			Exprs: tree.Exprs{expr, domainName},
+		}
+	}
+	for i := range domain.Checks {
+		check, err := parser.ParseExpr(domain.Checks[i].Expr)
+		if err != nil {
+			panic(err)
+		}
+		check, err = schemaexpr.ReplaceDomainValue(check, valueExpr)
+		if err != nil {
+			panic(err)
+		}
+		expr = &tree.FuncExpr{
+			Func: tree.WrapFunction("crdb_internal.check_domain_constraint"),
+			Exprs: tree.Exprs{
+				expr, check, domainName, tree.NewDString(domain.Checks[i].Name),
+			},
+		}
+	}
+
+	// The checks only refer to the value, so they are built in an empty scope.
+	checkScope := b.allocScope()
+	texpr := checkScope.resolveAndRequireType(expr, typ)
+	return b.buildScalar(texpr, checkScope, nil, nil, nil)
+}
+
+// domainValue is a placeholder for the value of a domain type within the
+// expressions that enforce the constraints of the domain. It is built as the
+// given scalar expression.
+type domainValue struct {
+	typ    *types.T
+	scalar opt.ScalarExpr
+}
+
+var _ tree.TypedExpr = &domainValue{}
+
+// String implements the Stringer interface.
+func (v *domainValue) String() string {
+	return tree.AsString(v)
+}
+
+// Format implements the NodeFormatter interface.
+func (v *domainValue) Format(ctx *tree.FmtCtx) {
+	name := schemaexpr.DomainValueName
+	ctx.FormatNode(&name)
+}
+
+// Walk implements the Expr interface.
+func (v *domainValue) Walk(_ tree.Visitor) tree.Expr {
+	return v
+}
+
+// TypeCheck implements the Expr interface.
+func (v *domainValue) TypeCheck(
+	_ context.Context, _ *tree.SemaContext, _ *types.T,
+) (tree.TypedExpr, error) {
+	return v, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (*domainValue) Eval(_ *tree.EvalContext) (tree.Datum, error) {
+	panic(errors.AssertionFailedf("domainValue must be replaced before evaluation"))
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (v *domainValue) ResolvedType() *types.T {
+	return v.typ
+}
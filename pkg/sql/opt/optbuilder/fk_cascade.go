@@ -424,6 +424,7 @@ func (cb *onDeleteSetBuilder) Build(
 
 		var mb mutationBuilder
 		mb.init(b, "update", cb.childTable, tree.MakeUnqualifiedTableName(cb.childTable.Name()))
+		mb.bypassRowLevelSecurity = true
 
 		// Build a semi join of the table with the mutation input.
 		//
@@ -639,6 +640,7 @@ func (cb *onUpdateCascadeBuilder) Build(
 
 		var mb mutationBuilder
 		mb.init(b, "update", cb.childTable, tree.MakeUnqualifiedTableName(cb.childTable.Name()))
+		mb.bypassRowLevelSecurity = true
 
 		// Build a join of the table with the mutation input.
 		mb.outScope = b.buildUpdateCascadeMutationInput(
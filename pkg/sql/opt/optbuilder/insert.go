@@ -350,6 +350,8 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 //      values specified for them.
 //   4. Each update value is the same as the corresponding insert value.
 //   5. There are no inbound foreign keys containing non-key columns.
+//   6. The row-level security policies of the table do not apply to the user.
+//      Existing rows must be checked against the policies.
 //
 // TODO(andyk): The fast path is currently only enabled when the UPSERT alias
 // is explicitly selected by the user. It's possible to fast path some queries
@@ -361,6 +363,10 @@ func (mb *mutationBuilder) needExistingRows() bool {
 		return true
 	}
 
+	if mb.b.rowLevelSecurityApplies(mb.tab) {
+		return true
+	}
+
 	// If there are any implicit partitioning columns in the primary index,
 	// these columns will need to be fetched.
 	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
@@ -643,6 +649,9 @@ func (mb *mutationBuilder) addSynthesizedColsForInsert() {
 
 	// Possibly round DECIMAL-related computed columns.
 	mb.roundDecimalValues(mb.insertColIDs, true /* roundComputedCols */)
+
+	// Enforce the constraints of columns of domain types.
+	mb.checkDomainValues(mb.insertColIDs)
 }
 
 // buildInsert constructs an Insert operator, possibly wrapped by a Project
@@ -652,6 +661,9 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 	// check constraint, refer to the correct columns.
 	mb.disambiguateColumns()
 
+	// Enforce the row-level security policies of the table.
+	mb.addRowLevelSecurityCheck()
+
 	// Keep a reference to the scope before the check constraint columns are
 	// projected. We use this scope when projecting the partial index put
 	// columns because the check columns are not in-scope for those expressions.
@@ -869,6 +881,9 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 	// check constraint, refer to the correct columns.
 	mb.disambiguateColumns()
 
+	// Enforce the row-level security policies of the table.
+	mb.addRowLevelSecurityCheck()
+
 	// Keep a reference to the scope before the check constraint columns are
 	// projected. We use this scope when projecting the partial index put
 	// columns because the check columns are not in-scope for those expressions.
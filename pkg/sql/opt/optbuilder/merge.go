@@ -0,0 +1,514 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// duplicateMergeErrText is error text used when a target row would be updated
+// or deleted by more than one source row in a MERGE statement.
+const duplicateMergeErrText = "MERGE command cannot affect row a second time"
+
+// buildMerge builds a memo group for a MERGE statement. MERGE is planned as a
+// set of mutations on top of a single buffered input expression. The input is
+// the left outer join of the source with the target table, augmented with a
+// column that identifies the WHEN clause that applies to each row:
+//
+//   CREATE TABLE t (k INT PRIMARY KEY, v INT)
+//   MERGE INTO t USING s ON t.k = s.k
+//   WHEN MATCHED AND s.v IS NULL THEN DELETE
+//   WHEN MATCHED THEN UPDATE SET v = s.v
+//   WHEN NOT MATCHED THEN INSERT VALUES (s.k, s.v)
+//
+// This would create an input expression similar to this SQL:
+//
+//   WITH input AS (
+//     SELECT *, CASE
+//       WHEN t.k IS NOT NULL AND s.v IS NULL THEN 1
+//       WHEN t.k IS NOT NULL THEN 2
+//       WHEN t.k IS NULL THEN 3
+//       ELSE 0
+//     END AS when_clause
+//     FROM s LEFT JOIN t ON t.k = s.k
+//   )
+//
+// A target row that would be updated or deleted by more than one source row
+// results in an error. Each WHEN clause with an action other than DO NOTHING
+// then becomes a separate Update, Delete or Insert operator that reads the
+// input rows for that clause. Update and Delete operators join those rows back
+// to the target table on its primary key in order to fetch the existing
+// values:
+//
+//   UPDATE t SET v = input.v FROM input WHERE t.k = input.k AND when_clause = 2
+//   DELETE FROM t USING input WHERE t.k = input.k AND when_clause = 1
+//   INSERT INTO t SELECT input.k, input.v FROM input WHERE when_clause = 3
+//
+// The mutations are added as CTEs so that they are executed ahead of the main
+// query. Since the WHEN clauses partition the input rows, each input row is
+// processed by at most one mutation, and the main query counts the input rows
+// that were processed in order to report the number of affected rows.
+func (b *Builder) buildMerge(merge *tree.Merge, inScope *scope) (outScope *scope) {
+	// Find which table we're working on, check the permissions. Select
+	// permission is always needed, since the existing rows must be read.
+	tab, depName, alias, refColumns := b.resolveTableForMutation(merge.Table, privilege.SELECT)
+
+	if refColumns != nil {
+		panic(pgerror.Newf(pgcode.Syntax,
+			"cannot specify a list of column IDs with MERGE"))
+	}
+
+	for _, when := range merge.Whens {
+		switch when.Action {
+		case tree.MergeUpdate:
+			b.checkPrivilege(depName, tab, privilege.UPDATE)
+		case tree.MergeDelete:
+			b.checkPrivilege(depName, tab, privilege.DELETE)
+		case tree.MergeInsert:
+			b.checkPrivilege(depName, tab, privilege.INSERT)
+		}
+	}
+
+	var mgb mergeBuilder
+	mgb.init(b, merge, tab, alias)
+	mgb.buildInput(inScope)
+
+	for i, when := range merge.Whens {
+		var mutationScope *scope
+		switch when.Action {
+		case tree.MergeDoNothing:
+			continue
+		case tree.MergeUpdate:
+			mutationScope = mgb.buildUpdate(i, when, inScope)
+		case tree.MergeDelete:
+			mutationScope = mgb.buildDelete(i, inScope)
+		case tree.MergeInsert:
+			mutationScope = mgb.buildInsert(i, when, inScope)
+		default:
+			panic(errors.AssertionFailedf("unexpected MERGE action %d", when.Action))
+		}
+		mgb.addCTE(mutationScope.expr, tree.MaterializeClause{})
+	}
+
+	// Count the input rows that were processed by one of the mutations.
+	outScope = mgb.scanInput(inScope)
+	outScope.expr = b.factory.ConstructSelect(
+		outScope.expr.(memo.RelExpr),
+		memo.FiltersExpr{b.factory.ConstructFiltersItem(
+			b.factory.ConstructNe(
+				b.factory.ConstructVariable(outScope.cols[mgb.whenColOrd].id),
+				b.factory.ConstructConstVal(tree.NewDInt(0), types.Int),
+			),
+		)},
+	)
+	countScope := outScope.replace()
+	countCol := b.synthesizeColumn(countScope, "count", types.Int, nil /* expr */, nil /* scalar */)
+	countScope.expr = b.factory.ConstructScalarGroupBy(
+		outScope.expr.(memo.RelExpr),
+		memo.AggregationsExpr{b.factory.ConstructAggregationsItem(
+			b.factory.ConstructCountRows(), countCol.id,
+		)},
+		&memo.GroupingPrivate{},
+	)
+	return countScope
+}
+
+// mergeBuilder is a helper struct that builds the input expression and the
+// mutations of a MERGE statement. See Builder.buildMerge for details.
+type mergeBuilder struct {
+	b     *Builder
+	merge *tree.Merge
+	tab   cat.Table
+	alias tree.TableName
+
+	// pkOrds are the ordinals of the primary key columns of the target table.
+	pkOrds []int
+
+	// inputID is the ID of the CTE that buffers the input expression.
+	inputID opt.WithID
+
+	// inputCols are the columns of the input expression: the source columns,
+	// followed by the target table columns, followed by the WHEN clause column.
+	inputCols []scopeColumn
+
+	// numSourceCols is the number of source columns in inputCols.
+	numSourceCols int
+
+	// whenColOrd is the position of the WHEN clause column in inputCols. The
+	// column holds the 1-based index of the WHEN clause that applies to each
+	// row, or zero if no clause applies or the clause is DO NOTHING.
+	whenColOrd int
+}
+
+func (mgb *mergeBuilder) init(b *Builder, merge *tree.Merge, tab cat.Table, alias tree.TableName) {
+	*mgb = mergeBuilder{
+		b:     b,
+		merge: merge,
+		tab:   tab,
+		alias: alias,
+	}
+	primaryIndex := tab.Index(cat.PrimaryIndex)
+	mgb.pkOrds = make([]int, primaryIndex.KeyColumnCount())
+	for i := range mgb.pkOrds {
+		mgb.pkOrds[i] = primaryIndex.Column(i).Ordinal()
+	}
+}
+
+// buildInput builds the input expression of the MERGE statement and adds it
+// as a CTE.
+func (mgb *mergeBuilder) buildInput(inScope *scope) {
+	b := mgb.b
+
+	var indexFlags *tree.IndexFlags
+	if source, ok := mgb.merge.Table.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
+		indexFlags = source.IndexFlags
+	}
+
+	targetScope := b.buildScan(
+		b.addTable(mgb.tab, &mgb.alias),
+		tableOrdinals(mgb.tab, columnKinds{
+			includeMutations:       true,
+			includeSystem:          true,
+			includeVirtualInverted: false,
+			includeVirtualComputed: true,
+		}),
+		indexFlags,
+		noRowLocking,
+		inScope,
+	)
+	sourceScope := b.buildDataSource(mgb.merge.Source, nil /* indexFlags */, noRowLocking, inScope)
+
+	// Check that the same table name is not used for the source and target.
+	b.validateJoinTableNames(sourceScope, targetScope)
+
+	// Build the left outer join of the source with the target table.
+	joinScope := inScope.push()
+	joinScope.appendColumnsFromScope(sourceScope)
+	joinScope.appendColumnsFromScope(targetScope)
+	mgb.numSourceCols = len(sourceScope.cols)
+
+	on := b.resolveAndBuildScalar(
+		mgb.merge.On,
+		types.Bool,
+		exprKindOn,
+		tree.RejectGenerators|tree.RejectWindowApplications,
+		joinScope,
+	)
+	joinScope.expr = b.factory.ConstructLeftJoin(
+		sourceScope.expr.(memo.RelExpr),
+		targetScope.expr.(memo.RelExpr),
+		memo.FiltersExpr{b.factory.ConstructFiltersItem(on)},
+		memo.EmptyJoinPrivate,
+	)
+
+	pkColIDs := make(opt.ColList, len(mgb.pkOrds))
+	for i, ord := range mgb.pkOrds {
+		pkColIDs[i] = mgb.targetColID(joinScope, ord)
+	}
+
+	// Determine the WHEN clause that applies to each row. The clauses are
+	// evaluated in order, and the first one whose conditions are satisfied
+	// applies. A row is matched if the first primary key column of the target
+	// table is not NULL.
+	matched := b.factory.ConstructIsNot(b.factory.ConstructVariable(pkColIDs[0]), memo.NullSingleton)
+	notMatched := b.factory.ConstructIs(b.factory.ConstructVariable(pkColIDs[0]), memo.NullSingleton)
+	whens := make(memo.ScalarListExpr, len(mgb.merge.Whens))
+	for i, when := range mgb.merge.Whens {
+		cond := notMatched
+		if when.Matched {
+			cond = matched
+		}
+		if when.Cond != nil {
+			cond = b.factory.ConstructAnd(cond, b.resolveAndBuildScalar(
+				when.Cond, types.Bool, exprKindMergeWhen, tree.RejectSpecial, joinScope,
+			))
+		}
+		val := tree.NewDInt(tree.DInt(i + 1))
+		if when.Action == tree.MergeDoNothing {
+			val = tree.NewDInt(0)
+		}
+		whens[i] = b.factory.ConstructWhen(cond, b.factory.ConstructConstVal(val, types.Int))
+	}
+	whenExpr := b.factory.ConstructCase(
+		memo.TrueSingleton, whens, b.factory.ConstructConstVal(tree.NewDInt(0), types.Int),
+	)
+
+	projectionsScope := joinScope.replace()
+	projectionsScope.appendColumnsFromScope(joinScope)
+	whenColID := b.synthesizeColumn(projectionsScope, "", types.Int, nil /* expr */, whenExpr).id
+	b.constructProjectForScope(joinScope, projectionsScope)
+
+	inputScope := mgb.checkDuplicates(projectionsScope, pkColIDs, whenColID)
+	mgb.inputCols = inputScope.cols
+	mgb.whenColOrd = len(inputScope.cols) - 1
+	// The input is always materialized, even when the main query is its only
+	// reader because every clause is DO NOTHING. This way the affected rows are
+	// always counted from the buffered input rows on the gateway.
+	mgb.inputID = mgb.addCTE(inputScope.expr, tree.MaterializeClause{Set: true, Materialize: true})
+}
+
+// checkDuplicates wraps the input expression of the MERGE statement in the
+// given scope so that an error is raised if a target row would be updated or
+// deleted by more than one source row. As in Postgres, the rows to which an
+// INSERT or DO NOTHING clause applies, or no clause at all, are not checked:
+// the primary key values of the target rows are replaced by NULLs for them,
+// so that they are treated as distinct.
+func (mgb *mergeBuilder) checkDuplicates(
+	inScope *scope, pkColIDs opt.ColList, whenColID opt.ColumnID,
+) (outScope *scope) {
+	b := mgb.b
+
+	// Build a condition that is true for the rows to which an UPDATE or DELETE
+	// clause applies.
+	var modifies opt.ScalarExpr
+	for i, when := range mgb.merge.Whens {
+		if when.Action != tree.MergeUpdate && when.Action != tree.MergeDelete {
+			continue
+		}
+		isWhen := b.factory.ConstructEq(
+			b.factory.ConstructVariable(whenColID),
+			b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i+1)), types.Int),
+		)
+		if modifies == nil {
+			modifies = isWhen
+		} else {
+			modifies = b.factory.ConstructOr(modifies, isWhen)
+		}
+	}
+	if modifies == nil {
+		return inScope
+	}
+
+	keyScope := inScope.replace()
+	keyScope.appendColumnsFromScope(inScope)
+	var keyCols opt.ColSet
+	for _, colID := range pkColIDs {
+		typ := b.factory.Metadata().ColumnMeta(colID).Type
+		key := b.factory.ConstructCase(
+			memo.TrueSingleton,
+			memo.ScalarListExpr{b.factory.ConstructWhen(modifies, b.factory.ConstructVariable(colID))},
+			b.factory.ConstructNull(typ),
+		)
+		keyCols.Add(b.synthesizeColumn(keyScope, "", typ, nil /* expr */, key).id)
+	}
+	b.constructProjectForScope(inScope, keyScope)
+	keyScope = b.buildDistinctOn(keyCols, keyScope, true /* nullsAreDistinct */, duplicateMergeErrText)
+
+	// Remove the key columns.
+	outScope = keyScope.replace()
+	outScope.appendColumnsFromScope(inScope)
+	b.constructProjectForScope(keyScope, outScope)
+	return outScope
+}
+
+// targetColID returns the ID of the column in the given scope that holds the
+// values of the target table column with the given ordinal.
+func (mgb *mergeBuilder) targetColID(s *scope, ord int) opt.ColumnID {
+	for i := mgb.numSourceCols; i < len(s.cols); i++ {
+		if s.cols[i].tableOrdinal == ord {
+			return s.cols[i].id
+		}
+	}
+	panic(errors.AssertionFailedf("target column %d not found", ord))
+}
+
+// addCTE adds the given expression as a CTE and returns its ID.
+func (mgb *mergeBuilder) addCTE(expr memo.RelExpr, mtr tree.MaterializeClause) opt.WithID {
+	id := mgb.b.factory.Memo().NextWithID()
+	mgb.b.factory.Metadata().AddWithBinding(id, expr)
+	mgb.b.addCTE(&cteSource{
+		name:         tree.AliasClause{},
+		originalExpr: mgb.merge,
+		expr:         expr,
+		mtr:          mtr,
+		id:           id,
+	})
+	return id
+}
+
+// scanInput constructs a WithScan of the input CTE. The target table columns
+// are not visible by name, since they are either fetched again by the
+// mutation or are NULL.
+func (mgb *mergeBuilder) scanInput(inScope *scope) (outScope *scope) {
+	md := mgb.b.factory.Metadata()
+	inCols := make(opt.ColList, len(mgb.inputCols))
+	outCols := make(opt.ColList, len(mgb.inputCols))
+	outScope = inScope.push()
+	for i, col := range mgb.inputCols {
+		inCols[i] = col.id
+		outCols[i] = md.AddColumn(string(col.name), col.typ)
+		col.id = outCols[i]
+		col.scalar = nil
+		col.expr = nil
+		if i >= mgb.numSourceCols {
+			col.clearName()
+		}
+		outScope.cols = append(outScope.cols, col)
+	}
+	outScope.expr = mgb.b.factory.ConstructWithScan(&memo.WithScanPrivate{
+		With:    mgb.inputID,
+		InCols:  inCols,
+		OutCols: outCols,
+		ID:      md.NextUniqueID(),
+	})
+	return outScope
+}
+
+// scanInputForWhen constructs a WithScan of the input CTE that is filtered to
+// the rows to which the WHEN clause with the given index applies.
+func (mgb *mergeBuilder) scanInputForWhen(idx int, inScope *scope) (outScope *scope) {
+	outScope = mgb.scanInput(inScope)
+	outScope.expr = mgb.b.factory.ConstructSelect(
+		outScope.expr.(memo.RelExpr),
+		memo.FiltersExpr{mgb.b.factory.ConstructFiltersItem(
+			mgb.b.factory.ConstructEq(
+				mgb.b.factory.ConstructVariable(outScope.cols[mgb.whenColOrd].id),
+				mgb.b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(idx+1)), types.Int),
+			),
+		)},
+	)
+	return outScope
+}
+
+// buildInputForMatched initializes the given mutationBuilder with an input
+// expression that joins the target table with the input rows to which the
+// WHEN clause with the given index applies. The target table columns are
+// added to the fetch columns of the mutation. Only the target rows which the
+// row-level security policies of the table allow the user to access with the
+// given command are matched.
+func (mgb *mergeBuilder) buildInputForMatched(
+	mb *mutationBuilder, idx int, cmd tree.PolicyCommand, inScope *scope,
+) {
+	b := mgb.b
+	mb.fetchScope = b.buildScan(
+		b.addTable(mb.tab, &mb.alias),
+		tableOrdinals(mb.tab, columnKinds{
+			includeMutations:       true,
+			includeSystem:          true,
+			includeVirtualInverted: false,
+			includeVirtualComputed: true,
+		}),
+		nil, /* indexFlags */
+		noRowLocking,
+		inScope,
+	)
+	mb.setFetchColIDs(mb.fetchScope.cols)
+	b.addRowLevelSecurityFilter(mb.tab, cmd, mb.fetchScope)
+
+	inputScope := mgb.scanInputForWhen(idx, inScope)
+
+	// Join on the primary key columns. The source columns are visible to the
+	// SET expressions of UPDATE actions.
+	on := make(memo.FiltersExpr, len(mgb.pkOrds))
+	for i, ord := range mgb.pkOrds {
+		on[i] = b.factory.ConstructFiltersItem(b.factory.ConstructEq(
+			b.factory.ConstructVariable(mb.fetchColIDs[ord]),
+			b.factory.ConstructVariable(mgb.targetColID(inputScope, ord)),
+		))
+	}
+	mb.outScope = mb.fetchScope.replace()
+	mb.outScope.appendColumnsFromScope(mb.fetchScope)
+	mb.outScope.appendColumnsFromScope(inputScope)
+	mb.outScope.expr = b.factory.ConstructInnerJoin(
+		mb.fetchScope.expr.(memo.RelExpr), inputScope.expr.(memo.RelExpr), on, memo.EmptyJoinPrivate,
+	)
+}
+
+// buildUpdate builds an Update operator for the WHEN MATCHED THEN UPDATE
+// clause with the given index.
+func (mgb *mergeBuilder) buildUpdate(idx int, when *tree.MergeWhen, inScope *scope) *scope {
+	var mb mutationBuilder
+	mb.init(mgb.b, "update", mgb.tab, mgb.alias)
+	mgb.buildInputForMatched(&mb, idx, tree.PolicyUpdate, inScope)
+
+	// Derive the columns that will be updated from the SET expressions.
+	mb.addTargetColsForUpdate(when.Exprs)
+
+	// Build each of the SET expressions.
+	mb.addUpdateCols(when.Exprs)
+
+	mb.buildUpdate(nil /* returning */)
+	return mb.outScope
+}
+
+// buildDelete builds a Delete operator for the WHEN MATCHED THEN DELETE clause
+// with the given index.
+func (mgb *mergeBuilder) buildDelete(idx int, inScope *scope) *scope {
+	var mb mutationBuilder
+	mb.init(mgb.b, "delete", mgb.tab, mgb.alias)
+	mgb.buildInputForMatched(&mb, idx, tree.PolicyDelete, inScope)
+
+	mb.buildDelete(nil /* returning */)
+	return mb.outScope
+}
+
+// buildInsert builds an Insert operator for the WHEN NOT MATCHED THEN INSERT
+// clause with the given index.
+func (mgb *mergeBuilder) buildInsert(idx int, when *tree.MergeWhen, inScope *scope) *scope {
+	var mb mutationBuilder
+	mb.init(mgb.b, "insert", mgb.tab, mgb.alias)
+
+	// Compute the target columns, either explicitly specified by name or
+	// implicitly targeted by the VALUES expressions.
+	if len(when.Columns) != 0 {
+		mb.addTargetNamedColsForInsert(when.Columns)
+		mb.checkNumCols(len(mb.targetColList), len(when.Values))
+	} else if when.Values != nil {
+		mb.addTargetTableColsForInsert(len(when.Values))
+	}
+
+	// Project the VALUES expressions over the input rows. The source columns
+	// are visible to the expressions.
+	scalarProps := &mgb.b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	mgb.b.semaCtx.Properties.Require(exprKindValues.String(), tree.RejectSpecial)
+
+	inputScope := mgb.scanInputForWhen(idx, inScope)
+	inputScope.context = exprKindValues
+	projectionsScope := inputScope.replace()
+	for i, expr := range when.Values {
+		targetColID := mb.targetColList[i]
+		ord := mb.tabID.ColumnOrdinal(targetColID)
+
+		// Allow DEFAULT in the VALUES list.
+		if _, ok := expr.(tree.DefaultVal); ok {
+			expr = mb.parseDefaultOrComputedExpr(targetColID)
+		}
+
+		targetColMeta := mb.md.ColumnMeta(targetColID)
+		texpr := inputScope.resolveType(expr, targetColMeta.Type)
+		scopeCol := projectionsScope.addColumn(targetColMeta.Alias, texpr)
+		mgb.b.buildScalar(texpr, inputScope, projectionsScope, scopeCol, nil)
+
+		// Type check the input expression against the corresponding table column.
+		checkDatumTypeFitsColumnType(mb.tab.Column(ord), scopeCol.typ)
+
+		// Record the ID of the column that contains the value to be inserted
+		// into the corresponding target table column.
+		mb.insertColIDs[ord] = scopeCol.id
+	}
+	mgb.b.constructProjectForScope(inputScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// Add default and computed columns that were not explicitly specified.
+	mb.addSynthesizedColsForInsert()
+
+	mb.buildInsert(nil /* returning */)
+	return mb.outScope
+}
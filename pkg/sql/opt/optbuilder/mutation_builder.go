@@ -174,10 +174,15 @@ type mutationBuilder struct {
 
 	// extraAccessibleCols stores all the columns that are available to the
 	// mutation that are not part of the target table. This is useful for
-	// UPDATE ... FROM queries, as the columns from the FROM tables must be
-	// made accessible to the RETURNING clause.
+	// UPDATE ... FROM and DELETE ... USING queries, as the columns from the
+	// FROM and USING tables must be made accessible to the RETURNING clause.
 	extraAccessibleCols []scopeColumn
 
+	// bypassRowLevelSecurity is true if the row-level security policies of the
+	// target table are not enforced for the mutation. This is the case for
+	// mutations built for foreign key cascades.
+	bypassRowLevelSecurity bool
+
 	// fkCheckHelper is used to prevent allocating the helper separately.
 	fkCheckHelper fkCheckHelper
 
@@ -291,6 +296,10 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	// Set list of columns that will be fetched by the input expression.
 	mb.setFetchColIDs(mb.fetchScope.cols)
 
+	// Only update the rows that the row-level security policies of the table
+	// allow the user to update.
+	mb.b.addRowLevelSecurityFilter(mb.tab, tree.PolicyUpdate, mb.fetchScope)
+
 	// If there is a FROM clause present, we must join all the tables
 	// together with the table being updated.
 	fromClausePresent := len(from) > 0
@@ -339,29 +348,32 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	// Build a distinct on to ensure there is at most one row in the joined output
 	// for every row in the table.
 	if fromClausePresent {
-		var pkCols opt.ColSet
-
-		// We need to ensure that the join has a maximum of one row for every row
-		// in the table and we ensure this by constructing a distinct on the primary
-		// key columns.
-		primaryIndex := mb.tab.Index(cat.PrimaryIndex)
-		for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
-			// If the primary key column is hidden, then we don't need to use it
-			// for the distinct on.
-			// TODO(radu): this logic seems fragile, is it assuming that only an
-			// implicit `rowid` column can be a hidden PK column?
-			if col := primaryIndex.Column(i); col.Visibility() != cat.Hidden {
-				pkCols.Add(mb.fetchColIDs[col.Ordinal()])
-			}
-		}
-
-		if !pkCols.Empty() {
-			mb.outScope = mb.b.buildDistinctOn(
-				pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
-		}
+		mb.buildDistinctOnPrimaryKey()
 	}
 }
 
+// buildDistinctOnPrimaryKey wraps the input expression in a DistinctOn on the
+// primary key columns of the target table. It is used by UPDATE .. FROM and
+// DELETE .. USING to ensure that the join with the additional tables has a
+// maximum of one row for every row in the target table.
+func (mb *mutationBuilder) buildDistinctOnPrimaryKey() {
+	// Hidden primary key columns (such as the implicit rowid column) are
+	// included, since they are the only way to tell apart the rows of a table
+	// without an explicit primary key.
+	var pkCols opt.ColSet
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
+		pkCols.Add(mb.fetchColIDs[primaryIndex.Column(i).Ordinal()])
+	}
+
+	// Any ORDER BY has already been used to apply the LIMIT, and mutations
+	// make no guarantees about the order in which rows are processed, so the
+	// ordering does not need to be maintained by the distinct on.
+	mb.outScope.ordering = nil
+	mb.outScope = mb.b.buildDistinctOn(
+		pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+}
+
 // buildInputForDelete constructs a Select expression from the fields in
 // the Delete operator, similar to this:
 //
@@ -371,10 +383,18 @@ func (mb *mutationBuilder) buildInputForUpdate(
 //   ORDER BY <order-by>
 //   LIMIT <limit>
 //
+// If there is a USING clause, the tables in it are joined with the target
+// table, similar to the FROM clause of an UPDATE. See buildInputForUpdate.
+//
 // All columns from the table to update are added to fetchColList.
 // TODO(andyk): Do needed column analysis to project fewer columns if possible.
 func (mb *mutationBuilder) buildInputForDelete(
-	inScope *scope, texpr tree.TableExpr, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
+	inScope *scope,
+	texpr tree.TableExpr,
+	using tree.TableExprs,
+	where *tree.Where,
+	limit *tree.Limit,
+	orderBy tree.OrderBy,
 ) {
 	var indexFlags *tree.IndexFlags
 	if source, ok := texpr.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
@@ -403,7 +423,40 @@ func (mb *mutationBuilder) buildInputForDelete(
 		noRowLocking,
 		inScope,
 	)
-	mb.outScope = mb.fetchScope
+
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	// Only delete the rows that the row-level security policies of the table
+	// allow the user to delete.
+	mb.b.addRowLevelSecurityFilter(mb.tab, tree.PolicyDelete, mb.fetchScope)
+
+	// If there is a USING clause present, we must join all the tables
+	// together with the table being deleted from.
+	usingClausePresent := len(using) > 0
+	if usingClausePresent {
+		usingScope := mb.b.buildFromTables(using, noRowLocking, inScope)
+
+		// Check that the same table name is not used multiple times.
+		mb.b.validateJoinTableNames(mb.fetchScope, usingScope)
+
+		// The USING table columns can be accessed by the RETURNING clause of the
+		// query and so we have to make them accessible.
+		mb.extraAccessibleCols = usingScope.cols
+
+		// Add the columns in the USING scope. We create a new scope so that
+		// fetchScope is not modified, since it is used later to build partial
+		// index predicate expressions.
+		mb.outScope = mb.fetchScope.replace()
+		mb.outScope.appendColumnsFromScope(mb.fetchScope)
+		mb.outScope.appendColumnsFromScope(usingScope)
+
+		left := mb.fetchScope.expr.(memo.RelExpr)
+		right := usingScope.expr.(memo.RelExpr)
+		mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter, memo.EmptyJoinPrivate)
+	} else {
+		mb.outScope = mb.fetchScope
+	}
 
 	// WHERE
 	mb.b.buildWhere(where, mb.outScope)
@@ -422,8 +475,11 @@ func (mb *mutationBuilder) buildInputForDelete(
 
 	mb.outScope = projectionsScope
 
-	// Set list of columns that will be fetched by the input expression.
-	mb.setFetchColIDs(mb.outScope.cols)
+	// Build a distinct on to ensure there is at most one row in the joined output
+	// for every row in the table.
+	if usingClausePresent {
+		mb.buildDistinctOnPrimaryKey()
+	}
 }
 
 // addTargetColsByName adds one target column for each of the names in the given
@@ -1119,8 +1175,8 @@ func (mb *mutationBuilder) buildReturning(returning tree.ReturningExprs) {
 
 	// extraAccessibleCols contains all the columns that the RETURNING
 	// clause can refer to in addition to the table columns. This is useful for
-	// UPDATE ... FROM statements, where all columns from tables in the FROM clause
-	// are in scope for the RETURNING clause.
+	// UPDATE ... FROM and DELETE ... USING statements, where all columns from
+	// tables in the FROM or USING clause are in scope for the RETURNING clause.
 	inScope.appendColumns(mb.extraAccessibleCols)
 
 	// Construct the Project operator that projects the RETURNING expressions.
@@ -1167,6 +1223,11 @@ func (mb *mutationBuilder) parseDefaultOrComputedExpr(colID opt.ColumnID) tree.E
 		exprStr = tabCol.ComputedExprStr()
 	case tabCol.HasDefault():
 		exprStr = tabCol.DefaultExprStr()
+	case tabCol.DatumType().IsDomain() && tabCol.DatumType().TypeMeta.DomainData != nil &&
+		tabCol.DatumType().TypeMeta.DomainData.DefaultExpr != nil:
+		// Columns of a domain type without a default of their own inherit the
+		// default of the domain.
+		exprStr = *tabCol.DatumType().TypeMeta.DomainData.DefaultExpr
 	case tabCol.IsMutation() && !tabCol.IsNullable():
 		// Synthesize default value for NOT NULL mutation column so that it can be
 		// set when in the write-only state. This is only used when no other value
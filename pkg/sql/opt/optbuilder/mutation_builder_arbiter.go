@@ -46,6 +46,9 @@ import (
 //   1. Must have columns that match the columns in conflictOrds.
 //   2. If it is a partial constraint, its predicate must be implied by the
 //      arbiterPredicate supplied by the user.
+//   3. Must not be DEFERRABLE. As in Postgres, deferrable constraints cannot
+//      be used as arbiters because conflicts may only be detected when the
+//      transaction commits.
 //
 // If conflictOrds is empty then all unique indexes and unique without index
 // constraints are returned as arbiters. This is required to support a
@@ -82,7 +85,9 @@ func (mb *mutationBuilder) findArbiters(
 			}
 		}
 		for uc, ucCount := 0, mb.tab.UniqueCount(); uc < ucCount; uc++ {
-			if mb.tab.Unique(uc).WithoutIndex() {
+			uniqueConstraint := mb.tab.Unique(uc)
+			if uniqueConstraint.WithoutIndex() &&
+				uniqueConstraint.Deferrability() == tree.NotDeferrableConstraint {
 				arbiters.AddUniqueConstraint(uc)
 			}
 		}
@@ -140,6 +145,11 @@ func (mb *mutationBuilder) findArbiters(
 			continue
 		}
 
+		if uniqueConstraint.Deferrability() != tree.NotDeferrableConstraint {
+			// Deferrable constraints cannot be arbiters.
+			continue
+		}
+
 		if uniqueConstraint.ColumnCount() != conflictOrds.Len() {
 			continue
 		}
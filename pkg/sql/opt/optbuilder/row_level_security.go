@@ -0,0 +1,177 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// rowLevelSecurityApplies returns true if the rows of the given table that the
+// current user can read and write are restricted by the row-level security
+// policies of the table. This is the case if row-level security is enabled
+// for the table, unless the user is an admin or an owner of the table.
+func (b *Builder) rowLevelSecurityApplies(tab cat.Table) bool {
+	if !tab.IsRowLevelSecurityEnabled() {
+		return false
+	}
+
+	// The policies that apply depend on the current user, so the memo cannot
+	// be reused by other users.
+	b.DisableMemoReuse = true
+
+	isAdmin, err := b.catalog.HasAdminRole(b.ctx)
+	if err != nil {
+		panic(err)
+	}
+	if isAdmin {
+		return false
+	}
+	isOwner, err := b.catalog.HasOwnership(b.ctx, tab)
+	if err != nil {
+		panic(err)
+	}
+	return !isOwner
+}
+
+// addRowLevelSecurityFilter wraps the scan of the given table in s.expr with a
+// Select that filters out the rows which the current user cannot access with
+// the given command.
+func (b *Builder) addRowLevelSecurityFilter(tab cat.Table, cmd tree.PolicyCommand, s *scope) {
+	if !b.rowLevelSecurityApplies(tab) {
+		return
+	}
+	filter := b.buildPolicyExpr(tab, cmd, false /* withCheck */, s)
+	s.expr = b.factory.ConstructSelect(
+		s.expr,
+		memo.FiltersExpr{b.factory.ConstructFiltersItem(filter)},
+	)
+}
+
+// buildPolicyExpr builds the disjunction of the expressions of the policies of
+// the given table that apply to cmd and to the current user. If withCheck is
+// true, the WITH CHECK expression of each policy is used if it has one, and its
+// USING expression otherwise. The columns of the table are resolved in inScope.
+// If no policy applies, the expression is false: row-level security denies
+// access by default.
+func (b *Builder) buildPolicyExpr(
+	tab cat.Table, cmd tree.PolicyCommand, withCheck bool, inScope *scope,
+) opt.ScalarExpr {
+	var result opt.ScalarExpr
+	for i, n := 0, tab.PolicyCount(); i < n; i++ {
+		policy := tab.Policy(i)
+		if !policy.AppliesToCommand(cmd) || !b.policyAppliesToUser(&policy) {
+			continue
+		}
+		exprStr := policy.UsingExpr
+		if withCheck && policy.WithCheckExpr != "" {
+			exprStr = policy.WithCheckExpr
+		}
+		if exprStr == "" {
+			continue
+		}
+		expr, err := parser.ParseExpr(exprStr)
+		if err != nil {
+			panic(err)
+		}
+		texpr := inScope.resolveAndRequireType(expr, types.Bool)
+		scalar := b.buildScalar(texpr, inScope, nil, nil, nil)
+		if result == nil {
+			result = scalar
+		} else {
+			result = b.factory.ConstructOr(result, scalar)
+		}
+	}
+	if result == nil {
+		return memo.FalseSingleton
+	}
+	return result
+}
+
+// policyAppliesToUser returns true if the current user is a member of one of
+// the roles to which the given policy applies.
+func (b *Builder) policyAppliesToUser(policy *cat.Policy) bool {
+	for _, role := range policy.Roles {
+		if role.IsPublicRole() {
+			return true
+		}
+		isMember, err := b.catalog.IsMemberOfRole(b.ctx, role)
+		if err != nil {
+			panic(err)
+		}
+		if isMember {
+			return true
+		}
+	}
+	return false
+}
+
+// addRowLevelSecurityCheck wraps the mutation input with a Select whose filter
+// raises an error for each inserted or updated row that violates the policies
+// of the target table. It must be called after the columns have been
+// disambiguated, so that the names of the table columns refer to the new
+// values of the rows.
+//
+// For an upsert, the insert policies apply to the inserted rows, while both the
+// existing and the new values of the updated rows are checked against the
+// update policies. Existing rows are always fetched when row-level security
+// applies (see needExistingRows).
+func (mb *mutationBuilder) addRowLevelSecurityCheck() {
+	if mb.bypassRowLevelSecurity || !mb.b.rowLevelSecurityApplies(mb.tab) {
+		return
+	}
+	f := mb.b.factory
+
+	var check opt.ScalarExpr
+	switch {
+	case mb.canaryColID != 0:
+		insertCheck := mb.b.buildPolicyExpr(mb.tab, tree.PolicyInsert, true /* withCheck */, mb.outScope)
+		updateCheck := f.ConstructAnd(
+			mb.b.buildPolicyExpr(mb.tab, tree.PolicyUpdate, false /* withCheck */, mb.fetchScope),
+			mb.b.buildPolicyExpr(mb.tab, tree.PolicyUpdate, true /* withCheck */, mb.outScope),
+		)
+		check = f.ConstructCase(
+			memo.TrueSingleton,
+			memo.ScalarListExpr{
+				f.ConstructWhen(
+					f.ConstructIs(f.ConstructVariable(mb.canaryColID), memo.NullSingleton),
+					insertCheck,
+				),
+			},
+			updateCheck,
+		)
+
+	case mb.insertColIDs != nil:
+		check = mb.b.buildPolicyExpr(mb.tab, tree.PolicyInsert, true /* withCheck */, mb.outScope)
+
+	default:
+		check = mb.b.buildPolicyExpr(mb.tab, tree.PolicyUpdate, true /* withCheck */, mb.outScope)
+	}
+
+	props, overloads := builtins.GetBuiltinProperties("crdb_internal.check_row_level_security")
+	private := &memo.FunctionPrivate{
+		Name:       "crdb_internal.check_row_level_security",
+		Typ:        types.Bool,
+		Properties: props,
+		Overload:   &overloads[0],
+	}
+	tabName := f.ConstructConstVal(tree.NewDString(string(mb.tab.Name())), types.String)
+	fn := f.ConstructFunction(memo.ScalarListExpr{check, tabName}, private)
+	mb.outScope.expr = f.ConstructSelect(
+		mb.outScope.expr,
+		memo.FiltersExpr{f.ConstructFiltersItem(fn)},
+	)
+}
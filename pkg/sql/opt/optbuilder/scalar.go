@@ -251,6 +251,10 @@ func (b *Builder) buildScalar(
 		texpr := t.Expr.(tree.TypedExpr)
 		arg := b.buildScalar(texpr, inScope, nil, nil, colRefs)
 		out = b.factory.ConstructCast(arg, t.ResolvedType())
+		out = b.buildDomainChecks(out, t.ResolvedType())
+
+	case *domainValue:
+		out = t.scalar
 
 	case *tree.CoalesceExpr:
 		args := make(memo.ScalarListExpr, len(t.Exprs))
@@ -715,6 +719,10 @@ func (b *Builder) constructComparison(
 			return b.factory.ConstructBBoxIntersects(left, right)
 		}
 		return b.factory.ConstructOverlaps(left, right)
+	case tree.TSMatches:
+		return b.factory.ConstructTSMatches(left, right)
+	case tree.TrigramSimilar:
+		return b.factory.ConstructTrigramSimilar(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled comparison operator: %s", log.Safe(cmp.Operator)))
 }
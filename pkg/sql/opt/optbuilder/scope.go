@@ -121,6 +121,7 @@ const (
 	exprKindHaving
 	exprKindLateralJoin
 	exprKindLimit
+	exprKindMergeWhen
 	exprKindOffset
 	exprKindOn
 	exprKindOrderBy
@@ -141,6 +142,7 @@ var exprKindName = [...]string{
 	exprKindHaving:            "HAVING",
 	exprKindLateralJoin:       "LATERAL JOIN",
 	exprKindLimit:             "LIMIT",
+	exprKindMergeWhen:         "MERGE WHEN",
 	exprKindOffset:            "OFFSET",
 	exprKindOn:                "ON",
 	exprKindOrderBy:           "ORDER BY",
@@ -51,6 +51,14 @@ func (b *Builder) buildDataSource(
 			telemetry.Inc(sqltelemetry.IndexHintUseCounter)
 			telemetry.Inc(sqltelemetry.IndexHintSelectUseCounter)
 			indexFlags = source.IndexFlags
+		} else if b.Hints != nil {
+			name := source.As.Alias
+			if tn, ok := source.Expr.(*tree.TableName); ok && name == "" {
+				name = tn.ObjectName
+			}
+			if flags := b.Hints.IndexFlags(name); name != "" && flags != nil {
+				indexFlags = flags
+			}
 		}
 		if source.As.Alias != "" {
 			locking = locking.filter(source.As.Alias)
@@ -111,7 +119,7 @@ func (b *Builder) buildDataSource(
 		switch t := ds.(type) {
 		case cat.Table:
 			tabMeta := b.addTable(t, &resName)
-			return b.buildScan(
+			outScope = b.buildScan(
 				tabMeta,
 				tableOrdinals(t, columnKinds{
 					includeMutations:       false,
@@ -121,6 +129,8 @@ func (b *Builder) buildDataSource(
 				}),
 				indexFlags, locking, inScope,
 			)
+			b.addRowLevelSecurityFilter(t, tree.PolicySelect, outScope)
+			return outScope
 
 		case cat.Sequence:
 			return b.buildSequenceSelect(t, &resName, inScope)
@@ -407,7 +417,28 @@ func (b *Builder) buildScanFromTableRef(
 
 	tn := tree.MakeUnqualifiedTableName(tab.Name())
 	tabMeta := b.addTable(tab, &tn)
-	return b.buildScan(tabMeta, ordinals, indexFlags, locking, inScope)
+	if ref.Columns == nil || !b.rowLevelSecurityApplies(tab) {
+		outScope = b.buildScan(tabMeta, ordinals, indexFlags, locking, inScope)
+		b.addRowLevelSecurityFilter(tab, tree.PolicySelect, outScope)
+		return outScope
+	}
+
+	// The policies of the table may refer to columns that are not in the list,
+	// so scan all the columns, filter the rows and then project the columns in
+	// the list.
+	scanScope := b.buildScan(tabMeta, tableOrdinals(tab, columnKinds{
+		includeMutations:       false,
+		includeSystem:          true,
+		includeVirtualInverted: false,
+		includeVirtualComputed: true,
+	}), indexFlags, locking, inScope)
+	b.addRowLevelSecurityFilter(tab, tree.PolicySelect, scanScope)
+	outScope = scanScope.replace()
+	for _, ord := range ordinals {
+		outScope.appendColumn(scanScope.getColumnForTableOrdinal(ord))
+	}
+	b.constructProjectForScope(scanScope, outScope)
+	return outScope
 }
 
 // addTable adds a table to the metadata and returns the TableMeta. The table
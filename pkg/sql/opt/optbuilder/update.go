@@ -320,6 +320,9 @@ func (mb *mutationBuilder) addSynthesizedColsForUpdate() {
 
 	// Possibly round DECIMAL-related computed columns.
 	mb.roundDecimalValues(mb.updateColIDs, true /* roundComputedCols */)
+
+	// Enforce the constraints of columns of domain types.
+	mb.checkDomainValues(mb.updateColIDs)
 }
 
 // buildUpdate constructs an Update operator, possibly wrapped by a Project
@@ -329,6 +332,9 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 	// check constraint, refer to the correct columns.
 	mb.disambiguateColumns()
 
+	// Enforce the row-level security policies of the table.
+	mb.addRowLevelSecurityCheck()
+
 	// Keep a reference to the scope before the check constraint columns are
 	// projected. We use this scope when projecting the partial index put
 	// columns because the check columns are not in-scope for those expressions.
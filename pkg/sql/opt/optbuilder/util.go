@@ -522,8 +522,9 @@ func (b *Builder) resolveTableForMutation(
 		alias = *outerAlias
 	}
 
-	// We can't mutate materialized views.
-	if tab.IsMaterializedView() {
+	// We can't mutate materialized views, unless we are applying the changes of
+	// the source tables of a view refreshed incrementally.
+	if tab.IsMaterializedView() && !b.evalCtx.SessionData.AllowMaterializedViewMutations {
 		panic(pgerror.Newf(pgcode.WrongObjectType, "cannot mutate materialized view %q", tab.Name()))
 	}
 
@@ -31,11 +31,16 @@ import (
 )
 
 // Histogram captures the distribution of values for a particular column within
-// a relational expression.
+// a relational expression. A multi-column histogram captures the distribution
+// of tuples of values for a list of columns; it cannot be filtered, but it can
+// be used to estimate the number of rows matching a given tuple.
 // Histograms are immutable.
 type Histogram struct {
 	evalCtx *tree.EvalContext
 	col     opt.ColumnID
+	// cols is only set for multi-column histograms, in which case the upper
+	// bounds of the buckets are tuples with the values of these columns.
+	cols    opt.ColList
 	buckets []cat.HistogramBucket
 }
 
@@ -60,6 +65,26 @@ func (h *Histogram) Init(
 	}
 }
 
+// InitMultiCol initializes a multi-column histogram with data from the
+// catalog. The upper bounds of the buckets are tuples with the values of the
+// given columns.
+func (h *Histogram) InitMultiCol(
+	evalCtx *tree.EvalContext, cols opt.ColList, buckets []cat.HistogramBucket,
+) {
+	*h = Histogram{
+		evalCtx: evalCtx,
+		cols:    cols,
+		buckets: buckets,
+	}
+}
+
+// Cols returns the columns of a multi-column histogram, in the order of the
+// values in the bucket upper bounds. It returns nil for a single-column
+// histogram.
+func (h *Histogram) Cols() opt.ColList {
+	return h.cols
+}
+
 // copy returns a deep copy of the histogram.
 func (h *Histogram) copy() *Histogram {
 	buckets := make([]cat.HistogramBucket, len(h.buckets))
@@ -67,6 +92,7 @@ func (h *Histogram) copy() *Histogram {
 	return &Histogram{
 		evalCtx: h.evalCtx,
 		col:     h.col,
+		cols:    h.cols,
 		buckets: buckets,
 	}
 }
@@ -94,6 +120,27 @@ func (h *Histogram) ValuesCount() float64 {
 	return count
 }
 
+// EqValuesCount returns the estimated number of values in the histogram that
+// are equal to val. If val is the upper bound of a bucket, this is the NumEq
+// of that bucket. Otherwise, the values in the range of the bucket containing
+// val are assumed to be uniformly distributed among its distinct values.
+func (h *Histogram) EqValuesCount(val tree.Datum) float64 {
+	i := sort.Search(len(h.buckets), func(i int) bool {
+		return h.buckets[i].UpperBound.Compare(h.evalCtx, val) >= 0
+	})
+	if i == len(h.buckets) {
+		return 0
+	}
+	b := &h.buckets[i]
+	if b.UpperBound.Compare(h.evalCtx, val) == 0 {
+		return b.NumEq
+	}
+	if b.DistinctRange == 0 {
+		return 0
+	}
+	return b.NumRange / math.Max(b.DistinctRange, 1)
+}
+
 // DistinctValuesCount returns the estimated number of distinct values in the
 // histogram.
 func (h *Histogram) DistinctValuesCount() float64 {
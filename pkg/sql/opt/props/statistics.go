@@ -170,9 +170,12 @@ type ColumnStatistic struct {
 	// count tracks only the rows in which all columns in the set are null.
 	NullCount float64
 
-	// Histogram is only used when the size of Cols is one. It contains
-	// the approximate distribution of values for that column, represented
-	// by a slice of histogram buckets.
+	// Histogram contains the approximate distribution of values for the
+	// column, represented by a slice of histogram buckets. If the size of Cols
+	// is greater than one, it is a multi-column histogram that contains the
+	// distribution of tuples of values for the columns (see Histogram.Cols).
+	// Multi-column histograms are only used to estimate the selectivity of
+	// filters that constrain all of the columns to a finite set of values.
 	Histogram *Histogram
 }
 
@@ -196,7 +196,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		switch def := def.(type) {
 		case *tree.UniqueConstraintTableDef:
 			if def.WithoutIndex {
-				tab.addUniqueConstraint(def.Name, def.Columns, def.Predicate, def.WithoutIndex)
+				tab.addUniqueConstraint(
+					def.Name, def.Columns, def.Predicate, def.WithoutIndex, def.Deferrability,
+				)
 			} else if !def.PrimaryKey {
 				tab.addIndex(&def.IndexTableDef, uniqueIndex)
 			}
@@ -215,6 +217,7 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 						tree.IndexElemList{{Column: def.Name}},
 						nil, /* predicate */
 						def.Unique.WithoutIndex,
+						tree.NotDeferrableConstraint,
 					)
 				} else {
 					tab.addIndex(
@@ -483,13 +486,18 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		matchMethod:              d.Match,
 		deleteAction:             d.Actions.Delete,
 		updateAction:             d.Actions.Update,
+		deferrability:            d.Deferrability,
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
 }
 
 func (tt *Table) addUniqueConstraint(
-	name tree.Name, columns tree.IndexElemList, predicate tree.Expr, withoutIndex bool,
+	name tree.Name,
+	columns tree.IndexElemList,
+	predicate tree.Expr,
+	withoutIndex bool,
+	deferrability tree.ConstraintDeferrability,
 ) {
 	// We don't currently use unique constraints with an index (those are already
 	// tracked with unique indexes), so don't bother adding them.
@@ -517,6 +525,7 @@ func (tt *Table) addUniqueConstraint(
 		columnOrdinals: cols,
 		withoutIndex:   withoutIndex,
 		validated:      true,
+		deferrability:  deferrability,
 	}
 	// Add partial unique constraint predicate.
 	if predicate != nil {
@@ -595,7 +604,9 @@ func (tt *Table) addIndexWithVersion(
 ) *Index {
 	// Add a unique constraint if this is a primary or unique index.
 	if typ != nonUniqueIndex {
-		tt.addUniqueConstraint(def.Name, def.Columns, def.Predicate, false /* withoutIndex */)
+		tt.addUniqueConstraint(
+			def.Name, def.Columns, def.Predicate, false /* withoutIndex */, tree.NotDeferrableConstraint,
+		)
 	}
 
 	idx := &Index{
@@ -899,26 +910,23 @@ func (ti *Index) addColumn(
 	return ti.addColumnByOrdinal(tt, ordinal, elem.Direction, colType)
 }
 
-// columnForIndexElemExpr returns a VirtualComputed table column that can be
-// used as an index column when the index element is an expression. If an
-// existing VirtualComputed column with the same expression exists, it is
-// reused. Otherwise, a new column is added to the table.
+// columnForIndexElemExpr returns a new inaccessible VirtualComputed table
+// column that can be used as an index column when the index element is an
+// expression. Note that this functionality should be kept in sync with the
+// real catalog implementation (replaceExpressionElemsWithVirtualCols in
+// create_index.go).
 func columnForIndexElemExpr(tt *Table, expr tree.Expr) cat.Column {
 	exprStr := serializeTableDefExpr(expr)
-	// Find an existing virtual computed column with the same expression.
-	for _, col := range tt.Columns {
-		if col.IsVirtualComputed() && col.ComputedExprStr() == exprStr {
-			return col
-		}
-	}
+
 	// Add a new virtual computed column with a unique name.
-	var name tree.Name
+	const baseName = "crdb_internal_idx_expr"
+	name := tree.Name(baseName)
 	for n, done := 1, false; !done; n++ {
 		done = true
-		name = tree.Name(fmt.Sprintf("idx_expr_%d", n))
 		for _, col := range tt.Columns {
 			if col.ColName() == name {
 				done = false
+				name = tree.Name(fmt.Sprintf("%s_%d", baseName, n))
 				break
 			}
 		}
@@ -932,7 +940,7 @@ func columnForIndexElemExpr(tt *Table, expr tree.Expr) cat.Column {
 		name,
 		typ,
 		true, /* nullable */
-		cat.Hidden,
+		cat.Inaccessible,
 		exprStr,
 	)
 	tt.Columns = append(tt.Columns, col)
@@ -946,7 +954,7 @@ func (ti *Index) addColumnByOrdinal(
 	if colType == keyCol || colType == strictKeyCol {
 		typ := col.DatumType()
 		if col.Kind() == cat.VirtualInverted {
-			if !colinfo.ColumnTypeIsInvertedIndexable(typ) {
+			if !colinfo.ColumnTypeIsInvertedIndexable(typ) && !colinfo.ColumnTypeIsTrigramIndexable(typ) {
 				panic(fmt.Errorf(
 					"column %s of type %s is not allowed as the last column of an inverted index",
 					col.ColName(), typ,
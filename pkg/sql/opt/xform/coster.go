@@ -81,36 +81,47 @@ type coster struct {
 	// 0.5, and the estimated cost of an expression is c, the cost returned by
 	// ComputeCost will be in the range [c - 0.5 * c, c + 0.5 * c).
 	perturbation float64
+
+	// cpuCostFactor, seqIOCostFactor and randIOCostFactor are the coefficients
+	// of the cost model that the memo is optimized with. See memo.CostFactors.
+	cpuCostFactor    memo.Cost
+	seqIOCostFactor  memo.Cost
+	randIOCostFactor memo.Cost
 }
 
 var _ Coster = &coster{}
 
 // MakeDefaultCoster creates an instance of the default coster.
 func MakeDefaultCoster(mem *memo.Memo) Coster {
-	return &coster{mem: mem}
+	c := &coster{mem: mem}
+	c.initCostFactors()
+	return c
 }
 
 const (
-	// These costs have been copied from the Postgres optimizer:
+	// The default cost factors have been copied from the Postgres optimizer
+	// (see memo.CostFactors):
 	// https://github.com/postgres/postgres/blob/master/src/include/optimizer/cost.h
 	// TODO(rytaft): "How Good are Query Optimizers, Really?" says that the
 	// PostgreSQL ratio between CPU and I/O is probably unrealistic in modern
 	// systems since much of the data can be cached in memory. Consider
-	// increasing the cpuCostFactor to account for this.
-	cpuCostFactor    = 0.01
-	seqIOCostFactor  = 1
-	randIOCostFactor = 4
+	// calibrating the cost factors to account for this.
+	// The costs in fnCost are expressed with the default CPU cost factor, and
+	// are scaled to the CPU cost factor of the memo.
+	cpuCostFactor = 0.01
 
 	// TODO(justin): make this more sophisticated.
-	// lookupJoinRetrieveRowCost is the cost to retrieve a single row during a
-	// lookup join.
+	// LookupJoinRetrieveRowCost is the cost to retrieve a single row during a
+	// lookup join, as a multiple of the sequential I/O cost factor. It is also
+	// used to calibrate the cost factors.
 	// See https://github.com/cockroachdb/cockroach/pull/35561 for the initial
 	// justification for this constant.
-	lookupJoinRetrieveRowCost = 2 * seqIOCostFactor
+	LookupJoinRetrieveRowCost = 2
 
 	// virtualScanTableDescriptorFetchCost is the cost to retrieve the table
-	// descriptors when performing a virtual table scan.
-	virtualScanTableDescriptorFetchCost = 25 * randIOCostFactor
+	// descriptors when performing a virtual table scan, as a multiple of the
+	// random I/O cost factor.
+	virtualScanTableDescriptorFetchCost = 25
 
 	// Input rows to a join are processed in batches of this size.
 	// See joinreader.go.
@@ -121,12 +132,12 @@ const (
 	// are higher, then overall cluster throughput will suffer somewhat, as there
 	// will be more queries in memory blocking on I/O. The impact on throughput
 	// is expected to be relatively low, so latencyCostFactor is set to a small
-	// value. However, even a low value will cause the optimizer to prefer
-	// indexes that are likely to be geographically closer, if they are otherwise
-	// the same cost to access.
+	// value, as a multiple of the CPU cost factor. However, even a low value
+	// will cause the optimizer to prefer indexes that are likely to be
+	// geographically closer, if they are otherwise the same cost to access.
 	// TODO(andyk): Need to do analysis to figure out right value and/or to come
 	// up with better way to incorporate latency into the coster.
-	latencyCostFactor = cpuCostFactor
+	latencyCostFactor = 1
 
 	// hugeCost is used with expressions we want to avoid; these are expressions
 	// that "violate" a hint like forcing a specific index or join algorithm.
@@ -415,6 +426,15 @@ func (c *coster) Init(evalCtx *tree.EvalContext, mem *memo.Memo, perturbation fl
 		locality:     evalCtx.Locality,
 		perturbation: perturbation,
 	}
+	c.initCostFactors()
+}
+
+// initCostFactors sets the cost model coefficients from the memo.
+func (c *coster) initCostFactors() {
+	factors := c.mem.CostFactors()
+	c.cpuCostFactor = factors.CPU
+	c.seqIOCostFactor = factors.SeqIO
+	c.randIOCostFactor = factors.RandIO
 }
 
 // ComputeCost calculates the estimated cost of the top-level operator in a
@@ -496,7 +516,7 @@ func (c *coster) ComputeCost(candidate memo.RelExpr, required *physical.Required
 	// Add a one-time cost for any operator, meant to reflect the cost of setting
 	// up execution for the operator. This makes plans with fewer operators
 	// preferable, all else being equal.
-	cost += cpuCostFactor
+	cost += c.cpuCostFactor
 
 	if !cost.Less(memo.MaxCost) {
 		// Optsteps uses MaxCost to suppress nodes in the memo. When a node with
@@ -548,14 +568,14 @@ func (c *coster) computeSortCost(sort *memo.SortExpr, required *physical.Require
 	// Start with a cost of storing each row; this takes the total number of
 	// columns into account so that a sort on fewer columns is preferred (e.g.
 	// sort before projecting a new column).
-	cost := memo.Cost(cpuCostFactor * float64(rel.OutputCols.Len()) * stats.RowCount)
+	cost := c.cpuCostFactor * memo.Cost(float64(rel.OutputCols.Len())*stats.RowCount)
 
 	if !sort.InputOrdering.Any() {
 		// Add the cost for finding the segments: each row is compared to the
 		// previous row on the preordered columns. Most of these comparisons will
 		// yield equality, so we don't use rowCmpCost(): we expect to have to
 		// compare all preordered columns.
-		cost += cpuCostFactor * memo.Cost(numPreorderedCols) * memo.Cost(stats.RowCount)
+		cost += c.cpuCostFactor * memo.Cost(numPreorderedCols) * memo.Cost(stats.RowCount)
 	}
 
 	// Add the cost to sort the segments. On average, each row is involved in
@@ -586,11 +606,11 @@ func (c *coster) computeScanCost(scan *memo.ScanExpr, required *physical.Require
 	} else if scan.InvertedConstraint != nil {
 		numSpans = len(scan.InvertedConstraint)
 	}
-	baseCost := memo.Cost(numSpans * randIOCostFactor)
+	baseCost := memo.Cost(numSpans) * c.randIOCostFactor
 
 	// If this is a virtual scan, add the cost of fetching table descriptors.
 	if c.mem.Metadata().Table(scan.Table).IsVirtualTable() {
-		baseCost += virtualScanTableDescriptorFetchCost
+		baseCost += virtualScanTableDescriptorFetchCost * c.randIOCostFactor
 	}
 
 	// Add a penalty to full table scans. All else being equal, we prefer a
@@ -606,7 +626,7 @@ func (c *coster) computeScanCost(scan *memo.ScanExpr, required *physical.Require
 		if partitionCount := index.PartitionCount(); partitionCount > 1 {
 			// Subtract 1 since we already accounted for the first partition when
 			// counting spans.
-			baseCost += memo.Cost(partitionCount-1) * randIOCostFactor
+			baseCost += memo.Cost(partitionCount-1) * c.randIOCostFactor
 		}
 	}
 
@@ -617,11 +637,11 @@ func (c *coster) computeScanCost(scan *memo.ScanExpr, required *physical.Require
 	if ordering.ScanIsReverse(scan, &required.Ordering) {
 		if rowCount > 1 {
 			// Need to do binary search to seek to the previous row.
-			perRowCost += memo.Cost(math.Log2(rowCount)) * cpuCostFactor
+			perRowCost += memo.Cost(math.Log2(rowCount)) * c.cpuCostFactor
 		}
 	}
 
-	cost := baseCost + memo.Cost(rowCount)*(seqIOCostFactor+perRowCost)
+	cost := baseCost + memo.Cost(rowCount)*(c.seqIOCostFactor+perRowCost)
 
 	// If this scan is locality optimized, divide the cost in two in order to make
 	// the total cost of the two scans in the locality optimized plan less than
@@ -655,22 +675,22 @@ func (c *coster) computeProjectCost(prj *memo.ProjectExpr) memo.Cost {
 	// Each synthesized column causes an expression to be evaluated on each row.
 	rowCount := prj.Relational().Stats.RowCount
 	synthesizedColCount := len(prj.Projections)
-	cost := memo.Cost(rowCount) * memo.Cost(synthesizedColCount) * cpuCostFactor
+	cost := memo.Cost(rowCount) * memo.Cost(synthesizedColCount) * c.cpuCostFactor
 
 	// Add the CPU cost of emitting the rows.
-	cost += memo.Cost(rowCount) * cpuCostFactor
+	cost += memo.Cost(rowCount) * c.cpuCostFactor
 	return cost
 }
 
 func (c *coster) computeInvertedFilterCost(invFilter *memo.InvertedFilterExpr) memo.Cost {
 	// The filter has to be evaluated on each input row.
 	inputRowCount := invFilter.Input.Relational().Stats.RowCount
-	cost := memo.Cost(inputRowCount) * cpuCostFactor
+	cost := memo.Cost(inputRowCount) * c.cpuCostFactor
 	return cost
 }
 
 func (c *coster) computeValuesCost(values *memo.ValuesExpr) memo.Cost {
-	return memo.Cost(values.Relational().Stats.RowCount) * cpuCostFactor
+	return memo.Cost(values.Relational().Stats.RowCount) * c.cpuCostFactor
 }
 
 func (c *coster) computeHashJoinCost(join memo.RelExpr) memo.Cost {
@@ -698,7 +718,7 @@ func (c *coster) computeHashJoinCost(join memo.RelExpr) memo.Cost {
 	// TODO(rytaft): This is the cost of an in-memory hash join. When a certain
 	// amount of memory is used, distsql switches to a disk-based hash join with
 	// a temp RocksDB store.
-	cost := memo.Cost(1.25*leftRowCount+1.75*rightRowCount) * cpuCostFactor
+	cost := memo.Cost(1.25*leftRowCount+1.75*rightRowCount) * c.cpuCostFactor
 
 	// Compute filter cost. Fetch the equality columns so they can be
 	// ignored later.
@@ -740,7 +760,7 @@ func (c *coster) computeMergeJoinCost(join *memo.MergeJoinExpr) memo.Cost {
 	leftRowCount := join.Left.Relational().Stats.RowCount
 	rightRowCount := join.Right.Relational().Stats.RowCount
 
-	cost := memo.Cost(leftRowCount+rightRowCount) * cpuCostFactor
+	cost := memo.Cost(leftRowCount+rightRowCount) * c.cpuCostFactor
 
 	filterSetup, filterPerRow := c.computeFiltersCost(join.On, util.FastIntMap{})
 	cost += filterSetup
@@ -839,7 +859,7 @@ func (c *coster) computeIndexLookupJoinCost(
 	// The rows in the (left) input are used to probe into the (right) table.
 	// Since the matching rows in the table may not all be in the same range, this
 	// counts as random I/O.
-	perLookupCost := memo.Cost(randIOCostFactor)
+	perLookupCost := c.randIOCostFactor
 	if !lookupColsAreTableKey {
 		// If the lookup columns don't form a key, execution will have to limit
 		// KV batches which prevents running requests to multiple nodes in parallel.
@@ -851,7 +871,7 @@ func (c *coster) computeIndexLookupJoinCost(
 	if c.mem.Metadata().Table(table).IsVirtualTable() {
 		// It's expensive to perform a lookup join into a virtual table because
 		// we need to fetch the table descriptors on each lookup.
-		perLookupCost += virtualScanTableDescriptorFetchCost
+		perLookupCost += virtualScanTableDescriptorFetchCost * c.randIOCostFactor
 	}
 	cost := memo.Cost(lookupCount) * perLookupCost
 
@@ -862,7 +882,7 @@ func (c *coster) computeIndexLookupJoinCost(
 	// rows (relevant when we expect many resulting rows per lookup) and the CPU
 	// cost of emitting the rows.
 	numLookupCols := cols.Difference(input.Relational().OutputCols).Len()
-	perRowCost := lookupJoinRetrieveRowCost + filterPerRow +
+	perRowCost := LookupJoinRetrieveRowCost*c.seqIOCostFactor + filterPerRow +
 		c.rowScanCost(table, index, numLookupCols)
 
 	cost += memo.Cost(rowsProcessed) * perRowCost
@@ -923,7 +943,7 @@ func (c *coster) computeInvertedJoinCost(
 	// The rows in the (left) input are used to probe into the (right) table.
 	// Since the matching rows in the table may not all be in the same range, this
 	// counts as random I/O.
-	perLookupCost := memo.Cost(randIOCostFactor)
+	perLookupCost := c.randIOCostFactor
 	// Since inverted indexes can't form a key, execution will have to
 	// limit KV batches which prevents running requests to multiple nodes
 	// in parallel.  An experiment on a 4 node cluster with a table with
@@ -939,7 +959,7 @@ func (c *coster) computeInvertedJoinCost(
 	// rows (relevant when we expect many resulting rows per lookup) and the CPU
 	// cost of emitting the rows.
 	numLookupCols := join.Cols.Difference(join.Input.Relational().OutputCols).Len()
-	perRowCost := lookupJoinRetrieveRowCost + filterPerRow +
+	perRowCost := LookupJoinRetrieveRowCost*c.seqIOCostFactor + filterPerRow +
 		c.rowScanCost(join.Table, join.Index, numLookupCols)
 
 	cost += memo.Cost(rowsProcessed) * perRowCost
@@ -954,7 +974,7 @@ func (c *coster) computeFiltersCost(
 ) (setupCost, perRowCost memo.Cost) {
 	// Add a base perRowCost so that callers do not need to have their own
 	// base per-row cost.
-	perRowCost += cpuCostFactor
+	perRowCost += c.cpuCostFactor
 	for i := range filters {
 		f := &filters[i]
 		switch f.Condition.Op() {
@@ -977,13 +997,13 @@ func (c *coster) computeFiltersCost(
 		case opt.FunctionOp:
 			function := f.Condition.(*memo.FunctionExpr)
 			// We are ok with the zero value here for functions not in the map.
-			perRowCost += fnCost[function.Name]
+			perRowCost += fnCost[function.Name] * (c.cpuCostFactor / cpuCostFactor)
 		}
 
 		// Add a constant "setup" cost per ON condition to account for the fact that
 		// the rowsProcessed estimate alone cannot effectively discriminate between
 		// plans when RowCount is too small.
-		setupCost += cpuCostFactor
+		setupCost += c.cpuCostFactor
 	}
 	return setupCost, perRowCost
 }
@@ -1009,14 +1029,14 @@ func (c *coster) computeZigzagJoinCost(join *memo.ZigzagJoinExpr) memo.Cost {
 
 	// Double the cost of emitting rows as well as the cost of seeking rows,
 	// given two indexes will be accessed.
-	cost := memo.Cost(rowCount) * (2*(cpuCostFactor+seqIOCostFactor) + scanCost + filterPerRow)
+	cost := memo.Cost(rowCount) * (2*(c.cpuCostFactor+c.seqIOCostFactor) + scanCost + filterPerRow)
 	cost += filterSetup
 	return cost
 }
 
 func (c *coster) computeSetCost(set memo.RelExpr) memo.Cost {
 	// Add the CPU cost of emitting the rows.
-	cost := memo.Cost(set.Relational().Stats.RowCount) * cpuCostFactor
+	cost := memo.Cost(set.Relational().Stats.RowCount) * c.cpuCostFactor
 
 	// A set operation must process every row from both tables once. UnionAll and
 	// LocalityOptimizedSearch can avoid any extra computation, but all other set
@@ -1024,7 +1044,7 @@ func (c *coster) computeSetCost(set memo.RelExpr) memo.Cost {
 	if set.Op() != opt.UnionAllOp && set.Op() != opt.LocalityOptimizedSearchOp {
 		leftRowCount := set.Child(0).(memo.RelExpr).Relational().Stats.RowCount
 		rightRowCount := set.Child(1).(memo.RelExpr).Relational().Stats.RowCount
-		cost += memo.Cost(leftRowCount+rightRowCount) * cpuCostFactor
+		cost += memo.Cost(leftRowCount+rightRowCount) * c.cpuCostFactor
 	}
 
 	return cost
@@ -1034,10 +1054,10 @@ func (c *coster) computeGroupingCost(grouping memo.RelExpr, required *physical.R
 	// Start with some extra fixed overhead, since the grouping operators have
 	// setup overhead that is greater than other operators like Project. This
 	// can matter for rules like ReplaceMaxWithLimit.
-	cost := memo.Cost(cpuCostFactor)
+	cost := c.cpuCostFactor
 
 	// Add the CPU cost of emitting the rows.
-	cost += memo.Cost(grouping.Relational().Stats.RowCount) * cpuCostFactor
+	cost += memo.Cost(grouping.Relational().Stats.RowCount) * c.cpuCostFactor
 
 	// GroupBy must process each input row once. Cost per row depends on the
 	// number of grouping columns and the number of aggregates.
@@ -1045,7 +1065,7 @@ func (c *coster) computeGroupingCost(grouping memo.RelExpr, required *physical.R
 	aggsCount := grouping.Child(1).ChildCount()
 	private := grouping.Private().(*memo.GroupingPrivate)
 	groupingColCount := private.GroupingCols.Len()
-	cost += memo.Cost(inputRowCount) * memo.Cost(aggsCount+groupingColCount) * cpuCostFactor
+	cost += memo.Cost(inputRowCount) * memo.Cost(aggsCount+groupingColCount) * c.cpuCostFactor
 
 	if groupingColCount > 0 {
 		// Add a cost that reflects the use of a hash table - unless we are doing a
@@ -1054,7 +1074,7 @@ func (c *coster) computeGroupingCost(grouping memo.RelExpr, required *physical.R
 		//
 		// The cost is chosen so that it's always less than the cost to sort the
 		// input.
-		hashCost := memo.Cost(inputRowCount) * cpuCostFactor
+		hashCost := memo.Cost(inputRowCount) * c.cpuCostFactor
 		n := len(ordering.StreamingGroupingColOrdering(private, &required.Ordering))
 		// n = 0:                factor = 1
 		// n = groupingColCount: factor = 0
@@ -1067,25 +1087,25 @@ func (c *coster) computeGroupingCost(grouping memo.RelExpr, required *physical.R
 
 func (c *coster) computeLimitCost(limit *memo.LimitExpr) memo.Cost {
 	// Add the CPU cost of emitting the rows.
-	cost := memo.Cost(limit.Relational().Stats.RowCount) * cpuCostFactor
+	cost := memo.Cost(limit.Relational().Stats.RowCount) * c.cpuCostFactor
 	return cost
 }
 
 func (c *coster) computeOffsetCost(offset *memo.OffsetExpr) memo.Cost {
 	// Add the CPU cost of emitting the rows.
-	cost := memo.Cost(offset.Relational().Stats.RowCount) * cpuCostFactor
+	cost := memo.Cost(offset.Relational().Stats.RowCount) * c.cpuCostFactor
 	return cost
 }
 
 func (c *coster) computeOrdinalityCost(ord *memo.OrdinalityExpr) memo.Cost {
 	// Add the CPU cost of emitting the rows.
-	cost := memo.Cost(ord.Relational().Stats.RowCount) * cpuCostFactor
+	cost := memo.Cost(ord.Relational().Stats.RowCount) * c.cpuCostFactor
 	return cost
 }
 
 func (c *coster) computeProjectSetCost(projectSet *memo.ProjectSetExpr) memo.Cost {
 	// Add the CPU cost of emitting the rows.
-	cost := memo.Cost(projectSet.Relational().Stats.RowCount) * cpuCostFactor
+	cost := memo.Cost(projectSet.Relational().Stats.RowCount) * c.cpuCostFactor
 	return cost
 }
 
@@ -1123,10 +1143,10 @@ func (c *coster) rowCmpCost(numKeyCols int) memo.Cost {
 	//   cpuCostFactor * [ 1 + Sum eqProb^(i-1) with i=1 to numKeyCols ]
 	//
 	const eqProb = 0.1
-	cost := cpuCostFactor
-	for i, c := 0, cpuCostFactor; i < numKeyCols; i, c = i+1, c*eqProb {
-		// c is cpuCostFactor * eqProb^i.
-		cost += c
+	cost := c.cpuCostFactor
+	for i, cmp := 0, c.cpuCostFactor; i < numKeyCols; i, cmp = i+1, cmp*eqProb {
+		// cmp is cpuCostFactor * eqProb^i.
+		cost += cmp
 	}
 
 	// There is a fixed "non-comparison" cost and a comparison cost proportional
@@ -1152,14 +1172,14 @@ func (c *coster) rowScanCost(tabID opt.TableID, idxOrd int, numScannedCols int)
 
 	// Adjust cost based on how well the current locality matches the index's
 	// zone constraints.
-	var costFactor memo.Cost = cpuCostFactor
+	costFactor := c.cpuCostFactor
 	if !tab.IsVirtualTable() && len(c.locality.Tiers) != 0 {
 		// If 0% of locality tiers have matching constraints, then add additional
 		// cost. If 100% of locality tiers have matching constraints, then add no
 		// additional cost. Anything in between is proportional to the number of
 		// matches.
 		adjustment := 1.0 - localityMatchScore(idx.Zone(), c.locality)
-		costFactor += latencyCostFactor * memo.Cost(adjustment)
+		costFactor += latencyCostFactor * c.cpuCostFactor * memo.Cost(adjustment)
 	}
 
 	// The number of the columns in the index matter because more columns means
@@ -910,26 +910,28 @@ func (a *groupStateAlloc) allocate() *groupState {
 	return state
 }
 
+// essentialRules are the rules that cannot be disabled without causing
+// planning errors.
+var essentialRules = util.MakeFastIntSet(
+	// Needed to prevent constraint building from failing.
+	int(opt.NormalizeInConst),
+	// Needed when an index is forced.
+	int(opt.GenerateIndexScans),
+	// Needed to prevent "same fingerprint cannot map to different groups."
+	int(opt.PruneJoinLeftCols),
+	int(opt.PruneJoinRightCols),
+	// Needed to prevent stack overflow.
+	int(opt.PushFilterIntoJoinLeftAndRight),
+	int(opt.PruneSelectCols),
+	// Needed to prevent execbuilder error.
+	// TODO(radu): the DistinctOn execution path should be fixed up so it
+	// supports distinct on an empty column set.
+	int(opt.EliminateDistinctNoColumns),
+	int(opt.EliminateEnsureDistinctNoColumns),
+)
+
 // disableRules disables rules with the given probability for testing.
 func (o *Optimizer) disableRules(probability float64) {
-	essentialRules := util.MakeFastIntSet(
-		// Needed to prevent constraint building from failing.
-		int(opt.NormalizeInConst),
-		// Needed when an index is forced.
-		int(opt.GenerateIndexScans),
-		// Needed to prevent "same fingerprint cannot map to different groups."
-		int(opt.PruneJoinLeftCols),
-		int(opt.PruneJoinRightCols),
-		// Needed to prevent stack overflow.
-		int(opt.PushFilterIntoJoinLeftAndRight),
-		int(opt.PruneSelectCols),
-		// Needed to prevent execbuilder error.
-		// TODO(radu): the DistinctOn execution path should be fixed up so it
-		// supports distinct on an empty column set.
-		int(opt.EliminateDistinctNoColumns),
-		int(opt.EliminateEnsureDistinctNoColumns),
-	)
-
 	for i := opt.RuleName(1); i < opt.NumRuleNames; i++ {
 		if rand.Float64() < probability && !essentialRules.Contains(int(i)) {
 			o.disabledRules.Add(int(i))
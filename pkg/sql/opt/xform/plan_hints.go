@@ -0,0 +1,354 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package xform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/planbaselines/planbaselinespb"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
+)
+
+// CapturePlanHints returns the PlanHints that describe the lowest cost plan of
+// the given memo, which must be fully optimized. Planning the same statement
+// with the returned hints (see SetPlanHints) reproduces the same table
+// accesses and joins, as long as the plan is still possible.
+func CapturePlanHints(mem *memo.Memo) planbaselinespb.PlanHints {
+	var hints planbaselinespb.PlanHints
+	md := mem.Metadata()
+	var walk func(e opt.Expr)
+	walk = func(e opt.Expr) {
+		if rel, ok := e.(memo.RelExpr); ok {
+			forEachAccess(rel, func(tabID opt.TableID, idx cat.IndexOrdinal) {
+				tab := md.Table(tabID)
+				hints.Accesses = append(hints.Accesses, planbaselinespb.PlanHints_Access{
+					TableOrdinal: tableOrdinal(md, tabID),
+					TableID:      uint64(tab.ID()),
+					IndexID:      uint64(tab.Index(idx).ID()),
+					Method:       rel.Op().String(),
+				})
+			})
+			if method, left, right, ok := joinTables(md, rel); ok {
+				hints.Joins = append(hints.Joins, planbaselinespb.PlanHints_Join{
+					Method:      method,
+					LeftTables:  tableSetToOrdinals(left),
+					RightTables: tableSetToOrdinals(right),
+				})
+			}
+		}
+		for i, n := 0, e.ChildCount(); i < n; i++ {
+			walk(e.Child(i))
+		}
+	}
+	walk(mem.RootExpr())
+	return hints
+}
+
+// ValidatePlanHints returns an error if the given hints cannot be honored
+// when planning the statement that was built into the given metadata, because
+// a table reference no longer resolves to the same table or an index that the
+// hints access no longer exists.
+func ValidatePlanHints(md *opt.Metadata, hints *planbaselinespb.PlanHints) error {
+	numTables := len(md.AllTables())
+	for i := range hints.Accesses {
+		a := &hints.Accesses[i]
+		if a.TableOrdinal <= 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "invalid table reference %d", a.TableOrdinal)
+		}
+		if int(a.TableOrdinal) > numTables {
+			// Table references can also be added during exploration (e.g. when a
+			// scan is split into a union of scans), so they can only be verified
+			// once the memo is optimized.
+			continue
+		}
+		tab := md.AllTables()[a.TableOrdinal-1].Table
+		if uint64(tab.ID()) != a.TableID {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"table reference %d no longer refers to table %d", a.TableOrdinal, a.TableID)
+		}
+		if findIndexByID(tab, a.IndexID) == -1 {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"index %d of table %s no longer exists", a.IndexID, tab.Name())
+		}
+	}
+	return nil
+}
+
+// PlanHintsEqual returns true if the two hints describe the same table
+// accesses and joins, regardless of their order.
+func PlanHintsEqual(left, right *planbaselinespb.PlanHints) bool {
+	l, r := makePlanHintSet(left), makePlanHintSet(right)
+	if len(l.accesses) != len(r.accesses) || len(l.joins) != len(r.joins) {
+		return false
+	}
+	for k := range l.accesses {
+		if _, ok := r.accesses[k]; !ok {
+			return false
+		}
+	}
+	for k := range l.joins {
+		if _, ok := r.joins[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatPlanHints returns a human-readable description of the given hints,
+// naming tables and indexes as they are resolved in the given metadata. For
+// example:
+//
+//   scan abc@abc_b_idx, lookup-join def@primary, lookup-join/inner-join (abc) (def)
+//
+func FormatPlanHints(md *opt.Metadata, hints *planbaselinespb.PlanHints) string {
+	numTables := len(md.AllTables())
+	tableName := func(ord int32) string {
+		if ord <= 0 || int(ord) > numTables {
+			return fmt.Sprintf("[%d]", ord)
+		}
+		return string(md.AllTables()[ord-1].Alias.ObjectName)
+	}
+	tableNames := func(ords []int32) string {
+		names := make([]string, len(ords))
+		for i, ord := range ords {
+			names[i] = tableName(ord)
+		}
+		return "(" + strings.Join(names, ", ") + ")"
+	}
+
+	var parts []string
+	for i := range hints.Accesses {
+		a := &hints.Accesses[i]
+		idxName := fmt.Sprintf("[%d]", a.IndexID)
+		if a.TableOrdinal > 0 && int(a.TableOrdinal) <= numTables {
+			tab := md.AllTables()[a.TableOrdinal-1].Table
+			if ord := findIndexByID(tab, a.IndexID); ord != -1 {
+				idxName = string(tab.Index(ord).Name())
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s %s@%s", a.Method, tableName(a.TableOrdinal), idxName))
+	}
+	for i := range hints.Joins {
+		j := &hints.Joins[i]
+		parts = append(parts, fmt.Sprintf(
+			"%s %s %s", j.Method, tableNames(j.LeftTables), tableNames(j.RightTables),
+		))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SetPlanHints makes the optimizer avoid any table access or join that is not
+// part of the given hints, so that it chooses the plan that the hints describe
+// whenever that plan is possible. Like index and join hints, the avoided
+// expressions are not prohibited but assigned a huge cost, so a plan is still
+// produced if the hints cannot be satisfied; callers should compare the hints
+// of the resulting plan (see CapturePlanHints) to find out.
+//
+// SetPlanHints must be called after the statement is built into the memo and
+// before it is optimized. The hints should have been checked with
+// ValidatePlanHints.
+func (o *Optimizer) SetPlanHints(hints *planbaselinespb.PlanHints) {
+	o.coster = &planHintsCoster{
+		wrapped: o.coster,
+		md:      o.mem.Metadata(),
+		set:     makePlanHintSet(hints),
+	}
+}
+
+// planHintsCoster is a Coster that wraps another Coster and adds a huge cost
+// to the expressions that access tables or join them in a way that is not
+// allowed by a set of plan hints.
+type planHintsCoster struct {
+	wrapped Coster
+	md      *opt.Metadata
+	set     planHintSet
+}
+
+var _ Coster = &planHintsCoster{}
+
+// ComputeCost is part of the Coster interface.
+func (c *planHintsCoster) ComputeCost(candidate memo.RelExpr, required *physical.Required) memo.Cost {
+	cost := c.wrapped.ComputeCost(candidate, required)
+	allowed := true
+	forEachAccess(candidate, func(tabID opt.TableID, idx cat.IndexOrdinal) {
+		key := accessKey{
+			table:  tableOrdinal(c.md, tabID),
+			index:  uint64(c.md.Table(tabID).Index(idx).ID()),
+			method: candidate.Op().String(),
+		}
+		if _, ok := c.set.accesses[key]; !ok {
+			allowed = false
+		}
+	})
+	if method, left, right, ok := joinTables(c.md, candidate); ok {
+		key := joinKey{method: method, left: left.String(), right: right.String()}
+		if _, ok := c.set.joins[key]; !ok {
+			allowed = false
+		}
+	}
+	if !allowed {
+		cost += hugeCost
+	}
+	return cost
+}
+
+type accessKey struct {
+	table  int32
+	index  uint64
+	method string
+}
+
+type joinKey struct {
+	method      string
+	left, right string
+}
+
+// planHintSet is the representation of PlanHints used to look up accesses and
+// joins. Table IDs are not part of the keys, since they are validated up front
+// by ValidatePlanHints.
+type planHintSet struct {
+	accesses map[accessKey]struct{}
+	joins    map[joinKey]struct{}
+}
+
+func makePlanHintSet(hints *planbaselinespb.PlanHints) planHintSet {
+	set := planHintSet{
+		accesses: make(map[accessKey]struct{}, len(hints.Accesses)),
+		joins:    make(map[joinKey]struct{}, len(hints.Joins)),
+	}
+	for i := range hints.Accesses {
+		a := &hints.Accesses[i]
+		set.accesses[accessKey{table: a.TableOrdinal, index: a.IndexID, method: a.Method}] = struct{}{}
+	}
+	for i := range hints.Joins {
+		j := &hints.Joins[i]
+		key := joinKey{
+			method: j.Method,
+			left:   ordinalsToTableSet(j.LeftTables).String(),
+			right:  ordinalsToTableSet(j.RightTables).String(),
+		}
+		set.joins[key] = struct{}{}
+	}
+	return set
+}
+
+// forEachAccess calls fn for each index of a base table that the given
+// expression reads directly.
+func forEachAccess(e memo.RelExpr, fn func(tabID opt.TableID, idx cat.IndexOrdinal)) {
+	switch t := e.(type) {
+	case *memo.ScanExpr:
+		fn(t.Table, t.Index)
+	case *memo.IndexJoinExpr:
+		fn(t.Table, cat.PrimaryIndex)
+	case *memo.LookupJoinExpr:
+		fn(t.Table, t.Index)
+	case *memo.InvertedJoinExpr:
+		fn(t.Table, t.Index)
+	case *memo.ZigzagJoinExpr:
+		fn(t.LeftTable, t.LeftIndex)
+		fn(t.RightTable, t.RightIndex)
+	}
+}
+
+// joinTables returns the method of the given expression if it is a join, along
+// with the ordinals (see tableOrdinal) of the table references whose columns
+// are produced by its left and right inputs. For lookup and inverted joins,
+// the right input is the table that is looked up. ok is false if the
+// expression is not a join.
+func joinTables(
+	md *opt.Metadata, e memo.RelExpr,
+) (method string, left, right util.FastIntSet, ok bool) {
+	switch t := e.(type) {
+	case *memo.InnerJoinExpr, *memo.LeftJoinExpr, *memo.RightJoinExpr, *memo.FullJoinExpr,
+		*memo.SemiJoinExpr, *memo.AntiJoinExpr, *memo.InnerJoinApplyExpr,
+		*memo.LeftJoinApplyExpr, *memo.SemiJoinApplyExpr, *memo.AntiJoinApplyExpr:
+		left = outputTables(md, e.Child(0).(memo.RelExpr))
+		right = outputTables(md, e.Child(1).(memo.RelExpr))
+		return e.Op().String(), left, right, true
+
+	case *memo.MergeJoinExpr:
+		left = outputTables(md, t.Left)
+		right = outputTables(md, t.Right)
+		return fmt.Sprintf("%s/%s", t.Op(), t.JoinType), left, right, true
+
+	case *memo.LookupJoinExpr:
+		right.Add(int(tableOrdinal(md, t.Table)))
+		return fmt.Sprintf("%s/%s", t.Op(), t.JoinType), outputTables(md, t.Input), right, true
+
+	case *memo.InvertedJoinExpr:
+		right.Add(int(tableOrdinal(md, t.Table)))
+		return fmt.Sprintf("%s/%s", t.Op(), t.JoinType), outputTables(md, t.Input), right, true
+	}
+	return "", util.FastIntSet{}, util.FastIntSet{}, false
+}
+
+// outputTables returns the ordinals of the table references that have columns
+// in the output of the given expression. Since it only depends on the logical
+// properties of the expression, it is the same for all members of its group.
+func outputTables(md *opt.Metadata, e memo.RelExpr) util.FastIntSet {
+	var tables util.FastIntSet
+	e.Relational().OutputCols.ForEach(func(col opt.ColumnID) {
+		if tabID := md.ColumnMeta(col).Table; tabID != 0 {
+			tables.Add(int(tableOrdinal(md, tabID)))
+		}
+	})
+	return tables
+}
+
+// tableOrdinal returns the 1-based position of the given table reference in
+// the metadata. Unlike the opt.TableID, which also encodes the IDs of the
+// table's columns, the ordinal only depends on the order in which the
+// statement references its tables, so it is the same each time the statement
+// is built.
+func tableOrdinal(md *opt.Metadata, tabID opt.TableID) int32 {
+	tables := md.AllTables()
+	for i := range tables {
+		if tables[i].MetaID == tabID {
+			return int32(i + 1)
+		}
+	}
+	panic(errors.AssertionFailedf("table %d not found in metadata", tabID))
+}
+
+func tableSetToOrdinals(tables util.FastIntSet) []int32 {
+	ords := make([]int32, 0, tables.Len())
+	tables.ForEach(func(i int) {
+		ords = append(ords, int32(i))
+	})
+	return ords
+}
+
+func ordinalsToTableSet(ords []int32) util.FastIntSet {
+	var tables util.FastIntSet
+	for _, ord := range ords {
+		tables.Add(int(ord))
+	}
+	return tables
+}
+
+// findIndexByID returns the ordinal of the public index of the given table
+// with the given stable ID, or -1 if there is none.
+func findIndexByID(tab cat.Table, id uint64) cat.IndexOrdinal {
+	for i, n := 0, tab.IndexCount(); i < n; i++ {
+		if uint64(tab.Index(i).ID()) == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,195 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package xform
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// SetStatementHints makes the optimizer follow the join order, join algorithm
+// and disabled rule hints of a statement. Index hints are applied by
+// optbuilder instead. Like the join hints in the statement text, the join
+// order and algorithm hints don't prohibit other joins but assign them a huge
+// cost, so a plan is still produced if the hints cannot be satisfied.
+//
+// SetStatementHints must be called before the statement is built into the
+// memo, so that disabled normalization rules are not applied while building
+// it. It returns an error if a disabled rule does not exist or cannot be
+// disabled.
+func (o *Optimizer) SetStatementHints(hints *tree.StatementHints) error {
+	if len(hints.DisabledRules) > 0 {
+		var disabled RuleSet
+		for _, name := range hints.DisabledRules {
+			rule, ok := lookupRuleName(string(name))
+			if !ok {
+				return pgerror.Newf(pgcode.InvalidParameterValue, "unknown optimizer rule %q", name)
+			}
+			if essentialRules.Contains(int(rule)) {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					"optimizer rule %s cannot be disabled", rule)
+			}
+			disabled.Add(int(rule))
+		}
+		matchedRule := o.matchedRule
+		o.NotifyOnMatchedRule(func(ruleName opt.RuleName) bool {
+			if disabled.Contains(int(ruleName)) {
+				return false
+			}
+			return matchedRule == nil || matchedRule(ruleName)
+		})
+	}
+	if len(hints.Leading) > 0 || len(hints.Joins) > 0 {
+		o.coster = &joinHintsCoster{
+			wrapped: o.coster,
+			md:      o.mem.Metadata(),
+			hints:   hints,
+		}
+	}
+	return nil
+}
+
+// lookupRuleName returns the rule with the given name, ignoring case.
+func lookupRuleName(name string) (_ opt.RuleName, ok bool) {
+	for i := opt.RuleName(1); i < opt.NumRuleNames; i++ {
+		if strings.EqualFold(i.String(), name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// joinHintsCoster is a Coster that wraps another Coster and adds a huge cost
+// to the joins that don't follow the LEADING and join algorithm hints of a
+// statement. Tables are identified by their alias in the metadata.
+type joinHintsCoster struct {
+	wrapped Coster
+	md      *opt.Metadata
+	hints   *tree.StatementHints
+}
+
+var _ Coster = &joinHintsCoster{}
+
+// ComputeCost is part of the Coster interface.
+func (c *joinHintsCoster) ComputeCost(candidate memo.RelExpr, required *physical.Required) memo.Cost {
+	cost := c.wrapped.ComputeCost(candidate, required)
+	if algorithm, left, right, ok := c.joinTableNames(candidate); ok {
+		if !c.followsJoinHints(algorithm, left, right) || !c.followsLeadingHint(left, right) {
+			cost += hugeCost
+		}
+	}
+	return cost
+}
+
+// joinTableNames returns the algorithm of the given expression if it is a
+// join, as one of the tree.Ast* join hints (or the empty string for apply
+// joins), along with the names of the tables produced by its left and right
+// inputs. ok is false if the expression is not a join.
+func (c *joinHintsCoster) joinTableNames(
+	e memo.RelExpr,
+) (algorithm string, left, right []tree.Name, ok bool) {
+	_, leftTables, rightTables, ok := joinTables(c.md, e)
+	if !ok {
+		return "", nil, nil, false
+	}
+	switch e.Op() {
+	case opt.InnerJoinOp, opt.LeftJoinOp, opt.RightJoinOp, opt.FullJoinOp,
+		opt.SemiJoinOp, opt.AntiJoinOp:
+		algorithm = tree.AstHash
+	case opt.MergeJoinOp:
+		algorithm = tree.AstMerge
+	case opt.LookupJoinOp:
+		algorithm = tree.AstLookup
+	case opt.InvertedJoinOp:
+		algorithm = tree.AstInverted
+	}
+	tables := c.md.AllTables()
+	tableNames := func(ords []int32) []tree.Name {
+		names := make([]tree.Name, len(ords))
+		for i, ord := range ords {
+			names[i] = tables[ord-1].Alias.ObjectName
+		}
+		return names
+	}
+	left = tableNames(tableSetToOrdinals(leftTables))
+	right = tableNames(tableSetToOrdinals(rightTables))
+	return algorithm, left, right, true
+}
+
+// followsJoinHints returns false if there is a join algorithm hint for the
+// tables of both join inputs that requires another algorithm.
+func (c *joinHintsCoster) followsJoinHints(algorithm string, left, right []tree.Name) bool {
+	for i := range c.hints.Joins {
+		h := &c.hints.Joins[i]
+		if h.Algorithm == algorithm || len(h.Tables) != len(left)+len(right) {
+			continue
+		}
+		matches := true
+		for _, name := range h.Tables {
+			if !containsName(left, name) && !containsName(right, name) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return false
+		}
+	}
+	return true
+}
+
+// followsLeadingHint returns false if the join is not consistent with the
+// order of the LEADING hint. Only the tables of the hint are considered: if
+// both inputs have such tables, the left input must have the first n-1 of
+// them and the right input the n-th one.
+func (c *joinHintsCoster) followsLeadingHint(left, right []tree.Name) bool {
+	leading := c.hints.Leading
+	if len(leading) == 0 {
+		return true
+	}
+	var numLeft, numRight int
+	for _, name := range leading {
+		if containsName(left, name) {
+			numLeft++
+		}
+		if containsName(right, name) {
+			numRight++
+		}
+	}
+	if numLeft == 0 || numRight == 0 {
+		return true
+	}
+	n := numLeft + numRight
+	if numRight != 1 || !containsName(right, leading[n-1]) {
+		return false
+	}
+	for _, name := range leading[:n-1] {
+		if !containsName(left, name) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsName(names []tree.Name, name tree.Name) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -325,6 +326,31 @@ func (oc *optCatalog) HasRoleOption(
 	return oc.planner.HasRoleOption(ctx, roleOption)
 }
 
+// HasOwnership is part of the cat.Catalog interface.
+func (oc *optCatalog) HasOwnership(ctx context.Context, o cat.Object) (bool, error) {
+	desc, err := getDescFromCatalogObjectForPermissions(o)
+	if err != nil {
+		return false, err
+	}
+	return oc.planner.HasOwnership(ctx, desc)
+}
+
+// IsMemberOfRole is part of the cat.Catalog interface.
+func (oc *optCatalog) IsMemberOfRole(
+	ctx context.Context, role security.SQLUsername,
+) (bool, error) {
+	user := oc.planner.User()
+	if user == role {
+		return true, nil
+	}
+	memberOf, err := oc.planner.MemberOfWithAdminOption(ctx, user)
+	if err != nil {
+		return false, err
+	}
+	_, ok := memberOf[role]
+	return ok, nil
+}
+
 // FullyQualifiedName is part of the cat.Catalog interface.
 func (oc *optCatalog) FullyQualifiedName(
 	ctx context.Context, ds cat.DataSource,
@@ -419,7 +445,7 @@ func (oc *optCatalog) dataSourceForTable(
 	var tableStats []*stats.TableStatistic
 	if !flags.NoTableStats {
 		var err error
-		tableStats, err = oc.planner.execCfg.TableStatsCache.GetTableStats(context.TODO(), desc.GetID())
+		tableStats, err = oc.planner.execCfg.TableStatsCache.GetTableStatsWithForecasts(context.TODO(), desc.GetID())
 		if err != nil {
 			// Ignore any error. We still want to be able to run queries even if we lose
 			// access to the statistics table.
@@ -658,7 +684,9 @@ func newOptTable(
 		switch {
 		case col.Public():
 			kind = cat.Ordinary
-			if col.IsHidden() {
+			if col.IsInaccessible() {
+				visibility = cat.Inaccessible
+			} else if col.IsHidden() {
 				visibility = cat.Hidden
 			}
 		case col.WriteAndDeleteOnly():
@@ -735,12 +763,13 @@ func newOptTable(
 	for i := range ot.desc.GetUniqueWithoutIndexConstraints() {
 		u := &ot.desc.GetUniqueWithoutIndexConstraints()[i]
 		ot.uniqueConstraints = append(ot.uniqueConstraints, optUniqueConstraint{
-			name:         u.Name,
-			table:        ot.ID(),
-			columns:      u.ColumnIDs,
-			predicate:    u.Predicate,
-			withoutIndex: true,
-			validity:     u.Validity,
+			name:          u.Name,
+			table:         ot.ID(),
+			columns:       u.ColumnIDs,
+			predicate:     u.Predicate,
+			withoutIndex:  true,
+			validity:      u.Validity,
+			deferrability: u.Deferrability,
 		})
 	}
 
@@ -828,6 +857,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability,
 		})
 	}
 	for i := range ot.desc.GetInboundFKs() {
@@ -842,6 +872,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability,
 		})
 	}
 
@@ -1107,6 +1138,45 @@ func (ot *optTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &ot.uniqueConstraints[i]
 }
 
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optTable) IsRowLevelSecurityEnabled() bool {
+	return ot.desc.GetRowLevelSecurity()
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optTable) PolicyCount() int {
+	return len(ot.desc.GetPolicies())
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optTable) Policy(i int) cat.Policy {
+	p := &ot.desc.GetPolicies()[i]
+	policy := cat.Policy{
+		Name:          tree.Name(p.Name),
+		UsingExpr:     p.UsingExpr,
+		WithCheckExpr: p.WithCheckExpr,
+	}
+	switch p.Command {
+	case descpb.TableDescriptor_Policy_ALL:
+		policy.Command = tree.PolicyAll
+	case descpb.TableDescriptor_Policy_SELECT:
+		policy.Command = tree.PolicySelect
+	case descpb.TableDescriptor_Policy_INSERT:
+		policy.Command = tree.PolicyInsert
+	case descpb.TableDescriptor_Policy_UPDATE:
+		policy.Command = tree.PolicyUpdate
+	case descpb.TableDescriptor_Policy_DELETE:
+		policy.Command = tree.PolicyDelete
+	default:
+		panic(errors.AssertionFailedf("unknown policy command %s", p.Command))
+	}
+	policy.Roles = make([]security.SQLUsername, len(p.Roles))
+	for j, role := range p.Roles {
+		policy.Roles[j] = role.Decode()
+	}
+	return policy
+}
+
 // lookupColumnOrdinal returns the ordinal of the column with the given ID. A
 // cache makes the lookup O(1).
 func (ot *optTable) lookupColumnOrdinal(colID descpb.ColumnID) (int, error) {
@@ -1503,6 +1573,11 @@ func (os *optTableStat) Histogram() []cat.HistogramBucket {
 	return os.stat.Histogram
 }
 
+// IsForecast is part of the cat.TableStatistic interface.
+func (os *optTableStat) IsForecast() bool {
+	return os.stat.IsForecast()
+}
+
 // optFamily is a wrapper around descpb.ColumnFamilyDescriptor that keeps a
 // reference to the table wrapper.
 type optFamily struct {
@@ -1554,8 +1629,9 @@ type optUniqueConstraint struct {
 	columns   []descpb.ColumnID
 	predicate string
 
-	withoutIndex bool
-	validity     descpb.ConstraintValidity
+	withoutIndex  bool
+	validity      descpb.ConstraintValidity
+	deferrability descpb.ConstraintDeferrability
 }
 
 var _ cat.UniqueConstraint = &optUniqueConstraint{}
@@ -1603,6 +1679,11 @@ func (u *optUniqueConstraint) Validated() bool {
 	return u.validity == descpb.ConstraintValidity_Validated
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return descpb.ConstraintDeferrabilityType[u.deferrability]
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -1616,10 +1697,11 @@ type optForeignKeyConstraint struct {
 	referencedTable   cat.StableID
 	referencedColumns []descpb.ColumnID
 
-	validity     descpb.ConstraintValidity
-	match        descpb.ForeignKeyReference_Match
-	deleteAction descpb.ForeignKeyReference_Action
-	updateAction descpb.ForeignKeyReference_Action
+	validity      descpb.ConstraintValidity
+	match         descpb.ForeignKeyReference_Match
+	deleteAction  descpb.ForeignKeyReference_Action
+	updateAction  descpb.ForeignKeyReference_Action
+	deferrability descpb.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1691,6 +1773,11 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return descpb.ForeignKeyReferenceActionType[fk.updateAction]
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return descpb.ConstraintDeferrabilityType[fk.deferrability]
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc catalog.TableDescriptor
@@ -1983,6 +2070,21 @@ func (ot *optVirtualTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optVirtualTable) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optVirtualTable) PolicyCount() int {
+	return 0
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optVirtualTable) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("no policies"))
+}
+
 // optVirtualIndex is a dummy implementation of cat.Index for the indexes
 // reported by a virtual table. The index assumes that table column 0 is a dummy
 // PK column.
@@ -15,11 +15,16 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
+	"github.com/gorhill/cronexpr"
 )
 
 // ApplyStorageParameters applies given storage parameters with the
@@ -68,10 +73,28 @@ type StorageParamObserver interface {
 }
 
 // TableStorageParamObserver observes storage parameters for tables.
-type TableStorageParamObserver struct{}
+type TableStorageParamObserver struct {
+	tableDesc *tabledesc.Mutable
+	// ttlExpirationColumn is the name of the column given by the
+	// ttl_expiration_column storage parameter. It is resolved to a column ID
+	// by the caller once the columns of the table have been created.
+	ttlExpirationColumn tree.Name
+}
 
 var _ StorageParamObserver = (*TableStorageParamObserver)(nil)
 
+// NewTableStorageParamObserver returns a new TableStorageParamObserver
+// applying storage parameters to the given table descriptor.
+func NewTableStorageParamObserver(tableDesc *tabledesc.Mutable) *TableStorageParamObserver {
+	return &TableStorageParamObserver{tableDesc: tableDesc}
+}
+
+// TTLExpirationColumn returns the name of the column given by the
+// ttl_expiration_column storage parameter, if any.
+func (a *TableStorageParamObserver) TTLExpirationColumn() tree.Name {
+	return a.ttlExpirationColumn
+}
+
 func applyFillFactorStorageParam(evalCtx *tree.EvalContext, key string, datum tree.Datum) error {
 	val, err := DatumAsFloat(evalCtx, key, datum)
 	if err != nil {
@@ -89,8 +112,108 @@ func applyFillFactorStorageParam(evalCtx *tree.EvalContext, key string, datum tr
 	return nil
 }
 
+// rowLevelTTL returns the row-level TTL configuration of the table,
+// initializing it if needed.
+func (a *TableStorageParamObserver) rowLevelTTL() *descpb.TableDescriptor_RowLevelTTL {
+	if a.tableDesc.RowLevelTTL == nil {
+		a.tableDesc.RowLevelTTL = &descpb.TableDescriptor_RowLevelTTL{}
+	}
+	return a.tableDesc.RowLevelTTL
+}
+
+func (a *TableStorageParamObserver) applyRowLevelTTLStorageParam(
+	evalCtx *tree.EvalContext, key string, datum tree.Datum,
+) error {
+	switch key {
+	case `ttl_expire_after`:
+		var d *tree.DInterval
+		switch v := datum.(type) {
+		case *tree.DInterval:
+			d = v
+		case *tree.DString:
+			var err error
+			if d, err = tree.ParseDInterval(string(*v)); err != nil {
+				return pgerror.Wrapf(err, pgcode.InvalidParameterValue, "error decoding %q", key)
+			}
+		default:
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"parameter %q requires an interval value", key)
+		}
+		if d.Duration.Compare(duration.Duration{}) <= 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "value of %q must be positive", key)
+		}
+		a.rowLevelTTL().ExpireAfter = d.Duration.String()
+	case `ttl_expiration_column`:
+		s, err := DatumAsString(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		a.rowLevelTTL()
+		a.ttlExpirationColumn = tree.Name(s)
+	case `ttl_select_batch_size`, `ttl_delete_batch_size`, `ttl_delete_rate_limit`:
+		val, err := DatumAsInt(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if val <= 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "value of %q must be positive", key)
+		}
+		ttl := a.rowLevelTTL()
+		switch key {
+		case `ttl_select_batch_size`:
+			ttl.SelectBatchSize = val
+		case `ttl_delete_batch_size`:
+			ttl.DeleteBatchSize = val
+		case `ttl_delete_rate_limit`:
+			ttl.DeleteRateLimit = val
+		}
+	case `ttl_job_cron`:
+		s, err := DatumAsString(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if _, err := cronexpr.Parse(s); err != nil {
+			return pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+				"invalid cron expression for %q", key)
+		}
+		a.rowLevelTTL().JobCron = s
+	case `ttl_pause`:
+		b, err := datumAsBool(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		a.rowLevelTTL().Pause = b
+	default:
+		return errors.AssertionFailedf("unknown row-level TTL storage parameter %q", key)
+	}
+	return nil
+}
+
+// datumAsBool transforms a datum holding either a boolean or a string
+// representing a boolean into a bool.
+func datumAsBool(evalCtx *tree.EvalContext, key string, datum tree.Datum) (bool, error) {
+	if stringVal, err := DatumAsString(evalCtx, key, datum); err == nil {
+		return ParseBoolVar(key, stringVal)
+	}
+	s, err := GetSingleBool(key, datum)
+	if err != nil {
+		return false, err
+	}
+	return bool(*s), nil
+}
+
 // RunPostChecks implements the StorageParamObserver interface.
 func (a *TableStorageParamObserver) RunPostChecks() error {
+	if ttl := a.tableDesc.RowLevelTTL; ttl != nil {
+		if ttl.ExpireAfter == "" && a.ttlExpirationColumn == "" {
+			return pgerror.New(pgcode.InvalidParameterValue,
+				`"ttl_expire_after" or "ttl_expiration_column" must be set to use row-level TTL`)
+		}
+		if ttl.ExpireAfter != "" && a.ttlExpirationColumn != "" {
+			return pgerror.New(pgcode.InvalidParameterValue,
+				`"ttl_expire_after" and "ttl_expiration_column" cannot both be set`)
+		}
+	}
 	return nil
 }
 
@@ -102,18 +225,9 @@ func (a *TableStorageParamObserver) Apply(
 	case `fillfactor`:
 		return applyFillFactorStorageParam(evalCtx, key, datum)
 	case `autovacuum_enabled`:
-		var boolVal bool
-		if stringVal, err := DatumAsString(evalCtx, key, datum); err == nil {
-			boolVal, err = ParseBoolVar(key, stringVal)
-			if err != nil {
-				return err
-			}
-		} else {
-			s, err := GetSingleBool(key, datum)
-			if err != nil {
-				return err
-			}
-			boolVal = bool(*s)
+		boolVal, err := datumAsBool(evalCtx, key, datum)
+		if err != nil {
+			return err
 		}
 		if !boolVal && evalCtx != nil {
 			evalCtx.ClientNoticeSender.BufferClientNotice(
@@ -122,6 +236,14 @@ func (a *TableStorageParamObserver) Apply(
 			)
 		}
 		return nil
+	case `ttl_expire_after`,
+		`ttl_expiration_column`,
+		`ttl_select_batch_size`,
+		`ttl_delete_batch_size`,
+		`ttl_delete_rate_limit`,
+		`ttl_job_cron`,
+		`ttl_pause`:
+		return a.applyRowLevelTTLStorageParam(evalCtx, key, datum)
 	case `toast_tuple_target`,
 		`parallel_workers`,
 		`toast.autovacuum_enabled`,
@@ -154,6 +276,53 @@ func (a *TableStorageParamObserver) Apply(
 	return errors.Errorf("invalid storage parameter %q", key)
 }
 
+// ViewStorageParamObserver observes storage parameters for materialized
+// views.
+type ViewStorageParamObserver struct {
+	viewDesc *tabledesc.Mutable
+}
+
+var _ StorageParamObserver = (*ViewStorageParamObserver)(nil)
+
+// NewViewStorageParamObserver returns a new ViewStorageParamObserver
+// applying storage parameters to the given view descriptor.
+func NewViewStorageParamObserver(viewDesc *tabledesc.Mutable) *ViewStorageParamObserver {
+	return &ViewStorageParamObserver{viewDesc: viewDesc}
+}
+
+// Apply implements the StorageParamObserver interface.
+func (a *ViewStorageParamObserver) Apply(
+	evalCtx *tree.EvalContext, key string, datum tree.Datum,
+) error {
+	switch key {
+	case `auto_refresh`:
+		s, err := DatumAsString(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		switch s {
+		case `manual`:
+			a.viewDesc.IncrementalRefresh = nil
+		case `incremental`:
+			a.viewDesc.IncrementalRefresh = &descpb.TableDescriptor_IncrementalRefresh{}
+		default:
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid value for %q: %q, expected \"manual\" or \"incremental\"", key, s)
+		}
+		return nil
+	}
+	return errors.Errorf("invalid storage parameter %q", key)
+}
+
+// RunPostChecks implements the StorageParamObserver interface.
+func (a *ViewStorageParamObserver) RunPostChecks() error {
+	if a.viewDesc.IncrementalRefresh != nil && !a.viewDesc.MaterializedView() {
+		return pgerror.New(pgcode.InvalidParameterValue,
+			`"auto_refresh" can only be set for materialized views`)
+	}
+	return nil
+}
+
 // IndexStorageParamObserver observes storage parameters for indexes.
 type IndexStorageParamObserver struct {
 	IndexDesc *descpb.IndexDescriptor
@@ -44,6 +44,8 @@ func TestContextualHelp(t *testing.T) {
 		{`ALTER TABLE blah RENAME TO blih ??`, `ALTER TABLE`},
 		{`ALTER TABLE blah SPLIT AT (SELECT 1) ??`, `ALTER TABLE`},
 
+		{`ALTER DOMAIN ??`, `ALTER DOMAIN`},
+		{`ALTER DOMAIN d ??`, `ALTER DOMAIN`},
 		{`ALTER TYPE ??`, `ALTER TYPE`},
 		{`ALTER TYPE t ??`, `ALTER TYPE`},
 		{`ALTER TYPE t ADD VALUE ??`, `ALTER TYPE`},
@@ -79,6 +81,10 @@ func TestContextualHelp(t *testing.T) {
 
 		{`ALTER ROLE bleh ?? WITH NOCREATEROLE`, `ALTER ROLE`},
 
+		{`ALTER DEFAULT PRIVILEGES ??`, `ALTER DEFAULT PRIVILEGES`},
+		{`ALTER DEFAULT PRIVILEGES FOR ROLE foo GRANT ??`, `ALTER DEFAULT PRIVILEGES`},
+		{`ALTER DEFAULT PRIVILEGES IN SCHEMA s REVOKE SELECT ON ??`, `ALTER DEFAULT PRIVILEGES`},
+
 		{`ALTER RANGE foo CONFIGURE ??`, `ALTER RANGE`},
 		{`ALTER RANGE ??`, `ALTER RANGE`},
 
@@ -144,7 +150,13 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE TABLE blah AS SELECT 1 ??`, `SELECT`},
 
 		{`CREATE TYPE blah AS ENUM ??`, `CREATE TYPE`},
+		{`CREATE DOMAIN ??`, `CREATE TYPE`},
 		{`DROP TYPE ??`, `DROP TYPE`},
+		{`DROP DOMAIN ??`, `DROP TYPE`},
+
+		{`CREATE POLICY ??`, `CREATE POLICY`},
+		{`CREATE POLICY p ON ??`, `CREATE POLICY`},
+		{`DROP POLICY ??`, `DROP POLICY`},
 
 		{`CREATE SCHEMA IF ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA IF NOT ??`, `CREATE SCHEMA`},
@@ -189,6 +201,8 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP SCHEDULE ???`, `DROP SCHEDULES`},
 		{`DROP SCHEDULES ???`, `DROP SCHEDULES`},
 
+		{`DROP PLAN BASELINE ??`, `DROP PLAN BASELINE`},
+
 		{`DROP SCHEMA ??`, `DROP SCHEMA`},
 
 		{`EXPLAIN (??`, `EXPLAIN`},
@@ -215,6 +229,15 @@ func TestContextualHelp(t *testing.T) {
 		{`DEALLOCATE ALL ??`, `DEALLOCATE`},
 		{`DEALLOCATE PREPARE ??`, `DEALLOCATE`},
 
+		{`DECLARE ??`, `DECLARE`},
+		{`DECLARE foo ??`, `DECLARE`},
+		{`DECLARE foo CURSOR FOR ??`, `DECLARE`},
+		{`FETCH ??`, `FETCH`},
+		{`FETCH 2 ??`, `FETCH`},
+		{`MOVE ??`, `MOVE`},
+		{`MOVE ALL IN ??`, `MOVE`},
+		{`CLOSE ??`, `CLOSE`},
+
 		{`INSERT INTO ??`, `INSERT`},
 		{`INSERT INTO blah (??`, `<SELECTCLAUSE>`},
 		{`INSERT INTO blah VALUES (1) RETURNING ??`, `INSERT`},
@@ -229,6 +252,10 @@ func TestContextualHelp(t *testing.T) {
 		{`UPSERT INTO blah VALUES (1) ??`, `VALUES`},
 		{`UPSERT INTO blah TABLE foo ??`, `TABLE`},
 
+		{`MERGE ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ON true ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ON true WHEN MATCHED THEN ??`, `MERGE`},
+
 		{`UPDATE blah ??`, `UPDATE`},
 		{`UPDATE blah SET ??`, `UPDATE`},
 		{`UPDATE blah SET x = 3 WHERE true ??`, `UPDATE`},
@@ -302,6 +329,9 @@ func TestContextualHelp(t *testing.T) {
 		{`SHOW SCHEDULE ??`, `SHOW SCHEDULES`},
 		{`SHOW SCHEDULES ??`, `SHOW SCHEDULES`},
 
+		{`SHOW PLAN ??`, `SHOW PLAN BASELINES`},
+		{`SHOW PLAN BASELINES ??`, `SHOW PLAN BASELINES`},
+
 		{`SHOW BACKUP 'foo' ??`, `SHOW BACKUP`},
 
 		{`SHOW CLUSTER SETTING all ??`, `SHOW CLUSTER SETTING`},
@@ -393,6 +423,9 @@ func TestContextualHelp(t *testing.T) {
 		{`SET SESSION blah TO ??`, `SET SESSION`},
 		{`SET SESSION blah TO 42 ??`, `SET SESSION`},
 
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
+
 		{`SET TRANSACTION ??`, `SET TRANSACTION`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
 		{`SET TIME ??`, `SET SESSION`},
@@ -419,6 +452,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`REFRESH ??`, `REFRESH`},
 
+		{`PIN ??`, `PIN PLAN`},
+		{`PIN PLAN FOR ??`, `PIN PLAN`},
+
 		{`ROLLBACK TRANSACTION ??`, `ROLLBACK`},
 		{`ROLLBACK TO ??`, `ROLLBACK`},
 
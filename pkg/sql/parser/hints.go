@@ -0,0 +1,140 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parser
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// ParseStatementHints parses optimizer hints, as they appear in the
+// /*+ ... */ comments of a statement or in system.statement_hints. The hints
+// are a sequence of:
+//
+//	INDEX(table index)
+//	NO_INDEX_JOIN(table ...)
+//	LEADING(table table ...)
+//	HASH_JOIN(table table ...)
+//	MERGE_JOIN(table table ...)
+//	LOOKUP_JOIN(table table ...)
+//	DISABLE_RULE(rule ...)
+//
+// Hint names are case-insensitive. Arguments are identifiers, separated by
+// spaces or commas.
+func ParseStatementHints(hints string) (*tree.StatementHints, error) {
+	s := makeScanner(hints)
+	res := &tree.StatementHints{}
+	var lval sqlSymType
+	for {
+		s.scan(&lval)
+		if lval.id == 0 {
+			return res, nil
+		}
+		if !isHintIdent(&lval) {
+			return nil, hintSyntaxError(hints, &lval)
+		}
+		name := lval.str
+		s.scan(&lval)
+		if lval.id != '(' {
+			return nil, hintSyntaxError(hints, &lval)
+		}
+		var args tree.NameList
+		for {
+			s.scan(&lval)
+			if lval.id == ')' {
+				break
+			}
+			if lval.id == ',' && len(args) > 0 {
+				continue
+			}
+			if !isHintIdent(&lval) {
+				return nil, hintSyntaxError(hints, &lval)
+			}
+			args = append(args, tree.Name(lval.str))
+		}
+
+		switch name {
+		case "index":
+			if len(args) != 2 {
+				return nil, hintArgsError(name, "a table and an index")
+			}
+			res.Indexes = append(res.Indexes, tree.IndexHint{Table: args[0], Index: args[1]})
+
+		case "no_index_join":
+			if len(args) == 0 {
+				return nil, hintArgsError(name, "at least one table")
+			}
+			res.NoIndexJoins = append(res.NoIndexJoins, args...)
+
+		case "leading":
+			if len(args) < 2 {
+				return nil, hintArgsError(name, "at least two tables")
+			}
+			if len(res.Leading) > 0 {
+				return nil, pgerror.New(pgcode.Syntax, "LEADING hint specified multiple times")
+			}
+			res.Leading = args
+
+		case "hash_join", "merge_join", "lookup_join":
+			if len(args) < 2 {
+				return nil, hintArgsError(name, "at least two tables")
+			}
+			var algorithm string
+			switch name {
+			case "hash_join":
+				algorithm = tree.AstHash
+			case "merge_join":
+				algorithm = tree.AstMerge
+			case "lookup_join":
+				algorithm = tree.AstLookup
+			}
+			res.Joins = append(res.Joins, tree.JoinHint{Algorithm: algorithm, Tables: args})
+
+		case "disable_rule":
+			if len(args) == 0 {
+				return nil, hintArgsError(name, "at least one rule")
+			}
+			res.DisabledRules = append(res.DisabledRules, args...)
+
+		default:
+			return nil, pgerror.Newf(pgcode.Syntax, "unknown hint %s", strings.ToUpper(name))
+		}
+	}
+}
+
+// isHintIdent returns true if the token is an identifier or a keyword, both
+// of which can be used as hint names and arguments.
+func isHintIdent(lval *sqlSymType) bool {
+	if lval.id == IDENT {
+		return true
+	}
+	_, isKeyword := lex.KeywordsCategories[lval.str]
+	return isKeyword && lval.id == lex.GetKeywordID(lval.str)
+}
+
+func hintSyntaxError(hints string, lval *sqlSymType) error {
+	hints = strings.TrimSpace(hints)
+	if lval.id == ERROR {
+		return pgerror.Newf(pgcode.Syntax, "invalid hints %q: %s", hints, lval.str)
+	}
+	if lval.id == 0 {
+		return pgerror.Newf(pgcode.Syntax, "invalid hints %q: unexpected end of hints", hints)
+	}
+	return pgerror.Newf(pgcode.Syntax, "invalid hints %q: unexpected %q", hints, lval.str)
+}
+
+func hintArgsError(name, expected string) error {
+	return pgerror.Newf(pgcode.Syntax, "%s hint requires %s", strings.ToUpper(name), expected)
+}
@@ -54,6 +54,15 @@ type Statement struct {
 	// NumAnnotations indicates the number of annotations in the tree. It is equal
 	// to the maximum annotation index.
 	NumAnnotations tree.AnnotationIdx
+
+	// Hints are the optimizer hints in the /*+ ... */ comments of the statement,
+	// or nil if there are none.
+	Hints *tree.StatementHints
+
+	// HintsErr is the error encountered while parsing the hints, if any. Invalid
+	// hints are ignored rather than failing the statement, since the comments
+	// could be meant for other databases or tools (e.g. pg_hint_plan).
+	HintsErr error
 }
 
 // Statements is a list of parsed statements.
@@ -129,6 +138,7 @@ func (p *Parser) parseOneWithInt(sql string, nakedIntType *types.T) (Statement,
 func (p *Parser) scanOneStmt() (sql string, tokens []sqlSymType, done bool) {
 	var lval sqlSymType
 	tokens = p.tokBuf[:0]
+	p.scanner.hints = p.scanner.hints[:0]
 
 	// Scan the first token.
 	for {
@@ -165,7 +175,7 @@ func (p *Parser) parseWithDepth(depth int, sql string, nakedIntType *types.T) (S
 	defer p.scanner.cleanup()
 	for {
 		sql, tokens, done := p.scanOneStmt()
-		stmt, err := p.parse(depth+1, sql, tokens, nakedIntType)
+		stmt, err := p.parse(depth+1, sql, tokens, p.scanner.hints, nakedIntType)
 		if err != nil {
 			return nil, err
 		}
@@ -179,9 +189,10 @@ func (p *Parser) parseWithDepth(depth int, sql string, nakedIntType *types.T) (S
 	return stmts, nil
 }
 
-// parse parses a statement from the given scanned tokens.
+// parse parses a statement from the given scanned tokens and the contents of
+// its hint comments.
 func (p *Parser) parse(
-	depth int, sql string, tokens []sqlSymType, nakedIntType *types.T,
+	depth int, sql string, tokens []sqlSymType, hints []string, nakedIntType *types.T,
 ) (Statement, error) {
 	p.lexer.init(sql, tokens, nakedIntType)
 	defer p.lexer.cleanup()
@@ -208,12 +219,21 @@ func (p *Parser) parse(
 
 		return Statement{}, err
 	}
-	return Statement{
+	stmt := Statement{
 		AST:             p.lexer.stmt,
 		SQL:             sql,
 		NumPlaceholders: p.lexer.numPlaceholders,
 		NumAnnotations:  p.lexer.numAnnotations,
-	}, nil
+	}
+	if len(hints) > 0 && stmt.AST != nil {
+		h, err := ParseStatementHints(strings.Join(hints, " "))
+		if err != nil {
+			stmt.HintsErr = err
+		} else if !h.Empty() {
+			stmt.Hints = h
+		}
+	}
+	return stmt, nil
 }
 
 // unaryNegation constructs an AST node for a negation. This attempts
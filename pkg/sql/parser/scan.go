@@ -36,6 +36,9 @@ type scanner struct {
 	in            string
 	pos           int
 	bytesPrealloc []byte
+	// hints accumulates the contents of the /*+ ... */ comments that were
+	// skipped by the scanner.
+	hints []string
 }
 
 func makeScanner(str string) scanner {
@@ -47,6 +50,7 @@ func makeScanner(str string) scanner {
 func (s *scanner) init(str string) {
 	s.in = str
 	s.pos = 0
+	s.hints = nil
 	// Preallocate some buffer space for identifiers etc.
 	s.bytesPrealloc = make([]byte, len(str))
 }
@@ -339,6 +343,10 @@ func (s *scanner) scan(lval *sqlSymType) {
 			s.pos++
 			lval.id = CONTAINS
 			return
+		case '@': // @@
+			s.pos++
+			lval.id = AT_AT
+			return
 		}
 		return
 
@@ -467,6 +475,9 @@ func (s *scanner) scanComment(lval *sqlSymType) (present, ok bool) {
 					s.pos++
 					depth--
 					if depth == 0 {
+						if strings.HasPrefix(s.in[start:], "/*+") {
+							s.hints = append(s.hints, s.in[start+3:s.pos-2])
+						}
 						return true, true
 					}
 					continue
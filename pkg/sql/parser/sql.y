@@ -135,6 +135,9 @@ func (u *sqlSymUnion) placeholder() *tree.Placeholder {
 func (u *sqlSymUnion) auditMode() tree.AuditMode {
     return u.val.(tree.AuditMode)
 }
+func (u *sqlSymUnion) policyCommand() tree.PolicyCommand {
+    return u.val.(tree.PolicyCommand)
+}
 func (u *sqlSymUnion) bool() bool {
     return u.val.(bool)
 }
@@ -169,6 +172,9 @@ func (u *sqlSymUnion) nameList() tree.NameList {
 func (u *sqlSymUnion) enumValueList() tree.EnumValueList {
     return u.val.(tree.EnumValueList)
 }
+func (u *sqlSymUnion) compositeTypeList() []tree.CompositeTypeElem {
+    return u.val.([]tree.CompositeTypeElem)
+}
 func (u *sqlSymUnion) unresolvedName() *tree.UnresolvedName {
     return u.val.(*tree.UnresolvedName)
 }
@@ -355,6 +361,12 @@ func (u *sqlSymUnion) updateExpr() *tree.UpdateExpr {
 func (u *sqlSymUnion) updateExprs() tree.UpdateExprs {
     return u.val.(tree.UpdateExprs)
 }
+func (u *sqlSymUnion) mergeWhen() *tree.MergeWhen {
+    return u.val.(*tree.MergeWhen)
+}
+func (u *sqlSymUnion) mergeWhens() tree.MergeWhens {
+    return u.val.(tree.MergeWhens)
+}
 func (u *sqlSymUnion) limit() *tree.Limit {
     return u.val.(*tree.Limit)
 }
@@ -370,6 +382,9 @@ func (u *sqlSymUnion) privilegeType() privilege.Kind {
 func (u *sqlSymUnion) privilegeList() privilege.List {
     return u.val.(privilege.List)
 }
+func (u *sqlSymUnion) targetObjectType() privilege.TargetObjectType {
+    return u.val.(privilege.TargetObjectType)
+}
 func (u *sqlSymUnion) onConflict() *tree.OnConflict {
     return u.val.(*tree.OnConflict)
 }
@@ -430,6 +445,9 @@ func (u *sqlSymUnion) readWriteMode() tree.ReadWriteMode {
 func (u *sqlSymUnion) deferrableMode() tree.DeferrableMode {
     return u.val.(tree.DeferrableMode)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+    return u.val.(tree.ConstraintDeferrability)
+}
 func (u *sqlSymUnion) idxElem() tree.IndexElem {
     return u.val.(tree.IndexElem)
 }
@@ -586,6 +604,15 @@ func (u *sqlSymUnion) objectNamePrefix() tree.ObjectNamePrefix {
 func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
     return u.val.(tree.ObjectNamePrefixList)
 }
+func (u *sqlSymUnion) cursorSensitivity() tree.CursorSensitivity {
+    return u.val.(tree.CursorSensitivity)
+}
+func (u *sqlSymUnion) cursorScrollOption() tree.CursorScrollOption {
+    return u.val.(tree.CursorScrollOption)
+}
+func (u *sqlSymUnion) cursorStmt() tree.CursorStmt {
+    return u.val.(tree.CursorStmt)
+}
 %}
 
 // NB: the %token definitions must come before the %type definitions in this
@@ -605,11 +632,11 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACCESS ACTION ADD ADMIN AFTER AGGREGATE
+%token <str> ABORT ABSOLUTE ACCESS ACTION ADD ADMIN AFTER AGGREGATE
 %token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC
-%token <str> ASYMMETRIC AT ATTRIBUTE AUTHORIZATION AUTOMATIC AVAILABILITY
+%token <str> ASENSITIVE ASYMMETRIC AT AT_AT ATTRIBUTE AUTHORIZATION AUTOMATIC AVAILABILITY
 
-%token <str> BACKUP BACKUPS BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
+%token <str> BACKUP BACKUPS BACKWARD BASELINE BASELINES BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BUCKET_COUNT
 %token <str> BOOLEAN BOTH BOX2D BUNDLE BY
 
@@ -625,29 +652,29 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT DEFAULTS
 %token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DESC DESTINATION DETACHED
-%token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
+%token <str> DISABLE DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> ELSE ENCODING ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
+%token <str> ELSE ENABLE ENCODING ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT
-%token <str> EXPIRATION EXPLAIN EXPORT EXTENSION EXTRACT EXTRACT_DURATION
+%token <str> EXPIRATION EXPLAIN EXPORT EXTENSION EXTRACT EXTRACT_DURATION EXTREMES
 
 %token <str> FAILURE FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
 %token <str> FILES FILTER
-%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE FORCE_INDEX FOREIGN FROM FULL FUNCTION
+%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE FORCE_INDEX FOREIGN FORMAT FORWARD FROM FULL FUNCTION
 
 %token <str> GENERATED GEOGRAPHY GEOMETRY GEOMETRYM GEOMETRYZ GEOMETRYZM
 %token <str> GEOMETRYCOLLECTION GEOMETRYCOLLECTIONM GEOMETRYCOLLECTIONZ GEOMETRYCOLLECTIONZM
 %token <str> GLOBAL GOAL GRANT GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HASH HIGH HISTOGRAM HOUR
+%token <str> HAVING HASH HEADER HIGH HISTOGRAM HOLD HOUR
 
 %token <str> IDENTITY
 %token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCLUDE INCLUDE_DEPRECATED_INTERLEAVES INCLUDING INCREMENT INCREMENTAL
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INTERLEAVE INITIALLY
-%token <str> INNER INSERT INT INTEGER
+%token <str> INNER INSENSITIVE INSERT INT INTEGER
 %token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED IS ISERROR ISNULL ISOLATION
 
 %token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
@@ -659,7 +686,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
 %token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH
+%token <str> MATCH MATCHED MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
 %token <str> MULTIPOINT MULTIPOINTM MULTIPOINTZ MULTIPOINTZM
 %token <str> MULTIPOLYGON MULTIPOLYGONM MULTIPOLYGONZ MULTIPOLYGONZM
@@ -671,25 +698,25 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %token <str> OF OFF OFFSET OID OIDS OIDVECTOR ON ONLY OPT OPTION OPTIONS OR
 %token <str> ORDER ORDINALITY OTHERS OUT OUTER OVER OVERLAPS OVERLAY OWNED OWNER OPERATOR
 
-%token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACING
-%token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
-%token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIORITY PRIVILEGES
+%token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PIN PLACING
+%token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLICY POLYGON POLYGONM POLYGONZ POLYGONZM
+%token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
 %token <str> PROCEDURAL PUBLIC PUBLICATION
 
-%token <str> QUERIES QUERY
+%token <str> QUERIES QUERY QUOTE
 
 %token <str> RANGE RANGES READ REAL REASSIGN RECURSIVE RECURRING REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE REINDEX
 %token <str> REMOVE_PATH RENAME REPEATABLE REPLACE REPLICATION
-%token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETRY REVISION_HISTORY REVOKE RIGHT
+%token <str> RELATIVE RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETRY REVISION_HISTORY REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT RULE RUNNING
 
-%token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
+%token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCROLL SCRUB SEARCH SECOND SECURITY SELECT SEQUENCE SEQUENCES
 %token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETS SETTING SETTINGS
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SKIP_MISSING_FOREIGN_KEYS
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING
+%token <str> START STATISTICS STATUS STDIN STDOUT STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING
 %token <str> SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENTS
 
 %token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
@@ -743,6 +770,8 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> alter_partition_stmt
 %type <tree.Statement> alter_role_stmt
 %type <tree.Statement> alter_type_stmt
+%type <tree.Statement> alter_domain_stmt
+%type <tree.Statement> alter_default_privileges_stmt
 %type <tree.Statement> alter_schema_stmt
 %type <tree.Statement> alter_unsupported_stmt
 
@@ -815,6 +844,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> comment_stmt
 %type <tree.Statement> commit_stmt
 %type <tree.Statement> copy_from_stmt
+%type <tree.Statement> copy_to_stmt
 
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_changefeed_stmt create_replication_stream_stmt
@@ -836,6 +866,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <*tree.CreateStatsOptions> create_stats_option
 
 %type <tree.Statement> create_type_stmt
+%type <tree.Statement> create_policy_stmt
 %type <tree.Statement> delete_stmt
 %type <tree.Statement> discard_stmt
 
@@ -847,6 +878,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> drop_schema_stmt
 %type <tree.Statement> drop_table_stmt
 %type <tree.Statement> drop_type_stmt
+%type <tree.Statement> drop_policy_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
 
@@ -860,6 +892,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> deallocate_stmt
 %type <tree.Statement> grant_stmt
 %type <tree.Statement> insert_stmt
+%type <tree.Statement> merge_stmt
 %type <tree.Statement> import_stmt
 %type <tree.Statement> pause_stmt pause_jobs_stmt pause_schedules_stmt
 %type <*tree.Select>   for_schedules_clause
@@ -869,11 +902,13 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> reset_stmt reset_session_stmt reset_csetting_stmt
 %type <tree.Statement> resume_stmt resume_jobs_stmt resume_schedules_stmt
 %type <tree.Statement> drop_schedule_stmt
+%type <tree.Statement> drop_plan_baseline_stmt
 %type <tree.Statement> restore_stmt
 %type <tree.StringOrPlaceholderOptList> string_or_placeholder_opt_list
 %type <[]tree.StringOrPlaceholderOptList> list_of_string_or_placeholder_opt_list
 %type <tree.Statement> revoke_stmt
 %type <tree.Statement> refresh_stmt
+%type <tree.Statement> pin_plan_stmt
 %type <*tree.Select> select_stmt
 %type <tree.Statement> abort_stmt
 %type <tree.Statement> rollback_stmt
@@ -883,6 +918,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -925,6 +961,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> show_users_stmt
 %type <tree.Statement> show_zone_stmt
 %type <tree.Statement> show_schedules_stmt
+%type <tree.Statement> show_plan_baselines_stmt
 %type <tree.Statement> show_full_scans_stmt
 
 %type <str> statements_or_queries
@@ -940,6 +977,14 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 
 %type <tree.Statement> close_cursor_stmt
 %type <tree.Statement> declare_cursor_stmt
+%type <tree.Statement> fetch_cursor_stmt
+%type <tree.Statement> move_cursor_stmt
+%type <tree.CursorStmt> cursor_movement_specifier
+%type <bool> opt_hold opt_binary
+%type <tree.CursorSensitivity> opt_sensitivity
+%type <tree.CursorScrollOption> opt_scroll
+%type <int64> opt_forward_backward forward_backward
+%type <int64> next_prior
 %type <tree.Statement> reindex_stmt
 
 %type <[]string> opt_incremental
@@ -948,6 +993,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <*tree.BackupOptions> opt_with_backup_options backup_options backup_options_list
 %type <*tree.RestoreOptions> opt_with_restore_options restore_options restore_options_list
 %type <*tree.CopyOptions> opt_with_copy_options copy_options copy_options_list
+%type <*tree.CopyOptions> copy_generic_option copy_generic_option_list
 %type <str> import_format
 %type <tree.StorageParam> storage_parameter
 %type <[]tree.StorageParam> storage_parameter_list opt_table_with opt_with_storage_parameter_list
@@ -1049,6 +1095,8 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.SelectExprs> target_list
 %type <tree.UpdateExprs> set_clause_list
 %type <*tree.UpdateExpr> set_clause multiple_set_clause
+%type <tree.MergeWhens> merge_when_list
+%type <*tree.MergeWhen> merge_when_clause
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
 %type <tree.Exprs> group_by_list
@@ -1056,7 +1104,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <*tree.Limit> select_limit opt_select_limit
 %type <tree.TableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
-%type <empty> opt_using_clause
+%type <tree.TableExprs> opt_using_clause
 %type <tree.RefreshDataOption> opt_clear_data
 
 %type <[]tree.SequenceOption> sequence_option_list opt_sequence_option_list
@@ -1135,6 +1183,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 
 %type <str> explain_option_name
 %type <[]string> explain_option_list opt_enum_val_list enum_val_list
+%type <[]tree.CompositeTypeElem> opt_composite_type_list composite_type_list
 
 %type <tree.ResolvableTypeReference> typename simple_typename cast_target
 %type <*types.T> const_typename
@@ -1184,8 +1233,12 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <[]tree.NamedColumnQualification> col_qual_list create_as_col_qual_list
 %type <tree.NamedColumnQualification> col_qualification create_as_col_qualification
 %type <tree.ColumnQualification> col_qualification_elem create_as_col_qualification_elem
+%type <[]tree.NamedColumnQualification> opt_domain_constraint_list
+%type <tree.NamedColumnQualification> domain_constraint
+%type <tree.ColumnQualification> domain_constraint_elem
 %type <tree.CompositeKeyMatchMethod> key_match
 %type <tree.ReferenceActions> reference_actions
+%type <tree.ConstraintDeferrability> opt_deferrable
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
 %type <tree.Expr> func_application func_expr_common_subexpr special_function
@@ -1215,6 +1268,12 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <privilege.List> privileges
 %type <[]tree.KVOption> opt_role_options role_options
 %type <tree.AuditMode> audit_mode
+%type <tree.PolicyCommand> opt_policy_command
+%type <[]security.SQLUsername> opt_policy_roles
+%type <[]security.SQLUsername> opt_for_roles
+%type <tree.ObjectNamePrefixList> opt_in_schemas
+%type <privilege.TargetObjectType> target_object_type
+%type <tree.Expr> opt_policy_using opt_policy_with_check
 %type <*tree.ReplicationOptions> opt_with_replication_options replication_options replication_options_list
 
 %type <str> relocate_kw
@@ -1222,6 +1281,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <*tree.SetZoneConfig> set_zone_config
 
 %type <tree.Expr> opt_alter_column_using
+%type <tree.Expr> opt_merge_when_cond
 
 %type <tree.Persistence> opt_temp
 %type <tree.Persistence> opt_persistence_temp_table
@@ -1276,7 +1336,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %left      '|'
 %left      '#'
 %left      '&'
-%left      LSHIFT RSHIFT INET_CONTAINS_OR_EQUALS INET_CONTAINED_BY_OR_EQUALS AND_AND SQRT CBRT
+%left      LSHIFT RSHIFT INET_CONTAINS_OR_EQUALS INET_CONTAINED_BY_OR_EQUALS AND_AND AT_AT SQRT CBRT
 %left      '+' '-'
 %left      '*' '/' FLOORDIV '%'
 %left      '^'
@@ -1310,6 +1370,7 @@ stmt:
 | preparable_stmt           // help texts in sub-rule
 | analyze_stmt              // EXTEND WITH HELP: ANALYZE
 | copy_from_stmt
+| copy_to_stmt
 | comment_stmt
 | execute_stmt              // EXTEND WITH HELP: EXECUTE
 | deallocate_stmt           // EXTEND WITH HELP: DEALLOCATE
@@ -1322,10 +1383,13 @@ stmt:
 | drop_owned_by_stmt        // EXTEND WITH HELP: DROP OWNED BY
 | release_stmt              // EXTEND WITH HELP: RELEASE
 | refresh_stmt              // EXTEND WITH HELP: REFRESH
+| pin_plan_stmt             // EXTEND WITH HELP: PIN PLAN
 | nonpreparable_set_stmt    // help texts in sub-rule
 | transaction_stmt          // help texts in sub-rule
-| close_cursor_stmt
-| declare_cursor_stmt
+| close_cursor_stmt         // EXTEND WITH HELP: CLOSE
+| declare_cursor_stmt       // EXTEND WITH HELP: DECLARE
+| fetch_cursor_stmt         // EXTEND WITH HELP: FETCH
+| move_cursor_stmt          // EXTEND WITH HELP: MOVE
 | reindex_stmt
 | /* EMPTY */
   {
@@ -1338,6 +1402,7 @@ stmt:
 alter_stmt:
   alter_ddl_stmt      // help texts in sub-rule
 | alter_role_stmt     // EXTEND WITH HELP: ALTER ROLE
+| alter_default_privileges_stmt // EXTEND WITH HELP: ALTER DEFAULT PRIVILEGES
 | alter_unsupported_stmt
 | ALTER error         // SHOW HELP: ALTER
 
@@ -1351,6 +1416,7 @@ alter_ddl_stmt:
 | alter_partition_stmt // EXTEND WITH HELP: ALTER PARTITION
 | alter_schema_stmt    // EXTEND WITH HELP: ALTER SCHEMA
 | alter_type_stmt      // EXTEND WITH HELP: ALTER TYPE
+| alter_domain_stmt    // EXTEND WITH HELP: ALTER DOMAIN
 
 // %Help: ALTER TABLE - change the definition of a table
 // %Category: DDL
@@ -1381,6 +1447,7 @@ alter_ddl_stmt:
 //   ALTER TABLE ... CONFIGURE ZONE <zoneconfig>
 //   ALTER TABLE ... SET SCHEMA <newschemaname>
 //   ALTER TABLE ... SET LOCALITY [REGIONAL BY [TABLE IN <region> | ROW] | GLOBAL]
+//   ALTER TABLE ... {ENABLE | DISABLE} ROW LEVEL SECURITY
 //
 // Column qualifiers:
 //   [CONSTRAINT <constraintname>] {NULL | NOT NULL | UNIQUE | PRIMARY KEY | CHECK (<expr>) | DEFAULT <expr>}
@@ -2049,6 +2116,16 @@ alter_table_cmd:
   {
     $$.val = &tree.AlterTableSetAudit{Mode: $3.auditMode()}
   }
+  // ALTER TABLE <name> ENABLE ROW LEVEL SECURITY
+| ENABLE ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableSetRowLevelSecurity{Enable: true}
+  }
+  // ALTER TABLE <name> DISABLE ROW LEVEL SECURITY
+| DISABLE ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableSetRowLevelSecurity{Enable: false}
+  }
   // ALTER TABLE <name> PARTITION BY ...
 | partition_by_table
   {
@@ -2142,6 +2219,55 @@ opt_validate_behavior:
     $$.val = tree.ValidationDefault
   }
 
+// %Help: ALTER DOMAIN - change the definition of a domain.
+// %Category: DDL
+// %Text: ALTER DOMAIN <domain_name> <command>
+//
+// Commands:
+//   ALTER DOMAIN ... ADD [CONSTRAINT <name>] CHECK (<expr>)
+//   ALTER DOMAIN ... DROP CONSTRAINT [IF EXISTS] <name>
+alter_domain_stmt:
+  ALTER DOMAIN type_name ADD CONSTRAINT constraint_name CHECK '(' a_expr ')'
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainAddConstraint{
+        Name: tree.Name($6),
+        Expr: $9.expr(),
+      },
+    }
+  }
+| ALTER DOMAIN type_name ADD CHECK '(' a_expr ')'
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainAddConstraint{
+        Expr: $7.expr(),
+      },
+    }
+  }
+| ALTER DOMAIN type_name DROP CONSTRAINT constraint_name
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainDropConstraint{
+        Name: tree.Name($6),
+        IfExists: false,
+      },
+    }
+  }
+| ALTER DOMAIN type_name DROP CONSTRAINT IF EXISTS constraint_name
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainDropConstraint{
+        Name: tree.Name($8),
+        IfExists: true,
+      },
+    }
+  }
+| ALTER DOMAIN error // SHOW HELP: ALTER DOMAIN
+
 // %Help: ALTER TYPE - change the definition of a type.
 // %Category: DDL
 // %Text: ALTER TYPE <typename> <command>
@@ -2789,18 +2915,10 @@ alter_unsupported_stmt:
   {
     return unimplemented(sqllex, "alter function")
   }
-| ALTER DOMAIN error
-  {
-    return unimplemented(sqllex, "alter domain")
-  }
 | ALTER AGGREGATE error
   {
     return unimplemented(sqllex, "alter aggregate")
   }
-| ALTER DEFAULT error
-  {
-    return unimplemented(sqllex, "alter default privileges")
-  }
 
 
 // %Help: IMPORT - load data from file in a distributed manner
@@ -2981,14 +3099,12 @@ copy_from_stmt:
   {
     /* FORCE DOC */
     name := $2.unresolvedObjectName().ToTableName()
-    if $7.expr() != nil {
-      return unimplementedWithIssue(sqllex, 54580)
-    }
     $$.val = &tree.CopyFrom{
        Table: name,
        Columns: $3.nameList(),
        Stdin: true,
        Options: *$6.copyOptions(),
+       Where: tree.NewWhere(tree.AstWhere, $7.expr()),
     }
   }
 | COPY table_name opt_column_list FROM error
@@ -2996,11 +3112,37 @@ copy_from_stmt:
     return unimplemented(sqllex, "copy from unsupported format")
   }
 
+copy_to_stmt:
+  COPY table_name opt_column_list TO STDOUT opt_with_copy_options
+  {
+    name := $2.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CopyTo{
+       Table: name,
+       Columns: $3.nameList(),
+       Options: *$6.copyOptions(),
+    }
+  }
+| COPY '(' select_stmt ')' TO STDOUT opt_with_copy_options
+  {
+    $$.val = &tree.CopyTo{
+       Statement: $3.slct(),
+       Options: *$7.copyOptions(),
+    }
+  }
+| COPY table_name opt_column_list TO error
+  {
+    return unimplemented(sqllex, "copy to unsupported destination")
+  }
+
 opt_with_copy_options:
   opt_with copy_options_list
   {
     $$.val = $2.copyOptions()
   }
+| opt_with '(' copy_generic_option_list ')'
+  {
+    $$.val = $3.copyOptions()
+  }
 | /* EMPTY */
   {
     $$.val = &tree.CopyOptions{}
@@ -3039,6 +3181,54 @@ copy_options:
   {
     $$.val = &tree.CopyOptions{Null: $2.expr()}
   }
+| QUOTE string_or_placeholder
+  {
+    $$.val = &tree.CopyOptions{Quote: $2.expr()}
+  }
+| HEADER
+  {
+    $$.val = &tree.CopyOptions{Header: true}
+  }
+
+// copy_generic_option_list is the option list syntax of COPY introduced in
+// Postgres 9.0, e.g. COPY t TO STDOUT WITH (FORMAT csv, HEADER).
+copy_generic_option_list:
+  copy_generic_option
+  {
+    $$.val = $1.copyOptions()
+  }
+| copy_generic_option_list ',' copy_generic_option
+  {
+    if err := $1.copyOptions().CombineWith($3.copyOptions()); err != nil {
+      return setErr(sqllex, err)
+    }
+  }
+
+copy_generic_option:
+  copy_options
+  {
+    $$.val = $1.copyOptions()
+  }
+| FORMAT TEXT
+  {
+    $$.val = &tree.CopyOptions{CopyFormat: tree.CopyFormatText}
+  }
+| FORMAT BINARY
+  {
+    $$.val = &tree.CopyOptions{CopyFormat: tree.CopyFormatBinary}
+  }
+| FORMAT CSV
+  {
+    $$.val = &tree.CopyOptions{CopyFormat: tree.CopyFormatCSV}
+  }
+| HEADER TRUE
+  {
+    $$.val = &tree.CopyOptions{Header: true}
+  }
+| HEADER FALSE
+  {
+    $$.val = &tree.CopyOptions{}
+  }
 
 // %Help: CANCEL
 // %Category: Group
@@ -3258,7 +3448,6 @@ drop_unsupported:
 | DROP CAST error { return unimplemented(sqllex, "drop cast") }
 | DROP COLLATION error { return unimplemented(sqllex, "drop collation") }
 | DROP CONVERSION error { return unimplemented(sqllex, "drop conversion") }
-| DROP DOMAIN error { return unimplementedWithIssueDetail(sqllex, 27796, "drop") }
 | DROP EXTENSION IF EXISTS name error { return unimplemented(sqllex, "drop extension " + $5) }
 | DROP EXTENSION name error { return unimplemented(sqllex, "drop extension " + $3) }
 | DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
@@ -3284,13 +3473,93 @@ create_ddl_stmt:
 | create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
+| create_policy_stmt   // EXTEND WITH HELP: CREATE POLICY
+
+// %Help: CREATE POLICY - create a row-level security policy
+// %Category: DDL
+// %Text:
+// CREATE POLICY <name> ON <tablename>
+//   [FOR {ALL | SELECT | INSERT | UPDATE | DELETE}]
+//   [TO <role> [, ...]]
+//   [USING (<expr>)]
+//   [WITH CHECK (<expr>)]
+// %SeeAlso: DROP POLICY, ALTER TABLE
+create_policy_stmt:
+  CREATE POLICY name ON table_name opt_policy_command opt_policy_roles opt_policy_using opt_policy_with_check
+  {
+    $$.val = &tree.CreatePolicy{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName(),
+      Command: $6.policyCommand(),
+      Roles: $7.users(),
+      Using: $8.expr(),
+      WithCheck: $9.expr(),
+    }
+  }
+| CREATE POLICY error // SHOW HELP: CREATE POLICY
+
+opt_policy_command:
+  FOR ALL
+  {
+    $$.val = tree.PolicyAll
+  }
+| FOR SELECT
+  {
+    $$.val = tree.PolicySelect
+  }
+| FOR INSERT
+  {
+    $$.val = tree.PolicyInsert
+  }
+| FOR UPDATE
+  {
+    $$.val = tree.PolicyUpdate
+  }
+| FOR DELETE
+  {
+    $$.val = tree.PolicyDelete
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.PolicyAll
+  }
+
+opt_policy_roles:
+  TO role_spec_list
+  {
+    $$.val = $2.users()
+  }
+| /* EMPTY */
+  {
+    $$.val = []security.SQLUsername(nil)
+  }
+
+opt_policy_using:
+  USING '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+opt_policy_with_check:
+  WITH CHECK '(' a_expr ')'
+  {
+    $$.val = $4.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
 
 // %Help: CREATE STATISTICS - create a new table statistic
 // %Category: Misc
 // %Text:
 // CREATE STATISTICS <statisticname>
 //   [ON <colname> [, ...]]
-//   FROM <tablename> [AS OF SYSTEM TIME <expr>]
+//   FROM <tablename> [USING EXTREMES] [AS OF SYSTEM TIME <expr>]
 create_stats_stmt:
   CREATE STATISTICS statistics_name opt_stats_columns FROM create_stats_target opt_create_stats_options
   {
@@ -3340,6 +3609,21 @@ opt_create_stats_options:
       AsOf: $1.asOfClause(),
     }
   }
+// Allow USING EXTREMES without WITH OPTIONS, optionally followed by AS OF
+// SYSTEM TIME.
+| USING EXTREMES
+  {
+    $$.val = &tree.CreateStatsOptions{
+      UsingExtremes: true,
+    }
+  }
+| USING EXTREMES as_of_clause
+  {
+    $$.val = &tree.CreateStatsOptions{
+      UsingExtremes: true,
+      AsOf: $3.asOfClause(),
+    }
+  }
 | /* EMPTY */
   {
     $$.val = &tree.CreateStatsOptions{}
@@ -3379,6 +3663,12 @@ create_stats_option:
       AsOf: $1.asOfClause(),
     }
   }
+| USING EXTREMES
+  {
+    $$.val = &tree.CreateStatsOptions{
+      UsingExtremes: true,
+    }
+  }
 
 // %Help: CREATE CHANGEFEED  - create change data capture
 // %Category: CCL
@@ -3498,7 +3788,9 @@ replication_options:
 
 // %Help: DELETE - delete rows from a table
 // %Category: DML
-// %Text: DELETE FROM <tablename> [WHERE <expr>]
+// %Text: DELETE FROM <tablename> [[AS] <name>]
+//               [USING <table_expr> [, ...]]
+//               [WHERE <expr>]
 //               [ORDER BY <exprs...>]
 //               [LIMIT <expr>]
 //               [RETURNING <exprs...>]
@@ -3509,6 +3801,7 @@ delete_stmt:
     $$.val = &tree.Delete{
       With: $1.with(),
       Table: $4.tblExpr(),
+      Using: $5.tblExprs(),
       Where: tree.NewWhere(tree.AstWhere, $6.expr()),
       OrderBy: $7.orderBy(),
       Limit: $8.limit(),
@@ -3518,8 +3811,14 @@ delete_stmt:
 | opt_with_clause DELETE error // SHOW HELP: DELETE
 
 opt_using_clause:
-  USING from_list { return unimplementedWithIssueDetail(sqllex, 40963, "delete using") }
-| /* EMPTY */ { }
+  USING from_list
+  {
+    $$.val = $2.tblExprs()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.TableExprs{}
+  }
 
 
 // %Help: DISCARD - reset the session to its initial state
@@ -3545,6 +3844,7 @@ drop_stmt:
   drop_ddl_stmt      // help texts in sub-rule
 | drop_role_stmt     // EXTEND WITH HELP: DROP ROLE
 | drop_schedule_stmt // EXTEND WITH HELP: DROP SCHEDULES
+| drop_plan_baseline_stmt // EXTEND WITH HELP: DROP PLAN BASELINE
 | drop_unsupported   {}
 | DROP error         // SHOW HELP: DROP
 
@@ -3556,6 +3856,7 @@ drop_ddl_stmt:
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
 | drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 | drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
+| drop_policy_stmt   // EXTEND WITH HELP: DROP POLICY
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
@@ -3668,9 +3969,26 @@ drop_database_stmt:
   }
 | DROP DATABASE error // SHOW HELP: DROP DATABASE
 
+// %Help: DROP POLICY - remove a row-level security policy
+// %Category: DDL
+// %Text: DROP POLICY [IF EXISTS] <name> ON <tablename>
+// %SeeAlso: CREATE POLICY
+drop_policy_stmt:
+  DROP POLICY name ON table_name
+  {
+    $$.val = &tree.DropPolicy{Name: tree.Name($3), Table: $5.unresolvedObjectName()}
+  }
+| DROP POLICY IF EXISTS name ON table_name
+  {
+    $$.val = &tree.DropPolicy{Name: tree.Name($5), Table: $7.unresolvedObjectName(), IfExists: true}
+  }
+| DROP POLICY error // SHOW HELP: DROP POLICY
+
 // %Help: DROP TYPE - remove a type
 // %Category: DDL
-// %Text: DROP TYPE [IF EXISTS] <type_name> [, ...] [CASCASE | RESTRICT]
+// %Text:
+// DROP TYPE [IF EXISTS] <type_name> [, ...] [CASCASE | RESTRICT]
+// DROP DOMAIN [IF EXISTS] <domain_name> [, ...] [CASCASE | RESTRICT]
 drop_type_stmt:
   DROP TYPE type_name_list opt_drop_behavior
   {
@@ -3689,6 +4007,25 @@ drop_type_stmt:
     }
   }
 | DROP TYPE error // SHOW HELP: DROP TYPE
+| DROP DOMAIN type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropType{
+      Names: $3.unresolvedObjectNames(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+      IsDomain: true,
+    }
+  }
+| DROP DOMAIN IF EXISTS type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropType{
+      Names: $5.unresolvedObjectNames(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+      IsDomain: true,
+    }
+  }
+| DROP DOMAIN error // SHOW HELP: DROP TYPE
 
 target_types:
   type_name_list
@@ -3866,6 +4203,7 @@ preparable_stmt:
 | explain_stmt   // EXTEND WITH HELP: EXPLAIN
 | import_stmt    // EXTEND WITH HELP: IMPORT
 | insert_stmt    // EXTEND WITH HELP: INSERT
+| merge_stmt     // EXTEND WITH HELP: MERGE
 | pause_stmt     // help texts in sub-rule
 | reset_stmt     // help texts in sub-rule
 | restore_stmt   // EXTEND WITH HELP: RESTORE
@@ -3896,6 +4234,23 @@ row_source_extension_stmt:
 | update_stmt       // EXTEND WITH HELP: UPDATE
 | upsert_stmt       // EXTEND WITH HELP: UPSERT
 
+// %Help: PIN PLAN - pin the plan of a statement
+// %Category: Misc
+// %Text: PIN PLAN FOR <statement>
+//
+// The plan that the optimizer currently chooses for the statement is stored
+// as the plan baseline of the statement's fingerprint. Statements with the
+// same fingerprint are then planned with the pinned plan for as long as it
+// remains valid.
+//
+// %SeeAlso: SHOW PLAN BASELINES, DROP PLAN BASELINE, EXPLAIN
+pin_plan_stmt:
+  PIN PLAN FOR preparable_stmt
+  {
+    $$.val = &tree.PinPlan{Statement: $4.stmt()}
+  }
+| PIN error // SHOW HELP: PIN PLAN
+
 explain_option_list:
   explain_option_name
   {
@@ -4102,6 +4457,81 @@ revoke_stmt:
   }
 | REVOKE error // SHOW HELP: REVOKE
 
+// %Help: ALTER DEFAULT PRIVILEGES - define the privileges granted on objects created in the future
+// %Category: Priv
+// %Text:
+// ALTER DEFAULT PRIVILEGES [FOR ROLE <roles...>] [IN SCHEMA <schemanames...>]
+//   GRANT {ALL [PRIVILEGES] | <privileges...> } ON <targets> TO <grantees...>
+// ALTER DEFAULT PRIVILEGES [FOR ROLE <roles...>] [IN SCHEMA <schemanames...>]
+//   REVOKE {ALL [PRIVILEGES] | <privileges...> } ON <targets> FROM <grantees...>
+//
+// Targets:
+//   TABLES, SEQUENCES, TYPES, SCHEMAS
+//
+// %SeeAlso: GRANT, REVOKE
+alter_default_privileges_stmt:
+  ALTER DEFAULT PRIVILEGES opt_for_roles opt_in_schemas GRANT privileges ON target_object_type TO role_spec_list
+  {
+    $$.val = &tree.AlterDefaultPrivileges{
+      Roles: $4.users(),
+      Schemas: $5.objectNamePrefixList(),
+      IsGrant: true,
+      Privileges: $7.privilegeList(),
+      TargetObject: $9.targetObjectType(),
+      Grantees: $11.users(),
+    }
+  }
+| ALTER DEFAULT PRIVILEGES opt_for_roles opt_in_schemas REVOKE privileges ON target_object_type FROM role_spec_list
+  {
+    $$.val = &tree.AlterDefaultPrivileges{
+      Roles: $4.users(),
+      Schemas: $5.objectNamePrefixList(),
+      IsGrant: false,
+      Privileges: $7.privilegeList(),
+      TargetObject: $9.targetObjectType(),
+      Grantees: $11.users(),
+    }
+  }
+| ALTER DEFAULT PRIVILEGES error // SHOW HELP: ALTER DEFAULT PRIVILEGES
+
+opt_for_roles:
+  FOR role_or_group_or_user role_spec_list
+  {
+    $$.val = $3.users()
+  }
+| /* EMPTY */
+  {
+    $$.val = []security.SQLUsername(nil)
+  }
+
+opt_in_schemas:
+  IN SCHEMA schema_name_list
+  {
+    $$.val = $3.objectNamePrefixList()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.ObjectNamePrefixList(nil)
+  }
+
+target_object_type:
+  TABLES
+  {
+    $$.val = privilege.Tables
+  }
+| SEQUENCES
+  {
+    $$.val = privilege.Sequences
+  }
+| TYPES
+  {
+    $$.val = privilege.Types
+  }
+| SCHEMAS
+  {
+    $$.val = privilege.Schemas
+  }
+
 // ALL can either be by itself, or with the optional PRIVILEGES keyword (which no-ops)
 privileges:
   ALL opt_privileges_clause
@@ -4184,7 +4614,7 @@ use_stmt:
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 | SET LOCAL error { return unimplementedWithIssue(sqllex, 32562) }
 
 // SET SESSION / SET CLUSTER SETTING
@@ -4369,6 +4799,34 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - set when deferrable constraints are checked
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS { ALL | <name> [, ...] } { DEFERRED | IMMEDIATE }
+//
+// Deferred constraints are checked when the current transaction commits.
+// Setting a constraint to IMMEDIATE checks any pending changes right away.
+//
+// %SeeAlso: SET TRANSACTION
+set_constraints_stmt:
+  SET CONSTRAINTS ALL DEFERRED
+  {
+    $$.val = &tree.SetConstraints{Deferred: true}
+  }
+| SET CONSTRAINTS ALL IMMEDIATE
+  {
+    $$.val = &tree.SetConstraints{Deferred: false}
+  }
+| SET CONSTRAINTS name_list DEFERRED
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: true}
+  }
+| SET CONSTRAINTS name_list IMMEDIATE
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: false}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -4552,7 +5010,7 @@ zone_value:
 // %Text:
 // SHOW BACKUP, SHOW CLUSTER SETTING, SHOW COLUMNS, SHOW CONSTRAINTS,
 // SHOW CREATE, SHOW DATABASES, SHOW ENUMS, SHOW HISTOGRAM, SHOW INDEXES, SHOW
-// PARTITIONS, SHOW JOBS, SHOW STATEMENTS, SHOW RANGE, SHOW RANGES, SHOW REGIONS, SHOW SURVIVAL GOAL,
+// PARTITIONS, SHOW PLAN BASELINES, SHOW JOBS, SHOW STATEMENTS, SHOW RANGE, SHOW RANGES, SHOW REGIONS, SHOW SURVIVAL GOAL,
 // SHOW ROLES, SHOW SCHEMAS, SHOW SEQUENCES, SHOW SESSION, SHOW SESSIONS,
 // SHOW STATISTICS, SHOW SYNTAX, SHOW TABLES, SHOW TRACE, SHOW TRANSACTION,
 // SHOW TRANSACTIONS, SHOW TYPES, SHOW USERS, SHOW LAST QUERY STATISTICS, SHOW SCHEDULES,
@@ -4571,6 +5029,7 @@ show_stmt:
 | show_histogram_stmt       // EXTEND WITH HELP: SHOW HISTOGRAM
 | show_indexes_stmt         // EXTEND WITH HELP: SHOW INDEXES
 | show_partitions_stmt      // EXTEND WITH HELP: SHOW PARTITIONS
+| show_plan_baselines_stmt  // EXTEND WITH HELP: SHOW PLAN BASELINES
 | show_jobs_stmt            // EXTEND WITH HELP: SHOW JOBS
 | show_locality_stmt
 | show_schedules_stmt       // EXTEND WITH HELP: SHOW SCHEDULES
@@ -4597,14 +5056,244 @@ show_stmt:
 | show_last_query_stats_stmt
 | show_full_scans_stmt
 
-// Cursors are not yet supported by CockroachDB. CLOSE ALL is safe to no-op
-// since there will be no open cursors.
+// %Help: CLOSE - close a SQL cursor
+// %Category: Misc
+// %Text: CLOSE { <cursor_name> | ALL }
+// %SeeAlso: DECLARE, FETCH, MOVE
 close_cursor_stmt:
-	CLOSE ALL { }
-| CLOSE cursor_name { return unimplementedWithIssue(sqllex, 41412) }
+  CLOSE ALL
+  {
+    $$.val = &tree.CloseCursor{All: true}
+  }
+| CLOSE cursor_name
+  {
+    $$.val = &tree.CloseCursor{Name: tree.Name($2)}
+  }
+| CLOSE error // SHOW HELP: CLOSE
 
+// %Help: DECLARE - define a SQL cursor
+// %Category: Misc
+// %Text:
+// DECLARE <cursor_name> [ BINARY ] [ ASENSITIVE | INSENSITIVE ] [ [ NO ] SCROLL ]
+//   CURSOR [ { WITH | WITHOUT } HOLD ] FOR <selectclause>
+// %SeeAlso: FETCH, MOVE, CLOSE, SELECT
 declare_cursor_stmt:
-	DECLARE { return unimplementedWithIssue(sqllex, 41412) }
+  DECLARE cursor_name opt_binary opt_sensitivity opt_scroll CURSOR opt_hold FOR select_stmt
+  {
+    $$.val = &tree.DeclareCursor{
+      Name: tree.Name($2),
+      Binary: $3.bool(),
+      Sensitivity: $4.cursorSensitivity(),
+      Scroll: $5.cursorScrollOption(),
+      Hold: $7.bool(),
+      Select: $9.slct(),
+    }
+  }
+| DECLARE error // SHOW HELP: DECLARE
+
+opt_binary:
+  BINARY
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+opt_sensitivity:
+  INSENSITIVE
+  {
+    $$.val = tree.Insensitive
+  }
+| ASENSITIVE
+  {
+    $$.val = tree.Asensitive
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.UnspecifiedSensitivity
+  }
+
+opt_scroll:
+  SCROLL
+  {
+    $$.val = tree.Scroll
+  }
+| NO SCROLL
+  {
+    $$.val = tree.NoScroll
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.UnspecifiedScroll
+  }
+
+opt_hold:
+  WITH HOLD
+  {
+    $$.val = true
+  }
+| WITHOUT HOLD
+  {
+    $$.val = false
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+// %Help: FETCH - fetch rows from a SQL cursor
+// %Category: Misc
+// %Text:
+// FETCH [ <direction> [ FROM | IN ] ] <cursor_name>
+//
+// Direction:
+//   NEXT | FIRST | LAST | ABSOLUTE <count> | RELATIVE <count> | <count> | ALL
+//   | FORWARD [ <count> | ALL ]
+// %SeeAlso: MOVE, DECLARE, CLOSE
+fetch_cursor_stmt:
+  FETCH cursor_movement_specifier
+  {
+    $$.val = &tree.FetchCursor{
+      CursorStmt: $2.cursorStmt(),
+    }
+  }
+| FETCH error // SHOW HELP: FETCH
+
+// %Help: MOVE - move a SQL cursor without fetching rows
+// %Category: Misc
+// %Text:
+// MOVE [ <direction> [ FROM | IN ] ] <cursor_name>
+//
+// Direction:
+//   NEXT | FIRST | LAST | ABSOLUTE <count> | RELATIVE <count> | <count> | ALL
+//   | FORWARD [ <count> | ALL ]
+// %SeeAlso: FETCH, DECLARE, CLOSE
+move_cursor_stmt:
+  MOVE cursor_movement_specifier
+  {
+    $$.val = &tree.MoveCursor{
+      CursorStmt: $2.cursorStmt(),
+    }
+  }
+| MOVE error // SHOW HELP: MOVE
+
+cursor_movement_specifier:
+  cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($1),
+      Count: 1,
+    }
+  }
+| from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($2),
+      Count: 1,
+    }
+  }
+| next_prior opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      Count: $1.int64(),
+    }
+  }
+| forward_backward opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      Count: $1.int64(),
+    }
+  }
+| opt_forward_backward signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      Count: $2.int64() * $1.int64(),
+    }
+  }
+| opt_forward_backward ALL opt_from_or_in cursor_name
+  {
+    fetchType := tree.FetchAll
+    if $1.int64() < 0 {
+      fetchType = tree.FetchBackwardAll
+    }
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: fetchType,
+    }
+  }
+| ABSOLUTE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: tree.FetchAbsolute,
+      Count: $2.int64(),
+    }
+  }
+| RELATIVE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: tree.FetchRelative,
+      Count: $2.int64(),
+    }
+  }
+| FIRST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      FetchType: tree.FetchFirst,
+    }
+  }
+| LAST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      FetchType: tree.FetchLast,
+    }
+  }
+
+next_prior:
+  NEXT
+  {
+    $$.val = int64(1)
+  }
+| PRIOR
+  {
+    $$.val = int64(-1)
+  }
+
+opt_forward_backward:
+  forward_backward
+  {
+    $$.val = $1.int64()
+  }
+| /* EMPTY */
+  {
+    $$.val = int64(1)
+  }
+
+forward_backward:
+  FORWARD
+  {
+    $$.val = int64(1)
+  }
+| BACKWARD
+  {
+    $$.val = int64(-1)
+  }
+
+opt_from_or_in:
+  from_or_in { }
+| /* EMPTY */ { }
+
+from_or_in:
+  FROM { }
+| IN { }
 
 reindex_stmt:
   REINDEX TABLE error
@@ -4644,6 +5333,12 @@ show_session_stmt:
 
 session_var:
   IDENT
+// Variables of extensions are qualified with the name of the extension,
+// e.g. pg_trgm.similarity_threshold.
+| IDENT attrs
+  {
+    $$ = strings.Join(append([]string{$1}, $2.strs()...), ".")
+  }
 // Although ALL, SESSION_USER and DATABASE are identifiers for the
 // purpose of SHOW, they lex as separate token types, so they need
 // separate rules.
@@ -5021,6 +5716,17 @@ show_jobs_stmt:
   }
 | SHOW JOB error // SHOW HELP: SHOW JOBS
 
+// %Help: SHOW PLAN BASELINES - list pinned plans
+// %Category: Misc
+// %Text: SHOW PLAN BASELINES
+// %SeeAlso: PIN PLAN, DROP PLAN BASELINE
+show_plan_baselines_stmt:
+  SHOW PLAN BASELINES
+  {
+    $$.val = &tree.ShowPlanBaselines{}
+  }
+| SHOW PLAN error // SHOW HELP: SHOW PLAN BASELINES
+
 // %Help: SHOW SCHEDULES - list periodic schedules
 // %Category: Misc
 // %Text:
@@ -6517,6 +7223,10 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability() != tree.NotDeferrableConstraint {
+      sqllex.Error("CHECK constraints cannot be marked DEFERRABLE")
+      return 1
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
@@ -6524,6 +7234,10 @@ constraint_elem:
 | UNIQUE opt_without_index '(' index_params ')'
     opt_storing opt_interleave opt_partition_by_index opt_deferrable opt_where_clause
   {
+    /* FORCE DOC */
+    if !$2.bool() && $9.constraintDeferrability() != tree.NotDeferrableConstraint {
+      return unimplementedWithIssueDetail(sqllex, 31632, "deferrable unique index")
+    }
     $$.val = &tree.UniqueConstraintTableDef{
       WithoutIndex: $2.bool(),
       IndexTableDef: tree.IndexTableDef{
@@ -6533,6 +7247,7 @@ constraint_elem:
         PartitionByIndex: $8.partitionByIndex(),
         Predicate: $10.expr(),
       },
+      Deferrability: $9.constraintDeferrability(),
     }
   }
 | PRIMARY KEY '(' index_params ')' opt_hash_sharded opt_interleave
@@ -6556,6 +7271,7 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrability: $11.constraintDeferrability(),
     }
   }
 | EXCLUDE USING error
@@ -6665,13 +7381,33 @@ create_as_col_qualification_elem:
     $$.val = tree.PrimaryKeyConstraint{}
   }
 
+// INITIALLY DEFERRED implies DEFERRABLE, and INITIALLY IMMEDIATE without
+// DEFERRABLE is the default (NOT DEFERRABLE), as in Postgres.
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */
+  {
+    $$.val = tree.NotDeferrableConstraint
+  }
+| DEFERRABLE
+  {
+    $$.val = tree.InitiallyImmediateConstraint
+  }
+| DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.InitiallyImmediateConstraint
+  }
+| DEFERRABLE INITIALLY DEFERRED
+  {
+    $$.val = tree.InitiallyDeferredConstraint
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.NotDeferrableConstraint
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.InitiallyDeferredConstraint
+  }
 
 storing:
   COVERING
@@ -6958,7 +7694,10 @@ role_or_group_or_user:
 
 // %Help: CREATE VIEW - create a new view
 // %Category: DDL
-// %Text: CREATE [TEMPORARY | TEMP] [MATERIALIZED] VIEW [IF NOT EXISTS] <viewname> [( <colnames...> )] AS <source>
+// %Text:
+// CREATE [TEMPORARY | TEMP] VIEW [IF NOT EXISTS] <viewname> [( <colnames...> )] AS <source>
+// CREATE MATERIALIZED VIEW [IF NOT EXISTS] <viewname> [( <colnames...> )]
+//    [WITH ( auto_refresh = { manual | incremental } )] AS <source>
 // %SeeAlso: CREATE TABLE, SHOW CREATE, WEBDOCS/create-view.html
 create_view_stmt:
   CREATE opt_temp opt_view_recursive VIEW view_name opt_column_list AS select_stmt
@@ -6999,25 +7738,27 @@ create_view_stmt:
       Replace: false,
     }
   }
-| CREATE MATERIALIZED VIEW view_name opt_column_list AS select_stmt
+| CREATE MATERIALIZED VIEW view_name opt_column_list opt_with_storage_parameter_list AS select_stmt
   {
     name := $4.unresolvedObjectName().ToTableName()
     $$.val = &tree.CreateView{
       Name: name,
       ColumnNames: $5.nameList(),
-      AsSource: $7.slct(),
+      AsSource: $8.slct(),
       Materialized: true,
+      StorageParams: $6.storageParams(),
     }
   }
-| CREATE MATERIALIZED VIEW IF NOT EXISTS view_name opt_column_list AS select_stmt
+| CREATE MATERIALIZED VIEW IF NOT EXISTS view_name opt_column_list opt_with_storage_parameter_list AS select_stmt
   {
     name := $7.unresolvedObjectName().ToTableName()
     $$.val = &tree.CreateView{
       Name: name,
       ColumnNames: $8.nameList(),
-      AsSource: $10.slct(),
+      AsSource: $11.slct(),
       Materialized: true,
       IfNotExists: true,
+      StorageParams: $9.storageParams(),
     }
   }
 | CREATE opt_temp opt_view_recursive VIEW error // SHOW HELP: CREATE VIEW
@@ -7136,7 +7877,13 @@ opt_view_recursive:
 
 // %Help: CREATE TYPE -- create a type
 // %Category: DDL
-// %Text: CREATE TYPE [IF NOT EXISTS] <type_name> AS ENUM (...)
+// %Text:
+// CREATE TYPE [IF NOT EXISTS] <type_name> AS ENUM (...)
+// CREATE TYPE [IF NOT EXISTS] <type_name> AS (<name> <type> [, ...])
+// CREATE DOMAIN <domain_name> [AS] <type> [<constraint> ...]
+//
+// Domain constraints:
+//    [CONSTRAINT <name>] { NOT NULL | NULL | CHECK (<expr>) | DEFAULT <expr> }
 create_type_stmt:
   // Enum types.
   CREATE TYPE type_name AS ENUM '(' opt_enum_val_list ')'
@@ -7156,9 +7903,25 @@ create_type_stmt:
       IfNotExists: true,
     }
   }
+  // Composite types.
+| CREATE TYPE type_name AS '(' opt_composite_type_list ')'
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $3.unresolvedObjectName(),
+      Variety: tree.Composite,
+      CompositeTypeList: $6.compositeTypeList(),
+    }
+  }
+| CREATE TYPE IF NOT EXISTS type_name AS '(' opt_composite_type_list ')'
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $6.unresolvedObjectName(),
+      Variety: tree.Composite,
+      CompositeTypeList: $9.compositeTypeList(),
+      IfNotExists: true,
+    }
+  }
 | CREATE TYPE error // SHOW HELP: CREATE TYPE
-  // Record/Composite types.
-| CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
   // Range types.
 | CREATE TYPE type_name AS RANGE error    { return unimplementedWithIssue(sqllex, 27791) }
   // Base (primitive) types.
@@ -7166,7 +7929,65 @@ create_type_stmt:
   // Shell types, gateway to define base types using the previous syntax.
 | CREATE TYPE type_name                   { return unimplementedWithIssueDetail(sqllex, 27793, "shell") }
   // Domain types.
-| CREATE DOMAIN type_name error           { return unimplementedWithIssueDetail(sqllex, 27796, "create") }
+| CREATE DOMAIN type_name AS typename opt_domain_constraint_list
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $3.unresolvedObjectName(),
+      Variety: tree.Domain,
+      DomainType: $5.typeReference(),
+      DomainConstraints: $6.colQuals(),
+    }
+  }
+| CREATE DOMAIN type_name typename opt_domain_constraint_list
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $3.unresolvedObjectName(),
+      Variety: tree.Domain,
+      DomainType: $4.typeReference(),
+      DomainConstraints: $5.colQuals(),
+    }
+  }
+| CREATE DOMAIN error // SHOW HELP: CREATE TYPE
+
+opt_domain_constraint_list:
+  opt_domain_constraint_list domain_constraint
+  {
+    $$.val = append($1.colQuals(), $2.colQual())
+  }
+| /* EMPTY */
+  {
+    $$.val = []tree.NamedColumnQualification(nil)
+  }
+
+domain_constraint:
+  CONSTRAINT constraint_name domain_constraint_elem
+  {
+    $$.val = tree.NamedColumnQualification{Name: tree.Name($2), Qualification: $3.colQualElem()}
+  }
+| domain_constraint_elem
+  {
+    $$.val = tree.NamedColumnQualification{Qualification: $1.colQualElem()}
+  }
+
+// As for col_qualification_elem, DEFAULT uses b_expr to avoid a shift/reduce
+// conflict on NOT.
+domain_constraint_elem:
+  NOT NULL
+  {
+    $$.val = tree.NotNullConstraint{}
+  }
+| NULL
+  {
+    $$.val = tree.NullConstraint{}
+  }
+| CHECK '(' a_expr ')'
+  {
+    $$.val = &tree.ColumnCheckConstraint{Expr: $3.expr()}
+  }
+| DEFAULT b_expr
+  {
+    $$.val = &tree.ColumnDefault{Expr: $2.expr()}
+  }
 
 opt_enum_val_list:
   enum_val_list
@@ -7188,6 +8009,36 @@ enum_val_list:
     $$.val = append($1.enumValueList(), tree.EnumValue($3))
   }
 
+opt_composite_type_list:
+  composite_type_list
+  {
+    $$.val = $1.compositeTypeList()
+  }
+| /* EMPTY */
+  {
+    $$.val = []tree.CompositeTypeElem{}
+  }
+
+composite_type_list:
+  name typename
+  {
+    $$.val = []tree.CompositeTypeElem{
+      {
+        Label: tree.Name($1),
+        Type: $2.typeReference(),
+      },
+    }
+  }
+| composite_type_list ',' name typename
+  {
+    $$.val = append($1.compositeTypeList(),
+      tree.CompositeTypeElem{
+        Label: tree.Name($3),
+        Type: $4.typeReference(),
+      },
+    )
+  }
+
 // %Help: CREATE INDEX - create a new index
 // %Category: DDL
 // %Text:
@@ -7358,10 +8209,7 @@ index_elem_options:
     opClass := $1
     dir := $2.dir()
     nullsOrder := $3.nullsOrder()
-    if opClass != "" {
-      if opClass == "gin_trgm_ops" || opClass == "gist_trgm_ops" {
-        return unimplementedWithIssueDetail(sqllex, 41285, "index using " + opClass)
-      }
+    if opClass != "" && opClass != "gin_trgm_ops" && opClass != "gist_trgm_ops" {
       return unimplementedWithIssue(sqllex, 47420)
     }
     // We currently only support the opposite of Postgres defaults.
@@ -7373,7 +8221,7 @@ index_elem_options:
         return unimplementedWithIssue(sqllex, 6224)
       }
     }
-    $$.val = tree.IndexElem{Direction: dir, NullsOrder: nullsOrder}
+    $$.val = tree.IndexElem{OpClass: tree.Name(opClass), Direction: dir, NullsOrder: nullsOrder}
   }
 
 opt_class:
@@ -7807,6 +8655,23 @@ drop_schedule_stmt:
   }
 | DROP SCHEDULES error // SHOW HELP: DROP SCHEDULES
 
+// %Help: DROP PLAN BASELINE - remove a pinned plan
+// %Category: Misc
+// %Text:
+// DROP PLAN BASELINE FOR <statement>
+// DROP PLAN BASELINE <fingerprint>
+// %SeeAlso: PIN PLAN, SHOW PLAN BASELINES
+drop_plan_baseline_stmt:
+  DROP PLAN BASELINE FOR preparable_stmt
+  {
+    $$.val = &tree.DropPlanBaseline{Statement: $5.stmt()}
+  }
+| DROP PLAN BASELINE SCONST
+  {
+    $$.val = &tree.DropPlanBaseline{Fingerprint: $4}
+  }
+| DROP PLAN BASELINE error // SHOW HELP: DROP PLAN BASELINE
+
 // %Help: SAVEPOINT - start a sub-transaction
 // %Category: Txn
 // %Text: SAVEPOINT <savepoint name>
@@ -8189,6 +9054,81 @@ upsert_stmt:
   }
 | opt_with_clause UPSERT error // SHOW HELP: UPSERT
 
+// %Help: MERGE - insert, update or delete rows based on a join with a source
+// %Category: DML
+// %Text:
+// MERGE INTO <tablename> [[AS] <name>]
+//        USING <source> ON <join_condition>
+//        WHEN MATCHED [AND <expr>] THEN { UPDATE SET ... | DELETE | DO NOTHING }
+//        WHEN NOT MATCHED [AND <expr>] THEN
+//          { INSERT [( <colnames...> )] { VALUES ( <exprs...> ) | DEFAULT VALUES } | DO NOTHING }
+//        [...]
+// %SeeAlso: INSERT, UPSERT, UPDATE, DELETE
+merge_stmt:
+  opt_with_clause MERGE INTO table_expr_opt_alias_idx USING table_ref ON a_expr merge_when_list
+  {
+    $$.val = &tree.Merge{
+      With: $1.with(),
+      Table: $4.tblExpr(),
+      Source: $6.tblExpr(),
+      On: $8.expr(),
+      Whens: $9.mergeWhens(),
+    }
+  }
+| opt_with_clause MERGE error // SHOW HELP: MERGE
+
+merge_when_list:
+  merge_when_clause
+  {
+    $$.val = tree.MergeWhens{$1.mergeWhen()}
+  }
+| merge_when_list merge_when_clause
+  {
+    $$.val = append($1.mergeWhens(), $2.mergeWhen())
+  }
+
+merge_when_clause:
+  WHEN MATCHED opt_merge_when_cond THEN UPDATE SET set_clause_list
+  {
+    $$.val = &tree.MergeWhen{Matched: true, Cond: $3.expr(), Action: tree.MergeUpdate, Exprs: $7.updateExprs()}
+  }
+| WHEN MATCHED opt_merge_when_cond THEN DELETE
+  {
+    $$.val = &tree.MergeWhen{Matched: true, Cond: $3.expr(), Action: tree.MergeDelete}
+  }
+| WHEN MATCHED opt_merge_when_cond THEN DO NOTHING
+  {
+    $$.val = &tree.MergeWhen{Matched: true, Cond: $3.expr(), Action: tree.MergeDoNothing}
+  }
+| WHEN NOT MATCHED opt_merge_when_cond THEN INSERT VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeWhen{Cond: $4.expr(), Action: tree.MergeInsert, Values: $9.exprs()}
+  }
+| WHEN NOT MATCHED opt_merge_when_cond THEN INSERT '(' insert_column_list ')' VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeWhen{
+      Cond: $4.expr(), Action: tree.MergeInsert, Columns: $8.nameList(), Values: $12.exprs(),
+    }
+  }
+| WHEN NOT MATCHED opt_merge_when_cond THEN INSERT DEFAULT VALUES
+  {
+    $$.val = &tree.MergeWhen{Cond: $4.expr(), Action: tree.MergeInsert}
+  }
+| WHEN NOT MATCHED opt_merge_when_cond THEN DO NOTHING
+  {
+    $$.val = &tree.MergeWhen{Cond: $4.expr(), Action: tree.MergeDoNothing}
+  }
+
+opt_merge_when_cond:
+  AND a_expr
+  {
+    $$.val = $2.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
 insert_target:
   table_name
   {
@@ -10370,6 +11310,10 @@ a_expr:
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.Overlaps, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr AT_AT a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: tree.TSMatches, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr INET_CONTAINS_OR_EQUALS a_expr
   {
     $$.val = &tree.FuncExpr{Func: tree.WrapFunction("inet_contains_or_equals"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
@@ -12325,6 +13269,7 @@ unrestricted_name:
 // "Unreserved" keywords --- available for use as any kind of name.
 unreserved_keyword:
   ABORT
+| ABSOLUTE
 | ACTION
 | ACCESS
 | ADD
@@ -12333,12 +13278,16 @@ unreserved_keyword:
 | AGGREGATE
 | ALTER
 | ALWAYS
+| ASENSITIVE
 | AT
 | ATTRIBUTE
 | AUTOMATIC
 | AVAILABILITY
 | BACKUP
 | BACKUPS
+| BACKWARD
+| BASELINE
+| BASELINES
 | BEFORE
 | BEGIN
 | BINARY
@@ -12391,10 +13340,12 @@ unreserved_keyword:
 | DELIMITER
 | DESTINATION
 | DETACHED
+| DISABLE
 | DISCARD
 | DOMAIN
 | DOUBLE
 | DROP
+| ENABLE
 | ENCODING
 | ENCRYPTION_PASSPHRASE
 | ENUM
@@ -12413,6 +13364,7 @@ unreserved_keyword:
 | EXPLAIN
 | EXPORT
 | EXTENSION
+| EXTREMES
 | FAILURE
 | FILES
 | FILTER
@@ -12420,6 +13372,8 @@ unreserved_keyword:
 | FOLLOWING
 | FORCE
 | FORCE_INDEX
+| FORMAT
+| FORWARD
 | FUNCTION
 | GENERATED
 | GEOMETRYM
@@ -12434,8 +13388,10 @@ unreserved_keyword:
 | GRANTS
 | GROUPS
 | HASH
+| HEADER
 | HIGH
 | HISTOGRAM
+| HOLD
 | HOUR
 | IDENTITY
 | IMMEDIATE
@@ -12448,6 +13404,7 @@ unreserved_keyword:
 | INDEXES
 | INHERITS
 | INJECT
+| INSENSITIVE
 | INSERT
 | INTERLEAVE
 | INTO_DB
@@ -12477,6 +13434,7 @@ unreserved_keyword:
 | LOOKUP
 | LOW
 | MATCH
+| MATCHED
 | MATERIALIZED
 | MAXVALUE
 | MERGE
@@ -12484,6 +13442,7 @@ unreserved_keyword:
 | MINUTE
 | MINVALUE
 | MODIFYCLUSTERSETTING
+| MOVE
 | MULTILINESTRING
 | MULTILINESTRINGM
 | MULTILINESTRINGZ
@@ -12537,23 +13496,27 @@ unreserved_keyword:
 | PAUSE
 | PAUSED
 | PHYSICAL
+| PIN
 | PLAN
 | PLANS
 | POINTM
 | POINTZ
 | POINTZM
+| POLICY
 | POLYGONM
 | POLYGONZ
 | POLYGONZM
 | PRECEDING
 | PREPARE
 | PRESERVE
+| PRIOR
 | PRIORITY
 | PRIVILEGES
 | PUBLIC
 | PUBLICATION
 | QUERIES
 | QUERY
+| QUOTE
 | RANGE
 | RANGES
 | READ
@@ -12566,6 +13529,7 @@ unreserved_keyword:
 | REGIONAL
 | REGIONS
 | REINDEX
+| RELATIVE
 | RELEASE
 | RENAME
 | REPEATABLE
@@ -12587,6 +13551,7 @@ unreserved_keyword:
 | RUNNING
 | SCHEDULE
 | SCHEDULES
+| SCROLL
 | SETTING
 | SETTINGS
 | STATUS
@@ -12598,6 +13563,7 @@ unreserved_keyword:
 | SCRUB
 | SEARCH
 | SECOND
+| SECURITY
 | SERIALIZABLE
 | SEQUENCE
 | SEQUENCES
@@ -12621,6 +13587,7 @@ unreserved_keyword:
 | STATEMENTS
 | STATISTICS
 | STDIN
+| STDOUT
 | STORAGE
 | STORE
 | STORED
@@ -738,6 +738,10 @@ func populateTableConstraints(
 		consrc := tree.DNull
 		conbin := tree.DNull
 		condef := tree.DNull
+		condeferrable := tree.MakeDBool(
+			con.Deferrability() != descpb.ConstraintDeferrability_NotDeferrable)
+		condeferred := tree.MakeDBool(
+			con.Deferrability() == descpb.ConstraintDeferrability_InitiallyDeferred)
 
 		// Determine constraint kind-specific fields.
 		var err error
@@ -872,8 +876,8 @@ func populateTableConstraints(
 			dNameOrNull(conName), // conname
 			namespaceOid,         // connamespace
 			contype,              // contype
-			tree.DBoolFalse,      // condeferrable
-			tree.DBoolFalse,      // condeferred
+			condeferrable,        // condeferrable
+			condeferred,          // condeferred
 			tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -1578,6 +1582,9 @@ func indexDefFromDescriptor(
 		if index.ColumnDirections[index.ExplicitColumnStartIdx()+i] == descpb.IndexDescriptor_DESC {
 			elem.Direction = tree.Descending
 		}
+		if i == len(colNames)-1 {
+			elem.OpClass = index.InvertedColumnOpClass()
+		}
 		indexDef.Columns[i] = elem
 	}
 	for i, name := range index.StoreColumnNames {
@@ -2271,8 +2278,6 @@ var (
 	typTypeRange     = tree.NewDString("r")
 
 	// Avoid unused warning for constants.
-	_ = typTypeComposite
-	_ = typTypeDomain
 	_ = typTypePseudo
 	_ = typTypeRange
 
@@ -2294,7 +2299,6 @@ var (
 	typCategoryUnknown     = tree.NewDString("X")
 
 	// Avoid unused warning for constants.
-	_ = typCategoryComposite
 	_ = typCategoryEnum
 	_ = typCategoryGeometric
 	_ = typCategoryRange
@@ -2337,9 +2341,26 @@ func addPGTypeRow(
 		builtinPrefix = "enum_"
 		typType = typTypeEnum
 	}
+	if typ.Family() == types.TupleFamily && typ.UserDefined() {
+		builtinPrefix = "record_"
+		typType = typTypeComposite
+	}
 	if cat == typCategoryPseudo {
 		typType = typTypePseudo
 	}
+	typNotNull := tree.DBoolFalse
+	typBaseType := oidZero
+	typDefault := tree.DNull
+	if typ.IsDomain() {
+		typType = typTypeDomain
+		if domain := typ.TypeMeta.DomainData; domain != nil {
+			typNotNull = tree.MakeDBool(tree.DBool(domain.NotNull))
+			typBaseType = tree.NewDOid(tree.DInt(domain.BaseType.Oid()))
+			if domain.DefaultExpr != nil {
+				typDefault = tree.NewDString(*domain.DefaultExpr)
+			}
+		}
+	}
 	typname := typ.PGName()
 
 	return addRow(
@@ -2369,13 +2390,13 @@ func addPGTypeRow(
 
 		tree.DNull,      // typalign
 		tree.DNull,      // typstorage
-		tree.DBoolFalse, // typnotnull
-		oidZero,         // typbasetype
+		typNotNull,      // typnotnull
+		typBaseType,     // typbasetype
 		negOneVal,       // typtypmod
 		zeroVal,         // typndims
 		typColl(typ, h), // typcollation
 		tree.DNull,      // typdefaultbin
-		tree.DNull,      // typdefault
+		typDefault,      // typdefault
 		tree.DNull,      // typacl
 	)
 }
@@ -2666,12 +2687,28 @@ var pgCatalogGroupTable = virtualSchemaTable{
 }
 
 var pgCatalogCursorsTable = virtualSchemaTable{
-	comment: "pg_cursors was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogCursors,
+	comment: `open cursors
+https://www.postgresql.org/docs/current/view-pg-cursors.html`,
+	schema: vtable.PgCatalogCursors,
 	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		for name, cursor := range p.sqlCursors.list() {
+			ts, err := tree.MakeDTimestampTZ(cursor.created, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				tree.DBoolFalse, /* is_scrollable */
+				tree.NewDString(string(name)),
+				tree.NewDString(cursor.statement),
+				ts,
+				tree.DBoolFalse, /* is_binary */
+				tree.MakeDBool(tree.DBool(cursor.hold)),
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogTsParserTable = virtualSchemaTable{
@@ -2873,6 +2910,8 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.OidFamily:         typCategoryNumeric,
 	types.UuidFamily:        typCategoryUserDefined,
 	types.INetFamily:        typCategoryNetworkAddr,
+	types.TSQueryFamily:     typCategoryUserDefined,
+	types.TSVectorFamily:    typCategoryUserDefined,
 	types.UnknownFamily:     typCategoryUnknown,
 }
 
@@ -2881,6 +2920,10 @@ func typCategory(typ *types.T) tree.Datum {
 	if typ.Family() == types.ArrayFamily && typ.ArrayContents().Family() == types.AnyFamily {
 		return typCategoryPseudo
 	}
+	// Composite types are the only tuple types which aren't pseudo types.
+	if typ.Family() == types.TupleFamily && typ.UserDefined() {
+		return typCategoryComposite
+	}
 	return datumToTypeCategory[typ.Family()]
 }
 
@@ -38,6 +38,7 @@ const (
 	emptyQueryResponse
 	readyForQuery
 	flush
+	portalSuspended
 	// Some commands, like Describe, don't need a completion message.
 	noCompletionMsg
 )
@@ -89,6 +90,10 @@ type commandResult struct {
 	// statements.
 	bufferingDisabled bool
 
+	// copyOut is set for COPY TO statements, whose result rows are sent in the
+	// Copy-out subprotocol instead of DataRow messages.
+	copyOut *copyOutEncoder
+
 	// released is set when the command result has been released so that its
 	// memory can be reused. It is also used to assert against use-after-free
 	// errors.
@@ -137,6 +142,10 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 		}
 	}
 
+	if r.copyOut != nil && r.copyOut.started {
+		r.conn.bufferCopyDone(r.copyOut)
+	}
+
 	// Send a completion message, specific to the type of result.
 	switch r.typ {
 	case commandComplete:
@@ -159,6 +168,8 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 	case flush:
 		// The error is saved on conn.err.
 		_ /* err */ = r.conn.Flush(r.pos)
+	case portalSuspended:
+		r.conn.bufferPortalSuspended()
 	case noCompletionMsg:
 		// nothing to do
 	default:
@@ -203,7 +214,11 @@ func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 	}
 	r.rowsAffected++
 
-	r.conn.bufferRow(ctx, row, r.formatCodes, r.conv, r.location, r.types)
+	if r.copyOut != nil {
+		r.conn.bufferCopyData(ctx, r.copyOut, row, r.conv, r.location, r.types)
+	} else {
+		r.conn.bufferRow(ctx, row, r.formatCodes, r.conv, r.location, r.types)
+	}
 	var err error
 	if r.bufferingDisabled {
 		err = r.conn.Flush(r.pos)
@@ -213,6 +228,12 @@ func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 	return err
 }
 
+// SetPortalSuspended is part of the CommandResult interface.
+func (r *commandResult) SetPortalSuspended() {
+	r.assertNotReleased()
+	r.typ = portalSuspended
+}
+
 // DisableBuffering is part of the CommandResult interface.
 func (r *commandResult) DisableBuffering() {
 	r.assertNotReleased()
@@ -236,7 +257,9 @@ func (r *commandResult) BufferNotice(notice pgnotice.Notice) {
 func (r *commandResult) SetColumns(ctx context.Context, cols colinfo.ResultColumns) {
 	r.assertNotReleased()
 	r.conn.writerState.fi.registerCmd(r.pos)
-	if r.descOpt == sql.NeedRowDesc {
+	if r.copyOut != nil {
+		r.conn.bufferCopyOutResponse(r.copyOut, cols)
+	} else if r.descOpt == sql.NeedRowDesc {
 		_ /* err */ = r.conn.writeRowDescription(ctx, cols, r.formatCodes, &r.conn.writerState.buf)
 	}
 	r.types = make([]*types.T, len(cols))
@@ -345,6 +368,9 @@ func (c *conn) newCommandResult(
 		descOpt:        descOpt,
 		formatCodes:    formatCodes,
 	}
+	if copyTo, ok := stmt.(*tree.CopyTo); ok {
+		r.copyOut = c.newCopyOutEncoder(copyTo)
+	}
 	if limit == 0 {
 		return r
 	}
@@ -386,6 +412,12 @@ func (c *conn) newMiscResult(pos sql.CmdPos, typ completionMsgType) *commandResu
 // forward. The work included is things like auditing all of the defers and
 // post-execution stuff (like stats collection) to have it only execute once
 // per statement instead of once per portal.
+//
+// When the multiple_active_portals_enabled session variable is set, the
+// portals of read-only queries executed in explicit transactions are instead
+// suspended by the connExecutor (see connExecutor.execPausablePortal), and
+// can be interleaved with other portals and statements. Those portals don't
+// use a limitedCommandResult.
 type limitedCommandResult struct {
 	*commandResult
 	portalName  string
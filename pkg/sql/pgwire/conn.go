@@ -773,6 +773,14 @@ func (c *conn) handleSimpleQuery(
 			copyDone.Wait()
 			return nil
 		}
+		// The options of COPY TO are validated here, before the statement is
+		// executed, so that the result of the statement can be encoded without
+		// errors.
+		if cp, ok := stmts[i].AST.(*tree.CopyTo); ok {
+			if _, err := makeCopyOutFormatOptions(cp); err != nil {
+				return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
+			}
+		}
 
 		if err := c.stmtBuf.Push(
 			ctx,
@@ -873,6 +881,9 @@ func (c *conn) handleParse(
 		// https://www.postgresql.org/message-id/flat/CAMsr%2BYGvp2wRx9pPSxaKFdaObxX8DzWse%2BOkWk2xpXSvT0rq-g%40mail.gmail.com#CAMsr+YGvp2wRx9pPSxaKFdaObxX8DzWse+OkWk2xpXSvT0rq-g@mail.gmail.com
 		return c.stmtBuf.Push(ctx, sql.SendError{Err: fmt.Errorf("CopyFrom not supported in extended protocol mode")})
 	}
+	if _, ok := stmt.AST.(*tree.CopyTo); ok {
+		return c.stmtBuf.Push(ctx, sql.SendError{Err: fmt.Errorf("CopyTo not supported in extended protocol mode")})
+	}
 
 	return c.stmtBuf.Push(
 		ctx,
@@ -0,0 +1,238 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// binaryCopySignature is the header of data in the binary COPY format,
+// followed by the flags field and the length of the header extension area,
+// which are both zero.
+const binaryCopySignature = "PGCOPY\n\377\r\n\000" + "\x00\x00\x00\x00" + "\x00\x00\x00\x00"
+
+// copyOutEncoder implements the Copy-out subprotocol (COPY ... TO STDOUT). The
+// result rows of the statement are sent to the client in CopyData messages,
+// encoded in the text, CSV or binary COPY format, instead of DataRow messages.
+//
+// See: https://www.postgresql.org/docs/current/protocol-flow.html#PROTOCOL-COPY
+type copyOutEncoder struct {
+	opts sql.CopyFormatOptions
+	// started is set once the CopyOutResponse message has been sent. The
+	// CopyDone message is only sent if the Copy-out mode was started.
+	started bool
+	// scratch is used to produce the text representation of values before they
+	// are escaped.
+	scratch *writeBuffer
+}
+
+// evalCopyOption evaluates the expression of an option of a COPY TO
+// statement. The statement is only supported in the simple protocol, so the
+// options can't be placeholders.
+func evalCopyOption(e tree.Expr) (string, error) {
+	if s, ok := e.(*tree.StrVal); ok {
+		return s.RawString(), nil
+	}
+	return "", pgerror.Newf(pgcode.Syntax, "COPY option must be a string literal, got %s", e)
+}
+
+// makeCopyOutFormatOptions validates the options of a COPY TO statement.
+func makeCopyOutFormatOptions(stmt *tree.CopyTo) (sql.CopyFormatOptions, error) {
+	return sql.MakeCopyFormatOptions(&stmt.Options, evalCopyOption)
+}
+
+func (c *conn) newCopyOutEncoder(stmt *tree.CopyTo) *copyOutEncoder {
+	opts, err := makeCopyOutFormatOptions(stmt)
+	if err != nil {
+		// The options have been validated when the statement was received.
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "invalid COPY options"))
+	}
+	return &copyOutEncoder{
+		opts:    opts,
+		scratch: newWriteBuffer(c.metrics.BytesOutCount),
+	}
+}
+
+// bufferCopyOutResponse starts the Copy-out mode. It sends the CopyOutResponse
+// message followed by the header of the data, if the format has one.
+func (c *conn) bufferCopyOutResponse(e *copyOutEncoder, cols colinfo.ResultColumns) {
+	e.started = true
+	fmtCode := pgwirebase.FormatText
+	if e.opts.Format == tree.CopyFormatBinary {
+		fmtCode = pgwirebase.FormatBinary
+	}
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyOutResponse)
+	c.msgBuilder.writeByte(byte(fmtCode))
+	c.msgBuilder.putInt16(int16(len(cols)))
+	for range cols {
+		c.msgBuilder.putInt16(int16(fmtCode))
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+
+	switch {
+	case e.opts.Format == tree.CopyFormatBinary:
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		c.msgBuilder.writeString(binaryCopySignature)
+	case e.opts.Header:
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		for i := range cols {
+			if i > 0 {
+				c.msgBuilder.writeByte(e.opts.Delimiter)
+			}
+			e.writeCSVField(&c.msgBuilder, cols[i].Name, len(cols) == 1)
+		}
+		c.msgBuilder.writeByte('\n')
+	default:
+		return
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// bufferCopyData serializes a row into a CopyData message and adds it to the
+// buffer.
+func (c *conn) bufferCopyData(
+	ctx context.Context,
+	e *copyOutEncoder,
+	row tree.Datums,
+	conv sessiondatapb.DataConversionConfig,
+	sessionLoc *time.Location,
+	types []*types.T,
+) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	if e.opts.Format == tree.CopyFormatBinary {
+		c.msgBuilder.putInt16(int16(len(row)))
+		for i, col := range row {
+			c.msgBuilder.writeBinaryDatum(ctx, col, sessionLoc, types[i])
+		}
+	} else {
+		for i, col := range row {
+			if i > 0 {
+				c.msgBuilder.writeByte(e.opts.Delimiter)
+			}
+			if col == tree.DNull {
+				c.msgBuilder.writeString(e.opts.Null)
+				continue
+			}
+			e.scratch.reset()
+			e.scratch.writeTextDatum(ctx, col, conv, sessionLoc, types[i])
+			if e.scratch.err != nil {
+				c.msgBuilder.setError(e.scratch.err)
+				break
+			}
+			// Skip the length prefix written by writeTextDatum.
+			s := string(e.scratch.wrapped.Bytes()[4:])
+			if e.opts.Format == tree.CopyFormatCSV {
+				e.writeCSVField(&c.msgBuilder, s, len(row) == 1)
+			} else {
+				e.writeTextField(&c.msgBuilder, s)
+			}
+		}
+		c.msgBuilder.writeByte('\n')
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// bufferCopyDone ends the Copy-out mode. It sends the trailer of the data, if
+// the format has one, followed by the CopyDone message.
+func (c *conn) bufferCopyDone(e *copyOutEncoder) {
+	if e.opts.Format == tree.CopyFormatBinary {
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		c.msgBuilder.putInt16(-1)
+		if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+			panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+		}
+	}
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDone)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// writeTextField writes a value in the text COPY format. Backslashes, control
+// characters with a backslash escape sequence and the delimiter are escaped.
+func (e *copyOutEncoder) writeTextField(b *writeBuffer, s string) {
+	start := 0
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		var esc byte
+		switch ch {
+		case '\b':
+			esc = 'b'
+		case '\f':
+			esc = 'f'
+		case '\n':
+			esc = 'n'
+		case '\r':
+			esc = 'r'
+		case '\t':
+			esc = 't'
+		case '\v':
+			esc = 'v'
+		case '\\':
+			esc = '\\'
+		default:
+			if ch != e.opts.Delimiter {
+				continue
+			}
+			esc = ch
+		}
+		b.writeString(s[start:i])
+		b.writeByte('\\')
+		b.writeByte(esc)
+		start = i + 1
+	}
+	b.writeString(s[start:])
+}
+
+// writeCSVField writes a value in the CSV COPY format. The value is quoted if
+// it could otherwise be confused with the NULL string, the end-of-data marker
+// or the delimiters, and quote characters inside quoted values are doubled.
+func (e *copyOutEncoder) writeCSVField(b *writeBuffer, s string, singleField bool) {
+	quote := s == e.opts.Null || (singleField && s == `\.`)
+	for i := 0; i < len(s) && !quote; i++ {
+		switch s[i] {
+		case e.opts.Delimiter, e.opts.Quote, '\n', '\r':
+			quote = true
+		}
+	}
+	if !quote {
+		b.writeString(s)
+		return
+	}
+	b.writeByte(e.opts.Quote)
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == e.opts.Quote {
+			b.writeString(s[start : i+1])
+			b.writeByte(e.opts.Quote)
+			start = i + 1
+		}
+	}
+	b.writeString(s[start:])
+	b.writeByte(e.opts.Quote)
+}
@@ -421,6 +421,10 @@ func DecodeDatum(
 					"could not parse string %q as inet", b)
 			}
 			return d, nil
+		case oid.T_tsvector:
+			return tree.ParseDTSVector(string(b))
+		case oid.T_tsquery:
+			return tree.ParseDTSQuery(string(b))
 		case oid.T__int2, oid.T__int4, oid.T__int8:
 			var arr pgtype.Int8Array
 			if err := arr.DecodeText(nil, b); err != nil {
@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -178,6 +179,12 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DTSVector:
+		b.writeLengthPrefixedString(v.TSVector.String())
+
+	case *tree.DTSQuery:
+		b.writeLengthPrefixedString(v.TSQuery.String())
+
 	case *tree.DTuple:
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
@@ -506,6 +513,23 @@ func (b *writeBuffer) writeBinaryDatum(
 		// Postgres version number, as of writing, `1` is the only valid value.
 		b.writeByte(1)
 		b.writeString(s)
+	case *tree.DTSVector:
+		subWriter := newWriteBuffer(nil /* bytecount */)
+		subWriter.putInt32(int32(len(v.TSVector)))
+		for _, l := range v.TSVector {
+			subWriter.writeTerminatedString(l.Word)
+			subWriter.putInt16(int16(len(l.Positions)))
+			for _, p := range l.Positions {
+				// Each position is sent with its weight in the two high bits.
+				subWriter.putInt16(int16(uint16(p.Weight)<<14 | p.Pos))
+			}
+		}
+		b.writeLengthPrefixedBuffer(&subWriter.wrapped)
+	case *tree.DTSQuery:
+		subWriter := newWriteBuffer(nil /* bytecount */)
+		subWriter.putInt32(int32(v.NumNodes()))
+		writeBinaryTSQueryNode(subWriter, v.Root)
+		b.writeLengthPrefixedBuffer(&subWriter.wrapped)
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
@@ -514,6 +538,44 @@ func (b *writeBuffer) writeBinaryDatum(
 	}
 }
 
+// writeBinaryTSQueryNode writes the binary representation of the given node
+// of a tsquery, and of its children, in the order used by Postgres: each
+// operator is followed by its right operand and then its left operand.
+func writeBinaryTSQueryNode(b *writeBuffer, n *tsearch.QueryNode) {
+	if n == nil {
+		return
+	}
+	const (
+		pgTSQueryValue    = 1
+		pgTSQueryOperator = 2
+	)
+	if n.Op == tsearch.OpLexeme {
+		b.writeByte(pgTSQueryValue)
+		b.writeByte(n.Weights)
+		if n.Prefix {
+			b.writeByte(1)
+		} else {
+			b.writeByte(0)
+		}
+		b.writeTerminatedString(n.Lexeme)
+		return
+	}
+	b.writeByte(pgTSQueryOperator)
+	switch n.Op {
+	case tsearch.OpNot:
+		b.writeByte(1)
+	case tsearch.OpAnd:
+		b.writeByte(2)
+	case tsearch.OpOr:
+		b.writeByte(3)
+	case tsearch.OpPhrase:
+		b.writeByte(4)
+		b.putInt16(int16(n.Distance))
+	}
+	writeBinaryTSQueryNode(b, n.Right)
+	writeBinaryTSQueryNode(b, n.Left)
+}
+
 const (
 	pgTimeFormat              = "15:04:05.999999"
 	pgTimeTZFormat            = pgTimeFormat + "-07:00"
@@ -143,12 +143,14 @@ var _ planNode = &alterTableNode{}
 var _ planNode = &alterTableOwnerNode{}
 var _ planNode = &alterTableSetSchemaNode{}
 var _ planNode = &alterTypeNode{}
+var _ planNode = &alterDomainNode{}
 var _ planNode = &bufferNode{}
 var _ planNode = &cancelQueriesNode{}
 var _ planNode = &cancelSessionsNode{}
 var _ planNode = &changePrivilegesNode{}
 var _ planNode = &createDatabaseNode{}
 var _ planNode = &createIndexNode{}
+var _ planNode = &createPolicyNode{}
 var _ planNode = &createSequenceNode{}
 var _ planNode = &createStatsNode{}
 var _ planNode = &createTableNode{}
@@ -156,11 +158,14 @@ var _ planNode = &createTypeNode{}
 var _ planNode = &CreateRoleNode{}
 var _ planNode = &createViewNode{}
 var _ planNode = &delayedNode{}
+var _ planNode = &declareCursorNode{}
 var _ planNode = &deleteNode{}
 var _ planNode = &deleteRangeNode{}
 var _ planNode = &distinctNode{}
 var _ planNode = &dropDatabaseNode{}
 var _ planNode = &dropIndexNode{}
+var _ planNode = &dropPlanBaselineNode{}
+var _ planNode = &dropPolicyNode{}
 var _ planNode = &dropSchemaNode{}
 var _ planNode = &dropSequenceNode{}
 var _ planNode = &dropTableNode{}
@@ -169,6 +174,7 @@ var _ planNode = &DropRoleNode{}
 var _ planNode = &dropViewNode{}
 var _ planNode = &errorIfRowsNode{}
 var _ planNode = &explainVecNode{}
+var _ planNode = &fetchNode{}
 var _ planNode = &filterNode{}
 var _ planNode = &GrantRoleNode{}
 var _ planNode = &groupNode{}
@@ -180,6 +186,7 @@ var _ planNode = &joinNode{}
 var _ planNode = &limitNode{}
 var _ planNode = &max1RowNode{}
 var _ planNode = &ordinalityNode{}
+var _ planNode = &pinPlanNode{}
 var _ planNode = &projectSetNode{}
 var _ planNode = &reassignOwnedByNode{}
 var _ planNode = &refreshMaterializedViewNode{}
@@ -221,18 +228,22 @@ var _ planNodeFastPath = &setZoneConfigNode{}
 var _ planNodeFastPath = &controlJobsNode{}
 var _ planNodeFastPath = &controlSchedulesNode{}
 
+var _ planNodeReadingOwnWrites = &alterDefaultPrivilegesNode{}
 var _ planNodeReadingOwnWrites = &alterIndexNode{}
 var _ planNodeReadingOwnWrites = &alterSchemaNode{}
 var _ planNodeReadingOwnWrites = &alterSequenceNode{}
 var _ planNodeReadingOwnWrites = &alterTableNode{}
 var _ planNodeReadingOwnWrites = &alterTypeNode{}
+var _ planNodeReadingOwnWrites = &alterDomainNode{}
 var _ planNodeReadingOwnWrites = &createIndexNode{}
+var _ planNodeReadingOwnWrites = &createPolicyNode{}
 var _ planNodeReadingOwnWrites = &createSequenceNode{}
 var _ planNodeReadingOwnWrites = &createDatabaseNode{}
 var _ planNodeReadingOwnWrites = &createTableNode{}
 var _ planNodeReadingOwnWrites = &createTypeNode{}
 var _ planNodeReadingOwnWrites = &createViewNode{}
 var _ planNodeReadingOwnWrites = &changePrivilegesNode{}
+var _ planNodeReadingOwnWrites = &dropPolicyNode{}
 var _ planNodeReadingOwnWrites = &dropSchemaNode{}
 var _ planNodeReadingOwnWrites = &dropTypeNode{}
 var _ planNodeReadingOwnWrites = &refreshMaterializedViewNode{}
@@ -300,6 +311,10 @@ type planTop struct {
 	// flags is populated during planning and execution.
 	flags planFlags
 
+	// statementHints is set if the plan was built with the optimizer hints in
+	// the statement's comments or in system.statement_hints.
+	statementHints *tree.StatementHints
+
 	// avoidBuffering, when set, causes the execution to avoid buffering
 	// results.
 	avoidBuffering bool
@@ -423,6 +438,10 @@ type cascadeMetadata struct {
 // return an error (for example, foreign key violation).
 type checkPlan struct {
 	plan planMaybePhysical
+
+	// deferrable is set if the check verifies a DEFERRABLE constraint; in that
+	// case the check may be postponed until the transaction commits.
+	deferrable *exec.DeferrableConstraint
 }
 
 // close calls Close on all plan trees.
@@ -580,6 +599,10 @@ const (
 	// planFlagContainsFullIndexScan is set if the plan involves an unconstrained
 	// secondary index scan.
 	planFlagContainsFullIndexScan
+
+	// planFlagUsedPlanBaseline is set if the plan was built according to the
+	// plan baseline pinned for the statement's fingerprint.
+	planFlagUsedPlanBaseline
 )
 
 func (pf planFlags) IsSet(flag planFlags) bool {
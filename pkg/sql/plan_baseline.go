@@ -0,0 +1,127 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/planbaselines/planbaselinespb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type pinPlanNode struct {
+	fingerprint string
+	hints       planbaselinespb.PlanHints
+	plan        string
+}
+
+// PinPlan captures the plan that the optimizer currently chooses for a
+// statement and stores it as the plan baseline for the statement's
+// fingerprint. Subsequent executions of statements with the same fingerprint
+// use that plan for as long as it remains valid.
+// Privileges: admin.
+func (p *planner) PinPlan(ctx context.Context, n *tree.PinPlan) (planNode, error) {
+	if err := p.checkPlanBaselinesSupported(ctx, "PIN PLAN"); err != nil {
+		return nil, err
+	}
+	if err := checkPinnableStmt(n.Statement); err != nil {
+		return nil, err
+	}
+
+	var o xform.Optimizer
+	o.Init(p.EvalContext(), &p.optPlanningCtx.catalog)
+	f := o.Factory()
+	f.FoldingControl().AllowStableFolds()
+	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &p.optPlanningCtx.catalog, f, n.Statement)
+	if err := bld.Build(); err != nil {
+		return nil, err
+	}
+	if _, err := o.Optimize(); err != nil {
+		return nil, err
+	}
+	hints := xform.CapturePlanHints(f.Memo())
+	return &pinPlanNode{
+		fingerprint: anonymizeStmt(n.Statement),
+		hints:       hints,
+		plan:        xform.FormatPlanHints(f.Metadata(), &hints),
+	}, nil
+}
+
+func (n *pinPlanNode) startExec(params runParams) error {
+	return params.ExecCfg().PlanBaselines.Pin(params.ctx, n.fingerprint, n.hints, n.plan)
+}
+
+func (*pinPlanNode) Next(runParams) (bool, error) { return false, nil }
+func (*pinPlanNode) Values() tree.Datums          { return nil }
+func (*pinPlanNode) Close(context.Context)        {}
+
+type dropPlanBaselineNode struct {
+	fingerprint string
+}
+
+// DropPlanBaseline removes the plan baseline of a statement fingerprint.
+// Privileges: admin.
+func (p *planner) DropPlanBaseline(
+	ctx context.Context, n *tree.DropPlanBaseline,
+) (planNode, error) {
+	if err := p.checkPlanBaselinesSupported(ctx, "DROP PLAN BASELINE"); err != nil {
+		return nil, err
+	}
+	fingerprint := n.Fingerprint
+	if n.Statement != nil {
+		fingerprint = anonymizeStmt(n.Statement)
+	}
+	return &dropPlanBaselineNode{fingerprint: fingerprint}, nil
+}
+
+func (n *dropPlanBaselineNode) startExec(params runParams) error {
+	found, err := params.ExecCfg().PlanBaselines.Drop(params.ctx, n.fingerprint)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"no plan baseline for statement fingerprint %q", n.fingerprint)
+	}
+	return nil
+}
+
+func (*dropPlanBaselineNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropPlanBaselineNode) Values() tree.Datums          { return nil }
+func (*dropPlanBaselineNode) Close(context.Context)        {}
+
+// checkPlanBaselinesSupported returns an error if the cluster or the current
+// user cannot manage plan baselines.
+func (p *planner) checkPlanBaselinesSupported(ctx context.Context, action string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.PlanBaselines) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"not all nodes are the correct version for %s", action)
+	}
+	return p.RequireAdminRole(ctx, action)
+}
+
+// checkPinnableStmt returns an error if the optimizer does not plan the given
+// statement in a way that a plan baseline can describe. These are the same
+// statements whose memos can be cached (see optPlanningCtx.reset).
+func checkPinnableStmt(stmt tree.Statement) error {
+	switch stmt.(type) {
+	case *tree.ParenSelect, *tree.Select, *tree.SelectClause, *tree.UnionClause, *tree.ValuesClause,
+		*tree.Insert, *tree.Update, *tree.Delete:
+		return nil
+	}
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"cannot pin the plan of %s statements", stmt.StatementTag())
+}
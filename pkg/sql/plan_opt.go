@@ -27,7 +27,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/planbaselines/planbaselinespb"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -68,7 +70,7 @@ func (p *planner) prepareUsingOptimizer(ctx context.Context) (planFlags, error)
 		*tree.ReleaseSavepoint, *tree.RenameColumn, *tree.RenameDatabase,
 		*tree.RenameIndex, *tree.RenameTable, *tree.Revoke, *tree.RevokeRole,
 		*tree.RollbackToSavepoint, *tree.RollbackTransaction,
-		*tree.Savepoint, *tree.SetTransaction, *tree.SetTracing, *tree.SetSessionAuthorizationDefault,
+		*tree.Savepoint, *tree.SetTransaction, *tree.SetConstraints, *tree.SetTracing, *tree.SetSessionAuthorizationDefault,
 		*tree.SetSessionCharacteristics:
 		// These statements do not have result columns and do not support placeholders
 		// so there is no need to do anything during prepare.
@@ -288,6 +290,10 @@ type optPlanningCtx struct {
 	useCache bool
 
 	flags planFlags
+
+	// hints is set if the statement was planned with the optimizer hints in its
+	// comments or in system.statement_hints.
+	hints *tree.StatementHints
 }
 
 // init performs one-time initialization of the planning context; reset() must
@@ -303,6 +309,7 @@ func (opc *optPlanningCtx) reset() {
 	opc.catalog.reset()
 	opc.optimizer.Init(p.EvalContext(), &opc.catalog)
 	opc.flags = 0
+	opc.hints = nil
 
 	// We only allow memo caching for SELECT/INSERT/UPDATE/DELETE. We could
 	// support it for all statements in principle, but it would increase the
@@ -450,6 +457,23 @@ func (opc *optPlanningCtx) reuseMemo(cachedMemo *memo.Memo) (*memo.Memo, error)
 func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ error) {
 	prepared := opc.p.stmt.Prepared
 	p := opc.p
+	if hints := opc.lookupPlanBaseline(); hints != nil {
+		// A plan is pinned for this statement. Memos from the query cache or
+		// from preparation were optimized without the baseline, so always build
+		// the memo from scratch.
+		memo, ok, err := opc.buildPinnedMemo(ctx, hints)
+		if err != nil || ok {
+			return memo, err
+		}
+		// The baseline can't be honored; plan the statement normally.
+		opc.optimizer.Init(p.EvalContext(), &opc.catalog)
+	}
+	if hints := opc.lookupStatementHints(ctx); hints != nil {
+		// Memos from the query cache or from preparation were built without the
+		// hints, so always build the memo from scratch.
+		return opc.buildHintedMemo(ctx, hints)
+	}
+
 	if opc.allowMemoReuse && prepared != nil && prepared.Memo != nil {
 		// We are executing a previously prepared statement and a reusable memo is
 		// available.
@@ -533,6 +557,129 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 	return f.Memo(), nil
 }
 
+// lookupPlanBaseline returns the plan pinned for the fingerprint of the
+// current statement, or nil if there is none. EXPLAIN statements use the
+// baseline of the statement they explain.
+func (opc *optPlanningCtx) lookupPlanBaseline() *planbaselinespb.PlanHints {
+	p := opc.p
+	registry := p.execCfg.PlanBaselines
+	if registry == nil || !registry.HasBaselines() {
+		return nil
+	}
+	stmt := p.stmt.AST
+	if e, ok := stmt.(*tree.Explain); ok {
+		stmt = e.Statement
+	}
+	if checkPinnableStmt(stmt) != nil {
+		return nil
+	}
+	hints, ok := registry.Lookup(anonymizeStmt(stmt))
+	if !ok {
+		return nil
+	}
+	return hints
+}
+
+// buildPinnedMemo builds and optimizes the current statement so that it uses
+// the plan described by the given hints. It returns ok=false if the hints are
+// no longer valid (for example, because an index was dropped) or if the
+// optimizer could not honor them, in which case the caller should plan the
+// statement without the baseline. The returned memo is never added to the
+// query cache.
+func (opc *optPlanningCtx) buildPinnedMemo(
+	ctx context.Context, hints *planbaselinespb.PlanHints,
+) (_ *memo.Memo, ok bool, _ error) {
+	p := opc.p
+	f := opc.optimizer.Factory()
+	f.FoldingControl().AllowStableFolds()
+	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &opc.catalog, f, p.stmt.AST)
+	if err := bld.Build(); err != nil {
+		return nil, false, err
+	}
+	if err := xform.ValidatePlanHints(f.Metadata(), hints); err != nil {
+		log.VEventf(ctx, 1, "ignoring plan baseline: %v", err)
+		return nil, false, nil
+	}
+	opc.optimizer.SetPlanHints(hints)
+	if _, err := opc.optimizer.Optimize(); err != nil {
+		return nil, false, err
+	}
+	if chosen := xform.CapturePlanHints(f.Memo()); !xform.PlanHintsEqual(&chosen, hints) {
+		opc.log(ctx, "plan baseline could not be honored")
+		return nil, false, nil
+	}
+	opc.log(ctx, "using plan baseline")
+	opc.flags.Set(planFlagUsedPlanBaseline)
+	return f.Memo(), true, nil
+}
+
+// lookupStatementHints returns the optimizer hints of the current statement,
+// combining the hints in its comments with the hints stored for its
+// fingerprint in system.statement_hints; the former take precedence. EXPLAIN
+// statements use the stored hints of the statement they explain. It returns
+// nil if there are no hints or if they are disabled for the session. Invalid
+// hints in the comments are ignored with a notice.
+func (opc *optPlanningCtx) lookupStatementHints(ctx context.Context) *tree.StatementHints {
+	p := opc.p
+	if !p.SessionData().StatementHintsEnabled {
+		return nil
+	}
+	if err := p.stmt.HintsErr; err != nil {
+		p.BufferClientNotice(ctx, pgnotice.Newf("ignoring optimizer hints: %v", err))
+	}
+	var stored *tree.StatementHints
+	if registry := p.execCfg.StatementHints; registry != nil && registry.HasHints() {
+		stmt := p.stmt.AST
+		switch e := stmt.(type) {
+		case *tree.Explain:
+			stmt = e.Statement
+		case *tree.ExplainAnalyze:
+			stmt = e.Statement
+		}
+		switch stmt.(type) {
+		case *tree.ParenSelect, *tree.Select, *tree.SelectClause, *tree.UnionClause,
+			*tree.ValuesClause, *tree.Insert, *tree.Update, *tree.Delete:
+			stored, _ = registry.Lookup(anonymizeStmt(stmt))
+		}
+	}
+	if stored == nil {
+		return p.stmt.Hints
+	}
+	if p.stmt.Hints == nil {
+		return stored
+	}
+	hints := &tree.StatementHints{}
+	hints.Append(p.stmt.Hints)
+	hints.Append(stored)
+	return hints
+}
+
+// buildHintedMemo builds and optimizes the current statement according to the
+// given optimizer hints. The returned memo is never added to the query cache.
+func (opc *optPlanningCtx) buildHintedMemo(
+	ctx context.Context, hints *tree.StatementHints,
+) (*memo.Memo, error) {
+	p := opc.p
+	if err := opc.optimizer.SetStatementHints(hints); err != nil {
+		return nil, err
+	}
+	f := opc.optimizer.Factory()
+	f.FoldingControl().AllowStableFolds()
+	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &opc.catalog, f, p.stmt.AST)
+	bld.Hints = hints
+	if err := bld.Build(); err != nil {
+		return nil, err
+	}
+	if _, isCanned := p.stmt.AST.(*tree.CannedOptPlan); !isCanned {
+		if _, err := opc.optimizer.Optimize(); err != nil {
+			return nil, err
+		}
+	}
+	opc.log(ctx, "using statement hints")
+	opc.hints = hints
+	return f.Memo(), nil
+}
+
 // runExecBuilder execbuilds a plan using the given factory and stores the
 // result in planTop. If required, also captures explain data using the explain
 // factory.
@@ -586,6 +733,7 @@ func (opc *optPlanningCtx) runExecBuilder(
 	planTop.planComponents = *result
 	planTop.stmt = stmt
 	planTop.flags = opc.flags
+	planTop.statementHints = opc.hints
 	if isDDL {
 		planTop.flags.Set(planFlagIsDDL)
 	}
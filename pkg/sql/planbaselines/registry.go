@@ -0,0 +1,208 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planbaselines
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/planbaselines/planbaselinespb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Enabled controls whether the optimizer honors the plans pinned in
+// system.plan_baselines.
+var Enabled = settings.RegisterBoolSetting(
+	"sql.plan_baselines.enabled",
+	"if set, statements are planned with the plan pinned for their fingerprint, if there is one",
+	true,
+).WithPublic()
+
+var pollingInterval = settings.RegisterDurationSetting(
+	"sql.plan_baselines.poll_interval",
+	"rate at which the planbaselines.Registry polls system.plan_baselines, set to zero to disable",
+	10*time.Second,
+)
+
+// Registry maintains a view on the plans pinned for statement fingerprints
+// (i.e. system.plan_baselines) and provides utilities to look them up during
+// planning, pin new ones and drop existing ones.
+//
+// Baselines pinned or dropped through the registry are visible on the local
+// node right away; other nodes pick them up the next time they poll the
+// table.
+type Registry struct {
+	mu struct {
+		// NOTE: This lock can't be held while the registry runs any statements
+		// internally; it'd deadlock.
+		syncutil.Mutex
+		// baselines maps statement fingerprints to their pinned plans.
+		baselines map[string]*planbaselinespb.PlanHints
+
+		// epoch is observed before reading system.plan_baselines, and then
+		// checked again before loading the table's contents. If the value changed
+		// in between, then the table contents might be stale.
+		epoch int
+	}
+	st *cluster.Settings
+	ie sqlutil.InternalExecutor
+}
+
+// NewRegistry constructs a new Registry.
+func NewRegistry(ie sqlutil.InternalExecutor, st *cluster.Settings) *Registry {
+	return &Registry{ie: ie, st: st}
+}
+
+// Start will start the polling loop for the Registry.
+func (r *Registry) Start(ctx context.Context, stopper *stop.Stopper) {
+	sqlutil.StartPoller(
+		ctx, stopper, "plan-baselines-poll", &r.st.SV, pollingInterval, "plan baselines", r.pollBaselines,
+	)
+}
+
+// HasBaselines returns true if plan baselines are enabled and there is at
+// least one pinned plan. It allows callers to avoid fingerprinting statements
+// when there is nothing to look up.
+func (r *Registry) HasBaselines() bool {
+	if !Enabled.Get(&r.st.SV) {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.mu.baselines) > 0
+}
+
+// Lookup returns the plan pinned for the given statement fingerprint, if there
+// is one and plan baselines are enabled. The returned hints must not be
+// modified.
+func (r *Registry) Lookup(fingerprint string) (_ *planbaselinespb.PlanHints, ok bool) {
+	if !Enabled.Get(&r.st.SV) {
+		return nil, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hints, ok := r.mu.baselines[fingerprint]
+	return hints, ok
+}
+
+// Pin stores the given plan as the baseline for the statement fingerprint,
+// replacing any existing baseline. plan is a human-readable description of
+// the hints, displayed by SHOW PLAN BASELINES.
+func (r *Registry) Pin(
+	ctx context.Context, fingerprint string, hints planbaselinespb.PlanHints, plan string,
+) error {
+	encoded, err := protoutil.Marshal(&hints)
+	if err != nil {
+		return err
+	}
+	if _, err := r.ie.ExecEx(ctx, "plan-baselines-pin", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		"UPSERT INTO system.plan_baselines (fingerprint, hints, plan, created) VALUES ($1, $2, $3, $4)",
+		fingerprint, tree.NewDBytes(tree.DBytes(encoded)), plan, timeutil.Now(),
+	); err != nil {
+		return err
+	}
+
+	// Manually update the (local) registry, so that the baseline is used on
+	// this node without waiting for the poller.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.epoch++
+	if r.mu.baselines == nil {
+		r.mu.baselines = make(map[string]*planbaselinespb.PlanHints)
+	}
+	r.mu.baselines[fingerprint] = &hints
+	return nil
+}
+
+// Drop removes the baseline for the given statement fingerprint. It returns
+// false if there was no such baseline.
+func (r *Registry) Drop(ctx context.Context, fingerprint string) (bool, error) {
+	n, err := r.ie.ExecEx(ctx, "plan-baselines-drop", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		"DELETE FROM system.plan_baselines WHERE fingerprint = $1",
+		fingerprint,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.epoch++
+	delete(r.mu.baselines, fingerprint)
+	return n > 0, nil
+}
+
+// pollBaselines reads the rows of system.plan_baselines and replaces
+// r.mu.baselines accordingly.
+func (r *Registry) pollBaselines(ctx context.Context) error {
+	if !r.st.Version.IsActive(ctx, clusterversion.PlanBaselines) {
+		// The table may not exist yet.
+		return nil
+	}
+	var rows []tree.Datums
+	// Loop until we run the query without straddling an epoch increment.
+	for {
+		r.mu.Lock()
+		epoch := r.mu.epoch
+		r.mu.Unlock()
+
+		it, err := r.ie.QueryIteratorEx(ctx, "plan-baselines-poll", nil, /* txn */
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			"SELECT fingerprint, hints FROM system.plan_baselines")
+		if err != nil {
+			return err
+		}
+		rows = rows[:0]
+		var ok bool
+		for ok, err = it.Next(ctx); ok; ok, err = it.Next(ctx) {
+			rows = append(rows, it.Cur())
+		}
+		if err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		// If the epoch changed it means that a baseline was pinned or dropped
+		// manually while the query was running. In that case, if we were to
+		// process the query results normally, we might undo that change.
+		if r.mu.epoch != epoch {
+			r.mu.Unlock()
+			continue
+		}
+		break
+	}
+	defer r.mu.Unlock()
+
+	baselines := make(map[string]*planbaselinespb.PlanHints, len(rows))
+	for _, row := range rows {
+		fingerprint := string(tree.MustBeDString(row[0]))
+		hints := &planbaselinespb.PlanHints{}
+		if err := protoutil.Unmarshal([]byte(tree.MustBeDBytes(row[1])), hints); err != nil {
+			return errors.Wrapf(err, "decoding plan baseline for %q", fingerprint)
+		}
+		baselines[fingerprint] = hints
+	}
+	r.mu.baselines = baselines
+	return nil
+}
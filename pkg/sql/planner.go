@@ -99,6 +99,10 @@ type extendedEvalContext struct {
 	sqlStatsCollector *sqlStatsCollector
 
 	SchemaChangerState *SchemaChangerState
+
+	// DeferredConstraints refers to deferredConstraints in extraTxnState. It is
+	// nil for internal planners, which never defer constraint checks.
+	DeferredConstraints *DeferredConstraintState
 }
 
 // copy returns a deep copy of ctx.
@@ -166,6 +170,9 @@ type planner struct {
 
 	preparedStatements preparedStatementsAccessor
 
+	// sqlCursors is used to access the SQL cursors declared in the session.
+	sqlCursors sqlCursors
+
 	// avoidCachedDescriptors, when true, instructs all code that
 	// accesses table/view descriptors to force reading the descriptors
 	// within the transaction. This is necessary to read descriptors
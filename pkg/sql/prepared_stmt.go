@@ -15,10 +15,13 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 )
@@ -137,6 +140,42 @@ type PreparedPortal struct {
 	// meaning that any additional attempts to execute it should return no
 	// rows.
 	exhausted bool
+
+	// pauseInfo is set when the portal was executed with a row limit and
+	// suspended before being exhausted. It holds the paused execution of the
+	// portal's query, which is resumed when the portal is executed again. It is
+	// a pointer so that all copies of the portal share it.
+	pauseInfo *portalPauseInfo
+}
+
+// portalPauseInfo holds the state of a suspended portal.
+type portalPauseInfo struct {
+	// ctx is the context the portal's query runs in. It outlives the individual
+	// executions of the portal, and is canceled by cancel when the query is
+	// canceled or the portal is closed.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// rows iterates over the results of the portal's query, whose execution is
+	// paused in between executions of the portal.
+	rows sqlutil.InternalRows
+	// txn is the transaction in which the query runs.
+	txn *kv.Txn
+	// readSeqNum is the sequence number at which the query reads. It is set
+	// on the transaction whenever the portal is resumed, so that the portal
+	// does not observe the writes made by the transaction after it was first
+	// executed.
+	readSeqNum enginepb.TxnSeq
+}
+
+// close releases the paused execution of the portal, if any. It is idempotent.
+func (p *portalPauseInfo) close() error {
+	var err error
+	if p.rows != nil {
+		err = p.rows.Close()
+		p.rows = nil
+	}
+	p.cancel()
+	return err
 }
 
 // makePreparedPortal creates a new PreparedPortal.
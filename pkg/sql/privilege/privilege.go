@@ -12,6 +12,7 @@ package privilege
 
 import (
 	"bytes"
+	"fmt"
 	"sort"
 	"strings"
 
@@ -60,6 +61,53 @@ const (
 	Type ObjectType = "type"
 )
 
+// TargetObjectType represents the kinds of objects for which default
+// privileges can be defined with ALTER DEFAULT PRIVILEGES.
+type TargetObjectType uint32
+
+// TargetObjectType values. These are stored in descriptors, so new values
+// must be added to the end.
+const (
+	_ TargetObjectType = iota
+	// Tables represents tables and views.
+	Tables
+	// Sequences represents sequences.
+	Sequences
+	// Types represents user-defined types.
+	Types
+	// Schemas represents user-defined schemas.
+	Schemas
+)
+
+var targetObjectTypeName = [...]string{
+	Tables:    "TABLES",
+	Sequences: "SEQUENCES",
+	Types:     "TYPES",
+	Schemas:   "SCHEMAS",
+}
+
+func (t TargetObjectType) String() string {
+	if t == 0 || int(t) >= len(targetObjectTypeName) {
+		return fmt.Sprintf("TargetObjectType(%d)", t)
+	}
+	return targetObjectTypeName[t]
+}
+
+// ObjectType returns the type of the objects represented by t, which
+// determines the privileges that can be granted on them.
+func (t TargetObjectType) ObjectType() ObjectType {
+	switch t {
+	case Tables, Sequences:
+		return Table
+	case Types:
+		return Type
+	case Schemas:
+		return Schema
+	default:
+		panic(errors.AssertionFailedf("unknown target object type %d", t))
+	}
+}
+
 // Predefined sets of privileges.
 var (
 	AllPrivileges    = List{ALL, CONNECT, CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, USAGE, ZONECONFIG}
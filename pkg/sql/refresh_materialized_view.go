@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
 )
 
 type refreshMaterializedViewNode struct {
@@ -40,6 +41,13 @@ func (p *planner) RefreshMaterializedView(
 	if !desc.MaterializedView() {
 		return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a materialized view", desc.Name)
 	}
+	if desc.IncrementalRefresh != nil {
+		return nil, errors.WithHint(
+			pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"materialized view %q is refreshed incrementally", desc.Name),
+			"views created with auto_refresh = incremental are kept up to date automatically",
+		)
+	}
 	// TODO (rohany): Not sure if this is a real restriction, but let's start with
 	//  it to be safe.
 	for i := range desc.Mutations {
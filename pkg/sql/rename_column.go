@@ -154,6 +154,23 @@ func (p *planner) renameColumn(
 		}
 	}
 
+	// Rename the column in row-level security policies.
+	for i := range tableDesc.Policies {
+		policy := &tableDesc.Policies[i]
+		if policy.UsingExpr != "" {
+			policy.UsingExpr, err = schemaexpr.RenameColumn(policy.UsingExpr, *oldName, *newName)
+			if err != nil {
+				return false, err
+			}
+		}
+		if policy.WithCheckExpr != "" {
+			policy.WithCheckExpr, err = schemaexpr.RenameColumn(policy.WithCheckExpr, *oldName, *newName)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
 	// Rename the column in partial index predicates.
 	for _, index := range tableDesc.PublicNonPrimaryIndexes() {
 		if index.IsPartial() {
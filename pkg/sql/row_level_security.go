@@ -0,0 +1,268 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+// policyCommands maps the commands of CREATE POLICY to the commands stored in
+// the table descriptor.
+var policyCommands = map[tree.PolicyCommand]descpb.TableDescriptor_Policy_Command{
+	tree.PolicyAll:    descpb.TableDescriptor_Policy_ALL,
+	tree.PolicySelect: descpb.TableDescriptor_Policy_SELECT,
+	tree.PolicyInsert: descpb.TableDescriptor_Policy_INSERT,
+	tree.PolicyUpdate: descpb.TableDescriptor_Policy_UPDATE,
+	tree.PolicyDelete: descpb.TableDescriptor_Policy_DELETE,
+}
+
+type createPolicyNode struct {
+	n      *tree.CreatePolicy
+	tn     tree.TableName
+	desc   *tabledesc.Mutable
+	policy descpb.TableDescriptor_Policy
+}
+
+// CreatePolicy adds a row-level security policy to a table.
+func (p *planner) CreatePolicy(ctx context.Context, n *tree.CreatePolicy) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE POLICY",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.RowLevelSecurity) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"not all nodes are the correct version for row-level security")
+	}
+
+	tn := n.Table.ToTableName()
+	desc, err := p.ResolveMutableTableDescriptor(ctx, &tn, true /* required */, tree.ResolveRequireTableDesc)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkCanAlterRowLevelSecurity(ctx, desc); err != nil {
+		return nil, err
+	}
+
+	for i := range desc.Policies {
+		if desc.Policies[i].Name == string(n.Name) {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"policy %q for table %q already exists", n.Name, desc.Name)
+		}
+	}
+
+	policy := descpb.TableDescriptor_Policy{
+		Name:    string(n.Name),
+		Command: policyCommands[n.Command],
+	}
+	switch n.Command {
+	case tree.PolicyInsert:
+		if n.Using != nil {
+			return nil, pgerror.New(pgcode.Syntax,
+				"only WITH CHECK expression allowed for INSERT")
+		}
+	case tree.PolicySelect, tree.PolicyDelete:
+		if n.WithCheck != nil {
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"WITH CHECK cannot be applied to %s", n.Command)
+		}
+	}
+	if n.Using != nil {
+		policy.UsingExpr, _, err = schemaexpr.DequalifyAndValidateExpr(
+			ctx, desc, n.Using, types.Bool, "USING", &p.semaCtx, tree.VolatilityStable, &tn,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if n.WithCheck != nil {
+		policy.WithCheckExpr, _, err = schemaexpr.DequalifyAndValidateExpr(
+			ctx, desc, n.WithCheck, types.Bool, "WITH CHECK", &p.semaCtx, tree.VolatilityStable, &tn,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	roles := n.Roles
+	if len(roles) == 0 {
+		roles = []security.SQLUsername{security.PublicRoleName()}
+	}
+	for _, role := range roles {
+		policy.Roles = append(policy.Roles, role.EncodeProto())
+	}
+
+	return &createPolicyNode{n: n, tn: tn, desc: desc, policy: policy}, nil
+}
+
+func (n *createPolicyNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("policy"))
+
+	// Check that the roles exist.
+	users, err := params.p.GetAllRoles(params.ctx)
+	if err != nil {
+		return err
+	}
+	users[security.PublicRoleName()] = true // isRole
+	for _, role := range n.policy.Roles {
+		if _, ok := users[role.Decode()]; !ok {
+			return pgerror.Newf(pgcode.UndefinedObject, "role %s does not exist", role.Decode())
+		}
+	}
+
+	n.desc.Policies = append(n.desc.Policies, n.policy)
+	return params.p.writeRowLevelSecurityChange(params.ctx, n.desc, &n.tn, n.n)
+}
+
+func (n *createPolicyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createPolicyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createPolicyNode) Close(context.Context)        {}
+func (n *createPolicyNode) ReadingOwnWrites()            {}
+
+type dropPolicyNode struct {
+	n    *tree.DropPolicy
+	tn   tree.TableName
+	desc *tabledesc.Mutable
+}
+
+// DropPolicy removes a row-level security policy from a table.
+func (p *planner) DropPolicy(ctx context.Context, n *tree.DropPolicy) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP POLICY",
+	); err != nil {
+		return nil, err
+	}
+
+	tn := n.Table.ToTableName()
+	desc, err := p.ResolveMutableTableDescriptor(ctx, &tn, true /* required */, tree.ResolveRequireTableDesc)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkCanAlterRowLevelSecurity(ctx, desc); err != nil {
+		return nil, err
+	}
+	return &dropPolicyNode{n: n, tn: tn, desc: desc}, nil
+}
+
+func (n *dropPolicyNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("policy"))
+
+	policies := n.desc.Policies
+	for i := range policies {
+		if policies[i].Name == string(n.n.Name) {
+			n.desc.Policies = append(policies[:i:i], policies[i+1:]...)
+			return params.p.writeRowLevelSecurityChange(params.ctx, n.desc, &n.tn, n.n)
+		}
+	}
+	if n.n.IfExists {
+		params.p.BufferClientNotice(
+			params.ctx,
+			pgnotice.Newf("policy %q for table %q does not exist, skipping", n.n.Name, n.desc.Name),
+		)
+		return nil
+	}
+	return pgerror.Newf(pgcode.UndefinedObject,
+		"policy %q for table %q does not exist", n.n.Name, n.desc.Name)
+}
+
+func (n *dropPolicyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropPolicyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropPolicyNode) Close(context.Context)        {}
+func (n *dropPolicyNode) ReadingOwnWrites()            {}
+
+// checkCanAlterRowLevelSecurity returns an error if the current user is
+// neither an owner of the table nor an admin. Unlike other ALTER TABLE
+// commands, the CREATE privilege is not sufficient: a user who can change the
+// policies of a table could otherwise grant themselves access to every row.
+func (p *planner) checkCanAlterRowLevelSecurity(
+	ctx context.Context, desc *tabledesc.Mutable,
+) error {
+	hasOwnership, err := p.HasOwnership(ctx, desc)
+	if err != nil {
+		return err
+	}
+	if hasOwnership {
+		return nil
+	}
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if !hasAdmin {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of table %s", tree.Name(desc.GetName()))
+	}
+	return nil
+}
+
+// writeRowLevelSecurityChange writes a change to the policies of a table and
+// logs an event for it.
+func (p *planner) writeRowLevelSecurityChange(
+	ctx context.Context, desc *tabledesc.Mutable, tn *tree.TableName, stmt tree.Statement,
+) error {
+	if err := p.writeSchemaChange(
+		ctx, desc, descpb.InvalidMutationID, tree.AsStringWithFQNames(stmt, p.Ann()),
+	); err != nil {
+		return err
+	}
+	return p.logEvent(ctx,
+		desc.ID,
+		&eventpb.AlterTable{
+			TableName: tn.FQString(),
+		})
+}
+
+// checkColumnNotUsedByPolicies returns an error if the given column is
+// referenced by the expressions of a row-level security policy of the table.
+func checkColumnNotUsedByPolicies(desc *tabledesc.Mutable, col catalog.Column) error {
+	for i := range desc.Policies {
+		policy := &desc.Policies[i]
+		for _, exprStr := range []string{policy.UsingExpr, policy.WithCheckExpr} {
+			if exprStr == "" {
+				continue
+			}
+			expr, err := parser.ParseExpr(exprStr)
+			if err != nil {
+				return err
+			}
+			colIDs, err := schemaexpr.ExtractColumnIDs(desc, expr)
+			if err != nil {
+				return err
+			}
+			if colIDs.Contains(col.GetID()) {
+				return pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"cannot drop column %s because policy %s on table %s depends on it",
+					tree.Name(col.GetName()), tree.Name(policy.Name), tree.Name(desc.Name))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+// defaultRowLevelTTLJobCron is the schedule of the row-level TTL job of tables
+// which do not set the ttl_job_cron storage parameter.
+const defaultRowLevelTTLJobCron = "@hourly"
+
+// rowLevelTTLDefaultColDef returns the definition of the hidden column holding
+// the expiration time of the rows of a table created with the ttl_expire_after
+// storage parameter.
+func rowLevelTTLDefaultColDef(expireAfter string) (*tree.ColumnTableDef, error) {
+	defaultExpr, err := parser.ParseExpr(
+		fmt.Sprintf("current_timestamp() + %s::INTERVAL", lex.EscapeSQLString(expireAfter)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	c := &tree.ColumnTableDef{
+		Name:   tabledesc.RowLevelTTLExpirationColumnName,
+		Type:   types.TimestampTZ,
+		Hidden: true,
+	}
+	c.Nullable.Nullability = tree.NotNull
+	c.DefaultExpr.Expr = defaultExpr
+	return c, nil
+}
+
+// resolveRowLevelTTLExpirationColumn sets the expiration column of the
+// row-level TTL configuration of the table, which must have its column IDs
+// allocated. colName is the column given by the ttl_expiration_column storage
+// parameter, if any; otherwise the hidden column added for ttl_expire_after is
+// used.
+func resolveRowLevelTTLExpirationColumn(desc *tabledesc.Mutable, colName tree.Name) error {
+	if colName == "" {
+		colName = tabledesc.RowLevelTTLExpirationColumnName
+	}
+	col, err := desc.FindColumnWithName(colName)
+	if err != nil {
+		return pgerror.Newf(pgcode.UndefinedColumn,
+			"row-level TTL expiration column %s does not exist", colName.String())
+	}
+	if col.GetType().Family() != types.TimestampTZFamily {
+		return pgerror.Newf(pgcode.DatatypeMismatch,
+			"row-level TTL expiration column %s must be of type TIMESTAMPTZ, found type %s",
+			colName.String(), col.GetType().SQLString())
+	}
+	desc.RowLevelTTL.ExpirationColumnID = col.GetID()
+	return nil
+}
+
+// createRowLevelTTLSchedule creates the schedule which periodically runs the
+// row-level TTL job of the given table, and records its ID in the table
+// descriptor.
+func createRowLevelTTLSchedule(params runParams, desc *tabledesc.Mutable) error {
+	ttl := desc.RowLevelTTL
+	env := jobSchedulerEnv(params)
+	sj := jobs.NewScheduledJob(env)
+	sj.SetScheduleLabel(fmt.Sprintf("row-level-ttl-%d", desc.ID))
+	sj.SetOwner(params.p.User())
+	cron := ttl.JobCron
+	if cron == "" {
+		cron = defaultRowLevelTTLJobCron
+	}
+	if err := sj.SetSchedule(cron); err != nil {
+		return err
+	}
+	sj.SetScheduleDetails(jobspb.ScheduleDetails{
+		Wait:    jobspb.ScheduleDetails_SKIP,
+		OnError: jobspb.ScheduleDetails_RETRY_SCHED,
+	})
+	args, err := pbtypes.MarshalAny(&jobspb.ScheduledRowLevelTTLArgs{TableID: desc.ID})
+	if err != nil {
+		return err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledRowLevelTTLExecutor.InternalName(),
+		jobspb.ExecutionArguments{Args: args},
+	)
+	if err := sj.Create(params.ctx, params.ExecCfg().InternalExecutor, params.p.txn); err != nil {
+		return errors.Wrap(err, "creating row-level TTL schedule")
+	}
+	ttl.ScheduleID = sj.ScheduleID()
+	return nil
+}
+
+// deleteRowLevelTTLSchedule deletes the schedule of the row-level TTL job of
+// the given table, if any.
+func deleteRowLevelTTLSchedule(params runParams, desc *tabledesc.Mutable) error {
+	if desc.RowLevelTTL == nil || desc.RowLevelTTL.ScheduleID == 0 {
+		return nil
+	}
+	return deleteSchedule(params, desc.RowLevelTTL.ScheduleID)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -208,6 +209,12 @@ func DecodeTableKey(
 	if (dir != encoding.Ascending) && (dir != encoding.Descending) {
 		return nil, nil, errors.Errorf("invalid direction: %d", dir)
 	}
+	// A tuple is encoded as the concatenation of its elements, so it is never
+	// NULL itself. Decode it before checking for NULL, since the first element
+	// may be NULL.
+	if valType.Family() == types.TupleFamily {
+		return decodeTupleKey(a, valType, key, dir)
+	}
 	var isNull bool
 	if key, isNull = encoding.DecodeIfNull(key); isNull {
 		return tree.DNull, key, nil
@@ -292,6 +299,15 @@ func DecodeTableKey(
 			return nil, nil, err
 		}
 		return tree.DNull, key[jsonLen:], nil
+	case types.TSVectorFamily:
+		// Columns of this type only appear in keys of inverted indexes, where
+		// the key holds a single lexeme rather than the whole vector. Just
+		// return the remaining bytes of the key.
+		lexemeLen, err := encoding.PeekLength(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tree.DNull, key[lexemeLen:], nil
 	case types.BytesFamily:
 		var r []byte
 		if dir == encoding.Ascending {
@@ -489,6 +505,10 @@ func EncodeTableValue(
 			return nil, err
 		}
 		return encoding.EncodeJSONValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DTSVector:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.Encode(scratch)), nil
+	case *tree.DTSQuery:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.Encode(scratch)), nil
 	case *tree.DArray:
 		a, err := encodeArray(t, scratch)
 		if err != nil {
@@ -661,6 +681,26 @@ func DecodeUntaggedDatum(a *DatumAlloc, t *types.T, buf []byte) (tree.Datum, []b
 			return nil, b, err
 		}
 		return a.NewDJSON(tree.DJSON{JSON: j}), b, nil
+	case types.TSVectorFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		v, err := tsearch.DecodeTSVector(data)
+		if err != nil {
+			return nil, b, err
+		}
+		return tree.NewDTSVector(v), b, nil
+	case types.TSQueryFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		q, err := tsearch.DecodeTSQuery(data)
+		if err != nil {
+			return nil, b, err
+		}
+		return tree.NewDTSQuery(q), b, nil
 	case types.OidFamily:
 		b, data, err := encoding.DecodeUntaggedIntValue(buf)
 		return a.NewDOid(tree.MakeDOid(tree.DInt(data))), b, err
@@ -799,6 +839,16 @@ func MarshalColumnValue(col *descpb.ColumnDescriptor, val tree.Datum) (roachpb.V
 			r.SetBytes(data)
 			return r, nil
 		}
+	case types.TSVectorFamily:
+		if v, ok := val.(*tree.DTSVector); ok {
+			r.SetBytes(v.Encode(nil))
+			return r, nil
+		}
+	case types.TSQueryFamily:
+		if v, ok := val.(*tree.DTSQuery); ok {
+			r.SetBytes(v.Encode(nil))
+			return r, nil
+		}
 	case types.ArrayFamily:
 		if v, ok := val.(*tree.DArray); ok {
 			if err := checkElementType(v.ParamTyp, col.Type.ArrayContents()); err != nil {
@@ -834,6 +884,15 @@ func MarshalColumnValue(col *descpb.ColumnDescriptor, val tree.Datum) (roachpb.V
 			r.SetBytes(v.PhysicalRep)
 			return r, nil
 		}
+	case types.TupleFamily:
+		if v, ok := val.(*tree.DTuple); ok {
+			b, err := encodeUntaggedTuple(v, nil, nil)
+			if err != nil {
+				return r, err
+			}
+			r.SetBytes(b)
+			return r, nil
+		}
 	default:
 		return r, errors.AssertionFailedf("unsupported column type: %s", col.Type.Family())
 	}
@@ -1003,6 +1062,26 @@ func UnmarshalColumnValue(a *DatumAlloc, typ *types.T, value roachpb.Value) (tre
 			return nil, err
 		}
 		return tree.NewDJSON(jsonDatum), nil
+	case types.TSVectorFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		tsv, err := tsearch.DecodeTSVector(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDTSVector(tsv), nil
+	case types.TSQueryFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		tsq, err := tsearch.DecodeTSQuery(v)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDTSQuery(tsq), nil
 	case types.EnumFamily:
 		v, err := value.GetBytes()
 		if err != nil {
@@ -1013,6 +1092,13 @@ func UnmarshalColumnValue(a *DatumAlloc, typ *types.T, value roachpb.Value) (tre
 			return nil, err
 		}
 		return a.NewDEnum(tree.DEnum{EnumTyp: typ, PhysicalRep: phys, LogicalRep: log}), nil
+	case types.TupleFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		datum, _, err := decodeTuple(a, typ, v)
+		return datum, err
 	default:
 		return nil, errors.Errorf("unsupported column type: %s", typ.Family())
 	}
@@ -1021,6 +1107,12 @@ func UnmarshalColumnValue(a *DatumAlloc, typ *types.T, value roachpb.Value) (tre
 // encodeTuple produces the value encoding for a tuple.
 func encodeTuple(t *tree.DTuple, appendTo []byte, colID uint32, scratch []byte) ([]byte, error) {
 	appendTo = encoding.EncodeValueTag(appendTo, colID, encoding.Tuple)
+	return encodeUntaggedTuple(t, appendTo, scratch)
+}
+
+// encodeUntaggedTuple produces the value encoding for a tuple without a value
+// tag. It is decoded by decodeTuple().
+func encodeUntaggedTuple(t *tree.DTuple, appendTo []byte, scratch []byte) ([]byte, error) {
 	appendTo = encoding.EncodeNonsortingUvarint(appendTo, uint64(len(t.D)))
 
 	var err error
@@ -1118,6 +1210,22 @@ func decodeArrayKey(
 	return result, buf, nil
 }
 
+// decodeTupleKey decodes a tuple key generated by EncodeTableKey.
+func decodeTupleKey(
+	a *DatumAlloc, t *types.T, buf []byte, dir encoding.Direction,
+) (tree.Datum, []byte, error) {
+	contents := t.TupleContents()
+	datums := make(tree.Datums, len(contents))
+	for i := range contents {
+		var err error
+		datums[i], buf, err = DecodeTableKey(a, contents[i], buf, dir)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return tree.NewDTuple(t, datums...), buf, nil
+}
+
 // encodeArray produces the value encoding for an array.
 func encodeArray(d *tree.DArray, scratch []byte) ([]byte, error) {
 	if err := d.Validate(); err != nil {
@@ -76,7 +76,7 @@ func hasKeyEncoding(typ *types.T) bool {
 	// Only some types are round-trip key encodable.
 	switch typ.Family() {
 	case types.JsonFamily, types.CollatedStringFamily, types.TupleFamily, types.DecimalFamily,
-		types.GeographyFamily, types.GeometryFamily:
+		types.GeographyFamily, types.GeometryFamily, types.TSVectorFamily, types.TSQueryFamily:
 		return false
 	case types.ArrayFamily:
 		return hasKeyEncoding(typ.ArrayContents())
@@ -320,6 +320,30 @@ func TestDecodeTableKeyOutOfRangeTimestamp(t *testing.T) {
 	}
 }
 
+// TestDecodeTableKeyTuple tests that tuples, including tuples with NULL
+// elements, round-trip through the key encoding.
+func TestDecodeTableKeyTuple(t *testing.T) {
+	typ := types.MakeTuple([]*types.T{types.String, types.Int})
+	for _, d := range []*tree.DTuple{
+		tree.NewDTuple(typ, tree.NewDString("FR"), tree.NewDInt(75)),
+		tree.NewDTuple(typ, tree.DNull, tree.NewDInt(75)),
+		tree.NewDTuple(typ, tree.NewDString("FR"), tree.DNull),
+		tree.NewDTuple(typ, tree.DNull, tree.DNull),
+	} {
+		for _, dir := range []encoding.Direction{encoding.Ascending, encoding.Descending} {
+			t.Run(fmt.Sprintf("%s/direction:%d", d.String(), dir), func(t *testing.T) {
+				encoded, err := EncodeTableKey([]byte{}, d, dir)
+				require.NoError(t, err)
+				a := &DatumAlloc{}
+				decoded, rest, err := DecodeTableKey(a, typ, encoded, dir)
+				require.NoError(t, err)
+				require.Empty(t, rest)
+				require.Equal(t, d, decoded)
+			})
+		}
+	}
+}
+
 // TestDecodeTableValueOutOfRangeTimestamp deliberately tests out of range timestamps
 // can still be decoded from disk. See #46973.
 func TestDecodeTableValueOutOfRangeTimestamp(t *testing.T) {
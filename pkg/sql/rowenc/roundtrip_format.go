@@ -21,8 +21,9 @@ import (
 func ParseDatumStringAs(t *types.T, s string, evalCtx *tree.EvalContext) (tree.Datum, error) {
 	switch t.Family() {
 	// We use a different parser for array types because ParseAndRequireString only parses
-	// the internal postgres string representation of arrays.
-	case types.ArrayFamily, types.CollatedStringFamily:
+	// the internal postgres string representation of arrays. Tuples are parsed
+	// from their SQL literal representation.
+	case types.ArrayFamily, types.CollatedStringFamily, types.TupleFamily:
 		return parseAsTyp(evalCtx, t, s)
 	default:
 		res, _, err := tree.ParseAndRequireString(t, s, evalCtx)
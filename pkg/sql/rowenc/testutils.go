@@ -43,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -173,6 +174,10 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 			return nil
 		}
 		return &tree.DJSON{JSON: j}
+	case types.TSVectorFamily:
+		return tree.NewDTSVector(tsearch.RandomTSVector(rng))
+	case types.TSQueryFamily:
+		return tree.NewDTSQuery(tsearch.RandomTSQuery(rng))
 	case types.TupleFamily:
 		tuple := tree.DTuple{D: make(tree.Datums, len(typ.TupleContents()))}
 		for i := range typ.TupleContents() {
@@ -595,6 +600,23 @@ var (
 			// GEOMETRYCOLLECTION EMPTY
 			&tree.DGeometry{Geometry: geo.MustParseGeometry("010700000000000000")},
 		},
+		types.TSVectorFamily: {
+			tree.NewDTSVector(tsearch.TSVector{}),
+			tree.NewDTSVector(tsearch.TSVector{{Word: "a"}}),
+			tree.NewDTSVector(tsearch.TSVector{{Word: `'`, Positions: []tsearch.Position{{Pos: 1}}}}),
+			tree.NewDTSVector(tsearch.TSVector{
+				{Word: "b", Positions: []tsearch.Position{{Pos: 2}, {Pos: tsearch.MaxPosition}}},
+				{Word: "\u2603", Positions: []tsearch.Position{{Pos: 1, Weight: tsearch.WeightA}}},
+			}),
+		},
+		types.TSQueryFamily: {
+			tree.NewDTSQuery(tsearch.TSQuery{}),
+			tree.NewDTSQuery(tsearch.TSQuery{Root: &tsearch.QueryNode{Op: tsearch.OpLexeme, Lexeme: "a"}}),
+			tree.NewDTSQuery(tsearch.TSQuery{Root: &tsearch.QueryNode{
+				Op:   tsearch.OpNot,
+				Left: &tsearch.QueryNode{Op: tsearch.OpLexeme, Lexeme: `'`, Prefix: true},
+			}}),
+		},
 		types.StringFamily: {
 			tree.NewDString(""),
 			tree.NewDString("X"),
@@ -51,9 +51,9 @@ type invertedFilterer struct {
 	diskMonitor *mon.BytesMonitor
 	rc          *rowcontainer.DiskBackedNumberedRowContainer
 
-	invertedEval batchedInvertedExprEvaluator
+	invertedEval inverted.BatchedExprEvaluator
 	// The invertedEval result.
-	evalResult []KeyIndex
+	evalResult []inverted.KeyIndex
 	// The next result row, i.e., evalResult[resultIdx].
 	resultIdx int
 
@@ -80,8 +80,8 @@ func newInvertedFilterer(
 	ifr := &invertedFilterer{
 		input:          input,
 		invertedColIdx: spec.InvertedColIdx,
-		invertedEval: batchedInvertedExprEvaluator{
-			exprs: []*inverted.SpanExpressionProto{&spec.InvertedExpr},
+		invertedEval: inverted.BatchedExprEvaluator{
+			Exprs: []*inverted.SpanExpressionProto{&spec.InvertedExpr},
 		},
 	}
 
@@ -140,15 +140,15 @@ func newInvertedFilterer(
 		if err != nil {
 			return nil, err
 		}
-		ifr.invertedEval.filterer = preFilterer
-		ifr.invertedEval.preFilterState = append(ifr.invertedEval.preFilterState, preFiltererState)
+		ifr.invertedEval.Filterer = preFilterer
+		ifr.invertedEval.PreFilterState = append(ifr.invertedEval.PreFilterState, preFiltererState)
 	}
 	// TODO(sumeer): for expressions that only involve unions, and the output
 	// does not need to be in key-order, we should incrementally output after
 	// de-duping. It will reduce the container memory/disk by 2x.
 
 	// Prepare inverted evaluator for later evaluation.
-	_, err := ifr.invertedEval.init()
+	_, err := ifr.invertedEval.Init()
 	if err != nil {
 		return nil, err
 	}
@@ -197,7 +197,7 @@ func (ifr *invertedFilterer) readInput() (invertedFiltererState, *execinfrapb.Pr
 	}
 	if row == nil {
 		log.VEventf(ifr.Ctx, 1, "no more input rows")
-		evalResult := ifr.invertedEval.evaluate()
+		evalResult := ifr.invertedEval.Evaluate()
 		ifr.rc.SetupForRead(ifr.Ctx, evalResult)
 		// invertedEval had a single expression in the batch, and the results
 		// for that expression are in evalResult[0].
@@ -247,11 +247,11 @@ func (ifr *invertedFilterer) readInput() (invertedFiltererState, *execinfrapb.Pr
 		}
 		enc = []byte(*row[ifr.invertedColIdx].Datum.(*tree.DBytes))
 	}
-	if _, err = ifr.invertedEval.prepareAddIndexRow(enc, nil /* encFull */); err != nil {
+	if _, err = ifr.invertedEval.PrepareAddIndexRow(enc, nil /* encFull */); err != nil {
 		ifr.MoveToDraining(err)
 		return ifrStateUnknown, ifr.DrainHelper()
 	}
-	if err = ifr.invertedEval.addIndexRow(keyIndex); err != nil {
+	if err = ifr.invertedEval.AddIndexRow(keyIndex); err != nil {
 		ifr.MoveToDraining(err)
 		return ifrStateUnknown, ifr.DrainHelper()
 	}
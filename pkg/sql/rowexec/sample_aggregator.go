@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -99,10 +100,15 @@ func newSampleAggregator(
 		if s.GenerateHistogram && s.HistogramMaxBuckets == 0 {
 			return nil, errors.Errorf("histogram max buckets not specified")
 		}
-		if s.GenerateHistogram && len(s.Columns) != 1 {
-			return nil, errors.Errorf("histograms require one column")
+		if spec.FullStatisticID != 0 && (len(s.Columns) != 1 || !s.GenerateHistogram) {
+			return nil, errors.Errorf(
+				"merging into a full statistic requires a single column with a histogram",
+			)
 		}
 	}
+	if spec.FullStatisticID != 0 && len(spec.InvertedSketches) != 0 {
+		return nil, errors.Errorf("cannot merge inverted sketches into a full statistic")
+	}
 
 	ctx := flowCtx.EvalCtx.Ctx()
 	// Limit the memory use by creating a child monitor with a hard limit.
@@ -139,7 +145,11 @@ func newSampleAggregator(
 			numRows:  0,
 		}
 		if spec.Sketches[i].GenerateHistogram {
-			sampleCols.Add(int(spec.Sketches[i].Columns[0]))
+			// Multi-column histograms need the values of every column in the
+			// sketch.
+			for _, col := range spec.Sketches[i].Columns {
+				sampleCols.Add(int(col))
+			}
 		}
 	}
 
@@ -395,17 +405,52 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 	if err := s.FlowCtx.Cfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
 		for _, si := range s.sketches {
 			distinctCount := int64(si.sketch.Estimate())
+			rowCount, nullCount := si.numRows, si.numNulls
 			var histogram *stats.HistogramData
-			if si.spec.GenerateHistogram && len(s.sr.Get()) != 0 {
-				colIdx := int(si.spec.Columns[0])
-				typ := s.inTypes[colIdx]
+			if s.spec.FullStatisticID != 0 {
+				// The sketch only covers the values beyond the bounds of the
+				// histogram of an existing statistic, so merge it into that
+				// statistic.
+				full, err := stats.GetTableStatistic(
+					ctx, s.FlowCtx.Cfg.Executor, txn, s.tableID, s.spec.FullStatisticID,
+				)
+				if err != nil {
+					return err
+				}
+				h, err := s.generateHistogramAtExtremes(
+					ctx,
+					s.EvalCtx,
+					s.sr.Get(),
+					int(si.spec.Columns[0]),
+					s.inTypes,
+					si.numRows-si.numNulls,
+					distinctCount,
+					int(si.spec.HistogramMaxBuckets),
+					full.HistogramData,
+				)
+				if err != nil {
+					return err
+				}
+				histogram = &h
+				// NULLs were scanned again, so they replace the NULLs of the full
+				// statistic. NULL is counted as a distinct value by the sketches.
+				rowCount += int64(full.RowCount - full.NullCount)
+				distinctCount += int64(full.DistinctCount)
+				if full.NullCount > 0 {
+					distinctCount--
+				}
+			} else if si.spec.GenerateHistogram && len(s.sr.Get()) != 0 {
+				colIdxs := make([]int, len(si.spec.Columns))
+				for i, c := range si.spec.Columns {
+					colIdxs[i] = int(c)
+				}
 
 				h, err := s.generateHistogram(
 					ctx,
 					s.EvalCtx,
 					s.sr.Get(),
-					colIdx,
-					typ,
+					colIdxs,
+					s.inTypes,
 					si.numRows-si.numNulls,
 					distinctCount,
 					int(si.spec.HistogramMaxBuckets),
@@ -434,8 +479,8 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 					ctx,
 					s.EvalCtx,
 					invSr.Get(),
-					0, /* colIdx */
-					types.Bytes,
+					[]int{0}, /* colIdxs */
+					bytesRowType,
 					invSketch.numRows-invSketch.numNulls,
 					invDistinctCount,
 					int(invSketch.spec.HistogramMaxBuckets),
@@ -470,9 +515,9 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 				s.tableID,
 				si.spec.StatName,
 				columnIDs,
-				si.numRows,
+				rowCount,
 				distinctCount,
-				si.numNulls,
+				nullCount,
 				histogram,
 			); err != nil {
 				return err
@@ -494,34 +539,69 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 	return nil
 }
 
-// generateHistogram returns a histogram (on a given column) from a set of
-// samples.
+// generateHistogram returns a histogram (on a given set of columns) from a set
+// of samples. If there is more than one column, the histogram is built on
+// tuples of the column values.
 // numRows is the total number of rows from which values were sampled
-// (excluding rows that have NULL values on the histogram column).
+// (excluding rows that have NULL values on all the histogram columns).
 func (s *sampleAggregator) generateHistogram(
 	ctx context.Context,
 	evalCtx *tree.EvalContext,
 	samples []stats.SampledRow,
-	colIdx int,
-	colType *types.T,
+	colIdxs []int,
+	inTypes []*types.T,
 	numRows int64,
 	distinctCount int64,
 	maxBuckets int,
 ) (stats.HistogramData, error) {
-	// Account for the memory we'll use copying the samples into values.
-	if err := s.tempMemAcc.Grow(ctx, sizeOfDatum*int64(len(samples))); err != nil {
+	values, colType, err := s.histogramValues(ctx, samples, colIdxs, inTypes)
+	if err != nil {
 		return stats.HistogramData{}, err
 	}
+	return stats.EquiDepthHistogram(evalCtx, colType, values, numRows, distinctCount, maxBuckets)
+}
+
+// histogramValues decodes the values of the given columns from a set of
+// samples, ignoring samples that are NULL on all the columns. If there is more
+// than one column, the values are tuples of the column values. It also returns
+// the type of the values.
+func (s *sampleAggregator) histogramValues(
+	ctx context.Context, samples []stats.SampledRow, colIdxs []int, inTypes []*types.T,
+) (tree.Datums, *types.T, error) {
+	// Account for the memory we'll use copying the samples into values.
+	if err := s.tempMemAcc.Grow(ctx, sizeOfDatum*int64(len(samples)*len(colIdxs))); err != nil {
+		return nil, nil, err
+	}
 	values := make(tree.Datums, 0, len(samples))
 
+	var tupleType *types.T
+	if len(colIdxs) > 1 {
+		contents := make([]*types.T, len(colIdxs))
+		for i, colIdx := range colIdxs {
+			contents[i] = inTypes[colIdx]
+		}
+		tupleType = types.MakeTuple(contents)
+	}
+
 	var da rowenc.DatumAlloc
 	for _, sample := range samples {
-		ed := &sample.Row[colIdx]
-		// Ignore NULLs (they are counted separately).
-		if !ed.IsNull() {
+		var tuple tree.Datums
+		if tupleType != nil {
+			tuple = make(tree.Datums, len(colIdxs))
+		}
+		allNull := true
+		for i, colIdx := range colIdxs {
+			ed := &sample.Row[colIdx]
+			if ed.IsNull() {
+				if tuple != nil {
+					tuple[i] = tree.DNull
+				}
+				continue
+			}
+			allNull = false
 			beforeSize := ed.Datum.Size()
-			if err := ed.EnsureDecoded(colType, &da); err != nil {
-				return stats.HistogramData{}, err
+			if err := ed.EnsureDecoded(inTypes[colIdx], &da); err != nil {
+				return nil, nil, err
 			}
 			afterSize := ed.Datum.Size()
 
@@ -530,14 +610,105 @@ func (s *sampleAggregator) generateHistogram(
 			// destroyed.
 			if afterSize > beforeSize {
 				if err := s.memAcc.Grow(ctx, int64(afterSize-beforeSize)); err != nil {
-					return stats.HistogramData{}, err
+					return nil, nil, err
 				}
 			}
 
-			values = append(values, ed.Datum)
+			if tuple != nil {
+				tuple[i] = ed.Datum
+			} else {
+				values = append(values, ed.Datum)
+			}
+		}
+		// Ignore rows that are NULL on all the columns (they are counted
+		// separately).
+		if tuple != nil && !allNull {
+			values = append(values, tree.NewDTuple(tupleType, tuple...))
 		}
 	}
-	return stats.EquiDepthHistogram(evalCtx, colType, values, numRows, distinctCount, maxBuckets)
+	colType := tupleType
+	if colType == nil {
+		colType = inTypes[colIdxs[0]]
+	}
+	return values, colType, nil
+}
+
+// generateHistogramAtExtremes returns the given histogram of a full statistic
+// merged with histograms built from the samples below its first upper bound
+// and above its last upper bound. The samples are expected to have been
+// collected only over those ranges of a single column (see CREATE STATISTICS
+// ... USING EXTREMES).
+// numRows and distinctCount describe the sampled rows, and are split between
+// the two ranges in proportion to the number of samples in each range.
+func (s *sampleAggregator) generateHistogramAtExtremes(
+	ctx context.Context,
+	evalCtx *tree.EvalContext,
+	samples []stats.SampledRow,
+	colIdx int,
+	inTypes []*types.T,
+	numRows int64,
+	distinctCount int64,
+	maxBuckets int,
+	full *stats.HistogramData,
+) (stats.HistogramData, error) {
+	if full == nil || len(full.Buckets) == 0 {
+		return stats.HistogramData{}, errors.AssertionFailedf("full statistic has no histogram")
+	}
+	values, colType, err := s.histogramValues(ctx, samples, []int{colIdx}, inTypes)
+	if err != nil {
+		return stats.HistogramData{}, err
+	}
+
+	var da rowenc.DatumAlloc
+	lowerBound, _, err := rowenc.DecodeTableKey(
+		&da, colType, full.Buckets[0].UpperBound, encoding.Ascending,
+	)
+	if err != nil {
+		return stats.HistogramData{}, err
+	}
+	upperBound, _, err := rowenc.DecodeTableKey(
+		&da, colType, full.Buckets[len(full.Buckets)-1].UpperBound, encoding.Ascending,
+	)
+	if err != nil {
+		return stats.HistogramData{}, err
+	}
+
+	var lowerValues, upperValues tree.Datums
+	for _, v := range values {
+		if v.Compare(evalCtx, lowerBound) < 0 {
+			lowerValues = append(lowerValues, v)
+		} else if v.Compare(evalCtx, upperBound) > 0 {
+			upperValues = append(upperValues, v)
+		}
+		// Values within the bounds are already covered by the full histogram.
+	}
+
+	var lowerRows, lowerDistinct int64
+	if len(values) > 0 {
+		lowerRows = numRows * int64(len(lowerValues)) / int64(len(values))
+		lowerDistinct = distinctCount * int64(len(lowerValues)) / int64(len(values))
+	}
+	upperRows, upperDistinct := numRows-lowerRows, distinctCount-lowerDistinct
+	if len(lowerValues) > 0 && lowerDistinct == 0 {
+		lowerDistinct = 1
+	}
+	if len(upperValues) > 0 && upperDistinct == 0 {
+		upperDistinct = 1
+	}
+
+	lower, err := stats.EquiDepthHistogram(
+		evalCtx, colType, lowerValues, lowerRows, lowerDistinct, maxBuckets,
+	)
+	if err != nil {
+		return stats.HistogramData{}, err
+	}
+	upper, err := stats.EquiDepthHistogram(
+		evalCtx, colType, upperValues, upperRows, upperDistinct, maxBuckets,
+	)
+	if err != nil {
+		return stats.HistogramData{}, err
+	}
+	return stats.MergeHistogramsAtExtremes(&lower, full, &upper), nil
 }
 
 var _ execinfra.DoesNotUseTxn = &sampleAggregator{}
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils/distsqlutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
@@ -102,6 +103,13 @@ func TestSampleAggregator(t *testing.T) {
 				GenerateHistogram:   true,
 				HistogramMaxBuckets: 4,
 			},
+			{
+				SketchType:          execinfrapb.SketchType_HLL_PLUS_PLUS_V1,
+				Columns:             []uint32{0, 1},
+				GenerateHistogram:   true,
+				HistogramMaxBuckets: 4,
+				StatName:            "ab",
+			},
 		}
 
 		rng, _ := randutil.NewPseudoRand()
@@ -175,7 +183,8 @@ func TestSampleAggregator(t *testing.T) {
 		defer rows.Close()
 
 		type resultBucket struct {
-			numEq, numRange, upper int
+			numEq, numRange int
+			upper           string
 		}
 
 		type result struct {
@@ -202,10 +211,24 @@ func TestSampleAggregator(t *testing.T) {
 				distinctCount: 9,
 				nullCount:     1,
 				buckets: []resultBucket{
-					{numEq: 2, numRange: 0, upper: 1},
-					{numEq: 2, numRange: 1, upper: 3},
-					{numEq: 1, numRange: 1, upper: 5},
-					{numEq: 1, numRange: 2, upper: 8},
+					{numEq: 2, numRange: 0, upper: "1"},
+					{numEq: 2, numRange: 1, upper: "3"},
+					{numEq: 1, numRange: 1, upper: "5"},
+					{numEq: 1, numRange: 2, upper: "8"},
+				},
+			},
+			{
+				tableID:       13,
+				name:          "ab",
+				colIDs:        "{100,101}",
+				rowCount:      11,
+				distinctCount: 11,
+				nullCount:     0,
+				buckets: []resultBucket{
+					{numEq: 1, numRange: 0, upper: "(NULL, 1)"},
+					{numEq: 1, numRange: 2, upper: "(1, 1)"},
+					{numEq: 1, numRange: 2, upper: "(1, 7)"},
+					{numEq: 1, numRange: 3, upper: "(2, 8)"},
 				},
 			},
 		}
@@ -239,20 +262,15 @@ func TestSampleAggregator(t *testing.T) {
 				}
 
 				for _, b := range h.Buckets {
-					ed, _, err := rowenc.EncDatumFromBuffer(
-						types.Int, descpb.DatumEncoding_ASCENDING_KEY, b.UpperBound,
-					)
-					if err != nil {
-						t.Fatal(err)
-					}
 					var d rowenc.DatumAlloc
-					if err := ed.EnsureDecoded(types.Int, &d); err != nil {
+					upper, _, err := rowenc.DecodeTableKey(&d, h.ColumnType, b.UpperBound, encoding.Ascending)
+					if err != nil {
 						t.Fatal(err)
 					}
 					r.buckets = append(r.buckets, resultBucket{
 						numEq:    int(b.NumEq),
 						numRange: int(b.NumRange),
-						upper:    int(*ed.Datum.(*tree.DInt)),
+						upper:    upper.String(),
 					})
 				}
 			} else if len(exp.buckets) > 0 {
@@ -137,7 +137,11 @@ func newSamplerProcessor(
 			numRows:  0,
 		}
 		if spec.Sketches[i].GenerateHistogram {
-			sampleCols.Add(int(spec.Sketches[i].Columns[0]))
+			// Multi-column histograms need the values of every column in the
+			// sketch.
+			for _, col := range spec.Sketches[i].Columns {
+				sampleCols.Add(int(col))
+			}
 		}
 	}
 	for i := range spec.InvertedSketches {
@@ -21,15 +21,18 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltestutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/pgtest"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgproto3/v2"
 	"github.com/stretchr/testify/require"
 )
 
@@ -698,6 +701,62 @@ func TestStatementTimeoutRetryableErrors(t *testing.T) {
 		})
 }
 
+// TestCancelSuspendedPortal verifies that the resumed execution of a
+// suspended portal shows up as an active query of the session and can be
+// canceled.
+func TestCancelSuspendedPortal(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	// The pgtest client doesn't support TLS.
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{Insecure: true})
+	defer s.Stopper().Stop(ctx)
+
+	p, err := pgtest.NewPGTest(ctx, s.ServingSQLAddr(), security.RootUser)
+	require.NoError(t, err)
+	defer func() { _ = p.Close() }()
+
+	// The first row of the query is produced right away, the second one only
+	// after a minute.
+	const query = `SELECT i FROM generate_series(1, 3) AS g(i) WHERE i = 1 OR pg_sleep(60)`
+	for _, line := range []string{
+		`Query {"String": "SET multiple_active_portals_enabled = true"}`,
+		`Query {"String": "BEGIN"}`,
+		fmt.Sprintf(`Parse {"Query": "%s"}`, query),
+		`Bind`,
+		`Execute {"MaxRows": 1}`,
+		`Sync`,
+	} {
+		require.NoError(t, p.SendOneLine(line))
+	}
+	_, err = p.Until(false /* keepErrMsg */, pgtest.ParseMessages("ReadyForQuery\nReadyForQuery\nReadyForQuery")...)
+	require.NoError(t, err)
+
+	// Resume the portal and cancel it once its execution is an active query.
+	require.NoError(t, p.SendOneLine(`Execute {"MaxRows": 1}`))
+	require.NoError(t, p.SendOneLine(`Sync`))
+	startTime := timeutil.Now()
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+	testutils.SucceedsSoon(t, func() error {
+		rows := runner.QueryStr(t,
+			`SELECT query_id FROM [SHOW QUERIES] WHERE query LIKE '%pg_sleep(60)'`,
+		)
+		if len(rows) != 1 {
+			return errors.Errorf("expected the portal's query to be active, got %v", rows)
+		}
+		runner.Exec(t, `CANCEL QUERY $1`, rows[0][0])
+		return nil
+	})
+
+	msgs, err := p.Until(true /* keepErrMsg */, pgtest.ParseMessages("ErrorResponse\nReadyForQuery")...)
+	require.NoError(t, err)
+	require.Equal(t, "query execution canceled", msgs[0].(*pgproto3.ErrorResponse).Message)
+	if timeutil.Since(startTime) >= time.Minute {
+		t.Fatal("expected the portal's query to stop when canceled")
+	}
+}
+
 func getUserConn(t *testing.T, username string, server serverutils.TestServerInterface) *gosql.DB {
 	pgURL := url.URL{
 		Scheme:   "postgres",
@@ -677,6 +677,8 @@ var minimumTypeUsageVersions = map[types.Family]clusterversion.Key{
 	types.GeographyFamily: clusterversion.GeospatialType,
 	types.GeometryFamily:  clusterversion.GeospatialType,
 	types.Box2DFamily:     clusterversion.Box2DType,
+	types.TSQueryFamily:   clusterversion.TextSearchTypes,
+	types.TSVectorFamily:  clusterversion.TextSearchTypes,
 }
 
 // isTypeSupportedInVersion returns whether a given type is supported in the given version.
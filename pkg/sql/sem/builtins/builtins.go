@@ -73,6 +73,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/ulid"
 	"github.com/cockroachdb/cockroach/pkg/util/unaccent"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
@@ -3067,33 +3069,250 @@ may increase either contention or retry errors, or both.`,
 	})),
 
 	// Full text search functions.
-	"ts_match_qv":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"ts_match_vq":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
+	"to_tsvector": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"document", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return toTSVector(tsearch.DefaultConfigName, string(tree.MustBeDString(args[0])))
+			},
+			Info: "Converts `document` to a tsvector, normalizing its words into lexemes " +
+				"using the default text search configuration.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"document", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return toTSVector(string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])))
+			},
+			Info: "Converts `document` to a tsvector, normalizing its words into lexemes " +
+				"using the text search configuration `config`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"to_tsquery": makeTSQueryBuiltin(
+		func(c *tsearch.Config, input string) (tsearch.TSQuery, error) { return c.ToTSQuery(input) },
+		"Converts `query`, which must be in tsquery syntax, to a tsquery, "+
+			"normalizing its words into lexemes",
+	),
+	"plainto_tsquery": makeTSQueryBuiltin(
+		func(c *tsearch.Config, input string) (tsearch.TSQuery, error) { return c.PlainToTSQuery(input), nil },
+		"Converts the plain text `query` to a tsquery that matches documents "+
+			"containing all of its words, normalizing them into lexemes",
+	),
+	"phraseto_tsquery": makeTSQueryBuiltin(
+		func(c *tsearch.Config, input string) (tsearch.TSQuery, error) { return c.PhraseToTSQuery(input), nil },
+		"Converts the plain text `query` to a tsquery that matches documents "+
+			"containing its words as a phrase, normalizing them into lexemes",
+	),
+	"get_current_ts_config": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				return tree.NewDString(tsearch.DefaultConfigName), nil
+			},
+			Info:       "Returns the name of the default text search configuration.",
+			Volatility: tree.VolatilityStable,
+		},
+	),
+	"ts_match_vq": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				v, q := tree.MustBeDTSVector(args[0]), tree.MustBeDTSQuery(args[1])
+				return tree.MakeDBool(tree.DBool(q.Matches(v.TSVector))), nil
+			},
+			Info:       "Returns whether `vector` matches `query`. Equivalent to `vector @@ query`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"ts_match_qv": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.TSQuery}, {"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				q, v := tree.MustBeDTSQuery(args[0]), tree.MustBeDTSVector(args[1])
+				return tree.MakeDBool(tree.DBool(q.Matches(v.TSVector))), nil
+			},
+			Info:       "Returns whether `vector` matches `query`. Equivalent to `query @@ vector`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"tsvector_concat": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector1", types.TSVector}, {"vector2", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				v1, v2 := tree.MustBeDTSVector(args[0]), tree.MustBeDTSVector(args[1])
+				return tree.NewDTSVector(v1.Concat(v2.TSVector)), nil
+			},
+			Info: "Concatenates two tsvectors. The positions of `vector2` are shifted to " +
+				"follow the positions of `vector1`. Equivalent to `vector1 || vector2`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"setweight": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"weight", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				w, err := tsearch.ParseWeight(string(tree.MustBeDString(args[1])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(tree.MustBeDTSVector(args[0]).SetWeight(w)), nil
+			},
+			Info:       "Returns a copy of `vector` in which every position has the weight `weight`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"strip": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDTSVector(tree.MustBeDTSVector(args[0]).Strip()), nil
+			},
+			Info:       "Returns a copy of `vector` without any position or weight information.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"numnode": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDInt(tree.DInt(tree.MustBeDTSQuery(args[0]).NumNodes())), nil
+			},
+			Info:       "Returns the number of lexemes and operators in `query`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"tsvector_to_array": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.StringArray),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				v := tree.MustBeDTSVector(args[0])
+				arr := tree.NewDArray(types.String)
+				for _, l := range v.TSVector {
+					if err := arr.Append(tree.NewDString(l.Word)); err != nil {
+						return nil, err
+					}
+				}
+				return arr, nil
+			},
+			Info:       "Returns the lexemes of `vector` as an array.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"array_to_tsvector": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"lexemes", types.StringArray}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arr := tree.MustBeDArray(args[0])
+				words := make([]string, len(arr.Array))
+				for i, d := range arr.Array {
+					if d == tree.DNull {
+						return nil, pgerror.New(pgcode.NullValueNotAllowed, "lexeme array may not contain nulls")
+					}
+					words[i] = string(tree.MustBeDString(d))
+				}
+				v, err := tsearch.MakeTSVector(words)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(v), nil
+			},
+			Info:       "Converts an array of lexemes to a tsvector without positions.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"ts_rank": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(tree.DNull, args[0], args[1], tree.DZero)
+			},
+			Info:       "Ranks how well `vector` matches `query`, based on the frequency of matching lexemes.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"vector", types.TSVector},
+				{"query", types.TSQuery},
+				{"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(tree.DNull, args[0], args[1], args[2])
+			},
+			Info: "Ranks how well `vector` matches `query`, based on the frequency of matching " +
+				"lexemes. `normalization` is a bit mask of the ways in which the rank is adjusted " +
+				"for the length of the document.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.FloatArray},
+				{"vector", types.TSVector},
+				{"query", types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(args[0], args[1], args[2], tree.DZero)
+			},
+			Info: "Ranks how well `vector` matches `query`, based on the frequency of matching " +
+				"lexemes. `weights` gives the weight of each of the position labels {D, C, B, A}.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"weights", types.FloatArray},
+				{"vector", types.TSVector},
+				{"query", types.TSQuery},
+				{"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(args[0], args[1], args[2], args[3])
+			},
+			Info: "Ranks how well `vector` matches `query`, based on the frequency of matching " +
+				"lexemes. `weights` gives the weight of each of the position labels {D, C, B, A}, " +
+				"and `normalization` is a bit mask of the ways in which the rank is adjusted for " +
+				"the length of the document.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
 	"tsvector_cmp":                   makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"tsvector_concat":                makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_debug":                       makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_headline":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_lexize":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"websearch_to_tsquery":           makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"array_to_tsvector":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"get_current_ts_config":          makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"numnode":                        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"plainto_tsquery":                makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"phraseto_tsquery":               makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"querytree":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"setweight":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"strip":                          makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"to_tsquery":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"to_tsvector":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"json_to_tsvector":               makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"jsonb_to_tsvector":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_delete":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_filter":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"ts_rank":                        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_rank_cd":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"ts_rewrite":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"tsquery_phrase":                 makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
-	"tsvector_to_array":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"tsvector_update_trigger":        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 	"tsvector_update_trigger_column": makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: categoryFullTextSearch}),
 
@@ -3176,11 +3395,53 @@ may increase either contention or retry errors, or both.`,
 	"dmetaphone_alt":         makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 56820, Category: categoryFuzzyStringMatching}),
 
 	// Trigram functions.
-	"similarity":             makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
-	"show_trgm":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
+	"similarity": makeBuiltin(
+		tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"left", types.String}, {"right", types.String}},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				l, r := string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1]))
+				return tree.NewDFloat(tree.DFloat(float32(trigram.Similarity(l, r)))), nil
+			},
+			Info: "Returns a number that indicates how similar the two arguments are, from 0 " +
+				"(no trigrams in common) to 1 (identical sets of trigrams).",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"show_trgm": makeBuiltin(
+		tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}},
+			ReturnType: tree.FixedReturnType(types.StringArray),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				trigrams := trigram.MakeTrigrams(string(tree.MustBeDString(args[0])))
+				arr := tree.NewDArray(types.String)
+				for _, t := range trigrams {
+					if err := arr.Append(tree.NewDString(t)); err != nil {
+						return nil, err
+					}
+				}
+				return arr, nil
+			},
+			Info:       "Returns an array of all the trigrams in the given string.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"show_limit": makeBuiltin(
+		tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(ctx *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				return tree.NewDFloat(tree.DFloat(float32(ctx.SessionData.TrigramSimilarityThreshold))), nil
+			},
+			Info:       "Returns the current similarity threshold used by the % operator.",
+			Volatility: tree.VolatilityStable,
+		},
+	),
 	"word_similarity":        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
 	"strict_word_similarity": makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
-	"show_limit":             makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
 	"set_limit":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
 
 	// JSON functions.
@@ -4554,6 +4815,52 @@ may increase either contention or retry errors, or both.`,
 			},
 			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
 			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsvectorNumInvertedIndexEntries(ctx, args[0])
+			},
+			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"val", types.TSVector},
+				{"version", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				// The version argument is ignored, since there is only one version
+				// of text search inverted indexes.
+				return tsvectorNumInvertedIndexEntries(ctx, args[0])
+			},
+			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.String}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return trigramNumInvertedIndexEntries(ctx, args[0])
+			},
+			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"val", types.String},
+				{"version", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				// The version argument is ignored, since there is only one version
+				// of trigram inverted indexes.
+				return trigramNumInvertedIndexEntries(ctx, args[0])
+			},
+			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
+			Volatility: tree.VolatilityStable,
 		}),
 
 	// Returns true iff the current user has admin role.
@@ -4676,6 +4983,85 @@ may increase either contention or retry errors, or both.`,
 			Volatility: tree.VolatilityStable,
 		},
 	),
+
+	"crdb_internal.check_domain_not_null": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categorySystemInfo,
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"val", types.Any},
+				{"domain", types.String},
+			},
+			ReturnType: tree.IdentityReturnType(0),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if args[0] == tree.DNull {
+					return nil, pgerror.Newf(pgcode.NotNullViolation,
+						"domain %s does not allow null values", tree.MustBeDString(args[1]))
+				}
+				return args[0], nil
+			},
+			Info:       "This function is used internally to enforce NOT NULL constraints of domains.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"crdb_internal.check_domain_constraint": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categorySystemInfo,
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"val", types.Any},
+				{"ok", types.Bool},
+				{"domain", types.String},
+				{"constraint", types.String},
+			},
+			ReturnType: tree.IdentityReturnType(0),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				// As for table CHECK constraints, a NULL result does not violate
+				// the constraint.
+				if args[1] != tree.DNull && !tree.MustBeDBool(args[1]) {
+					return nil, pgerror.Newf(pgcode.CheckViolation,
+						"value for domain %s violates check constraint %q",
+						tree.MustBeDString(args[2]), tree.MustBeDString(args[3]))
+				}
+				return args[0], nil
+			},
+			Info:       "This function is used internally to enforce CHECK constraints of domains.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"crdb_internal.check_row_level_security": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categorySystemInfo,
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"ok", types.Bool},
+				{"table", types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				// Unlike for CHECK constraints, a NULL result violates the policy.
+				if args[0] == tree.DNull || !tree.MustBeDBool(args[0]) {
+					return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+						"new row violates row-level security policy for table %q",
+						tree.MustBeDString(args[1]))
+				}
+				return tree.DBoolTrue, nil
+			},
+			Info: "This function is used internally to enforce the WITH CHECK expressions " +
+				"of row-level security policies.",
+			// The function is volatile so that it is not folded away or evaluated
+			// when no rows are written.
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
 	"crdb_internal.completed_migrations": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
@@ -7395,6 +7781,20 @@ func jsonNumInvertedIndexEntries(_ *tree.EvalContext, val tree.Datum) (tree.Datu
 	return tree.NewDInt(tree.DInt(n)), nil
 }
 
+func tsvectorNumInvertedIndexEntries(_ *tree.EvalContext, val tree.Datum) (tree.Datum, error) {
+	if val == tree.DNull {
+		return tree.DZero, nil
+	}
+	return tree.NewDInt(tree.DInt(len(tree.MustBeDTSVector(val).TSVector))), nil
+}
+
+func trigramNumInvertedIndexEntries(_ *tree.EvalContext, val tree.Datum) (tree.Datum, error) {
+	if val == tree.DNull {
+		return tree.DZero, nil
+	}
+	return tree.NewDInt(tree.DInt(len(trigram.MakeTrigrams(string(tree.MustBeDString(val)))))), nil
+}
+
 func arrayNumInvertedIndexEntries(
 	ctx *tree.EvalContext, val, version tree.Datum,
 ) (tree.Datum, error) {
@@ -7420,3 +7820,79 @@ func arrayNumInvertedIndexEntries(
 	}
 	return tree.NewDInt(tree.DInt(len(keys))), nil
 }
+
+// toTSVector implements the to_tsvector builtin.
+func toTSVector(configName, document string) (tree.Datum, error) {
+	c, err := tsearch.GetConfig(configName)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDTSVector(c.ToTSVector(document)), nil
+}
+
+// makeTSQueryBuiltin returns the definition of a builtin that converts a
+// string to a tsquery using the given function, either with the default text
+// search configuration or with the one named by its first argument.
+func makeTSQueryBuiltin(
+	toTSQuery func(c *tsearch.Config, input string) (tsearch.TSQuery, error), info string,
+) builtinDefinition {
+	fn := func(configName string, input tree.Datum) (tree.Datum, error) {
+		c, err := tsearch.GetConfig(configName)
+		if err != nil {
+			return nil, err
+		}
+		q, err := toTSQuery(c, string(tree.MustBeDString(input)))
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDTSQuery(q), nil
+	}
+	return makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return fn(tsearch.DefaultConfigName, args[0])
+			},
+			Info:       info + " using the default text search configuration.",
+			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return fn(string(tree.MustBeDString(args[0])), args[1])
+			},
+			Info:       info + " using the text search configuration `config`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	)
+}
+
+// tsRank implements the ts_rank builtin. weights is NULL if the default
+// weights should be used.
+func tsRank(weights, vector, query, normalization tree.Datum) (tree.Datum, error) {
+	w := tsearch.DefaultRankWeights
+	if weights != tree.DNull {
+		arr := tree.MustBeDArray(weights)
+		vals := make([]float64, len(arr.Array))
+		for i, d := range arr.Array {
+			if d == tree.DNull {
+				return nil, pgerror.New(pgcode.NullValueNotAllowed, "array of weight must not contain nulls")
+			}
+			vals[i] = float64(tree.MustBeDFloat(d))
+		}
+		var err error
+		if w, err = tsearch.MakeRankWeights(vals); err != nil {
+			return nil, err
+		}
+	}
+	method := int(tree.MustBeDInt(normalization))
+	if method < 0 {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"unrecognized normalization method: %d", method)
+	}
+	rank := tsearch.Rank(w, tree.MustBeDTSVector(vector).TSVector, tree.MustBeDTSQuery(query).TSQuery, method)
+	return tree.NewDFloat(tree.DFloat(rank)), nil
+}
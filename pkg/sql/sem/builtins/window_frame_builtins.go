@@ -69,10 +69,7 @@ func (sw *slidingWindow) add(iv *indexedValue) {
 // indices smaller than given 'idx'. This operation corresponds to shifting the
 // start of the frame up to 'idx'.
 func (sw *slidingWindow) removeAllBefore(idx int) {
-	for i := 0; i < sw.values.Len() && i < idx; i++ {
-		if sw.values.Get(i).(*indexedValue).idx >= idx {
-			break
-		}
+	for sw.values.Len() > 0 && sw.values.GetFirst().(*indexedValue).idx < idx {
 		sw.values.RemoveFirst()
 	}
 }
@@ -123,6 +120,11 @@ func (w *slidingWindowFunc) Compute(
 			if err != nil {
 				return nil, err
 			}
+			if args[0] == tree.DNull {
+				// Null value can neither be minimum nor maximum over a window
+				// frame with non-null values, so we're not adding them.
+				continue
+			}
 			if res == nil {
 				res = args[0]
 			} else {
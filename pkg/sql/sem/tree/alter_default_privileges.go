@@ -0,0 +1,72 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+)
+
+// AlterDefaultPrivileges represents an ALTER DEFAULT PRIVILEGES statement.
+type AlterDefaultPrivileges struct {
+	// Roles is empty if the default privileges apply to the objects created by
+	// the current user.
+	Roles []security.SQLUsername
+	// Schemas is empty if the default privileges apply to the objects created
+	// in any schema of the current database.
+	Schemas ObjectNamePrefixList
+
+	// IsGrant is true for GRANT and false for REVOKE.
+	IsGrant      bool
+	Privileges   privilege.List
+	TargetObject privilege.TargetObjectType
+	Grantees     []security.SQLUsername
+}
+
+var _ Statement = &AlterDefaultPrivileges{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDefaultPrivileges) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER DEFAULT PRIVILEGES ")
+	if len(node.Roles) > 0 {
+		ctx.WriteString("FOR ROLE ")
+		formatUsernames(ctx, node.Roles)
+		ctx.WriteByte(' ')
+	}
+	if len(node.Schemas) > 0 {
+		ctx.WriteString("IN SCHEMA ")
+		ctx.FormatNode(&node.Schemas)
+		ctx.WriteByte(' ')
+	}
+	if node.IsGrant {
+		ctx.WriteString("GRANT ")
+	} else {
+		ctx.WriteString("REVOKE ")
+	}
+	node.Privileges.Format(&ctx.Buffer)
+	ctx.WriteString(" ON ")
+	ctx.WriteString(node.TargetObject.String())
+	if node.IsGrant {
+		ctx.WriteString(" TO ")
+	} else {
+		ctx.WriteString(" FROM ")
+	}
+	formatUsernames(ctx, node.Grantees)
+}
+
+func formatUsernames(ctx *FmtCtx, users []security.SQLUsername) {
+	for i, user := range users {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatUsername(user)
+	}
+}
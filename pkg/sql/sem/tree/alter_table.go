@@ -60,23 +60,24 @@ type AlterTableCmd interface {
 	alterTableCmd()
 }
 
-func (*AlterTableAddColumn) alterTableCmd()          {}
-func (*AlterTableAddConstraint) alterTableCmd()      {}
-func (*AlterTableAlterColumnType) alterTableCmd()    {}
-func (*AlterTableAlterPrimaryKey) alterTableCmd()    {}
-func (*AlterTableDropColumn) alterTableCmd()         {}
-func (*AlterTableDropConstraint) alterTableCmd()     {}
-func (*AlterTableDropNotNull) alterTableCmd()        {}
-func (*AlterTableDropStored) alterTableCmd()         {}
-func (*AlterTableSetNotNull) alterTableCmd()         {}
-func (*AlterTableRenameColumn) alterTableCmd()       {}
-func (*AlterTableRenameConstraint) alterTableCmd()   {}
-func (*AlterTableSetAudit) alterTableCmd()           {}
-func (*AlterTableSetDefault) alterTableCmd()         {}
-func (*AlterTableSetVisible) alterTableCmd()         {}
-func (*AlterTableValidateConstraint) alterTableCmd() {}
-func (*AlterTablePartitionByTable) alterTableCmd()   {}
-func (*AlterTableInjectStats) alterTableCmd()        {}
+func (*AlterTableAddColumn) alterTableCmd()           {}
+func (*AlterTableAddConstraint) alterTableCmd()       {}
+func (*AlterTableAlterColumnType) alterTableCmd()     {}
+func (*AlterTableAlterPrimaryKey) alterTableCmd()     {}
+func (*AlterTableDropColumn) alterTableCmd()          {}
+func (*AlterTableDropConstraint) alterTableCmd()      {}
+func (*AlterTableDropNotNull) alterTableCmd()         {}
+func (*AlterTableDropStored) alterTableCmd()          {}
+func (*AlterTableSetNotNull) alterTableCmd()          {}
+func (*AlterTableRenameColumn) alterTableCmd()        {}
+func (*AlterTableRenameConstraint) alterTableCmd()    {}
+func (*AlterTableSetAudit) alterTableCmd()            {}
+func (*AlterTableSetDefault) alterTableCmd()          {}
+func (*AlterTableSetVisible) alterTableCmd()          {}
+func (*AlterTableValidateConstraint) alterTableCmd()  {}
+func (*AlterTablePartitionByTable) alterTableCmd()    {}
+func (*AlterTableInjectStats) alterTableCmd()         {}
+func (*AlterTableSetRowLevelSecurity) alterTableCmd() {}
 
 var _ AlterTableCmd = &AlterTableAddColumn{}
 var _ AlterTableCmd = &AlterTableAddConstraint{}
@@ -94,6 +95,7 @@ var _ AlterTableCmd = &AlterTableSetVisible{}
 var _ AlterTableCmd = &AlterTableValidateConstraint{}
 var _ AlterTableCmd = &AlterTablePartitionByTable{}
 var _ AlterTableCmd = &AlterTableInjectStats{}
+var _ AlterTableCmd = &AlterTableSetRowLevelSecurity{}
 
 // ColumnMutationCmd is the subset of AlterTableCmds that modify an
 // existing column.
@@ -578,6 +580,29 @@ func (node *AlterTableInjectStats) Format(ctx *FmtCtx) {
 	ctx.FormatNode(node.Stats)
 }
 
+// AlterTableSetRowLevelSecurity represents an ALTER TABLE {ENABLE | DISABLE}
+// ROW LEVEL SECURITY command.
+type AlterTableSetRowLevelSecurity struct {
+	Enable bool
+}
+
+// TelemetryCounter implements the AlterTableCmd interface.
+func (node *AlterTableSetRowLevelSecurity) TelemetryCounter() telemetry.Counter {
+	if node.Enable {
+		return sqltelemetry.SchemaChangeAlterCounterWithExtra("table", "enable_row_level_security")
+	}
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("table", "disable_row_level_security")
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableSetRowLevelSecurity) Format(ctx *FmtCtx) {
+	if node.Enable {
+		ctx.WriteString(" ENABLE ROW LEVEL SECURITY")
+	} else {
+		ctx.WriteString(" DISABLE ROW LEVEL SECURITY")
+	}
+}
+
 // AlterTableLocality represents an ALTER TABLE LOCALITY command.
 type AlterTableLocality struct {
 	Name     *UnresolvedObjectName
@@ -121,6 +121,18 @@ var validCasts = []castInfo{
 	{from: types.GeometryFamily, to: types.Box2DFamily, volatility: VolatilityImmutable},
 	{from: types.Box2DFamily, to: types.Box2DFamily, volatility: VolatilityImmutable},
 
+	// Casts to TSVectorFamily.
+	{from: types.UnknownFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.CollatedStringFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+
+	// Casts to TSQueryFamily.
+	{from: types.UnknownFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.CollatedStringFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+
 	// Casts to GeographyFamily.
 	{from: types.UnknownFamily, to: types.GeographyFamily, volatility: VolatilityImmutable},
 	{from: types.BytesFamily, to: types.GeographyFamily, volatility: VolatilityImmutable},
@@ -167,6 +179,8 @@ var validCasts = []castInfo{
 	{from: types.TupleFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.GeometryFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.Box2DFamily, to: types.StringFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.StringFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.GeographyFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.BytesFamily, to: types.StringFamily, volatility: VolatilityStable},
 	{from: types.TimestampFamily, to: types.StringFamily, volatility: VolatilityImmutable},
@@ -193,6 +207,8 @@ var validCasts = []castInfo{
 	{from: types.ArrayFamily, to: types.CollatedStringFamily, volatility: VolatilityStable},
 	{from: types.TupleFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.Box2DFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.GeometryFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.GeographyFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.BytesFamily, to: types.CollatedStringFamily, volatility: VolatilityStable},
@@ -513,6 +529,22 @@ func AdjustValueToType(typ *types.T, inVal Datum) (outVal Datum, err error) {
 				return outArr, nil
 			}
 		}
+	case types.TupleFamily:
+		if inTuple, ok := inVal.(*DTuple); ok && typ.UserDefined() {
+			contents := typ.TupleContents()
+			if len(inTuple.D) != len(contents) {
+				break
+			}
+			outTuple := NewDTupleWithLen(typ, len(inTuple.D))
+			for i, inElem := range inTuple.D {
+				outElem, err := AdjustValueToType(contents[i], inElem)
+				if err != nil {
+					return nil, err
+				}
+				outTuple.D[i] = outElem
+			}
+			return outTuple, nil
+		}
 	case types.TimeFamily:
 		if in, ok := inVal.(*DTime); ok {
 			return in.Round(TimeFamilyPrecisionToRoundDuration(typ.Precision())), nil
@@ -885,6 +917,10 @@ func performCastWithoutPrecisionTruncation(ctx *EvalContext, d Datum, t *types.T
 			s = t.String()
 		case *DJSON:
 			s = t.JSON.String()
+		case *DTSVector:
+			s = t.TSVector.String()
+		case *DTSQuery:
+			s = t.TSQuery.String()
 		case *DEnum:
 			s = t.LogicalRep
 		}
@@ -972,6 +1008,26 @@ func performCastWithoutPrecisionTruncation(ctx *EvalContext, d Datum, t *types.T
 			return NewDBox2D(*bbox), nil
 		}
 
+	case types.TSVectorFamily:
+		switch d := d.(type) {
+		case *DString:
+			return ParseDTSVector(string(*d))
+		case *DCollatedString:
+			return ParseDTSVector(d.Contents)
+		case *DTSVector:
+			return d, nil
+		}
+
+	case types.TSQueryFamily:
+		switch d := d.(type) {
+		case *DString:
+			return ParseDTSQuery(string(*d))
+		case *DCollatedString:
+			return ParseDTSQuery(d.Contents)
+		case *DTSQuery:
+			return d, nil
+		}
+
 	case types.GeographyFamily:
 		switch d := d.(type) {
 		case *DString:
@@ -1268,6 +1324,25 @@ func performCastWithoutPrecisionTruncation(ctx *EvalContext, d Datum, t *types.T
 			}
 			return dcast, nil
 		}
+	case types.TupleFamily:
+		switch v := d.(type) {
+		case *DTuple:
+			if len(v.D) != len(t.TupleContents()) {
+				break
+			}
+			ret := NewDTupleWithLen(t, len(v.D))
+			for i, e := range v.D {
+				ret.D[i] = DNull
+				if e != DNull {
+					var err error
+					ret.D[i], err = PerformCast(ctx, e, t.TupleContents()[i])
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+			return ret, nil
+		}
 	case types.OidFamily:
 		switch v := d.(type) {
 		case *DOid:
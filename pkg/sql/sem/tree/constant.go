@@ -494,6 +494,8 @@ var (
 		types.AnyEnum,
 		types.INetArray,
 		types.VarBitArray,
+		types.TSQuery,
+		types.TSVector,
 	}
 	// StrValAvailBytes is the set of types convertible to byte array.
 	StrValAvailBytes = []*types.T{types.Bytes, types.Uuid, types.String, types.AnyEnum}
@@ -18,6 +18,17 @@ type CopyFrom struct {
 	Columns NameList
 	Stdin   bool
 	Options CopyOptions
+	Where   *Where
+}
+
+// CopyTo represents a COPY TO statement.
+type CopyTo struct {
+	// Table and Columns are set when the contents of a table are copied.
+	Table   TableName
+	Columns NameList
+	// Statement is set when the results of a query are copied.
+	Statement Statement
+	Options   CopyOptions
 }
 
 // CopyOptions describes options for COPY execution.
@@ -26,6 +37,8 @@ type CopyOptions struct {
 	CopyFormat  CopyFormat
 	Delimiter   Expr
 	Null        Expr
+	Quote       Expr
+	Header      bool
 }
 
 var _ NodeFormatter = &CopyOptions{}
@@ -47,6 +60,32 @@ func (node *CopyFrom) Format(ctx *FmtCtx) {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
 	}
+	if node.Where != nil {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(node.Where)
+	}
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CopyTo) Format(ctx *FmtCtx) {
+	ctx.WriteString("COPY ")
+	if node.Statement != nil {
+		ctx.WriteByte('(')
+		ctx.FormatNode(node.Statement)
+		ctx.WriteByte(')')
+	} else {
+		ctx.FormatNode(&node.Table)
+		if len(node.Columns) > 0 {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.Columns)
+			ctx.WriteString(")")
+		}
+	}
+	ctx.WriteString(" TO STDOUT")
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
 }
 
 // Format implements the NodeFormatter interface
@@ -79,6 +118,17 @@ func (o *CopyOptions) Format(ctx *FmtCtx) {
 		ctx.FormatNode(o.Null)
 		addSep = true
 	}
+	if o.Quote != nil {
+		maybeAddSep()
+		ctx.WriteString("QUOTE ")
+		ctx.FormatNode(o.Quote)
+		addSep = true
+	}
+	if o.Header {
+		maybeAddSep()
+		ctx.WriteString("HEADER")
+		addSep = true
+	}
 	if o.Destination != nil {
 		maybeAddSep()
 		// Lowercase because that's what has historically been produced
@@ -122,6 +172,18 @@ func (o *CopyOptions) CombineWith(other *CopyOptions) error {
 		}
 		o.Null = other.Null
 	}
+	if other.Quote != nil {
+		if o.Quote != nil {
+			return errors.New("quote option specified multiple times")
+		}
+		o.Quote = other.Quote
+	}
+	if other.Header {
+		if o.Header {
+			return errors.New("header option specified multiple times")
+		}
+		o.Header = other.Header
+	}
 	return nil
 }
 
@@ -119,7 +119,10 @@ type IndexElem struct {
 	Column Name
 	// Expr is set if the index element is an expression (part of an
 	// expression-based index). If set, Column is empty.
-	Expr       Expr
+	Expr Expr
+	// OpClass is set if an operator class was specified for the index element,
+	// e.g. gin_trgm_ops.
+	OpClass    Name
 	Direction  Direction
 	NullsOrder NullsOrder
 }
@@ -140,6 +143,10 @@ func (node *IndexElem) Format(ctx *FmtCtx) {
 			ctx.WriteByte(')')
 		}
 	}
+	if node.OpClass != "" {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.OpClass)
+	}
 	if node.Direction != DefaultDirection {
 		ctx.WriteByte(' ')
 		ctx.WriteString(node.Direction.String())
@@ -162,6 +169,9 @@ func (node *IndexElem) doc(p *PrettyCfg) pretty.Doc {
 			d = p.bracket("(", d, ")")
 		}
 	}
+	if node.OpClass != "" {
+		d = pretty.ConcatSpace(d, p.Doc(&node.OpClass))
+	}
 	if node.Direction != DefaultDirection {
 		d = pretty.ConcatSpace(d, pretty.Keyword(node.Direction.String()))
 	}
@@ -326,12 +336,26 @@ func (l *EnumValueList) Format(ctx *FmtCtx) {
 	}
 }
 
+// CompositeTypeElem is a single element in a composite type definition.
+type CompositeTypeElem struct {
+	Label Name
+	Type  ResolvableTypeReference
+}
+
 // CreateType represents a CREATE TYPE statement.
 type CreateType struct {
 	TypeName *UnresolvedObjectName
 	Variety  CreateTypeVariety
 	// EnumLabels is set when this represents a CREATE TYPE ... AS ENUM statement.
 	EnumLabels EnumValueList
+	// CompositeTypeList is set when this represents a CREATE TYPE ... AS ( )
+	// statement.
+	CompositeTypeList []CompositeTypeElem
+	// DomainType is set when this represents a CREATE DOMAIN statement.
+	DomainType ResolvableTypeReference
+	// DomainConstraints holds the DEFAULT, NULL, NOT NULL and CHECK clauses of
+	// a CREATE DOMAIN statement.
+	DomainConstraints []NamedColumnQualification
 	// IfNotExists is true if IF NOT EXISTS was requested.
 	IfNotExists bool
 }
@@ -340,6 +364,17 @@ var _ Statement = &CreateType{}
 
 // Format implements the NodeFormatter interface.
 func (node *CreateType) Format(ctx *FmtCtx) {
+	if node.Variety == Domain {
+		ctx.WriteString("CREATE DOMAIN ")
+		ctx.FormatNode(node.TypeName)
+		ctx.WriteString(" AS ")
+		ctx.FormatTypeReference(node.DomainType)
+		for i := range node.DomainConstraints {
+			ctx.WriteByte(' ')
+			formatDomainConstraint(ctx, &node.DomainConstraints[i])
+		}
+		return
+	}
 	ctx.WriteString("CREATE TYPE ")
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
@@ -351,6 +386,18 @@ func (node *CreateType) Format(ctx *FmtCtx) {
 		ctx.WriteString("AS ENUM (")
 		ctx.FormatNode(&node.EnumLabels)
 		ctx.WriteString(")")
+	case Composite:
+		ctx.WriteString("AS (")
+		for i := range node.CompositeTypeList {
+			elem := &node.CompositeTypeList[i]
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatNode(&elem.Label)
+			ctx.WriteByte(' ')
+			ctx.FormatTypeReference(elem.Type)
+		}
+		ctx.WriteString(")")
 	}
 }
 
@@ -358,6 +405,28 @@ func (node *CreateType) String() string {
 	return AsString(node)
 }
 
+// formatDomainConstraint formats a single clause of a CREATE DOMAIN statement.
+func formatDomainConstraint(ctx *FmtCtx, c *NamedColumnQualification) {
+	if c.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		ctx.FormatNode(&c.Name)
+		ctx.WriteByte(' ')
+	}
+	switch t := c.Qualification.(type) {
+	case *ColumnDefault:
+		ctx.WriteString("DEFAULT ")
+		ctx.FormatNode(t.Expr)
+	case NotNullConstraint:
+		ctx.WriteString("NOT NULL")
+	case NullConstraint:
+		ctx.WriteString("NULL")
+	case *ColumnCheckConstraint:
+		ctx.WriteString("CHECK (")
+		ctx.FormatNode(t.Expr)
+		ctx.WriteByte(')')
+	}
+}
+
 // TableDef represents a column, index or constraint definition within a CREATE
 // TABLE statement.
 type TableDef interface {
@@ -880,8 +949,9 @@ func (*CheckConstraintTableDef) constraintTableDef()      {}
 // TABLE statement.
 type UniqueConstraintTableDef struct {
 	IndexTableDef
-	PrimaryKey   bool
-	WithoutIndex bool
+	PrimaryKey    bool
+	WithoutIndex  bool
+	Deferrability ConstraintDeferrability
 }
 
 // SetName implements the TableDef interface.
@@ -921,6 +991,7 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionByIndex != nil {
 		ctx.FormatNode(node.PartitionByIndex)
 	}
+	ctx.FormatNode(&node.Deferrability)
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
@@ -994,14 +1065,52 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// ConstraintDeferrability specifies whether the checks for a constraint can be
+// postponed until the end of the transaction. See
+// https://www.postgresql.org/docs/current/sql-set-constraints.html.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	// NotDeferrableConstraint constraints are checked at the end of every
+	// statement.
+	NotDeferrableConstraint ConstraintDeferrability = iota
+	// InitiallyImmediateConstraint constraints are checked at the end of every
+	// statement, unless they are deferred with SET CONSTRAINTS.
+	InitiallyImmediateConstraint
+	// InitiallyDeferredConstraint constraints are checked when the transaction
+	// commits, unless they are made immediate with SET CONSTRAINTS.
+	InitiallyDeferredConstraint
+)
+
+var constraintDeferrabilityName = [...]string{
+	NotDeferrableConstraint:      "NOT DEFERRABLE",
+	InitiallyImmediateConstraint: "DEFERRABLE INITIALLY IMMEDIATE",
+	InitiallyDeferredConstraint:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (d ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[d]
+}
+
+// Format implements the NodeFormatter interface. NOT DEFERRABLE is omitted
+// because it is the default.
+func (d *ConstraintDeferrability) Format(ctx *FmtCtx) {
+	if *d != NotDeferrableConstraint {
+		ctx.WriteByte(' ')
+		ctx.WriteString(d.String())
+	}
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name     Name
-	Table    TableName
-	FromCols NameList
-	ToCols   NameList
-	Actions  ReferenceActions
-	Match    CompositeKeyMatchMethod
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -1029,6 +1138,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(&node.Deferrability)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1367,6 +1477,7 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 			ctx.FormatNode(&node.Defs)
 			ctx.WriteByte(')')
 		}
+		node.formatStorageParams(ctx)
 		ctx.WriteString(" AS ")
 		ctx.FormatNode(node.AsSource)
 	} else {
@@ -1379,8 +1490,7 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 		if node.PartitionByTable != nil {
 			ctx.FormatNode(node.PartitionByTable)
 		}
-		// No storage parameters are implemented, so we never list the storage
-		// parameters in the output format.
+		node.formatStorageParams(ctx)
 		if node.Locality != nil {
 			ctx.WriteString(" ")
 			ctx.FormatNode(node.Locality)
@@ -1388,6 +1498,14 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 	}
 }
 
+func (node *CreateTable) formatStorageParams(ctx *FmtCtx) {
+	if node.StorageParams != nil {
+		ctx.WriteString(" WITH (")
+		ctx.FormatNode(&node.StorageParams)
+		ctx.WriteByte(')')
+	}
+}
+
 // HoistConstraints finds column check and foreign key constraints defined
 // inline with their columns and makes them table-level constraints, stored in
 // n.Defs. For example, the foreign key constraint in
@@ -1807,6 +1925,8 @@ type CreateView struct {
 	Persistence  Persistence
 	Replace      bool
 	Materialized bool
+	// StorageParams can only be set for materialized views.
+	StorageParams StorageParams
 }
 
 // Format implements the NodeFormatter interface.
@@ -1839,6 +1959,12 @@ func (node *CreateView) Format(ctx *FmtCtx) {
 		ctx.WriteByte(')')
 	}
 
+	if node.StorageParams != nil {
+		ctx.WriteString(" WITH (")
+		ctx.FormatNode(&node.StorageParams)
+		ctx.WriteByte(')')
+	}
+
 	ctx.WriteString(" AS ")
 	ctx.FormatNode(node.AsSource)
 }
@@ -1924,11 +2050,16 @@ type CreateStatsOptions struct {
 	// Note that the timestamp will be moved up during the operation if it gets
 	// too old (in order to avoid problems with TTL expiration).
 	AsOf AsOfClause
+
+	// UsingExtremes collects a partial statistic on the index ranges beyond
+	// the bounds of the existing histogram, and merges it into the existing
+	// statistic.
+	UsingExtremes bool
 }
 
 // Empty returns true if no options were provided.
 func (o *CreateStatsOptions) Empty() bool {
-	return o.Throttling == 0 && o.AsOf.Expr == nil
+	return o.Throttling == 0 && o.AsOf.Expr == nil && !o.UsingExtremes
 }
 
 // Format implements the NodeFormatter interface.
@@ -1950,6 +2081,11 @@ func (o *CreateStatsOptions) Format(ctx *FmtCtx) {
 		ctx.FormatNode(&o.AsOf)
 		sep = " "
 	}
+	if o.UsingExtremes {
+		ctx.WriteString(sep)
+		ctx.WriteString("USING EXTREMES")
+		sep = " "
+	}
 }
 
 // CombineWith combines two options, erroring out if the two options contain
@@ -1967,6 +2103,12 @@ func (o *CreateStatsOptions) CombineWith(other *CreateStatsOptions) error {
 		}
 		o.AsOf = other.AsOf
 	}
+	if other.UsingExtremes {
+		if o.UsingExtremes {
+			return errors.New("USING EXTREMES specified multiple times")
+		}
+		o.UsingExtremes = true
+	}
 	return nil
 }
 
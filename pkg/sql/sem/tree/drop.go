@@ -188,13 +188,19 @@ type DropType struct {
 	Names        []*UnresolvedObjectName
 	IfExists     bool
 	DropBehavior DropBehavior
+	// IsDomain is true if this represents a DROP DOMAIN command.
+	IsDomain bool
 }
 
 var _ Statement = &DropType{}
 
 // Format implements the NodeFormatter interface.
 func (node *DropType) Format(ctx *FmtCtx) {
-	ctx.WriteString("DROP TYPE ")
+	if node.IsDomain {
+		ctx.WriteString("DROP DOMAIN ")
+	} else {
+		ctx.WriteString("DROP TYPE ")
+	}
 	if node.IfExists {
 		ctx.WriteString("IF EXISTS ")
 	}
@@ -47,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -1561,6 +1562,16 @@ var BinOps = map[BinaryOperator]binOpOverload{
 	},
 
 	Mod: {
+		// The % operator of two strings is the trigram similarity operator. It is
+		// type checked into a ComparisonExpr with the TrigramSimilar operator, so
+		// this overload is only used for overload resolution.
+		&BinOp{
+			LeftType:   types.String,
+			RightType:  types.String,
+			ReturnType: types.Bool,
+			Fn:         evalTrigramSimilar,
+			Volatility: VolatilityStable,
+		},
 		&BinOp{
 			LeftType:   types.Int,
 			RightType:  types.Int,
@@ -1685,6 +1696,15 @@ var BinOps = map[BinaryOperator]binOpOverload{
 			},
 			Volatility: VolatilityImmutable,
 		},
+		&BinOp{
+			LeftType:   types.TSVector,
+			RightType:  types.TSVector,
+			ReturnType: types.TSVector,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return NewDTSVector(MustBeDTSVector(left).Concat(MustBeDTSVector(right).TSVector)), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
 	},
 
 	// TODO(pmattis): Check that the shift is valid.
@@ -2117,6 +2137,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeEqFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeEqFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeEqFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeEqFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeEqFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeEqFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2173,6 +2195,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLtFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeLtFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeLtFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeLtFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeLtFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeLtFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeLtFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2229,6 +2253,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLeFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeLeFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeLeFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeLeFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeLeFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeLeFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeLeFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2295,6 +2321,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
 		makeIsFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeIsFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
+		makeIsFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeIsFn(types.TSVector, types.TSVector, VolatilityImmutable),
 		makeIsFn(types.Uuid, types.Uuid, VolatilityLeakProof),
 		makeIsFn(types.VarBit, types.VarBit, VolatilityLeakProof),
 
@@ -2567,8 +2595,47 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 			},
 		)...,
 	),
+
+	TrigramSimilar: {
+		&CmpOp{
+			LeftType:   types.String,
+			RightType:  types.String,
+			Fn:         evalTrigramSimilar,
+			Volatility: VolatilityStable,
+		},
+	},
+
+	TSMatches: {
+		&CmpOp{
+			LeftType:  types.TSVector,
+			RightType: types.TSQuery,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(MustBeDTSQuery(right).Matches(MustBeDTSVector(left).TSVector))), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+		&CmpOp{
+			LeftType:  types.TSQuery,
+			RightType: types.TSVector,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(MustBeDTSQuery(left).Matches(MustBeDTSVector(right).TSVector))), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+	},
 })
 
+// evalTrigramSimilar returns whether the similarity of the trigrams of the two
+// strings is at least the pg_trgm.similarity_threshold session setting.
+func evalTrigramSimilar(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+	var threshold float64
+	if ctx.SessionData != nil {
+		threshold = ctx.SessionData.TrigramSimilarityThreshold
+	}
+	similarity := trigram.Similarity(string(MustBeDString(left)), string(MustBeDString(right)))
+	return MakeDBool(DBool(similarity >= threshold)), nil
+}
+
 const experimentalBox2DClusterSettingName = "sql.spatial.experimental_box2d_comparison_operators.enabled"
 
 var experimentalBox2DClusterSetting = settings.RegisterBoolSetting(
@@ -3915,6 +3982,9 @@ func (expr *ColumnAccessExpr) Eval(ctx *EvalContext) (Datum, error) {
 	if err != nil {
 		return nil, err
 	}
+	if d == DNull {
+		return DNull, nil
+	}
 	return d.(*DTuple).D[expr.ColIndex], nil
 }
 
@@ -4017,9 +4087,14 @@ func (expr *FuncExpr) evalArgs(ctx *EvalContext) (bool, Datums, error) {
 // MaybeWrapError updates non-nil error depending on the FuncExpr to provide
 // more context.
 func (expr *FuncExpr) MaybeWrapError(err error) error {
-	// If we are facing an explicit error, propagate it unchanged.
+	// If we are facing an explicit error, or a violation of a domain constraint
+	// or row-level security policy which is not a user-visible function call,
+	// propagate it unchanged.
 	fName := expr.Func.String()
-	if fName == `crdb_internal.force_error` {
+	switch fName {
+	case `crdb_internal.force_error`,
+		`crdb_internal.check_domain_not_null`, `crdb_internal.check_domain_constraint`,
+		`crdb_internal.check_row_level_security`:
 		return err
 	}
 	// Otherwise, wrap it with context.
@@ -4425,6 +4500,16 @@ func (t *DBox2D) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DTSVector) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DTSQuery) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DGeography) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
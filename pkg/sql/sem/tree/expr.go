@@ -385,6 +385,8 @@ const (
 	JSONSomeExists
 	JSONAllExists
 	Overlaps
+	TSMatches
+	TrigramSimilar
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -436,6 +438,8 @@ var comparisonOpName = [...]string{
 	JSONSomeExists:    "?|",
 	JSONAllExists:     "?&",
 	Overlaps:          "&&",
+	TSMatches:         "@@",
+	TrigramSimilar:    "%",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -1786,6 +1790,8 @@ func (node *DFloat) String() string           { return AsString(node) }
 func (node *DBox2D) String() string           { return AsString(node) }
 func (node *DGeography) String() string       { return AsString(node) }
 func (node *DGeometry) String() string        { return AsString(node) }
+func (node *DTSQuery) String() string         { return AsString(node) }
+func (node *DTSVector) String() string        { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
 func (node *DJSON) String() string            { return AsString(node) }
@@ -0,0 +1,108 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// Merge represents a MERGE statement.
+type Merge struct {
+	With   *With
+	Table  TableExpr
+	Source TableExpr
+	On     Expr
+	Whens  MergeWhens
+}
+
+// Format implements the NodeFormatter interface.
+func (node *Merge) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.With)
+	ctx.WriteString("MERGE INTO ")
+	ctx.FormatNode(node.Table)
+	ctx.WriteString(" USING ")
+	ctx.FormatNode(node.Source)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.On)
+	for _, when := range node.Whens {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(when)
+	}
+}
+
+// MergeWhens represents the list of WHEN clauses of a MERGE statement.
+type MergeWhens []*MergeWhen
+
+// MergeAction is the action taken by a WHEN clause of a MERGE statement.
+type MergeAction int
+
+// The values for MergeAction.
+const (
+	// MergeDoNothing skips the row.
+	MergeDoNothing MergeAction = iota
+	// MergeUpdate updates the matched target row.
+	MergeUpdate
+	// MergeDelete deletes the matched target row.
+	MergeDelete
+	// MergeInsert inserts a new row into the target table.
+	MergeInsert
+)
+
+// MergeWhen represents a WHEN [NOT] MATCHED clause of a MERGE statement.
+type MergeWhen struct {
+	// Matched is true for WHEN MATCHED clauses, which apply to source rows that
+	// join with a target row, and false for WHEN NOT MATCHED clauses, which
+	// apply to source rows that do not.
+	Matched bool
+	// Cond is the optional AND condition of the clause.
+	Cond   Expr
+	Action MergeAction
+	// Exprs are the SET expressions of an UPDATE action.
+	Exprs UpdateExprs
+	// Columns is the optional list of target columns of an INSERT action.
+	Columns NameList
+	// Values are the values of an INSERT action. It is nil for
+	// INSERT DEFAULT VALUES.
+	Values Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeWhen) Format(ctx *FmtCtx) {
+	ctx.WriteString("WHEN ")
+	if !node.Matched {
+		ctx.WriteString("NOT ")
+	}
+	ctx.WriteString("MATCHED")
+	if node.Cond != nil {
+		ctx.WriteString(" AND ")
+		ctx.FormatNode(node.Cond)
+	}
+	ctx.WriteString(" THEN ")
+	switch node.Action {
+	case MergeDoNothing:
+		ctx.WriteString("DO NOTHING")
+	case MergeUpdate:
+		ctx.WriteString("UPDATE SET ")
+		ctx.FormatNode(&node.Exprs)
+	case MergeDelete:
+		ctx.WriteString("DELETE")
+	case MergeInsert:
+		ctx.WriteString("INSERT")
+		if len(node.Columns) > 0 {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.Columns)
+			ctx.WriteByte(')')
+		}
+		if node.Values == nil {
+			ctx.WriteString(" DEFAULT VALUES")
+		} else {
+			ctx.WriteString(" VALUES (")
+			ctx.FormatNode(&node.Values)
+			ctx.WriteByte(')')
+		}
+	}
+}
@@ -55,6 +55,10 @@ func ParseAndRequireString(
 		d, err = ParseDIntervalWithTypeMetadata(s, itm)
 	case types.Box2DFamily:
 		d, err = ParseDBox2D(s)
+	case types.TSQueryFamily:
+		d, err = ParseDTSQuery(s)
+	case types.TSVectorFamily:
+		d, err = ParseDTSVector(s)
 	case types.GeographyFamily:
 		d, err = ParseDGeography(s)
 	case types.GeometryFamily:
@@ -0,0 +1,59 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "github.com/cockroachdb/cockroach/pkg/sql/lex"
+
+// PinPlan represents a PIN PLAN FOR <statement> statement.
+type PinPlan struct {
+	Statement Statement
+}
+
+var _ Statement = &PinPlan{}
+
+// Format implements the NodeFormatter interface.
+func (n *PinPlan) Format(ctx *FmtCtx) {
+	ctx.WriteString("PIN PLAN FOR ")
+	ctx.FormatNode(n.Statement)
+}
+
+// DropPlanBaseline represents a DROP PLAN BASELINE statement. The baseline to
+// drop is identified either by a statement with its fingerprint or by the
+// fingerprint itself.
+type DropPlanBaseline struct {
+	// Statement is set for DROP PLAN BASELINE FOR <statement>.
+	Statement Statement
+	// Fingerprint is set for DROP PLAN BASELINE <fingerprint>.
+	Fingerprint string
+}
+
+var _ Statement = &DropPlanBaseline{}
+
+// Format implements the NodeFormatter interface.
+func (n *DropPlanBaseline) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP PLAN BASELINE ")
+	if n.Statement != nil {
+		ctx.WriteString("FOR ")
+		ctx.FormatNode(n.Statement)
+		return
+	}
+	lex.EncodeSQLStringWithFlags(&ctx.Buffer, n.Fingerprint, ctx.flags.EncodeFlags())
+}
+
+// ShowPlanBaselines represents a SHOW PLAN BASELINES statement.
+type ShowPlanBaselines struct{}
+
+var _ Statement = &ShowPlanBaselines{}
+
+// Format implements the NodeFormatter interface.
+func (n *ShowPlanBaselines) Format(ctx *FmtCtx) {
+	ctx.WriteString("SHOW PLAN BASELINES")
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "github.com/cockroachdb/cockroach/pkg/security"
+
+// PolicyCommand is the command to which a row-level security policy applies.
+type PolicyCommand int
+
+// PolicyCommand values.
+const (
+	PolicyAll PolicyCommand = iota
+	PolicySelect
+	PolicyInsert
+	PolicyUpdate
+	PolicyDelete
+)
+
+var policyCommandName = [...]string{
+	PolicyAll:    "ALL",
+	PolicySelect: "SELECT",
+	PolicyInsert: "INSERT",
+	PolicyUpdate: "UPDATE",
+	PolicyDelete: "DELETE",
+}
+
+func (c PolicyCommand) String() string {
+	return policyCommandName[c]
+}
+
+// CreatePolicy represents a CREATE POLICY statement.
+type CreatePolicy struct {
+	Name    Name
+	Table   *UnresolvedObjectName
+	Command PolicyCommand
+	// Roles is empty if the policy applies to the public role.
+	Roles     []security.SQLUsername
+	Using     Expr
+	WithCheck Expr
+}
+
+var _ Statement = &CreatePolicy{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreatePolicy) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE POLICY ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.Table)
+	if node.Command != PolicyAll {
+		ctx.WriteString(" FOR ")
+		ctx.WriteString(node.Command.String())
+	}
+	if len(node.Roles) > 0 {
+		ctx.WriteString(" TO ")
+		for i, role := range node.Roles {
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatUsername(role)
+		}
+	}
+	if node.Using != nil {
+		ctx.WriteString(" USING (")
+		ctx.FormatNode(node.Using)
+		ctx.WriteByte(')')
+	}
+	if node.WithCheck != nil {
+		ctx.WriteString(" WITH CHECK (")
+		ctx.FormatNode(node.WithCheck)
+		ctx.WriteByte(')')
+	}
+}
+
+// DropPolicy represents a DROP POLICY statement.
+type DropPolicy struct {
+	Name     Name
+	Table    *UnresolvedObjectName
+	IfExists bool
+}
+
+var _ Statement = &DropPolicy{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropPolicy) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP POLICY ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.Table)
+}
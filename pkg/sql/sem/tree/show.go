@@ -681,11 +681,16 @@ const (
 	// ScheduledBackupExecutor is an executor responsible for
 	// the execution of the scheduled backups.
 	ScheduledBackupExecutor
+
+	// ScheduledRowLevelTTLExecutor is an executor responsible for the
+	// deletion of the expired rows of tables with row-level TTL.
+	ScheduledRowLevelTTLExecutor
 )
 
 var scheduleExecutorInternalNames = map[ScheduledJobExecutorType]string{
-	InvalidExecutor:         "unknown-executor",
-	ScheduledBackupExecutor: "scheduled-backup-executor",
+	InvalidExecutor:              "unknown-executor",
+	ScheduledBackupExecutor:      "scheduled-backup-executor",
+	ScheduledRowLevelTTLExecutor: "scheduled-row-level-ttl-executor",
 }
 
 // InternalName returns an internal executor name.
@@ -699,6 +704,8 @@ func (t ScheduledJobExecutorType) UserName() string {
 	switch t {
 	case ScheduledBackupExecutor:
 		return "BACKUP"
+	case ScheduledRowLevelTTLExecutor:
+		return "ROW LEVEL TTL"
 	}
 	return "unsupported-executor"
 }
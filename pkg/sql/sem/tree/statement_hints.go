@@ -0,0 +1,126 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// StatementHints are optimizer hints that are supplied out of band, either in
+// /*+ ... */ comments of a statement or in system.statement_hints, rather than
+// in the statement's table expressions. Tables are referenced by their alias
+// in the statement, or by their unqualified name if they are not aliased.
+type StatementHints struct {
+	// Indexes forces scans of tables to use the given indexes, like the
+	// table@index syntax.
+	Indexes []IndexHint
+	// NoIndexJoins lists the tables that must not be scanned using an index
+	// join, like the table@{NO_INDEX_JOIN} syntax.
+	NoIndexJoins NameList
+	// Leading is the order in which the listed tables must be joined: the first
+	// two tables are joined with each other, and each following table is
+	// joined to the result as the right input.
+	Leading NameList
+	// Joins forces the algorithm used to join sets of tables.
+	Joins []JoinHint
+	// DisabledRules lists the names of optimizer rules that must not be
+	// applied. Rule names are matched case-insensitively.
+	DisabledRules NameList
+}
+
+// IndexHint forces the scans of a table to use an index.
+type IndexHint struct {
+	Table Name
+	Index Name
+}
+
+// JoinHint forces the join of a set of tables to use an algorithm.
+type JoinHint struct {
+	// Algorithm is one of AstHash, AstMerge or AstLookup.
+	Algorithm string
+	// Tables is the set of tables whose join is hinted, regardless of the order
+	// in which they are joined.
+	Tables NameList
+}
+
+// Empty returns true if there are no hints.
+func (h *StatementHints) Empty() bool {
+	return len(h.Indexes) == 0 && len(h.NoIndexJoins) == 0 && len(h.Leading) == 0 &&
+		len(h.Joins) == 0 && len(h.DisabledRules) == 0
+}
+
+// Append adds the hints of other to h. Hints that are already in h take
+// precedence over conflicting hints in other.
+func (h *StatementHints) Append(other *StatementHints) {
+	h.Indexes = append(h.Indexes, other.Indexes...)
+	h.NoIndexJoins = append(h.NoIndexJoins, other.NoIndexJoins...)
+	if len(h.Leading) == 0 {
+		h.Leading = other.Leading
+	}
+	h.Joins = append(h.Joins, other.Joins...)
+	h.DisabledRules = append(h.DisabledRules, other.DisabledRules...)
+}
+
+// IndexFlags returns the flags that the scans of the given table must use, or
+// nil if the hints don't apply to the table.
+func (h *StatementHints) IndexFlags(table Name) *IndexFlags {
+	var flags *IndexFlags
+	for i := range h.Indexes {
+		if h.Indexes[i].Table == table {
+			flags = &IndexFlags{Index: UnrestrictedName(h.Indexes[i].Index)}
+			break
+		}
+	}
+	for _, t := range h.NoIndexJoins {
+		if t == table {
+			if flags == nil {
+				flags = &IndexFlags{}
+			}
+			if flags.Index == "" {
+				flags.NoIndexJoin = true
+			}
+			break
+		}
+	}
+	return flags
+}
+
+// Format implements the NodeFormatter interface.
+func (h *StatementHints) Format(ctx *FmtCtx) {
+	sep := ""
+	formatHint := func(name string, args NameList) {
+		ctx.WriteString(sep)
+		ctx.WriteString(name)
+		ctx.WriteByte('(')
+		for i := range args {
+			if i > 0 {
+				ctx.WriteByte(' ')
+			}
+			ctx.FormatNode(&args[i])
+		}
+		ctx.WriteByte(')')
+		sep = " "
+	}
+	for i := range h.Indexes {
+		formatHint("INDEX", NameList{h.Indexes[i].Table, h.Indexes[i].Index})
+	}
+	if len(h.NoIndexJoins) > 0 {
+		formatHint("NO_INDEX_JOIN", h.NoIndexJoins)
+	}
+	if len(h.Leading) > 0 {
+		formatHint("LEADING", h.Leading)
+	}
+	for i := range h.Joins {
+		formatHint(h.Joins[i].Algorithm+"_JOIN", h.Joins[i].Tables)
+	}
+	if len(h.DisabledRules) > 0 {
+		formatHint("DISABLE_RULE", h.DisabledRules)
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (h *StatementHints) String() string { return AsString(h) }
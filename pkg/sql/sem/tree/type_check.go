@@ -347,6 +347,11 @@ func (expr *BinaryExpr) TypeCheck(
 	}
 
 	binOp := fns[0].(*BinOp)
+	if expr.Operator == Mod && binOp.LeftType.Family() == types.StringFamily {
+		// The % operator of two strings is the trigram similarity comparison.
+		cmp := &ComparisonExpr{Operator: TrigramSimilar, Left: leftTyped, Right: rightTyped}
+		return cmp.TypeCheck(ctx, semaCtx, desired)
+	}
 	if err := semaCtx.checkVolatility(binOp.Volatility); err != nil {
 		return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "%s", expr.Operator)
 	}
@@ -429,6 +434,28 @@ func isCastDeepValid(castFrom, castTo *types.T) (bool, telemetry.Counter, Volati
 	case toFamily == types.EnumFamily && fromFamily == types.EnumFamily:
 		// Casts from ENUM to ENUM type can only succeed if the two enums
 		return castFrom.Equivalent(castTo), sqltelemetry.EnumCastCounter, VolatilityImmutable
+	case toFamily == types.TupleFamily && fromFamily == types.TupleFamily && castTo.UserDefined():
+		// Casts to a composite type can only succeed from an anonymous tuple
+		// whose elements can each be cast to the corresponding element type, or
+		// from the same composite type.
+		if castFrom.UserDefined() {
+			return castFrom.Equivalent(castTo), sqltelemetry.CompositeCastCounter, VolatilityImmutable
+		}
+		fromContents, toContents := castFrom.TupleContents(), castTo.TupleContents()
+		if len(fromContents) != len(toContents) {
+			return false, nil, 0
+		}
+		maxVolatility := VolatilityImmutable
+		for i := range fromContents {
+			ok, _, v := isCastDeepValid(fromContents[i], toContents[i])
+			if !ok {
+				return false, nil, 0
+			}
+			if v > maxVolatility {
+				maxVolatility = v
+			}
+		}
+		return true, sqltelemetry.CompositeCastCounter, maxVolatility
 	}
 
 	cast := lookupCast(fromFamily, toFamily)
@@ -1443,6 +1470,22 @@ func (expr *Tuple) TypeCheck(
 		}
 	}
 	expr.typ = types.MakeLabeledTuple(contents, labels)
+	// A tuple whose elements all have the element types of a desired composite
+	// type takes on that composite type.
+	if desired.Family() == types.TupleFamily && desired.UserDefined() && len(labels) == 0 {
+		if desiredContents := desired.TupleContents(); len(desiredContents) == len(contents) {
+			matches := true
+			for i := range contents {
+				if contents[i].Family() != types.UnknownFamily && !contents[i].Equivalent(desiredContents[i]) {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				expr.typ = desired
+			}
+		}
+	}
 	return expr, nil
 }
 
@@ -1652,6 +1695,18 @@ func (d *DBox2D) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (Typed
 	return d, nil
 }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSVector) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSQuery) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DGeography) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
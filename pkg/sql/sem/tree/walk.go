@@ -749,6 +749,12 @@ func (expr *DInterval) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DBox2D) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DTSVector) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DTSQuery) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DGeography) Walk(_ Visitor) Expr { return expr }
 
@@ -990,6 +996,61 @@ func (stmt *Insert) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+// copyNode makes a copy of this Statement without recursing in any child Statements.
+func (stmt *Merge) copyNode() *Merge {
+	stmtCopy := *stmt
+	stmtCopy.Whens = make(MergeWhens, len(stmt.Whens))
+	for i, w := range stmt.Whens {
+		wCopy := *w
+		wCopy.Exprs = make(UpdateExprs, len(w.Exprs))
+		for j, e := range w.Exprs {
+			eCopy := *e
+			wCopy.Exprs[j] = &eCopy
+		}
+		if w.Values != nil {
+			wCopy.Values = append(Exprs(nil), w.Values...)
+		}
+		stmtCopy.Whens[i] = &wCopy
+	}
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (stmt *Merge) walkStmt(v Visitor) Statement {
+	ret := stmt
+	if e, changed := WalkExpr(v, stmt.On); changed {
+		ret = stmt.copyNode()
+		ret.On = e
+	}
+	for i, w := range stmt.Whens {
+		if w.Cond != nil {
+			if e, changed := WalkExpr(v, w.Cond); changed {
+				if ret == stmt {
+					ret = stmt.copyNode()
+				}
+				ret.Whens[i].Cond = e
+			}
+		}
+		for j, expr := range w.Exprs {
+			if e, changed := WalkExpr(v, expr.Expr); changed {
+				if ret == stmt {
+					ret = stmt.copyNode()
+				}
+				ret.Whens[i].Exprs[j].Expr = e
+			}
+		}
+		for j, expr := range w.Values {
+			if e, changed := WalkExpr(v, expr); changed {
+				if ret == stmt {
+					ret = stmt.copyNode()
+				}
+				ret.Whens[i].Values[j] = e
+			}
+		}
+	}
+	return ret
+}
+
 // copyNode makes a copy of this Statement without recursing in any child Statements.
 func (stmt *CreateTable) copyNode() *CreateTable {
 	stmtCopy := *stmt
@@ -1568,6 +1629,7 @@ var _ walkableStmt = &Backup{}
 var _ walkableStmt = &Delete{}
 var _ walkableStmt = &Explain{}
 var _ walkableStmt = &Insert{}
+var _ walkableStmt = &Merge{}
 var _ walkableStmt = &Import{}
 var _ walkableStmt = &ParenSelect{}
 var _ walkableStmt = &Restore{}
@@ -26,6 +26,9 @@ type InternalExecutorOverride struct {
 	// DatabaseIDToTempSchemaID represents the mapping for temp schemas used which
 	// allows temporary schema resolution by ID.
 	DatabaseIDToTempSchemaID map[uint32]uint32
+	// AllowMaterializedViewMutations allows the query to mutate materialized
+	// views.
+	AllowMaterializedViewMutations bool
 }
 
 // NoSessionDataOverride is the empty InternalExecutorOverride which does not
@@ -175,6 +175,10 @@ type LocalOnlySessionData struct {
 	// OptimizerUseMultiColStats indicates whether we should use multi-column
 	// statistics for cardinality estimation in the optimizer.
 	OptimizerUseMultiColStats bool
+	// OptimizerUseForecasts indicates whether we should use statistics
+	// forecasted from the history of collected statistics for cardinality
+	// estimation in the optimizer.
+	OptimizerUseForecasts bool
 	// LocalityOptimizedSearch indicates that the optimizer will try to plan scans
 	// and lookup joins in which local nodes (i.e., nodes in the gateway region)
 	// are searched for matching rows before remote nodes, in the hope that the
@@ -189,6 +193,15 @@ type LocalOnlySessionData struct {
 	// ZigzagJoinEnabled indicates whether the optimizer should try and plan a
 	// zigzag join.
 	ZigzagJoinEnabled bool
+	// AdaptiveJoinsEnabled indicates whether lookup joins, and hash joins
+	// against a full scan of an index, should be executed as adaptive joins
+	// which choose between a lookup join and a hash join depending on the
+	// actual number of input rows.
+	AdaptiveJoinsEnabled bool
+	// StatementHintsEnabled indicates whether the optimizer should apply the
+	// hints in the /*+ ... */ comments of statements and in
+	// system.statement_hints.
+	StatementHintsEnabled bool
 	// RequireExplicitPrimaryKeys indicates whether CREATE TABLE statements should
 	// error out if no primary key is provided.
 	RequireExplicitPrimaryKeys bool
@@ -239,10 +252,21 @@ type LocalOnlySessionData struct {
 	// NewSchemaChangerMode indicates whether to use the new schema changer.
 	NewSchemaChangerMode NewSchemaChangerMode
 
+	// AllowMaterializedViewMutations indicates whether materialized views can
+	// be mutated. It is only set by the job maintaining the materialized views
+	// refreshed incrementally, which applies the changes of their source
+	// tables to them.
+	AllowMaterializedViewMutations bool
+
 	// EnableStreamReplication indicates whether to allow setting up a replication
 	// stream.
 	EnableStreamReplication bool
 
+	// MultipleActivePortalsEnabled indicates whether portals executed with a
+	// row limit in an explicit transaction may be suspended and interleaved
+	// with the execution of other statements and portals.
+	MultipleActivePortalsEnabled bool
+
 	// SequenceCache stores sequence values which have been cached using the
 	// CACHE sequence option.
 	SequenceCache SequenceCache
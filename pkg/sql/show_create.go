@@ -83,6 +83,11 @@ func ShowCreateTable(
 	f.FormatNode(tn)
 	f.WriteString(" (")
 	for i, col := range desc.PublicColumns() {
+		// Inaccessible columns back expression indexes. They are shown as part
+		// of the index definitions instead.
+		if col.IsInaccessible() {
+			continue
+		}
 		if i != 0 {
 			f.WriteString(",")
 		}
@@ -204,10 +209,18 @@ func ShowCreateTable(
 		return "", err
 	}
 
+	if err := showCreateRowLevelTTL(desc, f); err != nil {
+		return "", err
+	}
+
 	if err := showCreateLocality(desc, f); err != nil {
 		return "", err
 	}
 
+	if err := showPolicies(ctx, tn, desc, &p.RunParams(ctx).p.semaCtx, &f.Buffer); err != nil {
+		return "", err
+	}
+
 	if !displayOptions.IgnoreComments {
 		if err := showComments(tn, desc, selectComment(ctx, p, desc.GetID()), &f.Buffer); err != nil {
 			return "", err
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -201,6 +202,74 @@ func showComments(
 	return nil
 }
 
+// showPolicies prints out the statements that enable row-level security on a
+// table and create its policies.
+func showPolicies(
+	ctx context.Context,
+	tn *tree.TableName,
+	table catalog.TableDescriptor,
+	semaCtx *tree.SemaContext,
+	buf *bytes.Buffer,
+) error {
+	if !table.GetRowLevelSecurity() && len(table.GetPolicies()) == 0 {
+		return nil
+	}
+	f := tree.NewFmtCtx(tree.FmtSimple)
+	if table.GetRowLevelSecurity() {
+		f.WriteString(";\n")
+		f.FormatNode(&tree.AlterTable{
+			Table: tn.ToUnresolvedObjectName(),
+			Cmds:  tree.AlterTableCmds{&tree.AlterTableSetRowLevelSecurity{Enable: true}},
+		})
+	}
+	policies := table.GetPolicies()
+	for i := range policies {
+		policy := &policies[i]
+		stmt := tree.CreatePolicy{
+			Name:  tree.Name(policy.Name),
+			Table: tn.ToUnresolvedObjectName(),
+		}
+		for cmd, descCmd := range policyCommands {
+			if descCmd == policy.Command {
+				stmt.Command = cmd
+			}
+		}
+		// Policies that only apply to the public role are shown without a TO
+		// clause.
+		if len(policy.Roles) != 1 || !policy.Roles[0].Decode().IsPublicRole() {
+			for _, role := range policy.Roles {
+				stmt.Roles = append(stmt.Roles, role.Decode())
+			}
+		}
+		var err error
+		if stmt.Using, err = formatPolicyExpr(ctx, table, policy.UsingExpr, semaCtx); err != nil {
+			return err
+		}
+		if stmt.WithCheck, err = formatPolicyExpr(ctx, table, policy.WithCheckExpr, semaCtx); err != nil {
+			return err
+		}
+		f.WriteString(";\n")
+		f.FormatNode(&stmt)
+	}
+	buf.WriteString(f.CloseAndGetString())
+	return nil
+}
+
+// formatPolicyExpr returns the expression of a policy in the form in which it
+// is displayed to users, or nil if expr is empty.
+func formatPolicyExpr(
+	ctx context.Context, table catalog.TableDescriptor, expr string, semaCtx *tree.SemaContext,
+) (tree.Expr, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	display, err := schemaexpr.FormatExprForDisplay(ctx, table, expr, semaCtx, tree.FmtParsable)
+	if err != nil {
+		return nil, err
+	}
+	return parser.ParseExpr(display)
+}
+
 // showForeignKeyConstraint returns a valid SQL representation of a FOREIGN KEY
 // clause for a given index. If the table's schema name is in the searchPath, then the
 // schema name will not be included in the result.
@@ -261,6 +330,10 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	if fk.Deferrability != descpb.ConstraintDeferrability_NotDeferrable {
+		buf.WriteByte(' ')
+		buf.WriteString(descpb.ConstraintDeferrabilityType[fk.Deferrability].String())
+	}
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
@@ -322,6 +395,44 @@ func showCreateLocality(desc catalog.TableDescriptor, f *tree.FmtCtx) error {
 	return nil
 }
 
+// showCreateRowLevelTTL returns a WITH clause holding the row-level TTL
+// storage parameters of the table, if applicable.
+func showCreateRowLevelTTL(desc catalog.TableDescriptor, f *tree.FmtCtx) error {
+	ttl := desc.GetRowLevelTTL()
+	if ttl == nil {
+		return nil
+	}
+	var params []string
+	if ttl.ExpireAfter != "" {
+		params = append(params, fmt.Sprintf("ttl_expire_after = %s", lex.EscapeSQLString(ttl.ExpireAfter)))
+	} else {
+		col, err := desc.FindColumnWithID(ttl.ExpirationColumnID)
+		if err != nil {
+			return err
+		}
+		params = append(params, fmt.Sprintf("ttl_expiration_column = %s", lex.EscapeSQLString(col.GetName())))
+	}
+	if ttl.SelectBatchSize != 0 {
+		params = append(params, fmt.Sprintf("ttl_select_batch_size = %d", ttl.SelectBatchSize))
+	}
+	if ttl.DeleteBatchSize != 0 {
+		params = append(params, fmt.Sprintf("ttl_delete_batch_size = %d", ttl.DeleteBatchSize))
+	}
+	if ttl.DeleteRateLimit != 0 {
+		params = append(params, fmt.Sprintf("ttl_delete_rate_limit = %d", ttl.DeleteRateLimit))
+	}
+	if ttl.JobCron != "" {
+		params = append(params, fmt.Sprintf("ttl_job_cron = %s", lex.EscapeSQLString(ttl.JobCron)))
+	}
+	if ttl.Pause {
+		params = append(params, "ttl_pause = true")
+	}
+	f.WriteString(" WITH (")
+	f.WriteString(strings.Join(params, ", "))
+	f.WriteString(")")
+	return nil
+}
+
 // showCreateInterleave returns an INTERLEAVE IN PARENT clause for the specified
 // index, if applicable.
 //
@@ -524,6 +635,10 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		if c.Deferrability != descpb.ConstraintDeferrability_NotDeferrable {
+			f.WriteString(" ")
+			f.WriteString(descpb.ConstraintDeferrabilityType[c.Deferrability].String())
+		}
 		if c.IsPartial() {
 			f.WriteString(" WHERE ")
 			pred, err := schemaexpr.FormatExprForDisplay(ctx, desc, c.Predicate, semaCtx, tree.FmtParsable)
@@ -76,13 +76,9 @@ func (p *planner) ShowHistogram(ctx context.Context, n *tree.ShowHistogram) (pla
 
 			v := p.newContainerValuesNode(showHistogramColumns, 0)
 			for _, b := range histogram.Buckets {
-				ed, _, err := rowenc.EncDatumFromBuffer(
-					histogram.ColumnType, descpb.DatumEncoding_ASCENDING_KEY, b.UpperBound,
-				)
-				if err != nil {
-					v.Close(ctx)
-					return nil, err
-				}
+				// The upper bound of a multi-column histogram is a tuple, which is
+				// encoded as several consecutive keys, so the whole buffer is used.
+				ed := rowenc.EncDatumFromEncoded(descpb.DatumEncoding_ASCENDING_KEY, b.UpperBound)
 				row := tree.Datums{
 					tree.NewDString(ed.String(histogram.ColumnType)),
 					tree.NewDInt(tree.DInt(b.NumRange)),
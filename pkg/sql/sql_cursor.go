@@ -0,0 +1,575 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/logtags"
+)
+
+// DeclareCursor implements the DECLARE statement.
+// See https://www.postgresql.org/docs/current/sql-declare.html for details.
+func (p *planner) DeclareCursor(ctx context.Context, s *tree.DeclareCursor) (planNode, error) {
+	if s.Binary {
+		return nil, unimplemented.NewWithIssue(41412, "DECLARE BINARY CURSOR")
+	}
+	if s.Scroll == tree.Scroll {
+		return nil, unimplemented.NewWithIssue(41412, "DECLARE SCROLL CURSOR")
+	}
+	if !s.Hold && p.EvalContext().TxnImplicit {
+		return nil, pgerror.New(pgcode.NoActiveSQLTransaction,
+			"DECLARE CURSOR can only be used in transaction blocks")
+	}
+	if p.sqlCursors.getCursor(s.Name) != nil {
+		return nil, pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", s.Name)
+	}
+	return &declareCursorNode{n: s}, nil
+}
+
+type declareCursorNode struct {
+	n *tree.DeclareCursor
+}
+
+func (n *declareCursorNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	if err := p.checkCursorQuery(ctx, n.n.Select); err != nil {
+		return err
+	}
+
+	// The iterator outlives the DECLARE statement, so it must not use the
+	// statement's context, which is canceled once the statement finishes.
+	itCtx := logtags.WithTags(context.Background(), logtags.FromContext(ctx))
+	rows, err := newPausedQueryIterator(
+		itCtx, p.ExecCfg(), p.SessionData(), p.txn, "sql-cursor",
+		tree.AsStringWithFlags(n.n.Select, tree.FmtParsable),
+	)
+	if err != nil {
+		return err
+	}
+	cursor := &sqlCursor{
+		InternalRows: rows,
+		txn:          p.txn,
+		readSeqNum:   p.txn.GetLeafTxnInputState(ctx).ReadSeqNum,
+		statement:    tree.AsString(n.n),
+		created:      timeutil.Now(),
+		hold:         n.n.Hold,
+	}
+	if err := p.sqlCursors.addCursor(n.n.Name, cursor); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	return nil
+}
+
+func (n *declareCursorNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *declareCursorNode) Values() tree.Datums                 { return nil }
+func (n *declareCursorNode) Close(ctx context.Context)           {}
+
+// newPausedQueryIterator runs a query in the given transaction through an
+// internal executor that uses a copy of the given session data. The rows are
+// produced lazily: the query's execution is paused in between calls to Next on
+// the returned iterator, so that it can be interleaved with other statements
+// in the transaction. The query runs in the given context until the iterator is
+// closed, so the context must outlive the statement that creates the iterator.
+func newPausedQueryIterator(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	sd *sessiondata.SessionData,
+	txn *kv.Txn,
+	opName string,
+	stmt string,
+) (sqlutil.InternalRows, error) {
+	// Distributed execution is disabled so that the query keeps using the root
+	// transaction rather than leaves that would have to be merged back into it
+	// whenever the query is paused.
+	sdCopy := *sd
+	sdCopy.DistSQLMode = sessiondata.DistSQLOff
+	ie := execCfg.DistSQLSrv.SessionBoundInternalExecutorFactory(ctx, &sdCopy)
+	return ie.QueryIteratorEx(ctx, opName, txn, sessiondata.InternalExecutorOverride{}, stmt)
+}
+
+// checkCursorQuery verifies that the query of a DECLARE statement does not
+// modify data. Postgres only permits data-modifying queries in cursors that
+// are not held, and executes them to completion when the cursor is opened; we
+// don't support that.
+func (p *planner) checkCursorQuery(ctx context.Context, sel *tree.Select) error {
+	var f norm.Factory
+	f.Init(p.EvalContext(), &p.optPlanningCtx.catalog)
+	b := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &p.optPlanningCtx.catalog, &f, sel)
+	if err := b.Build(); err != nil {
+		return err
+	}
+	if f.Memo().RootExpr().(memo.RelExpr).Relational().CanMutate {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"DECLARE CURSOR must not contain data-modifying statements")
+	}
+	return nil
+}
+
+// FetchCursor implements the FETCH and MOVE statements. MOVE positions the
+// cursor exactly like FETCH would, but does not return the rows.
+// See https://www.postgresql.org/docs/current/sql-fetch.html for details.
+func (p *planner) FetchCursor(
+	_ context.Context, s *tree.CursorStmt, isMove bool,
+) (planNode, error) {
+	cursor := p.sqlCursors.getCursor(s.Name)
+	if cursor == nil {
+		return nil, pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", s.Name)
+	}
+	errBackward := pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+		"cursor can only scan forward")
+
+	node := &fetchNode{cursor: cursor}
+	if !isMove {
+		node.columns = cursor.Types()
+	}
+	switch s.FetchType {
+	case tree.FetchNormal, tree.FetchRelative:
+		switch {
+		case s.Count < 0:
+			return nil, errBackward
+		case s.Count == 0:
+			node.current = true
+		case s.FetchType == tree.FetchRelative:
+			node.skip, node.limit = s.Count-1, 1
+		default:
+			node.limit = s.Count
+		}
+	case tree.FetchAbsolute, tree.FetchFirst:
+		target := s.Count
+		if s.FetchType == tree.FetchFirst {
+			target = 1
+		}
+		switch {
+		case target > cursor.pos:
+			node.skip, node.limit = target-cursor.pos-1, 1
+		case target == cursor.pos:
+			node.current = true
+		default:
+			return nil, errBackward
+		}
+	case tree.FetchLast:
+		node.last = true
+	case tree.FetchAll:
+		node.limit = math.MaxInt64
+	case tree.FetchBackwardAll:
+		return nil, errBackward
+	}
+	return node, nil
+}
+
+// fetchNode returns the rows of a FETCH or MOVE statement.
+type fetchNode struct {
+	cursor  *sqlCursor
+	columns colinfo.ResultColumns
+
+	// skip is the number of rows the cursor moves past before the rows to
+	// return.
+	skip int64
+	// limit is the maximum number of rows to return after skipping.
+	limit int64
+	// current is set if the row the cursor is positioned on is to be
+	// returned, without moving the cursor.
+	current bool
+	// last is set if the cursor is to be positioned on the last row, which
+	// is then returned.
+	last bool
+
+	// origReadSeqNum is the read sequence number of the cursor's transaction
+	// before the statement started, to be restored once it finishes.
+	origReadSeqNum enginepb.TxnSeq
+	seqNumSet      bool
+
+	row tree.Datums
+}
+
+func (n *fetchNode) startExec(params runParams) error {
+	if txn := n.cursor.txn; txn != nil {
+		// The cursor's query must not observe writes performed by the
+		// transaction after the cursor was declared, so its reads are
+		// performed at the sequence number captured by DECLARE.
+		n.origReadSeqNum = txn.GetLeafTxnInputState(params.ctx).ReadSeqNum
+		if err := txn.SetReadSeqNum(n.cursor.readSeqNum); err != nil {
+			return err
+		}
+		n.seqNumSet = true
+	}
+	return nil
+}
+
+func (n *fetchNode) Next(params runParams) (bool, error) {
+	ctx := params.ctx
+	if n.current {
+		n.current = false
+		n.row = n.cursor.curRow
+		return n.row != nil, nil
+	}
+	for ; n.skip > 0; n.skip-- {
+		if ok, err := n.cursor.next(ctx); !ok || err != nil {
+			return false, err
+		}
+	}
+	if n.last {
+		n.last = false
+		if ok, err := n.cursor.seekLast(ctx); !ok || err != nil {
+			return false, err
+		}
+		n.row = n.cursor.curRow
+		return true, nil
+	}
+	if n.limit <= 0 {
+		return false, nil
+	}
+	n.limit--
+	if ok, err := n.cursor.next(ctx); !ok || err != nil {
+		return false, err
+	}
+	n.row = n.cursor.curRow
+	return true, nil
+}
+
+func (n *fetchNode) Values() tree.Datums { return n.row }
+
+func (n *fetchNode) Close(ctx context.Context) {
+	if n.seqNumSet {
+		if err := n.cursor.txn.SetReadSeqNum(n.origReadSeqNum); err != nil {
+			log.Warningf(ctx, "failed to restore read sequence number: %v", err)
+		}
+	}
+}
+
+// CloseCursor implements the CLOSE statement.
+// See https://www.postgresql.org/docs/current/sql-close.html for details.
+func (p *planner) CloseCursor(ctx context.Context, s *tree.CloseCursor) (planNode, error) {
+	if s.All {
+		p.sqlCursors.closeAll(ctx)
+	} else if err := p.sqlCursors.closeCursor(ctx, s.Name); err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// sqlCursor is a SQL cursor declared in a session.
+type sqlCursor struct {
+	// InternalRows produces the rows of the cursor that have not been fetched
+	// yet.
+	sqlutil.InternalRows
+	// txn is the transaction the cursor's query runs in. It is nil once the
+	// rows of a WITH HOLD cursor have been persisted at commit.
+	txn *kv.Txn
+	// readSeqNum is the read sequence number of txn when the cursor was
+	// declared.
+	readSeqNum enginepb.TxnSeq
+	statement  string
+	created    time.Time
+	hold       bool
+	// committed is set once the transaction that declared the cursor has
+	// committed. Only WITH HOLD cursors survive to this point.
+	committed bool
+
+	// curRow is the row the cursor is positioned on, or nil if the cursor is
+	// positioned before the first row or after the last one.
+	curRow tree.Datums
+	// pos is the 1-based position of the cursor, as in Postgres. It is 0
+	// before the first row and one more than the number of rows after the last
+	// one.
+	pos int64
+	// afterLast is set once the cursor has moved past the last row.
+	afterLast bool
+}
+
+// next moves the cursor to the following row. It returns false once the
+// cursor has moved past the last row.
+func (c *sqlCursor) next(ctx context.Context) (bool, error) {
+	if c.afterLast {
+		return false, nil
+	}
+	ok, err := c.Next(ctx)
+	if err != nil {
+		return false, err
+	}
+	c.pos++
+	if !ok {
+		c.curRow = nil
+		c.afterLast = true
+		return false, nil
+	}
+	c.curRow = c.Cur()
+	return true, nil
+}
+
+// seekLast moves the cursor to the last row. It returns false if there is no
+// such row left to move to.
+func (c *sqlCursor) seekLast(ctx context.Context) (bool, error) {
+	lastRow, lastPos := c.curRow, c.pos
+	for {
+		ok, err := c.next(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			break
+		}
+		lastRow, lastPos = c.curRow, c.pos
+	}
+	if lastRow == nil {
+		return false, nil
+	}
+	// The rows are exhausted, so moving forward from the last row leaves the
+	// cursor after the last row again.
+	c.curRow, c.pos, c.afterLast = lastRow, lastPos, false
+	return true, nil
+}
+
+// sqlCursors gives a planner access to the SQL cursors of its session.
+type sqlCursors interface {
+	// getCursor returns the cursor with the given name, or nil if there is no
+	// such cursor.
+	getCursor(name tree.Name) *sqlCursor
+	// addCursor adds a cursor with the given name. It returns an error if a
+	// cursor with the same name already exists.
+	addCursor(name tree.Name, c *sqlCursor) error
+	// closeCursor closes and removes the cursor with the given name.
+	closeCursor(ctx context.Context, name tree.Name) error
+	// closeAll closes and removes all cursors.
+	closeAll(ctx context.Context)
+	// list returns all cursors, keyed by name.
+	list() map[tree.Name]*sqlCursor
+}
+
+// cursorMap is the sqlCursors implementation used by a connExecutor.
+type cursorMap struct {
+	cursors map[tree.Name]*sqlCursor
+}
+
+var _ sqlCursors = &cursorMap{}
+
+func (c *cursorMap) getCursor(name tree.Name) *sqlCursor {
+	return c.cursors[name]
+}
+
+func (c *cursorMap) addCursor(name tree.Name, cursor *sqlCursor) error {
+	if _, ok := c.cursors[name]; ok {
+		return pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", name)
+	}
+	if c.cursors == nil {
+		c.cursors = make(map[tree.Name]*sqlCursor)
+	}
+	c.cursors[name] = cursor
+	return nil
+}
+
+func (c *cursorMap) closeCursor(ctx context.Context, name tree.Name) error {
+	cursor, ok := c.cursors[name]
+	if !ok {
+		return pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", name)
+	}
+	delete(c.cursors, name)
+	return cursor.Close()
+}
+
+func (c *cursorMap) closeAll(ctx context.Context) {
+	for name := range c.cursors {
+		if err := c.closeCursor(ctx, name); err != nil {
+			log.Warningf(ctx, "error closing cursor %q: %v", name, err)
+		}
+	}
+}
+
+func (c *cursorMap) list() map[tree.Name]*sqlCursor {
+	return c.cursors
+}
+
+// finishTxn closes the cursors that do not survive the end of the current
+// transaction. If the transaction committed, the WITH HOLD cursors declared
+// in it, which have been persisted by persistHeldCursors, are kept.
+func (c *cursorMap) finishTxn(ctx context.Context, committed bool) {
+	for name, cursor := range c.cursors {
+		if cursor.committed {
+			continue
+		}
+		if committed && cursor.hold && cursor.txn == nil {
+			cursor.committed = true
+			continue
+		}
+		if err := c.closeCursor(ctx, name); err != nil {
+			log.Warningf(ctx, "error closing cursor %q: %v", name, err)
+		}
+	}
+}
+
+// persistHeldCursors is called before the current transaction commits. It
+// closes the cursors declared in the transaction without HOLD and reads the
+// remaining rows of the WITH HOLD cursors into row containers that can spill
+// to disk, so that they can be fetched after the transaction is gone.
+func (ex *connExecutor) persistHeldCursors(ctx context.Context) error {
+	cursors := &ex.extraTxnState.sqlCursors
+	for name, cursor := range cursors.cursors {
+		if cursor.committed || cursor.txn == nil {
+			continue
+		}
+		if !cursor.hold {
+			if err := cursors.closeCursor(ctx, name); err != nil {
+				log.Warningf(ctx, "error closing cursor %q: %v", name, err)
+			}
+			continue
+		}
+		if err := ex.persistHeldCursor(ctx, cursor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ex *connExecutor) persistHeldCursor(ctx context.Context, cursor *sqlCursor) error {
+	txn := cursor.txn
+	origReadSeqNum := txn.GetLeafTxnInputState(ctx).ReadSeqNum
+	if err := txn.SetReadSeqNum(cursor.readSeqNum); err != nil {
+		return err
+	}
+	defer func() {
+		if err := txn.SetReadSeqNum(origReadSeqNum); err != nil {
+			log.Warningf(ctx, "failed to restore read sequence number: %v", err)
+		}
+	}()
+
+	// Reading the first row makes the result columns available even if the
+	// cursor hasn't been fetched from yet.
+	ok, err := cursor.Next(ctx)
+	if err != nil {
+		return err
+	}
+	cfg := &ex.server.cfg.DistSQLSrv.ServerConfig
+	held := &heldCursorRows{cols: cursor.Types()}
+	held.types = make([]*types.T, len(held.cols))
+	for i := range held.cols {
+		held.types[i] = held.cols[i].Typ
+	}
+	held.memMonitor = execinfra.NewLimitedMonitor(ctx, ex.sessionMon, cfg, "sql-cursor-limited")
+	held.diskMonitor = execinfra.NewMonitor(ctx, cfg.ParentDiskMonitor, "sql-cursor-disk")
+	held.rows.Init(
+		nil /* ordering */, held.types, &ex.planner.extendedEvalCtx.EvalContext,
+		cfg.TempStorage, held.memMonitor, held.diskMonitor,
+	)
+	row := make(rowenc.EncDatumRow, len(held.types))
+	for ; ok; ok, err = cursor.Next(ctx) {
+		for i, d := range cursor.Cur() {
+			row[i] = rowenc.DatumToEncDatum(held.types[i], d)
+		}
+		if err = held.rows.AddRow(ctx, row); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		_ = held.Close()
+		return err
+	}
+	if err := cursor.InternalRows.Close(); err != nil {
+		_ = held.Close()
+		return err
+	}
+	cursor.InternalRows = held
+	cursor.txn = nil
+	return nil
+}
+
+// heldCursorRows implements sqlutil.InternalRows over the rows of a WITH HOLD
+// cursor that were persisted when its transaction committed.
+type heldCursorRows struct {
+	rows        rowcontainer.DiskBackedRowContainer
+	iter        rowcontainer.RowIterator
+	cols        colinfo.ResultColumns
+	types       []*types.T
+	memMonitor  *mon.BytesMonitor
+	diskMonitor *mon.BytesMonitor
+	alloc       rowenc.DatumAlloc
+	cur         tree.Datums
+	done        bool
+}
+
+var _ sqlutil.InternalRows = &heldCursorRows{}
+
+// Next is part of the sqlutil.InternalRows interface.
+func (h *heldCursorRows) Next(ctx context.Context) (bool, error) {
+	if h.done {
+		return false, nil
+	}
+	if h.iter == nil {
+		h.iter = h.rows.NewIterator(ctx)
+		h.iter.Rewind()
+	} else {
+		h.iter.Next()
+	}
+	if ok, err := h.iter.Valid(); !ok || err != nil {
+		h.done = true
+		return false, err
+	}
+	row, err := h.iter.Row()
+	if err != nil {
+		return false, err
+	}
+	h.cur = make(tree.Datums, len(row))
+	for i := range row {
+		if err := row[i].EnsureDecoded(h.types[i], &h.alloc); err != nil {
+			return false, err
+		}
+		h.cur[i] = row[i].Datum
+	}
+	return true, nil
+}
+
+// Cur is part of the sqlutil.InternalRows interface.
+func (h *heldCursorRows) Cur() tree.Datums {
+	return h.cur
+}
+
+// Close is part of the sqlutil.InternalRows interface.
+func (h *heldCursorRows) Close() error {
+	// The cursor may be closed when its session ends, after any statement
+	// context is gone.
+	ctx := context.Background()
+	if h.iter != nil {
+		h.iter.Close()
+		h.iter = nil
+	}
+	h.rows.Close(ctx)
+	h.memMonitor.Stop(ctx)
+	h.diskMonitor.Stop(ctx)
+	h.done = true
+	return nil
+}
+
+// Types is part of the sqlutil.InternalRows interface.
+func (h *heldCursorRows) Types() colinfo.ResultColumns {
+	return h.cols
+}
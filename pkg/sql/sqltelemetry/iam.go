@@ -97,6 +97,13 @@ func IncIAMRevokePrivilegesCounter(on string) {
 		fmt.Sprintf("%s.%s.%s.%s", iamRoles, "revoke", "privileges", on)))
 }
 
+// IncIAMAlterDefaultPrivilegesCounter is to be incremented every time an
+// ALTER DEFAULT PRIVILEGES happens.
+func IncIAMAlterDefaultPrivilegesCounter(on string) {
+	telemetry.Inc(telemetry.GetCounter(
+		fmt.Sprintf("%s.%s.%s", iamRoles, "alter_default_privileges", on)))
+}
+
 // TurnConnAuditingOnUseCounter counts how many time connection audit logs were enabled.
 var TurnConnAuditingOnUseCounter = telemetry.GetCounterOnce("auditing.connection.enabled")
 
@@ -56,6 +56,8 @@ const (
 	Schedules
 	// FullTableScans represents the SHOW FULL TABLE SCANS command.
 	FullTableScans
+	// PlanBaselines represents the SHOW PLAN BASELINES command.
+	PlanBaselines
 )
 
 var showTelemetryNameMap = map[ShowTelemetryType]string{
@@ -76,6 +78,7 @@ var showTelemetryNameMap = map[ShowTelemetryType]string{
 	Roles:                   "roles",
 	Schedules:               "schedules",
 	FullTableScans:          "full_table_scans",
+	PlanBaselines:           "plan_baselines",
 }
 
 func (s ShowTelemetryType) String() string {
@@ -0,0 +1,100 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sqlutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// StartPoller starts an async task that calls poll right away and then every
+// time the duration set by the interval setting elapses, until the stopper
+// quiesces. Setting the interval to a non-positive value stops the polling
+// until it is set again. It is meant for components that keep an in-memory
+// view of a system table and refresh it periodically.
+//
+// Errors returned by poll are logged, using what to describe what is being
+// polled for.
+func StartPoller(
+	ctx context.Context,
+	stopper *stop.Stopper,
+	taskName string,
+	sv *settings.Values,
+	interval *settings.DurationSetting,
+	what string,
+	poll func(ctx context.Context) error,
+) {
+	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
+	// NB: The only error that should occur here would be if the server were
+	// shutting down so let's swallow it.
+	_ = stopper.RunAsyncTask(ctx, taskName, func(ctx context.Context) {
+		runPoller(ctx, sv, interval, what, poll)
+	})
+}
+
+func runPoller(
+	ctx context.Context,
+	sv *settings.Values,
+	interval *settings.DurationSetting,
+	what string,
+	poll func(ctx context.Context) error,
+) {
+	var (
+		timer           timeutil.Timer
+		lastPoll        time.Time
+		deadline        time.Time
+		intervalChanged = make(chan struct{}, 1)
+		maybeResetTimer = func() {
+			if interval := interval.Get(sv); interval <= 0 {
+				// Setting the interval to a non-positive value stops the polling.
+				timer.Stop()
+			} else {
+				newDeadline := lastPoll.Add(interval)
+				if deadline.IsZero() || !deadline.Equal(newDeadline) {
+					deadline = newDeadline
+					timer.Reset(timeutil.Until(deadline))
+				}
+			}
+		}
+		doPoll = func() {
+			if err := poll(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Warningf(ctx, "error polling for %s: %s", what, err)
+			}
+			lastPoll = timeutil.Now()
+		}
+	)
+	interval.SetOnChange(sv, func() {
+		select {
+		case intervalChanged <- struct{}{}:
+		default:
+		}
+	})
+	for {
+		maybeResetTimer()
+		select {
+		case <-intervalChanged:
+			continue // go back around and maybe reset the timer
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			return
+		}
+		doPoll()
+	}
+}
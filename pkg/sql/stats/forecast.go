@@ -0,0 +1,565 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stats
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+)
+
+const (
+	// ForecastStatsName is the name of statistics forecasted from the history
+	// of collected statistics. Forecasts are never persisted; they only live in
+	// the TableStatisticsCache.
+	ForecastStatsName = "__forecast__"
+
+	// minObservationsForForecast is the minimum number of collected statistics
+	// on a set of columns needed to forecast a statistic on those columns.
+	minObservationsForForecast = 3
+
+	// minGoodnessOfFit is the minimum coefficient of determination (R²) a
+	// linear model of a quantity must reach for the quantity to be forecasted.
+	// Quantities which do not change linearly over time keep their most
+	// recently observed value in the forecast.
+	minGoodnessOfFit = 0.95
+)
+
+// IsForecast returns true if the statistic was forecasted from the history of
+// collected statistics rather than collected.
+func (ts *TableStatistic) IsForecast() bool {
+	return ts.Name == ForecastStatsName
+}
+
+// ForecastTableStatistics forecasts a statistic for each set of columns with at
+// least minObservationsForForecast collected statistics, by fitting a linear
+// model of the row count, distinct count, null count and histogram of the
+// statistics over time. The given statistics must be ordered by CreatedAt
+// (newest-to-oldest), and the forecasts are returned in the same order.
+//
+// The statistic of each set of columns is forecasted at the time its next
+// refresh is expected: the time of the latest collection plus the average time
+// between the collections. No forecast is returned for a set of columns if the
+// forecast would not differ from the latest collected statistic.
+func ForecastTableStatistics(ctx context.Context, observed []*TableStatistic) []*TableStatistic {
+	// Group the statistics by their set of columns, keeping the groups in the
+	// order of their latest statistic.
+	var groups [][]*TableStatistic
+	for _, stat := range observed {
+		if stat.IsForecast() {
+			continue
+		}
+		found := false
+		for i := range groups {
+			if areEqual(groups[i][0].ColumnIDs, stat.ColumnIDs) {
+				groups[i] = append(groups[i], stat)
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, []*TableStatistic{stat})
+		}
+	}
+
+	var forecasts []*TableStatistic
+	for _, group := range groups {
+		if len(group) < minObservationsForForecast {
+			continue
+		}
+		if forecast := forecastColumnStatistic(ctx, group); forecast != nil {
+			forecasts = append(forecasts, forecast)
+		}
+	}
+	sort.SliceStable(forecasts, func(i, j int) bool {
+		return forecasts[i].CreatedAt.After(forecasts[j].CreatedAt)
+	})
+	return forecasts
+}
+
+// forecastColumnStatistic forecasts a statistic from the given statistics on
+// the same set of columns, ordered by CreatedAt (newest-to-oldest). It returns
+// nil if no quantity of the statistics follows a linear trend.
+func forecastColumnStatistic(ctx context.Context, observed []*TableStatistic) *TableStatistic {
+	latest := observed[0]
+	oldest := observed[len(observed)-1]
+	if !latest.CreatedAt.After(oldest.CreatedAt) {
+		return nil
+	}
+	avgRefresh := latest.CreatedAt.Sub(oldest.CreatedAt) / time.Duration(len(observed)-1)
+	at := latest.CreatedAt.Add(avgRefresh)
+
+	// The time of each observation is measured in seconds relative to the
+	// latest observation, so the forecast is made at x = avgRefresh.
+	xs := make([]float64, len(observed))
+	for i, stat := range observed {
+		xs[i] = stat.CreatedAt.Sub(latest.CreatedAt).Seconds()
+	}
+	x := avgRefresh.Seconds()
+	forecastQuantity := func(quantity func(stat *TableStatistic) uint64) float64 {
+		ys := make([]float64, len(observed))
+		for i, stat := range observed {
+			ys[i] = float64(quantity(stat))
+		}
+		if fit := fitLinear(xs, ys); fit.goodnessOfFit() >= minGoodnessOfFit {
+			return math.Max(fit.predict(x), 0)
+		}
+		return ys[0]
+	}
+	rowCount := forecastQuantity(func(stat *TableStatistic) uint64 { return stat.RowCount })
+	nullCount := forecastQuantity(func(stat *TableStatistic) uint64 { return stat.NullCount })
+	distinctCount := forecastQuantity(func(stat *TableStatistic) uint64 { return stat.DistinctCount })
+
+	// Make the forecasted counts consistent with each other.
+	rowCount = math.Round(rowCount)
+	nullCount = math.Min(math.Round(nullCount), rowCount)
+	nonNullRows := rowCount - nullCount
+	maxDistinct := nonNullRows
+	if nullCount > 0 {
+		// NULL is counted as a distinct value.
+		maxDistinct++
+	}
+	distinctCount = math.Min(math.Round(distinctCount), maxDistinct)
+	if nonNullRows > 0 {
+		distinctCount = math.Max(distinctCount, 1)
+	}
+
+	forecast := &TableStatistic{
+		TableStatisticProto: TableStatisticProto{
+			TableID:       latest.TableID,
+			Name:          ForecastStatsName,
+			ColumnIDs:     latest.ColumnIDs,
+			CreatedAt:     at,
+			RowCount:      uint64(rowCount),
+			DistinctCount: uint64(distinctCount),
+			NullCount:     uint64(nullCount),
+		},
+	}
+
+	histogramChanged := false
+	if latest.Histogram != nil {
+		nonNullDistinct := distinctCount
+		if nullCount > 0 {
+			nonNullDistinct--
+		}
+		var buckets []cat.HistogramBucket
+		buckets, histogramChanged = forecastHistogram(observed, xs, x, nonNullRows, nonNullDistinct)
+		if nullCount > 0 {
+			// As in the statistics cache, a fake bucket is added for NULL values.
+			forecast.Histogram = append(forecast.Histogram, cat.HistogramBucket{
+				NumEq:      nullCount,
+				UpperBound: tree.DNull,
+			})
+		}
+		forecast.Histogram = append(forecast.Histogram, buckets...)
+	}
+
+	if !histogramChanged && forecast.RowCount == latest.RowCount &&
+		forecast.DistinctCount == latest.DistinctCount && forecast.NullCount == latest.NullCount {
+		return nil
+	}
+	log.VEventf(ctx, 2, "forecasted statistic on columns %v of table %d: %d rows, %d distinct, %d nulls",
+		forecast.ColumnIDs, forecast.TableID, forecast.RowCount, forecast.DistinctCount, forecast.NullCount,
+	)
+	return forecast
+}
+
+// linearFit is a linear model y = meanY + slope*(x - meanX) of a set of points,
+// fitted with the method of least squares.
+type linearFit struct {
+	meanX, meanY, slope float64
+	// ssRes and ssTot are the residual and total sums of squares of the points.
+	ssRes, ssTot float64
+}
+
+// fitLinear fits a linear model to the given points. The xs must not all be
+// equal.
+func fitLinear(xs, ys []float64) linearFit {
+	var fit linearFit
+	for i := range xs {
+		fit.meanX += xs[i]
+		fit.meanY += ys[i]
+	}
+	fit.meanX /= float64(len(xs))
+	fit.meanY /= float64(len(ys))
+
+	var sxx, sxy float64
+	for i := range xs {
+		dx, dy := xs[i]-fit.meanX, ys[i]-fit.meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		fit.ssTot += dy * dy
+	}
+	fit.slope = sxy / sxx
+	fit.ssRes = math.Max(fit.ssTot-fit.slope*sxy, 0)
+	return fit
+}
+
+// predict returns the value of the model at x.
+func (fit linearFit) predict(x float64) float64 {
+	return fit.meanY + fit.slope*(x-fit.meanX)
+}
+
+// goodnessOfFit returns the coefficient of determination (R²) of the model.
+func (fit linearFit) goodnessOfFit() float64 {
+	return goodnessOfFit(fit.ssRes, fit.ssTot)
+}
+
+// goodnessOfFit returns the coefficient of determination given the residual
+// and total sums of squares. Constant quantities are fitted perfectly.
+func goodnessOfFit(ssRes, ssTot float64) float64 {
+	if ssTot == 0 {
+		return 1
+	}
+	return 1 - ssRes/ssTot
+}
+
+// forecastHistogram forecasts the histogram (without the NULL bucket) of a
+// statistic from the histograms of the observed statistics, measured at the
+// given times xs, by fitting a linear model of their quantile functions over
+// time. This captures histograms whose buckets shift over time, e.g. the
+// histogram of a column with increasing timestamps.
+//
+// If the histograms cannot be converted to quantile functions (because of the
+// type of the column, or because some of the statistics do not have a
+// histogram), or if the quantile functions do not follow a linear trend, the
+// latest histogram is scaled to the forecasted counts instead. The returned
+// boolean indicates whether the forecasted shape of the histogram differs from
+// the latest histogram.
+func forecastHistogram(
+	observed []*TableStatistic, xs []float64, x float64, nonNullRows, nonNullDistinct float64,
+) (_ []cat.HistogramBucket, changed bool) {
+	latest := observed[0]
+	if nonNullRows == 0 {
+		return nil, false
+	}
+	typ := latest.HistogramData.ColumnType
+	qs := make([]quantile, len(observed))
+	ok := typ != nil && canMakeQuantile(typ)
+	for i := 0; ok && i < len(observed); i++ {
+		stat := observed[i]
+		if stat.HistogramData == nil || stat.HistogramData.ColumnType == nil ||
+			!stat.HistogramData.ColumnType.Equivalent(typ) {
+			ok = false
+			break
+		}
+		qs[i], ok = makeQuantile(nonNullBuckets(stat.Histogram))
+	}
+	if ok {
+		if q, ok, changed := predictQuantile(qs, xs, x); ok {
+			if h := q.toHistogram(typ, nonNullRows, nonNullDistinct); len(h) > 0 {
+				return h, changed
+			}
+		}
+	}
+	return scaleHistogram(nonNullBuckets(latest.Histogram), nonNullRows, nonNullDistinct), false
+}
+
+// nonNullBuckets returns the given histogram without the fake NULL bucket.
+func nonNullBuckets(h []cat.HistogramBucket) []cat.HistogramBucket {
+	if len(h) > 0 && h[0].UpperBound == tree.DNull {
+		return h[1:]
+	}
+	return h
+}
+
+// scaleHistogram returns a copy of the given histogram with the counts scaled
+// to the given number of rows and distinct values.
+func scaleHistogram(
+	h []cat.HistogramBucket, nonNullRows, nonNullDistinct float64,
+) []cat.HistogramBucket {
+	var rows, distinct float64
+	for i := range h {
+		rows += h[i].NumEq + h[i].NumRange
+		distinct += h[i].DistinctRange
+		if h[i].NumEq > 0 {
+			distinct++
+		}
+	}
+	if rows == 0 {
+		return nil
+	}
+	rowScale := nonNullRows / rows
+	distinctScale := 1.0
+	if distinct > 0 {
+		distinctScale = nonNullDistinct / distinct
+	}
+	res := make([]cat.HistogramBucket, len(h))
+	for i := range h {
+		res[i] = cat.HistogramBucket{
+			NumEq:         h[i].NumEq * rowScale,
+			NumRange:      h[i].NumRange * rowScale,
+			DistinctRange: math.Min(h[i].DistinctRange*distinctScale, h[i].NumRange*rowScale),
+			UpperBound:    h[i].UpperBound,
+		}
+	}
+	return res
+}
+
+// quantile is a quantile function of the non-NULL values of a column: a
+// piecewise linear function from the fraction of rows p in [0, 1] to the value
+// v below which that fraction of rows lies. The values are the values of the
+// column converted to float64 (see canMakeQuantile). The points are ordered by
+// p, and both p and v are non-decreasing.
+type quantile []quantilePoint
+
+type quantilePoint struct {
+	p, v float64
+}
+
+// canMakeQuantile returns true if the values of the given type can be
+// converted to and from the values of a quantile function.
+func canMakeQuantile(typ *types.T) bool {
+	switch typ.Family() {
+	case types.IntFamily, types.FloatFamily, types.DateFamily,
+		types.TimestampFamily, types.TimestampTZFamily:
+		return true
+	}
+	return false
+}
+
+// makeQuantile converts a histogram without the NULL bucket to a quantile
+// function. Within a bucket, the values in the range of the bucket are assumed
+// to be distributed uniformly.
+func makeQuantile(h []cat.HistogramBucket) (_ quantile, ok bool) {
+	var total float64
+	for i := range h {
+		total += h[i].NumEq + h[i].NumRange
+	}
+	if total == 0 {
+		return nil, false
+	}
+	q := make(quantile, 0, 2*len(h))
+	var cum float64
+	for i := range h {
+		v, ok := datumToQuantileValue(h[i].UpperBound)
+		if !ok {
+			return nil, false
+		}
+		if i == 0 {
+			// The range of the first bucket is unknown, so all its values are
+			// assumed to be equal to its upper bound.
+			q = append(q, quantilePoint{p: 0, v: v})
+			cum += h[i].NumRange + h[i].NumEq
+			q = append(q, quantilePoint{p: cum / total, v: v})
+			continue
+		}
+		cum += h[i].NumRange
+		q = append(q, quantilePoint{p: cum / total, v: v})
+		if h[i].NumEq > 0 {
+			cum += h[i].NumEq
+			q = append(q, quantilePoint{p: cum / total, v: v})
+		}
+	}
+	q[len(q)-1].p = 1
+	return q, true
+}
+
+// eval returns the value of the quantile function at p.
+func (q quantile) eval(p float64) float64 {
+	i := sort.Search(len(q), func(i int) bool { return q[i].p >= p })
+	if i == 0 {
+		return q[0].v
+	}
+	if i == len(q) {
+		return q[len(q)-1].v
+	}
+	lo, hi := q[i-1], q[i]
+	if hi.p == lo.p {
+		return hi.v
+	}
+	return lo.v + (hi.v-lo.v)*(p-lo.p)/(hi.p-lo.p)
+}
+
+// predictQuantile fits a linear model over time of the value of the given
+// quantile functions at each point of any of them, and returns the quantile
+// function the model predicts at x. It returns ok=false if the models do not
+// fit the quantile functions well enough overall. The returned boolean changed
+// indicates whether the prediction differs from the first quantile function.
+func predictQuantile(
+	qs []quantile, xs []float64, x float64,
+) (_ quantile, ok bool, changed bool) {
+	var ps []float64
+	for _, q := range qs {
+		for _, pt := range q {
+			ps = append(ps, pt.p)
+		}
+	}
+	sort.Float64s(ps)
+	n := 0
+	for i := range ps {
+		if i == 0 || ps[i] != ps[n-1] {
+			ps[n] = ps[i]
+			n++
+		}
+	}
+	ps = ps[:n]
+
+	res := make(quantile, len(ps))
+	ys := make([]float64, len(qs))
+	var ssRes, ssTot float64
+	for i, p := range ps {
+		for j, q := range qs {
+			ys[j] = q.eval(p)
+		}
+		fit := fitLinear(xs, ys)
+		ssRes += fit.ssRes
+		ssTot += fit.ssTot
+		res[i] = quantilePoint{p: p, v: fit.predict(x)}
+		// The values of a quantile function cannot decrease.
+		if i > 0 && res[i].v < res[i-1].v {
+			res[i].v = res[i-1].v
+		}
+		if res[i].v != ys[0] {
+			changed = true
+		}
+	}
+	if goodnessOfFit(ssRes, ssTot) < minGoodnessOfFit {
+		return nil, false, false
+	}
+	return res, true, changed
+}
+
+// toHistogram converts the quantile function to a histogram on a column of the
+// given type with the given number of non-NULL rows and distinct values.
+func (q quantile) toHistogram(
+	typ *types.T, nonNullRows, nonNullDistinct float64,
+) []cat.HistogramBucket {
+	discrete := typ.Family() == types.IntFamily || typ.Family() == types.DateFamily
+	h := make([]cat.HistogramBucket, 0, len(q))
+	vals := make([]float64, 0, len(q))
+	for i, pt := range q {
+		v := pt.v
+		if discrete || typ.Family() == types.TimestampFamily || typ.Family() == types.TimestampTZFamily {
+			v = math.Round(v)
+		}
+		if i > 0 && v == vals[len(vals)-1] {
+			// The rows between the previous point and this one are all equal to
+			// the upper bound of the last bucket.
+			h[len(h)-1].NumEq += (pt.p - q[i-1].p) * nonNullRows
+			continue
+		}
+		d, ok := quantileValueToDatum(typ, v)
+		if !ok {
+			// The value is out of the range of the type, so the rest of the
+			// quantile function is dropped.
+			break
+		}
+		var numRange float64
+		if i > 0 {
+			numRange = (pt.p - q[i-1].p) * nonNullRows
+		}
+		h = append(h, cat.HistogramBucket{NumRange: numRange, UpperBound: d})
+		vals = append(vals, v)
+	}
+
+	if discrete {
+		// The values in the range of a bucket are assumed to be distributed
+		// uniformly, including its upper bound.
+		for i := 1; i < len(h); i++ {
+			if h[i].NumEq == 0 && h[i].NumRange > 0 {
+				h[i].NumEq = h[i].NumRange / (vals[i] - vals[i-1])
+				h[i].NumRange -= h[i].NumEq
+			}
+		}
+	}
+
+	// Distribute the distinct values which are not upper bounds among the
+	// ranges of the buckets, proportionally to the number of rows in them.
+	var rangeRows float64
+	distinctRange := nonNullDistinct
+	for i := range h {
+		rangeRows += h[i].NumRange
+		if h[i].NumEq > 0 {
+			distinctRange--
+		}
+	}
+	if distinctRange > 0 && rangeRows > 0 {
+		for i := range h {
+			d := distinctRange * h[i].NumRange / rangeRows
+			d = math.Min(d, h[i].NumRange)
+			if discrete && i > 0 {
+				d = math.Min(d, vals[i]-vals[i-1]-1)
+			}
+			h[i].DistinctRange = math.Max(d, 0)
+		}
+	}
+	return h
+}
+
+// datumToQuantileValue converts a datum of one of the types accepted by
+// canMakeQuantile to a value of a quantile function.
+func datumToQuantileValue(d tree.Datum) (_ float64, ok bool) {
+	switch t := d.(type) {
+	case *tree.DInt:
+		return float64(*t), true
+	case *tree.DFloat:
+		f := float64(*t)
+		return f, !math.IsNaN(f) && !math.IsInf(f, 0)
+	case *tree.DDate:
+		if !t.IsFinite() {
+			return 0, false
+		}
+		return float64(t.UnixEpochDays()), true
+	case *tree.DTimestamp:
+		return float64(timeutil.ToUnixMicros(t.Time)), true
+	case *tree.DTimestampTZ:
+		return float64(timeutil.ToUnixMicros(t.Time)), true
+	}
+	return 0, false
+}
+
+// quantileValueToDatum converts a (rounded) value of a quantile function back
+// to a datum of the given type. It returns ok=false if the value is out of the
+// range of the type.
+func quantileValueToDatum(typ *types.T, v float64) (_ tree.Datum, ok bool) {
+	switch typ.Family() {
+	case types.IntFamily:
+		width := typ.Width()
+		if width == 0 {
+			width = 64
+		}
+		bound := math.Ldexp(1, int(width)-1)
+		if v < -bound || v >= bound {
+			return nil, false
+		}
+		return tree.NewDInt(tree.DInt(v)), true
+	case types.FloatFamily:
+		return tree.NewDFloat(tree.DFloat(v)), true
+	case types.DateFamily:
+		date, err := pgdate.MakeDateFromUnixEpoch(int64(v))
+		if err != nil || !date.IsFinite() {
+			return nil, false
+		}
+		return tree.NewDDate(date), true
+	case types.TimestampFamily:
+		d, err := tree.MakeDTimestamp(timeutil.FromUnixMicros(int64(v)), time.Microsecond)
+		if err != nil {
+			return nil, false
+		}
+		return d, true
+	case types.TimestampTZFamily:
+		d, err := tree.MakeDTimestampTZ(timeutil.FromUnixMicros(int64(v)), time.Microsecond)
+		if err != nil {
+			return nil, false
+		}
+		return d, true
+	}
+	return nil, false
+}
@@ -0,0 +1,258 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stats
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestForecastTableStatistics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	t0 := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// makeIntStat returns a statistic on column 1 of a table with the values
+	// [0, n) and the given number of NULLs, collected at t0 plus the given
+	// number of hours.
+	makeIntStat := func(hours, n, nulls int) *TableStatistic {
+		stat := &TableStatistic{
+			TableStatisticProto: TableStatisticProto{
+				TableID:       100,
+				Name:          AutoStatsName,
+				ColumnIDs:     []descpb.ColumnID{1},
+				CreatedAt:     t0.Add(time.Duration(hours) * time.Hour),
+				RowCount:      uint64(n + nulls),
+				DistinctCount: uint64(n),
+				NullCount:     uint64(nulls),
+				HistogramData: &HistogramData{ColumnType: types.Int},
+			},
+		}
+		if nulls > 0 {
+			stat.DistinctCount++
+			stat.Histogram = append(stat.Histogram, cat.HistogramBucket{
+				NumEq: float64(nulls), UpperBound: tree.DNull,
+			})
+		}
+		stat.Histogram = append(stat.Histogram,
+			cat.HistogramBucket{NumEq: 1, UpperBound: tree.NewDInt(0)},
+			cat.HistogramBucket{
+				NumEq:         1,
+				NumRange:      float64(n - 2),
+				DistinctRange: float64(n - 2),
+				UpperBound:    tree.NewDInt(tree.DInt(n - 1)),
+			},
+		)
+		return stat
+	}
+
+	// makeStringStat returns a statistic on column 2 of a table with n rows and
+	// n distinct values, collected at t0 plus the given number of hours.
+	makeStringStat := func(hours, n int) *TableStatistic {
+		return &TableStatistic{
+			TableStatisticProto: TableStatisticProto{
+				TableID:       100,
+				Name:          AutoStatsName,
+				ColumnIDs:     []descpb.ColumnID{2},
+				CreatedAt:     t0.Add(time.Duration(hours) * time.Hour),
+				RowCount:      uint64(n),
+				DistinctCount: uint64(n),
+				HistogramData: &HistogramData{ColumnType: types.String},
+			},
+			Histogram: []cat.HistogramBucket{
+				{NumEq: 1, UpperBound: tree.NewDString("a")},
+				{
+					NumEq:         1,
+					NumRange:      float64(n - 2),
+					DistinctRange: float64(n - 2),
+					UpperBound:    tree.NewDString("z"),
+				},
+			},
+		}
+	}
+
+	type expected struct {
+		columnID                       descpb.ColumnID
+		hours                          int
+		rowCount, distinctCount, nulls uint64
+		// lower and upper are the first and last non-NULL upper bounds of the
+		// histogram.
+		lower, upper tree.Datum
+	}
+
+	testCases := []struct {
+		name     string
+		observed []*TableStatistic
+		expected []expected
+	}{
+		{
+			name: "too few observations",
+			observed: []*TableStatistic{
+				makeIntStat(1, 200, 0),
+				makeIntStat(0, 100, 0),
+			},
+		},
+		{
+			name: "no change",
+			observed: []*TableStatistic{
+				makeIntStat(2, 100, 0),
+				makeIntStat(1, 100, 0),
+				makeIntStat(0, 100, 0),
+			},
+		},
+		{
+			name: "no trend",
+			observed: []*TableStatistic{
+				makeIntStat(2, 100, 0),
+				makeIntStat(1, 500, 0),
+				makeIntStat(0, 200, 0),
+			},
+		},
+		{
+			name: "linear growth",
+			observed: []*TableStatistic{
+				makeIntStat(2, 300, 0),
+				makeIntStat(1, 200, 0),
+				makeIntStat(0, 100, 0),
+			},
+			expected: []expected{{
+				columnID: 1, hours: 3, rowCount: 400, distinctCount: 400,
+				lower: tree.NewDInt(0), upper: tree.NewDInt(399),
+			}},
+		},
+		{
+			name: "irregular collections",
+			observed: []*TableStatistic{
+				makeIntStat(4, 500, 10),
+				makeIntStat(3, 400, 10),
+				makeIntStat(0, 100, 10),
+			},
+			expected: []expected{{
+				columnID: 1, hours: 6, rowCount: 710, distinctCount: 701, nulls: 10,
+				lower: tree.NewDInt(0), upper: tree.NewDInt(699),
+			}},
+		},
+		{
+			name: "scaled histogram",
+			observed: []*TableStatistic{
+				makeStringStat(2, 300),
+				makeIntStat(2, 100, 0),
+				makeStringStat(1, 200),
+				makeIntStat(1, 100, 0),
+				makeStringStat(0, 100),
+				makeIntStat(0, 100, 0),
+			},
+			expected: []expected{{
+				columnID: 2, hours: 3, rowCount: 400, distinctCount: 400,
+				lower: tree.NewDString("a"), upper: tree.NewDString("z"),
+			}},
+		},
+		{
+			name: "multiple column sets",
+			observed: []*TableStatistic{
+				makeStringStat(8, 300),
+				makeIntStat(6, 300, 0),
+				makeStringStat(4, 200),
+				makeIntStat(3, 200, 0),
+				makeStringStat(0, 100),
+				makeIntStat(0, 100, 0),
+			},
+			expected: []expected{
+				{
+					columnID: 2, hours: 12, rowCount: 400, distinctCount: 400,
+					lower: tree.NewDString("a"), upper: tree.NewDString("z"),
+				},
+				{
+					columnID: 1, hours: 9, rowCount: 400, distinctCount: 400,
+					lower: tree.NewDInt(0), upper: tree.NewDInt(399),
+				},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			forecasts := ForecastTableStatistics(ctx, tc.observed)
+			if len(forecasts) != len(tc.expected) {
+				t.Fatalf("expected %d forecasts, found %d", len(tc.expected), len(forecasts))
+			}
+			for i, f := range forecasts {
+				exp := tc.expected[i]
+				if !f.IsForecast() {
+					t.Errorf("forecast %d is not named %s", i, ForecastStatsName)
+				}
+				if len(f.ColumnIDs) != 1 || f.ColumnIDs[0] != exp.columnID {
+					t.Errorf("forecast %d: expected column %d, found %v", i, exp.columnID, f.ColumnIDs)
+				}
+				if at := t0.Add(time.Duration(exp.hours) * time.Hour); !f.CreatedAt.Equal(at) {
+					t.Errorf("forecast %d: expected forecast at %s, found %s", i, at, f.CreatedAt)
+				}
+				if f.RowCount != exp.rowCount || f.DistinctCount != exp.distinctCount ||
+					f.NullCount != exp.nulls {
+					t.Errorf("forecast %d: expected %d rows, %d distinct, %d nulls; found %d, %d, %d",
+						i, exp.rowCount, exp.distinctCount, exp.nulls,
+						f.RowCount, f.DistinctCount, f.NullCount,
+					)
+				}
+
+				h := f.Histogram
+				if exp.nulls > 0 {
+					if len(h) == 0 || h[0].UpperBound != tree.DNull || h[0].NumEq != float64(exp.nulls) {
+						t.Fatalf("forecast %d: expected a NULL bucket with %d rows", i, exp.nulls)
+					}
+					h = h[1:]
+				}
+				if len(h) < 2 {
+					t.Fatalf("forecast %d: expected at least 2 buckets, found %d", i, len(h))
+				}
+				if h[0].UpperBound.String() != exp.lower.String() ||
+					h[len(h)-1].UpperBound.String() != exp.upper.String() {
+					t.Errorf("forecast %d: expected histogram on [%s, %s], found [%s, %s]",
+						i, exp.lower, exp.upper, h[0].UpperBound, h[len(h)-1].UpperBound,
+					)
+				}
+				var rows, distinct float64
+				for j := range h {
+					rows += h[j].NumEq + h[j].NumRange
+					distinct += h[j].DistinctRange
+					if h[j].NumEq > 0 {
+						distinct++
+					}
+				}
+				nonNullRows := float64(exp.rowCount - exp.nulls)
+				if math.Abs(rows-nonNullRows) > 1e-6 {
+					t.Errorf("forecast %d: expected %f rows in histogram, found %f", i, nonNullRows, rows)
+				}
+				nonNullDistinct := float64(exp.distinctCount)
+				if exp.nulls > 0 {
+					nonNullDistinct--
+				}
+				// The distinct values are approximated by the buckets, which
+				// can only hold as many distinct values as there are integers in
+				// their ranges.
+				if math.Abs(distinct-nonNullDistinct) > 0.01*nonNullDistinct {
+					t.Errorf("forecast %d: expected %f distinct values in histogram, found %f",
+						i, nonNullDistinct, distinct,
+					)
+				}
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@
 package stats
 
 import (
+	"context"
 	"math"
 	"sort"
 
@@ -30,6 +31,15 @@ var HistogramClusterMode = settings.RegisterBoolSetting(
 	true,
 ).WithPublic()
 
+// MultiColumnHistogramClusterMode controls the cluster setting for enabling
+// collection of histograms on multi-column statistics over index prefixes.
+// The upper bounds of these histograms are tuples.
+var MultiColumnHistogramClusterMode = settings.RegisterBoolSetting(
+	"sql.stats.multi_column_histogram_collection.enabled",
+	"multi-column histogram collection mode",
+	false,
+).WithPublic()
+
 // EquiDepthHistogram creates a histogram where each bucket contains roughly
 // the same number of samples (though it can vary when a boundary value has
 // high frequency).
@@ -181,6 +191,42 @@ func maxDistinctValuesInRange(lowerBound, upperBound tree.Datum) (_ float64, ok
 	}
 }
 
+// histogramTypeIsUserDefined returns true if the histogram column type is, or
+// contains, a user defined type.
+func histogramTypeIsUserDefined(typ *types.T) bool {
+	if typ.Family() == types.TupleFamily {
+		for _, t := range typ.TupleContents() {
+			if t.UserDefined() {
+				return true
+			}
+		}
+		return false
+	}
+	return typ.UserDefined()
+}
+
+// hydrateHistogramColumnType resolves the user defined types in a histogram
+// column type. The elements of the tuple type of a multi-column histogram are
+// resolved individually.
+func hydrateHistogramColumnType(
+	ctx context.Context, typ *types.T, resolver tree.TypeReferenceResolver,
+) (*types.T, error) {
+	if typ.Family() == types.TupleFamily {
+		contents := make([]*types.T, len(typ.TupleContents()))
+		for i, t := range typ.TupleContents() {
+			var err error
+			if contents[i], err = hydrateHistogramColumnType(ctx, t, resolver); err != nil {
+				return nil, err
+			}
+		}
+		return types.MakeTuple(contents), nil
+	}
+	if !typ.UserDefined() {
+		return typ, nil
+	}
+	return resolver.ResolveTypeByOID(ctx, typ.Oid())
+}
+
 func getNextLowerBound(evalCtx *tree.EvalContext, currentUpperBound tree.Datum) tree.Datum {
 	nextLowerBound, ok := currentUpperBound.Next(evalCtx)
 	if !ok {
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"reflect"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -243,4 +244,53 @@ func TestEquiDepthHistogram(t *testing.T) {
 			t.Fatal("expected error")
 		}
 	})
+
+	t.Run("multi-column", func(t *testing.T) {
+		typ := types.MakeTuple([]*types.T{types.String, types.Int})
+		tuple := func(country string, city int) tree.Datum {
+			return tree.NewDTuple(typ, tree.NewDString(country), tree.NewDInt(tree.DInt(city)))
+		}
+		samples := tree.Datums{
+			tuple("US", 1), tuple("FR", 2), tuple("FR", 1), tuple("US", 1),
+			tree.NewDTuple(typ, tree.DNull, tree.NewDInt(3)), tuple("DE", 5),
+		}
+		h, err := EquiDepthHistogram(
+			evalCtx, typ, samples, 60 /* numRows */, 5 /* distinctCount */, 3, /* maxBuckets */
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The histogram must survive a round trip through its JSON
+		// representation.
+		var js JSONStatistic
+		if err := js.SetHistogram(&h); err != nil {
+			t.Fatal(err)
+		}
+		if js.HistogramColumnType != "(STRING, INT8)" {
+			t.Errorf("incorrect column type %s", js.HistogramColumnType)
+		}
+		expUpper := []string{
+			"(NULL:::STRING, 3:::INT8)",
+			"('FR':::STRING, 1:::INT8)",
+			"('US':::STRING, 1:::INT8)",
+		}
+		if len(js.HistogramBuckets) != len(expUpper) {
+			t.Fatalf("expected %d buckets, found %d", len(expUpper), len(js.HistogramBuckets))
+		}
+		for i := range expUpper {
+			if js.HistogramBuckets[i].UpperBound != expUpper[i] {
+				t.Errorf("bucket %d: incorrect upper bound %s, expected %s",
+					i, js.HistogramBuckets[i].UpperBound, expUpper[i])
+			}
+		}
+		semaCtx := tree.MakeSemaContext()
+		h2, err := js.GetHistogram(&semaCtx, evalCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(h.Buckets, h2.Buckets) {
+			t.Errorf("expected buckets %v, found %v", h.Buckets, h2.Buckets)
+		}
+	})
 }
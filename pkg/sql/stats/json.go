@@ -13,6 +13,7 @@ package stats
 import (
 	"context"
 	fmt "fmt"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
@@ -35,7 +36,7 @@ type JSONStatistic struct {
 	NullCount     uint64   `json:"null_count"`
 	// HistogramColumnType is the string representation of the column type for the
 	// histogram (or unset if there is no histogram). Parsable with
-	// tree.GetTypeFromValidSQLSyntax.
+	// ParseHistogramColumnType.
 	HistogramColumnType string            `json:"histo_col_type"`
 	HistogramBuckets    []JSONHistoBucket `json:"histo_buckets,omitempty"`
 }
@@ -49,7 +50,8 @@ type JSONHistoBucket struct {
 	NumRange      int64   `json:"num_range"`
 	DistinctRange float64 `json:"distinct_range"`
 	// UpperBound is the string representation of a datum; parsable with
-	// sqlbase.ParseDatumStringAs.
+	// rowenc.ParseDatumStringAs. The upper bounds of multi-column histograms
+	// are tuples.
 	UpperBound string `json:"upper_bound"`
 }
 
@@ -59,7 +61,7 @@ func (js *JSONStatistic) SetHistogram(h *HistogramData) error {
 	if typ == nil {
 		return fmt.Errorf("histogram type is unset")
 	}
-	js.HistogramColumnType = typ.SQLString()
+	js.HistogramColumnType = histogramColumnTypeString(typ)
 	js.HistogramBuckets = make([]JSONHistoBucket, len(h.Buckets))
 	var a rowenc.DatumAlloc
 	for i := range h.Buckets {
@@ -76,11 +78,17 @@ func (js *JSONStatistic) SetHistogram(h *HistogramData) error {
 			return err
 		}
 
+		// Tuple elements are formatted as SQL literals so that the tuple can be
+		// parsed back.
+		flags := tree.FmtExport
+		if typ.Family() == types.TupleFamily {
+			flags = tree.FmtParsable
+		}
 		js.HistogramBuckets[i] = JSONHistoBucket{
 			NumEq:         b.NumEq,
 			NumRange:      b.NumRange,
 			DistinctRange: b.DistinctRange,
-			UpperBound:    tree.AsStringWithFlags(datum, tree.FmtExport),
+			UpperBound:    tree.AsStringWithFlags(datum, flags),
 		}
 	}
 	return nil
@@ -107,12 +115,12 @@ func (js *JSONStatistic) DecodeAndSetHistogram(
 	}
 	// If the serialized column type is user defined, then it needs to be
 	// hydrated before use.
-	if h.ColumnType.UserDefined() {
+	if histogramTypeIsUserDefined(h.ColumnType) {
 		resolver := semaCtx.GetTypeResolver()
 		if resolver == nil {
 			return errors.AssertionFailedf("attempt to resolve user defined type with nil TypeResolver")
 		}
-		typ, err := resolver.ResolveTypeByOID(ctx, h.ColumnType.Oid())
+		typ, err := hydrateHistogramColumnType(ctx, h.ColumnType, resolver)
 		if err != nil {
 			return err
 		}
@@ -129,11 +137,9 @@ func (js *JSONStatistic) GetHistogram(
 		return nil, nil
 	}
 	h := &HistogramData{}
-	colTypeRef, err := parser.GetTypeFromValidSQLSyntax(js.HistogramColumnType)
-	if err != nil {
-		return nil, err
-	}
-	colType, err := tree.ResolveType(evalCtx.Context, colTypeRef, semaCtx.GetTypeResolver())
+	colType, err := ParseHistogramColumnType(
+		evalCtx.Context, js.HistogramColumnType, semaCtx.GetTypeResolver(),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -155,3 +161,77 @@ func (js *JSONStatistic) GetHistogram(
 	}
 	return h, nil
 }
+
+// histogramColumnTypeString returns the string representation of a histogram
+// column type. Multi-column histograms have a tuple type, which has no SQL
+// syntax, so it is represented as the parenthesized list of the element
+// types, e.g. "(STRING, INT8)".
+func histogramColumnTypeString(typ *types.T) string {
+	if typ.Family() != types.TupleFamily {
+		return typ.SQLString()
+	}
+	var buf strings.Builder
+	buf.WriteByte('(')
+	for i, t := range typ.TupleContents() {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(t.SQLString())
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// ParseHistogramColumnType parses the string representation of a histogram
+// column type (see JSONStatistic.HistogramColumnType). The resolver is used to
+// resolve user defined types, and may be nil if there are none.
+func ParseHistogramColumnType(
+	ctx context.Context, s string, resolver tree.TypeReferenceResolver,
+) (*types.T, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		typRef, err := parser.GetTypeFromValidSQLSyntax(s)
+		if err != nil {
+			return nil, err
+		}
+		return tree.ResolveType(ctx, typRef, resolver)
+	}
+
+	// Split the element types on the commas that are not nested inside
+	// parentheses (as in DECIMAL(10,2)) or quotes.
+	var contents []*types.T
+	inner := s[1 : len(s)-1]
+	depth, start, quoted := 0, 0, false
+	for i := 0; i <= len(inner); i++ {
+		if i < len(inner) {
+			switch inner[i] {
+			case '"':
+				quoted = !quoted
+				continue
+			case '(':
+				if !quoted {
+					depth++
+				}
+				continue
+			case ')':
+				if !quoted {
+					depth--
+				}
+				continue
+			case ',':
+				if quoted || depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		typ, err := ParseHistogramColumnType(ctx, inner[start:i], resolver)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, typ)
+		start = i + 1
+	}
+	return types.MakeTuple(contents), nil
+}
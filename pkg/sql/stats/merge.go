@@ -0,0 +1,93 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stats
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+// GetTableStatistic reads the statistic with the given ID from the
+// system.table_statistics table. The column type of the histogram (if any) is
+// returned as stored, without hydrating user-defined types.
+func GetTableStatistic(
+	ctx context.Context,
+	executor sqlutil.InternalExecutor,
+	txn *kv.Txn,
+	tableID descpb.ID,
+	statisticID uint64,
+) (*TableStatisticProto, error) {
+	row, err := executor.QueryRowEx(
+		ctx, "get-table-statistic", txn,
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT "columnIDs", "rowCount", "distinctCount", "nullCount", histogram
+               FROM system.table_statistics
+               WHERE "tableID" = $1 AND "statisticID" = $2`,
+		tableID,
+		statisticID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, errors.Errorf("statistic %d for table %d not found", statisticID, tableID)
+	}
+
+	res := &TableStatisticProto{
+		TableID:       tableID,
+		StatisticID:   statisticID,
+		RowCount:      uint64(*row[1].(*tree.DInt)),
+		DistinctCount: uint64(*row[2].(*tree.DInt)),
+		NullCount:     uint64(*row[3].(*tree.DInt)),
+	}
+	columnIDs := row[0].(*tree.DArray)
+	res.ColumnIDs = make([]descpb.ColumnID, len(columnIDs.Array))
+	for i, d := range columnIDs.Array {
+		res.ColumnIDs[i] = descpb.ColumnID(*d.(*tree.DInt))
+	}
+	if row[4] != tree.DNull {
+		res.HistogramData = &HistogramData{}
+		if err := protoutil.Unmarshal([]byte(*row[4].(*tree.DBytes)), res.HistogramData); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// MergeHistogramsAtExtremes returns a histogram over the values of full plus
+// the values of lower and upper, which must be histograms on values that are
+// strictly less than the first upper bound of full and strictly greater than
+// the last upper bound of full, respectively. Either of lower and upper may be
+// empty.
+//
+// Since the first bucket of an equi-depth histogram never has any values in
+// its range, the buckets can simply be concatenated: the range of the first
+// bucket of full (and of upper) does not overlap with the values of the
+// preceding histogram.
+func MergeHistogramsAtExtremes(lower, full, upper *HistogramData) HistogramData {
+	res := HistogramData{
+		ColumnType: full.ColumnType,
+		Buckets: make(
+			[]HistogramData_Bucket, 0, len(lower.Buckets)+len(full.Buckets)+len(upper.Buckets),
+		),
+	}
+	res.Buckets = append(res.Buckets, lower.Buckets...)
+	res.Buckets = append(res.Buckets, full.Buckets...)
+	res.Buckets = append(res.Buckets, upper.Buckets...)
+	return res
+}
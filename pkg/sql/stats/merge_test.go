@@ -0,0 +1,88 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stats
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestMergeHistogramsAtExtremes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	makeHist := func(vals ...int) HistogramData {
+		samples := make(tree.Datums, len(vals))
+		for i, v := range vals {
+			samples[i] = tree.NewDInt(tree.DInt(v))
+		}
+		h, err := EquiDepthHistogram(
+			evalCtx, types.Int, samples, int64(len(vals)), int64(len(vals)), 10, /* maxBuckets */
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	testCases := []struct {
+		lower, full, upper []int
+		expected           []int
+	}{
+		{
+			lower:    []int{-3, -1},
+			full:     []int{1, 5, 9},
+			upper:    []int{10, 12},
+			expected: []int{-3, -1, 1, 5, 9, 10, 12},
+		},
+		{
+			full:     []int{1, 5, 9},
+			upper:    []int{20},
+			expected: []int{1, 5, 9, 20},
+		},
+		{
+			lower:    []int{0},
+			full:     []int{1},
+			expected: []int{0, 1},
+		},
+		{
+			full:     []int{1, 2},
+			expected: []int{1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		lower, full, upper := makeHist(tc.lower...), makeHist(tc.full...), makeHist(tc.upper...)
+		h := MergeHistogramsAtExtremes(&lower, &full, &upper)
+		if !h.ColumnType.Equivalent(types.Int) {
+			t.Errorf("unexpected column type %s", h.ColumnType)
+		}
+		if len(h.Buckets) != len(tc.expected) {
+			t.Fatalf("expected %d buckets, found %d", len(tc.expected), len(h.Buckets))
+		}
+		var a rowenc.DatumAlloc
+		for i, b := range h.Buckets {
+			d, _, err := rowenc.DecodeTableKey(&a, types.Int, b.UpperBound, encoding.Ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if int(*d.(*tree.DInt)) != tc.expected[i] {
+				t.Errorf("bucket %d: expected upper bound %d, found %s", i, tc.expected[i], d)
+			}
+		}
+	}
+}
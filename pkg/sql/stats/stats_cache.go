@@ -100,7 +100,11 @@ type cacheEntry struct {
 	// timestamp was moved, it will trigger another refresh.
 	refreshing bool
 
-	stats []*TableStatistic
+	// stats are ordered by their CreatedAt time (newest-to-oldest). The first
+	// numForecasts of them are forecasted from the others, which were collected
+	// (see ForecastTableStatistics).
+	stats        []*TableStatistic
+	numForecasts int
 
 	// err is populated if the internal query to retrieve stats hit an error.
 	err error
@@ -214,25 +218,44 @@ func decodeTableStatisticsKV(
 // silently ignores any statistics that can't be decoded (e.g. because
 // user-defined types don't exit).
 //
-// The statistics are ordered by their CreatedAt time (newest-to-oldest).
+// The statistics are ordered by their CreatedAt time (newest-to-oldest). They
+// only include collected statistics; see GetTableStatsWithForecasts.
 func (sc *TableStatisticsCache) GetTableStats(
 	ctx context.Context, tableID descpb.ID,
 ) ([]*TableStatistic, error) {
+	stats, numForecasts, err := sc.getTableStats(ctx, tableID)
+	return stats[numForecasts:], err
+}
+
+// GetTableStatsWithForecasts is like GetTableStats, but the result also
+// includes the statistics forecasted from the collected ones, which come first
+// and can be recognized with IsForecast. It is meant for the optimizer;
+// forecasts are never persisted, and they can be dated in the future.
+func (sc *TableStatisticsCache) GetTableStatsWithForecasts(
+	ctx context.Context, tableID descpb.ID,
+) ([]*TableStatistic, error) {
+	stats, _, err := sc.getTableStats(ctx, tableID)
+	return stats, err
+}
+
+func (sc *TableStatisticsCache) getTableStats(
+	ctx context.Context, tableID descpb.ID,
+) (stats []*TableStatistic, numForecasts int, err error) {
 	if descpb.IsReservedID(tableID) {
 		// Don't try to get statistics for system tables (most importantly,
 		// for table_statistics itself).
-		return nil, nil
+		return nil, 0, nil
 	}
 	if descpb.IsVirtualTable(tableID) {
 		// Don't try to get statistics for virtual tables.
-		return nil, nil
+		return nil, 0, nil
 	}
 
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
 
 	if found, e := sc.lookupStatsLocked(ctx, tableID, false /* stealthy */); found {
-		return e.stats, e.err
+		return e.stats, e.numForecasts, e.err
 	}
 
 	return sc.addCacheEntryLocked(ctx, tableID)
@@ -289,7 +312,7 @@ func (sc *TableStatisticsCache) lookupStatsLocked(
 //
 func (sc *TableStatisticsCache) addCacheEntryLocked(
 	ctx context.Context, tableID descpb.ID,
-) (stats []*TableStatistic, err error) {
+) (stats []*TableStatistic, numForecasts int, err error) {
 	// Add a cache entry that other queries can find and wait on until we have the
 	// stats.
 	e := &cacheEntry{
@@ -304,12 +327,12 @@ func (sc *TableStatisticsCache) addCacheEntryLocked(
 		defer sc.mu.Lock()
 
 		log.VEventf(ctx, 1, "reading statistics for table %d", tableID)
-		stats, err = sc.getTableStatsFromDB(ctx, tableID)
+		stats, numForecasts, err = sc.getTableStatsFromDB(ctx, tableID)
 		log.VEventf(ctx, 1, "finished reading statistics for table %d", tableID)
 	}()
 
 	e.mustWait = false
-	e.stats, e.err = stats, err
+	e.stats, e.numForecasts, e.err = stats, numForecasts, err
 
 	// Wake up any other callers that are waiting on these stats.
 	e.waitCond.Broadcast()
@@ -319,7 +342,7 @@ func (sc *TableStatisticsCache) addCacheEntryLocked(
 		sc.mu.cache.Del(tableID)
 	}
 
-	return stats, err
+	return stats, numForecasts, err
 }
 
 // refreshCacheEntry retrieves table statistics from the database and updates
@@ -360,6 +383,7 @@ func (sc *TableStatisticsCache) refreshCacheEntry(
 	e.refreshing = true
 
 	var stats []*TableStatistic
+	var numForecasts int
 	var err error
 	for {
 		func() {
@@ -369,7 +393,7 @@ func (sc *TableStatisticsCache) refreshCacheEntry(
 
 			log.VEventf(ctx, 1, "refreshing statistics for table %d", tableID)
 			// TODO(radu): pass the timestamp and use AS OF SYSTEM TIME.
-			stats, err = sc.getTableStatsFromDB(ctx, tableID)
+			stats, numForecasts, err = sc.getTableStatsFromDB(ctx, tableID)
 			log.VEventf(ctx, 1, "done refreshing statistics for table %d", tableID)
 		}()
 		if e.lastRefreshTimestamp.Equal(ts) {
@@ -379,7 +403,7 @@ func (sc *TableStatisticsCache) refreshCacheEntry(
 		ts = e.lastRefreshTimestamp
 	}
 
-	e.stats, e.err = stats, err
+	e.stats, e.numForecasts, e.err = stats, numForecasts, err
 	e.refreshing = false
 
 	if err != nil {
@@ -492,7 +516,7 @@ func (sc *TableStatisticsCache) parseStats(
 
 		// Hydrate the type in case any user defined types are present.
 		// There are cases where typ is nil, so don't do anything if so.
-		if typ := res.HistogramData.ColumnType; typ != nil && typ.UserDefined() {
+		if typ := res.HistogramData.ColumnType; typ != nil && histogramTypeIsUserDefined(typ) {
 			// The metadata accessed here is never older than the metadata used when
 			// collecting the stats. Changes to types are backwards compatible across
 			// versions, so using a newer version of the type metadata here is safe.
@@ -507,7 +531,7 @@ func (sc *TableStatisticsCache) parseStats(
 				defer collection.ReleaseAll(ctx)
 				resolver := descs.NewDistSQLTypeResolver(collection, txn)
 				var err error
-				res.HistogramData.ColumnType, err = resolver.ResolveTypeByOID(ctx, typ.Oid())
+				res.HistogramData.ColumnType, err = hydrateHistogramColumnType(ctx, typ, resolver)
 				return err
 			})
 			if err != nil {
@@ -559,9 +583,13 @@ func (sc *TableStatisticsCache) parseStats(
 //
 // It ignores any statistics that cannot be decoded (e.g. because a user-defined
 // type that doesn't exist) and returns the rest (with no error).
+//
+// Statistics forecasted from the retrieved statistics are included at the
+// start of the result (see ForecastTableStatistics); numForecasts is their
+// number.
 func (sc *TableStatisticsCache) getTableStatsFromDB(
 	ctx context.Context, tableID descpb.ID,
-) ([]*TableStatistic, error) {
+) (_ []*TableStatistic, numForecasts int, _ error) {
 	const getTableStatisticsStmt = `
 SELECT
   "tableID",
@@ -581,7 +609,7 @@ ORDER BY "createdAt" DESC
 		ctx, "get-table-statistics", nil /* txn */, getTableStatisticsStmt, tableID,
 	)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	var statsList []*TableStatistic
@@ -595,8 +623,10 @@ ORDER BY "createdAt" DESC
 		statsList = append(statsList, stats)
 	}
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return statsList, nil
+	// Forecasts are newer than all the collected statistics, so they go first.
+	forecasts := ForecastTableStatistics(ctx, statsList)
+	return append(forecasts, statsList...), len(forecasts), nil
 }
@@ -95,7 +95,8 @@ func lookupTableStats(
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
 	if e, ok := sc.mu.cache.Get(tableID); ok {
-		return e.(*cacheEntry).stats, true
+		entry := e.(*cacheEntry)
+		return entry.stats[entry.numForecasts:], true
 	}
 	return nil, false
 }
@@ -325,6 +326,59 @@ func TestCacheBasic(t *testing.T) {
 	}
 }
 
+// TestCacheForecasts verifies that forecasted statistics are only returned by
+// GetTableStatsWithForecasts.
+func TestCacheForecasts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, _, db := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	ex := s.InternalExecutor().(sqlutil.InternalExecutor)
+
+	tableID := descpb.ID(100)
+	var expected []*TableStatisticProto
+	for i := 0; i < 3; i++ {
+		stat := &TableStatisticProto{
+			TableID:       tableID,
+			StatisticID:   uint64(i),
+			ColumnIDs:     []descpb.ColumnID{1},
+			CreatedAt:     time.Date(2010, 11, 20, 11+i, 0, 0, 0, time.UTC),
+			RowCount:      uint64(100 * (i + 1)),
+			DistinctCount: uint64(100 * (i + 1)),
+		}
+		if err := insertTableStat(ctx, db, ex, stat); err != nil {
+			t.Fatal(err)
+		}
+		// The expected stats are ordered by CreatedAt (newest-to-oldest).
+		expected = append([]*TableStatisticProto{stat}, expected...)
+	}
+
+	sc := NewTableStatisticsCache(
+		ctx,
+		2, /* cacheSize */
+		db,
+		ex,
+		keys.SystemSQLCodec,
+		s.LeaseManager().(*lease.Manager),
+		s.ClusterSettings(),
+		s.RangeFeedFactory().(*rangefeed.Factory),
+	)
+	checkStatsForTable(ctx, t, sc, expected, tableID)
+
+	statsList, err := sc.GetTableStatsWithForecasts(ctx, tableID)
+	if err != nil {
+		t.Fatalf("error retrieving stats: %s", err)
+	}
+	if len(statsList) != len(expected)+1 || !statsList[0].IsForecast() {
+		t.Fatalf("expected a forecast followed by the collected stats, got %s", statsList)
+	}
+	if !checkStats(statsList[1:], expected) {
+		t.Fatalf("expected stats %s, got %s", expected, statsList[1:])
+	}
+}
+
 func TestCacheUserDefinedTypes(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
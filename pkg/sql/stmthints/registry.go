@@ -0,0 +1,118 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package stmthints
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+var pollingInterval = settings.RegisterDurationSetting(
+	"sql.statement_hints.poll_interval",
+	"rate at which the stmthints.Registry polls system.statement_hints, set to zero to disable",
+	10*time.Second,
+)
+
+// Registry maintains a view on the optimizer hints stored for statement
+// fingerprints (i.e. system.statement_hints) and provides utilities to look
+// them up during planning.
+//
+// Hints are written to system.statement_hints directly, with regular SQL
+// statements. Changes are picked up by each node the next time it polls the
+// table.
+type Registry struct {
+	mu struct {
+		syncutil.Mutex
+		// hints maps statement fingerprints to their hints.
+		hints map[string]*tree.StatementHints
+	}
+	st *cluster.Settings
+	ie sqlutil.InternalExecutor
+}
+
+// NewRegistry constructs a new Registry.
+func NewRegistry(ie sqlutil.InternalExecutor, st *cluster.Settings) *Registry {
+	return &Registry{ie: ie, st: st}
+}
+
+// Start will start the polling loop for the Registry.
+func (r *Registry) Start(ctx context.Context, stopper *stop.Stopper) {
+	sqlutil.StartPoller(
+		ctx, stopper, "statement-hints-poll", &r.st.SV, pollingInterval, "statement hints", r.pollHints,
+	)
+}
+
+// HasHints returns true if there are hints for at least one statement
+// fingerprint. It allows callers to avoid fingerprinting statements when there
+// is nothing to look up.
+func (r *Registry) HasHints() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.mu.hints) > 0
+}
+
+// Lookup returns the hints for the given statement fingerprint, if there are
+// any. The returned hints must not be modified.
+func (r *Registry) Lookup(fingerprint string) (_ *tree.StatementHints, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hints, ok := r.mu.hints[fingerprint]
+	return hints, ok
+}
+
+// pollHints reads the rows of system.statement_hints and replaces r.mu.hints
+// accordingly. Rows with hints that can't be parsed are skipped.
+func (r *Registry) pollHints(ctx context.Context) error {
+	if !r.st.Version.IsActive(ctx, clusterversion.StatementHints) {
+		// The table may not exist yet.
+		return nil
+	}
+	it, err := r.ie.QueryIteratorEx(ctx, "statement-hints-poll", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		"SELECT fingerprint, hints FROM system.statement_hints")
+	if err != nil {
+		return err
+	}
+	hints := make(map[string]*tree.StatementHints)
+	var ok bool
+	for ok, err = it.Next(ctx); ok; ok, err = it.Next(ctx) {
+		row := it.Cur()
+		fingerprint := string(tree.MustBeDString(row[0]))
+		h, err := parser.ParseStatementHints(string(tree.MustBeDString(row[1])))
+		if err != nil {
+			log.Warningf(ctx, "ignoring statement hints for %q: %v", fingerprint, err)
+			continue
+		}
+		if !h.Empty() {
+			hints[fingerprint] = h
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.hints = hints
+	return nil
+}
@@ -0,0 +1,320 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package ttljob implements the job which deletes the expired rows of tables
+// with row-level TTL, and the executor of the schedules creating these jobs.
+package ttljob
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	defaultSelectBatchSize = settings.RegisterIntSetting(
+		"sql.ttl.default_select_batch_size",
+		"default number of expired rows to select in each query of the row-level TTL job",
+		500,
+		settings.PositiveInt,
+	).WithPublic()
+	defaultDeleteBatchSize = settings.RegisterIntSetting(
+		"sql.ttl.default_delete_batch_size",
+		"default number of expired rows to delete in each transaction of the row-level TTL job",
+		100,
+		settings.PositiveInt,
+	).WithPublic()
+	defaultDeleteRateLimit = settings.RegisterIntSetting(
+		"sql.ttl.default_delete_rate_limit",
+		"default maximum number of rows deleted per second by the row-level TTL job "+
+			"of a table; 0 means unlimited",
+		0,
+		settings.NonNegativeInt,
+	).WithPublic()
+	jobEnabled = settings.RegisterBoolSetting(
+		"sql.ttl.job.enabled",
+		"whether the row-level TTL job deletes the expired rows of tables",
+		true,
+	).WithPublic()
+)
+
+// defaultAOSTDuration is how far in the past the expired rows are selected, so
+// that the selection does not contend with foreground traffic.
+const defaultAOSTDuration = -time.Second * 30
+
+type rowLevelTTLResumer struct {
+	job *jobs.Job
+	st  *cluster.Settings
+}
+
+var _ jobs.Resumer = (*rowLevelTTLResumer)(nil)
+
+// Resume is part of the jobs.Resumer interface.
+func (t rowLevelTTLResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	if !jobEnabled.Get(&t.st.SV) {
+		return errors.New("row-level TTL jobs are disabled by the sql.ttl.job.enabled cluster setting")
+	}
+
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := t.job.Details().(jobspb.RowLevelTTLDetails)
+
+	var desc catalog.TableDescriptor
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) (err error) {
+		desc, err = catalogkv.MustGetTableDescByID(ctx, txn, execCfg.Codec, details.TableID)
+		return err
+	}); err != nil {
+		if errors.Is(err, catalog.ErrDescriptorNotFound) {
+			// The table has been dropped and garbage collected.
+			return nil
+		}
+		return err
+	}
+	ttl := desc.GetRowLevelTTL()
+	if desc.Dropped() || ttl == nil || ttl.Pause {
+		return nil
+	}
+
+	expirationCol, err := desc.FindColumnWithID(ttl.ExpirationColumnID)
+	if err != nil {
+		return err
+	}
+	primaryIndex := desc.GetPrimaryIndex()
+	pkColumns := make([]string, primaryIndex.NumColumns())
+	for i := range pkColumns {
+		pkColumns[i] = primaryIndex.GetColumnName(i)
+	}
+
+	selectBatchSize := ttl.SelectBatchSize
+	if selectBatchSize == 0 {
+		selectBatchSize = defaultSelectBatchSize.Get(&t.st.SV)
+	}
+	deleteBatchSize := ttl.DeleteBatchSize
+	if deleteBatchSize == 0 {
+		deleteBatchSize = defaultDeleteBatchSize.Get(&t.st.SV)
+	}
+	deleteRateLimit := ttl.DeleteRateLimit
+	if deleteRateLimit == 0 {
+		deleteRateLimit = defaultDeleteRateLimit.Get(&t.st.SV)
+	}
+	var rateLimiter *quotapool.RateLimiter
+	if deleteRateLimit > 0 {
+		rateLimiter = quotapool.NewRateLimiter(
+			"ttl-delete", quotapool.Limit(deleteRateLimit), deleteRateLimit,
+		)
+	}
+	aost := defaultAOSTDuration
+	if knobs := execCfg.TTLTestingKnobs; knobs != nil && knobs.AOSTDuration != nil {
+		aost = *knobs.AOSTDuration
+	}
+
+	cutoff := tree.MustMakeDTimestampTZ(details.Cutoff, time.Microsecond)
+	q := makeQueryBuilder(desc.GetID(), pkColumns, expirationCol.GetName(), aost)
+	metrics := execCfg.JobRegistry.MetricsStruct().RowLevelTTL.(*Metrics)
+	ie := execCfg.InternalExecutor
+
+	var lastRow tree.Datums
+	for {
+		selectArgs := []interface{}{cutoff}
+		for _, d := range lastRow {
+			selectArgs = append(selectArgs, d)
+		}
+		// Select the primary keys of the next batch of expired rows.
+		start := timeutil.Now()
+		rows, err := ie.QueryBufferedEx(
+			ctx,
+			"ttl-select",
+			nil, /* txn */
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			q.selectQuery(lastRow != nil, selectBatchSize),
+			selectArgs...,
+		)
+		if err != nil {
+			return errors.Wrapf(err, "selecting expired rows")
+		}
+		metrics.SelectDuration.RecordValue(timeutil.Since(start).Nanoseconds())
+		metrics.RowsSelected.Inc(int64(len(rows)))
+
+		// Delete the expired rows in batches, each in its own low priority
+		// transaction.
+		for startIdx := 0; startIdx < len(rows); startIdx += int(deleteBatchSize) {
+			endIdx := startIdx + int(deleteBatchSize)
+			if endIdx > len(rows) {
+				endIdx = len(rows)
+			}
+			batch := rows[startIdx:endIdx]
+			if rateLimiter != nil {
+				if err := rateLimiter.WaitN(ctx, int64(len(batch))); err != nil {
+					return err
+				}
+			}
+			deleteArgs := []interface{}{cutoff}
+			for _, row := range batch {
+				for _, d := range row {
+					deleteArgs = append(deleteArgs, d)
+				}
+			}
+			start := timeutil.Now()
+			var numDeleted int
+			if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+				if err := txn.SetUserPriority(roachpb.MinUserPriority); err != nil {
+					return err
+				}
+				var err error
+				numDeleted, err = ie.ExecEx(
+					ctx,
+					"ttl-delete",
+					txn,
+					sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+					q.deleteQuery(len(batch)),
+					deleteArgs...,
+				)
+				return err
+			}); err != nil {
+				return errors.Wrapf(err, "deleting expired rows")
+			}
+			metrics.DeleteDuration.RecordValue(timeutil.Since(start).Nanoseconds())
+			metrics.RowsDeleted.Inc(int64(numDeleted))
+
+			if err := t.job.Update(ctx, nil /* txn */, func(
+				_ *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+			) error {
+				progress := md.Progress
+				progress.GetRowLevelTTL().RowsDeleted += int64(numDeleted)
+				ju.UpdateProgress(progress)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		if int64(len(rows)) < selectBatchSize {
+			break
+		}
+		lastRow = rows[len(rows)-1]
+	}
+	log.Infof(ctx, "row-level TTL job %d finished deleting expired rows of table %d",
+		t.job.ID(), details.TableID)
+	return nil
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (t rowLevelTTLResumer) OnFailOrCancel(ctx context.Context, execCtx interface{}) error {
+	return nil
+}
+
+// queryBuilder builds the queries selecting and deleting the expired rows of
+// a table. The expired rows are selected in primary key order, and each batch
+// is selected after the primary key of the last row of the previous one.
+type queryBuilder struct {
+	tableID       descpb.ID
+	pkColumns     string
+	expirationCol string
+	aost          time.Duration
+	numPKColumns  int
+}
+
+func makeQueryBuilder(
+	tableID descpb.ID, pkColumns []string, expirationCol string, aost time.Duration,
+) queryBuilder {
+	var buf bytes.Buffer
+	for i, col := range pkColumns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		name := tree.Name(col)
+		buf.WriteString(name.String())
+	}
+	name := tree.Name(expirationCol)
+	return queryBuilder{
+		tableID:       tableID,
+		pkColumns:     buf.String(),
+		expirationCol: name.String(),
+		aost:          aost,
+		numPKColumns:  len(pkColumns),
+	}
+}
+
+// placeholders writes a tuple of placeholders for a primary key, starting at
+// the given placeholder index.
+func (q queryBuilder) placeholders(buf *bytes.Buffer, startIdx int) {
+	buf.WriteByte('(')
+	for i := 0; i < q.numPKColumns; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(buf, "$%d", startIdx+i)
+	}
+	buf.WriteByte(')')
+}
+
+// selectQuery returns the query selecting the primary keys of the next batch
+// of expired rows. The cutoff time is the first placeholder, followed by the
+// primary key of the last row of the previous batch if afterLastRow is true.
+func (q queryBuilder) selectQuery(afterLastRow bool, limit int64) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "SELECT %s FROM [%d AS t]", q.pkColumns, q.tableID)
+	if q.aost != 0 {
+		fmt.Fprintf(&buf, " AS OF SYSTEM TIME '%s'", q.aost)
+	}
+	fmt.Fprintf(&buf, " WHERE %s <= $1", q.expirationCol)
+	if afterLastRow {
+		fmt.Fprintf(&buf, " AND (%s) > ", q.pkColumns)
+		q.placeholders(&buf, 2)
+	}
+	fmt.Fprintf(&buf, " ORDER BY %s LIMIT %d", q.pkColumns, limit)
+	return buf.String()
+}
+
+// deleteQuery returns the query deleting the given number of rows. The cutoff
+// time is the first placeholder, followed by the primary keys of the rows. The
+// expiration of the rows is checked again, since it may have been updated
+// since they were selected.
+func (q queryBuilder) deleteQuery(numRows int) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "DELETE FROM [%d AS t] WHERE %s <= $1 AND (%s) IN (",
+		q.tableID, q.expirationCol, q.pkColumns)
+	for i := 0; i < numRows; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		q.placeholders(&buf, 2+i*q.numPKColumns)
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+func init() {
+	jobs.RegisterConstructor(jobspb.TypeRowLevelTTL, func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+		return &rowLevelTTLResumer{
+			job: job,
+			st:  settings,
+		}
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+var (
+	metaRowLevelTTLRowsSelected = metric.Metadata{
+		Name:        "jobs.row_level_ttl.rows_selected",
+		Help:        "Number of expired rows selected for deletion by the row-level TTL job",
+		Measurement: "Rows",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRowLevelTTLRowsDeleted = metric.Metadata{
+		Name:        "jobs.row_level_ttl.rows_deleted",
+		Help:        "Number of expired rows deleted by the row-level TTL job",
+		Measurement: "Rows",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRowLevelTTLSelectDuration = metric.Metadata{
+		Name:        "jobs.row_level_ttl.select_duration",
+		Help:        "Duration of the queries selecting expired rows in the row-level TTL job",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaRowLevelTTLDeleteDuration = metric.Metadata{
+		Name:        "jobs.row_level_ttl.delete_duration",
+		Help:        "Duration of the queries deleting expired rows in the row-level TTL job",
+		Measurement: "Latency",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+)
+
+// Metrics are the metrics of the row-level TTL job.
+type Metrics struct {
+	RowsSelected   *metric.Counter
+	RowsDeleted    *metric.Counter
+	SelectDuration *metric.Histogram
+	DeleteDuration *metric.Histogram
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (*Metrics) MetricStruct() {}
+
+// MakeMetrics makes the metrics of the row-level TTL job.
+func MakeMetrics(histogramWindow time.Duration) metric.Struct {
+	return &Metrics{
+		RowsSelected:   metric.NewCounter(metaRowLevelTTLRowsSelected),
+		RowsDeleted:    metric.NewCounter(metaRowLevelTTLRowsDeleted),
+		SelectDuration: metric.NewLatency(metaRowLevelTTLSelectDuration, histogramWindow),
+		DeleteDuration: metric.NewLatency(metaRowLevelTTLDeleteDuration, histogramWindow),
+	}
+}
+
+func init() {
+	jobs.MakeRowLevelTTLMetricsHook = MakeMetrics
+}
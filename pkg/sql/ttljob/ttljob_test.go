@@ -0,0 +1,97 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/ttljob"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowLevelTTLJob(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	// Select the expired rows at the current time, so that rows inserted by the
+	// test are visible to the job.
+	var aost time.Duration
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			TTL: &sql.TTLTestingKnobs{AOSTDuration: &aost},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	runner.Exec(t, `CREATE DATABASE db`)
+	runner.Exec(t, `
+CREATE TABLE db.t (
+  a INT,
+  b STRING,
+  expire_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (a, b)
+) WITH (ttl_expiration_column = 'expire_at', ttl_select_batch_size = 3, ttl_delete_batch_size = 2)`)
+	runner.Exec(t, `
+INSERT INTO db.t
+SELECT i, i::STRING, now() - '1 hour'::INTERVAL FROM generate_series(1, 10) AS g(i)`)
+	runner.Exec(t, `
+INSERT INTO db.t
+SELECT i, i::STRING, now() + '1 hour'::INTERVAL FROM generate_series(11, 15) AS g(i)`)
+
+	tableDesc := catalogkv.TestingGetTableDescriptor(kvDB, s.ExecutorConfig().(sql.ExecutorConfig).Codec, "db", "t")
+	require.NotNil(t, tableDesc.GetRowLevelTTL())
+
+	registry := s.JobRegistry().(*jobs.Registry)
+	jobID := registry.MakeJobID()
+	record := jobs.Record{
+		Description: "ttl for db.public.t",
+		Username:    security.RootUserName(),
+		Details: jobspb.RowLevelTTLDetails{
+			TableID: tableDesc.GetID(),
+			Cutoff:  timeutil.Now(),
+		},
+		Progress: jobspb.RowLevelTTLProgress{},
+	}
+	_, err := registry.CreateAdoptableJobWithTxn(ctx, record, jobID, nil /* txn */)
+	require.NoError(t, err)
+	require.NoError(t, registry.Run(
+		ctx, s.InternalExecutor().(*sql.InternalExecutor), []jobspb.JobID{jobID},
+	))
+
+	// Only the rows which have not expired remain.
+	runner.CheckQueryResults(t, `SELECT min(a), max(a), count(*) FROM db.t`, [][]string{
+		{"11", "15", "5"},
+	})
+
+	job, err := registry.LoadJob(ctx, jobID)
+	require.NoError(t, err)
+	progress := job.Progress()
+	require.Equal(t, int64(10), progress.GetRowLevelTTL().RowsDeleted)
+
+	metrics := registry.MetricsStruct().RowLevelTTL.(*ttljob.Metrics)
+	require.Equal(t, int64(10), metrics.RowsSelected.Count())
+	require.Equal(t, int64(10), metrics.RowsDeleted.Count())
+}
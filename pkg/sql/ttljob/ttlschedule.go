@@ -0,0 +1,149 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+// rowLevelTTLExecutor is the executor of the schedules of the row-level TTL
+// jobs, which are created along with the tables using row-level TTL.
+type rowLevelTTLExecutor struct {
+	metrics rowLevelTTLScheduleMetrics
+}
+
+type rowLevelTTLScheduleMetrics struct {
+	*jobs.ExecutorMetrics
+}
+
+var _ metric.Struct = &rowLevelTTLScheduleMetrics{}
+
+// MetricStruct implements metric.Struct interface.
+func (m *rowLevelTTLScheduleMetrics) MetricStruct() {}
+
+var _ jobs.ScheduledJobExecutor = &rowLevelTTLExecutor{}
+
+// ExecuteJob implements the jobs.ScheduledJobExecutor interface.
+func (s rowLevelTTLExecutor) ExecuteJob(
+	ctx context.Context,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	if err := s.createJob(ctx, cfg, env, sj, txn); err != nil {
+		s.metrics.NumFailed.Inc(1)
+		return err
+	}
+	s.metrics.NumStarted.Inc(1)
+	return nil
+}
+
+func (s rowLevelTTLExecutor) createJob(
+	ctx context.Context,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	args := &jobspb.ScheduledRowLevelTTLArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return errors.Wrap(err, "un-marshaling args")
+	}
+
+	p, cleanup := cfg.PlanHookMaker("create-row-level-ttl-job", txn, security.RootUserName())
+	defer cleanup()
+	execCfg := p.(sql.PlanHookState).ExecCfg()
+
+	tn, err := p.(sql.PlanHookState).GetQualifiedTableNameByID(
+		ctx, int64(args.TableID), tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return err
+	}
+
+	record := jobs.Record{
+		Description: "ttl for " + tn.FQString(),
+		Username:    security.RootUserName(),
+		Details: jobspb.RowLevelTTLDetails{
+			TableID: args.TableID,
+			Cutoff:  env.Now(),
+		},
+		Progress: jobspb.RowLevelTTLProgress{},
+		CreatedBy: &jobs.CreatedByInfo{
+			Name: jobs.CreatedByScheduledJobs,
+			ID:   sj.ScheduleID(),
+		},
+	}
+	jobID := execCfg.JobRegistry.MakeJobID()
+	if _, err := execCfg.JobRegistry.CreateAdoptableJobWithTxn(ctx, record, jobID, txn); err != nil {
+		return err
+	}
+	log.Infof(ctx, "created row-level TTL job %d for table %d by schedule %d",
+		jobID, args.TableID, sj.ScheduleID())
+	return nil
+}
+
+// NotifyJobTermination implements the jobs.ScheduledJobExecutor interface.
+func (s rowLevelTTLExecutor) NotifyJobTermination(
+	ctx context.Context,
+	jobID jobspb.JobID,
+	jobStatus jobs.Status,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	ex sqlutil.InternalExecutor,
+	txn *kv.Txn,
+) error {
+	if jobStatus == jobs.StatusSucceeded {
+		s.metrics.NumSucceeded.Inc(1)
+		return nil
+	}
+
+	s.metrics.NumFailed.Inc(1)
+	err := errors.Errorf(
+		"row-level TTL job %d scheduled by %d failed with status %s",
+		jobID, sj.ScheduleID(), jobStatus)
+	log.Errorf(ctx, "row-level TTL error: %v", err)
+	jobs.DefaultHandleFailedRun(sj, "row-level TTL job %d failed with err=%v", jobID, err)
+	return nil
+}
+
+// Metrics implements the jobs.ScheduledJobExecutor interface.
+func (s rowLevelTTLExecutor) Metrics() metric.Struct {
+	return &s.metrics
+}
+
+func init() {
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledRowLevelTTLExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			m := jobs.MakeExecutorMetrics(tree.ScheduledRowLevelTTLExecutor.InternalName())
+			return &rowLevelTTLExecutor{
+				metrics: rowLevelTTLScheduleMetrics{
+					ExecutorMetrics: &m,
+				},
+			}, nil
+		})
+}
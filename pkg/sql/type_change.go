@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -418,6 +419,18 @@ func (t *typeSchemaChanger) exec(ctx context.Context) error {
 		}
 	}
 
+	// Validate the CHECK constraints that are being added to a domain against
+	// the existing values of the columns that use it.
+	if typeDesc.GetKind() == descpb.TypeDescriptor_DOMAIN &&
+		domainHasValidatingChecks(typeDesc.TypeDesc().Domain) {
+		if err := t.validateDomainChecks(ctx); err != nil {
+			return err
+		}
+		if err := refreshTypeDescriptorLeases(ctx, leaseMgr, typeDesc); err != nil {
+			return err
+		}
+	}
+
 	// If the type is being dropped, remove the descriptor here.
 	if typeDesc.Dropped() {
 		if err := t.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
@@ -690,6 +703,201 @@ func (t *typeSchemaChanger) cleanupEnumValues(ctx context.Context) error {
 	return nil
 }
 
+// domainHasValidatingChecks returns true if the domain has CHECK constraints
+// that are not yet validated against the existing data.
+func domainHasValidatingChecks(domain *descpb.TypeDescriptor_Domain) bool {
+	if domain == nil {
+		return false
+	}
+	for i := range domain.Checks {
+		if domain.Checks[i].Validity == descpb.ConstraintValidity_Validating {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDomainChecks checks that the existing values of all columns of the
+// domain type satisfy the CHECK constraints being added to the domain, and
+// marks those constraints as validated if they do.
+func (t *typeSchemaChanger) validateDomainChecks(ctx context.Context) error {
+	// The validation is done in a separate txn to the one that mutates the
+	// descriptor, as it can take arbitrarily long.
+	validated := make(map[string]struct{})
+	validate := func(ctx context.Context, txn *kv.Txn, descsCol *descs.Collection) error {
+		typeDesc, err := descsCol.GetMutableTypeVersionByID(ctx, txn, t.typeID)
+		if err != nil {
+			return err
+		}
+		for i := range typeDesc.Domain.Checks {
+			check := &typeDesc.Domain.Checks[i]
+			if check.Validity != descpb.ConstraintValidity_Validating {
+				continue
+			}
+			if err := t.validateDomainCheck(ctx, typeDesc, check, txn, descsCol); err != nil {
+				return err
+			}
+			validated[check.Name] = struct{}{}
+		}
+		return nil
+	}
+	if err := descs.Txn(
+		ctx, t.execCfg.Settings, t.execCfg.LeaseManager,
+		t.execCfg.InternalExecutor, t.execCfg.DB, validate,
+	); err != nil {
+		return err
+	}
+
+	run := func(ctx context.Context, txn *kv.Txn, descsCol *descs.Collection) error {
+		typeDesc, err := descsCol.GetMutableTypeVersionByID(ctx, txn, t.typeID)
+		if err != nil {
+			return err
+		}
+		for i := range typeDesc.Domain.Checks {
+			check := &typeDesc.Domain.Checks[i]
+			if _, ok := validated[check.Name]; ok &&
+				check.Validity == descpb.ConstraintValidity_Validating {
+				check.Validity = descpb.ConstraintValidity_Validated
+			}
+		}
+		b := txn.NewBatch()
+		if err := descsCol.WriteDescToBatch(
+			ctx, true /* kvTrace */, typeDesc, b,
+		); err != nil {
+			return err
+		}
+
+		// The version of the array type needs to get bumped as well so that
+		// changes to the underlying type are picked up.
+		arrayTypeDesc, err := descsCol.GetMutableTypeVersionByID(ctx, txn, typeDesc.ArrayTypeID)
+		if err != nil {
+			return err
+		}
+		if err := descsCol.WriteDescToBatch(
+			ctx, true /* kvTrace */, arrayTypeDesc, b,
+		); err != nil {
+			return err
+		}
+		return txn.Run(ctx, b)
+	}
+	return descs.Txn(
+		ctx, t.execCfg.Settings, t.execCfg.LeaseManager,
+		t.execCfg.InternalExecutor, t.execCfg.DB, run,
+	)
+}
+
+// validateDomainCheck checks that the existing values of all columns of the
+// domain type satisfy the given CHECK constraint of the domain.
+func (t *typeSchemaChanger) validateDomainCheck(
+	ctx context.Context,
+	typeDesc *typedesc.Mutable,
+	check *descpb.TypeDescriptor_Domain_CheckConstraint,
+	txn *kv.Txn,
+	descsCol *descs.Collection,
+) error {
+	const validationErr = "could not validate constraint %q of domain %q"
+	checkExpr, err := parser.ParseExpr(check.Expr)
+	if err != nil {
+		return err
+	}
+	for _, ID := range typeDesc.ReferencingDescriptorIDs {
+		desc, err := descsCol.GetImmutableTableByID(ctx, txn, ID, tree.ObjectLookupFlags{})
+		if err != nil {
+			return errors.Wrapf(err, validationErr, check.Name, typeDesc.Name)
+		}
+		// Views only derive their values from tables, which are validated on
+		// their own.
+		if !desc.IsPhysicalTable() || desc.Dropped() {
+			continue
+		}
+		for _, col := range desc.PublicColumns() {
+			if !col.GetType().UserDefined() || typedesc.GetTypeDescID(col.GetType()) != typeDesc.ID {
+				continue
+			}
+			colExpr, err := schemaexpr.ReplaceDomainValue(
+				checkExpr, &tree.ColumnItem{ColumnName: col.ColName()},
+			)
+			if err != nil {
+				return err
+			}
+			query := fmt.Sprintf(
+				"SELECT 1 FROM [%d AS t] WHERE NOT (%s) LIMIT 1",
+				desc.GetID(), tree.AsStringWithFlags(colExpr, tree.FmtSerializable),
+			)
+
+			// We need to override the internal executor's current database (which
+			// would be unset by default), for the same reason as when validating
+			// the removal of enum values.
+			_, dbDesc, err := descsCol.GetImmutableDatabaseByID(
+				ctx, txn, typeDesc.ParentID, tree.DatabaseLookupFlags{Required: true})
+			if err != nil {
+				return errors.Wrapf(err, validationErr, check.Name, typeDesc.Name)
+			}
+			override := sessiondata.InternalExecutorOverride{
+				User:     security.RootUserName(),
+				Database: dbDesc.GetName(),
+			}
+			rows, err := t.execCfg.InternalExecutor.QueryRowEx(
+				ctx, "validate-domain-check", txn, override, query,
+			)
+			if err != nil {
+				return errors.Wrapf(err, validationErr, check.Name, typeDesc.Name)
+			}
+			if len(rows) > 0 {
+				return pgerror.Newf(pgcode.CheckViolation,
+					"column %q of table %q contains values that violate the new constraint",
+					col.GetName(), desc.GetName())
+			}
+		}
+	}
+	return nil
+}
+
+// cleanupDomainChecks removes the CHECK constraints that were being added to a
+// domain when the type schema change job failed to validate them.
+func (t *typeSchemaChanger) cleanupDomainChecks(ctx context.Context) error {
+	cleanup := func(ctx context.Context, txn *kv.Txn, descsCol *descs.Collection) error {
+		typeDesc, err := descsCol.GetMutableTypeVersionByID(ctx, txn, t.typeID)
+		if err != nil {
+			return err
+		}
+		// No cleanup required.
+		if typeDesc.Kind != descpb.TypeDescriptor_DOMAIN ||
+			!domainHasValidatingChecks(typeDesc.Domain) {
+			return nil
+		}
+
+		checks := typeDesc.Domain.Checks[:0]
+		for _, check := range typeDesc.Domain.Checks {
+			if check.Validity != descpb.ConstraintValidity_Validating {
+				checks = append(checks, check)
+			}
+		}
+		typeDesc.Domain.Checks = checks
+
+		b := txn.NewBatch()
+		if err := descsCol.WriteDescToBatch(
+			ctx, true /* kvTrace */, typeDesc, b,
+		); err != nil {
+			return err
+		}
+		return txn.Run(ctx, b)
+	}
+	if err := descs.Txn(ctx, t.execCfg.Settings, t.execCfg.LeaseManager, t.execCfg.InternalExecutor,
+		t.execCfg.DB, cleanup); err != nil {
+		return err
+	}
+
+	// Finally, make sure all of the leases are updated.
+	if err := WaitToUpdateLeases(ctx, t.execCfg.LeaseManager, t.typeID); err != nil {
+		if errors.Is(err, catalog.ErrDescriptorNotFound) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
 // convertToSQLStringRepresentation takes an array of bytes (the physical
 // representation of an enum) and converts it into a string that can be used
 // in a SQL predicate.
@@ -1102,6 +1310,10 @@ func (t *typeChangeResumer) OnFailOrCancel(ctx context.Context, execCtx interfac
 			return err
 		}
 
+		if err := tc.cleanupDomainChecks(ctx); err != nil {
+			return err
+		}
+
 		if err := drainNamesForDescriptor(
 			ctx, tc.execCfg.Settings, tc.typeID, tc.execCfg.DB,
 			tc.execCfg.InternalExecutor, tc.execCfg.LeaseManager, tc.execCfg.Codec, nil,
@@ -86,6 +86,8 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_timetz:       TimeTZ,
 	oid.T_timestamp:    Timestamp,
 	oid.T_timestamptz:  TimestampTZ,
+	oid.T_tsquery:      TSQuery,
+	oid.T_tsvector:     TSVector,
 	oid.T_unknown:      Unknown,
 	oid.T_uuid:         Uuid,
 	oid.T_varbit:       VarBit,
@@ -129,6 +131,8 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oid.T_timetz:       oid.T__timetz,
 	oid.T_timestamp:    oid.T__timestamp,
 	oid.T_timestamptz:  oid.T__timestamptz,
+	oid.T_tsquery:      oid.T__tsquery,
+	oid.T_tsvector:     oid.T__tsvector,
 	oid.T_uuid:         oid.T__uuid,
 	oid.T_varbit:       oid.T__varbit,
 	oid.T_varchar:      oid.T__varchar,
@@ -163,6 +167,8 @@ var familyToOid = map[Family]oid.Oid{
 	JsonFamily:           oid.T_jsonb,
 	TupleFamily:          oid.T_record,
 	BitFamily:            oid.T_bit,
+	TSQueryFamily:        oid.T_tsquery,
+	TSVectorFamily:       oid.T_tsvector,
 	AnyFamily:            oid.T_anyelement,
 
 	GeometryFamily:  oidext.T_geometry,
@@ -184,6 +190,9 @@ func init() {
 // type.
 func CalcArrayOid(elemTyp *T) oid.Oid {
 	o := elemTyp.Oid()
+	if elemTyp.IsDomain() {
+		return elemTyp.UserDefinedArrayOID()
+	}
 	switch elemTyp.Family() {
 	case ArrayFamily:
 		// Postgres nested arrays return the OID of the nested array (i.e. the
@@ -205,6 +214,11 @@ func CalcArrayOid(elemTyp *T) oid.Oid {
 
 	case EnumFamily:
 		return elemTyp.UserDefinedArrayOID()
+
+	case TupleFamily:
+		if elemTyp.UserDefined() {
+			return elemTyp.UserDefinedArrayOID()
+		}
 	}
 
 	// Map the OID of the array element type to the corresponding array OID.
@@ -194,6 +194,9 @@ type UserDefinedTypeMetadata struct {
 
 	// enumData is non-nil iff the metadata is for an ENUM type.
 	EnumData *EnumMetadata
+
+	// DomainData is non-nil iff the metadata is for a DOMAIN type.
+	DomainData *DomainMetadata
 }
 
 // EnumMetadata is metadata about an ENUM needed for evaluation.
@@ -212,6 +215,26 @@ type EnumMetadata struct {
 	//  should occur, if at all.
 }
 
+// DomainMetadata is metadata about a DOMAIN needed to enforce its
+// constraints.
+type DomainMetadata struct {
+	// BaseType is the type that the domain wraps.
+	BaseType *T
+	// NotNull is true if values of the domain may not be NULL.
+	NotNull bool
+	// DefaultExpr is the serialized default expression of the domain, if any.
+	DefaultExpr *string
+	// Checks holds the CHECK constraints of the domain.
+	Checks []DomainCheck
+}
+
+// DomainCheck is a CHECK constraint of a DOMAIN. Expr is a serialized boolean
+// expression that refers to the value being checked as VALUE.
+type DomainCheck struct {
+	Name string
+	Expr string
+}
+
 func (e *EnumMetadata) debugString() string {
 	return fmt.Sprintf(
 		"PhysicalReps: %v; LogicalReps: %s",
@@ -443,6 +466,23 @@ var (
 		},
 	}
 
+	// TSVector is the type of a document that has been processed for
+	// full-text search, consisting of a sorted list of lexemes and their
+	// positions. For example:
+	//
+	//   'fat':2 'rat':3A
+	//
+	TSVector = &T{InternalType: InternalType{
+		Family: TSVectorFamily, Oid: oid.T_tsvector, Locale: &emptyLocale}}
+
+	// TSQuery is the type of a full-text search query, consisting of lexemes
+	// combined with boolean and phrase operators. For example:
+	//
+	//   'fat' & ( 'rat' | 'cat' )
+	//
+	TSQuery = &T{InternalType: InternalType{
+		Family: TSQueryFamily, Oid: oid.T_tsquery, Locale: &emptyLocale}}
+
 	// Scalar contains all types that meet this criteria:
 	//
 	//   1. Scalar type (no ArrayFamily or TupleFamily types).
@@ -470,6 +510,8 @@ var (
 		TimeTZ,
 		Jsonb,
 		VarBit,
+		TSQuery,
+		TSVector,
 	}
 
 	// Any is a special type used only during static analysis as a wildcard type
@@ -1053,6 +1095,39 @@ func MakeEnum(typeOID, arrayTypeOID oid.Oid) *T {
 	}}
 }
 
+// MakeComposite constructs a new instance of a TupleFamily type that
+// represents a user defined composite type with the given stable type ID and
+// elements. Note that it does not hydrate cached fields on the type.
+func MakeComposite(typeOID, arrayTypeOID oid.Oid, contents []*T, labels []string) *T {
+	if len(contents) != len(labels) {
+		panic(errors.AssertionFailedf(
+			"composite contents and labels must be of same length: %v, %v", contents, labels))
+	}
+	return &T{InternalType: InternalType{
+		Family:        TupleFamily,
+		Oid:           typeOID,
+		TupleContents: contents,
+		TupleLabels:   labels,
+		Locale:        &emptyLocale,
+		UDTMetadata: &PersistentUserDefinedTypeMetadata{
+			ArrayTypeOID: arrayTypeOID,
+		},
+	}}
+}
+
+// MakeDomain constructs a new instance of a type that represents a user
+// defined domain over the given base type, with the given stable type ID. The
+// domain has the same family and attributes as its base type. Note that it
+// does not hydrate cached fields on the type.
+func MakeDomain(typeOID, arrayTypeOID oid.Oid, base *T) *T {
+	internalType := base.InternalType
+	internalType.Oid = typeOID
+	internalType.UDTMetadata = &PersistentUserDefinedTypeMetadata{
+		ArrayTypeOID: arrayTypeOID,
+	}
+	return &T{InternalType: internalType}
+}
+
 // MakeArray constructs a new instance of an ArrayFamily type with the given
 // element type (which may itself be an ArrayFamily type).
 func MakeArray(typ *T) *T {
@@ -1267,6 +1342,16 @@ func (t *T) UserDefined() bool {
 	return IsOIDUserDefinedType(t.Oid())
 }
 
+// IsDomain returns whether or not t is a user defined domain type. Unlike
+// other user defined types, domains take on the family of their base type.
+func (t *T) IsDomain() bool {
+	switch t.Family() {
+	case EnumFamily, TupleFamily, ArrayFamily:
+		return false
+	}
+	return t.UserDefined()
+}
+
 // IsOIDUserDefinedType returns whether or not o corresponds to a user
 // defined type.
 func IsOIDUserDefinedType(o oid.Oid) bool {
@@ -1298,6 +1383,8 @@ var familyNames = map[Family]string{
 	TimestampFamily:      "timestamp",
 	TimestampTZFamily:    "timestamptz",
 	TimeTZFamily:         "timetz",
+	TSQueryFamily:        "tsquery",
+	TSVectorFamily:       "tsvector",
 	TupleFamily:          "tuple",
 	UnknownFamily:        "unknown",
 	UuidFamily:           "uuid",
@@ -1322,6 +1409,14 @@ func (f Family) Name() string {
 //
 // TODO(andyk): Should these be changed to be the same as SQLStandardName?
 func (t *T) Name() string {
+	if t.IsDomain() {
+		// This can be nil during unit testing.
+		if t.TypeMeta.Name == nil {
+			return "unknown_domain"
+		}
+		return t.TypeMeta.Name.Basename()
+	}
+
 	switch fam := t.Family(); fam {
 	case AnyFamily:
 		return "anyelement"
@@ -1383,7 +1478,11 @@ func (t *T) Name() string {
 		panic(errors.AssertionFailedf("unexpected OID: %d", t.Oid()))
 
 	case TupleFamily:
-		// Tuple types are currently anonymous, with no name.
+		// Composite types are named after their type descriptor, other tuple
+		// types are anonymous, with no name.
+		if t.TypeMeta.Name != nil {
+			return t.TypeMeta.Name.Basename()
+		}
 		return ""
 
 	case EnumFamily:
@@ -1462,6 +1561,9 @@ func (t *T) TelemetryName() string {
 // This function is full of special cases. See backend/utils/adt/format_type.c
 // in Postgres.
 func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
+	if t.IsDomain() {
+		return t.Name()
+	}
 	var buf strings.Builder
 	switch t.Family() {
 	case AnyFamily:
@@ -1614,7 +1716,14 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			return "timestamp with time zone"
 		}
 		return fmt.Sprintf("timestamp(%d) with time zone", typmod)
+	case TSQueryFamily:
+		return "tsquery"
+	case TSVectorFamily:
+		return "tsvector"
 	case TupleFamily:
+		if t.UserDefined() {
+			return t.TypeMeta.Name.Basename()
+		}
 		return "record"
 	case UnknownFamily:
 		return "unknown"
@@ -1644,6 +1753,12 @@ func (t *T) InformationSchemaName() string {
 // reproduce the type via parsing the string as a type. It is used in error
 // messages and also to produce the output of SHOW CREATE.
 func (t *T) SQLString() string {
+	if t.IsDomain() {
+		if t.TypeMeta.Name == nil {
+			return strings.ToUpper(t.Name())
+		}
+		return t.TypeMeta.Name.FQName()
+	}
 	switch t.Family() {
 	case BitFamily:
 		o := t.Oid()
@@ -1738,6 +1853,10 @@ func (t *T) SQLString() string {
 			return "anyenum"
 		}
 		return t.TypeMeta.Name.FQName()
+	case TupleFamily:
+		if t.UserDefined() {
+			return t.TypeMeta.Name.FQName()
+		}
 	}
 	return strings.ToUpper(t.Name())
 }
@@ -1778,6 +1897,12 @@ func (t *T) Equivalent(other *T) bool {
 		if IsWildcardTupleType(t) || IsWildcardTupleType(other) {
 			return true
 		}
+		// Distinct composite types are never equivalent, even if they have the
+		// same elements. A composite type is equivalent to anonymous tuples with
+		// matching elements, however.
+		if t.UserDefined() && other.UserDefined() && t.Oid() != other.Oid() {
+			return false
+		}
 		if len(t.TupleContents()) != len(other.TupleContents()) {
 			return false
 		}
@@ -1982,6 +2107,13 @@ func (t *T) Unmarshal(data []byte) error {
 // setting required values. This is necessary to preserve backwards-
 // compatibility with older formats (e.g. restoring database from old backup).
 func (t *T) upgradeType() error {
+	// Domains carry their own OID, which must survive the upgrade of the
+	// attributes of their base type below.
+	if t.IsDomain() {
+		domainOid := t.InternalType.Oid
+		defer func() { t.InternalType.Oid = domainOid }()
+	}
+
 	switch t.Family() {
 	case IntFamily:
 		// Check VisibleType field that was populated in previous versions.
@@ -2233,7 +2365,9 @@ func (t *T) downgradeType() error {
 		case oid.T_name:
 			t.InternalType.Family = name
 		default:
-			return errors.AssertionFailedf("unexpected Oid: %d", t.Oid())
+			if !t.IsDomain() {
+				return errors.AssertionFailedf("unexpected Oid: %d", t.Oid())
+			}
 		}
 
 	case ArrayFamily:
@@ -2277,6 +2411,9 @@ func (t *T) downgradeType() error {
 // TODO(andyk): It'd be nice to have this return SqlString() method output,
 // since that is more descriptive.
 func (t *T) String() string {
+	if t.IsDomain() {
+		return t.Name()
+	}
 	switch t.Family() {
 	case CollatedStringFamily:
 		if t.Locale() == "" {
@@ -2293,6 +2430,9 @@ func (t *T) String() string {
 		return t.ArrayContents().String() + "[]"
 
 	case TupleFamily:
+		if t.TypeMeta.Name != nil {
+			return t.Name()
+		}
 		var buf bytes.Buffer
 		buf.WriteString("tuple")
 		if len(t.TupleContents()) != 0 && !IsWildcardTupleType(t) {
@@ -2434,6 +2574,8 @@ func IsValidArrayElementType(t *T) (valid bool, issueNum int) {
 	switch t.Family() {
 	case JsonFamily:
 		return false, 23468
+	case TSVectorFamily, TSQueryFamily:
+		return false, 7821
 	default:
 		return true, 0
 	}
@@ -2633,8 +2775,10 @@ var unreservedTypeTokens = map[string]*T{
 	"smallserial": &Serial2Type,
 	"bigserial":   &Serial8Type,
 
-	"string": String,
-	"uuid":   Uuid,
+	"string":   String,
+	"tsquery":  TSQuery,
+	"tsvector": TSVector,
+	"uuid":     Uuid,
 }
 
 // The following map must include all types predefined in PostgreSQL
@@ -2653,8 +2797,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"money":         -1,
 	"path":          21286,
 	"pg_lsn":        -1,
-	"tsquery":       7821,
-	"tsvector":      7821,
 	"txid_snapshot": -1,
 	"xml":           -1,
 }
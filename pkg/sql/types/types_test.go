@@ -485,6 +485,16 @@ func TestTypes(t *testing.T) {
 			Family: TimestampTZFamily, Oid: oid.T_timestamptz, Precision: 6, TimePrecisionIsSet: true, Locale: &emptyLocale}}},
 		{MakeTimestampTZ(6), MakeScalar(TimestampTZFamily, oid.T_timestamptz, 6, 0, emptyLocale)},
 
+		// TSQUERY
+		{TSQuery, &T{InternalType: InternalType{
+			Family: TSQueryFamily, Oid: oid.T_tsquery, Locale: &emptyLocale}}},
+		{TSQuery, MakeScalar(TSQueryFamily, oid.T_tsquery, 0, 0, emptyLocale)},
+
+		// TSVECTOR
+		{TSVector, &T{InternalType: InternalType{
+			Family: TSVectorFamily, Oid: oid.T_tsvector, Locale: &emptyLocale}}},
+		{TSVector, MakeScalar(TSVectorFamily, oid.T_tsvector, 0, 0, emptyLocale)},
+
 		// TUPLE
 		{MakeTuple(nil), EmptyTuple},
 		{MakeTuple([]*T{Any}), AnyTuple},
@@ -516,6 +526,29 @@ func TestTypes(t *testing.T) {
 				ArrayTypeOID: 15213,
 			},
 		}}},
+
+		// COMPOSITEs
+		{MakeComposite(100050, 100051, []*T{Int, String}, []string{"a", "b"}), &T{InternalType: InternalType{
+			Family:        TupleFamily,
+			Locale:        &emptyLocale,
+			Oid:           100050,
+			TupleContents: []*T{Int, String},
+			TupleLabels:   []string{"a", "b"},
+			UDTMetadata: &PersistentUserDefinedTypeMetadata{
+				ArrayTypeOID: 100051,
+			},
+		}}},
+
+		// DOMAINs
+		{MakeDomain(100070, 100071, MakeVarChar(10)), &T{InternalType: InternalType{
+			Family: StringFamily,
+			Width:  10,
+			Locale: &emptyLocale,
+			Oid:    100070,
+			UDTMetadata: &PersistentUserDefinedTypeMetadata{
+				ArrayTypeOID: 100071,
+			},
+		}}},
 	}
 
 	for i, tc := range testCases {
@@ -622,6 +655,19 @@ func TestEquivalent(t *testing.T) {
 		{MakeEnum(15210, 15213), MakeEnum(15210, 15213), true},
 		{MakeEnum(15210, 15213), MakeEnum(15150, 15213), false},
 
+		// COMPOSITE
+		{MakeComposite(100050, 100051, []*T{Int, String}, []string{"a", "b"}),
+			MakeComposite(100050, 100051, []*T{Int, String}, []string{"a", "b"}), true},
+		{MakeComposite(100050, 100051, []*T{Int, String}, []string{"a", "b"}),
+			MakeTuple([]*T{Int4, VarChar}), true},
+		{MakeComposite(100050, 100051, []*T{Int, String}, []string{"a", "b"}),
+			MakeComposite(100060, 100061, []*T{Int, String}, []string{"a", "b"}), false},
+
+		// DOMAIN
+		{MakeDomain(100070, 100071, Int), Int, true},
+		{MakeDomain(100070, 100071, Int), MakeDomain(100080, 100081, Int), true},
+		{MakeDomain(100070, 100071, Int), String, false},
+
 		// UNKNOWN
 		{Unknown, &T{InternalType: InternalType{
 			Family: UnknownFamily, Oid: oid.T_unknown, Locale: &emptyLocale}}, true},
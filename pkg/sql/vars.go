@@ -98,6 +98,10 @@ func formatBoolAsPostgresSetting(b bool) string {
 	return "off"
 }
 
+func formatFloatAsPostgresSetting(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1 /* prec */, 64)
+}
+
 // makeDummyBooleanSessionVar generates a sessionVar for a bool session setting.
 // These functions allow the setting to be changed, but whose values are not used.
 // They are logged to telemetry and output a notice that these are unused.
@@ -470,6 +474,44 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`enable_adaptive_joins`: {
+		GetStringVal: makePostgresBoolGetStringValFn(`enable_adaptive_joins`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			b, err := paramparse.ParseBoolVar("enable_adaptive_joins", s)
+			if err != nil {
+				return err
+			}
+			m.SetAdaptiveJoinsEnabled(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return formatBoolAsPostgresSetting(evalCtx.SessionData.AdaptiveJoinsEnabled)
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return formatBoolAsPostgresSetting(adaptiveJoinsClusterMode.Get(sv))
+		},
+	},
+
+	// CockroachDB extension.
+	`enable_statement_hints`: {
+		GetStringVal: makePostgresBoolGetStringValFn(`enable_statement_hints`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			b, err := paramparse.ParseBoolVar("enable_statement_hints", s)
+			if err != nil {
+				return err
+			}
+			m.SetStatementHintsEnabled(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return formatBoolAsPostgresSetting(evalCtx.SessionData.StatementHintsEnabled)
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return formatBoolAsPostgresSetting(statementHintsClusterMode.Get(sv))
+		},
+	},
+
 	// CockroachDB extension.
 	`reorder_joins_limit`: {
 		GetStringVal: makeIntGetStringValFn(`reorder_joins_limit`),
@@ -629,6 +671,25 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`optimizer_use_forecasts`: {
+		GetStringVal: makePostgresBoolGetStringValFn(`optimizer_use_forecasts`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			b, err := paramparse.ParseBoolVar("optimizer_use_forecasts", s)
+			if err != nil {
+				return err
+			}
+			m.SetOptimizerUseForecasts(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return formatBoolAsPostgresSetting(evalCtx.SessionData.OptimizerUseForecasts)
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return formatBoolAsPostgresSetting(optUseForecastsClusterMode.Get(sv))
+		},
+	},
+
 	// CockroachDB extension.
 	`locality_optimized_partitioned_index_scan`: {
 		GetStringVal: makePostgresBoolGetStringValFn(`locality_optimized_partitioned_index_scan`),
@@ -751,6 +812,29 @@ var varGen = map[string]sessionVar{
 		GlobalDefault: func(sv *settings.Values) string { return "0" },
 	},
 
+	// See https://www.postgresql.org/docs/current/pgtrgm.html#PGTRGM-GUC
+	`pg_trgm.similarity_threshold`: {
+		GetStringVal: makeFloatGetStringValFn(`pg_trgm.similarity_threshold`),
+		Set: func(
+			_ context.Context, m *sessionDataMutator, s string,
+		) error {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return wrapSetVarError("pg_trgm.similarity_threshold", s, "%v", err)
+			}
+			if f < 0 || f > 1 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					`%s is outside the valid range for parameter "pg_trgm.similarity_threshold" (0 .. 1)`, s)
+			}
+			m.SetTrigramSimilarityThreshold(f)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return formatFloatAsPostgresSetting(evalCtx.SessionData.TrigramSimilarityThreshold)
+		},
+		GlobalDefault: func(sv *settings.Values) string { return "0.3" },
+	},
+
 	// CockroachDB extension. See docs on SessionData.ForceSavepointRestart.
 	// https://github.com/cockroachdb/cockroach/issues/30588
 	`force_savepoint_restart`: {
@@ -1292,6 +1376,25 @@ var varGen = map[string]sessionVar{
 			return formatBoolAsPostgresSetting(experimentalStreamReplicationEnabled.Get(sv))
 		},
 	},
+
+	// CockroachDB extension.
+	`multiple_active_portals_enabled`: {
+		GetStringVal: makePostgresBoolGetStringValFn(`multiple_active_portals_enabled`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			b, err := paramparse.ParseBoolVar(`multiple_active_portals_enabled`, s)
+			if err != nil {
+				return err
+			}
+			m.SetMultipleActivePortalsEnabled(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return formatBoolAsPostgresSetting(evalCtx.SessionData.MultipleActivePortalsEnabled)
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return formatBoolAsPostgresSetting(multipleActivePortalsEnabledClusterMode.Get(sv))
+		},
+	},
 }
 
 const compatErrMsg = "this parameter is currently recognized only for compatibility and has no effect in CockroachDB."
@@ -1446,6 +1549,21 @@ func makeIntGetStringValFn(name string) getStringValFn {
 	}
 }
 
+// makeFloatGetStringValFn returns a getStringValFn which allows
+// the user to provide plain numeric values to a SET variable.
+func makeFloatGetStringValFn(name string) getStringValFn {
+	return func(ctx context.Context, evalCtx *extendedEvalContext, values []tree.TypedExpr) (string, error) {
+		if len(values) != 1 {
+			return "", newSingleArgVarError(name)
+		}
+		f, err := paramparse.DatumAsFloat(&evalCtx.EvalContext, name, values[0])
+		if err != nil {
+			return "", err
+		}
+		return formatFloatAsPostgresSetting(f), nil
+	}
+}
+
 // IsSessionVariableConfigurable returns true iff there is a session
 // variable with the given name and it is settable by a client
 // (e.g. in pgwire).
@@ -1087,8 +1087,8 @@ CREATE TABLE pg_catalog.pg_group (
 	grosysid OID
 )`
 
-// PgCatalogCursors is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogCursors describes the schema of the pg_catalog.pg_cursors table.
+// https://www.postgresql.org/docs/current/view-pg-cursors.html
 const PgCatalogCursors = `
 CREATE TABLE pg_catalog.pg_cursors (
 	is_scrollable BOOL,
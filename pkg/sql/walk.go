@@ -334,6 +334,7 @@ func nodeName(plan planNode) string {
 // be changed without changing the output of "EXPLAIN".
 var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterDatabaseOwnerNode{}):         "alter database owner",
+	reflect.TypeOf(&alterDefaultPrivilegesNode{}):     "alter default privileges",
 	reflect.TypeOf(&alterDatabaseAddRegionNode{}):     "alter database add region",
 	reflect.TypeOf(&alterDatabasePrimaryRegionNode{}): "alter database primary region",
 	reflect.TypeOf(&alterDatabaseSurvivalGoalNode{}):  "alter database survive",
@@ -346,6 +347,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterTableSetLocalityNode{}):      "alter table set locality",
 	reflect.TypeOf(&alterTableSetSchemaNode{}):        "alter table set schema",
 	reflect.TypeOf(&alterTypeNode{}):                  "alter type",
+	reflect.TypeOf(&alterDomainNode{}):                "alter domain",
 	reflect.TypeOf(&alterRoleNode{}):                  "alter role",
 	reflect.TypeOf(&applyJoinNode{}):                  "apply join",
 	reflect.TypeOf(&bufferNode{}):                     "buffer",
@@ -361,6 +363,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&createDatabaseNode{}):             "create database",
 	reflect.TypeOf(&createExtensionNode{}):            "create extension",
 	reflect.TypeOf(&createIndexNode{}):                "create index",
+	reflect.TypeOf(&createPolicyNode{}):               "create policy",
 	reflect.TypeOf(&createSequenceNode{}):             "create sequence",
 	reflect.TypeOf(&createSchemaNode{}):               "create schema",
 	reflect.TypeOf(&createStatsNode{}):                "create statistics",
@@ -370,10 +373,13 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&createViewNode{}):                 "create view",
 	reflect.TypeOf(&delayedNode{}):                    "virtual table",
 	reflect.TypeOf(&deleteNode{}):                     "delete",
+	reflect.TypeOf(&declareCursorNode{}):              "declare cursor",
 	reflect.TypeOf(&deleteRangeNode{}):                "delete range",
 	reflect.TypeOf(&distinctNode{}):                   "distinct",
 	reflect.TypeOf(&dropDatabaseNode{}):               "drop database",
 	reflect.TypeOf(&dropIndexNode{}):                  "drop index",
+	reflect.TypeOf(&dropPlanBaselineNode{}):           "drop plan baseline",
+	reflect.TypeOf(&dropPolicyNode{}):                 "drop policy",
 	reflect.TypeOf(&dropSequenceNode{}):               "drop sequence",
 	reflect.TypeOf(&dropSchemaNode{}):                 "drop schema",
 	reflect.TypeOf(&dropTableNode{}):                  "drop table",
@@ -385,6 +391,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&explainVecNode{}):                 "explain vectorized",
 	reflect.TypeOf(&explainDDLNode{}):                 "explain ddl",
 	reflect.TypeOf(&exportNode{}):                     "export",
+	reflect.TypeOf(&fetchNode{}):                      "fetch",
 	reflect.TypeOf(&filterNode{}):                     "filter",
 	reflect.TypeOf(&GrantRoleNode{}):                  "grant role",
 	reflect.TypeOf(&groupNode{}):                      "group",
@@ -399,6 +406,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&lookupJoinNode{}):                 "lookup join",
 	reflect.TypeOf(&max1RowNode{}):                    "max1row",
 	reflect.TypeOf(&ordinalityNode{}):                 "ordinality",
+	reflect.TypeOf(&pinPlanNode{}):                    "pin plan",
 	reflect.TypeOf(&projectSetNode{}):                 "project set",
 	reflect.TypeOf(&reassignOwnedByNode{}):            "reassign owned by",
 	reflect.TypeOf(&dropOwnedByNode{}):                "drop owned by",
@@ -16,6 +16,8 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
 	"github.com/cockroachdb/datadriven"
@@ -212,6 +214,19 @@ func MsgsToJSONWithIgnore(msgs []pgproto3.BackendMessage, args *datadriven.TestD
 			}); err != nil {
 				panic(err)
 			}
+		} else if data, ok := msg.(*pgproto3.CopyData); ok && isText(data.Data) {
+			// Render the data as a string rather than in hex, which is how
+			// pgproto3 encodes it, to make text data readable. Binary data is
+			// left in hex.
+			if err := enc.Encode(struct {
+				Type string
+				Data string
+			}{
+				Type: "CopyData",
+				Data: string(data.Data),
+			}); err != nil {
+				panic(err)
+			}
 		} else if err := enc.Encode(msg); err != nil {
 			panic(err)
 		}
@@ -219,6 +234,20 @@ func MsgsToJSONWithIgnore(msgs []pgproto3.BackendMessage, args *datadriven.TestD
 	return sb.String()
 }
 
+// isText returns whether b is made of printable UTF-8 characters and
+// whitespace.
+func isText(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
 func toMessage(typ string) interface{} {
 	switch typ {
 	case "Bind":
@@ -233,6 +262,8 @@ func toMessage(typ string) interface{} {
 		return &pgproto3.CopyDone{}
 	case "CopyInResponse":
 		return &pgproto3.CopyInResponse{}
+	case "CopyOutResponse":
+		return &pgproto3.CopyOutResponse{}
 	case "DataRow":
 		return &pgproto3.DataRow{}
 	case "Describe":
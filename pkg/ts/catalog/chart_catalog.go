@@ -2359,6 +2359,19 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{Jobs, "Schedules", "Row Level TTL"}},
+		Charts: []chartDescription{
+			{
+				Title: "Counts",
+				Metrics: []string{
+					"schedules.scheduled-row-level-ttl-executor.started",
+					"schedules.scheduled-row-level-ttl-executor.succeeded",
+					"schedules.scheduled-row-level-ttl-executor.failed",
+				},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{Jobs, "Execution"}},
 		Charts: []chartDescription{
@@ -2377,6 +2390,8 @@ var charts = []sectionDescription{
 					"jobs.typedesc_schema_change.currently_running",
 					"jobs.stream_ingestion.currently_running",
 					"jobs.migration.currently_running",
+					"jobs.row_level_ttl.currently_running",
+					"jobs.incremental_materialized_view.currently_running",
 				},
 			},
 			{
@@ -2521,6 +2536,45 @@ var charts = []sectionDescription{
 					"jobs.migration.resume_retry_error",
 				},
 			},
+			{
+				Title: "Row Level TTL",
+				Metrics: []string{
+					"jobs.row_level_ttl.fail_or_cancel_completed",
+					"jobs.row_level_ttl.fail_or_cancel_failed",
+					"jobs.row_level_ttl.fail_or_cancel_retry_error",
+					"jobs.row_level_ttl.resume_completed",
+					"jobs.row_level_ttl.resume_failed",
+					"jobs.row_level_ttl.resume_retry_error",
+				},
+				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
+			},
+			{
+				Title: "Row Level TTL Rows",
+				Metrics: []string{
+					"jobs.row_level_ttl.rows_selected",
+					"jobs.row_level_ttl.rows_deleted",
+				},
+				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
+			},
+			{
+				Title: "Row Level TTL Latency",
+				Metrics: []string{
+					"jobs.row_level_ttl.select_duration",
+					"jobs.row_level_ttl.delete_duration",
+				},
+			},
+			{
+				Title: "Incremental Materialized View",
+				Metrics: []string{
+					"jobs.incremental_materialized_view.fail_or_cancel_completed",
+					"jobs.incremental_materialized_view.fail_or_cancel_failed",
+					"jobs.incremental_materialized_view.fail_or_cancel_retry_error",
+					"jobs.incremental_materialized_view.resume_completed",
+					"jobs.incremental_materialized_view.resume_failed",
+					"jobs.incremental_materialized_view.resume_retry_error",
+				},
+				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
+			},
 		},
 	},
 }
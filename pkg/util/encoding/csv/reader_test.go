@@ -31,6 +31,7 @@ func TestRead(t *testing.T) {
 
 		// These fields are copied into the Reader
 		Comma              rune
+		Quote              rune
 		Comment            rune
 		UseFieldsPerRecord bool // false (default) means FieldsPerRecord is -1
 		FieldsPerRecord    int
@@ -388,6 +389,26 @@ x,,,
 		Comma:   'X',
 		Comment: 'X',
 		Error:   errInvalidDelim,
+	}, {
+		Name:   "QuoteDollar",
+		Input:  "$a,b$,$c$$d$,\"e\"\n",
+		Output: [][]string{{"a,b", "c$d", `"e"`}},
+		Quote:  '$',
+	}, {
+		Name:   "QuoteMultiByte",
+		Input:  "§a,b§,§c§§d§\n",
+		Output: [][]string{{"a,b", "c§d"}},
+		Quote:  '§',
+	}, {
+		Name:  "BareQuoteDollar",
+		Input: "a$b,c\n",
+		Error: &ParseError{StartLine: 1, Line: 1, Column: 1, Err: ErrBareQuote},
+		Quote: '$',
+	}, {
+		Name:  "BadQuoteComma",
+		Comma: '$',
+		Quote: '$',
+		Error: errInvalidQuote,
 	}}
 
 	for _, tt := range tests {
@@ -397,6 +418,9 @@ x,,,
 			if tt.Comma != 0 {
 				r.Comma = tt.Comma
 			}
+			if tt.Quote != 0 {
+				r.Quote = tt.Quote
+			}
 			r.Comment = tt.Comment
 			if tt.UseFieldsPerRecord {
 				r.FieldsPerRecord = tt.FieldsPerRecord
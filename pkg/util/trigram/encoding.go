@@ -0,0 +1,78 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package trigram
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// EncodeInvertedIndexKeys takes in a key prefix and returns a slice of
+// inverted index keys, one per trigram in the string.
+func EncodeInvertedIndexKeys(inKey []byte, s string) [][]byte {
+	trigrams := MakeTrigrams(s)
+	outKeys := make([][]byte, len(trigrams))
+	for i := range trigrams {
+		outKeys[i] = encoding.EncodeStringAscending(inKey[:len(inKey):len(inKey)], trigrams[i])
+	}
+	return outKeys
+}
+
+// EncodeLikeInvertedIndexSpans returns the spans that must be scanned in the
+// inverted index to evaluate a LIKE (or ILIKE) predicate with the given
+// pattern, i.e. the spans of the strings that contain all the trigrams of the
+// pattern. The returned inverted.Expression is never tight, since containing
+// the trigrams of a pattern does not imply matching it. If the pattern has no
+// trigrams, an inverted.NonInvertedColExpression is returned.
+func EncodeLikeInvertedIndexSpans(pattern string) inverted.Expression {
+	var expr inverted.Expression
+	for _, t := range MakeLikePatternTrigrams(pattern) {
+		spanExpr := encodeTrigramSpan(t)
+		if expr == nil {
+			expr = spanExpr
+		} else {
+			expr = inverted.And(expr, spanExpr)
+		}
+	}
+	if expr == nil {
+		return inverted.NonInvertedColExpression{}
+	}
+	return expr
+}
+
+// EncodeSimilarInvertedIndexSpans returns the spans that must be scanned in
+// the inverted index to evaluate a similarity (%) predicate with the given
+// string, i.e. the spans of the strings that share at least one trigram with
+// it. This is only correct if the similarity threshold is greater than zero.
+// The returned inverted.Expression is never tight. If the string has no
+// trigrams, an inverted.NonInvertedColExpression is returned.
+func EncodeSimilarInvertedIndexSpans(s string) inverted.Expression {
+	var expr inverted.Expression
+	for _, t := range MakeTrigrams(s) {
+		spanExpr := encodeTrigramSpan(t)
+		if expr == nil {
+			expr = spanExpr
+		} else {
+			expr = inverted.Or(expr, spanExpr)
+		}
+	}
+	if expr == nil {
+		return inverted.NonInvertedColExpression{}
+	}
+	return expr
+}
+
+func encodeTrigramSpan(t string) *inverted.SpanExpression {
+	key := encoding.EncodeStringAscending(nil, t)
+	expr := inverted.ExprForSpan(inverted.MakeSingleValSpan(key), false /* tight */)
+	expr.Unique = true
+	return expr
+}
@@ -0,0 +1,164 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package trigram implements the trigram operations of the Postgres pg_trgm
+// extension: splitting strings into trigrams, computing the similarity of two
+// strings, and encoding trigrams into inverted index keys.
+package trigram
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Words are padded on the left with two spaces and on the right with one space
+// before being split into trigrams, so that the trigrams of a string also
+// capture where its words begin and end. This matches Postgres.
+const (
+	leftPadding  = "  "
+	rightPadding = " "
+)
+
+// isWordRune returns whether r is part of a word. All other runes separate
+// words.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// MakeTrigrams returns the sorted, de-duplicated trigrams of the given string.
+// The string is lowercased and split into words made of letters and digits.
+// Each word is padded with spaces and contributes all of its three-rune
+// substrings. For example, the trigrams of "Cat" are "  c", " ca", "at " and
+// "cat".
+func MakeTrigrams(s string) []string {
+	var trigrams []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !isWordRune(r)
+	}) {
+		trigrams = appendTrigrams(trigrams, leftPadding+word+rightPadding)
+	}
+	return sortAndDedup(trigrams)
+}
+
+// appendTrigrams appends all the three-rune substrings of s to trigrams.
+func appendTrigrams(trigrams []string, s string) []string {
+	// starts holds the byte offsets of the last three runes seen.
+	var starts [3]int
+	n := 0
+	for i := range s {
+		starts[0], starts[1], starts[2] = starts[1], starts[2], i
+		n++
+		if n >= 3 {
+			_, size := utf8.DecodeRuneInString(s[i:])
+			trigrams = append(trigrams, s[starts[0]:i+size])
+		}
+	}
+	return trigrams
+}
+
+func sortAndDedup(trigrams []string) []string {
+	if len(trigrams) == 0 {
+		return nil
+	}
+	sort.Strings(trigrams)
+	n := 1
+	for i := 1; i < len(trigrams); i++ {
+		if trigrams[i] != trigrams[n-1] {
+			trigrams[n] = trigrams[i]
+			n++
+		}
+	}
+	return trigrams[:n]
+}
+
+// Similarity returns a number between 0 and 1 that indicates how similar the
+// two strings are, based on the number of trigrams they share: it is the
+// number of shared trigrams divided by the number of distinct trigrams of both
+// strings. Identical strings have a similarity of 1.
+func Similarity(a, b string) float64 {
+	left, right := MakeTrigrams(a), MakeTrigrams(b)
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+	// Both slices are sorted, so the shared trigrams can be counted by merging
+	// them.
+	shared := 0
+	for i, j := 0, 0; i < len(left) && j < len(right); {
+		switch {
+		case left[i] < right[j]:
+			i++
+		case left[i] > right[j]:
+			j++
+		default:
+			shared++
+			i++
+			j++
+		}
+	}
+	return float64(shared) / float64(len(left)+len(right)-shared)
+}
+
+// MakeLikePatternTrigrams returns the sorted, de-duplicated trigrams that any
+// string matching the given LIKE pattern must contain. The pattern is split on
+// its wildcards (% and _) into literal parts, and the words of each part are
+// padded only where the pattern determines that they begin or end, i.e. unless
+// they are adjacent to a wildcard. The escape character of the pattern is the
+// backslash.
+//
+// The result may be empty if the literal parts of the pattern are too short to
+// contain any trigram, in which case any string could match the pattern.
+func MakeLikePatternTrigrams(pattern string) []string {
+	var trigrams []string
+	var word strings.Builder
+	// padLeft is true if the current word is known to begin at a word
+	// boundary, which is the case unless it directly follows a wildcard.
+	padLeft := true
+	endWord := func(padRight bool) {
+		if word.Len() > 0 {
+			w := word.String()
+			if padLeft {
+				w = leftPadding + w
+			}
+			if padRight {
+				w += rightPadding
+			}
+			trigrams = appendTrigrams(trigrams, w)
+			word.Reset()
+		}
+	}
+	escaped := false
+	for _, r := range strings.ToLower(pattern) {
+		if !escaped {
+			switch r {
+			case '\\':
+				escaped = true
+				continue
+			case '%', '_':
+				endWord(false /* padRight */)
+				padLeft = false
+				continue
+			}
+		}
+		escaped = false
+		if isWordRune(r) {
+			word.WriteRune(r)
+		} else {
+			endWord(true /* padRight */)
+			padLeft = true
+		}
+	}
+	// The end of the pattern is also the end of the string, so the last word is
+	// padded unless the pattern ends with a wildcard (in which case the word has
+	// already been ended).
+	endWord(true /* padRight */)
+	return sortAndDedup(trigrams)
+}
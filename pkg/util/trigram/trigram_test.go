@@ -0,0 +1,143 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package trigram
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeTrigrams(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{``, nil},
+		{`  !? `, nil},
+		{`a`, []string{"  a", " a "}},
+		{`Cat`, []string{"  c", " ca", "at ", "cat"}},
+		{`cat cat`, []string{"  c", " ca", "at ", "cat"}},
+		{`a-b`, []string{"  a", "  b", " a ", " b "}},
+		{`héllo`, []string{"  h", " hé", "hél", "llo", "lo ", "éll"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			require.Equal(t, tc.expected, MakeTrigrams(tc.input))
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected float64
+	}{
+		{`word`, `word`, 1},
+		{`word`, `WORD`, 1},
+		{`word`, `two words`, 4.0 / 11},
+		{`cat`, `dog`, 0},
+		{``, ``, 0},
+		{`cat`, ``, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.a+"/"+tc.b, func(t *testing.T) {
+			require.InDelta(t, tc.expected, Similarity(tc.a, tc.b), 1e-9)
+			require.InDelta(t, tc.expected, Similarity(tc.b, tc.a), 1e-9)
+		})
+	}
+}
+
+func TestMakeLikePatternTrigrams(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{`%`, nil},
+		{`%ab%`, nil},
+		{`%foo%`, []string{"foo"}},
+		{`%Foo%`, []string{"foo"}},
+		{`foo%`, []string{"  f", " fo", "foo"}},
+		{`%foo`, []string{"foo", "oo "}},
+		{`foo`, []string{"  f", " fo", "foo", "oo "}},
+		{`%foo bar%`, []string{"  b", " ba", "bar", "foo", "oo "}},
+		{`%fo_ar%`, nil},
+		{`%foo\%%`, []string{"foo", "oo "}},
+		{`%a\_bcd%`, []string{"  b", " bc", "bcd"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			require.Equal(t, tc.expected, MakeLikePatternTrigrams(tc.pattern))
+		})
+	}
+}
+
+func TestEncodeInvertedIndexSpans(t *testing.T) {
+	// Every string matching a pattern must be found by scanning the spans of the
+	// pattern.
+	testCases := []struct {
+		pattern string
+		matches []string
+	}{
+		{`%foo%`, []string{`foo`, `xfoox`, `a foo b`, `FOO`}},
+		{`foo%`, []string{`foo`, `foobar`, `foo bar`}},
+		{`%bar`, []string{`bar`, `foobar`, `foo bar`}},
+		{`%foo bar%`, []string{`foo bar`, `xfoo barx`}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			expr := EncodeLikeInvertedIndexSpans(tc.pattern)
+			spanExpr, ok := expr.(*inverted.SpanExpression)
+			require.True(t, ok)
+			require.False(t, spanExpr.Tight)
+			for _, s := range tc.matches {
+				require.True(t, spansMatch(spanExpr, EncodeInvertedIndexKeys(nil, s)), s)
+			}
+		})
+	}
+
+	_, ok := EncodeLikeInvertedIndexSpans(`%ab%`).(inverted.NonInvertedColExpression)
+	require.True(t, ok)
+
+	expr := EncodeSimilarInvertedIndexSpans(`word`)
+	spanExpr, ok := expr.(*inverted.SpanExpression)
+	require.True(t, ok)
+	require.True(t, spansMatch(spanExpr, EncodeInvertedIndexKeys(nil, `two words`)))
+	require.False(t, spansMatch(spanExpr, EncodeInvertedIndexKeys(nil, `cat`)))
+}
+
+// spansMatch returns whether a row with the given inverted index keys is
+// returned by the given span expression.
+func spansMatch(expr *inverted.SpanExpression, keys [][]byte) bool {
+	inSpans := func(spans []inverted.Span) bool {
+		for _, span := range spans {
+			for _, key := range keys {
+				if span.ContainsKey(key) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if inSpans(expr.FactoredUnionSpans) {
+		return true
+	}
+	switch expr.Operator {
+	case inverted.SetIntersection:
+		return spansMatch(expr.Left.(*inverted.SpanExpression), keys) &&
+			spansMatch(expr.Right.(*inverted.SpanExpression), keys)
+	case inverted.SetUnion:
+		return spansMatch(expr.Left.(*inverted.SpanExpression), keys) ||
+			spansMatch(expr.Right.(*inverted.SpanExpression), keys)
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultConfigName is the name of the configuration used by the text search
+// functions when none is specified.
+const DefaultConfigName = "english"
+
+// Config is a text search configuration, which determines how a document or
+// query is split into words and how those words are normalized into
+// lexemes.
+//
+// Unlike Postgres, which recognizes many kinds of tokens (URLs, email
+// addresses, hyphenated words, etc.), documents are simply split into runs
+// of letters and digits, and everything else is treated as a separator.
+type Config struct {
+	name      string
+	stopwords map[string]struct{}
+	stem      func(string) string
+}
+
+var configs = map[string]*Config{
+	"english": {name: "english", stopwords: englishStopwords, stem: stemEnglish},
+	"simple":  {name: "simple"},
+}
+
+// GetConfig returns the text search configuration with the given name, which
+// may be qualified with the pg_catalog schema.
+func GetConfig(name string) (*Config, error) {
+	if c, ok := configs[strings.TrimPrefix(name, "pg_catalog.")]; ok {
+		return c, nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject,
+		"text search configuration %q does not exist", name)
+}
+
+// Name returns the name of the configuration.
+func (c *Config) Name() string {
+	return c.name
+}
+
+// token is a lexeme produced by a configuration, along with its position in
+// the input text.
+type token struct {
+	word string
+	pos  int
+}
+
+// tokenize splits the input into words, and normalizes them into lexemes.
+// Stopwords, and words too long to be lexemes, are dropped but still occupy
+// a position, so that phrase distances are preserved.
+func (c *Config) tokenize(input string) []token {
+	var tokens []token
+	pos := 0
+	for _, word := range strings.FieldsFunc(input, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		pos++
+		word = strings.ToLower(word)
+		if _, ok := c.stopwords[word]; ok || len(word) > maxLexemeLen {
+			continue
+		}
+		if c.stem != nil {
+			word = c.stem(word)
+		}
+		tokens = append(tokens, token{word: word, pos: pos})
+	}
+	return tokens
+}
+
+// ToTSVector converts a document into a TSVector, implementing to_tsvector.
+func (c *Config) ToTSVector(document string) TSVector {
+	tokens := c.tokenize(document)
+	ret := make(TSVector, len(tokens))
+	for i, t := range tokens {
+		ret[i] = Lexeme{Word: t.word, Positions: []Position{{Pos: clampPosition(t.pos)}}}
+	}
+	return ret.normalize()
+}
+
+// ToTSQuery converts the text representation of a query into a TSQuery,
+// normalizing each of its operands, implementing to_tsquery. An operand that
+// contains several words is replaced by a phrase of those words, and
+// stopwords are removed from the query.
+func (c *Config) ToTSQuery(input string) (TSQuery, error) {
+	q, err := parseTSQuery(input, func(n *QueryNode) (*QueryNode, error) {
+		tokens := c.tokenize(n.Lexeme)
+		if len(tokens) == 0 {
+			return &QueryNode{Op: opStopword}, nil
+		}
+		var ret *QueryNode
+		for i, t := range tokens {
+			operand := &QueryNode{Op: OpLexeme, Lexeme: t.word, Prefix: n.Prefix, Weights: n.Weights}
+			if i == 0 {
+				ret = operand
+				continue
+			}
+			distance := clampDistance(t.pos - tokens[i-1].pos)
+			ret = &QueryNode{Op: OpPhrase, Distance: distance, Left: ret, Right: operand}
+		}
+		return ret, nil
+	})
+	if err != nil {
+		return TSQuery{}, err
+	}
+	var ladd, radd int
+	q.Root = cleanStopwords(q.Root, &ladd, &radd)
+	return q, nil
+}
+
+// PlainToTSQuery converts plain text into a TSQuery that matches documents
+// containing all of its words, implementing plainto_tsquery.
+func (c *Config) PlainToTSQuery(input string) TSQuery {
+	var ret *QueryNode
+	for _, t := range c.tokenize(input) {
+		operand := &QueryNode{Op: OpLexeme, Lexeme: t.word}
+		if ret == nil {
+			ret = operand
+		} else {
+			ret = &QueryNode{Op: OpAnd, Left: ret, Right: operand}
+		}
+	}
+	return TSQuery{Root: ret}
+}
+
+// PhraseToTSQuery converts plain text into a TSQuery that matches documents
+// containing its words as a phrase, implementing phraseto_tsquery.
+func (c *Config) PhraseToTSQuery(input string) TSQuery {
+	var ret *QueryNode
+	tokens := c.tokenize(input)
+	for i, t := range tokens {
+		operand := &QueryNode{Op: OpLexeme, Lexeme: t.word}
+		if i == 0 {
+			ret = operand
+			continue
+		}
+		distance := clampDistance(t.pos - tokens[i-1].pos)
+		ret = &QueryNode{Op: OpPhrase, Distance: distance, Left: ret, Right: operand}
+	}
+	return TSQuery{Root: ret}
+}
+
+func clampDistance(d int) uint16 {
+	if d > maxPhraseDistance {
+		return maxPhraseDistance
+	}
+	return uint16(d)
+}
+
+// cleanStopwords removes the stopword placeholders from a query, along with
+// any operators left without operands. The distance of a phrase operator is
+// increased to account for the stopwords removed from either side of it, so
+// that 'fat' <-> 'the' <-> 'rat' becomes 'fat' <2> 'rat'. The distances that
+// could not be accounted for at this level of the tree are returned in ladd
+// and radd, for the left and right side of the subtree respectively. This
+// follows clean_stopword_intree in Postgres.
+func cleanStopwords(n *QueryNode, ladd, radd *int) *QueryNode {
+	*ladd, *radd = 0, 0
+	switch n.Op {
+	case OpLexeme:
+		return n
+	case opStopword:
+		return nil
+	case OpNot:
+		if n.Left = cleanStopwords(n.Left, ladd, radd); n.Left == nil {
+			return nil
+		}
+		return n
+	}
+	var lladd, lradd, rladd, rradd int
+	n.Left = cleanStopwords(n.Left, &lladd, &lradd)
+	n.Right = cleanStopwords(n.Right, &rladd, &rradd)
+	isPhrase := n.Op == OpPhrase
+	distance := int(n.Distance)
+	switch {
+	case n.Left == nil && n.Right == nil:
+		if isPhrase {
+			*ladd = lladd + distance + rradd
+			*radd = *ladd
+		}
+		return nil
+	case n.Left == nil:
+		if isPhrase {
+			*ladd = lladd + distance + rladd
+		} else {
+			*ladd = rladd
+		}
+		*radd = rradd
+		return n.Right
+	case n.Right == nil:
+		*ladd = lladd
+		if isPhrase {
+			*radd = lradd + distance + rradd
+		} else {
+			*radd = lradd
+		}
+		return n.Left
+	case isPhrase:
+		n.Distance = clampDistance(distance + lradd + rladd)
+		*ladd, *radd = lladd, rradd
+	}
+	return n
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// EncodeInvertedIndexKeys takes in a key prefix and returns a slice of
+// inverted index keys, one per lexeme in the vector.
+func EncodeInvertedIndexKeys(inKey []byte, v TSVector) [][]byte {
+	outKeys := make([][]byte, len(v))
+	for i := range v {
+		outKeys[i] = encoding.EncodeStringAscending(inKey[:len(inKey):len(inKey)], v[i].Word)
+	}
+	return outKeys
+}
+
+// EncodeMatchingInvertedIndexSpans returns the spans that must be scanned in
+// the inverted index to evaluate a match (@@) predicate with the given query
+// (i.e., find the vectors in the index that could match the query).
+//
+// The spans are returned in an inverted.Expression. A lexeme is represented
+// by the span for its key, and a prefix by the span of all keys starting with
+// it. The expression is tight unless the query contains phrase operators or
+// weight restrictions, which cannot be checked using the index alone. NOT
+// operators cannot be evaluated using the index, so a query containing them
+// may return an inverted.NonInvertedColExpression.
+func EncodeMatchingInvertedIndexSpans(q TSQuery) inverted.Expression {
+	if q.Root == nil {
+		// The empty query matches nothing, but there is no way to express that
+		// in an inverted.Expression.
+		return inverted.NonInvertedColExpression{}
+	}
+	return encodeMatchingSpans(q.Root)
+}
+
+func encodeMatchingSpans(n *QueryNode) inverted.Expression {
+	switch n.Op {
+	case OpLexeme:
+		key := encoding.EncodeStringAscending(nil, n.Lexeme)
+		var expr *inverted.SpanExpression
+		if n.Prefix {
+			// Strip the terminator from the encoded lexeme to get a prefix of
+			// the keys of all the lexemes that start with it.
+			key = key[:len(key)-2]
+			span := inverted.Span{Start: key, End: inverted.EncVal(roachpb.Key(key).PrefixEnd())}
+			expr = inverted.ExprForSpan(span, true /* tight */)
+		} else {
+			expr = inverted.ExprForSpan(inverted.MakeSingleValSpan(key), true /* tight */)
+			expr.Unique = true
+		}
+		if n.Weights != 0 {
+			expr.SetNotTight()
+		}
+		return expr
+	case OpAnd:
+		return inverted.And(encodeMatchingSpans(n.Left), encodeMatchingSpans(n.Right))
+	case OpOr:
+		return inverted.Or(encodeMatchingSpans(n.Left), encodeMatchingSpans(n.Right))
+	case OpPhrase:
+		expr := inverted.And(encodeMatchingSpans(n.Left), encodeMatchingSpans(n.Right))
+		expr.SetNotTight()
+		return expr
+	}
+	return inverted.NonInvertedColExpression{}
+}
@@ -0,0 +1,286 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "sort"
+
+// Matches returns whether the vector matches the query, implementing the @@
+// operator. The empty query matches nothing.
+func (q TSQuery) Matches(v TSVector) bool {
+	if q.Root == nil {
+		return false
+	}
+	return evalNode(q.Root, v)
+}
+
+func evalNode(n *QueryNode, v TSVector) bool {
+	switch n.Op {
+	case OpLexeme:
+		return matchesLexeme(n, v)
+	case OpAnd:
+		return evalNode(n.Left, v) && evalNode(n.Right, v)
+	case OpOr:
+		return evalNode(n.Left, v) || evalNode(n.Right, v)
+	case OpNot:
+		return !evalNode(n.Left, v)
+	case OpPhrase:
+		res, _ := evalPhrase(n, v)
+		return res == phraseYes
+	}
+	return false
+}
+
+// matchesLexeme returns whether the vector contains the lexeme of the given
+// node with one of the required weights. A lexeme without positions is
+// treated as having weight D.
+func matchesLexeme(n *QueryNode, v TSVector) bool {
+	start, end := lexemeRange(n, v)
+	for i := start; i < end; i++ {
+		if n.Weights == 0 {
+			return true
+		}
+		if len(v[i].Positions) == 0 && n.Weights&(1<<WeightD) != 0 {
+			return true
+		}
+		for _, p := range v[i].Positions {
+			if n.Weights&(1<<p.Weight) != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lexemeRange returns the range of lexemes in the vector that match the
+// lexeme, or the prefix, of the given node.
+func lexemeRange(n *QueryNode, v TSVector) (start, end int) {
+	if n.Prefix {
+		return v.findPrefix(n.Lexeme)
+	}
+	if i := v.find(n.Lexeme); i >= 0 {
+		return i, i + 1
+	}
+	return 0, 0
+}
+
+// phraseResult is the result of evaluating a node within a phrase. The
+// result is "maybe" if the vector does not have the position information
+// required to decide.
+type phraseResult int
+
+const (
+	phraseNo phraseResult = iota
+	phraseYes
+	phraseMaybe
+)
+
+// phraseData describes the positions at which a node within a phrase
+// matches. A match of width w at position p spans positions p-w to p. If
+// negate is set, the node matches at every position except those listed.
+type phraseData struct {
+	positions []int
+	width     int
+	negate    bool
+}
+
+// phraseOutput controls which positions are emitted when merging the
+// positions of the two children of a node.
+type phraseOutput int
+
+const (
+	outputBoth phraseOutput = 1 << iota
+	outputLeftOnly
+	outputRightOnly
+)
+
+// evalPhrase evaluates a node within a phrase operator, following the
+// semantics of Postgres' TS_phrase_execute.
+func evalPhrase(n *QueryNode, v TSVector) (phraseResult, phraseData) {
+	switch n.Op {
+	case OpLexeme:
+		var data phraseData
+		start, end := lexemeRange(n, v)
+		if start == end {
+			return phraseNo, data
+		}
+		for i := start; i < end; i++ {
+			if len(v[i].Positions) == 0 {
+				return phraseMaybe, data
+			}
+			for _, p := range v[i].Positions {
+				if n.Weights == 0 || n.Weights&(1<<p.Weight) != 0 {
+					data.positions = append(data.positions, int(p.Pos))
+				}
+			}
+		}
+		if len(data.positions) == 0 {
+			return phraseNo, data
+		}
+		if n.Prefix {
+			data.positions = sortedUnique(data.positions)
+		}
+		return phraseYes, data
+
+	case OpNot:
+		res, data := evalPhrase(n.Left, v)
+		switch res {
+		case phraseNo:
+			// Matching nothing becomes matching everything.
+			return phraseYes, phraseData{negate: true}
+		case phraseYes:
+			if len(data.positions) > 0 {
+				data.negate = !data.negate
+				return phraseYes, data
+			}
+			// Matching everything becomes matching nothing.
+			return phraseNo, phraseData{}
+		}
+		return phraseMaybe, phraseData{}
+
+	case OpAnd, OpPhrase:
+		lres, ldata := evalPhrase(n.Left, v)
+		if lres == phraseNo {
+			return phraseNo, phraseData{}
+		}
+		rres, rdata := evalPhrase(n.Right, v)
+		if rres == phraseNo {
+			return phraseNo, phraseData{}
+		}
+		if lres == phraseMaybe || rres == phraseMaybe {
+			return phraseMaybe, phraseData{}
+		}
+		var out phraseData
+		var loffset, roffset int
+		if n.Op == OpPhrase {
+			loffset = int(n.Distance) + rdata.width
+			out.width = int(n.Distance) + ldata.width + rdata.width
+		} else {
+			out.width = maxInt(ldata.width, rdata.width)
+			loffset, roffset = out.width-ldata.width, out.width-rdata.width
+		}
+		var emit phraseOutput
+		switch {
+		case ldata.negate && rdata.negate:
+			// !L <-> !R is !(L | R).
+			out.negate = true
+			out.positions = mergePositions(ldata, rdata, outputBoth|outputLeftOnly|outputRightOnly, loffset, roffset)
+			return phraseYes, out
+		case ldata.negate:
+			// !L <-> R is R & !L.
+			emit = outputRightOnly
+		case rdata.negate:
+			// L <-> !R is L & !R.
+			emit = outputLeftOnly
+		default:
+			emit = outputBoth
+		}
+		out.positions = mergePositions(ldata, rdata, emit, loffset, roffset)
+		if len(out.positions) > 0 {
+			return phraseYes, out
+		}
+		return phraseNo, phraseData{}
+
+	case OpOr:
+		lres, ldata := evalPhrase(n.Left, v)
+		rres, rdata := evalPhrase(n.Right, v)
+		if lres == phraseNo && rres == phraseNo {
+			return phraseNo, phraseData{}
+		}
+		if lres == phraseMaybe || rres == phraseMaybe {
+			return phraseMaybe, phraseData{}
+		}
+		var out phraseData
+		out.width = maxInt(ldata.width, rdata.width)
+		loffset, roffset := out.width-ldata.width, out.width-rdata.width
+		switch {
+		case ldata.negate && rdata.negate:
+			// !L | !R is !(L & R).
+			out.negate = true
+			out.positions = mergePositions(ldata, rdata, outputBoth, loffset, roffset)
+			return phraseYes, out
+		case ldata.negate:
+			// !L | R is !(L & !R).
+			out.negate = true
+			out.positions = mergePositions(ldata, rdata, outputLeftOnly, loffset, roffset)
+			return phraseYes, out
+		case rdata.negate:
+			// L | !R is !(!L & R).
+			out.negate = true
+			out.positions = mergePositions(ldata, rdata, outputRightOnly, loffset, roffset)
+			return phraseYes, out
+		}
+		out.positions = mergePositions(ldata, rdata, outputBoth|outputLeftOnly|outputRightOnly, loffset, roffset)
+		if len(out.positions) > 0 {
+			return phraseYes, out
+		}
+		return phraseNo, phraseData{}
+	}
+	return phraseNo, phraseData{}
+}
+
+// mergePositions merges the sorted positions of the two children of a node,
+// after shifting them by the given offsets. Positions present in both
+// children, only the left, or only the right are emitted according to emit.
+func mergePositions(l, r phraseData, emit phraseOutput, loffset, roffset int) []int {
+	const inf = int(^uint(0) >> 1)
+	var out []int
+	i, j := 0, 0
+	for i < len(l.positions) || j < len(r.positions) {
+		lpos, rpos := inf, inf
+		if i < len(l.positions) {
+			lpos = l.positions[i] + loffset
+		} else if emit&outputRightOnly == 0 {
+			break
+		}
+		if j < len(r.positions) {
+			rpos = r.positions[j] + roffset
+		} else if emit&outputLeftOnly == 0 {
+			break
+		}
+		switch {
+		case lpos < rpos:
+			if emit&outputLeftOnly != 0 {
+				out = append(out, lpos)
+			}
+			i++
+		case lpos == rpos:
+			if emit&outputBoth != 0 {
+				out = append(out, rpos)
+			}
+			i++
+			j++
+		default:
+			if emit&outputRightOnly != 0 {
+				out = append(out, rpos)
+			}
+			j++
+		}
+	}
+	return out
+}
+
+func sortedUnique(positions []int) []int {
+	sort.Ints(positions)
+	ret := positions[:0]
+	for i, p := range positions {
+		if i == 0 || p != positions[i-1] {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,184 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// lexer tokenizes the text representation of TSVector and TSQuery values.
+type lexer struct {
+	input string
+	pos   int
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\f', '\v':
+		return true
+	}
+	return false
+}
+
+// isQueryOperator returns whether the given character terminates an unquoted
+// word in a TSQuery.
+func isQueryOperator(c byte) bool {
+	switch c {
+	case '!', '&', '|', '(', ')', '<':
+		return true
+	}
+	return false
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (l *lexer) done() bool {
+	return l.pos >= len(l.input)
+}
+
+// peek returns the next character of the input, or 0 at the end of the
+// input.
+func (l *lexer) peek() byte {
+	if l.done() {
+		return 0
+	}
+	return l.input[l.pos]
+}
+
+func (l *lexer) skipSpace() {
+	for !l.done() && isSpace(l.input[l.pos]) {
+		l.pos++
+	}
+}
+
+func (l *lexer) syntaxError(typ string) error {
+	return pgerror.Newf(pgcode.Syntax, "syntax error in %s: %q", typ, l.input)
+}
+
+// scanWord scans a quoted or unquoted word. Within a word, a backslash
+// escapes the following character, and within a quoted word a quote can
+// also be escaped by doubling it.
+func (l *lexer) scanWord(inQuery bool) (string, error) {
+	typ := "tsvector"
+	if inQuery {
+		typ = "tsquery"
+	}
+	var buf strings.Builder
+	if l.peek() == '\'' {
+		l.pos++
+		for {
+			if l.done() {
+				return "", l.syntaxError(typ)
+			}
+			c := l.input[l.pos]
+			l.pos++
+			switch c {
+			case '\\':
+				if l.done() {
+					return "", l.syntaxError(typ)
+				}
+				buf.WriteByte(l.input[l.pos])
+				l.pos++
+				continue
+			case '\'':
+				if l.peek() == '\'' {
+					buf.WriteByte('\'')
+					l.pos++
+					continue
+				}
+			default:
+				buf.WriteByte(c)
+				continue
+			}
+			break
+		}
+		if buf.Len() == 0 {
+			return "", l.syntaxError(typ)
+		}
+	} else {
+		for !l.done() {
+			c := l.input[l.pos]
+			if isSpace(c) || c == ':' || c == '\'' || (inQuery && isQueryOperator(c)) {
+				break
+			}
+			l.pos++
+			if c == '\\' {
+				if l.done() {
+					return "", l.syntaxError(typ)
+				}
+				c = l.input[l.pos]
+				l.pos++
+			}
+			buf.WriteByte(c)
+		}
+		if buf.Len() == 0 {
+			return "", l.syntaxError(typ)
+		}
+	}
+	word := buf.String()
+	if err := checkLexeme(word); err != nil {
+		return "", err
+	}
+	return word, nil
+}
+
+// scanInt scans a non-negative decimal integer, returning false if the input
+// does not start with a digit. Values larger than limit are clamped to it.
+func (l *lexer) scanInt(limit int) (int, bool) {
+	if !isDigit(l.peek()) {
+		return 0, false
+	}
+	n := 0
+	for isDigit(l.peek()) {
+		n = n*10 + int(l.input[l.pos]-'0')
+		if n > limit {
+			n = limit + 1
+		}
+		l.pos++
+	}
+	if n > limit {
+		n = limit
+	}
+	return n, true
+}
+
+// scanPositions scans a comma-separated list of positions, each optionally
+// followed by a weight, as in 1,3A,7B.
+func (l *lexer) scanPositions() ([]Position, error) {
+	var positions []Position
+	for {
+		n, ok := l.scanInt(MaxPosition)
+		if !ok {
+			return nil, l.syntaxError("tsvector")
+		}
+		if n == 0 {
+			return nil, pgerror.Newf(pgcode.Syntax, "wrong position info in tsvector: %q", l.input)
+		}
+		p := Position{Pos: uint16(n)}
+		if w, ok := weightFromByte(l.peek()); ok {
+			p.Weight = w
+			l.pos++
+		} else if l.peek() == '*' {
+			// Postgres accepts and ignores a trailing asterisk.
+			l.pos++
+		}
+		positions = append(positions, p)
+		if l.peek() != ',' {
+			return positions, nil
+		}
+		l.pos++
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "math/rand"
+
+var randomWords = []string{
+	"a", "cat", "fat", "rat", "sat", "mat", "on", "the", "it's", "two words",
+}
+
+// RandomTSVector generates a random TSVector.
+func RandomTSVector(rng *rand.Rand) TSVector {
+	n := rng.Intn(len(randomWords))
+	v := make(TSVector, 0, n)
+	for i := 0; i < n; i++ {
+		l := Lexeme{Word: randomWords[rng.Intn(len(randomWords))]}
+		for j, numPositions := 0, rng.Intn(4); j < numPositions; j++ {
+			l.Positions = append(l.Positions, Position{
+				Pos:    uint16(1 + rng.Intn(MaxPosition)),
+				Weight: Weight(rng.Intn(4)),
+			})
+		}
+		v = append(v, l)
+	}
+	return v.normalize()
+}
+
+// RandomTSQuery generates a random TSQuery.
+func RandomTSQuery(rng *rand.Rand) TSQuery {
+	if rng.Intn(10) == 0 {
+		return TSQuery{}
+	}
+	return TSQuery{Root: randomQueryNode(rng, 4 /* depth */)}
+}
+
+func randomQueryNode(rng *rand.Rand, depth int) *QueryNode {
+	if depth <= 0 || rng.Intn(3) == 0 {
+		return &QueryNode{
+			Op:      OpLexeme,
+			Lexeme:  randomWords[rng.Intn(len(randomWords))],
+			Prefix:  rng.Intn(5) == 0,
+			Weights: byte(rng.Intn(16)),
+		}
+	}
+	n := &QueryNode{Left: randomQueryNode(rng, depth-1)}
+	switch rng.Intn(4) {
+	case 0:
+		n.Op = OpAnd
+	case 1:
+		n.Op = OpOr
+	case 2:
+		n.Op = OpPhrase
+		n.Distance = uint16(rng.Intn(4))
+	default:
+		n.Op = OpNot
+		return n
+	}
+	n.Right = randomQueryNode(rng, depth-1)
+	return n
+}
@@ -0,0 +1,229 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultRankWeights are the weights given to lexemes of weight D, C, B and
+// A respectively when ranking a document.
+var DefaultRankWeights = [4]float32{0.1, 0.2, 0.4, 1.0}
+
+// The normalization options for Rank, which may be combined. They
+// determine how the rank of a document is scaled according to its length.
+const (
+	// RankNormLogLength divides the rank by 1 + the logarithm of the length
+	// of the document.
+	RankNormLogLength = 1
+	// RankNormLength divides the rank by the length of the document.
+	RankNormLength = 2
+	// RankNormUniq divides the rank by the number of unique words in the
+	// document.
+	RankNormUniq = 8
+	// RankNormLogUniq divides the rank by 1 + the logarithm of the number of
+	// unique words in the document.
+	RankNormLogUniq = 16
+	// RankNormRDivRPlus1 divides the rank by itself + 1.
+	RankNormRDivRPlus1 = 32
+)
+
+// MakeRankWeights validates the given D, C, B and A weights, as passed to
+// ts_rank, and returns them as an array. Negative weights are replaced by
+// the default.
+func MakeRankWeights(weights []float64) ([4]float32, error) {
+	var ret [4]float32
+	if len(weights) < len(ret) {
+		return ret, pgerror.New(pgcode.ArraySubscript, "array of weight is too short")
+	}
+	for i := range ret {
+		switch w := weights[i]; {
+		case w < 0:
+			ret[i] = DefaultRankWeights[i]
+		case w > 1:
+			return ret, pgerror.New(pgcode.InvalidParameterValue, "weight out of range")
+		default:
+			ret[i] = float32(w)
+		}
+	}
+	return ret, nil
+}
+
+// Rank ranks a document against a query, implementing ts_rank. The rank is
+// based on the frequency of the query's lexemes in the document, their
+// weights, and, if the query is a conjunction or phrase, their proximity.
+// method is a combination of the RankNorm options.
+func Rank(weights [4]float32, v TSVector, q TSQuery, method int) float32 {
+	if len(v) == 0 || q.Root == nil {
+		return 0
+	}
+	operands := q.uniqueOperands()
+	var res float32
+	if (q.Root.Op == OpAnd || q.Root.Op == OpPhrase) && len(operands) >= 2 {
+		res = rankAnd(weights, v, operands)
+	} else {
+		res = rankOr(weights, v, operands)
+	}
+	if res < 0 {
+		res = 1e-20
+	}
+	if method&RankNormLogLength != 0 {
+		res = float32(float64(res) / (math.Log(float64(v.length()+1)) / math.Log(2)))
+	}
+	if method&RankNormLength != 0 {
+		if length := v.length(); length > 0 {
+			res /= float32(length)
+		}
+	}
+	if method&RankNormUniq != 0 {
+		res /= float32(len(v))
+	}
+	if method&RankNormLogUniq != 0 {
+		res = float32(float64(res) / (math.Log(float64(len(v)+1)) / math.Log(2)))
+	}
+	if method&RankNormRDivRPlus1 != 0 {
+		res /= res + 1
+	}
+	return res
+}
+
+// length returns the number of words in the document that the vector was
+// built from, counting a lexeme without positions as a single word.
+func (v TSVector) length() int {
+	length := 0
+	for _, l := range v {
+		if len(l.Positions) == 0 {
+			length++
+		} else {
+			length += len(l.Positions)
+		}
+	}
+	return length
+}
+
+// uniqueOperands returns the operands of the query, sorted and without
+// duplicate lexemes. Operands under NOT operators are included.
+func (q TSQuery) uniqueOperands() []*QueryNode {
+	var operands []*QueryNode
+	var collect func(n *QueryNode)
+	collect = func(n *QueryNode) {
+		if n == nil {
+			return
+		}
+		if n.Op == OpLexeme {
+			operands = append(operands, n)
+		}
+		collect(n.Left)
+		collect(n.Right)
+	}
+	collect(q.Root)
+	sort.SliceStable(operands, func(i, j int) bool { return operands[i].Lexeme < operands[j].Lexeme })
+	ret := operands[:0]
+	for i, n := range operands {
+		if i == 0 || n.Lexeme != operands[i-1].Lexeme {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
+// wordDistance returns the factor by which the contribution of two lexemes
+// to the rank is scaled, given the distance between them.
+func wordDistance(distance int) float32 {
+	if distance > 100 {
+		return 1e-30
+	}
+	return float32(1.0 / (1.005 + 0.05*math.Exp(float64(float32(distance))/1.5-2)))
+}
+
+// rankAnd computes the rank of a conjunction, based on the proximity of each
+// pair of matching lexemes. Lexemes without positions are treated as being
+// at the largest possible position.
+func rankAnd(weights [4]float32, v TSVector, operands []*QueryNode) float32 {
+	nullPositions := []Position{{Pos: MaxPosition}}
+	var res float32 = -1
+	positions := make([][]Position, len(operands))
+	isNull := make([]bool, len(operands))
+	for i, n := range operands {
+		start, end := lexemeRange(n, v)
+		for e := start; e < end; e++ {
+			positions[i] = v[e].Positions
+			isNull[i] = len(positions[i]) == 0
+			if isNull[i] {
+				positions[i] = nullPositions
+			}
+			for k := 0; k < i; k++ {
+				if positions[k] == nil {
+					continue
+				}
+				for _, p := range positions[i] {
+					for _, other := range positions[k] {
+						dist := int(p.Pos) - int(other.Pos)
+						if dist < 0 {
+							dist = -dist
+						}
+						if dist == 0 && !isNull[i] && !isNull[k] {
+							continue
+						}
+						if dist == 0 {
+							dist = MaxPosition + 1
+						}
+						curw := float32(math.Sqrt(float64(weights[p.Weight] * weights[other.Weight] * wordDistance(dist))))
+						if res < 0 {
+							res = curw
+						} else {
+							res = float32(1.0 - (1.0-float64(res))*(1.0-float64(curw)))
+						}
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// rankOr computes the rank of a disjunction, based on the number of
+// occurrences of each matching lexeme and their weights.
+func rankOr(weights [4]float32, v TSVector, operands []*QueryNode) float32 {
+	nullPositions := []Position{{Pos: 0}}
+	var res float32
+	for _, n := range operands {
+		start, end := lexemeRange(n, v)
+		for e := start; e < end; e++ {
+			positions := v[e].Positions
+			if len(positions) == 0 {
+				positions = nullPositions
+			}
+			var resj float32
+			var wjm float32 = -1
+			jm := 0
+			for j, p := range positions {
+				w := weights[p.Weight]
+				resj += w / float32((j+1)*(j+1))
+				if w > wjm {
+					wjm = w
+					jm = j
+				}
+			}
+			// The sum of 1/i^2 for i from 1 to infinity is pi^2/6.
+			res = float32(float64(res) +
+				float64(wjm+resj-wjm/float32((jm+1)*(jm+1)))/1.64493406685)
+		}
+	}
+	if len(operands) > 0 {
+		res /= float32(len(operands))
+	}
+	return res
+}
@@ -0,0 +1,389 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+// This file implements the Snowball English ("Porter2") stemming algorithm,
+// which is the algorithm used by the english_stem dictionary in Postgres.
+// See https://snowballstem.org/algorithms/english/stemmer.html. Only ASCII
+// letters are treated as vowels, so words in other scripts are mostly left
+// unchanged.
+
+// stemExceptions are words that are stemmed to a fixed form, or left alone,
+// before the algorithm is applied.
+var stemExceptions = map[string]string{
+	"skis":   "ski",
+	"skies":  "sky",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+// postStep1aExceptions are words that are left alone once step 1a has been
+// applied.
+var postStep1aExceptions = map[string]struct{}{
+	"inning":  {},
+	"outing":  {},
+	"canning": {},
+	"herring": {},
+	"earring": {},
+	"proceed": {},
+	"exceed":  {},
+	"succeed": {},
+}
+
+// regionPrefixes are prefixes that define the R1 region of a word in place
+// of the usual rule.
+var regionPrefixes = []string{"gener", "commun", "arsen"}
+
+// stemEnglish returns the stem of the given lower case word.
+func stemEnglish(word string) string {
+	if stem, ok := stemExceptions[word]; ok {
+		return stem
+	}
+	s := stemmer{w: []rune(word)}
+	if len(s.w) < 3 {
+		return word
+	}
+	s.prelude()
+	s.markRegions()
+	s.step1a()
+	if _, ok := postStep1aExceptions[string(s.w)]; !ok {
+		s.step1b()
+		s.step1c()
+		s.step2()
+		s.step3()
+		s.step4()
+		s.step5()
+	}
+	for i, r := range s.w {
+		if r == 'Y' {
+			s.w[i] = 'y'
+		}
+	}
+	return string(s.w)
+}
+
+// stemmer holds the state of the stemming algorithm for a single word. p1
+// and p2 are the start of the R1 and R2 regions of the word.
+type stemmer struct {
+	w      []rune
+	p1, p2 int
+}
+
+func isVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+// prelude marks consonant y's as Y, so that they are not treated as vowels.
+func (s *stemmer) prelude() {
+	if s.w[0] == 'y' {
+		s.w[0] = 'Y'
+	}
+	for i := 1; i < len(s.w); i++ {
+		if s.w[i] == 'y' && isVowel(s.w[i-1]) {
+			s.w[i] = 'Y'
+		}
+	}
+}
+
+// regionStart returns the position following the first non-vowel that
+// follows a vowel at or after start, or the length of the word if there is
+// none.
+func (s *stemmer) regionStart(start int) int {
+	for i := start + 1; i < len(s.w); i++ {
+		if !isVowel(s.w[i]) && isVowel(s.w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(s.w)
+}
+
+func (s *stemmer) markRegions() {
+	s.p1 = -1
+	for _, prefix := range regionPrefixes {
+		if s.hasPrefix(prefix) {
+			s.p1 = len(prefix)
+			break
+		}
+	}
+	if s.p1 < 0 {
+		s.p1 = s.regionStart(0)
+	}
+	s.p2 = s.regionStart(s.p1)
+}
+
+func (s *stemmer) hasPrefix(prefix string) bool {
+	if len(s.w) < len(prefix) {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		if s.w[i] != rune(prefix[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *stemmer) hasSuffix(suffix string) bool {
+	if len(s.w) < len(suffix) {
+		return false
+	}
+	offset := len(s.w) - len(suffix)
+	for i := 0; i < len(suffix); i++ {
+		if s.w[offset+i] != rune(suffix[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// longestSuffix returns the longest of the given suffixes that the word ends
+// with, or the empty string if there is none.
+func (s *stemmer) longestSuffix(suffixes ...string) string {
+	longest := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(longest) && s.hasSuffix(suffix) {
+			longest = suffix
+		}
+	}
+	return longest
+}
+
+// replaceSuffix replaces the last n characters of the word with repl.
+func (s *stemmer) replaceSuffix(n int, repl string) {
+	s.w = append(s.w[:len(s.w)-n], []rune(repl)...)
+}
+
+// hasVowel returns whether the first end characters of the word contain a
+// vowel.
+func (s *stemmer) hasVowel(end int) bool {
+	for _, r := range s.w[:end] {
+		if isVowel(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsShortSyllable returns whether the first end characters of the word end
+// in a short syllable, i.e. either a non-vowel other than w, x or Y preceded
+// by a vowel preceded by a non-vowel, or a vowel at the beginning of the word
+// followed by a non-vowel.
+func (s *stemmer) endsShortSyllable(end int) bool {
+	if end >= 3 {
+		last := s.w[end-1]
+		return !isVowel(last) && last != 'w' && last != 'x' && last != 'Y' &&
+			isVowel(s.w[end-2]) && !isVowel(s.w[end-3])
+	}
+	return end == 2 && isVowel(s.w[0]) && !isVowel(s.w[1])
+}
+
+func (s *stemmer) step1a() {
+	switch suffix := s.longestSuffix("sses", "ied", "ies", "us", "ss", "s"); suffix {
+	case "sses":
+		s.replaceSuffix(len(suffix), "ss")
+	case "ied", "ies":
+		if len(s.w) > 4 {
+			s.replaceSuffix(len(suffix), "i")
+		} else {
+			s.replaceSuffix(len(suffix), "ie")
+		}
+	case "s":
+		if s.hasVowel(len(s.w) - 2) {
+			s.replaceSuffix(len(suffix), "")
+		}
+	}
+}
+
+func (s *stemmer) step1b() {
+	suffix := s.longestSuffix("eed", "eedly", "ed", "edly", "ing", "ingly")
+	start := len(s.w) - len(suffix)
+	switch suffix {
+	case "":
+		return
+	case "eed", "eedly":
+		if start >= s.p1 {
+			s.replaceSuffix(len(suffix), "ee")
+		}
+		return
+	}
+	if !s.hasVowel(start) {
+		return
+	}
+	s.replaceSuffix(len(suffix), "")
+	switch {
+	case s.hasSuffix("at") || s.hasSuffix("bl") || s.hasSuffix("iz"):
+		s.replaceSuffix(0, "e")
+	case s.endsInDouble():
+		s.replaceSuffix(1, "")
+	case len(s.w) == s.p1 && s.endsShortSyllable(len(s.w)):
+		s.replaceSuffix(0, "e")
+	}
+}
+
+// endsInDouble returns whether the word ends in one of bb, dd, ff, gg, mm,
+// nn, pp, rr or tt.
+func (s *stemmer) endsInDouble() bool {
+	n := len(s.w)
+	if n < 2 || s.w[n-1] != s.w[n-2] {
+		return false
+	}
+	switch s.w[n-1] {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	}
+	return false
+}
+
+func (s *stemmer) step1c() {
+	n := len(s.w)
+	if (s.w[n-1] == 'y' || s.w[n-1] == 'Y') && n > 2 && !isVowel(s.w[n-2]) {
+		s.w[n-1] = 'i'
+	}
+}
+
+var step2Replacements = map[string]string{
+	"tional":  "tion",
+	"enci":    "ence",
+	"anci":    "ance",
+	"abli":    "able",
+	"entli":   "ent",
+	"izer":    "ize",
+	"ization": "ize",
+	"ational": "ate",
+	"ation":   "ate",
+	"ator":    "ate",
+	"alism":   "al",
+	"aliti":   "al",
+	"alli":    "al",
+	"fulness": "ful",
+	"ousli":   "ous",
+	"ousness": "ous",
+	"iveness": "ive",
+	"iviti":   "ive",
+	"biliti":  "ble",
+	"bli":     "ble",
+	"ogi":     "og",
+	"fulli":   "ful",
+	"lessli":  "less",
+	"li":      "",
+}
+
+var step2Suffixes = mapKeys(step2Replacements)
+
+func (s *stemmer) step2() {
+	suffix := s.longestSuffix(step2Suffixes...)
+	start := len(s.w) - len(suffix)
+	if suffix == "" || start < s.p1 {
+		return
+	}
+	switch suffix {
+	case "ogi":
+		if start == 0 || s.w[start-1] != 'l' {
+			return
+		}
+	case "li":
+		if start == 0 || !isValidLIEnding(s.w[start-1]) {
+			return
+		}
+	}
+	s.replaceSuffix(len(suffix), step2Replacements[suffix])
+}
+
+// isValidLIEnding returns whether a trailing li preceded by the given
+// character can be removed.
+func isValidLIEnding(r rune) bool {
+	switch r {
+	case 'c', 'd', 'e', 'g', 'h', 'k', 'm', 'n', 'r', 't':
+		return true
+	}
+	return false
+}
+
+var step3Replacements = map[string]string{
+	"tional":  "tion",
+	"ational": "ate",
+	"alize":   "al",
+	"icate":   "ic",
+	"iciti":   "ic",
+	"ical":    "ic",
+	"ful":     "",
+	"ness":    "",
+	"ative":   "",
+}
+
+var step3Suffixes = mapKeys(step3Replacements)
+
+func (s *stemmer) step3() {
+	suffix := s.longestSuffix(step3Suffixes...)
+	start := len(s.w) - len(suffix)
+	if suffix == "" || start < s.p1 || (suffix == "ative" && start < s.p2) {
+		return
+	}
+	s.replaceSuffix(len(suffix), step3Replacements[suffix])
+}
+
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+}
+
+func (s *stemmer) step4() {
+	suffix := s.longestSuffix(step4Suffixes...)
+	start := len(s.w) - len(suffix)
+	if suffix == "" || start < s.p2 {
+		return
+	}
+	if suffix == "ion" && (start == 0 || (s.w[start-1] != 's' && s.w[start-1] != 't')) {
+		return
+	}
+	s.replaceSuffix(len(suffix), "")
+}
+
+func (s *stemmer) step5() {
+	n := len(s.w)
+	start := n - 1
+	switch s.w[start] {
+	case 'e':
+		if start >= s.p2 || (start >= s.p1 && !s.endsShortSyllable(start)) {
+			s.replaceSuffix(1, "")
+		}
+	case 'l':
+		if start >= s.p2 && start > 0 && s.w[start-1] == 'l' {
+			s.replaceSuffix(1, "")
+		}
+	}
+}
+
+func mapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
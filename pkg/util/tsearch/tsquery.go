@@ -0,0 +1,420 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// maxPhraseDistance is the largest distance allowed in a phrase operator.
+const maxPhraseDistance = 16384
+
+// Operator is the type of a node in a TSQuery.
+type Operator byte
+
+const (
+	// OpLexeme is a leaf node that matches a single lexeme, or any lexeme
+	// starting with a prefix.
+	OpLexeme Operator = iota + 1
+	// OpAnd matches if both of its children match.
+	OpAnd
+	// OpOr matches if either of its children match.
+	OpOr
+	// OpNot matches if its child does not match.
+	OpNot
+	// OpPhrase matches if its children match at positions that are exactly
+	// Distance apart.
+	OpPhrase
+	// opStopword is a placeholder for an operand that was entirely made of
+	// stopwords during normalization. It never appears in a parsed TSQuery.
+	opStopword
+)
+
+// priority returns the binding strength of the operator, used to decide
+// where parentheses are needed in the text representation of a query.
+func (o Operator) priority() int {
+	switch o {
+	case OpOr:
+		return 1
+	case OpAnd:
+		return 2
+	case OpPhrase:
+		return 3
+	case OpNot:
+		return 4
+	}
+	return 5
+}
+
+// QueryNode is a node in the tree of a TSQuery.
+type QueryNode struct {
+	Op Operator
+	// Lexeme, Prefix and Weights are set for OpLexeme nodes. Weights is a
+	// bitmask with bit 1<<w set for each Weight w that the lexeme must have
+	// in order to match; zero means any weight matches.
+	Lexeme  string
+	Prefix  bool
+	Weights byte
+	// Distance is set for OpPhrase nodes.
+	Distance uint16
+	// Left is set for all operators; Right is set for binary operators.
+	Left, Right *QueryNode
+}
+
+// TSQuery is a parsed text search query. The zero value is the empty query,
+// which matches nothing.
+type TSQuery struct {
+	Root *QueryNode
+}
+
+// ParseTSQuery parses the text representation of a TSQuery, such as
+// 'fat' & ( 'rat' | 'cat':*B ). Lexemes are not normalized.
+func ParseTSQuery(input string) (TSQuery, error) {
+	return parseTSQuery(input, nil /* normalize */)
+}
+
+// normalizeFunc converts an operand of a query into the query that should
+// replace it.
+type normalizeFunc func(n *QueryNode) (*QueryNode, error)
+
+func parseTSQuery(input string, normalize normalizeFunc) (TSQuery, error) {
+	p := queryParser{lexer: lexer{input: input}, normalize: normalize}
+	p.skipSpace()
+	if p.done() {
+		return TSQuery{}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return TSQuery{}, err
+	}
+	p.skipSpace()
+	if !p.done() {
+		return TSQuery{}, p.syntaxError("tsquery")
+	}
+	return TSQuery{Root: root}, nil
+}
+
+// queryParser is a recursive descent parser for the text representation of
+// a TSQuery. Operators in decreasing order of precedence are !, <N>, & and |.
+type queryParser struct {
+	lexer
+	normalize normalizeFunc
+}
+
+func (p *queryParser) parseOr() (*QueryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.peek() != '|' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &QueryNode{Op: OpOr, Left: left, Right: right}
+	}
+}
+
+func (p *queryParser) parseAnd() (*QueryNode, error) {
+	left, err := p.parsePhrase()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.peek() != '&' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parsePhrase()
+		if err != nil {
+			return nil, err
+		}
+		left = &QueryNode{Op: OpAnd, Left: left, Right: right}
+	}
+}
+
+func (p *queryParser) parsePhrase() (*QueryNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.peek() != '<' {
+			return left, nil
+		}
+		distance, err := p.scanPhraseOperator()
+		if err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &QueryNode{Op: OpPhrase, Distance: distance, Left: left, Right: right}
+	}
+}
+
+// scanPhraseOperator scans a <-> or <N> operator, returning its distance.
+func (p *queryParser) scanPhraseOperator() (uint16, error) {
+	p.pos++
+	var distance int
+	if p.peek() == '-' {
+		p.pos++
+		distance = 1
+	} else {
+		var ok bool
+		if distance, ok = p.scanInt(maxPhraseDistance + 1); !ok {
+			return 0, p.syntaxError("tsquery")
+		}
+		if distance > maxPhraseDistance {
+			return 0, pgerror.Newf(pgcode.InvalidParameterValue,
+				"distance in phrase operator must be an integer value between zero and %d inclusive",
+				maxPhraseDistance)
+		}
+	}
+	if p.peek() != '>' {
+		return 0, p.syntaxError("tsquery")
+	}
+	p.pos++
+	return uint16(distance), nil
+}
+
+func (p *queryParser) parseNot() (*QueryNode, error) {
+	p.skipSpace()
+	switch p.peek() {
+	case '!':
+		p.pos++
+		child, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &QueryNode{Op: OpNot, Left: child}, nil
+	case '(':
+		p.pos++
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.peek() != ')' {
+			return nil, p.syntaxError("tsquery")
+		}
+		p.pos++
+		return n, nil
+	}
+	return p.parseOperand()
+}
+
+// parseOperand parses a lexeme, optionally followed by a colon and any
+// combination of a prefix marker (*) and weight letters.
+func (p *queryParser) parseOperand() (*QueryNode, error) {
+	if p.done() {
+		return nil, p.syntaxError("tsquery")
+	}
+	word, err := p.scanWord(true /* inQuery */)
+	if err != nil {
+		return nil, err
+	}
+	n := &QueryNode{Op: OpLexeme, Lexeme: word}
+	if p.peek() == ':' {
+		p.pos++
+		for !p.done() {
+			if c := p.peek(); c == '*' {
+				n.Prefix = true
+			} else if w, ok := weightFromByte(c); ok {
+				n.Weights |= 1 << w
+			} else {
+				break
+			}
+			p.pos++
+		}
+	}
+	if p.normalize != nil {
+		return p.normalize(n)
+	}
+	return n, nil
+}
+
+// String returns the text representation of the query.
+func (q TSQuery) String() string {
+	if q.Root == nil {
+		return ""
+	}
+	var buf strings.Builder
+	q.Root.format(&buf, 0 /* parentPriority */, false /* rightOfPhrase */)
+	return buf.String()
+}
+
+// format writes the node to buf, parenthesizing it if it binds less tightly
+// than its parent. The right child of a phrase operator is also parenthesized
+// if it is a phrase, since phrase operators are left-associative.
+func (n *QueryNode) format(buf *strings.Builder, parentPriority int, rightOfPhrase bool) {
+	switch n.Op {
+	case OpLexeme:
+		writeQuotedLexeme(buf, n.Lexeme)
+		if n.Prefix || n.Weights != 0 {
+			buf.WriteByte(':')
+			if n.Prefix {
+				buf.WriteByte('*')
+			}
+			for w := WeightA; ; w-- {
+				if n.Weights&(1<<w) != 0 {
+					buf.WriteString(w.String())
+				}
+				if w == WeightD {
+					break
+				}
+			}
+		}
+	case OpNot:
+		buf.WriteByte('!')
+		n.Left.format(buf, n.Op.priority(), false /* rightOfPhrase */)
+	default:
+		priority := n.Op.priority()
+		parens := priority < parentPriority || (n.Op == OpPhrase && rightOfPhrase)
+		if parens {
+			buf.WriteString("( ")
+		}
+		n.Left.format(buf, priority, false /* rightOfPhrase */)
+		switch n.Op {
+		case OpAnd:
+			buf.WriteString(" & ")
+		case OpOr:
+			buf.WriteString(" | ")
+		case OpPhrase:
+			if n.Distance == 1 {
+				buf.WriteString(" <-> ")
+			} else {
+				buf.WriteString(" <")
+				buf.WriteString(strconv.Itoa(int(n.Distance)))
+				buf.WriteString("> ")
+			}
+		}
+		n.Right.format(buf, priority, n.Op == OpPhrase)
+		if parens {
+			buf.WriteString(" )")
+		}
+	}
+}
+
+// Size returns the approximate size of the query in bytes.
+func (q TSQuery) Size() uintptr {
+	return q.Root.size()
+}
+
+func (n *QueryNode) size() uintptr {
+	if n == nil {
+		return 0
+	}
+	return 56 + uintptr(len(n.Lexeme)) + n.Left.size() + n.Right.size()
+}
+
+// NumNodes returns the number of lexemes and operators in the query.
+func (q TSQuery) NumNodes() int {
+	return q.Root.numNodes()
+}
+
+func (n *QueryNode) numNodes() int {
+	if n == nil {
+		return 0
+	}
+	return 1 + n.Left.numNodes() + n.Right.numNodes()
+}
+
+// Compare compares two queries, returning -1, 0 or 1. The ordering is not
+// meaningful beyond being a total order that is consistent with equality.
+func (q TSQuery) Compare(other TSQuery) int {
+	return strings.Compare(q.String(), other.String())
+}
+
+// Encode appends the binary encoding of the query to the given buffer.
+func (q TSQuery) Encode(b []byte) []byte {
+	return q.Root.encode(b)
+}
+
+func (n *QueryNode) encode(b []byte) []byte {
+	if n == nil {
+		return b
+	}
+	b = append(b, byte(n.Op))
+	switch n.Op {
+	case OpLexeme:
+		b = appendUvarint(b, uint64(len(n.Lexeme)))
+		b = append(b, n.Lexeme...)
+		flags := n.Weights
+		if n.Prefix {
+			flags |= 1 << 4
+		}
+		b = append(b, flags)
+	case OpNot:
+		b = n.Left.encode(b)
+	default:
+		if n.Op == OpPhrase {
+			b = appendUvarint(b, uint64(n.Distance))
+		}
+		b = n.Left.encode(b)
+		b = n.Right.encode(b)
+	}
+	return b
+}
+
+// DecodeTSQuery decodes a query encoded with Encode.
+func DecodeTSQuery(b []byte) (TSQuery, error) {
+	if len(b) == 0 {
+		return TSQuery{}, nil
+	}
+	d := decoder{b: b}
+	root := d.queryNode()
+	if d.err == nil && len(d.b) > 0 {
+		d.err = errors.AssertionFailedf("%d trailing bytes in encoded tsquery", len(d.b))
+	}
+	if d.err != nil {
+		return TSQuery{}, d.err
+	}
+	return TSQuery{Root: root}, nil
+}
+
+func (d *decoder) queryNode() *QueryNode {
+	n := &QueryNode{Op: Operator(d.byte())}
+	if d.err != nil {
+		return nil
+	}
+	switch n.Op {
+	case OpLexeme:
+		n.Lexeme = d.string()
+		flags := d.byte()
+		n.Weights = flags & 0xf
+		n.Prefix = flags&(1<<4) != 0
+	case OpNot:
+		n.Left = d.queryNode()
+	case OpAnd, OpOr, OpPhrase:
+		if n.Op == OpPhrase {
+			n.Distance = uint16(d.uvarint())
+		}
+		n.Left = d.queryNode()
+		n.Right = d.queryNode()
+	default:
+		d.err = errors.AssertionFailedf("unknown tsquery operator %d", n.Op)
+	}
+	return n
+}
@@ -0,0 +1,251 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTSQuery(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{``, ``},
+		{`fat`, `'fat'`},
+		{`fat & rat`, `'fat' & 'rat'`},
+		{`fat & (rat | cat)`, `'fat' & ( 'rat' | 'cat' )`},
+		{`fat & rat | cat`, `'fat' & 'rat' | 'cat'`},
+		{`fat | rat & cat`, `'fat' | 'rat' & 'cat'`},
+		{`!fat`, `!'fat'`},
+		{`!(fat & rat)`, `!( 'fat' & 'rat' )`},
+		{`!!fat`, `!!'fat'`},
+		{`fat <-> rat`, `'fat' <-> 'rat'`},
+		{`fat <2> rat`, `'fat' <2> 'rat'`},
+		{`fat <0> rat`, `'fat' <0> 'rat'`},
+		{`a <-> b <-> c`, `'a' <-> 'b' <-> 'c'`},
+		{`a <-> (b <-> c)`, `'a' <-> ( 'b' <-> 'c' )`},
+		{`a <-> b & c`, `'a' <-> 'b' & 'c'`},
+		{`a <-> (b & c)`, `'a' <-> ( 'b' & 'c' )`},
+		{`super:*`, `'super':*`},
+		{`fat:ab | cat:*C`, `'fat':AB | 'cat':*C`},
+		{`'it''s' & 'x y'`, `'it''s' & 'x y'`},
+		{`a&b|c`, `'a' & 'b' | 'c'`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, q.String())
+
+			// The output must parse back to the same query.
+			q2, err := ParseTSQuery(q.String())
+			require.NoError(t, err)
+			require.Equal(t, 0, q.Compare(q2))
+
+			decoded, err := DecodeTSQuery(q.Encode(nil))
+			require.NoError(t, err)
+			require.Equal(t, q.String(), decoded.String())
+		})
+	}
+}
+
+func TestParseTSQueryError(t *testing.T) {
+	for _, input := range []string{
+		`fat &`,
+		`& fat`,
+		`(fat`,
+		`fat)`,
+		`fat rat`,
+		`fat <> rat`,
+		`fat <-1> rat`,
+		`fat <20000> rat`,
+		`!`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseTSQuery(input)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	const doc = `'a':1 'fat':2 'cat':3 'sat':4 'on':5 'mat':7 'ate':9 'rat':12B`
+	testCases := []struct {
+		query    string
+		expected bool
+	}{
+		{``, false},
+		{`cat`, true},
+		{`dog`, false},
+		{`cat & rat`, true},
+		{`cat & dog`, false},
+		{`cat | dog`, true},
+		{`!dog`, true},
+		{`!cat`, false},
+		{`ca:*`, true},
+		{`do:*`, false},
+		{`rat:B`, true},
+		{`rat:A`, false},
+		{`rat:AB & cat:D`, true},
+		{`fat <-> cat`, true},
+		{`cat <-> fat`, false},
+		{`fat <2> sat`, true},
+		{`fat <-> sat`, false},
+		{`fat <-> cat <-> sat`, true},
+		{`fat <-> (cat | sat)`, true},
+		{`fat <-> !sat`, true},
+		{`fat <-> !cat`, false},
+		{`!fat <-> cat`, false},
+		{`on <2> mat`, true},
+		{`on <-> mat`, false},
+		{`fa:* <-> ca:*`, true},
+		{`fat <-> rat:A`, false},
+	}
+	v, err := ParseTSVector(doc)
+	require.NoError(t, err)
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, q.Matches(v))
+		})
+	}
+
+	// Phrase operators cannot match a vector without positions.
+	q, err := ParseTSQuery(`fat <-> cat`)
+	require.NoError(t, err)
+	require.False(t, q.Matches(v.Strip()))
+	q, err = ParseTSQuery(`fat & cat`)
+	require.NoError(t, err)
+	require.True(t, q.Matches(v.Strip()))
+}
+
+func TestToTSQuery(t *testing.T) {
+	c, err := GetConfig("english")
+	require.NoError(t, err)
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{`The & Fat & Rats`, `'fat' & 'rat'`},
+		{`supernovae:*`, `'supernova':*`},
+		{`fat:AB & rats`, `'fat':AB & 'rat'`},
+		{`'fat rats'`, `'fat' <-> 'rat'`},
+		{`fat-the-rat`, `'fat' <2> 'rat'`},
+		{`fat <-> the <-> rat`, `'fat' <2> 'rat'`},
+		{`the <-> fat`, `'fat'`},
+		{`!the`, ``},
+		{`the | an`, ``},
+		{`fat & !the`, `'fat'`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := c.ToTSQuery(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, q.String())
+		})
+	}
+
+	require.Equal(t, `'fat' & 'rat'`, c.PlainToTSQuery(`The Fat Rats`).String())
+	require.Equal(t, `'fat' <-> 'rat'`, c.PhraseToTSQuery(`The Fat Rats`).String())
+	require.Equal(t, `'cat' <2> 'rat'`, c.PhraseToTSQuery(`cats and rats`).String())
+	require.Equal(t, ``, c.PlainToTSQuery(`the`).String())
+}
+
+func TestRank(t *testing.T) {
+	v, err := ParseTSVector(`'fat':2,11 'cat':3 'rat':12A`)
+	require.NoError(t, err)
+	testCases := []struct {
+		query    string
+		method   int
+		expected string
+	}{
+		{`cat`, 0, `0.06079271`},
+		{`fat`, 0, `0.075990885`},
+		{`rat`, 0, `0.6079271`},
+		{`fat & rat`, 0, `0.39974484`},
+		{`fat & cat`, 0, `0.15717629`},
+		{`fat | rat`, 0, `0.341959`},
+		{`dog`, 0, `0`},
+		{`dog & cat`, 0, `1e-20`},
+		{`cat`, RankNormLength, `0.015198178`},
+		{`cat`, RankNormUniq | RankNormRDivRPlus1, `0.019861752`},
+		{`cat`, RankNormLogLength, `0.026181996`},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s/%d", tc.query, tc.method), func(t *testing.T) {
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, fmt.Sprint(Rank(DefaultRankWeights, v, q, tc.method)))
+		})
+	}
+
+	_, err = MakeRankWeights([]float64{0.1, 0.2})
+	require.EqualError(t, err, "array of weight is too short")
+	_, err = MakeRankWeights([]float64{0.1, 0.2, 0.3, 1.5})
+	require.EqualError(t, err, "weight out of range")
+	w, err := MakeRankWeights([]float64{-1, 0.2, 0.3, 0.9})
+	require.NoError(t, err)
+	require.Equal(t, [4]float32{0.1, 0.2, 0.3, 0.9}, w)
+}
+
+func TestEncodeMatchingInvertedIndexSpans(t *testing.T) {
+	v, err := ParseTSVector(`'fat':2 'cat':3 'rat':12A`)
+	require.NoError(t, err)
+	keys := EncodeInvertedIndexKeys(nil, v)
+	require.Len(t, keys, 3)
+	for i := range v {
+		require.Equal(t, encoding.EncodeStringAscending(nil, v[i].Word), keys[i])
+	}
+
+	testCases := []struct {
+		query      string
+		indexable  bool
+		tight      bool
+		containsV  bool
+		spansCount int
+	}{
+		{`cat`, true, true, true, 1},
+		{`dog`, true, true, false, 1},
+		{`ca:*`, true, true, true, 1},
+		{`c:* & dog`, true, true, false, 2},
+		{`cat | dog`, true, true, true, 2},
+		{`cat:A`, true, false, true, 1},
+		{`fat <-> cat`, true, false, true, 2},
+		{`cat & !dog`, true, false, true, 1},
+		{`cat | !dog`, false, false, false, 0},
+		{`!cat`, false, false, false, 0},
+		{``, false, false, false, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			expr := EncodeMatchingInvertedIndexSpans(q)
+			spanExpr, ok := expr.(*inverted.SpanExpression)
+			require.Equal(t, tc.indexable, ok)
+			if !ok {
+				return
+			}
+			require.Equal(t, tc.tight, spanExpr.Tight)
+			require.Len(t, spanExpr.SpansToRead, tc.spansCount)
+			contains, err := spanExpr.ContainsKeys(keys)
+			require.NoError(t, err)
+			require.Equal(t, tc.containsV, contains)
+		})
+	}
+}
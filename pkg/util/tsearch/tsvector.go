@@ -0,0 +1,443 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// maxLexemeLen is the maximum length, in bytes, of a single lexeme. It
+// matches the Postgres limit.
+const maxLexemeLen = 2046
+
+// maxPositionsPerLexeme is the maximum number of positions stored for a
+// single lexeme. Additional positions are silently dropped, as in Postgres.
+const maxPositionsPerLexeme = 256
+
+// MaxPosition is the largest position that can be stored in a TSVector.
+// Larger positions are clamped to this value, as in Postgres.
+const MaxPosition = 16383
+
+// Weight is the weight of a lexeme position in a TSVector. Weights are used
+// to mark lexemes that come from different parts of a document (for example,
+// a title versus a body), and are taken into account by ranking functions
+// and by weight-restricted TSQuery terms.
+type Weight byte
+
+// The possible weights of a position. D is the default, and is not shown
+// in the text representation of a TSVector.
+const (
+	WeightD Weight = iota
+	WeightC
+	WeightB
+	WeightA
+)
+
+// String implements the fmt.Stringer interface.
+func (w Weight) String() string {
+	return string("DCBA"[w])
+}
+
+// ParseWeight returns the weight named by the given (case insensitive)
+// letter, as accepted by setweight.
+func ParseWeight(s string) (Weight, error) {
+	if len(s) == 1 {
+		if w, ok := weightFromByte(s[0]); ok {
+			return w, nil
+		}
+	}
+	return 0, pgerror.Newf(pgcode.InvalidParameterValue, "unrecognized weight: %q", s)
+}
+
+// weightFromByte returns the weight corresponding to the given (case
+// insensitive) weight letter.
+func weightFromByte(c byte) (Weight, bool) {
+	switch c {
+	case 'a', 'A':
+		return WeightA, true
+	case 'b', 'B':
+		return WeightB, true
+	case 'c', 'C':
+		return WeightC, true
+	case 'd', 'D':
+		return WeightD, true
+	}
+	return 0, false
+}
+
+// Position is the position of a lexeme in a document, along with its weight.
+type Position struct {
+	Pos    uint16
+	Weight Weight
+}
+
+// Lexeme is a normalized word in a TSVector, along with the positions at
+// which it appears in the document. A Lexeme may have no positions, for
+// example if it was created from a TSVector literal without any.
+type Lexeme struct {
+	Word      string
+	Positions []Position
+}
+
+// TSVector is a sorted list of distinct lexemes, representing a document
+// that has been optimized for full-text search.
+type TSVector []Lexeme
+
+// ParseTSVector parses the text representation of a TSVector, such as
+// 'fat':2 'rat':3A. Lexemes are not normalized.
+func ParseTSVector(input string) (TSVector, error) {
+	l := lexer{input: input}
+	var ret TSVector
+	for {
+		l.skipSpace()
+		if l.done() {
+			break
+		}
+		word, err := l.scanWord(false /* inQuery */)
+		if err != nil {
+			return nil, err
+		}
+		lexeme := Lexeme{Word: word}
+		if l.peek() == ':' {
+			l.pos++
+			if lexeme.Positions, err = l.scanPositions(); err != nil {
+				return nil, err
+			}
+		}
+		if !l.done() && !isSpace(l.peek()) {
+			return nil, l.syntaxError("tsvector")
+		}
+		ret = append(ret, lexeme)
+	}
+	return ret.normalize(), nil
+}
+
+// MakeTSVector returns a TSVector containing the given lexemes, without any
+// positions.
+func MakeTSVector(words []string) (TSVector, error) {
+	v := make(TSVector, len(words))
+	for i, w := range words {
+		if w == "" {
+			return nil, pgerror.New(pgcode.ZeroLengthCharacterString,
+				"lexeme array may not contain empty strings")
+		}
+		if err := checkLexeme(w); err != nil {
+			return nil, err
+		}
+		v[i].Word = w
+	}
+	return v.normalize(), nil
+}
+
+// normalize sorts the lexemes of the vector, merging duplicates along with
+// their positions.
+func (v TSVector) normalize() TSVector {
+	sort.SliceStable(v, func(i, j int) bool { return v[i].Word < v[j].Word })
+	ret := v[:0]
+	for i := range v {
+		if len(ret) > 0 && ret[len(ret)-1].Word == v[i].Word {
+			last := &ret[len(ret)-1]
+			last.Positions = append(last.Positions, v[i].Positions...)
+			continue
+		}
+		ret = append(ret, v[i])
+	}
+	for i := range ret {
+		ret[i].Positions = normalizePositions(ret[i].Positions)
+	}
+	return ret
+}
+
+// normalizePositions sorts the given positions and removes duplicates,
+// keeping the highest weight of any duplicated position.
+func normalizePositions(positions []Position) []Position {
+	if len(positions) == 0 {
+		return nil
+	}
+	sort.SliceStable(positions, func(i, j int) bool { return positions[i].Pos < positions[j].Pos })
+	ret := positions[:1]
+	for _, p := range positions[1:] {
+		last := &ret[len(ret)-1]
+		if p.Pos == last.Pos {
+			if p.Weight > last.Weight {
+				last.Weight = p.Weight
+			}
+			continue
+		}
+		ret = append(ret, p)
+	}
+	if len(ret) > maxPositionsPerLexeme {
+		ret = ret[:maxPositionsPerLexeme]
+	}
+	return ret
+}
+
+// find returns the index of the given word in the vector, or -1 if it is not
+// present.
+func (v TSVector) find(word string) int {
+	i := sort.Search(len(v), func(i int) bool { return v[i].Word >= word })
+	if i < len(v) && v[i].Word == word {
+		return i
+	}
+	return -1
+}
+
+// findPrefix returns the range [start, end) of lexemes in the vector that
+// start with the given prefix.
+func (v TSVector) findPrefix(prefix string) (start, end int) {
+	start = sort.Search(len(v), func(i int) bool { return v[i].Word >= prefix })
+	end = start
+	for end < len(v) && strings.HasPrefix(v[end].Word, prefix) {
+		end++
+	}
+	return start, end
+}
+
+// String returns the text representation of the vector.
+func (v TSVector) String() string {
+	var buf strings.Builder
+	for i, l := range v {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		writeQuotedLexeme(&buf, l.Word)
+		for j, p := range l.Positions {
+			if j == 0 {
+				buf.WriteByte(':')
+			} else {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.Itoa(int(p.Pos)))
+			if p.Weight != WeightD {
+				buf.WriteString(p.Weight.String())
+			}
+		}
+	}
+	return buf.String()
+}
+
+// writeQuotedLexeme writes the given word surrounded by single quotes,
+// escaping any quotes and backslashes it contains.
+func writeQuotedLexeme(buf *strings.Builder, word string) {
+	buf.WriteByte('\'')
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		if c == '\'' || c == '\\' {
+			buf.WriteByte(c)
+		}
+		buf.WriteByte(c)
+	}
+	buf.WriteByte('\'')
+}
+
+// Size returns the approximate size of the vector in bytes.
+func (v TSVector) Size() uintptr {
+	size := uintptr(len(v)) * 40
+	for _, l := range v {
+		size += uintptr(len(l.Word)) + uintptr(len(l.Positions))*4
+	}
+	return size
+}
+
+// Compare compares two vectors, returning -1, 0 or 1. The ordering is not
+// meaningful beyond being a total order that is consistent with equality.
+func (v TSVector) Compare(other TSVector) int {
+	for i := 0; i < len(v) && i < len(other); i++ {
+		if c := strings.Compare(v[i].Word, other[i].Word); c != 0 {
+			return c
+		}
+		a, b := v[i].Positions, other[i].Positions
+		for j := 0; j < len(a) && j < len(b); j++ {
+			if a[j] != b[j] {
+				if a[j].Pos != b[j].Pos {
+					return compareInts(int(a[j].Pos), int(b[j].Pos))
+				}
+				return compareInts(int(a[j].Weight), int(b[j].Weight))
+			}
+		}
+		if c := compareInts(len(a), len(b)); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(v), len(other))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Strip returns a copy of the vector without any position information.
+func (v TSVector) Strip() TSVector {
+	ret := make(TSVector, len(v))
+	for i := range v {
+		ret[i].Word = v[i].Word
+	}
+	return ret
+}
+
+// SetWeight returns a copy of the vector in which every position has the
+// given weight.
+func (v TSVector) SetWeight(w Weight) TSVector {
+	ret := make(TSVector, len(v))
+	for i := range v {
+		ret[i].Word = v[i].Word
+		ret[i].Positions = make([]Position, len(v[i].Positions))
+		for j, p := range v[i].Positions {
+			ret[i].Positions[j] = Position{Pos: p.Pos, Weight: w}
+		}
+	}
+	return ret
+}
+
+// Concat concatenates two vectors. The positions of the second vector are
+// shifted by the largest position of the first, as in Postgres.
+func (v TSVector) Concat(other TSVector) TSVector {
+	var maxPos uint16
+	for _, l := range v {
+		for _, p := range l.Positions {
+			if p.Pos > maxPos {
+				maxPos = p.Pos
+			}
+		}
+	}
+	ret := make(TSVector, 0, len(v)+len(other))
+	for _, l := range v {
+		ret = append(ret, Lexeme{Word: l.Word, Positions: append([]Position(nil), l.Positions...)})
+	}
+	for _, l := range other {
+		positions := make([]Position, len(l.Positions))
+		for j, p := range l.Positions {
+			positions[j] = Position{Pos: clampPosition(int(p.Pos) + int(maxPos)), Weight: p.Weight}
+		}
+		ret = append(ret, Lexeme{Word: l.Word, Positions: positions})
+	}
+	return ret.normalize()
+}
+
+// clampPosition limits the given position to the valid range of positions.
+func clampPosition(pos int) uint16 {
+	if pos > MaxPosition {
+		return MaxPosition
+	}
+	return uint16(pos)
+}
+
+// Encode appends the binary encoding of the vector to the given buffer.
+func (v TSVector) Encode(b []byte) []byte {
+	b = appendUvarint(b, uint64(len(v)))
+	for _, l := range v {
+		b = appendUvarint(b, uint64(len(l.Word)))
+		b = append(b, l.Word...)
+		b = appendUvarint(b, uint64(len(l.Positions)))
+		for _, p := range l.Positions {
+			b = appendUvarint(b, uint64(p.Pos)<<2|uint64(p.Weight))
+		}
+	}
+	return b
+}
+
+// DecodeTSVector decodes a vector encoded with Encode.
+func DecodeTSVector(b []byte) (TSVector, error) {
+	d := decoder{b: b}
+	n := d.uvarint()
+	ret := make(TSVector, 0, n)
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		l := Lexeme{Word: d.string()}
+		if numPositions := d.uvarint(); numPositions > 0 && d.err == nil {
+			l.Positions = make([]Position, numPositions)
+			for j := range l.Positions {
+				p := d.uvarint()
+				l.Positions[j] = Position{Pos: uint16(p >> 2), Weight: Weight(p & 3)}
+			}
+		}
+		ret = append(ret, l)
+	}
+	if d.err == nil && len(d.b) > 0 {
+		d.err = errors.AssertionFailedf("%d trailing bytes in encoded tsvector", len(d.b))
+	}
+	return ret, d.err
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// decoder reads values written with appendUvarint, remembering the first
+// error encountered.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = errors.AssertionFailedf("invalid varint in encoded text search value")
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.b) == 0 {
+		d.err = errors.AssertionFailedf("unexpected end of encoded text search value")
+		return 0
+	}
+	c := d.b[0]
+	d.b = d.b[1:]
+	return c
+}
+
+func (d *decoder) string() string {
+	n := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if uint64(len(d.b)) < n {
+		d.err = errors.AssertionFailedf("unexpected end of encoded text search value")
+		return ""
+	}
+	s := string(d.b[:n])
+	d.b = d.b[n:]
+	return s
+}
+
+// checkLexeme returns an error if the given word cannot be stored in a
+// TSVector or TSQuery.
+func checkLexeme(word string) error {
+	if len(word) > maxLexemeLen {
+		return pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"word is too long (%d bytes, max %d bytes)", len(word), maxLexemeLen)
+	}
+	return nil
+}
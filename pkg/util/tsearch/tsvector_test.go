@@ -0,0 +1,169 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTSVector(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{``, ``},
+		{`   `, ``},
+		{`foo`, `'foo'`},
+		{`a fat cat sat on a mat and ate a fat rat`,
+			`'a' 'and' 'ate' 'cat' 'fat' 'mat' 'on' 'rat' 'sat'`},
+		{`'fat':2 'rat':3A 'fat':1B`, `'fat':1B,2 'rat':3A`},
+		{`a:1,3,2,1D`, `'a':1,2,3`},
+		{`a:1A,1B`, `'a':1A`},
+		{`a:20000`, `'a':16383`},
+		{`'with space' 'it''s' 'back\\slash' b\'c`,
+			`'b''c' 'back\\slash' 'it''s' 'with space'`},
+		{`'a' b`, `'a' 'b'`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseTSVector(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, v.String())
+
+			// The output must parse back to the same vector.
+			v2, err := ParseTSVector(v.String())
+			require.NoError(t, err)
+			require.Equal(t, 0, v.Compare(v2))
+
+			decoded, err := DecodeTSVector(v.Encode(nil))
+			require.NoError(t, err)
+			require.Equal(t, v.String(), decoded.String())
+		})
+	}
+}
+
+func TestParseTSVectorError(t *testing.T) {
+	for _, input := range []string{
+		`'unterminated`,
+		`a:`,
+		`a:b`,
+		`a:0`,
+		`a:1x`,
+		`''`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseTSVector(input)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestTSVectorOperations(t *testing.T) {
+	a, err := ParseTSVector(`a:1 b:2A`)
+	require.NoError(t, err)
+	b, err := ParseTSVector(`b:1 c:2`)
+	require.NoError(t, err)
+
+	require.Equal(t, `'a':1 'b':2A,3 'c':4`, a.Concat(b).String())
+	require.Equal(t, `'a' 'b'`, a.Strip().String())
+	require.Equal(t, `'a':1C 'b':2C`, a.SetWeight(WeightC).String())
+	require.Equal(t, -1, a.Compare(b))
+	require.Equal(t, 1, b.Compare(a))
+
+	m, err := MakeTSVector([]string{"fat", "cat", "fat"})
+	require.NoError(t, err)
+	require.Equal(t, `'cat' 'fat'`, m.String())
+	_, err = MakeTSVector([]string{"fat", ""})
+	require.EqualError(t, err, "lexeme array may not contain empty strings")
+
+	w, err := ParseWeight("b")
+	require.NoError(t, err)
+	require.Equal(t, WeightB, w)
+	_, err = ParseWeight("AB")
+	require.EqualError(t, err, `unrecognized weight: "AB"`)
+}
+
+func TestToTSVector(t *testing.T) {
+	testCases := []struct {
+		config   string
+		input    string
+		expected string
+	}{
+		{"english", `a fat  cat sat on a mat - it ate a fat rats`,
+			`'ate':9 'cat':3 'fat':2,11 'mat':7 'rat':12 'sat':4`},
+		{"english", `The quick brown foxes jumped over the lazy dogs`,
+			`'brown':3 'dog':9 'fox':4 'jump':5 'lazi':8 'quick':2`},
+		{"english", `Running, RUNS and runner!`, `'run':1,2 'runner':4`},
+		{"english", `the and of`, ``},
+		{"simple", `The Fat Rats`, `'fat':2 'rats':3 'the':1`},
+		{"pg_catalog.simple", `naïve café`, `'café':2 'naïve':1`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			c, err := GetConfig(tc.config)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, c.ToTSVector(tc.input).String())
+		})
+	}
+
+	_, err := GetConfig("klingon")
+	require.EqualError(t, err, `text search configuration "klingon" does not exist`)
+}
+
+func TestStemEnglish(t *testing.T) {
+	// These examples are from the sample vocabulary of the Snowball English
+	// stemmer.
+	testCases := map[string]string{
+		"consign":       "consign",
+		"consigned":     "consign",
+		"consigning":    "consign",
+		"consignment":   "consign",
+		"consistency":   "consist",
+		"consistently":  "consist",
+		"consolation":   "consol",
+		"consolatory":   "consolatori",
+		"consolidating": "consolid",
+		"consolingly":   "consol",
+		"conspicuously": "conspicu",
+		"conspiracy":    "conspiraci",
+		"conspirators":  "conspir",
+		"constable":     "constabl",
+		"constancy":     "constanc",
+		"knackeries":    "knackeri",
+		"kneaded":       "knead",
+		"knees":         "knee",
+		"knightly":      "knight",
+		"knitted":       "knit",
+		"knives":        "knive",
+		"knocker":       "knocker",
+		"generously":    "generous",
+		"hoped":         "hope",
+		"hopping":       "hop",
+		"cries":         "cri",
+		"ties":          "tie",
+		"gas":           "gas",
+		"gaps":          "gap",
+		"agreed":        "agre",
+		"feed":          "feed",
+		"sky":           "sky",
+		"skies":         "sky",
+		"dying":         "die",
+		"succeeding":    "succeed",
+		"yelling":       "yell",
+		"saying":        "say",
+		"by":            "by",
+	}
+	for word, expected := range testCases {
+		require.Equal(t, expected, stemEnglish(word), word)
+	}
+}